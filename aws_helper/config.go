@@ -71,6 +71,9 @@ func CreateAwsSessionFromConfig(config *AwsSessionConfig, terragruntOptions *opt
 		Profile:           config.Profile,
 		SharedConfigState: session.SharedConfigEnable,
 	}
+	if sessionOptions.Profile == "" {
+		sessionOptions.Profile = terragruntOptions.AWSProfile
+	}
 
 	if len(config.CredsFilename) > 0 {
 		sessionOptions.SharedConfigFiles = []string{config.CredsFilename}
@@ -132,7 +135,7 @@ func CreateAwsSession(config *AwsSessionConfig, terragruntOptions *options.Terra
 	var sess *session.Session
 	var err error
 	if config == nil {
-		sessionOptions := session.Options{SharedConfigState: session.SharedConfigEnable}
+		sessionOptions := session.Options{SharedConfigState: session.SharedConfigEnable, Profile: terragruntOptions.AWSProfile}
 		sess, err = session.NewSessionWithOptions(sessionOptions)
 		if err != nil {
 			return nil, errors.WithStackTrace(err)
@@ -163,7 +166,13 @@ func CreateAwsSession(config *AwsSessionConfig, terragruntOptions *options.Terra
 
 // Make API calls to AWS to assume the IAM role specified and return the temporary AWS credentials to use that role
 func AssumeIamRole(iamRoleOpts options.IAMRoleOptions) (*sts.Credentials, error) {
-	sessionOptions := session.Options{SharedConfigState: session.SharedConfigEnable}
+	return AssumeIamRoleWithProfile(iamRoleOpts, "")
+}
+
+// AssumeIamRoleWithProfile assumes the IAM role specified, as AssumeIamRole does, with the credentials of the given named
+// profile of the AWS shared config files, or of the default credentials chain if it is empty.
+func AssumeIamRoleWithProfile(iamRoleOpts options.IAMRoleOptions, profile string) (*sts.Credentials, error) {
+	sessionOptions := session.Options{SharedConfigState: session.SharedConfigEnable, Profile: profile}
 	sess, err := session.NewSessionWithOptions(sessionOptions)
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
@@ -277,7 +286,7 @@ func AssumeRoleAndUpdateEnvIfNecessary(terragruntOptions *options.TerragruntOpti
 	}
 
 	terragruntOptions.Logger.Debugf("Assuming IAM role %s with a session duration of %d seconds.", iamRoleOpts.RoleARN, iamRoleOpts.AssumeRoleDuration)
-	creds, err := AssumeIamRole(iamRoleOpts)
+	creds, err := AssumeIamRoleWithProfile(iamRoleOpts, terragruntOptions.AWSProfile)
 	if err != nil {
 		return err
 	}
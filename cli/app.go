@@ -34,11 +34,25 @@ import (
 	"github.com/gruntwork-io/terragrunt/cli/commands"
 	awsproviderpatch "github.com/gruntwork-io/terragrunt/cli/commands/aws-provider-patch"
 	"github.com/gruntwork-io/terragrunt/cli/commands/catalog"
+	"github.com/gruntwork-io/terragrunt/cli/commands/completion"
+	"github.com/gruntwork-io/terragrunt/cli/commands/debug"
+	"github.com/gruntwork-io/terragrunt/cli/commands/decrypt"
+	"github.com/gruntwork-io/terragrunt/cli/commands/docs"
+	"github.com/gruntwork-io/terragrunt/cli/commands/export"
+	"github.com/gruntwork-io/terragrunt/cli/commands/generate"
 	graphdependencies "github.com/gruntwork-io/terragrunt/cli/commands/graph-dependencies"
 	"github.com/gruntwork-io/terragrunt/cli/commands/hclfmt"
+	"github.com/gruntwork-io/terragrunt/cli/commands/history"
+	"github.com/gruntwork-io/terragrunt/cli/commands/licenses"
+	"github.com/gruntwork-io/terragrunt/cli/commands/lint"
+	locktables "github.com/gruntwork-io/terragrunt/cli/commands/lock-tables"
+	moduledocs "github.com/gruntwork-io/terragrunt/cli/commands/module-docs"
 	outputmodulegroups "github.com/gruntwork-io/terragrunt/cli/commands/output-module-groups"
+	"github.com/gruntwork-io/terragrunt/cli/commands/promote"
+	"github.com/gruntwork-io/terragrunt/cli/commands/providers"
 	renderjson "github.com/gruntwork-io/terragrunt/cli/commands/render-json"
 	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
+	"github.com/gruntwork-io/terragrunt/cli/commands/taint"
 	terraformCmd "github.com/gruntwork-io/terragrunt/cli/commands/terraform"
 	terragruntinfo "github.com/gruntwork-io/terragrunt/cli/commands/terragrunt-info"
 	validateinputs "github.com/gruntwork-io/terragrunt/cli/commands/validate-inputs"
@@ -82,6 +96,7 @@ func NewApp(writer io.Writer, errWriter io.Writer) *App {
 	app.Before = beforeAction(opts)
 	app.DefaultCommand = telemetryCommand(opts, terraformCmd.NewCommand(opts)) // by default, if no terragrunt command is specified, run the Terraform command
 	app.OsExiter = osExiter
+	app.FlagValueCompletions = moduleDirFlagCompletions()
 
 	return &App{app}
 }
@@ -126,6 +141,22 @@ func (app *App) RunContext(ctx context.Context, args []string) error {
 	return nil
 }
 
+// moduleDirFlagCompletions completes the values of the flags taking module dirs with the dirs of the modules found in
+// the current dir.
+func moduleDirFlagCompletions() map[string]cli.FlagValueCompleteFunc {
+	completions := map[string]cli.FlagValueCompleteFunc{}
+	for _, flagName := range []string{
+		commands.TerragruntWorkingDirFlagName,
+		commands.TerragruntIncludeDirFlagName,
+		commands.TerragruntExcludeDirFlagName,
+		commands.TerragruntAssumeAppliedFlagName,
+		graph.TerragruntGraphRootFlagName,
+	} {
+		completions[flagName] = completion.CompleteModuleDirs
+	}
+	return completions
+}
+
 // This set of commands is also used in unit tests
 func terragruntCommands(opts *options.TerragruntOptions) cli.Commands {
 	cmds := cli.Commands{
@@ -140,6 +171,21 @@ func terragruntCommands(opts *options.TerragruntOptions) cli.Commands {
 		telemetryCommand(opts, catalog.NewCommand(opts)),            // catalog
 		telemetryCommand(opts, scaffold.NewCommand(opts)),           // scaffold
 		telemetryCommand(opts, graph.NewCommand(opts)),              // graph
+		telemetryCommand(opts, locktables.NewCommand(opts)),         // lock-tables
+		telemetryCommand(opts, completion.NewCommand(opts)),         // completion
+		telemetryCommand(opts, providers.NewCommand(opts)),          // providers
+		telemetryCommand(opts, licenses.NewCommand(opts)),           // licenses
+		telemetryCommand(opts, promote.NewCommand(opts)),            // promote
+		telemetryCommand(opts, docs.NewCommand(opts)),               // docs
+		telemetryCommand(opts, moduledocs.NewCommand(opts)),         // module-docs
+		telemetryCommand(opts, decrypt.NewCommand(opts)),            // decrypt
+		telemetryCommand(opts, lint.NewCommand(opts)),               // lint
+		telemetryCommand(opts, debug.NewCommand(opts)),              // debug
+		telemetryCommand(opts, taint.NewTaintCommand(opts)),         // taint
+		telemetryCommand(opts, taint.NewUntaintCommand(opts)),       // untaint
+		telemetryCommand(opts, history.NewCommand(opts)),            // history
+		telemetryCommand(opts, export.NewCommand(opts)),             // export
+		telemetryCommand(opts, generate.NewCommand(opts)),           // generate
 	}
 
 	sort.Sort(cmds)
@@ -164,9 +210,23 @@ func telemetryCommand(opts *options.TerragruntOptions, cmd *cli.Command) *cli.Co
 				return err
 			}
 
-			return runAction(ctx, opts, action)
+			err := runAction(ctx, opts, action)
+			telemetry.CountCaches(childCtx, opts)
+			if err != nil && opts.GitHubAnnotations {
+				emitGitHubAnnotations(opts, err)
+			}
+			return withDetailedExitCode(opts, err, childCtx.Err() != nil)
 		})
 	}
+	if after := cmd.After; after != nil {
+		cmd.After = func(ctx *cli.Context) error {
+			err := after(ctx)
+			if err != nil && opts.GitHubAnnotations {
+				emitGitHubAnnotations(opts, err)
+			}
+			return withDetailedExitCode(opts, err, ctx.Err() != nil)
+		}
+	}
 	return cmd
 }
 
@@ -239,12 +299,7 @@ func initialSetup(cliCtx *cli.Context, opts *options.TerragruntOptions) error {
 		cmdName = cliCtx.Args().CommandName()
 
 		// `terraform apply -destroy` is an alias for `terraform destroy`.
-		// It is important to resolve the alias because the `run-all` relies on terraform command to determine the order, for `destroy` command is used the reverse order.
-		if cmdName == terraform.CommandNameApply && util.ListContainsElement(args, terraform.FlagNameDestroy) {
-			cmdName = terraform.CommandNameDestroy
-			args = append([]string{terraform.CommandNameDestroy}, args.Tail()...)
-			args = util.RemoveElementFromList(args, terraform.FlagNameDestroy)
-		}
+		cmdName, args = resolveDestroyCommand(cmdName, args)
 	default:
 		args = append([]string{cmdName}, args...)
 	}
@@ -259,12 +314,30 @@ func initialSetup(cliCtx *cli.Context, opts *options.TerragruntOptions) error {
 		util.DisableLogColors()
 	}
 
-	if opts.JsonLogFormat {
-		util.JsonFormat()
+	if opts.LogFormat == "" {
+		opts.LogFormat = util.LogFormatHuman
+		if opts.JsonLogFormat {
+			opts.LogFormat = util.LogFormatJSON
+		}
+	}
+	if err := util.SetLogFormat(opts.LogFormat); err != nil {
+		return err
+	}
+	opts.JsonLogFormat = opts.LogFormat == util.LogFormatJSON
+
+	if len(opts.LogFields) > 0 {
+		var logFields []string
+		for _, fields := range opts.LogFields {
+			logFields = append(logFields, strings.Split(fields, ",")...)
+		}
+		if err := util.SetLogFields(logFields...); err != nil {
+			return err
+		}
+		opts.LogFields = logFields
 	}
 
 	opts.LogLevel = util.ParseLogLevel(opts.LogLevelStr)
-	opts.Logger = util.CreateLogEntry("", opts.LogLevel)
+	opts.Logger = util.CreateLogEntry("", opts.LogLevel).WithField(util.LogFieldCommand, cmdName)
 	opts.Logger.Logger.SetOutput(cliCtx.App.ErrWriter)
 
 	log.SetLogger(opts.Logger.Logger)
@@ -290,6 +363,13 @@ func initialSetup(cliCtx *cli.Context, opts *options.TerragruntOptions) error {
 	}
 	opts.DownloadDir = filepath.ToSlash(downloadDir)
 
+	// --- Run ID
+	if opts.RunID == "" {
+		opts.RunID = options.NewRunID()
+	}
+	opts.Env[options.RunIDEnvName] = opts.RunID
+	opts.Logger = opts.Logger.WithField(util.LogFieldRunID, opts.RunID)
+
 	// --- Terragrunt ConfigPath
 	if opts.TerragruntConfigPath == "" {
 		opts.TerragruntConfigPath = config.GetDefaultConfigPath(opts.WorkingDir)
@@ -297,6 +377,52 @@ func initialSetup(cliCtx *cli.Context, opts *options.TerragruntOptions) error {
 		opts.TerragruntConfigPath = util.JoinPath(opts.WorkingDir, opts.TerragruntConfigPath)
 	}
 
+	// --- Restricted
+	if opts.Restricted {
+		// Auto creating and updating the remote state resources makes changes outside of the module dir.
+		opts.FailIfBucketCreationRequired = true
+		opts.DisableBucketUpdate = true
+	}
+
+	// --- Warnings
+	if err := options.ValidateWarningCodes(opts.SuppressWarnings); err != nil {
+		return err
+	}
+
+	// --- Exec Policies
+	if err := resolveExecPolicies(cliCtx.Context, opts); err != nil {
+		return err
+	}
+
+	// --- Command Aliases
+	if cmdName == CommandNameRun {
+		aliasArgs, err := resolveCommandAlias(cliCtx.Context, opts, args)
+		if err != nil {
+			return err
+		}
+		cmdName, args = resolveDestroyCommand(aliasArgs[0], aliasArgs)
+
+		opts.TerraformCommand = cmdName
+		opts.TerraformCliArgs = args
+		opts.Logger = opts.Logger.WithField(util.LogFieldCommand, cmdName)
+	}
+
+	// --- Read Only
+	if opts.ReadOnly {
+		// Auto creating and updating the remote state resources changes the infrastructure.
+		opts.FailIfBucketCreationRequired = true
+		opts.DisableBucketUpdate = true
+
+		if terraform.IsMutatingCommand(opts.TerraformCliArgs) {
+			return errors.WithStackTrace(terraformCmd.ReadOnlyCommandNotAllowed{Command: cmdName, Source: "--" + commands.TerragruntReadOnlyFlagName})
+		}
+	}
+
+	// --- Extra Args
+	if _, err := terraformCmd.ParseCLIExtraArgs(opts.ExtraArgs); err != nil {
+		return err
+	}
+
 	opts.TerraformPath = filepath.ToSlash(opts.TerraformPath)
 
 	opts.ExcludeDirs, err = util.GlobCanonicalPath(opts.WorkingDir, opts.ExcludeDirs...)
@@ -309,6 +435,77 @@ func initialSetup(cliCtx *cli.Context, opts *options.TerragruntOptions) error {
 		return err
 	}
 
+	opts.PreventDestroyPaths, err = util.GlobCanonicalPath(opts.WorkingDir, opts.PreventDestroyPaths...)
+	if err != nil {
+		return err
+	}
+
+	var assumeAppliedDirs []string
+	for _, dirs := range opts.AssumeAppliedDirs {
+		assumeAppliedDirs = append(assumeAppliedDirs, strings.Split(dirs, ",")...)
+	}
+	opts.AssumeAppliedDirs, err = util.GlobCanonicalPath(opts.WorkingDir, assumeAppliedDirs...)
+	if err != nil {
+		return err
+	}
+
+	opts.AllowDestroyDirs, err = util.GlobCanonicalPath(opts.WorkingDir, splitCommaSeparated(opts.AllowDestroyDirs)...)
+	if err != nil {
+		return err
+	}
+
+	// --- Exit Codes
+	if !util.ListContainsElement(exitCodeModes, opts.ExitCodeMode) {
+		return errors.WithStackTrace(UnknownExitCodeMode(opts.ExitCodeMode))
+	}
+	var exitCodes []string
+	for _, codes := range opts.ExitCodes {
+		exitCodes = append(exitCodes, strings.Split(codes, ",")...)
+	}
+	if _, err := parseExitCodes(exitCodes); err != nil {
+		return err
+	}
+	opts.ExitCodes = exitCodes
+
+	// --- Schema cache
+	if opts.SchemaCache && cmdName == terraform.CommandNameValidate && !opts.Offline {
+		// The provider cache server downloads each provider once, and the modules validated use the cached providers
+		opts.ProviderCache = true
+	}
+
+	// --- Offline
+	if opts.Offline && opts.ProviderCache {
+		return errors.WithStackTrace(options.OfflineWithProviderCache{})
+	}
+
+	// --- Downloads
+	for _, mirror := range opts.DownloadMirrors {
+		if _, err := util.ParseDownloadMirror(mirror); err != nil {
+			return err
+		}
+	}
+
+	// --- Saved plans
+	for _, dir := range []*string{&opts.SavePlansDir, &opts.UsePlansDir} {
+		if *dir == "" {
+			continue
+		}
+		absDir, err := filepath.Abs(*dir)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		*dir = filepath.ToSlash(absDir)
+	}
+
+	// --- Snapshot
+	if opts.SnapshotDir != "" {
+		snapshotDir, err := filepath.Abs(opts.SnapshotDir)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		opts.SnapshotDir = filepath.ToSlash(snapshotDir)
+	}
+
 	// --- Terragrunt Version
 	terragruntVersion, err := hashicorpversion.NewVersion(cliCtx.App.Version)
 	if err != nil {
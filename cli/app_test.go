@@ -471,6 +471,26 @@ func (err argMissingValueError) Error() string {
 	return fmt.Sprintf("flag needs an argument: -%s", string(err))
 }
 
+func TestAutocompleteModuleDirs(t *testing.T) {
+	defer os.Unsetenv("COMP_LINE")
+
+	root := filepath.ToSlash(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "live", "vpc"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "live", "vpc", "terragrunt.hcl"), []byte{}, 0644))
+
+	for _, compLine := range []string{"run-all plan --terragrunt-working-dir " + root + "/live/v", "run-all plan --terragrunt-exclude-dir=" + root + "/live/v"} {
+		os.Setenv("COMP_LINE", "terragrunt "+compLine)
+
+		output := &bytes.Buffer{}
+		app := NewApp(output, os.Stderr)
+
+		err := app.Run([]string{"terragrunt"})
+		require.NoError(t, err)
+
+		assert.Contains(t, output.String(), root+"/live/vpc")
+	}
+}
+
 func TestAutocomplete(t *testing.T) {
 	defer os.Unsetenv("COMP_LINE")
 
@@ -509,3 +529,91 @@ func TestAutocomplete(t *testing.T) {
 		assert.Contains(t, output.String(), strings.Join(testCase.expectedCompletes, "\n"))
 	}
 }
+
+func TestCommandAliases(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.ToSlash(t.TempDir())
+	moduleDir := util.JoinPath(root, "live", "vpc")
+	require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(util.JoinPath(root, "terragrunt.hcl"), []byte(`
+command_aliases = {
+  preview  = ["plan", "-lock=false"]
+  teardown = ["apply", "-destroy", "-auto-approve"]
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(util.JoinPath(moduleDir, "terragrunt.hcl"), []byte(`
+include "root" {
+  path = find_in_parent_folders()
+}
+
+command_aliases = {
+  preview = ["plan", "-refresh=false"]
+}
+`), 0644))
+
+	testCases := []struct {
+		args                     []string
+		expectedTerraformCommand string
+		expectedTerraformArgs    []string
+		expectedErr              error
+	}{
+		{
+			[]string{"run", "preview", "-no-color", doubleDashed(commands.TerragruntWorkingDirFlagName), root},
+			"plan",
+			[]string{"plan", "-lock=false", "-no-color"},
+			nil,
+		},
+		{
+			[]string{"run", "preview", doubleDashed(commands.TerragruntWorkingDirFlagName), moduleDir},
+			"plan",
+			[]string{"plan", "-refresh=false"},
+			nil,
+		},
+		{
+			[]string{"run-all", "run", "teardown", doubleDashed(commands.TerragruntWorkingDirFlagName), moduleDir},
+			"destroy",
+			[]string{"destroy", "-auto-approve"},
+			nil,
+		},
+		{
+			[]string{"run", "deploy", doubleDashed(commands.TerragruntWorkingDirFlagName), moduleDir},
+			"",
+			nil,
+			UnknownCommandAlias{Name: "deploy", Defined: map[string][]string{"preview": {"plan", "-refresh=false"}, "teardown": {"apply", "-destroy", "-auto-approve"}}},
+		},
+		{
+			[]string{"run", doubleDashed(commands.TerragruntWorkingDirFlagName), moduleDir},
+			"",
+			nil,
+			MissingCommandAliasName{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		opts, err := runAppTest(testCase.args, options.NewTerragruntOptions())
+		if testCase.expectedErr != nil {
+			assert.EqualError(t, err, testCase.expectedErr.Error())
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expectedTerraformCommand, opts.TerraformCommand)
+		assert.Equal(t, testCase.expectedTerraformArgs, []string(opts.TerraformCliArgs))
+	}
+}
+
+func TestRestrictedExecPolicies(t *testing.T) {
+	t.Parallel()
+
+	opts, err := runAppTest([]string{"plan", doubleDashed(commands.TerragruntRestrictedFlagName)}, options.NewTerragruntOptions())
+	require.NoError(t, err)
+	assert.True(t, opts.FailIfBucketCreationRequired)
+	require.Len(t, opts.ExecPolicies, 1)
+	assert.False(t, opts.ExecPolicies[0].Allows("tflint"))
+
+	opts, err = runAppTest([]string{"plan", doubleDashed(commands.TerragruntRestrictedFlagName), doubleDashed(commands.TerragruntExecAllowFlagName), "tflint,jq"}, options.NewTerragruntOptions())
+	require.NoError(t, err)
+	require.Len(t, opts.ExecPolicies, 1)
+	assert.Equal(t, []string{"tflint", "jq"}, opts.ExecPolicies[0].Allow)
+	assert.False(t, opts.ExecPolicies[0].Allows("bash"))
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The command running one of the command aliases defined in the `command_aliases` attribute of the configuration, e.g.
+// `terragrunt run preview` or `terragrunt run-all run preview`.
+const CommandNameRun = "run"
+
+// resolveCommandAlias replaces the alias name with the args the alias is defined with, followed by the remaining args,
+// so that `terragrunt run preview -no-color` runs `terraform plan -lock=false -no-color` for the alias
+// `preview = ["plan", "-lock=false"]`. The alias is looked up in the configs of the working dir and the parent dirs,
+// the closest config defining it wins.
+func resolveCommandAlias(ctx context.Context, opts *options.TerragruntOptions, args []string) ([]string, error) {
+	if len(args) < 2 || strings.HasPrefix(args[1], "-") {
+		return nil, errors.WithStackTrace(MissingCommandAliasName{})
+	}
+	name := args[1]
+
+	aliases, err := findCommandAliases(ctx, opts, name)
+	if err != nil {
+		return nil, err
+	}
+
+	aliasArgs, ok := aliases[name]
+	if !ok {
+		return nil, errors.WithStackTrace(UnknownCommandAlias{Name: name, Defined: aliases})
+	}
+	if len(aliasArgs) == 0 {
+		return nil, errors.WithStackTrace(EmptyCommandAlias(name))
+	}
+
+	opts.Logger.Debugf("Running command alias %s as %s", name, strings.Join(aliasArgs, " "))
+	return append(append([]string{}, aliasArgs...), args[2:]...), nil
+}
+
+// findCommandAliases returns the command aliases of the closest config, starting from the working dir, that defines the
+// alias with the given name. If none does, returns all the aliases found, to list them in the error.
+func findCommandAliases(ctx context.Context, opts *options.TerragruntOptions, name string) (map[string][]string, error) {
+	allAliases := map[string][]string{}
+
+	dir := opts.WorkingDir
+	for i := 0; i < opts.MaxFoldersToCheck; i++ {
+		configPath := config.GetDefaultConfigPath(dir)
+		if util.FileExists(configPath) {
+			parsingCtx := config.NewParsingContext(ctx, opts.Clone(configPath)).WithDecodeList(config.TerragruntFlags)
+			terragruntConfig, err := config.PartialParseConfigFile(parsingCtx, configPath, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := terragruntConfig.CommandAliases[name]; ok {
+				return terragruntConfig.CommandAliases, nil
+			}
+			for aliasName, aliasArgs := range terragruntConfig.CommandAliases {
+				if _, ok := allAliases[aliasName]; !ok {
+					allAliases[aliasName] = aliasArgs
+				}
+			}
+		}
+
+		parentDir := filepath.ToSlash(filepath.Dir(dir))
+		if parentDir == dir {
+			break
+		}
+		dir = parentDir
+	}
+
+	return allAliases, nil
+}
+
+// resolveDestroyCommand resolves `terraform apply -destroy` to `terraform destroy`. This is important because the
+// `run-all` relies on terraform command to determine the order, for `destroy` command is used the reverse order.
+func resolveDestroyCommand(cmdName string, args []string) (string, []string) {
+	if cmdName == terraform.CommandNameApply && util.ListContainsElement(args, terraform.FlagNameDestroy) {
+		cmdName = terraform.CommandNameDestroy
+		args = append([]string{terraform.CommandNameDestroy}, args[1:]...)
+		args = util.RemoveElementFromList(args, terraform.FlagNameDestroy)
+	}
+	return cmdName, args
+}
+
+// Custom error types
+
+type MissingCommandAliasName struct{}
+
+func (err MissingCommandAliasName) Error() string {
+	return fmt.Sprintf("The %s command requires the name of a command alias defined in the command_aliases attribute, e.g. `terragrunt %s <alias>`", CommandNameRun, CommandNameRun)
+}
+
+type UnknownCommandAlias struct {
+	Name    string
+	Defined map[string][]string
+}
+
+func (err UnknownCommandAlias) Error() string {
+	if len(err.Defined) == 0 {
+		return fmt.Sprintf("Unknown command alias %s, no command aliases are defined in the command_aliases attribute of the configuration", err.Name)
+	}
+
+	var names []string
+	for name := range err.Defined {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("Unknown command alias %s, must be one of: %s", err.Name, strings.Join(names, ", "))
+}
+
+type EmptyCommandAlias string
+
+func (err EmptyCommandAlias) Error() string {
+	return fmt.Sprintf("The command alias %s must define at least the terraform command to run", string(err))
+}
@@ -25,6 +25,10 @@
 // This is a temporary workaround for a Terraform bug (https://github.com/hashicorp/terraform/issues/13018) where
 // any dynamic values in nested provider blocks are not handled correctly when you call 'terraform import', so by
 // temporarily hard-coding them, we can allow 'import' to work.
+//
+// The providers of other types than aws can be patched as well, the attributes can be set even in the provider blocks
+// that don't set them, the attributes can be set to variables injected into the modules instead of the values, and the
+// patch can be previewed as a diff without patching the modules.
 
 package awsproviderpatch
 
@@ -34,21 +38,62 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mattn/go-zglob"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/codegen"
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/util"
 )
 
-const defaultKeyParts = 2
+const (
+	defaultKeyParts = 2
+
+	// The provider type patched when none is given.
+	defaultProviderType = "aws"
+
+	// The file, in the patched modules, declaring the variables the attributes are set to with
+	// --terragrunt-patch-inject-vars.
+	injectedVarsFile = "terragrunt_patch_variables.tf"
+
+	// The prefix of the names of the injected variables.
+	injectedVarPrefix = "terragrunt_patch_"
+)
+
+// providerPatch is how the provider blocks are patched.
+type providerPatch struct {
+	// The types of the provider blocks patched, e.g. "aws".
+	providerTypes []string
+	// The attributes to override, with their values in JSON.
+	attributes map[string]string
+	// Set the attributes, and their nested blocks, even in the blocks that don't set them.
+	setMissing bool
+	// Set the attributes to injected variables, instead of the values.
+	injectVars bool
+}
+
+func newProviderPatch(opts *options.TerragruntOptions) *providerPatch {
+	providerTypes := opts.AwsProviderPatchProviders
+	if len(providerTypes) == 0 {
+		providerTypes = []string{defaultProviderType}
+	}
+	return &providerPatch{
+		providerTypes: providerTypes,
+		attributes:    opts.AwsProviderPatchOverrides,
+		setMissing:    opts.AwsProviderPatchSetMissing,
+		injectVars:    opts.AwsProviderPatchInjectVars,
+	}
+}
 
 func Run(ctx context.Context, opts *options.TerragruntOptions) error {
 	target := terraform.NewTarget(terraform.TargetPointInitCommand, runAwsProviderPatch)
@@ -60,35 +105,126 @@ func runAwsProviderPatch(ctx context.Context, opts *options.TerragruntOptions, c
 	if len(opts.AwsProviderPatchOverrides) == 0 {
 		return errors.WithStackTrace(MissingOverrideAttrError(FlagNameTerragruntOverrideAttr))
 	}
+	patch := newProviderPatch(opts)
 
 	terraformFilesInModules, err := findAllTerraformFilesInModules(opts)
 	if err != nil {
 		return err
 	}
 
+	// The variables injected into the patched modules, by module dir
+	injectedVars := map[string]map[string]cty.Value{}
+
 	for _, terraformFile := range terraformFilesInModules {
+		if filepath.Base(terraformFile) == injectedVarsFile {
+			continue
+		}
+
 		opts.Logger.Debugf("Looking at file %s", terraformFile)
 		originalTerraformFileContents, err := util.ReadFileAsString(terraformFile)
 		if err != nil {
 			return err
 		}
 
-		updatedTerraformFileContents, codeWasUpdated, err := patchAwsProviderInTerraformCode(originalTerraformFileContents, terraformFile, opts.AwsProviderPatchOverrides)
+		moduleDir := filepath.Dir(terraformFile)
+		if injectedVars[moduleDir] == nil {
+			injectedVars[moduleDir] = map[string]cty.Value{}
+		}
+
+		updatedTerraformFileContents, codeWasUpdated, err := patch.patchTerraformCode(originalTerraformFileContents, terraformFile, injectedVars[moduleDir])
 		if err != nil {
 			return err
 		}
 
 		if codeWasUpdated {
-			opts.Logger.Debugf("Patching AWS provider in %s", terraformFile)
-			if err := util.WriteFileWithSamePermissions(terraformFile, terraformFile, []byte(updatedTerraformFileContents)); err != nil {
+			opts.Logger.Debugf("Patching providers in %s", terraformFile)
+			if err := writePatchedFile(opts, terraformFile, originalTerraformFileContents, updatedTerraformFileContents); err != nil {
 				return err
 			}
 		}
 	}
 
+	moduleDirs := make([]string, 0, len(injectedVars))
+	for moduleDir := range injectedVars {
+		moduleDirs = append(moduleDirs, moduleDir)
+	}
+	sort.Strings(moduleDirs)
+
+	for _, moduleDir := range moduleDirs {
+		if len(injectedVars[moduleDir]) == 0 {
+			continue
+		}
+
+		varsFile := filepath.Join(moduleDir, injectedVarsFile)
+		originalVarsFileContents := ""
+		if util.FileExists(varsFile) {
+			if originalVarsFileContents, err = util.ReadFileAsString(varsFile); err != nil {
+				return err
+			}
+		}
+
+		opts.Logger.Debugf("Declaring the injected variables in %s", varsFile)
+		if err := writePatchedFile(opts, varsFile, originalVarsFileContents, injectedVarsCode(injectedVars[moduleDir])); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// writePatchedFile writes the patched contents of the given file or, in dry run, prints their diff with the original
+// contents instead.
+func writePatchedFile(opts *options.TerragruntOptions, path string, originalContents string, updatedContents string) error {
+	if updatedContents == originalContents {
+		return nil
+	}
+
+	if opts.AwsProviderPatchDryRun {
+		displayPath := path
+		if relPath, err := util.GetPathRelativeTo(path, opts.WorkingDir); err == nil {
+			displayPath = relPath
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(originalContents),
+			B:        difflib.SplitLines(updatedContents),
+			FromFile: displayPath,
+			ToFile:   displayPath,
+			Context:  3,
+		})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		_, err = fmt.Fprint(opts.Writer, diff)
+		return errors.WithStackTrace(err)
+	}
+
+	if !util.FileExists(path) {
+		return errors.WithStackTrace(os.WriteFile(path, []byte(updatedContents), 0644))
+	}
+	return util.WriteFileWithSamePermissions(path, path, []byte(updatedContents))
+}
+
+// injectedVarsCode returns the code declaring the given injected variables, with the values as their defaults.
+func injectedVarsCode(vars map[string]cty.Value) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hclFile := hclwrite.NewEmptyFile()
+	for i, name := range names {
+		if i > 0 {
+			hclFile.Body().AppendNewline()
+		}
+		body := hclFile.Body().AppendNewBlock("variable", []string{name}).Body()
+		body.SetAttributeValue("description", cty.StringVal("Injected by terragrunt aws-provider-patch."))
+		body.SetAttributeValue("default", vars[name])
+	}
+	return fmt.Sprintf("%s%s\n%s", codegen.DefaultCommentPrefix, codegen.TerragruntGeneratedSignature, hclFile.Bytes())
+}
+
 // The format we expect in the .terraform/modules/modules.json file
 type TerraformModulesJson struct {
 	Modules []TerraformModule
@@ -132,6 +268,7 @@ func findAllTerraformFilesInModules(opts *options.TerragruntOptions) ([]string,
 
 	var terraformFiles []string
 
+	// The manifest lists the nested modules, at any depth, alongside the modules calling them
 	for _, module := range terraformModulesJson.Modules {
 		if module.Key != "" && module.Dir != "" {
 			moduleAbsPath := module.Dir
@@ -151,12 +288,17 @@ func findAllTerraformFilesInModules(opts *options.TerragruntOptions) ([]string,
 		}
 	}
 
+	// The nested modules within the dir of the modules calling them are matched by both modules
+	terraformFiles = util.RemoveDuplicatesFromList(terraformFiles)
+	sort.Strings(terraformFiles)
+
 	return terraformFiles, nil
 }
 
-// patchAwsProviderInTerraformCode looks for provider "aws" { ... } blocks in the given Terraform code and overwrites
-// the attributes in those provider blocks with the given attributes. It returns the new Terraform code and a boolean
-// true if that code was updated.
+// patchTerraformCode looks for the provider blocks of the patched types, e.g. provider "aws" { ... }, in the given
+// Terraform code and overwrites the attributes in those provider blocks with the given attributes. It returns the new
+// Terraform code and a boolean true if that code was updated. The variables injected into the code, if the patch
+// injects them, are added to injectedVars.
 //
 // For example, if you passed in the following Terraform code:
 //
@@ -164,17 +306,23 @@ func findAllTerraformFilesInModules(opts *options.TerragruntOptions) ([]string,
 //	   region = var.aws_region
 //	}
 //
-// And you set attributesToOverride to map[string]string{"region": "us-east-1"}, then this method will return:
+// And you set the attributes to map[string]string{"region": "us-east-1"}, then this method will return:
 //
 //	provider "aws" {
 //	   region = "us-east-1"
 //	}
 //
+// Or, if the patch injects the variables:
+//
+//	provider "aws" {
+//	   region = var.terragrunt_patch_aws_region
+//	}
+//
 // This is a temporary workaround for a Terraform bug (https://github.com/hashicorp/terraform/issues/13018) where
 // any dynamic values in nested provider blocks are not handled correctly when you call 'terraform import', so by
 // temporarily hard-coding them, we can allow 'import' to work.
-func patchAwsProviderInTerraformCode(terraformCode string, terraformFilePath string, attributesToOverride map[string]string) (string, bool, error) {
-	if len(attributesToOverride) == 0 {
+func (patch *providerPatch) patchTerraformCode(terraformCode string, terraformFilePath string, injectedVars map[string]cty.Value) (string, bool, error) {
+	if len(patch.attributes) == 0 {
 		return terraformCode, false, nil
 	}
 
@@ -183,26 +331,59 @@ func patchAwsProviderInTerraformCode(terraformCode string, terraformFilePath str
 		return "", false, errors.WithStackTrace(err)
 	}
 
+	// The missing attributes are added in the order of their keys
+	keys := make([]string, 0, len(patch.attributes))
+	for key := range patch.attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	codeWasUpdated := false
 
 	for _, block := range hclFile.Body().Blocks() {
-		if block.Type() == "provider" && len(block.Labels()) == 1 && block.Labels()[0] == "aws" {
-			for key, value := range attributesToOverride {
-				attributeOverridden, err := overrideAttributeInBlock(block, key, value)
-				if err != nil {
-					return string(hclFile.Bytes()), codeWasUpdated, err
-				}
-				codeWasUpdated = codeWasUpdated || attributeOverridden
+		if block.Type() != "provider" || len(block.Labels()) != 1 || !util.ListContainsElement(patch.providerTypes, block.Labels()[0]) {
+			continue
+		}
+		providerType := block.Labels()[0]
+
+		for _, key := range keys {
+			value := patch.attributes[key]
+			injectedVar := ""
+			if patch.injectVars {
+				injectedVar = injectedVarName(providerType, key)
 			}
+
+			attributeOverridden, ctyVal, err := overrideAttributeInBlock(block, key, value, patch.setMissing, injectedVar)
+			if err != nil {
+				return string(hclFile.Bytes()), codeWasUpdated, err
+			}
+			if attributeOverridden && injectedVar != "" && injectedVars != nil {
+				injectedVars[injectedVar] = ctyVal
+			}
+			codeWasUpdated = codeWasUpdated || attributeOverridden
 		}
 	}
 
 	return string(hclFile.Bytes()), codeWasUpdated, nil
 }
 
+// injectedVarName returns the name of the variable injected for the given attribute of the given provider type, e.g.
+// terragrunt_patch_aws_assume_role_role_arn for the attribute assume_role.role_arn of aws.
+func injectedVarName(providerType string, key string) string {
+	name := injectedVarPrefix + providerType + "_" + key
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
 // Override the attribute specified in the given key to the given value in a Terraform block: that is, if the attribute
-// is already set, then update its value to the new value; if the attribute is not already set, do nothing. This method
-// returns true if an attribute was overridden and false if nothing was changed.
+// is already set, then update its value to the new value; if the attribute is not already set, do nothing, unless
+// setMissing is true, in which case the attribute, and its nested blocks, are added. If injectedVar is set, the
+// attribute is set to a reference to the variable instead of the value. This method returns true if an attribute was
+// overridden and false if nothing was changed, along with the value.
 //
 // Note that you can set attributes within nested blocks by using a dot syntax similar to Terraform addresses: e.g.,
 // "<NESTED_BLOCK>.<KEY>".
@@ -220,8 +401,8 @@ func patchAwsProviderInTerraformCode(terraformCode string, terraformFilePath str
 //
 // If you call:
 //
-// overrideAttributeInBlock(block1, "region", "eu-west-1")
-// overrideAttributeInBlock(block1, "assume_role.role_arn", "foo")
+// overrideAttributeInBlock(block1, "region", "eu-west-1", false, "")
+// overrideAttributeInBlock(block1, "assume_role.role_arn", "foo", false, "")
 //
 // The result would be:
 //
@@ -238,19 +419,19 @@ func patchAwsProviderInTerraformCode(terraformCode string, terraformFilePath str
 //
 // If you call:
 //
-// overrideAttributeInBlock(block2, "region", "eu-west-1")
-// overrideAttributeInBlock(block2, "assume_role.role_arn", "foo")
+// overrideAttributeInBlock(block2, "region", "eu-west-1", false, "")
+// overrideAttributeInBlock(block2, "assume_role.role_arn", "foo", false, "")
 //
 // The result would be:
 //
 // provider "aws" {}
 //
 // Returns an error if the provided value is not valid json.
-func overrideAttributeInBlock(block *hclwrite.Block, key string, value string) (bool, error) {
-	body, attr := traverseBlock(block, strings.Split(key, "."))
-	if body == nil || body.GetAttribute(attr) == nil {
+func overrideAttributeInBlock(block *hclwrite.Block, key string, value string, setMissing bool, injectedVar string) (bool, cty.Value, error) {
+	body, attr := traverseBlock(block, strings.Split(key, "."), setMissing)
+	if body == nil || (body.GetAttribute(attr) == nil && !setMissing) {
 		// We didn't find an existing block or attribute, so there's nothing to override
-		return false, nil
+		return false, cty.NilVal, nil
 	}
 
 	// The cty library requires concrete types, but since the value is user provided, we don't have a way to know the
@@ -262,17 +443,21 @@ func overrideAttributeInBlock(block *hclwrite.Block, key string, value string) (
 	if err != nil {
 		// Wrap error in a custom error type that has better error messaging to the user.
 		returnErr := TypeInferenceError{value: value, underlyingErr: err}
-		return false, errors.WithStackTrace(returnErr)
+		return false, cty.NilVal, errors.WithStackTrace(returnErr)
 	}
 	ctyVal, err := ctyjson.Unmarshal(valueBytes, ctyType)
 	if err != nil {
 		// Wrap error in a custom error type that has better error messaging to the user.
 		returnErr := MalformedJSONValError{value: value, underlyingErr: err}
-		return false, errors.WithStackTrace(returnErr)
+		return false, cty.NilVal, errors.WithStackTrace(returnErr)
 	}
 
-	body.SetAttributeValue(attr, ctyVal)
-	return true, nil
+	if injectedVar != "" {
+		body.SetAttributeTraversal(attr, hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: injectedVar}})
+	} else {
+		body.SetAttributeValue(attr, ctyVal)
+	}
+	return true, ctyVal, nil
 }
 
 // Given a Terraform block and slice of keys, return the body of the block that is indicated by the keys, and the
@@ -280,7 +465,7 @@ func overrideAttributeInBlock(block *hclwrite.Block, key string, value string) (
 // and the one entry in the slice. However, if the slice contains multiple values, those indicate nested blocks, so
 // this method will recursively descend into those blocks and return the body of the final one and the final entry in
 // the slice to set on it. If a nested block is specified that doesn't actually exist, this method returns a nil body
-// and empty string for the attribute.
+// and empty string for the attribute, unless create is true, in which case the nested block is added.
 //
 // Examples:
 //
@@ -293,22 +478,22 @@ func overrideAttributeInBlock(block *hclwrite.Block, key string, value string) (
 //	  }
 //	}
 //
-// traverseBlock(block, []string{"region"})
+// traverseBlock(block, []string{"region"}, false)
 //
 //	=> returns (<body of the current block>, "region")
 //
-// traverseBlock(block, []string{"assume_role", "role_arn"})
+// traverseBlock(block, []string{"assume_role", "role_arn"}, false)
 //
 //	=> returns (<body of the nested assume_role block>, "role_arn")
 //
-// traverseBlock(block, []string{"foo"})
+// traverseBlock(block, []string{"foo"}, false)
 //
 //	=> returns (nil, "")
 //
-// traverseBlock(block, []string{"assume_role", "foo"})
+// traverseBlock(block, []string{"assume_role", "foo"}, false)
 //
 //	=> returns (nil, "")
-func traverseBlock(block *hclwrite.Block, keyParts []string) (*hclwrite.Body, string) {
+func traverseBlock(block *hclwrite.Block, keyParts []string, create bool) (*hclwrite.Body, string) {
 	if block == nil {
 		return nil, ""
 	}
@@ -318,5 +503,9 @@ func traverseBlock(block *hclwrite.Block, keyParts []string) (*hclwrite.Body, st
 	}
 
 	blockName := keyParts[0]
-	return traverseBlock(block.Body().FirstMatchingBlock(blockName, nil), keyParts[1:])
+	nestedBlock := block.Body().FirstMatchingBlock(blockName, nil)
+	if nestedBlock == nil && create {
+		nestedBlock = block.Body().AppendNewBlock(blockName, nil)
+	}
+	return traverseBlock(nestedBlock, keyParts[1:], create)
 }
@@ -1,9 +1,19 @@
 package awsproviderpatch
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const terraformCodeExampleOutputOnly = `
@@ -317,7 +327,7 @@ func TestPatchAwsProviderInTerraformCodeHappyPath(t *testing.T) {
 		testCase := testCase
 		t.Run(testCase.testName, func(t *testing.T) {
 			t.Parallel()
-			actualTerraformCode, actualCodeWasUpdated, err := patchAwsProviderInTerraformCode(testCase.originalTerraformCode, "test.tf", testCase.attributesToOverride)
+			actualTerraformCode, actualCodeWasUpdated, err := (&providerPatch{providerTypes: []string{"aws"}, attributes: testCase.attributesToOverride}).patchTerraformCode(testCase.originalTerraformCode, "test.tf", nil)
 			assert.NoError(t, err)
 			assert.Equal(t, testCase.expectedCodeWasUpdated, actualCodeWasUpdated)
 
@@ -330,3 +340,99 @@ func TestPatchAwsProviderInTerraformCodeHappyPath(t *testing.T) {
 		})
 	}
 }
+
+func TestPatchTerraformCodeOtherProviders(t *testing.T) {
+	t.Parallel()
+
+	patch := &providerPatch{providerTypes: []string{"google"}, attributes: map[string]string{"region": `"europe-west1"`}}
+	actualTerraformCode, codeWasUpdated, err := patch.patchTerraformCode(terraformCodeExampleGcpProvider, "test.tf", nil)
+	require.NoError(t, err)
+	assert.True(t, codeWasUpdated)
+	assert.Equal(t, `
+provider "google" {
+  credentials = file("account.json")
+  project     = "my-project-id"
+  region      = "europe-west1"
+}
+
+output "hello" {
+  value = "Hello, World"
+}
+`, actualTerraformCode)
+}
+
+func TestPatchTerraformCodeSetMissing(t *testing.T) {
+	t.Parallel()
+
+	patch := &providerPatch{
+		providerTypes: []string{"aws"},
+		attributes:    map[string]string{"region": `"eu-west-1"`, "assume_role.role_arn": `"arn:aws:iam::123456789012:role/deploy"`},
+		setMissing:    true,
+	}
+	actualTerraformCode, codeWasUpdated, err := patch.patchTerraformCode(terraformCodeExampleAwsProviderEmptyOriginal, "test.tf", nil)
+	require.NoError(t, err)
+	assert.True(t, codeWasUpdated)
+	assert.Equal(t, `
+provider "aws" {
+  assume_role {
+    role_arn = "arn:aws:iam::123456789012:role/deploy"
+  }
+  region = "eu-west-1"
+}
+
+output "hello" {
+  value = "Hello, World"
+}
+`, actualTerraformCode)
+}
+
+func TestPatchTerraformCodeInjectVars(t *testing.T) {
+	t.Parallel()
+
+	patch := &providerPatch{
+		providerTypes: []string{"aws"},
+		attributes:    map[string]string{"region": `"eu-west-1"`, "assume_role.role_arn": `"nested-override"`},
+		injectVars:    true,
+	}
+	injectedVars := map[string]cty.Value{}
+	actualTerraformCode, codeWasUpdated, err := patch.patchTerraformCode(terraformCodeExampleAwsOneProviderNestedBlocks, "test.tf", injectedVars)
+	require.NoError(t, err)
+	assert.True(t, codeWasUpdated)
+	assert.Contains(t, actualTerraformCode, "region = var.terragrunt_patch_aws_region")
+	assert.Contains(t, actualTerraformCode, "role_arn = var.terragrunt_patch_aws_assume_role_role_arn")
+	assert.Equal(t, map[string]cty.Value{
+		"terragrunt_patch_aws_region":               cty.StringVal("eu-west-1"),
+		"terragrunt_patch_aws_assume_role_role_arn": cty.StringVal("nested-override"),
+	}, injectedVars)
+
+	varsCode := injectedVarsCode(injectedVars)
+	assert.Contains(t, varsCode, codegen.TerragruntGeneratedSignature)
+	_, diags := hclwrite.ParseConfig([]byte(varsCode), injectedVarsFile, hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	assert.Contains(t, varsCode, `variable "terragrunt_patch_aws_region" {`)
+	assert.Contains(t, varsCode, `default     = "eu-west-1"`)
+}
+
+func TestWritePatchedFileDryRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.tf")
+	require.NoError(t, os.WriteFile(path, []byte(terraformCodeExampleAwsProviderNonEmptyOriginal), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(dir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = dir
+	opts.AwsProviderPatchDryRun = true
+	var out bytes.Buffer
+	opts.Writer = &out
+
+	require.NoError(t, writePatchedFile(opts, path, terraformCodeExampleAwsProviderNonEmptyOriginal, terraformCodeExampleAwsProviderRegionOverridenVersionNotOverriddenExpected))
+	assert.Contains(t, out.String(), "--- main.tf\n+++ main.tf\n")
+	assert.Contains(t, out.String(), "-  region  = var.aws_region\n+  region  = \"eu-west-1\"\n")
+
+	// The file is not patched in dry run
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, terraformCodeExampleAwsProviderNonEmptyOriginal, string(contents))
+}
@@ -8,7 +8,11 @@ import (
 const (
 	CommandName = "aws-provider-patch"
 
-	FlagNameTerragruntOverrideAttr = "terragrunt-override-attr"
+	FlagNameTerragruntOverrideAttr    = "terragrunt-override-attr"
+	FlagNameTerragruntPatchProvider   = "terragrunt-patch-provider"
+	FlagNameTerragruntPatchSetMissing = "terragrunt-patch-set-missing"
+	FlagNameTerragruntPatchInjectVars = "terragrunt-patch-inject-vars"
+	FlagNameTerragruntPatchDryRun     = "terragrunt-patch-dry-run"
 )
 
 func NewFlags(opts *options.TerragruntOptions) cli.Flags {
@@ -19,13 +23,37 @@ func NewFlags(opts *options.TerragruntOptions) cli.Flags {
 			EnvVar:      "TERRAGRUNT_EXCLUDE_DIR",
 			Usage:       "A key=value attribute to override in a provider block as part of the aws-provider-patch command. May be specified multiple times.",
 		},
+		&cli.SliceFlag[string]{
+			Name:        FlagNameTerragruntPatchProvider,
+			Destination: &opts.AwsProviderPatchProviders,
+			EnvVar:      "TERRAGRUNT_PATCH_PROVIDER",
+			Usage:       "The type of the provider blocks to patch, instead of aws. May be specified multiple times.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntPatchSetMissing,
+			Destination: &opts.AwsProviderPatchSetMissing,
+			EnvVar:      "TERRAGRUNT_PATCH_SET_MISSING",
+			Usage:       "Set the overridden attributes, and their nested blocks, even in the provider blocks that don't set them.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntPatchInjectVars,
+			Destination: &opts.AwsProviderPatchInjectVars,
+			EnvVar:      "TERRAGRUNT_PATCH_INJECT_VARS",
+			Usage:       "Set the overridden attributes to variables declared in the patched modules, with the values as their defaults.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntPatchDryRun,
+			Destination: &opts.AwsProviderPatchDryRun,
+			EnvVar:      "TERRAGRUNT_PATCH_DRY_RUN",
+			Usage:       "Print the diff of the patched files, without patching them.",
+		},
 	}
 }
 
 func NewCommand(opts *options.TerragruntOptions) *cli.Command {
 	return &cli.Command{
 		Name:   CommandName,
-		Usage:  "Overwrite settings on nested AWS providers to work around a Terraform bug (issue #13018).",
+		Usage:  "Overwrite settings on nested AWS providers, or providers of other types, to work around a Terraform bug (issue #13018).",
 		Flags:  NewFlags(opts).Sort(),
 		Action: func(ctx *cli.Context) error { return Run(ctx, opts.OptionsFromContext(ctx)) },
 	}
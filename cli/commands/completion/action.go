@@ -0,0 +1,103 @@
+package completion
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The completion scripts of the supported shells, formatted with the path to the terragrunt binary. They all let the
+// shell run terragrunt with the line being completed in COMP_LINE, see pkg/cli.
+var shellScripts = map[string]string{
+	"bash": "complete -o default -C %[1]q terragrunt\n",
+	"zsh":  "autoload -U +X bashcompinit && bashcompinit\ncomplete -o default -C %[1]q terragrunt\n",
+	"fish": `function __complete_terragrunt
+    set -lx COMP_LINE (commandline -cp)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    %[1]q
+end
+complete -f -c terragrunt -a "(__complete_terragrunt)"
+`,
+}
+
+func Run(opts *options.TerragruntOptions, shell string) error {
+	script, ok := shellScripts[shell]
+	if !ok {
+		return errors.WithStackTrace(UnsupportedShell(shell))
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "terragrunt"
+	}
+
+	if _, err := fmt.Fprintf(opts.Writer, script, executable); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// CompleteModuleDirs suggests the dirs, starting with the given arg, that are or contain Terragrunt modules, by scanning
+// the dir of the arg for Terragrunt config files. Hidden dirs, such as the Terragrunt cache, are not scanned.
+func CompleteModuleDirs(_ *cli.Context, arg string) []string {
+	root := "."
+	if filepath.IsAbs(arg) {
+		root = filepath.Dir(arg)
+	}
+
+	dirs := map[string]bool{}
+	_ = filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !util.ListContainsElement(config.DefaultTerragruntConfigPaths, entry.Name()) {
+			return nil
+		}
+
+		// The module dir and all its parent dirs under the root are suggested
+		for dir := filepath.Dir(path); dir != root && dir != "."; dir = filepath.Dir(dir) {
+			dirs[filepath.ToSlash(dir)] = true
+		}
+		return nil
+	})
+
+	var suggestions []string
+	for dir := range dirs {
+		if strings.HasPrefix(dir, arg) {
+			suggestions = append(suggestions, dir)
+		}
+	}
+	sort.Strings(suggestions)
+	return suggestions
+}
+
+// Custom error types
+
+type UnsupportedShell string
+
+func (err UnsupportedShell) Error() string {
+	var shells []string
+	for shell := range shellScripts {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+	return fmt.Sprintf("Unsupported shell %q, must be one of: %s", string(err), strings.Join(shells, ", "))
+}
@@ -0,0 +1,40 @@
+package completion
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestCompleteModuleDirs(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.ToSlash(t.TempDir())
+	for _, path := range []string{"live/prod/vpc/terragrunt.hcl", "live/prod/.terragrunt-cache/abc/terragrunt.hcl", "live/stage/app/terragrunt.hcl.json", "lib/main.tf"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte{}, 0644))
+	}
+
+	assert.Equal(t, []string{root + "/live", root + "/live/prod", root + "/live/prod/vpc", root + "/live/stage", root + "/live/stage/app"}, CompleteModuleDirs(nil, root+"/l"))
+	assert.Equal(t, []string{root + "/live/stage", root + "/live/stage/app"}, CompleteModuleDirs(nil, root+"/live/s"))
+}
+
+func TestRunUnsupportedShell(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+	var output bytes.Buffer
+	opts.Writer = &output
+
+	require.NoError(t, Run(opts, "fish"))
+	assert.Contains(t, output.String(), "complete -f -c terragrunt")
+
+	assert.Error(t, Run(opts, "tcsh"))
+}
@@ -0,0 +1,21 @@
+package completion
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "completion"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:      CommandName,
+		Usage:     "Prints the shell completion script for bash, zsh or fish.",
+		UsageText: "terragrunt completion <bash|zsh|fish>",
+		Description: "Prints the shell completion script for the given shell, completing commands, flags and module paths. " +
+			"For example, add `source <(terragrunt completion bash)` to ~/.bashrc.",
+		Action: func(ctx *cli.Context) error { return Run(opts, ctx.Args().First()) },
+	}
+}
@@ -0,0 +1,35 @@
+package debug
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName     = "debug"
+	SubCommandMerge = "merge"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:                   CommandName,
+		Usage:                  "Debug how terragrunt reads the config of the module.",
+		DisallowUndefinedFlags: true,
+		Subcommands:            subCommands(opts),
+		Action: func(ctx *cli.Context) error {
+			return errors.WithStackTrace(MissingDebugger{})
+		},
+	}
+}
+
+func subCommands(opts *options.TerragruntOptions) cli.Commands {
+	return cli.Commands{
+		&cli.Command{
+			Name:        SubCommandMerge,
+			Usage:       "Show step by step how each include transforms the config of the module.",
+			Description: "The command reads the config of the module, and shows for each included config, in the order they are merged in, the merge strategy and the blocks and attributes the merge adds, overrides, merges or removes.",
+			Action:      func(ctx *cli.Context) error { return RunMerge(ctx, opts.OptionsFromContext(ctx)) },
+		},
+	}
+}
@@ -0,0 +1,13 @@
+package debug
+
+import (
+	"fmt"
+)
+
+// Custom error types
+
+type MissingDebugger struct{}
+
+func (err MissingDebugger) Error() string {
+	return fmt.Sprintf("Missing what to debug, e.g. terragrunt %s %s.", CommandName, SubCommandMerge)
+}
@@ -0,0 +1,205 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The changes an include makes to a block or attribute of the config.
+const (
+	// The included config sets the block or attribute, the config does not.
+	changeAdded = "added"
+	// Both configs set the block or attribute, and the value of the config is kept.
+	changeOverridden = "overridden"
+	// Both configs set the block or attribute, and the values are merged, or the value of the included config is kept.
+	changeMerged = "merged"
+	// The config sets the block or attribute, and the merge removes it, e.g. with the no_merge strategy.
+	changeRemoved = "removed"
+	// The included config sets the block or attribute, and the merge does not add it, e.g. with the no_merge strategy.
+	changeNotMerged = "not merged"
+)
+
+// The locals are not merged by design.
+var untracedMergeFields = []string{config.MetadataLocals}
+
+// MergeChange is a change an include makes to a block or attribute of the config, e.g. `inputs.region`.
+type MergeChange struct {
+	Path   string
+	Change string
+}
+
+func RunMerge(ctx context.Context, opts *options.TerragruntOptions) error {
+	if !util.FileExists(opts.TerragruntConfigPath) {
+		return errors.WithStackTrace(config.TerragruntConfigNotFoundError{Path: opts.TerragruntConfigPath})
+	}
+
+	var steps []config.IncludeMergeStep
+	parsingCtx := config.NewParsingContext(ctx, opts)
+	parsingCtx.TraceIncludeMergeFunc = func(step config.IncludeMergeStep) {
+		// The configs of the dependencies and the partial parses are merged too, only the full merge of the module is traced
+		if step.ConfigPath == opts.TerragruntConfigPath && !step.IsPartial {
+			steps = append(steps, step)
+		}
+	}
+
+	if _, err := config.ParseConfigFile(opts, parsingCtx, opts.TerragruntConfigPath, nil); err != nil {
+		return err
+	}
+
+	return writeMergeSteps(opts.Writer, opts.TerragruntConfigPath, steps)
+}
+
+func writeMergeSteps(writer io.Writer, configPath string, steps []config.IncludeMergeStep) error {
+	var lines []string
+	if len(steps) == 0 {
+		lines = append(lines, fmt.Sprintf("The config %s includes no config.", configPath))
+	} else {
+		lines = append(lines, fmt.Sprintf("Merge of the includes of %s, in the order they are merged in:", configPath))
+	}
+
+	for i, step := range steps {
+		lines = append(lines, "", fmt.Sprintf("%d. include %q (%s) with the strategy %s%s", i+1, step.Include.Name, step.Include.Path, step.MergeStrategy, fieldStrategiesString(step.FieldStrategies)))
+
+		changes := MergeStepChanges(step)
+		if len(changes) == 0 {
+			lines = append(lines, "   No changes")
+		}
+		for _, change := range changes {
+			lines = append(lines, fmt.Sprintf("   %-10s  %s", change.Change, change.Path))
+		}
+	}
+
+	_, err := fmt.Fprintln(writer, strings.Join(lines, "\n"))
+	return errors.WithStackTrace(err)
+}
+
+func fieldStrategiesString(strategies map[string]config.MergeStrategyType) string {
+	if len(strategies) == 0 {
+		return ""
+	}
+
+	var fields []string
+	for field, strategy := range strategies {
+		fields = append(fields, fmt.Sprintf("%s = %s", field, strategy))
+	}
+	sort.Strings(fields)
+	return fmt.Sprintf(" (%s)", strings.Join(fields, ", "))
+}
+
+// MergeStepChanges returns the changes the given merge step makes to the blocks and attributes of the config, sorted by
+// path. The blocks and maps both configs set are compared key by key.
+func MergeStepChanges(step config.IncludeMergeStep) []MergeChange {
+	var changes []MergeChange
+	for _, key := range objectKeys(step.Before, step.Included, step.After) {
+		if util.ListContainsElement(untracedMergeFields, key) {
+			continue
+		}
+		changes = append(changes, valueChanges(key, attribute(step.Before, key), attribute(step.Included, key), attribute(step.After, key))...)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func valueChanges(path string, before, included, after cty.Value) []MergeChange {
+	if isSet(before) && isSet(included) && isObject(before) && isObject(included) && isObject(after) {
+		var changes []MergeChange
+		for _, key := range objectKeys(before, included, after) {
+			changes = append(changes, valueChanges(path+"."+key, attribute(before, key), attribute(included, key), attribute(after, key))...)
+		}
+		return changes
+	}
+
+	var change string
+	switch {
+	case !isSet(included) && isSet(before) && !isSet(after):
+		change = changeRemoved
+	case !isSet(included):
+		return nil
+	case !isSet(before) && isSet(after):
+		change = changeAdded
+	case !isSet(before):
+		change = changeNotMerged
+	case !isSet(after):
+		change = changeRemoved
+	case after.RawEquals(before):
+		change = changeOverridden
+	default:
+		change = changeMerged
+	}
+	return []MergeChange{{Path: path, Change: change}}
+}
+
+// isSet returns true if the given value is set, i.e. neither null, an empty collection nor the zero value of the
+// attributes the config defaults, such as an empty string or false.
+func isSet(value cty.Value) bool {
+	if value == cty.NilVal || value.IsNull() {
+		return false
+	}
+	if !value.IsKnown() {
+		return true
+	}
+
+	switch ty := value.Type(); {
+	case ty.IsCollectionType() || ty.IsObjectType() || ty.IsTupleType():
+		return value.LengthInt() > 0
+	case ty == cty.String:
+		return value.AsString() != ""
+	case ty == cty.Bool:
+		return value.True()
+	}
+	return true
+}
+
+func isObject(value cty.Value) bool {
+	return value != cty.NilVal && value.IsKnown() && !value.IsNull() && (value.Type().IsObjectType() || value.Type().IsMapType())
+}
+
+// attribute returns the attribute, or the element, with the given key of the given object or map, or a null value.
+func attribute(value cty.Value, key string) cty.Value {
+	if !isObject(value) {
+		return cty.NilVal
+	}
+	if value.Type().IsObjectType() {
+		if !value.Type().HasAttribute(key) {
+			return cty.NilVal
+		}
+		return value.GetAttr(key)
+	}
+	if !value.HasIndex(cty.StringVal(key)).True() {
+		return cty.NilVal
+	}
+	return value.Index(cty.StringVal(key))
+}
+
+// objectKeys returns the keys of the given objects and maps, sorted.
+func objectKeys(values ...cty.Value) []string {
+	keys := map[string]bool{}
+	for _, value := range values {
+		if !isObject(value) {
+			continue
+		}
+		for key := range value.AsValueMap() {
+			keys[key] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,90 @@
+package debug
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunMerge(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "root.hcl"), []byte(`
+inputs = {
+  region = "us-east-1"
+  tags   = { team = "platform" }
+}
+
+remote_state {
+  backend = "local"
+  config  = {}
+}
+
+retryable_errors = ["(?s).*throttled.*"]
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "env.hcl"), []byte(`
+inputs = {
+  region = "eu-west-1"
+  env    = "dev"
+}
+`), 0644))
+
+	configPath := filepath.Join(dir, "app", config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), 0755))
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+include "root" {
+  path             = "../root.hcl"
+  merge_strategies = { inputs = "deep", retryable_errors = "no_merge" }
+}
+
+include "env" {
+  path = "../env.hcl"
+}
+
+inputs = {
+  tags = { app = "api" }
+}
+`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	opts.Writer = &out
+
+	require.NoError(t, RunMerge(context.Background(), opts))
+	assert.Equal(t, `Merge of the includes of `+configPath+`, in the order they are merged in:
+
+1. include "env" (../env.hcl) with the strategy shallow
+   added       inputs.env
+   added       inputs.region
+
+2. include "root" (../root.hcl) with the strategy shallow (inputs = deep, retryable_errors = no_merge)
+   overridden  inputs.region
+   added       inputs.tags.team
+   added       remote_state
+   not merged  retryable_errors
+`, out.String())
+}
+
+func TestRunMergeWithoutIncludes(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`inputs = { region = "us-east-1" }`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	var out bytes.Buffer
+	opts.Writer = &out
+
+	require.NoError(t, RunMerge(context.Background(), opts))
+	assert.Equal(t, "The config "+configPath+" includes no config.\n", out.String())
+}
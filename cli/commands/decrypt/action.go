@@ -0,0 +1,88 @@
+// `decrypt` command decrypts the values terragrunt encrypted in a JSON file with the key of the sensitive outputs,
+// e.g. the inputs of the debug file set from sensitive dependency outputs, so that the file can be passed to terraform.
+
+package decrypt
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/encryption"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func Run(ctx context.Context, opts *options.TerragruntOptions, file string) error {
+	if file == "" {
+		return errors.WithStackTrace(MissingFile{})
+	}
+
+	encrypter, err := encryption.NewEncrypter(opts)
+	if err != nil {
+		return err
+	}
+	if encrypter == nil {
+		return errors.WithStackTrace(MissingKey{})
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	var values interface{}
+	if err := json.Unmarshal(content, &values); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	decrypted, err := decryptValues(ctx, encrypter, values)
+	if err != nil {
+		return err
+	}
+
+	jsonContent, err := json.MarshalIndent(decrypted, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = opts.Writer.Write(append(jsonContent, '\n'))
+	return errors.WithStackTrace(err)
+}
+
+// decryptValues returns the value with the encrypted strings nested in it replaced with the JSON values they encrypt.
+func decryptValues(ctx context.Context, encrypter encryption.Encrypter, value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			decrypted, err := decryptValues(ctx, encrypter, item)
+			if err != nil {
+				return nil, err
+			}
+			value[key] = decrypted
+		}
+		return value, nil
+	case []interface{}:
+		for i, item := range value {
+			decrypted, err := decryptValues(ctx, encrypter, item)
+			if err != nil {
+				return nil, err
+			}
+			value[i] = decrypted
+		}
+		return value, nil
+	case string:
+		if !encryption.IsEncrypted(value) {
+			return value, nil
+		}
+		plaintext, err := encryption.DecryptValue(ctx, encrypter, value)
+		if err != nil {
+			return nil, err
+		}
+		var decrypted interface{}
+		if err := json.Unmarshal(plaintext, &decrypted); err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		return decrypted, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,43 @@
+package decrypt
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/encryption"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLocalKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func TestDecryptDebugFile(t *testing.T) {
+	t.Parallel()
+
+	encrypter, err := encryption.NewLocalEncrypter(testLocalKey)
+	require.NoError(t, err)
+	password, err := encryption.EncryptValue(context.Background(), encrypter, []byte(`"hunter2"`))
+	require.NoError(t, err)
+	users, err := encryption.EncryptValue(context.Background(), encrypter, []byte(`{"admin":"s3cr3t"}`))
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "terragrunt-debug.tfvars.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"address": "db.internal", "password": "`+password+`", "users": "`+users+`"}`), 0600))
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	var out bytes.Buffer
+	opts.Writer = &out
+
+	err = Run(context.Background(), opts, file)
+	assert.IsType(t, MissingKey{}, errors.Unwrap(err))
+
+	opts.SensitiveOutputsKey = testLocalKey
+	require.NoError(t, Run(context.Background(), opts, file))
+	assert.JSONEq(t, `{"address": "db.internal", "password": "hunter2", "users": {"admin": "s3cr3t"}}`, out.String())
+}
@@ -0,0 +1,21 @@
+package decrypt
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "decrypt"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:                   CommandName,
+		DisallowUndefinedFlags: true,
+		Usage:                  "Decrypt the values of sensitive dependency outputs encrypted in a JSON file, e.g. terragrunt-debug.tfvars.json, and print the file.",
+		Action: func(ctx *cli.Context) error {
+			return Run(ctx, opts.OptionsFromContext(ctx), ctx.Args().Get(0))
+		},
+	}
+}
@@ -0,0 +1,21 @@
+package decrypt
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands"
+)
+
+// Custom error types
+
+type MissingFile struct{}
+
+func (err MissingFile) Error() string {
+	return fmt.Sprintf("Missing the file to decrypt, e.g. terragrunt %s terragrunt-debug.tfvars.json", CommandName)
+}
+
+type MissingKey struct{}
+
+func (err MissingKey) Error() string {
+	return fmt.Sprintf("Missing the key to decrypt the file with. Pass the key the values were encrypted with via --%s or --%s.", commands.TerragruntSensitiveOutputsKeyFlagName, commands.TerragruntSensitiveOutputsKMSKeyIDFlagName)
+}
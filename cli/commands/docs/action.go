@@ -0,0 +1,221 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// StackDocs is the documentation of a stack.
+type StackDocs struct {
+	Name    string
+	Modules []*ModuleDocs
+}
+
+// ModuleDocs is the documentation of a module of the stack. The paths are relative to the working dir.
+type ModuleDocs struct {
+	Path         string
+	Source       string
+	Version      string
+	Dependencies []string
+	Inputs       []ModuleInput
+}
+
+// ModuleInput is an input of a module, with the expression it is set to, as the values are only known once the
+// dependencies are applied.
+type ModuleInput struct {
+	Name  string
+	Value string
+}
+
+// Run renders the documentation of the stack in the working dir, without running terraform, and writes it to the
+// output file or to stdout.
+func Run(ctx context.Context, opts *options.TerragruntOptions) error {
+	format, err := docsFormat(opts)
+	if err != nil {
+		return err
+	}
+
+	stackOpts := opts.Clone(opts.TerragruntConfigPath)
+	// The external dependencies are only listed as the dependencies of the modules
+	stackOpts.IgnoreExternalDependencies = true
+
+	stack, err := configstack.FindStackInSubfolders(ctx, stackOpts, nil)
+	if err != nil {
+		return err
+	}
+
+	stackDocs, err := newStackDocs(stack, opts.WorkingDir)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if format == FormatHTML {
+		err = renderHTML(&out, stackDocs)
+	} else {
+		err = renderMarkdown(&out, stackDocs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.DocsOutFile == "" {
+		_, err := opts.Writer.Write(out.Bytes())
+		return errors.WithStackTrace(err)
+	}
+
+	outFile := util.JoinPath(opts.WorkingDir, opts.DocsOutFile)
+	if err := os.MkdirAll(filepath.Dir(outFile), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(outFile, out.Bytes(), 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	opts.Logger.Infof("Wrote the documentation of %d modules to %s", len(stackDocs.Modules), outFile)
+	return nil
+}
+
+func docsFormat(opts *options.TerragruntOptions) (string, error) {
+	switch strings.ToLower(opts.DocsFormat) {
+	case "":
+		if strings.EqualFold(filepath.Ext(opts.DocsOutFile), ".html") {
+			return FormatHTML, nil
+		}
+		return FormatMarkdown, nil
+	case FormatMarkdown, "md":
+		return FormatMarkdown, nil
+	case FormatHTML:
+		return FormatHTML, nil
+	default:
+		return "", errors.WithStackTrace(InvalidDocsFormat(opts.DocsFormat))
+	}
+}
+
+func newStackDocs(stack *configstack.Stack, workingDir string) (*StackDocs, error) {
+	relPath := func(path string) string {
+		if relPath, err := util.GetPathRelativeTo(path, workingDir); err == nil {
+			return relPath
+		}
+		return path
+	}
+
+	stackDocs := &StackDocs{Name: filepath.Base(workingDir)}
+	for _, module := range stack.Modules {
+		moduleDocs := &ModuleDocs{Path: relPath(module.Path)}
+
+		if module.Config.Terraform != nil && module.Config.Terraform.Source != nil {
+			moduleDocs.Source = *module.Config.Terraform.Source
+			if filepath.IsAbs(moduleDocs.Source) {
+				moduleDocs.Source = relPath(moduleDocs.Source)
+			}
+			_, moduleDocs.Version = terraform.SourceVersion(moduleDocs.Source)
+		}
+
+		for _, dependency := range module.Dependencies {
+			moduleDocs.Dependencies = append(moduleDocs.Dependencies, relPath(dependency.Path))
+		}
+		sort.Strings(moduleDocs.Dependencies)
+
+		inputs, err := ConfigInputs(module.TerragruntOptions.TerragruntConfigPath, module.Config.ProcessedIncludes)
+		if err != nil {
+			return nil, err
+		}
+		moduleDocs.Inputs = inputs
+
+		stackDocs.Modules = append(stackDocs.Modules, moduleDocs)
+	}
+	sort.Slice(stackDocs.Modules, func(i, j int) bool {
+		return stackDocs.Modules[i].Path < stackDocs.Modules[j].Path
+	})
+	return stackDocs, nil
+}
+
+// ConfigInputs returns the inputs set in the given config and in the given configs it includes, with their
+// expressions. The inputs of the config take precedence over the included ones.
+func ConfigInputs(configPath string, includes config.IncludeConfigs) ([]ModuleInput, error) {
+	var configPaths []string
+	for _, include := range includes {
+		includePath := include.Path
+		if !filepath.IsAbs(includePath) {
+			includePath = util.JoinPath(filepath.Dir(configPath), includePath)
+		}
+		configPaths = append(configPaths, includePath)
+	}
+	sort.Strings(configPaths)
+	configPaths = append(configPaths, configPath)
+
+	expressions := map[string]string{}
+	for _, path := range configPaths {
+		if err := readInputExpressions(path, expressions); err != nil {
+			return nil, err
+		}
+	}
+
+	inputs := []ModuleInput{}
+	for name, value := range expressions {
+		inputs = append(inputs, ModuleInput{Name: name, Value: value})
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		return inputs[i].Name < inputs[j].Name
+	})
+	return inputs, nil
+}
+
+// readInputExpressions reads the expressions of the inputs set in the given config into the given map. Only the inputs
+// set as an object are read, e.g. not the ones merged from other values.
+func readInputExpressions(configPath string, expressions map[string]string) error {
+	// The inputs of the configs in JSON are not documented
+	if strings.HasSuffix(configPath, ".json") || !util.FileExists(configPath) {
+		return nil
+	}
+
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	file, diags := hclsyntax.ParseConfig(content, configPath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return errors.WithStackTrace(diags)
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok || body.Attributes["inputs"] == nil {
+		return nil
+	}
+	object, ok := body.Attributes["inputs"].Expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return nil
+	}
+
+	for _, item := range object.Items {
+		name := hcl.ExprAsKeyword(item.KeyExpr)
+		if name == "" {
+			key, diags := item.KeyExpr.Value(nil)
+			if diags.HasErrors() || !key.Type().Equals(cty.String) {
+				continue
+			}
+			name = key.AsString()
+		}
+		expressions[name] = string(item.ValueExpr.Range().SliceBytes(content))
+	}
+	return nil
+}
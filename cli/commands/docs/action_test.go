@@ -0,0 +1,107 @@
+package docs
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderStackDocs(t *testing.T) {
+	t.Parallel()
+
+	rootDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	writeFile := func(path, contents string) {
+		path = filepath.Join(rootDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+
+	writeFile("live/common.hcl", "inputs = {\n  env  = \"dev\"\n  tags = { team = \"platform\" }\n}\n")
+	writeFile("live/vpc/terragrunt.hcl", `
+terraform {
+  source = "git::https://example.com/modules.git//vpc?ref=v1.2.0"
+}
+
+inputs = {
+  cidr = "10.0.0.0/16"
+}
+`)
+	writeFile("live/app/terragrunt.hcl", `
+include "common" {
+  path = "../common.hcl"
+}
+
+terraform {
+  source = "tfr:///example/app/aws?version=2.0.0"
+}
+
+dependency "vpc" {
+  config_path = "../vpc"
+}
+
+inputs = {
+  env    = "staging"
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`)
+	writeFile("live/vpc/main.tf", "")
+	writeFile("live/app/main.tf", "")
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "live", "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = filepath.Join(rootDir, "live")
+	stdout := bytes.Buffer{}
+	opts.Writer = &stdout
+
+	require.NoError(t, Run(context.Background(), opts))
+
+	markdown := stdout.String()
+	assert.Contains(t, markdown, "# live\n")
+	assert.Contains(t, markdown, "```mermaid\ngraph TD\n  m0[\"app\"]\n  m1[\"vpc\"]\n  m1 --> m0\n```\n")
+	assert.Contains(t, markdown, "| app | `tfr:///example/app/aws?version=2.0.0` | `2.0.0` | vpc |\n")
+	assert.Contains(t, markdown, "| vpc | `git::https://example.com/modules.git//vpc?ref=v1.2.0` | `v1.2.0` | - |\n")
+	// The inputs of the module take precedence over the included ones
+	assert.Contains(t, markdown, "### app\n\n**Source**: `tfr:///example/app/aws?version=2.0.0`\n\n**Version**: `2.0.0`\n\n**Dependencies**: vpc\n\n"+
+		"| Input | Value |\n| --- | --- |\n| `env` | `\"staging\"` |\n| `tags` | `{ team = \"platform\" }` |\n| `vpc_id` | `dependency.vpc.outputs.vpc_id` |\n")
+
+	opts.DocsOutFile = "docs/stack.html"
+	require.NoError(t, Run(context.Background(), opts))
+
+	html, err := os.ReadFile(filepath.Join(rootDir, "live", "docs", "stack.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "<pre class=\"mermaid\">\ngraph TD\n  m0[&#34;app&#34;]\n  m1[&#34;vpc&#34;]\n  m1 --&gt; m0\n</pre>")
+	assert.Contains(t, string(html), `<h3 id="module-app">app</h3>`)
+	assert.Contains(t, string(html), `<tr><td><code>vpc_id</code></td><td><pre>dependency.vpc.outputs.vpc_id</pre></td></tr>`)
+}
+
+func TestDocsFormat(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		format   string
+		outFile  string
+		expected string
+	}{
+		{"", "", FormatMarkdown},
+		{"", "docs/README.md", FormatMarkdown},
+		{"", "docs/index.HTML", FormatHTML},
+		{"markdown", "docs/index.html", FormatMarkdown},
+		{"HTML", "", FormatHTML},
+	}
+
+	for _, testCase := range testCases {
+		format, err := docsFormat(&options.TerragruntOptions{DocsFormat: testCase.format, DocsOutFile: testCase.outFile})
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, format)
+	}
+
+	_, err := docsFormat(&options.TerragruntOptions{DocsFormat: "pdf"})
+	require.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package docs
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "docs"
+
+	FlagNameTerragruntDocsFormat = "terragrunt-docs-format"
+	FlagNameTerragruntDocsOut    = "terragrunt-docs-out"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDocsFormat,
+			Destination: &opts.DocsFormat,
+			EnvVar:      "TERRAGRUNT_DOCS_FORMAT",
+			Usage:       "The format to render the documentation of the stack in, markdown or html. Defaults to html if the output file has the .html extension, and to markdown otherwise.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDocsOut,
+			Destination: &opts.DocsOutFile,
+			EnvVar:      "TERRAGRUNT_DOCS_OUT",
+			Usage:       "The file to write the documentation of the stack to, instead of stdout.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:   CommandName,
+		Usage:  "Render the documentation of the stack: its modules, their sources, inputs and dependencies, and the dependency diagram.",
+		Flags:  NewFlags(opts).Sort(),
+		Action: func(ctx *cli.Context) error { return Run(ctx, opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -0,0 +1,11 @@
+package docs
+
+import "fmt"
+
+// Custom error types
+
+type InvalidDocsFormat string
+
+func (format InvalidDocsFormat) Error() string {
+	return fmt.Sprintf("Invalid value %q for --%s, expected %s or %s", string(format), FlagNameTerragruntDocsFormat, FormatMarkdown, FormatHTML)
+}
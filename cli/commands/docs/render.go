@@ -0,0 +1,167 @@
+package docs
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{ .Name }}</title>
+  <style>
+    body { font-family: sans-serif; margin: 2em; }
+    table { border-collapse: collapse; margin-bottom: 1em; }
+    th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+    td pre { margin: 0; }
+  </style>
+</head>
+<body>
+  <h1>{{ .Name }}</h1>
+  <p><em>Generated with <code>terragrunt docs</code>.</em></p>
+
+  <h2>Dependency diagram</h2>
+  <pre class="mermaid">
+{{ mermaid . }}</pre>
+
+  <h2>Modules</h2>
+  <table>
+    <tr><th>Module</th><th>Source</th><th>Version</th><th>Dependencies</th></tr>
+{{- range .Modules }}
+    <tr><td><a href="#{{ anchor .Path }}">{{ .Path }}</a></td><td><code>{{ .Source }}</code></td><td><code>{{ .Version }}</code></td><td>{{ join .Dependencies ", " }}</td></tr>
+{{- end }}
+  </table>
+{{ range .Modules }}
+  <h3 id="{{ anchor .Path }}">{{ .Path }}</h3>
+  {{- if .Source }}
+  <p><strong>Source</strong>: <code>{{ .Source }}</code></p>
+  {{- end }}
+  {{- if .Version }}
+  <p><strong>Version</strong>: <code>{{ .Version }}</code></p>
+  {{- end }}
+  {{- if .Dependencies }}
+  <p><strong>Dependencies</strong>: {{ range $i, $dependency := .Dependencies }}{{ if $i }}, {{ end }}<a href="#{{ anchor $dependency }}">{{ $dependency }}</a>{{ end }}</p>
+  {{- end }}
+  {{- if .Inputs }}
+  <table>
+    <tr><th>Input</th><th>Value</th></tr>
+  {{- range .Inputs }}
+    <tr><td><code>{{ .Name }}</code></td><td><pre>{{ .Value }}</pre></td></tr>
+  {{- end }}
+  </table>
+  {{- end }}
+{{ end }}
+  <script type="module">
+    import mermaid from "https://cdn.jsdelivr.net/npm/mermaid@10/dist/mermaid.esm.min.mjs";
+    mermaid.initialize({ startOnLoad: true });
+  </script>
+</body>
+</html>
+`
+
+// renderMarkdown renders the documentation of the stack in markdown, with the dependency diagram in mermaid, which is
+// rendered by GitHub and GitLab.
+func renderMarkdown(writer io.Writer, stackDocs *StackDocs) error {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "# %s\n\n_Generated with `terragrunt docs`._\n\n", stackDocs.Name)
+	fmt.Fprintf(&out, "## Dependency diagram\n\n```mermaid\n%s```\n\n", mermaidDiagram(stackDocs))
+
+	out.WriteString("## Modules\n\n| Module | Source | Version | Dependencies |\n| --- | --- | --- | --- |\n")
+	for _, module := range stackDocs.Modules {
+		fmt.Fprintf(&out, "| %s | %s | %s | %s |\n", MarkdownCell(module.Path), MarkdownCode(module.Source), MarkdownCode(module.Version), MarkdownCell(strings.Join(module.Dependencies, ", ")))
+	}
+
+	for _, module := range stackDocs.Modules {
+		fmt.Fprintf(&out, "\n### %s\n\n", module.Path)
+		if module.Source != "" {
+			fmt.Fprintf(&out, "**Source**: %s\n\n", MarkdownCode(module.Source))
+		}
+		if module.Version != "" {
+			fmt.Fprintf(&out, "**Version**: %s\n\n", MarkdownCode(module.Version))
+		}
+		if len(module.Dependencies) > 0 {
+			fmt.Fprintf(&out, "**Dependencies**: %s\n\n", strings.Join(module.Dependencies, ", "))
+		}
+		if len(module.Inputs) > 0 {
+			out.WriteString("| Input | Value |\n| --- | --- |\n")
+			for _, input := range module.Inputs {
+				fmt.Fprintf(&out, "| %s | %s |\n", MarkdownCode(input.Name), MarkdownCode(input.Value))
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(writer, strings.TrimRight(out.String(), "\n")+"\n")
+	return errors.WithStackTrace(err)
+}
+
+// renderHTML renders the documentation of the stack in a standalone HTML page, with the dependency diagram rendered by
+// mermaid.
+func renderHTML(writer io.Writer, stackDocs *StackDocs) error {
+	tmpl, err := template.New("docs").Funcs(template.FuncMap{
+		"mermaid": mermaidDiagram,
+		"anchor":  htmlAnchor,
+		"join":    strings.Join,
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(tmpl.Execute(writer, stackDocs))
+}
+
+// mermaidDiagram returns the mermaid flowchart of the dependencies of the stack, with the arrows from the dependencies
+// to the modules depending on them, i.e. in the order they are applied.
+func mermaidDiagram(stackDocs *StackDocs) string {
+	var out strings.Builder
+	out.WriteString("graph TD\n")
+
+	nodes := map[string]string{}
+	node := func(path string) string {
+		if id, ok := nodes[path]; ok {
+			return id
+		}
+		id := fmt.Sprintf("m%d", len(nodes))
+		nodes[path] = id
+		fmt.Fprintf(&out, "  %s[\"%s\"]\n", id, strings.ReplaceAll(path, `"`, "#quot;"))
+		return id
+	}
+
+	for _, module := range stackDocs.Modules {
+		node(module.Path)
+	}
+	for _, module := range stackDocs.Modules {
+		for _, dependency := range module.Dependencies {
+			fmt.Fprintf(&out, "  %s --> %s\n", node(dependency), node(module.Path))
+		}
+	}
+	return out.String()
+}
+
+// MarkdownCell returns the given value in a markdown table cell, on a single line.
+func MarkdownCell(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(strings.Join(strings.Fields(value), " "), "|", `\|`)
+}
+
+// MarkdownCode returns the given value as inline code in a markdown table cell, on a single line.
+func MarkdownCode(value string) string {
+	if value == "" {
+		return "-"
+	}
+	if strings.Contains(value, "`") {
+		return "`` " + MarkdownCell(value) + " ``"
+	}
+	return "`" + MarkdownCell(value) + "`"
+}
+
+func htmlAnchor(path string) string {
+	return "module-" + strings.NewReplacer("/", "-", ".", "-", " ", "-").Replace(path)
+}
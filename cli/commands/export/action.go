@@ -0,0 +1,191 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The platforms the stack can be exported to.
+const (
+	FormatSpacelift = "spacelift"
+	FormatEnv0      = "env0"
+)
+
+var formats = []string{FormatSpacelift, FormatEnv0}
+
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// ExportedStack is the stack exported to a platform: the modules, in the repo of the working dir.
+type ExportedStack struct {
+	// The repo of the modules and its current branch, as the origin remote of git has them, empty if unknown
+	Repository string
+	Branch     string
+	Modules    []*ExportedModule
+}
+
+// ExportedModule is a module of the exported stack.
+type ExportedModule struct {
+	// The unique ID of the module on the platform, derived from its path
+	ID string
+	// The path of the module relative to the root of the repo, the dir the platform runs terragrunt in
+	Path         string
+	Labels       []string
+	Dependencies []*ExportedModule
+}
+
+// Run exports the stack in the working dir to the given platform, without running terraform, and writes it to the
+// given file or to stdout.
+func Run(ctx context.Context, opts *options.TerragruntOptions, format string, out string) error {
+	format = strings.ToLower(format)
+	if !util.ListContainsElement(formats, format) {
+		return errors.WithStackTrace(InvalidFormat(format))
+	}
+
+	stackOpts := opts.Clone(opts.TerragruntConfigPath)
+	// Only the modules of the working dir are exported, the external dependencies are not added to the stack
+	stackOpts.IgnoreExternalDependencies = true
+
+	stack, err := configstack.FindStackInSubfolders(ctx, stackOpts, nil)
+	if err != nil {
+		return err
+	}
+
+	rootDir := opts.WorkingDir
+	if gitTopLevelDir, err := shell.GitTopLevelDir(ctx, opts, opts.WorkingDir); err == nil {
+		rootDir = gitTopLevelDir
+	} else {
+		opts.Logger.Debugf("The working dir %s is not in a git repo, exporting the paths of the modules relative to it: %v", opts.WorkingDir, err)
+	}
+
+	exported, err := newExportedStack(stack.Modules, rootDir)
+	if err != nil {
+		return err
+	}
+	exported.Repository, exported.Branch = gitRepository(ctx, opts, rootDir)
+
+	var content bytes.Buffer
+	if format == FormatSpacelift {
+		err = renderSpacelift(&content, exported)
+	} else {
+		err = renderEnv0(&content, exported)
+	}
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err := opts.Writer.Write(content.Bytes())
+		return errors.WithStackTrace(err)
+	}
+
+	outFile := util.JoinPath(opts.WorkingDir, out)
+	if err := os.MkdirAll(filepath.Dir(outFile), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(outFile, content.Bytes(), 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	opts.Logger.Infof("Exported %d modules to %s", len(exported.Modules), outFile)
+	return nil
+}
+
+// newExportedStack returns the given modules, which are not excluded, as an exported stack with their paths relative
+// to the given root dir of the repo. The dependencies which are not exported themselves are left out.
+func newExportedStack(modules []*configstack.TerraformModule, rootDir string) (*ExportedStack, error) {
+	byPath := map[string]*ExportedModule{}
+	ids := map[string]string{}
+	for _, module := range modules {
+		if module.FlagExcluded {
+			continue
+		}
+		relPath, err := util.GetPathRelativeTo(module.Path, rootDir)
+		if err != nil {
+			return nil, err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		id := moduleID(relPath)
+		if otherPath, ok := ids[id]; ok {
+			return nil, errors.WithStackTrace(DuplicateModuleID{ID: id, Paths: []string{otherPath, relPath}})
+		}
+		ids[id] = relPath
+		byPath[module.Path] = &ExportedModule{ID: id, Path: relPath, Labels: module.Config.Tags}
+	}
+
+	exported := &ExportedStack{}
+	for _, module := range modules {
+		exportedModule, ok := byPath[module.Path]
+		if !ok {
+			continue
+		}
+		for _, dependency := range module.Dependencies {
+			if exportedDependency, ok := byPath[dependency.Path]; ok {
+				exportedModule.Dependencies = append(exportedModule.Dependencies, exportedDependency)
+			}
+		}
+		sort.Slice(exportedModule.Dependencies, func(i, j int) bool {
+			return exportedModule.Dependencies[i].Path < exportedModule.Dependencies[j].Path
+		})
+		exported.Modules = append(exported.Modules, exportedModule)
+	}
+	sort.Slice(exported.Modules, func(i, j int) bool {
+		return exported.Modules[i].Path < exported.Modules[j].Path
+	})
+	return exported, nil
+}
+
+// moduleID returns the ID of the module at the given path relative to the root of the repo, valid as the name of a
+// terraform resource and as the key of a YAML map, e.g. live_prod_vpc for live/prod/vpc.
+func moduleID(relPath string) string {
+	id := strings.Trim(invalidIDChars.ReplaceAllString(relPath, "_"), "_")
+	if id == "" {
+		return "root"
+	}
+	if id[0] >= '0' && id[0] <= '9' {
+		return "_" + id
+	}
+	return id
+}
+
+// gitRepository returns the name of the repo of the given dir, as the URL of its origin remote has it, and its current
+// branch, or empty strings if git does not know them.
+func gitRepository(ctx context.Context, opts *options.TerragruntOptions, dir string) (string, string) {
+	gitOutput := func(args ...string) string {
+		gitOpts := opts.Clone(opts.TerragruntConfigPath)
+		gitOpts.Writer, gitOpts.ErrWriter = &bytes.Buffer{}, &bytes.Buffer{}
+		output, err := shell.RunShellCommandWithOutput(ctx, gitOpts, dir, true, false, "git", args...)
+		if err != nil {
+			opts.Logger.Debugf("Failed to run git %s in %s: %v", strings.Join(args, " "), dir, err)
+			return ""
+		}
+		return strings.TrimSpace(output.Stdout)
+	}
+
+	repository := ""
+	if remoteURL := gitOutput("config", "--get", "remote.origin.url"); remoteURL != "" {
+		// The SCP-like URLs, e.g. git@github.com:acme/infra.git, have the path after the colon
+		if parsedURL, err := url.Parse(remoteURL); err == nil && parsedURL.Path != "" {
+			remoteURL = parsedURL.Path
+		} else if _, remotePath, found := strings.Cut(remoteURL, ":"); found {
+			remoteURL = remotePath
+		}
+		repository = strings.TrimSuffix(path.Base(strings.TrimSuffix(remoteURL, "/")), ".git")
+	}
+
+	// Empty on a detached HEAD
+	branch := gitOutput("symbolic-ref", "--quiet", "--short", "HEAD")
+	return repository, branch
+}
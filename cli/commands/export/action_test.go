@@ -0,0 +1,163 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRepo(t *testing.T) (string, *options.TerragruntOptions) {
+	t.Helper()
+
+	repoDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	writeFile := func(path, contents string) {
+		path = filepath.Join(repoDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+	writeFile("live/vpc/terragrunt.hcl", "tags = [\"network\"]\n")
+	writeFile("live/app/terragrunt.hcl", "dependency \"vpc\" {\n  config_path = \"../vpc\"\n}\n\ndependencies {\n  paths = [\"../db\"]\n}\n")
+	writeFile("live/db/terragrunt.hcl", "dependency \"vpc\" {\n  config_path = \"../vpc\"\n}\n")
+	for _, module := range []string{"vpc", "app", "db"} {
+		writeFile(filepath.Join("live", module, "main.tf"), "")
+	}
+
+	for _, args := range [][]string{{"init", "--quiet", "--initial-branch=main"}, {"remote", "add", "origin", "git@github.com:acme/infra.git"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(repoDir, "live", "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = filepath.Join(repoDir, "live")
+	return repoDir, opts
+}
+
+func TestExportSpacelift(t *testing.T) {
+	t.Parallel()
+
+	_, opts := newTestRepo(t)
+	stdout := bytes.Buffer{}
+	opts.Writer = &stdout
+
+	require.NoError(t, Run(context.Background(), opts, "spacelift", ""))
+	assert.Equal(t, `# The Spacelift stacks of the terragrunt modules, generated by `+"`terragrunt export --format=spacelift`"+`.
+
+variable "repository" {
+  type        = string
+  description = "The repository of the stacks"
+  default     = "infra"
+}
+
+variable "branch" {
+  type        = string
+  description = "The branch the stacks track"
+  default     = "main"
+}
+
+resource "spacelift_stack" "live_app" {
+  name         = "live/app"
+  repository   = var.repository
+  branch       = var.branch
+  project_root = "live/app"
+
+  terragrunt {
+    use_run_all = false
+  }
+}
+
+resource "spacelift_stack" "live_db" {
+  name         = "live/db"
+  repository   = var.repository
+  branch       = var.branch
+  project_root = "live/db"
+
+  terragrunt {
+    use_run_all = false
+  }
+}
+
+resource "spacelift_stack" "live_vpc" {
+  name         = "live/vpc"
+  repository   = var.repository
+  branch       = var.branch
+  project_root = "live/vpc"
+  labels       = ["network"]
+
+  terragrunt {
+    use_run_all = false
+  }
+}
+
+resource "spacelift_stack_dependency" "live_app_on_live_db" {
+  stack_id            = spacelift_stack.live_app.id
+  depends_on_stack_id = spacelift_stack.live_db.id
+}
+
+resource "spacelift_stack_dependency" "live_app_on_live_vpc" {
+  stack_id            = spacelift_stack.live_app.id
+  depends_on_stack_id = spacelift_stack.live_vpc.id
+}
+
+resource "spacelift_stack_dependency" "live_db_on_live_vpc" {
+  stack_id            = spacelift_stack.live_db.id
+  depends_on_stack_id = spacelift_stack.live_vpc.id
+}
+`, stdout.String())
+}
+
+func TestExportEnv0(t *testing.T) {
+	t.Parallel()
+
+	_, opts := newTestRepo(t)
+
+	require.NoError(t, Run(context.Background(), opts, "env0", "env0.workflow.yaml"))
+	content, err := os.ReadFile(filepath.Join(opts.WorkingDir, "env0.workflow.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, `# The env0 workflow of the terragrunt modules, generated by `+"`terragrunt export --format=env0`"+`.
+# Each environment is deployed with the Terragrunt template of the same name, with the path of the module.
+environments:
+  live_app:
+    name: "live/app"
+    templateName: "live/app"
+    needs:
+      - live_db
+      - live_vpc
+  live_db:
+    name: "live/db"
+    templateName: "live/db"
+    needs:
+      - live_vpc
+  live_vpc:
+    name: "live/vpc"
+    templateName: "live/vpc"
+`, string(content))
+}
+
+func TestExportInvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	_, opts := newTestRepo(t)
+	err := Run(context.Background(), opts, "atlantis", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `Invalid value "atlantis" for --format, expected one of spacelift, env0`)
+}
+
+func TestModuleID(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "live_prod_vpc", moduleID("live/prod/vpc"))
+	assert.Equal(t, "live_us_east_1_app", moduleID("live/us-east-1/app"))
+	assert.Equal(t, "_1_bootstrap", moduleID("1-bootstrap"))
+	assert.Equal(t, "root", moduleID("."))
+}
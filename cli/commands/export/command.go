@@ -0,0 +1,34 @@
+package export
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "export"
+
+	FlagNameFormat = "format"
+	FlagNameOut    = "out"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	var format, out string
+	return &cli.Command{
+		Name:  CommandName,
+		Usage: "Export the modules of the stack, with their dependencies, as the stacks of Spacelift or the environments of env0.",
+		Flags: cli.Flags{
+			&cli.GenericFlag[string]{
+				Name:        FlagNameFormat,
+				Destination: &format,
+				Usage:       "The platform to export the stack to: spacelift, as the terraform config of the Spacelift provider, or env0, as an env0 workflow file.",
+			},
+			&cli.GenericFlag[string]{
+				Name:        FlagNameOut,
+				Destination: &out,
+				Usage:       "The file to write the export to, instead of stdout.",
+			},
+		},
+		Action: func(ctx *cli.Context) error { return Run(ctx, opts.OptionsFromContext(ctx), format, out) },
+	}
+}
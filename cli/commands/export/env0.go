@@ -0,0 +1,34 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// renderEnv0 writes the given stack as an env0 workflow file: an environment per module, deployed with the template
+// named after the path of the module, which needs the environments of the dependencies of the module.
+func renderEnv0(out io.Writer, stack *ExportedStack) error {
+	var content strings.Builder
+	content.WriteString("# The env0 workflow of the terragrunt modules, generated by `terragrunt export --format=env0`.\n")
+	content.WriteString("# Each environment is deployed with the Terragrunt template of the same name, with the path of the module.\n")
+	content.WriteString("environments:\n")
+
+	for _, module := range stack.Modules {
+		fmt.Fprintf(&content, "  %s:\n", module.ID)
+		fmt.Fprintf(&content, "    name: %s\n", strconv.Quote(module.Path))
+		fmt.Fprintf(&content, "    templateName: %s\n", strconv.Quote(module.Path))
+		if len(module.Dependencies) > 0 {
+			content.WriteString("    needs:\n")
+			for _, dependency := range module.Dependencies {
+				fmt.Fprintf(&content, "      - %s\n", dependency.ID)
+			}
+		}
+	}
+
+	_, err := io.WriteString(out, content.String())
+	return errors.WithStackTrace(err)
+}
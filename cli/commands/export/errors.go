@@ -0,0 +1,23 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Custom error types
+
+type InvalidFormat string
+
+func (format InvalidFormat) Error() string {
+	return fmt.Sprintf("Invalid value %q for --%s, expected one of %s", string(format), FlagNameFormat, strings.Join(formats, ", "))
+}
+
+type DuplicateModuleID struct {
+	ID    string
+	Paths []string
+}
+
+func (err DuplicateModuleID) Error() string {
+	return fmt.Sprintf("The modules %s have the same ID %s on the platform, rename one of them", strings.Join(err.Paths, " and "), err.ID)
+}
@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// renderSpacelift writes the given stack as the terraform config of the Spacelift provider: a stack per module, running
+// terragrunt in the dir of the module, and a stack dependency per dependency of a module. The repo and the branch of the
+// stacks are variables, defaulting to the repo and the branch of the working dir.
+func renderSpacelift(out io.Writer, stack *ExportedStack) error {
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+
+	body.AppendUnstructuredTokens(hclwrite.Tokens{{Type: hclsyntax.TokenComment, Bytes: []byte("# The Spacelift stacks of the terragrunt modules, generated by `terragrunt export --format=spacelift`.\n")}})
+	body.AppendNewline()
+
+	for _, variable := range []struct{ name, description, value string }{
+		{"repository", "The repository of the stacks", stack.Repository},
+		{"branch", "The branch the stacks track", stack.Branch},
+	} {
+		block := body.AppendNewBlock("variable", []string{variable.name}).Body()
+		block.SetAttributeTraversal("type", hcl.Traversal{hcl.TraverseRoot{Name: "string"}})
+		block.SetAttributeValue("description", cty.StringVal(variable.description))
+		if variable.value != "" {
+			block.SetAttributeValue("default", cty.StringVal(variable.value))
+		}
+		body.AppendNewline()
+	}
+
+	for _, module := range stack.Modules {
+		block := body.AppendNewBlock("resource", []string{"spacelift_stack", module.ID}).Body()
+		block.SetAttributeValue("name", cty.StringVal(module.Path))
+		block.SetAttributeTraversal("repository", hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: "repository"}})
+		block.SetAttributeTraversal("branch", hcl.Traversal{hcl.TraverseRoot{Name: "var"}, hcl.TraverseAttr{Name: "branch"}})
+		if module.Path != "." {
+			block.SetAttributeValue("project_root", cty.StringVal(module.Path))
+		}
+		if len(module.Labels) > 0 {
+			labels := make([]cty.Value, 0, len(module.Labels))
+			for _, label := range module.Labels {
+				labels = append(labels, cty.StringVal(label))
+			}
+			block.SetAttributeValue("labels", cty.ListVal(labels))
+		}
+		block.AppendNewline()
+		block.AppendNewBlock("terragrunt", nil).Body().SetAttributeValue("use_run_all", cty.False)
+		body.AppendNewline()
+	}
+
+	for _, module := range stack.Modules {
+		for _, dependency := range module.Dependencies {
+			block := body.AppendNewBlock("resource", []string{"spacelift_stack_dependency", module.ID + "_on_" + dependency.ID}).Body()
+			block.SetAttributeTraversal("stack_id", hcl.Traversal{hcl.TraverseRoot{Name: "spacelift_stack"}, hcl.TraverseAttr{Name: module.ID}, hcl.TraverseAttr{Name: "id"}})
+			block.SetAttributeTraversal("depends_on_stack_id", hcl.Traversal{hcl.TraverseRoot{Name: "spacelift_stack"}, hcl.TraverseAttr{Name: dependency.ID}, hcl.TraverseAttr{Name: "id"}})
+			body.AppendNewline()
+		}
+	}
+
+	_, err := out.Write(append(bytes.TrimRight(hclwrite.Format(file.Bytes()), "\n"), '\n'))
+	return errors.WithStackTrace(err)
+}
@@ -42,10 +42,81 @@ const (
 	TerragruntFailOnStateBucketCreationFlagName      = "terragrunt-fail-on-state-bucket-creation"
 	TerragruntDisableBucketUpdateFlagName            = "terragrunt-disable-bucket-update"
 	TerragruntDisableCommandValidationFlagName       = "terragrunt-disable-command-validation"
+	TerragruntStackLockFlagName                      = "terragrunt-stack-lock"
+	TerragruntStealStackLockFlagName                 = "terragrunt-steal-stack-lock"
+	TerragruntModuleDurationsFileFlagName            = "terragrunt-module-durations-file"
+	TerragruntContinueOnErrorFlagName                = "terragrunt-continue-on-error"
+	TerragruntAssumeAppliedFlagName                  = "terragrunt-assume-applied"
+	TerragruntSkipUnchangedFlagName                  = "terragrunt-skip-unchanged"
+	TerragruntPlanGatedApplyFlagName                 = "terragrunt-plan-gated-apply"
+	TerragruntConfirmEachFlagName                    = "terragrunt-confirm-each"
+	TerragruntBufferOutputFlagName                   = "terragrunt-buffer-output"
+	TerragruntNoPrefixFlagName                       = "terragrunt-no-prefix"
+	TerragruntPrefixThemeFlagName                    = "terragrunt-prefix-theme"
+	TerragruntLogFormatFlagName                      = "terragrunt-log-format"
+	TerragruntLogFieldsFlagName                      = "terragrunt-log-fields"
+	TerragruntNoProgressFlagName                     = "terragrunt-no-progress"
+	TerragruntGitHubAnnotationsFlagName              = "terragrunt-github-annotations"
+	TerragruntExitCodeModeFlagName                   = "terragrunt-exit-code-mode"
+	TerragruntExitCodeFlagName                       = "terragrunt-exit-code"
+	TerragruntExecPolicyConfigFlagName               = "terragrunt-exec-policy-config"
+	TerragruntExecAllowFlagName                      = "terragrunt-exec-allow"
+	TerragruntExecDenyFlagName                       = "terragrunt-exec-deny"
+	TerragruntExecNoNetworkFlagName                  = "terragrunt-exec-no-network"
+	TerragruntRestrictedFlagName                     = "terragrunt-restricted"
+	TerragruntReadOnlyFlagName                       = "terragrunt-read-only"
+	TerragruntAllowDestroyFlagName                   = "terragrunt-allow-destroy"
+	TerragruntRunQueueFileFlagName                   = "terragrunt-run-queue-file"
+	TerragruntShardFlagName                          = "terragrunt-shard"
+	TerragruntShardLedgerFlagName                    = "terragrunt-shard-ledger"
+	TerragruntOfflineFlagName                        = "terragrunt-offline"
+	TerragruntOfflineAllowHostFlagName               = "terragrunt-offline-allow-host"
+	TerragruntDownloadMaxAttemptsFlagName            = "terragrunt-download-max-attempts"
+	TerragruntDownloadRetryIntervalSecFlagName       = "terragrunt-download-retry-interval-sec"
+	TerragruntDownloadMirrorFlagName                 = "terragrunt-download-mirror"
+	TerragruntSnapshotDirFlagName                    = "terragrunt-snapshot-dir"
+	TerragruntSnapshotRunIDFlagName                  = "terragrunt-snapshot-run-id"
+	TerragruntQuotaPreflightFlagName                 = "terragrunt-quota-preflight"
+	TerragruntSchemaCacheFlagName                    = "terragrunt-schema-cache"
+	TerragruntSensitiveOutputsKeyFlagName            = "terragrunt-sensitive-outputs-key"
+	TerragruntSensitiveOutputsKMSKeyIDFlagName       = "terragrunt-sensitive-outputs-kms-key-id"
+	TerragruntAutoTerraformBinaryFlagName            = "terragrunt-auto-terraform-binary"
+	TerragruntTerraformBinaryDirFlagName             = "terragrunt-terraform-binary-dir"
+	TerragruntRunIDFlagName                          = "terragrunt-run-id"
+	TerragruntExtraArgsFlagName                      = "terragrunt-extra-args"
+	TerragruntTargetFlagName                         = "terragrunt-target"
+	TerragruntReplaceFlagName                        = "terragrunt-replace"
+	TerragruntSkipUntargetedFlagName                 = "terragrunt-skip-untargeted"
+	TerragruntModuleGroupsDetailsFlagName            = "terragrunt-module-groups-details"
+	TerragruntOwnerFlagName                          = "terragrunt-owner"
+	TerragruntHistoryFileFlagName                    = "terragrunt-history-file"
+	TerragruntFlakyRetryMaxAttemptsFlagName          = "terragrunt-flaky-retry-max-attempts"
+	TerragruntResumeFlagName                         = "terragrunt-resume"
+	TerragruntReportFileFlagName                     = "terragrunt-report-file"
+	TerragruntGroupParallelismFlagName               = "terragrunt-group-parallelism"
+	TerragruntSuppressWarningFlagName                = "terragrunt-suppress-warning"
+	TerragruntWarningsAsErrorsFlagName               = "terragrunt-warnings-as-errors"
+	TerragruntChangedSinceFlagName                   = "terragrunt-changed-since"
+	TerragruntAllowDestroyWithConsumersFlagName      = "terragrunt-allow-destroy-with-consumers"
+	TerragruntIncludeTagsFlagName                    = "terragrunt-include-tags"
+	TerragruntExcludeTagsFlagName                    = "terragrunt-exclude-tags"
+	TerragruntIncludeDependencyClosureFlagName       = "terragrunt-include-dependency-closure"
+	TerragruntReadOnlyExternalDependenciesFlagName   = "terragrunt-read-only-external-dependencies"
+	TerragruntShowSensitiveFlagName                  = "terragrunt-show-sensitive"
+	TerragruntPreventDestroyPathFlagName             = "terragrunt-prevent-destroy-path"
+	TerragruntRemoteCacheFlagName                    = "terragrunt-remote-cache"
+	TerragruntRemoteCacheTokenFlagName               = "terragrunt-remote-cache-token"
+	TerragruntRemoteCacheOutputsTTLSecFlagName       = "terragrunt-remote-cache-outputs-ttl-sec"
+	TerragruntRetryFailedFlagName                    = "terragrunt-retry-failed"
 
 	TerragruntOutDirFlagEnvVarName = "TERRAGRUNT_OUT_DIR"
 	TerragruntOutDirFlagName       = "terragrunt-out-dir"
 
+	TerragruntSavePlansFlagEnvVarName = "TERRAGRUNT_SAVE_PLANS"
+	TerragruntSavePlansFlagName       = "terragrunt-save-plans"
+	TerragruntUsePlansFlagEnvVarName  = "TERRAGRUNT_USE_PLANS"
+	TerragruntUsePlansFlagName        = "terragrunt-use-plans"
+
 	// Terragrunt Provider Cache flags/envs
 	TerragruntProviderCacheFlagName                         = "terragrunt-provider-cache"
 	TerragruntProviderCacheEnvVarName                       = "TERRAGRUNT_PROVIDER_CACHE"
@@ -219,6 +290,18 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			EnvVar:      "TERRAGRUNT_JSON_LOG",
 			Usage:       "If specified, Terragrunt will output its logs in JSON format.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntLogFormatFlagName,
+			Destination: &opts.LogFormat,
+			EnvVar:      "TERRAGRUNT_LOG_FORMAT",
+			Usage:       "Sets the format of the Terragrunt logs. Supported formats: human, logfmt, json.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntLogFieldsFlagName,
+			Destination: &opts.LogFields,
+			EnvVar:      "TERRAGRUNT_LOG_FIELDS",
+			Usage:       "Comma separated list of the fields to include in the logs. Supported fields: module, group, command, duration.",
+		},
 		&cli.BoolFlag{
 			Name:        TerragruntTfLogJsonFlagName,
 			Destination: &opts.TerraformLogsToJson,
@@ -271,6 +354,391 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			EnvVar:      "TERRAGRUNT_DISABLE_COMMAND_VALIDATION",
 			Usage:       "When this flag is set, Terragrunt will not validate the terraform command.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntStackLockFlagName,
+			Destination: &opts.StackLock,
+			EnvVar:      "TERRAGRUNT_STACK_LOCK",
+			Usage:       "Location of a distributed lock held for the whole stack during 'run-all': dynamodb://<table>[/<lock-id>][?region=<region>] or gcs://<bucket>[/<lock-id>].",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntStealStackLockFlagName,
+			Destination: &opts.StealStackLock,
+			EnvVar:      "TERRAGRUNT_STEAL_STACK_LOCK",
+			Usage:       "Take over the stack lock set with '--terragrunt-stack-lock' even if it is held by another run.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntModuleDurationsFileFlagName,
+			Destination: &opts.ModuleDurationsFile,
+			EnvVar:      "TERRAGRUNT_MODULE_DURATIONS_FILE",
+			Usage:       "Path to a file where 'run-all' records how long each module took, so that among modules ready to run the historically slow ones are started first.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntContinueOnErrorFlagName,
+			Destination: &opts.ContinueOnError,
+			EnvVar:      "TERRAGRUNT_CONTINUE_ON_ERROR",
+			Usage:       "When a module fails, 'run-all' only skips its dependents, reports failed, skipped and succeeded modules separately and exits with a distinct code for each outcome.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntAssumeAppliedFlagName,
+			Destination: &opts.AssumeAppliedDirs,
+			EnvVar:      "TERRAGRUNT_ASSUME_APPLIED",
+			Usage:       "Comma separated unix-style globs of modules that 'run-all' assumes are already applied: their outputs are fetched, but they are not run.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntSkipUnchangedFlagName,
+			Destination: &opts.SkipUnchanged,
+			EnvVar:      "TERRAGRUNT_SKIP_UNCHANGED",
+			Usage:       "Record a fingerprint of the config, source, inputs and dependency outputs on successful apply, and skip applying modules whose fingerprint did not change since.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntPlanGatedApplyFlagName,
+			Destination: &opts.PlanGatedApply,
+			EnvVar:      "TERRAGRUNT_PLAN_GATED_APPLY",
+			Usage:       "Run 'plan -detailed-exitcode' before each apply and only apply the saved plan of modules that have changes.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntConfirmEachFlagName,
+			Destination: &opts.ConfirmEach,
+			EnvVar:      "TERRAGRUNT_CONFIRM_EACH",
+			Usage:       "In interactive mode, show the plan summary of each module and ask to approve, skip or abort before applying it.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntBufferOutputFlagName,
+			Destination: &opts.BufferOutput,
+			EnvVar:      "TERRAGRUNT_BUFFER_OUTPUT",
+			Usage:       "Buffer the output of each module in 'run-all' and print it as a contiguous block once the module finishes, instead of interleaving the output of concurrent modules.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntNoPrefixFlagName,
+			Destination: &opts.NoPrefix,
+			EnvVar:      "TERRAGRUNT_NO_PREFIX",
+			Usage:       "Don't prefix the output of each module in 'run-all' with the module path.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntPrefixThemeFlagName,
+			Destination: &opts.PrefixTheme,
+			EnvVar:      "TERRAGRUNT_PREFIX_THEME",
+			Usage:       "The color theme of the module prefixes in the output of 'run-all': default, bright or none.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntNoProgressFlagName,
+			Destination: &opts.NoProgress,
+			EnvVar:      "TERRAGRUNT_NO_PROGRESS",
+			Usage:       "Don't periodically log the progress of 'run-all', with the number of modules complete and the estimated time remaining.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntGitHubAnnotationsFlagName,
+			Destination: &opts.GitHubAnnotations,
+			EnvVar:      "TERRAGRUNT_GITHUB_ANNOTATIONS",
+			Usage:       "Write GitHub Actions annotations for module failures, configuration errors and drifted modules.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntExitCodeModeFlagName,
+			Destination: &opts.ExitCodeMode,
+			EnvVar:      "TERRAGRUNT_EXIT_CODE_MODE",
+			Usage:       "How the exit code is chosen: 'terraform' to exit with the exit code of Terraform, 'detailed' to exit with a distinct exit code per outcome.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntExitCodeFlagName,
+			Destination: &opts.ExitCodes,
+			EnvVar:      "TERRAGRUNT_EXIT_CODES",
+			Usage:       "Overrides the exit code of an outcome in the 'detailed' exit code mode, as <outcome>=<exit code>.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntExecPolicyConfigFlagName,
+			Destination: &opts.ExecPolicyConfigPath,
+			EnvVar:      "TERRAGRUNT_EXEC_POLICY_CONFIG",
+			Usage:       "The path to a trusted Terragrunt config whose 'exec_policy' block restricts the commands hooks and run_cmd may execute.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntExecAllowFlagName,
+			Destination: &opts.ExecAllow,
+			EnvVar:      "TERRAGRUNT_EXEC_ALLOW",
+			Usage:       "Only allow hooks and run_cmd to execute the given commands. Supports glob patterns.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntExecDenyFlagName,
+			Destination: &opts.ExecDeny,
+			EnvVar:      "TERRAGRUNT_EXEC_DENY",
+			Usage:       "Don't allow hooks and run_cmd to execute the given commands. Supports glob patterns.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntExecNoNetworkFlagName,
+			Destination: &opts.ExecNoNetwork,
+			EnvVar:      "TERRAGRUNT_EXEC_NO_NETWORK",
+			Usage:       "Execute the commands of hooks and run_cmd without network access. Only supported on Linux.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntRestrictedFlagName,
+			Destination: &opts.Restricted,
+			EnvVar:      "TERRAGRUNT_RESTRICTED",
+			Usage:       "Disable the features that execute arbitrary local code or make changes outside of the module dir, to safely run untrusted configs.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntReadOnlyFlagName,
+			Destination: &opts.ReadOnly,
+			EnvVar:      "TERRAGRUNT_READ_ONLY",
+			Usage:       "Refuse to run the terraform commands that change the infrastructure or the state, such as apply, destroy, import or state rm.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntAllowDestroyFlagName,
+			Destination: &opts.AllowDestroyDirs,
+			EnvVar:      "TERRAGRUNT_ALLOW_DESTROY",
+			Usage:       "Allow destroying the modules in the given dir, even if they are protected by prevent_destroy. Supports glob patterns.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntRunQueueFileFlagName,
+			Destination: &opts.RunQueueFile,
+			EnvVar:      "TERRAGRUNT_RUN_QUEUE_FILE",
+			Usage:       "Path to a file where 'run-all' saves the run graph and the status of each module, so that the run can be resumed, or continued with another command, in a later CI job.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntShardFlagName,
+			Destination: &opts.Shard,
+			EnvVar:      "TERRAGRUNT_SHARD",
+			Usage:       "Only run the modules of the given shard N/M in 'run-all', to split the run across M workers.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntShardLedgerFlagName,
+			Destination: &opts.ShardLedger,
+			EnvVar:      "TERRAGRUNT_SHARD_LEDGER",
+			Usage:       "Location of the ledger where the workers of a sharded 'run-all' record the finished modules: dynamodb://<table>/<ledger-id>, gcs://<bucket>/<ledger-id> or a shared dir.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntOfflineFlagName,
+			Destination: &opts.Offline,
+			EnvVar:      "TERRAGRUNT_OFFLINE",
+			Usage:       "Forbid all network fetches, relying on vendored sources, provider mirrors and caches.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntOfflineAllowHostFlagName,
+			Destination: &opts.OfflineAllowedHosts,
+			EnvVar:      "TERRAGRUNT_OFFLINE_ALLOW_HOST",
+			Usage:       "Host, or glob pattern of hosts, that may still be fetched from in offline mode, e.g. a provider mirror.",
+		},
+		&cli.GenericFlag[int]{
+			Name:        TerragruntDownloadMaxAttemptsFlagName,
+			Destination: &opts.DownloadMaxAttempts,
+			EnvVar:      "TERRAGRUNT_DOWNLOAD_MAX_ATTEMPTS",
+			Usage:       "The number of attempts of every source and provider download, before falling back to the mirrors.",
+		},
+		&cli.GenericFlag[int]{
+			Name:        TerragruntDownloadRetryIntervalSecFlagName,
+			Destination: &opts.DownloadRetryIntervalSec,
+			EnvVar:      "TERRAGRUNT_DOWNLOAD_RETRY_INTERVAL_SEC",
+			Usage:       "The seconds to sleep after the first failed attempt of a download, doubled after every further failed attempt.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntDownloadMirrorFlagName,
+			Destination: &opts.DownloadMirrors,
+			EnvVar:      "TERRAGRUNT_DOWNLOAD_MIRROR",
+			Usage:       "A mirror the downloads fall back to, in the <original URL prefix>=<mirror URL prefix> format, e.g. https://github.com/=https://git.example.com/github/.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntSnapshotDirFlagName,
+			Destination: &opts.SnapshotDir,
+			EnvVar:      "TERRAGRUNT_SNAPSHOT_DIR",
+			Usage:       "The dir to write the manifest of the sources, inputs, providers and outputs of the modules to after a successful run-all apply.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntSnapshotRunIDFlagName,
+			Destination: &opts.SnapshotRunID,
+			EnvVar:      "TERRAGRUNT_SNAPSHOT_RUN_ID",
+			Usage:       "The ID of the run the snapshot manifest is stored under. By default, generated from the time of the run.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntQuotaPreflightFlagName,
+			Destination: &opts.QuotaPreflight,
+			EnvVar:      "TERRAGRUNT_QUOTA_PREFLIGHT",
+			Usage:       "Plan the modules before run-all apply and warn about the service quotas the resources they create would exceed.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntSchemaCacheFlagName,
+			Destination: &opts.SchemaCache,
+			EnvVar:      "TERRAGRUNT_SCHEMA_CACHE",
+			Usage:       "Share the downloaded providers and their schemas across the modules of run-all validate, and init the modules without their backend.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntSensitiveOutputsKeyFlagName,
+			Destination: &opts.SensitiveOutputsKey,
+			EnvVar:      "TERRAGRUNT_SENSITIVE_OUTPUTS_KEY",
+			Usage:       "The base64 encoded 256 bit key to encrypt the values of sensitive dependency outputs written to disk, e.g. in the debug file.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntSensitiveOutputsKMSKeyIDFlagName,
+			Destination: &opts.SensitiveOutputsKMSKeyID,
+			EnvVar:      "TERRAGRUNT_SENSITIVE_OUTPUTS_KMS_KEY_ID",
+			Usage:       "The AWS KMS key to encrypt the values of sensitive dependency outputs written to disk, e.g. in the debug file.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntAutoTerraformBinaryFlagName,
+			Destination: &opts.AutoTerraformBinary,
+			EnvVar:      "TERRAGRUNT_AUTO_TERRAFORM_BINARY",
+			Usage:       "Select for every module the newest terraform or tofu binary in the PATH and the binary dirs matching the required_version of the module and terraform_version_constraint.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntTerraformBinaryDirFlagName,
+			Destination: &opts.TerraformBinaryDirs,
+			EnvVar:      "TERRAGRUNT_TERRAFORM_BINARY_DIR",
+			Usage:       "A dir, or a glob pattern of dirs such as ~/.tfenv/versions/*/bin, to search for the terraform and tofu binaries with --terragrunt-auto-terraform-binary. May be specified multiple times.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntRunIDFlagName,
+			Destination: &opts.RunID,
+			EnvVar:      options.RunIDEnvName,
+			Usage:       "The ID of the run, returned by get_run_id(), set in the TERRAGRUNT_RUN_ID env var of terraform and the hooks and in the run_id field of the logs, e.g. the ID of the CI build. Generated for every run by default.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntExtraArgsFlagName,
+			Destination: &opts.ExtraArgs,
+			EnvVar:      "TERRAGRUNT_EXTRA_ARGS",
+			EnvVarSep:   ";",
+			Usage:       "An argument to pass to the given terraform commands, in the form <commands>:<argument>, e.g. plan,apply:-lock-timeout=5m. Added to the extra_arguments of the config of every module for this run. May be specified multiple times.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntTargetFlagName,
+			Destination: &opts.Targets,
+			EnvVar:      "TERRAGRUNT_TARGET",
+			Usage:       "A resource to target in run-all, in the form <module path>:<resource address>, e.g. vpc:aws_subnet.private. The -target flag is only passed to the module owning the resource. May be specified multiple times.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntReplaceFlagName,
+			Destination: &opts.Replaces,
+			EnvVar:      "TERRAGRUNT_REPLACE",
+			Usage:       "A resource to replace in run-all, in the form <module path>:<resource address>. The -replace flag is only passed to the module owning the resource. May be specified multiple times.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntSkipUntargetedFlagName,
+			Destination: &opts.SkipUntargeted,
+			EnvVar:      "TERRAGRUNT_SKIP_UNTARGETED",
+			Usage:       "Skip the modules no --terragrunt-target or --terragrunt-replace is in, instead of running them normally.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntModuleGroupsDetailsFlagName,
+			Destination: &opts.ModuleGroupsDetails,
+			EnvVar:      "TERRAGRUNT_MODULE_GROUPS_DETAILS",
+			Usage:       "Output the direct dependencies, the exclusion and the estimated duration of each module in output-module-groups, instead of only its path.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntOwnerFlagName,
+			Destination: &opts.Owners,
+			EnvVar:      "TERRAGRUNT_OWNER",
+			Usage:       "Only run the modules with the given owner, as set by the owner attribute of their config, in run-all. May be specified multiple times.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntHistoryFileFlagName,
+			Destination: &opts.HistoryFile,
+			EnvVar:      "TERRAGRUNT_HISTORY_FILE",
+			Usage:       "The file run-all records the duration, the outcome and the retries of each module to, read by the history command.",
+		},
+		&cli.GenericFlag[int]{
+			Name:        TerragruntFlakyRetryMaxAttemptsFlagName,
+			Destination: &opts.FlakyRetryMaxAttempts,
+			EnvVar:      "TERRAGRUNT_FLAKY_RETRY_MAX_ATTEMPTS",
+			Usage:       "The max attempts of the terraform commands of the modules found flaky in the run history of --terragrunt-history-file, in run-all.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntResumeFlagName,
+			Destination: &opts.Resume,
+			EnvVar:      "TERRAGRUNT_RESUME",
+			Usage:       "Record the status of each module in a checkpoint file in the working dir, and skip the modules which already succeeded in the last failed run-all.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntReportFileFlagName,
+			Destination: &opts.ReportFile,
+			EnvVar:      "TERRAGRUNT_REPORT_FILE",
+			Usage:       "The file run-all writes the result of each module to in JSON, with its run group, start and end times, attempts, exit code and error.",
+		},
+		&cli.GenericFlag[int]{
+			Name:        TerragruntGroupParallelismFlagName,
+			Destination: &opts.GroupParallelism,
+			EnvVar:      "TERRAGRUNT_GROUP_PARALLELISM",
+			Usage:       "*-all commands parallelism set to at most N modules of each run group, in addition to --terragrunt-parallelism",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntSuppressWarningFlagName,
+			Destination: &opts.SuppressWarnings,
+			EnvVar:      "TERRAGRUNT_SUPPRESS_WARNINGS",
+			Usage:       "The code of a warning not to log, e.g. TG2001. May be specified multiple times.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntWarningsAsErrorsFlagName,
+			Destination: &opts.WarningsAsErrors,
+			EnvVar:      "TERRAGRUNT_WARNINGS_AS_ERRORS",
+			Usage:       "Fail on the warnings which are not suppressed, instead of logging them.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntChangedSinceFlagName,
+			Destination: &opts.ChangedSince,
+			EnvVar:      "TERRAGRUNT_CHANGED_SINCE",
+			Usage:       "*-all commands run only the modules changed since the given git ref, e.g. origin/main, and the modules depending on them.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntAllowDestroyWithConsumersFlagName,
+			Destination: &opts.AllowDestroyWithConsumers,
+			EnvVar:      "TERRAGRUNT_ALLOW_DESTROY_WITH_CONSUMERS",
+			Usage:       "Let run-all destroy destroy modules whose outputs are still consumed by modules not destroyed, without confirmation.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntIncludeTagsFlagName,
+			Destination: &opts.IncludeTags,
+			EnvVar:      "TERRAGRUNT_INCLUDE_TAGS",
+			Usage:       "Run only the modules with one of the given tags, as set by the tags attribute of their config. May be specified multiple times.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntExcludeTagsFlagName,
+			Destination: &opts.ExcludeTags,
+			EnvVar:      "TERRAGRUNT_EXCLUDE_TAGS",
+			Usage:       "Do not run the modules with one of the given tags, as set by the tags attribute of their config. May be specified multiple times.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntIncludeDependencyClosureFlagName,
+			Destination: &opts.IncludeDependencyClosure,
+			EnvVar:      "TERRAGRUNT_INCLUDE_DEPENDENCY_CLOSURE",
+			Usage:       "Add the dependencies of the modules of --terragrunt-include-dir, direct or not, to the run to read their outputs, without running them.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntReadOnlyExternalDependenciesFlagName,
+			Destination: &opts.ReadOnlyExternalDependencies,
+			EnvVar:      "TERRAGRUNT_READ_ONLY_EXTERNAL_DEPENDENCIES",
+			Usage:       "*-all commands will read the outputs of the dependencies outside of the working dir and of --terragrunt-include-dir, without running against them.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntShowSensitiveFlagName,
+			Destination: &opts.ShowSensitive,
+			EnvVar:      "TERRAGRUNT_SHOW_SENSITIVE",
+			Usage:       "Show the values of the sensitive outputs of the dependencies in the debug logs and render-json, instead of masking them.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        TerragruntPreventDestroyPathFlagName,
+			Destination: &opts.PreventDestroyPaths,
+			EnvVar:      "TERRAGRUNT_PREVENT_DESTROY_PATH",
+			Usage:       "Unix-style glob of directories of the modules run-all destroy fails instead of destroying.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntRemoteCacheFlagName,
+			Destination: &opts.RemoteCacheURL,
+			EnvVar:      "TERRAGRUNT_REMOTE_CACHE",
+			Usage:       "The URL of the remote cache of the sources, providers and dependency outputs shared between machines: s3://bucket/prefix, gs://bucket/prefix or http(s)://host/prefix.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        TerragruntRemoteCacheTokenFlagName,
+			Destination: &opts.RemoteCacheToken,
+			EnvVar:      "TERRAGRUNT_REMOTE_CACHE_TOKEN",
+			Usage:       "The token sent as a bearer token to the HTTP server of the remote cache.",
+		},
+		&cli.GenericFlag[int]{
+			Name:        TerragruntRemoteCacheOutputsTTLSecFlagName,
+			Destination: &opts.RemoteCacheOutputsTTLSec,
+			EnvVar:      "TERRAGRUNT_REMOTE_CACHE_OUTPUTS_TTL_SEC",
+			Usage:       "The seconds the outputs of the dependencies stored in the remote cache are used for. The outputs are not stored in the remote cache if not set.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntRetryFailedFlagName,
+			Destination: &opts.RetryFailed,
+			EnvVar:      "TERRAGRUNT_RETRY_FAILED",
+			Usage:       "*-all commands only run the modules which failed in the previous run recorded in --terragrunt-report-file, and the modules depending on them which did not run.",
+		},
 		// Terragrunt Provider Cache flags
 		&cli.BoolFlag{
 			Name:        TerragruntProviderCacheFlagName,
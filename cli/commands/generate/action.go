@@ -0,0 +1,88 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// RunStatus prints the status of the generated files of the module, in the working dir terraform runs in once its
+// source is downloaded, and fails if any is modified or stale.
+func RunStatus(ctx context.Context, opts *options.TerragruntOptions) error {
+	target := terraform.NewTarget(terraform.TargetPointDownloadSource, func(ctx context.Context, opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+		manifest, err := codegen.ReadGeneratedFilesManifest(opts.WorkingDir)
+		if err != nil {
+			return err
+		}
+		statuses, err := manifest.Status(opts.WorkingDir, cfg.GenerateConfigs)
+		if err != nil {
+			return err
+		}
+		if err := writeStatus(opts.Writer, statuses); err != nil {
+			return err
+		}
+		return checkStatus(statuses)
+	})
+	return terraform.RunWithTarget(ctx, opts, target)
+}
+
+// RunClean removes the stale generated files of the module.
+func RunClean(ctx context.Context, opts *options.TerragruntOptions) error {
+	target := terraform.NewTarget(terraform.TargetPointDownloadSource, func(ctx context.Context, opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+		manifest, err := codegen.ReadGeneratedFilesManifest(opts.WorkingDir)
+		if err != nil {
+			return err
+		}
+		removed, err := manifest.Clean(opts, opts.WorkingDir, cfg.GenerateConfigs)
+		if err != nil {
+			return err
+		}
+		for _, path := range removed {
+			opts.Logger.Infof("Removed the stale generated file %s", path)
+		}
+		if len(removed) == 0 {
+			opts.Logger.Infof("No stale generated file to remove in %s", opts.WorkingDir)
+		}
+		return nil
+	})
+	return terraform.RunWithTarget(ctx, opts, target)
+}
+
+func writeStatus(writer io.Writer, statuses []codegen.GeneratedFileStatus) error {
+	if len(statuses) == 0 {
+		_, err := fmt.Fprintln(writer, "The module has no generated files.")
+		return errors.WithStackTrace(err)
+	}
+
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "FILE\tBLOCK\tSTATUS")
+	for _, status := range statuses {
+		fmt.Fprintf(table, "%s\t%s\t%s\n", status.Path, status.Block, status.Status)
+	}
+	return errors.WithStackTrace(table.Flush())
+}
+
+// checkStatus returns an error if any of the generated files was modified by hand or is stale.
+func checkStatus(statuses []codegen.GeneratedFileStatus) error {
+	var modified, stale []string
+	for _, status := range statuses {
+		switch status.Status {
+		case codegen.GeneratedFileStatusModified:
+			modified = append(modified, status.Path)
+		case codegen.GeneratedFileStatusStale:
+			stale = append(stale, status.Path)
+		}
+	}
+	if len(modified) > 0 || len(stale) > 0 {
+		return errors.WithStackTrace(GeneratedFilesOutOfDate{Modified: modified, Stale: stale})
+	}
+	return nil
+}
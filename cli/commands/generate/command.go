@@ -0,0 +1,42 @@
+package generate
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName      = "generate"
+	SubCommandStatus = "status"
+	SubCommandClean  = "clean"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:                   CommandName,
+		Usage:                  "Show or clean the files the generate blocks of the module generated.",
+		DisallowUndefinedFlags: true,
+		Subcommands:            subCommands(opts),
+		Action: func(ctx *cli.Context) error {
+			return errors.WithStackTrace(MissingGenerateSubCommand{})
+		},
+	}
+}
+
+func subCommands(opts *options.TerragruntOptions) cli.Commands {
+	return cli.Commands{
+		&cli.Command{
+			Name:        SubCommandStatus,
+			Usage:       "Show the status of the files the generate blocks of the module generated.",
+			Description: "The command compares the manifest of the generated files of the module with its generate blocks and the files of its working dir, and fails if a generated file was modified by hand or is stale, i.e. its generate block was removed or renamed.",
+			Action:      func(ctx *cli.Context) error { return RunStatus(ctx, opts.OptionsFromContext(ctx)) },
+		},
+		&cli.Command{
+			Name:        SubCommandClean,
+			Usage:       "Remove the stale files of the generate blocks of the module which were removed or renamed.",
+			Description: "The command removes the files of the manifest of the generated files of the module which none of its generate blocks generates anymore, except the files modified by hand since they were generated.",
+			Action:      func(ctx *cli.Context) error { return RunClean(ctx, opts.OptionsFromContext(ctx)) },
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Custom error types
+
+type MissingGenerateSubCommand struct{}
+
+func (err MissingGenerateSubCommand) Error() string {
+	return fmt.Sprintf("Missing what to do with the generated files, e.g. terragrunt %s %s.", CommandName, SubCommandStatus)
+}
+
+type GeneratedFilesOutOfDate struct {
+	Modified []string
+	Stale    []string
+}
+
+func (err GeneratedFilesOutOfDate) Error() string {
+	var problems []string
+	if len(err.Modified) > 0 {
+		problems = append(problems, fmt.Sprintf("modified by hand: %s", strings.Join(err.Modified, ", ")))
+	}
+	if len(err.Stale) > 0 {
+		problems = append(problems, fmt.Sprintf("stale, run `terragrunt %s %s` to remove them: %s", CommandName, SubCommandClean, strings.Join(err.Stale, ", ")))
+	}
+	return fmt.Sprintf("The generated files of the module are out of date, %s", strings.Join(problems, "; "))
+}
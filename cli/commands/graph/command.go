@@ -8,6 +8,7 @@ import (
 	awsproviderpatch "github.com/gruntwork-io/terragrunt/cli/commands/aws-provider-patch"
 	graphdependencies "github.com/gruntwork-io/terragrunt/cli/commands/graph-dependencies"
 	"github.com/gruntwork-io/terragrunt/cli/commands/hclfmt"
+	moduledocs "github.com/gruntwork-io/terragrunt/cli/commands/module-docs"
 	renderjson "github.com/gruntwork-io/terragrunt/cli/commands/render-json"
 	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
 	terragruntinfo "github.com/gruntwork-io/terragrunt/cli/commands/terragrunt-info"
@@ -17,16 +18,25 @@ import (
 )
 
 const (
-	CommandName = "graph"
+	CommandName    = "graph"
+	SubCommandDiff = "diff"
+
+	TerragruntGraphRootFlagName     = "terragrunt-graph-root"
+	TerragruntGraphDiffJSONFlagName = "terragrunt-graph-diff-json"
 )
 
 func NewFlags(opts *options.TerragruntOptions) cli.Flags {
 	globalFlags := commands.NewGlobalFlags(opts)
 	globalFlags.Add(
 		&cli.GenericFlag[string]{
-			Name:        "terragrunt-graph-root",
+			Name:        TerragruntGraphRootFlagName,
 			Destination: &opts.GraphRoot,
 			Usage:       "Root directory from where to build graph dependencies.",
+		},
+		&cli.BoolFlag{
+			Name:        TerragruntGraphDiffJSONFlagName,
+			Destination: &opts.GraphDiffJSON,
+			Usage:       "Print the diff of the graph diff command in JSON.",
 		})
 	return globalFlags
 }
@@ -44,6 +54,11 @@ func NewCommand(opts *options.TerragruntOptions) *cli.Command {
 
 func action(opts *options.TerragruntOptions) cli.ActionFunc {
 	return func(cliCtx *cli.Context) error {
+		// `graph diff` compares the structure of the stack between git refs instead of running a command on the graph
+		if cliCtx.Args().CommandName() == SubCommandDiff {
+			return cliCtx.Command.Subcommand(SubCommandDiff).Action(cliCtx)
+		}
+
 		opts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
 			if cmd := cliCtx.Command.Subcommand(opts.TerraformCommand); cmd != nil {
 				cliCtx := cliCtx.WithValue(options.ContextKey, opts)
@@ -62,13 +77,24 @@ func subCommands(opts *options.TerragruntOptions) cli.Commands {
 	cmds := cli.Commands{
 		terragruntinfo.NewCommand(opts),    // terragrunt-info
 		validateinputs.NewCommand(opts),    // validate-inputs
+		moduledocs.NewCommand(opts),        // module-docs
 		graphdependencies.NewCommand(opts), // graph-dependencies
 		hclfmt.NewCommand(opts),            // hclfmt
 		renderjson.NewCommand(opts),        // render-json
 		awsproviderpatch.NewCommand(opts),  // aws-provider-patch
+		newDiffCommand(opts),               // diff
 	}
 	sort.Sort(cmds)
 	cmds.Add(terraform.NewCommand(opts))
 
 	return cmds
 }
+
+func newDiffCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        SubCommandDiff,
+		Usage:       "Report the modules, dependencies and sources of the stack that changed between two git refs.",
+		Description: "The command resolves the stack of the current directory at both git refs, without running terraform, and reports the added and removed modules, the changed dependency edges and the changed sources. If only one ref is given, it is compared to the working tree.",
+		Action:      func(ctx *cli.Context) error { return RunDiff(ctx, opts.OptionsFromContext(ctx), ctx.Args().Tail()) },
+	}
+}
@@ -0,0 +1,307 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// GraphDiff is the structural diff of the stack between two git refs. The modules are the dirs of the modules relative
+// to the root of the repo.
+type GraphDiff struct {
+	AddedModules        []string         `json:"added_modules"`
+	RemovedModules      []string         `json:"removed_modules"`
+	AddedDependencies   []DependencyEdge `json:"added_dependencies"`
+	RemovedDependencies []DependencyEdge `json:"removed_dependencies"`
+	ChangedSources      []SourceChange   `json:"changed_sources"`
+}
+
+// DependencyEdge is the dependency of a module on another module.
+type DependencyEdge struct {
+	Module     string `json:"module"`
+	Dependency string `json:"dependency"`
+}
+
+// SourceChange is the change of the terraform source of a module.
+type SourceChange struct {
+	Module string `json:"module"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// IsEmpty returns true if the stack did not change.
+func (diff *GraphDiff) IsEmpty() bool {
+	return len(diff.AddedModules) == 0 && len(diff.RemovedModules) == 0 && len(diff.AddedDependencies) == 0 &&
+		len(diff.RemovedDependencies) == 0 && len(diff.ChangedSources) == 0
+}
+
+// stackGraph is the structure of a stack: the dependencies and the terraform source of every module, by the dir of the
+// module relative to the root of the repo.
+type stackGraph map[string]stackGraphModule
+
+type stackGraphModule struct {
+	Dependencies []string
+	Source       string
+}
+
+// RunDiff resolves the stack of the working dir at the two given git refs, without running terraform, and prints the
+// modules added and removed, the dependencies that changed and the modules whose terraform source changed. If only one
+// ref is given, it is compared to the working tree.
+func RunDiff(ctx context.Context, opts *options.TerragruntOptions, refs []string) error {
+	if len(refs) < 1 || len(refs) > 2 {
+		return errors.WithStackTrace(WrongNumberOfDiffRefs(len(refs)))
+	}
+
+	workingDir, err := filepath.EvalSymlinks(opts.WorkingDir)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	gitRoot, err := shell.GitTopLevelDir(ctx, opts, workingDir)
+	if err != nil {
+		return err
+	}
+	relWorkingDir, err := filepath.Rel(gitRoot, workingDir)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	fromGraph, err := stackGraphAtRef(ctx, opts, gitRoot, relWorkingDir, refs[0])
+	if err != nil {
+		return err
+	}
+
+	var toGraph stackGraph
+	if len(refs) == 2 {
+		toGraph, err = stackGraphAtRef(ctx, opts, gitRoot, relWorkingDir, refs[1])
+	} else {
+		toGraph, err = stackGraphInDir(ctx, opts, gitRoot, workingDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	diff := diffStackGraphs(fromGraph, toGraph)
+
+	if opts.GraphDiffJSON {
+		content, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		_, err = fmt.Fprintln(opts.Writer, string(content))
+		return errors.WithStackTrace(err)
+	}
+
+	to := "the working tree"
+	if len(refs) == 2 {
+		to = refs[1]
+	}
+	return printGraphDiff(opts.Writer, diff, refs[0], to)
+}
+
+// stackGraphAtRef checks out the given git ref into a temporary worktree and resolves the stack of the given dir, which
+// is relative to the root of the repo, in it.
+func stackGraphAtRef(ctx context.Context, opts *options.TerragruntOptions, gitRoot, relWorkingDir, ref string) (stackGraph, error) {
+	tempDir, err := os.MkdirTemp("", "terragrunt-graph-diff-")
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer os.RemoveAll(tempDir) //nolint:errcheck
+
+	if tempDir, err = filepath.EvalSymlinks(tempDir); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	worktreeDir := filepath.Join(tempDir, "worktree")
+
+	if err := runGit(ctx, opts, gitRoot, "worktree", "add", "--detach", worktreeDir, ref); err != nil {
+		return nil, errors.WithStackTrace(GitRefCheckoutFailed{Ref: ref, Err: err})
+	}
+	defer func() {
+		if err := runGit(ctx, opts, gitRoot, "worktree", "remove", "--force", worktreeDir); err != nil {
+			opts.Logger.Warnf("Failed to remove the worktree %s of %s: %v", worktreeDir, ref, err)
+		}
+	}()
+
+	opts.Logger.Debugf("Resolving the stack at %s in %s", ref, worktreeDir)
+	return stackGraphInDir(ctx, opts, worktreeDir, filepath.Join(worktreeDir, relWorkingDir))
+}
+
+// stackGraphInDir resolves the stack of the given dir, in the repo in the given root dir. The stack is empty if the dir
+// does not exist.
+func stackGraphInDir(ctx context.Context, opts *options.TerragruntOptions, rootDir, workingDir string) (stackGraph, error) {
+	if !util.IsDir(workingDir) {
+		return stackGraph{}, nil
+	}
+
+	stackOpts := opts.Clone(config.GetDefaultConfigPath(workingDir))
+	stackOpts.WorkingDir = workingDir
+	// Only the structure of the stack is needed, the external dependencies are never run
+	stackOpts.IgnoreExternalDependencies = true
+
+	stack, err := configstack.FindStackInSubfolders(ctx, stackOpts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newStackGraph(stack, rootDir), nil
+}
+
+func newStackGraph(stack *configstack.Stack, rootDir string) stackGraph {
+	relPath := func(path string) string {
+		if relPath, err := filepath.Rel(rootDir, path); err == nil && !strings.HasPrefix(relPath, "..") {
+			return filepath.ToSlash(relPath)
+		}
+		return path
+	}
+
+	graph := stackGraph{}
+	for _, module := range stack.Modules {
+		var graphModule stackGraphModule
+		for _, dependency := range module.Dependencies {
+			graphModule.Dependencies = append(graphModule.Dependencies, relPath(dependency.Path))
+		}
+		sort.Strings(graphModule.Dependencies)
+
+		if module.Config.Terraform != nil && module.Config.Terraform.Source != nil {
+			// The sources in the repo, e.g. built with get_repo_root(), are made relative to the repo, as the repo is
+			// checked out in a different dir at every ref
+			graphModule.Source = strings.ReplaceAll(*module.Config.Terraform.Source, rootDir+"/", "./")
+		}
+		graph[relPath(module.Path)] = graphModule
+	}
+	return graph
+}
+
+func diffStackGraphs(from, to stackGraph) *GraphDiff {
+	diff := &GraphDiff{
+		AddedModules:        []string{},
+		RemovedModules:      []string{},
+		AddedDependencies:   []DependencyEdge{},
+		RemovedDependencies: []DependencyEdge{},
+		ChangedSources:      []SourceChange{},
+	}
+
+	for _, module := range sortedModules(to) {
+		fromModule, existed := from[module]
+		if !existed {
+			diff.AddedModules = append(diff.AddedModules, module)
+		} else if fromModule.Source != to[module].Source {
+			diff.ChangedSources = append(diff.ChangedSources, SourceChange{Module: module, From: fromModule.Source, To: to[module].Source})
+		}
+
+		for _, dependency := range to[module].Dependencies {
+			if !util.ListContainsElement(fromModule.Dependencies, dependency) {
+				diff.AddedDependencies = append(diff.AddedDependencies, DependencyEdge{Module: module, Dependency: dependency})
+			}
+		}
+	}
+
+	for _, module := range sortedModules(from) {
+		toModule, exists := to[module]
+		if !exists {
+			diff.RemovedModules = append(diff.RemovedModules, module)
+		}
+
+		for _, dependency := range from[module].Dependencies {
+			if !util.ListContainsElement(toModule.Dependencies, dependency) {
+				diff.RemovedDependencies = append(diff.RemovedDependencies, DependencyEdge{Module: module, Dependency: dependency})
+			}
+		}
+	}
+
+	return diff
+}
+
+func sortedModules(graph stackGraph) []string {
+	var modules []string
+	for module := range graph {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+func printGraphDiff(writer io.Writer, diff *GraphDiff, from, to string) error {
+	if diff.IsEmpty() {
+		_, err := fmt.Fprintf(writer, "No changes to the stack between %s and %s.\n", from, to)
+		return errors.WithStackTrace(err)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Changes to the stack between %s and %s:\n", from, to)
+
+	printSection := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&out, "\n%s:\n", title)
+		for _, line := range lines {
+			fmt.Fprintf(&out, "  %s\n", line)
+		}
+	}
+
+	var lines []string
+	for _, module := range diff.AddedModules {
+		lines = append(lines, "+ "+module)
+	}
+	printSection("Modules added", lines)
+
+	lines = nil
+	for _, module := range diff.RemovedModules {
+		lines = append(lines, "- "+module)
+	}
+	printSection("Modules removed", lines)
+
+	lines = nil
+	for _, edge := range diff.AddedDependencies {
+		lines = append(lines, fmt.Sprintf("+ %s -> %s", edge.Module, edge.Dependency))
+	}
+	printSection("Dependencies added", lines)
+
+	lines = nil
+	for _, edge := range diff.RemovedDependencies {
+		lines = append(lines, fmt.Sprintf("- %s -> %s", edge.Module, edge.Dependency))
+	}
+	printSection("Dependencies removed", lines)
+
+	lines = nil
+	for _, change := range diff.ChangedSources {
+		lines = append(lines, fmt.Sprintf("~ %s: %s -> %s", change.Module, sourceOrNone(change.From), sourceOrNone(change.To)))
+	}
+	printSection("Sources changed", lines)
+
+	_, err := io.WriteString(writer, out.String())
+	return errors.WithStackTrace(err)
+}
+
+func sourceOrNone(source string) string {
+	if source == "" {
+		return "(none)"
+	}
+	return source
+}
+
+// runGit runs git in the given dir, returning its stderr as the error if it fails.
+func runGit(ctx context.Context, opts *options.TerragruntOptions, dir string, args ...string) error {
+	gitOpts := opts.Clone(opts.TerragruntConfigPath)
+	gitOpts.Writer = io.Discard
+	gitOpts.ErrWriter = io.Discard
+
+	output, err := shell.RunShellCommandWithOutput(ctx, gitOpts, dir, true, false, "git", args...)
+	if err != nil && output != nil {
+		if details := strings.TrimSpace(output.Stderr); details != "" {
+			return errors.WithStackTrace(GitCommandFailed(details))
+		}
+	}
+	return err
+}
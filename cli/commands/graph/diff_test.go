@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffStackGraphs(t *testing.T) {
+	t.Parallel()
+
+	from := stackGraph{
+		"live/vpc": {Source: "git::https://example.com/modules.git//vpc?ref=v1.0.0"},
+		"live/db":  {Dependencies: []string{"live/vpc"}},
+		"live/app": {Dependencies: []string{"live/db", "live/vpc"}},
+	}
+	to := stackGraph{
+		"live/vpc":   {Source: "git::https://example.com/modules.git//vpc?ref=v1.1.0"},
+		"live/cache": {Dependencies: []string{"live/vpc"}},
+		"live/app":   {Dependencies: []string{"live/cache", "live/vpc"}},
+	}
+
+	diff := diffStackGraphs(from, to)
+	assert.Equal(t, []string{"live/cache"}, diff.AddedModules)
+	assert.Equal(t, []string{"live/db"}, diff.RemovedModules)
+	assert.Equal(t, []DependencyEdge{{Module: "live/app", Dependency: "live/cache"}, {Module: "live/cache", Dependency: "live/vpc"}}, diff.AddedDependencies)
+	assert.Equal(t, []DependencyEdge{{Module: "live/app", Dependency: "live/db"}, {Module: "live/db", Dependency: "live/vpc"}}, diff.RemovedDependencies)
+	assert.Equal(t, []SourceChange{{Module: "live/vpc", From: "git::https://example.com/modules.git//vpc?ref=v1.0.0", To: "git::https://example.com/modules.git//vpc?ref=v1.1.0"}}, diff.ChangedSources)
+
+	assert.True(t, diffStackGraphs(from, from).IsEmpty())
+}
+
+func TestRunDiff(t *testing.T) {
+	t.Parallel()
+
+	repoDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	runGitCommand(t, repoDir, "init", "--quiet")
+
+	writeModule := func(dir, config string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(repoDir, dir), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, dir, "terragrunt.hcl"), []byte(config), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, dir, "main.tf"), nil, 0644))
+	}
+
+	writeModule("live/vpc", "terraform {\n  source = \"${get_repo_root()}/modules//vpc\"\n}\n")
+	writeModule("live/app", "dependency \"vpc\" {\n  config_path = \"../vpc\"\n}\n")
+	runGitCommand(t, repoDir, "add", "--all")
+	runGitCommand(t, repoDir, "commit", "--quiet", "--message", "first")
+
+	writeModule("live/db", "dependency \"vpc\" {\n  config_path = \"../vpc\"\n}\n")
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(repoDir, "live", "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = filepath.Join(repoDir, "live")
+	opts.GraphDiffJSON = true
+	stdout := bytes.Buffer{}
+	opts.Writer = &stdout
+
+	require.NoError(t, RunDiff(context.Background(), opts, []string{"HEAD"}))
+
+	var diff GraphDiff
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &diff))
+	assert.Equal(t, []string{"live/db"}, diff.AddedModules)
+	assert.Equal(t, []DependencyEdge{{Module: "live/db", Dependency: "live/vpc"}}, diff.AddedDependencies)
+	assert.Empty(t, diff.RemovedModules)
+	// The sources in the repo are compared relative to the root of the repo
+	assert.Empty(t, diff.ChangedSources)
+}
+
+func runGitCommand(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
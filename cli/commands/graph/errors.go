@@ -0,0 +1,30 @@
+package graph
+
+import "fmt"
+
+// Custom error types
+
+type WrongNumberOfDiffRefs int
+
+func (count WrongNumberOfDiffRefs) Error() string {
+	return fmt.Sprintf("The %s %s command takes one or two git refs, e.g. `%s %s main HEAD`, but got %d", CommandName, SubCommandDiff, CommandName, SubCommandDiff, int(count))
+}
+
+type GitRefCheckoutFailed struct {
+	Ref string
+	Err error
+}
+
+func (err GitRefCheckoutFailed) Error() string {
+	return fmt.Sprintf("Failed to check out the git ref %s: %v", err.Ref, err.Err)
+}
+
+func (err GitRefCheckoutFailed) Unwrap() error {
+	return err.Err
+}
+
+type GitCommandFailed string
+
+func (details GitCommandFailed) Error() string {
+	return string(details)
+}
@@ -0,0 +1,202 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// ModuleTrend is how a module ran over the analyzed runs.
+type ModuleTrend struct {
+	Path string
+	// The number of runs which ran the module, whatever the outcome
+	Runs     int
+	Failures int
+	// The number of times the outcome of the module changed from one run to the next, between succeeded and failed
+	Flips   int
+	Retries int64
+	// The average duration of the successful runs, and the duration of the last one
+	MeanDuration time.Duration
+	LastDuration time.Duration
+}
+
+// Flakiness returns how flaky the module is: how many times it flipped between succeeded and failed, and how many times
+// it was retried.
+func (trend *ModuleTrend) Flakiness() int64 {
+	return int64(trend.Flips) + trend.Retries
+}
+
+// Run prints the trends of the last given number of runs recorded in the run history.
+func Run(opts *options.TerragruntOptions, runsCount, limit int) error {
+	if opts.HistoryFile == "" {
+		return errors.WithStackTrace(HistoryFileNotSet{})
+	}
+	if runsCount < 1 {
+		return errors.WithStackTrace(InvalidCount{Flag: FlagNameRuns, Count: runsCount})
+	}
+	if limit < 1 {
+		return errors.WithStackTrace(InvalidCount{Flag: FlagNameLimit, Count: limit})
+	}
+
+	runs, err := configstack.NewRunHistoryStore(opts.HistoryFile).Runs()
+	if err != nil {
+		return err
+	}
+
+	total := len(runs)
+	if total > runsCount {
+		runs = runs[total-runsCount:]
+	}
+
+	_, err = fmt.Fprint(opts.Writer, historyString(runs, total, limit))
+	return errors.WithStackTrace(err)
+}
+
+// ModuleTrends returns the trends of the modules of the given runs, oldest first, sorted by path.
+func ModuleTrends(runs []*configstack.HistoryRun) []*ModuleTrend {
+	trends := map[string]*ModuleTrend{}
+	lastOutcomes := map[string]string{}
+	successes := map[string]int{}
+
+	for _, run := range runs {
+		for _, module := range run.Modules {
+			trend, ok := trends[module.Path]
+			if !ok {
+				trend = &ModuleTrend{Path: module.Path}
+				trends[module.Path] = trend
+			}
+			trend.Retries += module.Retries
+
+			// The skipped modules did not run
+			if module.Outcome != configstack.OutcomeSucceeded && module.Outcome != configstack.OutcomeFailed {
+				continue
+			}
+			trend.Runs++
+			if module.Outcome == configstack.OutcomeFailed {
+				trend.Failures++
+			}
+			if lastOutcome, ok := lastOutcomes[module.Path]; ok && lastOutcome != module.Outcome {
+				trend.Flips++
+			}
+			lastOutcomes[module.Path] = module.Outcome
+
+			if module.Outcome == configstack.OutcomeSucceeded && module.Duration > 0 {
+				duration := secondsDuration(module.Duration)
+				trend.MeanDuration += (duration - trend.MeanDuration) / time.Duration(successes[module.Path]+1)
+				trend.LastDuration = duration
+				successes[module.Path]++
+			}
+		}
+	}
+
+	sorted := make([]*ModuleTrend, 0, len(trends))
+	for _, trend := range trends {
+		sorted = append(sorted, trend)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted
+}
+
+// SlowestModules returns the given number of modules with the longest average duration, the slowest first.
+func SlowestModules(trends []*ModuleTrend, limit int) []*ModuleTrend {
+	var slowest []*ModuleTrend
+	for _, trend := range trends {
+		if trend.MeanDuration > 0 {
+			slowest = append(slowest, trend)
+		}
+	}
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].MeanDuration > slowest[j].MeanDuration
+	})
+	return truncate(slowest, limit)
+}
+
+// FlakiestModules returns the given number of modules which flipped between succeeded and failed or were retried the
+// most, the flakiest first.
+func FlakiestModules(trends []*ModuleTrend, limit int) []*ModuleTrend {
+	var flakiest []*ModuleTrend
+	for _, trend := range trends {
+		if trend.Flakiness() > 0 {
+			flakiest = append(flakiest, trend)
+		}
+	}
+	sort.SliceStable(flakiest, func(i, j int) bool {
+		return flakiest[i].Flakiness() > flakiest[j].Flakiness()
+	})
+	return truncate(flakiest, limit)
+}
+
+func historyString(runs []*configstack.HistoryRun, total, limit int) string {
+	if len(runs) == 0 {
+		return "The run history has no runs.\n"
+	}
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Last %d of %d runs:", len(runs), total))
+	for i := len(runs) - 1; i >= 0; i-- {
+		lines = append(lines, "  "+runString(runs[i]))
+	}
+
+	trends := ModuleTrends(runs)
+
+	lines = append(lines, "", "Slowest modules:")
+	slowest := SlowestModules(trends, limit)
+	if len(slowest) == 0 {
+		lines = append(lines, "  No module succeeded")
+	}
+	for _, trend := range slowest {
+		lines = append(lines, fmt.Sprintf("  - %s: %s on average, %s in the last successful run", trend.Path, formatDuration(trend.MeanDuration), formatDuration(trend.LastDuration)))
+	}
+
+	lines = append(lines, "", "Flakiest modules:")
+	flakiest := FlakiestModules(trends, limit)
+	if len(flakiest) == 0 {
+		lines = append(lines, "  No module flipped between succeeded and failed or was retried")
+	}
+	for _, trend := range flakiest {
+		lines = append(lines, fmt.Sprintf("  - %s: %d flips, %d of %d runs failed, %d retries", trend.Path, trend.Flips, trend.Failures, trend.Runs, trend.Retries))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// runString returns the summary of the given run, e.g. `2024-05-01 10:00:00 apply (run 0123): 3 succeeded, 1 failed in 2m30s`.
+func runString(run *configstack.HistoryRun) string {
+	counts := map[string]int{}
+	for _, module := range run.Modules {
+		counts[module.Outcome]++
+	}
+
+	outcomes := []string{fmt.Sprintf("%d succeeded", counts[configstack.OutcomeSucceeded]), fmt.Sprintf("%d failed", counts[configstack.OutcomeFailed])}
+	if skipped := counts[configstack.OutcomeSkippedUpstreamFailure] + counts[configstack.OutcomeSkippedByUser]; skipped > 0 {
+		outcomes = append(outcomes, fmt.Sprintf("%d skipped", skipped))
+	}
+
+	runID := ""
+	if run.RunID != "" {
+		runID = fmt.Sprintf(" (run %s)", run.RunID)
+	}
+	return fmt.Sprintf("%s %s%s: %s in %s", run.StartedAt.Local().Format(time.DateTime), run.Command, runID, strings.Join(outcomes, ", "), formatDuration(secondsDuration(run.Duration)))
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func formatDuration(duration time.Duration) string {
+	return duration.Round(time.Second).String()
+}
+
+func truncate(trends []*ModuleTrend, limit int) []*ModuleTrend {
+	if len(trends) > limit {
+		return trends[:limit]
+	}
+	return trends
+}
@@ -0,0 +1,74 @@
+package history_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/history"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRun(modules ...*configstack.HistoryModule) *configstack.HistoryRun {
+	return &configstack.HistoryRun{Command: "apply", Modules: modules}
+}
+
+func TestModuleTrends(t *testing.T) {
+	t.Parallel()
+
+	runs := []*configstack.HistoryRun{
+		newRun(&configstack.HistoryModule{Path: "vpc", Outcome: configstack.OutcomeSucceeded, Duration: 10}, &configstack.HistoryModule{Path: "app", Outcome: configstack.OutcomeSucceeded, Duration: 1}),
+		newRun(&configstack.HistoryModule{Path: "vpc", Outcome: configstack.OutcomeSucceeded, Duration: 20}, &configstack.HistoryModule{Path: "app", Outcome: configstack.OutcomeFailed, Retries: 2}),
+		newRun(&configstack.HistoryModule{Path: "vpc", Outcome: configstack.OutcomeFailed}, &configstack.HistoryModule{Path: "app", Outcome: configstack.OutcomeSkippedUpstreamFailure}),
+		newRun(&configstack.HistoryModule{Path: "vpc", Outcome: configstack.OutcomeSucceeded, Duration: 30}, &configstack.HistoryModule{Path: "app", Outcome: configstack.OutcomeSucceeded, Duration: 3}),
+	}
+
+	trends := history.ModuleTrends(runs)
+	assert.Equal(t, []*history.ModuleTrend{
+		{Path: "app", Runs: 3, Failures: 1, Flips: 2, Retries: 2, MeanDuration: 2 * time.Second, LastDuration: 3 * time.Second},
+		{Path: "vpc", Runs: 4, Failures: 1, Flips: 2, MeanDuration: 20 * time.Second, LastDuration: 30 * time.Second},
+	}, trends)
+
+	slowest := history.SlowestModules(trends, 1)
+	require.Len(t, slowest, 1)
+	assert.Equal(t, "vpc", slowest[0].Path)
+
+	flakiest := history.FlakiestModules(trends, 10)
+	require.Len(t, flakiest, 2)
+	assert.Equal(t, "app", flakiest[0].Path)
+}
+
+func TestRunHistoryFileNotSet(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("history_test")
+	require.NoError(t, err)
+
+	err = history.Run(opts, 20, 10)
+	var notSet history.HistoryFileNotSet
+	require.ErrorAs(t, err, &notSet)
+}
+
+func TestRunHistory(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("history_test")
+	require.NoError(t, err)
+	opts.HistoryFile = filepath.Join(t.TempDir(), "runs.jsonl")
+	var out bytes.Buffer
+	opts.Writer = &out
+
+	store := configstack.NewRunHistoryStore(opts.HistoryFile)
+	require.NoError(t, store.Append(newRun(&configstack.HistoryModule{Path: "vpc", Outcome: configstack.OutcomeSucceeded, Duration: 10})))
+	require.NoError(t, store.Append(newRun(&configstack.HistoryModule{Path: "vpc", Outcome: configstack.OutcomeFailed, Retries: 1})))
+	require.NoError(t, store.Append(newRun(&configstack.HistoryModule{Path: "vpc", Outcome: configstack.OutcomeSucceeded, Duration: 20})))
+
+	require.NoError(t, history.Run(opts, 2, 10))
+	assert.Contains(t, out.String(), "Last 2 of 3 runs:")
+	assert.Contains(t, out.String(), "  - vpc: 20s on average, 20s in the last successful run")
+	assert.Contains(t, out.String(), "  - vpc: 1 flips, 1 of 2 runs failed, 1 retries")
+}
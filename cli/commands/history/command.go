@@ -0,0 +1,37 @@
+package history
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "history"
+
+	FlagNameRuns  = "runs"
+	FlagNameLimit = "limit"
+
+	defaultRuns  = 20
+	defaultLimit = 10
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	runs, limit := defaultRuns, defaultLimit
+	return &cli.Command{
+		Name:  CommandName,
+		Usage: "Show the trends of the runs recorded in the run history of --terragrunt-history-file: the last runs, the slowest modules and the flakiest modules.",
+		Flags: cli.Flags{
+			&cli.GenericFlag[int]{
+				Name:        FlagNameRuns,
+				Destination: &runs,
+				Usage:       "The number of the last runs analyzed.",
+			},
+			&cli.GenericFlag[int]{
+				Name:        FlagNameLimit,
+				Destination: &limit,
+				Usage:       "The number of modules listed as the slowest and the flakiest.",
+			},
+		},
+		Action: func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx), runs, limit) },
+	}
+}
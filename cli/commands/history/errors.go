@@ -0,0 +1,22 @@
+package history
+
+import (
+	"fmt"
+)
+
+// Custom error types
+
+type HistoryFileNotSet struct{}
+
+func (err HistoryFileNotSet) Error() string {
+	return "The history command reads the run history of --terragrunt-history-file, which is not set."
+}
+
+type InvalidCount struct {
+	Flag  string
+	Count int
+}
+
+func (err InvalidCount) Error() string {
+	return fmt.Sprintf("--%s must be at least 1, got %d.", err.Flag, err.Count)
+}
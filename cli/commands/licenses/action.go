@@ -0,0 +1,258 @@
+// `licenses` command reports the licenses of the sources of the module: the source of the terraform code of the
+// module, set in the terraform block, and the sources of all the modules the code pulls in, i.e. the modules terraform
+// installs in .terraform/modules. The sources whose licenses match the denylist are flagged and fail the command.
+//
+// With run-all, the sources of all the modules of the stack are aggregated into a single report, listing every source
+// once with the modules that use it.
+
+package licenses
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	tf "github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The file terragrunt writes at the root of every downloaded source.
+const sourceVersionFile = ".terragrunt-source-version"
+
+func Run(ctx context.Context, opts *options.TerragruntOptions, report *Report) error {
+	for _, pattern := range opts.LicenseDenylist {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.WithStackTrace(InvalidLicensePattern(pattern))
+		}
+	}
+
+	target := terraform.NewTarget(terraform.TargetPointInitCommand, func(ctx context.Context, opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+		return runLicenses(opts, cfg, report)
+	})
+
+	return terraform.RunWithTarget(ctx, opts, target)
+}
+
+func runLicenses(opts *options.TerragruntOptions, cfg *config.TerragruntConfig, report *Report) error {
+	moduleDir := filepath.Dir(opts.TerragruntConfigPath)
+
+	if cfg.Terraform != nil && cfg.Terraform.Source != nil && *cfg.Terraform.Source != "" {
+		licenses, files, err := findLicenses(opts.WorkingDir, sourceRootDir(opts.WorkingDir))
+		if err != nil {
+			return err
+		}
+		report.Add(moduleDir, *cfg.Terraform.Source, "", licenses, files)
+	}
+
+	modules, err := tf.InstalledModules(opts)
+	if err != nil {
+		return err
+	}
+
+	modulesDir := filepath.Join(opts.DataDir(), "modules")
+	for _, module := range modules {
+		if module.IsLocal() {
+			// The local modules are covered by the license of the module calling them
+			continue
+		}
+
+		dir := module.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(opts.WorkingDir, dir)
+		}
+		licenses, files, err := findLicenses(dir, moduleRootDir(dir, modulesDir))
+		if err != nil {
+			return err
+		}
+		opts.Logger.Debugf("Found the licenses %s of the module %s from %s", strings.Join(licenses, ", "), module.Key, module.Source)
+		report.Add(moduleDir, module.Source, module.Version, licenses, files)
+	}
+	return nil
+}
+
+// sourceRootDir returns the root of the source downloaded into the given working dir, i.e. the dir of the repo before
+// the double-slash of the source URL, or the working dir itself if the root is not found.
+func sourceRootDir(workingDir string) string {
+	for dir := workingDir; ; dir = filepath.Dir(dir) {
+		if util.FileExists(filepath.Join(dir, sourceVersionFile)) {
+			return dir
+		}
+		if filepath.Dir(dir) == dir {
+			return workingDir
+		}
+	}
+}
+
+// moduleRootDir returns the root of the module installed by terraform in the given dir, i.e. the dir of the module in
+// the modules dir of terraform, which is the root of the repo for the modules from a subdir of a repo.
+func moduleRootDir(dir, modulesDir string) string {
+	relDir, err := filepath.Rel(modulesDir, dir)
+	if err != nil || relDir == "." || strings.HasPrefix(relDir, "..") {
+		return dir
+	}
+	return filepath.Join(modulesDir, strings.SplitN(filepath.ToSlash(relDir), "/", 2)[0])
+}
+
+// SourceLicenses is a source of the modules with the licenses it was found to have.
+type SourceLicenses struct {
+	Source   string   `json:"source"`
+	Version  string   `json:"version,omitempty"`
+	Licenses []string `json:"licenses"`
+	Files    []string `json:"license_files,omitempty"`
+	// The licenses of the source matching the denylist.
+	Denied []string `json:"denied,omitempty"`
+	// The dirs of the terragrunt modules using the source.
+	Modules []string `json:"modules"`
+}
+
+func (source *SourceLicenses) String() string {
+	if source.Version != "" {
+		return source.Source + "@" + source.Version
+	}
+	return source.Source
+}
+
+// Report collects the licenses of the sources of one or, with run-all, all the modules of the stack. It is safe for
+// concurrent use.
+type Report struct {
+	mu      sync.Mutex
+	sources map[string]*SourceLicenses
+}
+
+// Add records the licenses of the source used by the module in the given dir.
+func (report *Report) Add(moduleDir, source, version string, licenses, files []string) {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	if report.sources == nil {
+		report.sources = map[string]*SourceLicenses{}
+	}
+
+	key := source + "@" + version
+	sourceLicenses, ok := report.sources[key]
+	if !ok {
+		sourceLicenses = &SourceLicenses{Source: source, Version: version, Licenses: licenses, Files: files}
+		report.sources[key] = sourceLicenses
+	}
+	if !util.ListContainsElement(sourceLicenses.Modules, moduleDir) {
+		sourceLicenses.Modules = append(sourceLicenses.Modules, moduleDir)
+	}
+}
+
+// Sources returns the sources of the report, sorted, with the licenses matching the given denylist flagged and the
+// dirs of the modules relative to the given dir.
+func (report *Report) Sources(denylist []string, workingDir string) []*SourceLicenses {
+	report.mu.Lock()
+	defer report.mu.Unlock()
+
+	var sources []*SourceLicenses
+	for _, source := range report.sources {
+		source := *source
+
+		for _, license := range source.Licenses {
+			if isDenied(license, denylist) {
+				source.Denied = append(source.Denied, license)
+			}
+		}
+
+		var modules []string
+		for _, moduleDir := range source.Modules {
+			if relDir, err := util.GetPathRelativeTo(moduleDir, workingDir); err == nil {
+				moduleDir = relDir
+			}
+			modules = append(modules, moduleDir)
+		}
+		sort.Strings(modules)
+		source.Modules = modules
+
+		sources = append(sources, &source)
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].String() < sources[j].String()
+	})
+	return sources
+}
+
+// Finish prints the report, writes it to the report file, if set, and returns an error if any of the sources has a
+// denied license.
+func (report *Report) Finish(opts *options.TerragruntOptions) error {
+	sources := report.Sources(opts.LicenseDenylist, opts.WorkingDir)
+
+	if len(sources) == 0 {
+		opts.Logger.Infof("No module sources found")
+	} else if err := printReport(opts, sources); err != nil {
+		return err
+	}
+
+	if opts.LicenseReportFile != "" {
+		content, err := json.MarshalIndent(map[string]interface{}{"sources": sources}, "", "  ")
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if err := os.WriteFile(opts.LicenseReportFile, content, os.FileMode(0644)); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	var deniedSources []*SourceLicenses
+	for _, source := range sources {
+		if len(source.Denied) > 0 {
+			deniedSources = append(deniedSources, source)
+		}
+	}
+	if len(deniedSources) > 0 {
+		return errors.WithStackTrace(DeniedLicensesFound{Sources: deniedSources})
+	}
+	return nil
+}
+
+func printReport(opts *options.TerragruntOptions, sources []*SourceLicenses) error {
+	writer := tabwriter.NewWriter(opts.Writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "SOURCE\tLICENSES\tDENIED\tMODULES")
+	for _, source := range sources {
+		denied := "-"
+		if len(source.Denied) > 0 {
+			denied = strings.Join(source.Denied, ", ")
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", source.String(), strings.Join(source.Licenses, ", "), denied, strings.Join(source.Modules, ", "))
+	}
+	return errors.WithStackTrace(writer.Flush())
+}
+
+// isDenied returns true if the license matches one of the SPDX identifiers or globs of the denylist, ignoring the
+// case. A license expression, e.g. `MIT OR Apache-2.0`, is denied only if every alternative of it has a denied license.
+func isDenied(license string, denylist []string) bool {
+	for _, alternative := range strings.Split(license, " OR ") {
+		if !hasDeniedLicense(alternative, denylist) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasDeniedLicense(expression string, denylist []string) bool {
+	ids := strings.FieldsFunc(expression, func(r rune) bool { return r == ' ' || r == '(' || r == ')' })
+	for _, id := range ids {
+		if id == "AND" || id == "WITH" {
+			continue
+		}
+		for _, pattern := range denylist {
+			if matched, _ := path.Match(strings.ToUpper(pattern), strings.ToUpper(id)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentifyLicense(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		text     string
+		expected string
+	}{
+		{"MIT License\n\nPermission is hereby granted, free of charge, to any person\nobtaining a copy", "MIT"},
+		{"                                 Apache License\n                           Version 2.0, January 2004", "Apache-2.0"},
+		{"Mozilla Public License Version 2.0\n...\n(b) the GNU Lesser General Public License, Version 2.1", "MPL-2.0"},
+		{"GNU AFFERO GENERAL PUBLIC LICENSE\n Version 3, 19 November 2007", "AGPL-3.0"},
+		{"GNU LESSER GENERAL PUBLIC LICENSE\n Version 2.1, February 1999", "LGPL-2.1"},
+		{"GNU GENERAL PUBLIC LICENSE\n Version 2, June 1991", "GPL-2.0"},
+		{"Redistribution and use in source and binary forms, with or without\nmodification, are permitted", "BSD-2-Clause"},
+		{"SPDX-License-Identifier: MIT OR Apache-2.0", "MIT OR Apache-2.0"},
+		{"All rights reserved.", LicenseNoAssertion},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, IdentifyLicense(testCase.text), testCase.text)
+	}
+}
+
+func TestFindLicenses(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	moduleDir := filepath.Join(rootDir, "modules", "vpc")
+	require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "LICENSE.md"), []byte("GNU AFFERO GENERAL PUBLIC LICENSE\nVersion 3"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte("# SPDX-License-Identifier: MPL-2.0\n\nresource \"null_resource\" \"this\" {}\n"), 0644))
+
+	licenses, files, err := findLicenses(moduleDir, rootDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AGPL-3.0", "MPL-2.0"}, licenses)
+	assert.Equal(t, []string{"LICENSE.md"}, files)
+
+	// The license files outside the root dir are not searched
+	licenses, files, err = findLicenses(moduleDir, moduleDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MPL-2.0"}, licenses)
+	assert.Empty(t, files)
+
+	licenses, _, err = findLicenses(rootDir+"/modules", rootDir+"/modules")
+	require.NoError(t, err)
+	assert.Equal(t, []string{LicenseNone}, licenses)
+}
+
+func TestModuleRootDir(t *testing.T) {
+	t.Parallel()
+
+	modulesDir := filepath.Join("work", ".terraform", "modules")
+	assert.Equal(t, filepath.Join(modulesDir, "vpc"), moduleRootDir(filepath.Join(modulesDir, "vpc", "modules", "endpoints"), modulesDir))
+	assert.Equal(t, filepath.Join(modulesDir, "vpc"), moduleRootDir(filepath.Join(modulesDir, "vpc"), modulesDir))
+	assert.Equal(t, filepath.Join("other", "dir"), moduleRootDir(filepath.Join("other", "dir"), modulesDir))
+}
+
+func TestIsDenied(t *testing.T) {
+	t.Parallel()
+
+	denylist := []string{"agpl-*", "GPL-3.0", LicenseNone}
+
+	assert.True(t, isDenied("AGPL-3.0", denylist))
+	assert.True(t, isDenied("GPL-3.0", denylist))
+	assert.True(t, isDenied(LicenseNone, denylist))
+	assert.True(t, isDenied("MIT AND GPL-3.0", denylist))
+	assert.False(t, isDenied("MIT OR GPL-3.0", denylist))
+	assert.False(t, isDenied("LGPL-3.0", denylist))
+	assert.False(t, isDenied("Apache-2.0", nil))
+}
+
+func TestReportAggregatesSources(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{}
+	report.Add("/live/prod/vpc", "registry.terraform.io/terraform-aws-modules/vpc/aws", "5.1.0", []string{"Apache-2.0"}, []string{"LICENSE"})
+	report.Add("/live/dev/vpc", "registry.terraform.io/terraform-aws-modules/vpc/aws", "5.1.0", []string{"Apache-2.0"}, []string{"LICENSE"})
+	report.Add("/live/dev/vpc", "git::https://example.com/modules.git//vpc?ref=v1.0.0", "", []string{"AGPL-3.0"}, nil)
+
+	sources := report.Sources([]string{"AGPL-*"}, "/live")
+	require.Len(t, sources, 2)
+
+	assert.Equal(t, "git::https://example.com/modules.git//vpc?ref=v1.0.0", sources[0].String())
+	assert.Equal(t, []string{"AGPL-3.0"}, sources[0].Denied)
+	assert.Equal(t, []string{"dev/vpc"}, sources[0].Modules)
+
+	assert.Equal(t, "registry.terraform.io/terraform-aws-modules/vpc/aws@5.1.0", sources[1].String())
+	assert.Empty(t, sources[1].Denied)
+	assert.Equal(t, []string{"dev/vpc", "prod/vpc"}, sources[1].Modules)
+}
@@ -0,0 +1,43 @@
+package licenses
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "licenses"
+
+	FlagNameTerragruntLicenseDeny   = "terragrunt-license-deny"
+	FlagNameTerragruntLicenseReport = "terragrunt-license-report"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.SliceFlag[string]{
+			Name:        FlagNameTerragruntLicenseDeny,
+			Destination: &opts.LicenseDenylist,
+			EnvVar:      "TERRAGRUNT_LICENSE_DENY",
+			Usage:       "The SPDX identifier of a license to deny in the module sources, e.g. AGPL-3.0, GPL-* or NONE for the sources without a license. May be specified multiple times.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntLicenseReport,
+			Destination: &opts.LicenseReportFile,
+			EnvVar:      "TERRAGRUNT_LICENSE_REPORT",
+			Usage:       "The file to write the license report to in JSON, in addition to printing it.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	// The report aggregates the sources of all modules when the command runs with run-all
+	report := &Report{}
+
+	return &cli.Command{
+		Name:   CommandName,
+		Usage:  "Report the licenses of the module sources, including the modules they pull in, and flag the denied ones.",
+		Flags:  NewFlags(opts).Sort(),
+		Action: func(ctx *cli.Context) error { return Run(ctx, opts.OptionsFromContext(ctx), report) },
+		After:  func(ctx *cli.Context) error { return report.Finish(opts.OptionsFromContext(ctx)) },
+	}
+}
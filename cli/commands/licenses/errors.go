@@ -0,0 +1,27 @@
+package licenses
+
+import (
+	"fmt"
+	"strings"
+)
+
+type InvalidLicensePattern string
+
+func (pattern InvalidLicensePattern) Error() string {
+	return fmt.Sprintf("Invalid license pattern %q in --%s", string(pattern), FlagNameTerragruntLicenseDeny)
+}
+
+type DeniedLicensesFound struct {
+	Sources []*SourceLicenses
+}
+
+func (err DeniedLicensesFound) Error() string {
+	var sources []string
+	for _, source := range err.Sources {
+		sources = append(sources, fmt.Sprintf("%s (%s)", source.String(), strings.Join(source.Denied, ", ")))
+	}
+	return fmt.Sprintf("Found the module sources with denied licenses: %s", strings.Join(sources, ", "))
+}
+
+// PolicyViolation marks the error as Terragrunt refusing the sources the denylist forbids.
+func (err DeniedLicensesFound) PolicyViolation() {}
@@ -0,0 +1,186 @@
+package licenses
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+const (
+	// The SPDX identifier of the sources without any license.
+	LicenseNone = "NONE"
+	// The SPDX identifier of the license files that could not be identified.
+	LicenseNoAssertion = "NOASSERTION"
+)
+
+// The number of lines of the top of the terraform files searched for a license header.
+const licenseHeaderLines = 20
+
+// The prefixes of the names of the license files, in upper case.
+var licenseFilePrefixes = []string{"LICENSE", "LICENCE", "COPYING", "UNLICENSE"}
+
+var spdxIdentifierRegexp = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+\-]+(?:\s+(?:AND|OR|WITH)\s+[A-Za-z0-9.+\-]+)*)`)
+
+// licenseMatcher identifies a license by the phrases its text contains. The matchers are checked in order, from the
+// most to the least specific, e.g. the LGPL before the GPL, and the MPL, which names the GPL licenses it is compatible
+// with, first.
+type licenseMatcher struct {
+	id      string
+	phrases []string
+}
+
+var licenseMatchers = []licenseMatcher{
+	{id: "MPL-2.0", phrases: []string{"Mozilla Public License", "2.0"}},
+	{id: "AGPL-3.0", phrases: []string{"GNU AFFERO GENERAL PUBLIC LICENSE"}},
+	{id: "LGPL-3.0", phrases: []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 3"}},
+	{id: "LGPL-2.1", phrases: []string{"GNU LESSER GENERAL PUBLIC LICENSE", "Version 2.1"}},
+	{id: "GPL-3.0", phrases: []string{"GNU GENERAL PUBLIC LICENSE", "Version 3"}},
+	{id: "GPL-2.0", phrases: []string{"GNU GENERAL PUBLIC LICENSE", "Version 2"}},
+	{id: "BUSL-1.1", phrases: []string{"Business Source License"}},
+	{id: "EUPL-1.2", phrases: []string{"European Union Public Licence", "v. 1.2"}},
+	{id: "Apache-2.0", phrases: []string{"Apache License", "Version 2.0"}},
+	{id: "BSD-3-Clause", phrases: []string{"Redistribution and use in source and binary forms", "Neither the name"}},
+	{id: "BSD-2-Clause", phrases: []string{"Redistribution and use in source and binary forms"}},
+	{id: "ISC", phrases: []string{"Permission to use, copy, modify, and/or distribute this software for any purpose"}},
+	{id: "MIT", phrases: []string{"Permission is hereby granted, free of charge"}},
+	{id: "Unlicense", phrases: []string{"This is free and unencumbered software released into the public domain"}},
+	{id: "CC0-1.0", phrases: []string{"CC0 1.0 Universal"}},
+}
+
+// IdentifyLicense returns the SPDX identifier of the license with the given text, or NOASSERTION if the license is not
+// known.
+func IdentifyLicense(text string) string {
+	if match := spdxIdentifierRegexp.FindStringSubmatch(text); match != nil {
+		return match[1]
+	}
+
+	// Ignore the line wrapping of the text. The case is not ignored, as the titles of the licenses are in upper case,
+	// unlike the references to the other licenses in their text.
+	normalized := strings.Join(strings.Fields(text), " ")
+	for _, matcher := range licenseMatchers {
+		matches := true
+		for _, phrase := range matcher.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return matcher.id
+		}
+	}
+	return LicenseNoAssertion
+}
+
+// isLicenseFile returns true if the file with the given name is a license file, e.g. LICENSE, LICENSE.md or COPYING.
+func isLicenseFile(name string) bool {
+	name = strings.ToUpper(name)
+	for _, prefix := range licenseFilePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// findLicenses returns the licenses of the module code in the given dir, and the license files they were identified
+// from, relative to the given root dir. The license files are searched in the dir and then in its parents up to the
+// root dir, as the modules in the subdirs of a repo are usually covered by the license file at the root of the repo.
+// The SPDX license headers of the terraform files of the dir are also included.
+func findLicenses(dir, rootDir string) ([]string, []string, error) {
+	licenses := map[string]bool{}
+	var files []string
+
+	for searchDir := dir; ; searchDir = filepath.Dir(searchDir) {
+		entries, err := os.ReadDir(searchDir)
+		if err != nil {
+			return nil, nil, errors.WithStackTrace(err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !isLicenseFile(entry.Name()) {
+				continue
+			}
+			file := filepath.Join(searchDir, entry.Name())
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, nil, errors.WithStackTrace(err)
+			}
+			licenses[IdentifyLicense(string(content))] = true
+
+			if relFile, err := filepath.Rel(rootDir, file); err == nil {
+				file = relFile
+			}
+			files = append(files, filepath.ToSlash(file))
+		}
+
+		parentDir := filepath.Dir(searchDir)
+		if len(files) > 0 || searchDir == rootDir || parentDir == searchDir || !strings.HasPrefix(searchDir, rootDir) {
+			break
+		}
+	}
+
+	headerLicenses, err := findLicenseHeaders(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, license := range headerLicenses {
+		licenses[license] = true
+	}
+
+	// A recognized license header makes up for a license file that could not be identified
+	if len(headerLicenses) > 0 {
+		delete(licenses, LicenseNoAssertion)
+	}
+	if len(licenses) == 0 {
+		licenses[LicenseNone] = true
+	}
+
+	var ids []string
+	for license := range licenses {
+		ids = append(ids, license)
+	}
+	sort.Strings(ids)
+	return ids, files, nil
+}
+
+// findLicenseHeaders returns the licenses of the SPDX-License-Identifier headers of the terraform files in the given
+// dir.
+func findLicenseHeaders(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var licenses []string
+	for _, file := range files {
+		license, err := findLicenseHeader(file)
+		if err != nil {
+			return nil, err
+		}
+		if license != "" {
+			licenses = append(licenses, license)
+		}
+	}
+	return licenses, nil
+}
+
+func findLicenseHeader(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(f)
+	for line := 0; line < licenseHeaderLines && scanner.Scan(); line++ {
+		if match := spdxIdentifierRegexp.FindStringSubmatch(scanner.Text()); match != nil {
+			return match[1], nil
+		}
+	}
+	return "", errors.WithStackTrace(scanner.Err())
+}
@@ -0,0 +1,35 @@
+package lint
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName         = "lint"
+	SubCommandProviders = "providers"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:                   CommandName,
+		Usage:                  "Lint the terraform code of the modules of the stack.",
+		DisallowUndefinedFlags: true,
+		Subcommands:            subCommands(opts),
+		Action: func(ctx *cli.Context) error {
+			return errors.WithStackTrace(MissingLinter{})
+		},
+	}
+}
+
+func subCommands(opts *options.TerragruntOptions) cli.Commands {
+	return cli.Commands{
+		&cli.Command{
+			Name:        SubCommandProviders,
+			Usage:       "Lint the required_providers of the modules of the stack.",
+			Description: "The command recursively finds the terragrunt modules in the current directory tree, and flags the providers their terraform code requires without a version constraint, with a floating constraint, i.e. without an upper bound such as >= 4.0, or from a source not on the approved_providers list.",
+			Action:      func(ctx *cli.Context) error { return RunProviders(ctx, opts.OptionsFromContext(ctx)) },
+		},
+	}
+}
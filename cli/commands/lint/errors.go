@@ -0,0 +1,28 @@
+package lint
+
+import (
+	"fmt"
+)
+
+// Custom error types
+
+type MissingLinter struct{}
+
+func (err MissingLinter) Error() string {
+	return fmt.Sprintf("Missing the linter to run, e.g. terragrunt %s %s.", CommandName, SubCommandProviders)
+}
+
+type ProviderLintIssues struct {
+	Modules int
+	Issues  int
+}
+
+func (err ProviderLintIssues) Error() string {
+	return fmt.Sprintf("Found %d provider issues in %d modules", err.Issues, err.Modules)
+}
+
+type InvalidApprovedProvider string
+
+func (source InvalidApprovedProvider) Error() string {
+	return fmt.Sprintf("Invalid approved provider %q: the source must be a valid pattern, e.g. hashicorp/aws or hashicorp/*.", string(source))
+}
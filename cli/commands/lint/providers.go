@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The hostnames of the public registries, which the sources of the providers default to.
+var defaultRegistryHostnames = []string{"registry.terraform.io", "registry.opentofu.org"}
+
+// RunProviders lints the providers required by the terraform code of the modules of the stack, and fails if any module
+// requires a provider without a version constraint, with a floating constraint, or from a source not approved by the
+// approved_providers attribute of its config.
+func RunProviders(ctx context.Context, opts *options.TerragruntOptions) error {
+	stack, err := configstack.FindStackInSubfolders(ctx, opts, nil)
+	if err != nil {
+		return err
+	}
+
+	modulesWithIssues := 0
+	issuesCount := 0
+	for _, module := range stack.Modules {
+		if module.FlagExcluded {
+			continue
+		}
+
+		// Lint the code of the module, before terragrunt generates any file into it
+		var issues []string
+		target := terraform.NewTarget(terraform.TargetPointDownloadSource, func(ctx context.Context, opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+			var err error
+			issues, err = lintModuleProviders(opts.WorkingDir, cfg)
+			return err
+		})
+		if err := terraform.RunWithTarget(ctx, module.TerragruntOptions.Clone(module.TerragruntOptions.TerragruntConfigPath), target); err != nil {
+			return err
+		}
+
+		if len(issues) > 0 {
+			modulesWithIssues++
+			issuesCount += len(issues)
+			opts.Logger.Warnf("The module %s:\n  - %s", module.Path, strings.Join(issues, "\n  - "))
+		}
+	}
+
+	if issuesCount > 0 {
+		return errors.WithStackTrace(ProviderLintIssues{Modules: modulesWithIssues, Issues: issuesCount})
+	}
+	opts.Logger.Infof("The providers of the %d modules have no issues", len(stack.Modules))
+	return nil
+}
+
+// lintModuleProviders returns the issues of the providers required by the terraform module in the given dir.
+func lintModuleProviders(dir string, cfg *config.TerragruntConfig) ([]string, error) {
+	// The files generated by the prior runs are not part of the code of the module
+	var generatedPaths []string
+	if cfg.ProviderMatrix != nil {
+		generatedPaths = append(generatedPaths, filepath.Join(dir, cfg.ProviderMatrix.GeneratedPath()))
+	}
+	if cfg.ProviderVersionOverrides != nil {
+		generatedPaths = append(generatedPaths, filepath.Join(dir, cfg.ProviderVersionOverrides.GeneratedPath()))
+	}
+
+	module, err := config.LoadTerraformModule(dir, generatedPaths...)
+	if err != nil {
+		return nil, err
+	}
+	return providerIssues(module, cfg.ApprovedProviders)
+}
+
+// providerIssues returns the issues of the providers required by the given module, sorted by provider.
+func providerIssues(module *tfconfig.Module, approvedProviders []string) ([]string, error) {
+	names := make([]string, 0, len(module.RequiredProviders))
+	for name := range module.RequiredProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var issues []string
+	for _, name := range names {
+		requirement := module.RequiredProviders[name]
+		source := normalizeProviderSource(name, requirement.Source)
+
+		if len(requirement.VersionConstraints) == 0 {
+			issues = append(issues, fmt.Sprintf("requires the provider %s without a version constraint", name))
+		} else {
+			constraint := strings.Join(requirement.VersionConstraints, ", ")
+			floating, err := isFloatingConstraint(constraint)
+			if err != nil {
+				issues = append(issues, fmt.Sprintf("requires the provider %s with the invalid version constraint %q", name, constraint))
+			} else if floating {
+				issues = append(issues, fmt.Sprintf("requires the provider %s with the floating version constraint %q, without an upper bound", name, constraint))
+			}
+		}
+
+		if len(approvedProviders) > 0 {
+			approved, err := isApprovedProvider(source, approvedProviders)
+			if err != nil {
+				return nil, err
+			}
+			if !approved {
+				issues = append(issues, fmt.Sprintf("requires the provider %s from %s, which is not approved", name, source))
+			}
+		}
+	}
+	return issues, nil
+}
+
+// isFloatingConstraint returns true if the given version constraint has no upper bound, e.g. ">= 4.0", so that any
+// future major version of the provider satisfies it.
+func isFloatingConstraint(constraint string) (bool, error) {
+	constraints, err := version.NewConstraint(constraint)
+	if err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+
+	for _, c := range constraints {
+		op := strings.TrimSpace(c.String())
+		if strings.HasPrefix(op, "!=") || strings.HasPrefix(op, ">") {
+			continue
+		}
+		// The constraints "<", "<=", "~>", "=" and the exact versions are bounded
+		return false, nil
+	}
+	return true, nil
+}
+
+// isApprovedProvider returns true if the given provider source matches one of the approved sources, which can contain
+// wildcards, e.g. hashicorp/*.
+func isApprovedProvider(source string, approvedProviders []string) (bool, error) {
+	for _, approved := range approvedProviders {
+		pattern := normalizeProviderSource("", approved)
+		matched, err := path.Match(pattern, source)
+		if err != nil {
+			return false, errors.WithStackTrace(InvalidApprovedProvider(approved))
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// normalizeProviderSource returns the given provider source lowercased and without the hostname of the public
+// registries, e.g. hashicorp/aws for registry.terraform.io/HashiCorp/aws. The source of the providers required without
+// a source defaults to hashicorp/<name>, as in terraform.
+func normalizeProviderSource(name string, source string) string {
+	if source == "" {
+		source = "hashicorp/" + name
+	}
+	source = strings.ToLower(source)
+	for _, hostname := range defaultRegistryHostnames {
+		source = strings.TrimPrefix(source, hostname+"/")
+	}
+	return source
+}
@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintModuleProviders(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+terraform {
+  required_providers {
+    aws = {
+      source  = "registry.terraform.io/hashicorp/aws"
+      version = ">= 4.0"
+    }
+    random = {
+      source  = "hashicorp/random"
+      version = ">= 3.0, < 4.0"
+    }
+    cloudflare = {
+      source  = "cloudflare/cloudflare"
+      version = "~> 4.0"
+    }
+  }
+}
+
+resource "null_resource" "example" {}
+`), 0644))
+
+	cfg := &config.TerragruntConfig{ApprovedProviders: []string{"hashicorp/*"}}
+	issues, err := lintModuleProviders(dir, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`requires the provider aws with the floating version constraint ">= 4.0", without an upper bound`,
+		"requires the provider cloudflare from cloudflare/cloudflare, which is not approved",
+		"requires the provider null without a version constraint",
+	}, issues)
+
+	// Without an approved list, only the version constraints are linted
+	issues, err = lintModuleProviders(dir, &config.TerragruntConfig{})
+	require.NoError(t, err)
+	assert.Len(t, issues, 2)
+}
+
+func TestIsFloatingConstraint(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		constraint string
+		expected   bool
+	}{
+		{">= 4.0", true},
+		{"> 4.0, != 4.2.0", true},
+		{"~> 5.0", false},
+		{">= 4.0, < 5.0", false},
+		{"5.40.0", false},
+		{"= 5.40.0", false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.constraint, func(t *testing.T) {
+			t.Parallel()
+
+			floating, err := isFloatingConstraint(testCase.constraint)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, floating)
+		})
+	}
+}
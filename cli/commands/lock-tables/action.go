@@ -0,0 +1,35 @@
+package locktables
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// Run resolves the DynamoDB lock table of every module in the stack and prints the report to stdout
+func Run(ctx context.Context, opts *options.TerragruntOptions) error {
+	stack, err := configstack.FindStackInSubfolders(ctx, opts, nil)
+	if err != nil {
+		return err
+	}
+
+	usage, err := stack.FindLockTableUsage(opts.Logger)
+	if err != nil {
+		return err
+	}
+
+	js, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if _, err := fmt.Fprintf(opts.Writer, "%s\n", js); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
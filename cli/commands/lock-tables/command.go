@@ -0,0 +1,19 @@
+package locktables
+
+import (
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = configstack.LockTablesCommand
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:   CommandName,
+		Usage:  "Output which modules of the stack use which DynamoDB lock table in JSON, failing if modules sharing a state bucket resolve to different tables.",
+		Action: func(ctx *cli.Context) error { return Run(ctx, opts.OptionsFromContext(ctx)) },
+	}
+}
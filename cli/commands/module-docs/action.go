@@ -0,0 +1,294 @@
+// `module-docs` command renders the documentation of the variables and outputs of the terraform module, in the style of
+// terraform-docs, along with the inputs terragrunt sets for the variables, and flags the variables and outputs without
+// a description, the variables the module never uses, and the inputs terragrunt sets that are not variables of the
+// module.
+
+package moduledocs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/cli/commands/docs"
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	validateinputs "github.com/gruntwork-io/terragrunt/cli/commands/validate-inputs"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// ModuleDocs is the documentation of the terraform module of a terragrunt module.
+type ModuleDocs struct {
+	Name      string
+	Source    string
+	Variables []*VariableDocs
+	Outputs   []*OutputDocs
+	// The issues found in the module and in the inputs terragrunt sets.
+	Findings []string
+}
+
+// VariableDocs is the documentation of a variable of the module, with the expression terragrunt sets it to.
+type VariableDocs struct {
+	Name        string
+	Description string
+	Type        string
+	Default     string
+	Required    bool
+	// The expression of the input terragrunt sets the variable to, if set in the inputs.
+	Input string
+	// True if terragrunt sets the variable, in the inputs or otherwise, e.g. in a var file.
+	Provided bool
+}
+
+// OutputDocs is the documentation of an output of the module.
+type OutputDocs struct {
+	Name        string
+	Description string
+	Sensitive   bool
+}
+
+func Run(ctx context.Context, opts *options.TerragruntOptions) error {
+	target := terraform.NewTarget(terraform.TargetPointGenerateConfig, runModuleDocs)
+
+	return terraform.RunWithTarget(ctx, opts, target)
+}
+
+func runModuleDocs(ctx context.Context, opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+	moduleDocs, err := newModuleDocs(opts, cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, finding := range moduleDocs.Findings {
+		opts.Logger.Warnf("%s: %s", moduleDocs.Name, finding)
+	}
+
+	if opts.ModuleDocsOutFile == "" {
+		return renderMarkdown(opts.Writer, moduleDocs)
+	}
+
+	outFile := util.JoinPath(filepath.Dir(opts.TerragruntConfigPath), opts.ModuleDocsOutFile)
+	file, err := os.Create(outFile)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	if err := renderMarkdown(file, moduleDocs); err != nil {
+		return err
+	}
+	opts.Logger.Infof("Wrote the documentation of the module to %s", outFile)
+	return nil
+}
+
+func newModuleDocs(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) (*ModuleDocs, error) {
+	module, diags := tfconfig.LoadModule(opts.WorkingDir)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+
+	references, err := variableReferences(opts.WorkingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	providedInputs, err := validateinputs.GetDefinedTerragruntInputs(opts, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := docs.ConfigInputs(opts.TerragruntConfigPath, cfg.ProcessedIncludes)
+	if err != nil {
+		return nil, err
+	}
+	inputExpressions := map[string]string{}
+	for _, input := range inputs {
+		inputExpressions[input.Name] = input.Value
+	}
+
+	source, err := config.GetTerraformSourceUrl(opts, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	moduleDocs := &ModuleDocs{
+		Name:   moduleName(opts),
+		Source: source,
+	}
+
+	for _, variable := range module.Variables {
+		variableDocs := &VariableDocs{
+			Name:        variable.Name,
+			Description: variable.Description,
+			Type:        variable.Type,
+			Required:    variable.Required,
+			Input:       inputExpressions[variable.Name],
+			Provided:    util.ListContainsElement(providedInputs, variable.Name),
+		}
+		if !variable.Required {
+			defaultValue, err := json.Marshal(variable.Default)
+			if err != nil {
+				return nil, errors.WithStackTrace(err)
+			}
+			variableDocs.Default = string(defaultValue)
+		}
+		moduleDocs.Variables = append(moduleDocs.Variables, variableDocs)
+	}
+	sort.Slice(moduleDocs.Variables, func(i, j int) bool {
+		return moduleDocs.Variables[i].Name < moduleDocs.Variables[j].Name
+	})
+
+	for _, output := range module.Outputs {
+		moduleDocs.Outputs = append(moduleDocs.Outputs, &OutputDocs{
+			Name:        output.Name,
+			Description: output.Description,
+			Sensitive:   output.Sensitive,
+		})
+	}
+	sort.Slice(moduleDocs.Outputs, func(i, j int) bool {
+		return moduleDocs.Outputs[i].Name < moduleDocs.Outputs[j].Name
+	})
+
+	for _, variable := range moduleDocs.Variables {
+		if variable.Description == "" {
+			moduleDocs.Findings = append(moduleDocs.Findings, fmt.Sprintf("The variable %s has no description", variable.Name))
+		}
+		if !references[variable.Name] {
+			moduleDocs.Findings = append(moduleDocs.Findings, fmt.Sprintf("The variable %s is not used by the module", variable.Name))
+		}
+		if variable.Required && !variable.Provided {
+			moduleDocs.Findings = append(moduleDocs.Findings, fmt.Sprintf("The required variable %s is not set by terragrunt", variable.Name))
+		}
+	}
+	for _, output := range moduleDocs.Outputs {
+		if output.Description == "" {
+			moduleDocs.Findings = append(moduleDocs.Findings, fmt.Sprintf("The output %s has no description", output.Name))
+		}
+	}
+	sort.Strings(providedInputs)
+	for _, input := range providedInputs {
+		if _, ok := module.Variables[input]; !ok {
+			moduleDocs.Findings = append(moduleDocs.Findings, fmt.Sprintf("The input %s is set by terragrunt, but is not a variable of the module", input))
+		}
+	}
+
+	return moduleDocs, nil
+}
+
+// moduleName returns the dir of the terragrunt module relative to the current dir, e.g. the dir run-all runs in.
+func moduleName(opts *options.TerragruntOptions) string {
+	moduleDir := filepath.Dir(opts.TerragruntConfigPath)
+	if currentDir, err := os.Getwd(); err == nil {
+		if relPath, err := util.GetPathRelativeTo(moduleDir, currentDir); err == nil && relPath != "." {
+			return relPath
+		}
+	}
+	return filepath.Base(moduleDir)
+}
+
+// variableReferences returns the names of the variables referenced in the terraform code in the given dir, e.g. as
+// `var.name`.
+func variableReferences(dir string) (map[string]bool, error) {
+	references := map[string]bool{}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		hclFile, diags := hclsyntax.ParseConfig(content, file, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+
+		body, ok := hclFile.Body.(*hclsyntax.Body)
+		if !ok {
+			continue
+		}
+		diags = hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+			expr, ok := node.(*hclsyntax.ScopeTraversalExpr)
+			if !ok || len(expr.Traversal) < 2 || expr.Traversal.RootName() != "var" {
+				return nil
+			}
+			if attr, ok := expr.Traversal[1].(hcl.TraverseAttr); ok {
+				references[attr.Name] = true
+			}
+			return nil
+		})
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+	}
+	return references, nil
+}
+
+func renderMarkdown(writer io.Writer, moduleDocs *ModuleDocs) error {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "# %s\n\n", moduleDocs.Name)
+	if moduleDocs.Source != "" {
+		fmt.Fprintf(&out, "**Source**: %s\n\n", docs.MarkdownCode(moduleDocs.Source))
+	}
+
+	out.WriteString("## Inputs\n\n")
+	if len(moduleDocs.Variables) == 0 {
+		out.WriteString("No inputs.\n\n")
+	} else {
+		out.WriteString("| Name | Description | Type | Default | Required | Set by terragrunt |\n| --- | --- | --- | --- | --- | --- |\n")
+		for _, variable := range moduleDocs.Variables {
+			required := "no"
+			if variable.Required {
+				required = "yes"
+			}
+			input := docs.MarkdownCode(variable.Input)
+			if variable.Input == "" && variable.Provided {
+				// Set outside the inputs, e.g. in a var file or with an env var
+				input = "yes"
+			}
+			fmt.Fprintf(&out, "| %s | %s | %s | %s | %s | %s |\n", docs.MarkdownCode(variable.Name), docs.MarkdownCell(variable.Description),
+				docs.MarkdownCode(variable.Type), docs.MarkdownCode(variable.Default), required, input)
+		}
+		out.WriteString("\n")
+	}
+
+	out.WriteString("## Outputs\n\n")
+	if len(moduleDocs.Outputs) == 0 {
+		out.WriteString("No outputs.\n\n")
+	} else {
+		out.WriteString("| Name | Description | Sensitive |\n| --- | --- | --- |\n")
+		for _, output := range moduleDocs.Outputs {
+			sensitive := "no"
+			if output.Sensitive {
+				sensitive = "yes"
+			}
+			fmt.Fprintf(&out, "| %s | %s | %s |\n", docs.MarkdownCode(output.Name), docs.MarkdownCell(output.Description), sensitive)
+		}
+		out.WriteString("\n")
+	}
+
+	if len(moduleDocs.Findings) > 0 {
+		out.WriteString("## Findings\n\n")
+		for _, finding := range moduleDocs.Findings {
+			fmt.Fprintf(&out, "- %s.\n", finding)
+		}
+		out.WriteString("\n")
+	}
+
+	_, err := io.WriteString(writer, strings.TrimRight(out.String(), "\n")+"\n")
+	return errors.WithStackTrace(err)
+}
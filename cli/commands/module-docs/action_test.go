@@ -0,0 +1,96 @@
+package moduledocs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleDocs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+variable "name" {
+  description = "The name of the VPC."
+  type        = string
+}
+
+variable "cidr" {
+  type    = string
+  default = "10.0.0.0/16"
+}
+
+variable "unused" {
+  description = "Not used."
+  default     = null
+}
+
+resource "null_resource" "this" {
+  triggers = {
+    name = "${var.name}-${var.cidr}"
+  }
+}
+
+output "id" {
+  value = null_resource.this.id
+}
+
+output "name" {
+  description = "The name of the VPC."
+  value       = var.name
+  sensitive   = true
+}
+`), 0644))
+	configPath := filepath.Join(dir, "terragrunt.hcl")
+	require.NoError(t, os.WriteFile(configPath, []byte("inputs = {\n  name    = local.name\n  subnets = []\n}\n"), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	opts.WorkingDir = dir
+	opts.Env = map[string]string{"TF_VAR_cidr": "10.1.0.0/16"}
+	cfg := &config.TerragruntConfig{Inputs: map[string]interface{}{"name": "vpc", "subnets": []interface{}{}}}
+
+	moduleDocs, err := newModuleDocs(opts, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"The variable cidr has no description",
+		"The variable unused is not used by the module",
+		"The output id has no description",
+		"The input subnets is set by terragrunt, but is not a variable of the module",
+	}, moduleDocs.Findings)
+
+	moduleDocs.Name = "vpc"
+	out := bytes.Buffer{}
+	require.NoError(t, renderMarkdown(&out, moduleDocs))
+	assert.Equal(t, `# vpc
+
+## Inputs
+
+| Name | Description | Type | Default | Required | Set by terragrunt |
+| --- | --- | --- | --- | --- | --- |
+| `+"`cidr` | - | `string` | `\"10.0.0.0/16\"` | no | yes |"+`
+| `+"`name` | The name of the VPC. | `string` | - | yes | `local.name` |"+`
+| `+"`unused` | Not used. | - | `null` | no | - |"+`
+
+## Outputs
+
+| Name | Description | Sensitive |
+| --- | --- | --- |
+| `+"`id` | - | no |"+`
+| `+"`name` | The name of the VPC. | yes |"+`
+
+## Findings
+
+- The variable cidr has no description.
+- The variable unused is not used by the module.
+- The output id has no description.
+- The input subnets is set by terragrunt, but is not a variable of the module.
+`, out.String())
+}
@@ -0,0 +1,32 @@
+package moduledocs
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "module-docs"
+
+	FlagNameTerragruntModuleDocsOut = "terragrunt-module-docs-out"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntModuleDocsOut,
+			Destination: &opts.ModuleDocsOutFile,
+			EnvVar:      "TERRAGRUNT_MODULE_DOCS_OUT",
+			Usage:       "The file, in the dir of the module, to write the documentation of the module to, e.g. README.md, instead of stdout.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:   CommandName,
+		Usage:  "Render the documentation of the variables and outputs of the terraform module, with the inputs terragrunt sets, and flag the undocumented and unused ones.",
+		Flags:  NewFlags(opts).Sort(),
+		Action: func(ctx *cli.Context) error { return Run(ctx, opts.OptionsFromContext(ctx)) },
+	}
+}
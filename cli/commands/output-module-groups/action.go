@@ -14,7 +14,18 @@ func Run(ctx context.Context, opts *options.TerragruntOptions) error {
 		return err
 	}
 
-	js, err := stack.JsonModuleDeployOrder(opts.TerraformCommand)
+	var js string
+	if opts.ModuleGroupsDetails {
+		durations := configstack.ModuleDurations{}
+		if opts.ModuleDurationsFile != "" {
+			if durations, err = configstack.ReadModuleDurations(opts.ModuleDurationsFile); err != nil {
+				return err
+			}
+		}
+		js, err = stack.JsonModuleDeployOrderDetails(opts.TerraformCommand, durations)
+	} else {
+		js, err = stack.JsonModuleDeployOrder(opts.TerraformCommand)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,312 @@
+package promote
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Run copies the versions the modules in the `from` environment tree are pinned to, i.e. the `ref` or the `version` of
+// their terraform source, to the same modules in the `to` environment tree, printing the diff of the configs. The
+// versions are taken from the configs of the modules, or from the snapshot manifest of a run-all apply of the `from`
+// environment if one is given. The configs are modified in place unless it is a dry run.
+func Run(ctx context.Context, opts *options.TerragruntOptions) error {
+	fromDir, err := environmentDir(opts, opts.PromoteFrom, FlagNameFrom)
+	if err != nil {
+		return err
+	}
+	toDir, err := environmentDir(opts, opts.PromoteTo, FlagNameTo)
+	if err != nil {
+		return err
+	}
+	if fromDir == toDir {
+		return errors.WithStackTrace(SameEnvironments(opts.PromoteFrom))
+	}
+
+	var manifest *configstack.SnapshotManifest
+	if opts.PromoteSnapshot != "" {
+		if manifest, err = configstack.ReadSnapshotManifest(util.JoinPath(opts.WorkingDir, opts.PromoteSnapshot)); err != nil {
+			return err
+		}
+	}
+
+	configFiles, err := config.FindConfigFilesInPath(fromDir, opts)
+	if err != nil {
+		return err
+	}
+
+	promoted := 0
+	for _, fromFile := range configFiles {
+		relPath, err := filepath.Rel(fromDir, fromFile)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		toFile := filepath.Join(toDir, relPath)
+		if !util.FileExists(toFile) {
+			opts.Logger.Debugf("Not promoting %s, as there is no %s", fromFile, toFile)
+			continue
+		}
+
+		source, err := pinnedSource(ctx, opts, fromDir, fromFile, manifest)
+		if err != nil {
+			return err
+		}
+		param, version := terraform.SourceVersion(source)
+		if param == "" {
+			opts.Logger.Debugf("Not promoting %s, as its terraform source is not pinned to a version", fromFile)
+			continue
+		}
+
+		contents, err := os.ReadFile(toFile)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		newContents, skipReason, err := promoteSourcePin(contents, toFile, source, param, version)
+		if err != nil {
+			return err
+		}
+		if skipReason != "" {
+			opts.Logger.Warnf("Not promoting %s to %s, as %s", fromFile, toFile, skipReason)
+			continue
+		}
+		if newContents == nil {
+			continue
+		}
+
+		displayPath := toFile
+		if relPath, err := util.GetPathRelativeTo(toFile, opts.WorkingDir); err == nil {
+			displayPath = relPath
+		}
+		if _, err := fmt.Fprint(opts.Writer, linesDiff(contents, newContents, displayPath)); err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		if !opts.PromoteDryRun {
+			if err := os.WriteFile(toFile, newContents, 0644); err != nil {
+				return errors.WithStackTrace(err)
+			}
+		}
+		promoted++
+	}
+
+	switch {
+	case promoted == 0:
+		opts.Logger.Infof("Nothing to promote from %s to %s", opts.PromoteFrom, opts.PromoteTo)
+	case opts.PromoteDryRun:
+		opts.Logger.Infof("Would promote %d modules from %s to %s", promoted, opts.PromoteFrom, opts.PromoteTo)
+	default:
+		opts.Logger.Infof("Promoted %d modules from %s to %s", promoted, opts.PromoteFrom, opts.PromoteTo)
+	}
+	return nil
+}
+
+func environmentDir(opts *options.TerragruntOptions, dir, flagName string) (string, error) {
+	if dir == "" {
+		return "", errors.WithStackTrace(MissingEnvironmentFlag(flagName))
+	}
+
+	dir = filepath.Clean(util.JoinPath(opts.WorkingDir, dir))
+	if !util.IsDir(dir) {
+		return "", errors.WithStackTrace(EnvironmentDirNotFound(dir))
+	}
+	return dir, nil
+}
+
+// pinnedSource returns the terraform source of the module with the given config, from the snapshot manifest if one is
+// given, and otherwise from the config. The source is empty if the module has none or was not applied in the run of the
+// snapshot.
+func pinnedSource(ctx context.Context, opts *options.TerragruntOptions, fromDir, configPath string, manifest *configstack.SnapshotManifest) (string, error) {
+	if manifest != nil {
+		module := findSnapshotModule(opts, manifest, fromDir, filepath.Dir(configPath))
+		if module == nil {
+			opts.Logger.Debugf("The module %s was not applied in the snapshot run %s", filepath.Dir(configPath), manifest.RunID)
+			return "", nil
+		}
+		return module.Source, nil
+	}
+
+	moduleOpts := opts.Clone(configPath)
+	parsingCtx := config.NewParsingContext(ctx, moduleOpts).WithDecodeList(config.TerraformSource)
+	terragruntConfig, err := config.PartialParseConfigFile(parsingCtx, configPath, nil)
+	if err != nil {
+		return "", err
+	}
+	if terragruntConfig.Terraform == nil || terragruntConfig.Terraform.Source == nil {
+		return "", nil
+	}
+	return *terragruntConfig.Terraform.Source, nil
+}
+
+// findSnapshotModule returns the module of the snapshot manifest in the given dir. As the snapshot run may have been in
+// another checkout, e.g. in CI, the dir is also matched relative to the environment tree and to the working dir, in case
+// the run was in the same dir of the other checkout.
+func findSnapshotModule(opts *options.TerragruntOptions, manifest *configstack.SnapshotManifest, fromDir, moduleDir string) *configstack.SnapshotModule {
+	if module := manifest.FindModule(moduleDir); module != nil {
+		return module
+	}
+
+	for _, baseDir := range []string{fromDir, opts.WorkingDir} {
+		relPath, err := util.GetPathRelativeTo(moduleDir, baseDir)
+		if err != nil {
+			continue
+		}
+		for _, module := range manifest.Modules {
+			if module.Path == relPath {
+				return module
+			}
+		}
+	}
+	return nil
+}
+
+// promoteSourcePin pins the terraform source in the given config to the given version, either in the source itself or
+// in the local the version is interpolated from, e.g. `ref=${local.version}`. It returns nil contents if the source is
+// already pinned to the version, and the reason if the source cannot be promoted, e.g. if it is not in the config.
+func promoteSourcePin(contents []byte, configPath, fromSource, param, version string) ([]byte, string, error) {
+	file, diags := hclwrite.ParseConfig(contents, configPath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, "", errors.WithStackTrace(diags)
+	}
+
+	terraformBlock := file.Body().FirstMatchingBlock("terraform", nil)
+	if terraformBlock == nil || terraformBlock.Body().GetAttribute("source") == nil {
+		return nil, "its terraform source is not set in the config", nil
+	}
+	tokens := terraformBlock.Body().GetAttribute("source").Expr().BuildTokens(nil)
+
+	// The version is only copied between the same modules, as far as it can be told without evaluating the source
+	if len(tokens) == 3 && tokens[1].Type == hclsyntax.TokenQuotedLit && unpinnedSource(string(tokens[1].Bytes)) != unpinnedSource(fromSource) {
+		return nil, fmt.Sprintf("its terraform source %s is not the module %s", tokens[1].Bytes, unpinnedSource(fromSource)), nil
+	}
+
+	for i, token := range tokens {
+		if token.Type != hclsyntax.TokenQuotedLit {
+			continue
+		}
+		literal := string(token.Bytes)
+		start := pinValueIndex(literal, param)
+		if start < 0 {
+			continue
+		}
+		end := strings.IndexByte(literal[start:], '&')
+		if end < 0 {
+			end = len(literal)
+		} else {
+			end += start
+		}
+
+		if start == len(literal) && i+1 < len(tokens) && tokens[i+1].Type == hclsyntax.TokenTemplateInterp {
+			return promoteLocalPin(file, tokens[i+1:], version)
+		}
+
+		newLiteral := literal[:start] + escapeTemplateLiteral(version) + literal[end:]
+		if newLiteral == literal {
+			return nil, "", nil
+		}
+		token.Bytes = []byte(newLiteral)
+		terraformBlock.Body().SetAttributeRaw("source", tokens)
+		return file.Bytes(), "", nil
+	}
+
+	return nil, fmt.Sprintf("its terraform source is not pinned with the %s param", param), nil
+}
+
+// promoteLocalPin sets the local the version is interpolated from, with the given tokens starting with the
+// interpolation, to the given version.
+func promoteLocalPin(file *hclwrite.File, tokens hclwrite.Tokens, version string) ([]byte, string, error) {
+	if len(tokens) < 5 || tokens[1].Type != hclsyntax.TokenIdent || string(tokens[1].Bytes) != "local" ||
+		tokens[2].Type != hclsyntax.TokenDot || tokens[3].Type != hclsyntax.TokenIdent || tokens[4].Type != hclsyntax.TokenTemplateSeqEnd {
+		return nil, "the version of its terraform source is interpolated from an expression other than a local", nil
+	}
+	name := string(tokens[3].Bytes)
+
+	for _, block := range file.Body().Blocks() {
+		if block.Type() != "locals" {
+			continue
+		}
+		attr := block.Body().GetAttribute(name)
+		if attr == nil {
+			continue
+		}
+
+		localTokens := attr.Expr().BuildTokens(nil)
+		switch {
+		case len(localTokens) == 2 && localTokens[0].Type == hclsyntax.TokenOQuote && localTokens[1].Type == hclsyntax.TokenCQuote:
+		case len(localTokens) == 3 && localTokens[1].Type == hclsyntax.TokenQuotedLit:
+			if string(localTokens[1].Bytes) == escapeTemplateLiteral(version) {
+				return nil, "", nil
+			}
+		default:
+			return nil, fmt.Sprintf("the local.%s the version of its terraform source is interpolated from is not a string", name), nil
+		}
+
+		block.Body().SetAttributeValue(name, cty.StringVal(version))
+		return file.Bytes(), "", nil
+	}
+
+	return nil, fmt.Sprintf("the local.%s the version of its terraform source is interpolated from is not in the config", name), nil
+}
+
+// pinValueIndex returns the index of the value of the given query param in the given part of a terraform source, or -1
+// if the param is not in it.
+func pinValueIndex(literal, param string) int {
+	for _, separator := range []string{"?", "&"} {
+		if index := strings.Index(literal, separator+param+"="); index >= 0 {
+			return index + len(separator+param+"=")
+		}
+	}
+	return -1
+}
+
+// unpinnedSource returns the given terraform source without the query params it is pinned with.
+func unpinnedSource(source string) string {
+	index := strings.LastIndex(source, "?")
+	if index < 0 {
+		return source
+	}
+
+	var params []string
+	for _, param := range strings.Split(source[index+1:], "&") {
+		name, _, _ := strings.Cut(param, "=")
+		if !util.ListContainsElement(terraform.SourceVersionParams, name) {
+			params = append(params, param)
+		}
+	}
+	if len(params) == 0 {
+		return source[:index]
+	}
+	return source[:index] + "?" + strings.Join(params, "&")
+}
+
+func escapeTemplateLiteral(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", "$${", "%{", "%%{").Replace(value)
+}
+
+// linesDiff returns the diff of the lines of the given contents of a config, whose lines are only ever modified by the
+// promotion, never added or removed.
+func linesDiff(oldContents, newContents []byte, path string) string {
+	oldLines := strings.Split(string(oldContents), "\n")
+	newLines := strings.Split(string(newContents), "\n")
+
+	var diff strings.Builder
+	fmt.Fprintf(&diff, "--- %s\n+++ %s\n", path, path)
+	for i := 0; i < len(oldLines) && i < len(newLines); i++ {
+		if oldLines[i] != newLines[i] {
+			fmt.Fprintf(&diff, "@@ -%d +%d @@\n-%s\n+%s\n", i+1, i+1, oldLines[i], newLines[i])
+		}
+	}
+	return diff.String()
+}
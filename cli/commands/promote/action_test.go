@@ -0,0 +1,180 @@
+package promote
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoteSourcePin(t *testing.T) {
+	t.Parallel()
+
+	fromSource := "git::https://example.com/modules.git//vpc?ref=v1.2.0"
+
+	testCases := []struct {
+		name             string
+		config           string
+		expectedConfig   string
+		expectSkipReason bool
+	}{
+		{
+			name:           "literal source",
+			config:         "terraform {\n  source = \"git::https://example.com/modules.git//vpc?ref=v1.0.0\"\n}\n",
+			expectedConfig: "terraform {\n  source = \"git::https://example.com/modules.git//vpc?ref=v1.2.0\"\n}\n",
+		},
+		{
+			name:           "interpolated source",
+			config:         "terraform {\n  source = \"${local.repo}//vpc?ref=v1.0.0&depth=1\"\n}\n",
+			expectedConfig: "terraform {\n  source = \"${local.repo}//vpc?ref=v1.2.0&depth=1\"\n}\n",
+		},
+		{
+			name:           "version in local",
+			config:         "locals {\n  version = \"v1.0.0\" # pinned\n}\n\nterraform {\n  source = \"git::https://example.com/modules.git//vpc?ref=${local.version}\"\n}\n",
+			expectedConfig: "locals {\n  version = \"v1.2.0\" # pinned\n}\n\nterraform {\n  source = \"git::https://example.com/modules.git//vpc?ref=${local.version}\"\n}\n",
+		},
+		{
+			name:   "already promoted",
+			config: "terraform {\n  source = \"git::https://example.com/modules.git//vpc?ref=v1.2.0\"\n}\n",
+		},
+		{
+			name:             "different module",
+			config:           "terraform {\n  source = \"git::https://example.com/modules.git//db?ref=v1.0.0\"\n}\n",
+			expectSkipReason: true,
+		},
+		{
+			name:             "source in include",
+			config:           "include \"envcommon\" {\n  path = \"${get_repo_root()}/_envcommon/vpc.hcl\"\n}\n",
+			expectSkipReason: true,
+		},
+		{
+			name:             "version in expression",
+			config:           "terraform {\n  source = \"git::https://example.com/modules.git//vpc?ref=${include.envcommon.locals.version}\"\n}\n",
+			expectSkipReason: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			param, version := terraform.SourceVersion(fromSource)
+			newContents, skipReason, err := promoteSourcePin([]byte(testCase.config), "terragrunt.hcl", fromSource, param, version)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectSkipReason, skipReason != "", skipReason)
+			if testCase.expectedConfig == "" {
+				assert.Nil(t, newContents)
+			} else {
+				assert.Equal(t, testCase.expectedConfig, string(newContents))
+			}
+		})
+	}
+}
+
+func TestRunPromote(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	writeConfig := func(dir, source string) string {
+		configPath := filepath.Join(rootDir, dir, "terragrunt.hcl")
+		require.NoError(t, os.MkdirAll(filepath.Dir(configPath), os.ModePerm))
+		require.NoError(t, os.WriteFile(configPath, []byte("terraform {\n  source = \""+source+"\"\n}\n"), 0644))
+		return configPath
+	}
+
+	writeConfig("env/dev/vpc", "git::https://example.com/modules.git//vpc?ref=v1.2.0")
+	writeConfig("env/dev/app", "tfr:///example/app/aws?version=2.0.0")
+	writeConfig("env/dev/db", "git::https://example.com/modules.git//db?ref=v3.0.0")
+	stagingVpc := writeConfig("env/staging/vpc", "git::https://example.com/modules.git//vpc?ref=v1.0.0")
+	stagingApp := writeConfig("env/staging/app", "tfr:///example/app/aws?version=1.0.0")
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = rootDir
+	opts.PromoteFrom = "env/dev"
+	opts.PromoteTo = "env/staging"
+	opts.PromoteDryRun = true
+	stdout := bytes.Buffer{}
+	opts.Writer = &stdout
+
+	require.NoError(t, Run(context.Background(), opts))
+	assert.Contains(t, stdout.String(), "--- env/staging/vpc/terragrunt.hcl\n+++ env/staging/vpc/terragrunt.hcl\n@@ -2 +2 @@\n-  source = \"git::https://example.com/modules.git//vpc?ref=v1.0.0\"\n+  source = \"git::https://example.com/modules.git//vpc?ref=v1.2.0\"\n")
+	assert.Contains(t, stdout.String(), "+  source = \"tfr:///example/app/aws?version=2.0.0\"\n")
+
+	contents, err := os.ReadFile(stagingVpc)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "ref=v1.0.0")
+
+	opts.PromoteDryRun = false
+	require.NoError(t, Run(context.Background(), opts))
+
+	contents, err = os.ReadFile(stagingVpc)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "ref=v1.2.0")
+	contents, err = os.ReadFile(stagingApp)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "version=2.0.0")
+	assert.NoFileExists(t, filepath.Join(rootDir, "env/staging/db/terragrunt.hcl"))
+}
+
+func TestRunPromoteFromSnapshot(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	for _, dir := range []string{"env/dev/vpc", "env/staging/vpc"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, dir), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, dir, "terragrunt.hcl"), []byte("terraform {\n  source = \"git::https://example.com/modules.git//vpc?ref=main\"\n}\n"), 0644))
+	}
+	manifestFile := filepath.Join(rootDir, "manifest.json")
+	require.NoError(t, os.WriteFile(manifestFile, []byte(`{
+  "run_id": "run-1",
+  "working_dir": "`+filepath.ToSlash(filepath.Join(rootDir, "env", "dev"))+`",
+  "modules": [{"path": "vpc", "source": "git::https://example.com/modules.git//vpc?ref=4a5b6c7"}]
+}`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = rootDir
+	opts.PromoteFrom = "env/dev"
+	opts.PromoteTo = "env/staging"
+	opts.PromoteSnapshot = "manifest.json"
+	opts.Writer = &bytes.Buffer{}
+
+	require.NoError(t, Run(context.Background(), opts))
+
+	contents, err := os.ReadFile(filepath.Join(rootDir, "env/staging/vpc/terragrunt.hcl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "ref=4a5b6c7")
+
+	// The snapshot of a run in another checkout
+	require.NoError(t, os.WriteFile(manifestFile, []byte(`{
+  "run_id": "run-2",
+  "working_dir": "/ci/checkout",
+  "modules": [{"path": "env/dev/vpc", "source": "git::https://example.com/modules.git//vpc?ref=8d9e0f1"}]
+}`), 0644))
+
+	require.NoError(t, Run(context.Background(), opts))
+
+	contents, err = os.ReadFile(filepath.Join(rootDir, "env/staging/vpc/terragrunt.hcl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "ref=8d9e0f1")
+}
+
+func TestRunPromoteRequiresEnvironments(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.PromoteTo = "env/staging"
+
+	err = Run(context.Background(), opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--from")
+}
@@ -0,0 +1,50 @@
+package promote
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "promote"
+
+	FlagNameFrom     = "from"
+	FlagNameTo       = "to"
+	FlagNameSnapshot = "snapshot"
+	FlagNameDryRun   = "dry-run"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameFrom,
+			Destination: &opts.PromoteFrom,
+			Usage:       "The environment tree to copy the pinned module versions from, e.g. env/dev.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTo,
+			Destination: &opts.PromoteTo,
+			Usage:       "The environment tree to copy the pinned module versions to, e.g. env/staging.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameSnapshot,
+			Destination: &opts.PromoteSnapshot,
+			Usage:       "The snapshot manifest of a run-all apply of the source environment, or the dir of its run, to take the pinned module versions from instead of the configs.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameDryRun,
+			Destination: &opts.PromoteDryRun,
+			Usage:       "Only show the diff, without modifying the configs.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:                   CommandName,
+		Usage:                  "Promote the pinned module versions from one environment tree to another.",
+		DisallowUndefinedFlags: true,
+		Flags:                  NewFlags(opts).Sort(),
+		Action:                 func(ctx *cli.Context) error { return Run(ctx, opts.OptionsFromContext(ctx)) },
+	}
+}
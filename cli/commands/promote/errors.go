@@ -0,0 +1,23 @@
+package promote
+
+import "fmt"
+
+// Custom error types
+
+type MissingEnvironmentFlag string
+
+func (flagName MissingEnvironmentFlag) Error() string {
+	return fmt.Sprintf("The %s command requires the --%s flag, e.g. `%s --%s env/dev --%s env/staging`", CommandName, string(flagName), CommandName, FlagNameFrom, FlagNameTo)
+}
+
+type EnvironmentDirNotFound string
+
+func (dir EnvironmentDirNotFound) Error() string {
+	return fmt.Sprintf("The environment tree %s does not exist", string(dir))
+}
+
+type SameEnvironments string
+
+func (dir SameEnvironments) Error() string {
+	return fmt.Sprintf("Cannot promote the environment tree %s to itself", string(dir))
+}
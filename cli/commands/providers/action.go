@@ -0,0 +1,219 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	tf "github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/terraform/cliconfig"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+)
+
+const (
+	// The dir, in the download dir, where the configs used to download the providers are generated.
+	warmDirName    = "providers-warm"
+	warmConfigFile = "versions.tf.json"
+
+	// The namespace of the providers required without a source, e.g. `aws` is `hashicorp/aws`.
+	defaultProviderNamespace = "hashicorp"
+)
+
+// RunWarm downloads the providers required by the modules of the stack into the plugin cache. The providers are
+// either cached by the Terragrunt Provider Cache, if enabled, or in the plugin cache dir of terraform.
+func RunWarm(ctx context.Context, opts *options.TerragruntOptions) error {
+	if !opts.ProviderCache {
+		cacheDir, err := pluginCacheDir(opts)
+		if err != nil {
+			return err
+		}
+		opts.Logger.Debugf("Warming the plugin cache dir %s", cacheDir)
+	}
+
+	stack, err := configstack.FindStackInSubfolders(ctx, opts, nil)
+	if err != nil {
+		return err
+	}
+
+	requirements := providerRequirements{}
+	moduleCount := 0
+	for _, module := range stack.Modules {
+		if module.FlagExcluded {
+			continue
+		}
+		moduleCount++
+
+		// Get the code of the module, with the generated files, to read the providers it requires
+		target := terraform.NewTarget(terraform.TargetPointGenerateConfig, func(ctx context.Context, opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+			return requirements.addModule(opts.WorkingDir)
+		})
+		if err := terraform.RunWithTarget(ctx, module.TerragruntOptions.Clone(module.TerragruntOptions.TerragruntConfigPath), target); err != nil {
+			return err
+		}
+	}
+
+	if len(requirements) == 0 {
+		opts.Logger.Infof("The modules of the stack don't require any providers")
+		return nil
+	}
+
+	warmDir := filepath.Join(opts.DownloadDir, warmDirName)
+	if err := os.RemoveAll(warmDir); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// The configs are initialized one after the other, as the point is to not download the same provider concurrently
+	for i, warmConfig := range requirements.warmConfigs() {
+		dir := filepath.Join(warmDir, fmt.Sprint(i))
+		if err := writeWarmConfig(dir, warmConfig); err != nil {
+			return err
+		}
+
+		initOpts := opts.Clone(opts.TerragruntConfigPath)
+		initOpts.WorkingDir = dir
+		initOpts.TerraformCommand = tf.CommandNameInit
+		initOpts.TerraformCliArgs = []string{tf.CommandNameInit, "-backend=false", "-input=false"}
+
+		opts.Logger.Debugf("Downloading the providers of %s", dir)
+		if err := shell.RunTerraformCommand(ctx, initOpts, initOpts.TerraformCliArgs...); err != nil {
+			return err
+		}
+	}
+
+	opts.Logger.Infof("Warmed the plugin cache with %d providers required by %d modules", len(requirements), moduleCount)
+	return nil
+}
+
+// pluginCacheDir returns the plugin cache dir of terraform, failing if none is configured, as terraform would then
+// download the providers for every module anyway.
+func pluginCacheDir(opts *options.TerragruntOptions) (string, error) {
+	if dir := opts.Env[tf.EnvNameTFPluginCacheDir]; dir != "" {
+		return dir, nil
+	}
+
+	cfg, err := cliconfig.LoadUserConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg.PluginCacheDir != "" {
+		return cfg.PluginCacheDir, nil
+	}
+	return "", errors.WithStackTrace(MissingPluginCache{})
+}
+
+// providerRequirements are the distinct version constraints of every provider source, e.g. `hashicorp/aws`.
+type providerRequirements map[string][]string
+
+// addModule adds the providers required by the terraform module in the given dir, and by its local modules.
+func (requirements providerRequirements) addModule(dir string) error {
+	return requirements.addModuleDir(dir, map[string]bool{})
+}
+
+func (requirements providerRequirements) addModuleDir(dir string, visited map[string]bool) error {
+	dir = filepath.Clean(dir)
+	if visited[dir] {
+		return nil
+	}
+	visited[dir] = true
+
+	module, diags := tfconfig.LoadModule(dir)
+	if diags.HasErrors() {
+		return errors.WithStackTrace(diags)
+	}
+
+	for name, requirement := range module.RequiredProviders {
+		source := requirement.Source
+		if source == "" {
+			source = defaultProviderNamespace + "/" + name
+		}
+		requirements.add(strings.ToLower(source), strings.Join(requirement.VersionConstraints, ", "))
+	}
+
+	// The remote modules are only known once terraform downloaded them, which the init of the module does
+	for _, call := range module.ModuleCalls {
+		if strings.HasPrefix(call.Source, "./") || strings.HasPrefix(call.Source, "../") {
+			if err := requirements.addModuleDir(filepath.Join(dir, call.Source), visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (requirements providerRequirements) add(source string, constraint string) {
+	if !util.ListContainsElement(requirements[source], constraint) {
+		requirements[source] = append(requirements[source], constraint)
+	}
+}
+
+// warmConfigs returns the `required_providers` of the configs to initialize to download every provider. Since the
+// modules may require a provider with conflicting constraints, e.g. `~> 4.0` and `~> 5.0`, each config requires each
+// provider with one of its constraints, so that there are as many configs as the most constraints of a provider.
+func (requirements providerRequirements) warmConfigs() []map[string]interface{} {
+	sources := make([]string, 0, len(requirements))
+	for source := range requirements {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var configs []map[string]interface{}
+	for i := 0; ; i++ {
+		requiredProviders := map[string]interface{}{}
+		for _, source := range sources {
+			constraints := requirements[source]
+			if i >= len(constraints) {
+				continue
+			}
+
+			requirement := map[string]string{"source": source}
+			if constraints[i] != "" {
+				requirement["version"] = constraints[i]
+			}
+			requiredProviders[providerLocalName(requiredProviders, source)] = requirement
+		}
+		if len(requiredProviders) == 0 {
+			return configs
+		}
+		configs = append(configs, requiredProviders)
+	}
+}
+
+// providerLocalName returns the type of the provider with the given source, suffixed if another provider of the same
+// type, from another namespace, is already required.
+func providerLocalName(requiredProviders map[string]interface{}, source string) string {
+	name := source[strings.LastIndex(source, "/")+1:]
+	localName := name
+	for i := 2; requiredProviders[localName] != nil; i++ {
+		localName = fmt.Sprintf("%s_%d", name, i)
+	}
+	return localName
+}
+
+func writeWarmConfig(dir string, requiredProviders map[string]interface{}) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	content, err := json.MarshalIndent(map[string]interface{}{
+		"terraform": map[string]interface{}{"required_providers": requiredProviders},
+	}, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, warmConfigFile), content, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderRequirementsAddModule(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "modules", "dns"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "random" {}
+
+module "dns" {
+  source = "./modules/dns"
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "modules", "dns", "main.tf"), []byte(`
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 4.0"
+    }
+    cloudflare = {
+      source = "Cloudflare/cloudflare"
+    }
+  }
+}
+`), 0644))
+
+	requirements := providerRequirements{}
+	require.NoError(t, requirements.addModule(dir))
+	require.NoError(t, requirements.addModule(filepath.Join(dir, "modules", "dns")))
+
+	assert.Equal(t, providerRequirements{
+		"hashicorp/aws":         {"~> 5.0", "~> 4.0"},
+		"hashicorp/random":      {""},
+		"cloudflare/cloudflare": {""},
+	}, requirements)
+}
+
+func TestProviderRequirementsWarmConfigs(t *testing.T) {
+	t.Parallel()
+
+	requirements := providerRequirements{
+		"hashicorp/aws":   {"~> 5.0", "~> 4.0"},
+		"hashicorp/dns":   {""},
+		"example.com/dns": {">= 1.0"},
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"dns":   map[string]string{"source": "example.com/dns", "version": ">= 1.0"},
+			"aws":   map[string]string{"source": "hashicorp/aws", "version": "~> 5.0"},
+			"dns_2": map[string]string{"source": "hashicorp/dns"},
+		},
+		{
+			"aws": map[string]string{"source": "hashicorp/aws", "version": "~> 4.0"},
+		},
+	}, requirements.warmConfigs())
+}
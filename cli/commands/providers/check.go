@@ -0,0 +1,124 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// RunCheck checks the modules of the stack against the provider matrix, and fails if any module requires or
+// configures the providers of the matrix on its own, as the requirements and the provider blocks generated would then
+// conflict with the ones of the module. It also reports the modules requiring the providers with other version
+// constraints than the provider version overrides, which fails the check too.
+func RunCheck(ctx context.Context, opts *options.TerragruntOptions) error {
+	stack, err := configstack.FindStackInSubfolders(ctx, opts, nil)
+	if err != nil {
+		return err
+	}
+
+	deviatingModules := 0
+	checkedModules := 0
+	for _, module := range stack.Modules {
+		if module.FlagExcluded {
+			continue
+		}
+
+		// Get the code of the module, before the provider matrix is generated into it
+		var deviations []string
+		target := terraform.NewTarget(terraform.TargetPointDownloadSource, func(ctx context.Context, opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+			if cfg.ProviderMatrix == nil && cfg.ProviderVersionOverrides == nil {
+				return nil
+			}
+			checkedModules++
+
+			if cfg.ProviderMatrix != nil {
+				matrixDeviations, err := providerMatrixDeviations(opts.WorkingDir, cfg.ProviderMatrix)
+				if err != nil {
+					return err
+				}
+				deviations = append(deviations, matrixDeviations...)
+			}
+			if cfg.ProviderVersionOverrides != nil {
+				overridesDeviations, err := providerVersionOverridesDeviations(opts.WorkingDir, cfg.ProviderVersionOverrides)
+				if err != nil {
+					return err
+				}
+				deviations = append(deviations, overridesDeviations...)
+			}
+			return nil
+		})
+		if err := terraform.RunWithTarget(ctx, module.TerragruntOptions.Clone(module.TerragruntOptions.TerragruntConfigPath), target); err != nil {
+			return err
+		}
+
+		if len(deviations) > 0 {
+			deviatingModules++
+			opts.Logger.Warnf("The module %s deviates from the provider matrix or version overrides:\n  - %s", module.Path, strings.Join(deviations, "\n  - "))
+		}
+	}
+
+	if deviatingModules > 0 {
+		return errors.WithStackTrace(ProviderMatrixDeviations{Modules: deviatingModules})
+	}
+	opts.Logger.Infof("The %d modules with a provider matrix or version overrides match them", checkedModules)
+	return nil
+}
+
+// providerMatrixDeviations returns how the terraform module in the given dir deviates from the provider matrix: the
+// providers of the matrix the module requires from another source or with other version constraints, and the ones it
+// configures with its own default provider block.
+func providerMatrixDeviations(dir string, matrix *config.ProviderMatrixConfig) ([]string, error) {
+	// The file generated by the prior runs is not part of the code of the module
+	module, err := config.LoadTerraformModule(dir, filepath.Join(dir, matrix.GeneratedPath()))
+	if err != nil {
+		return nil, err
+	}
+
+	var deviations []string
+	for _, provider := range matrix.Providers {
+		if requirement, ok := module.RequiredProviders[provider.Name]; ok {
+			if requirement.Source != "" && !strings.EqualFold(requirement.Source, provider.Source) {
+				deviations = append(deviations, fmt.Sprintf("requires the provider %s from %s, instead of %s", provider.Name, requirement.Source, provider.Source))
+			}
+
+			matrixVersion := ""
+			if provider.Version != nil {
+				matrixVersion = *provider.Version
+			}
+			if version := strings.Join(requirement.VersionConstraints, ", "); version != "" && version != matrixVersion {
+				deviations = append(deviations, fmt.Sprintf("requires the version %q of the provider %s, instead of %q", version, provider.Name, matrixVersion))
+			}
+		}
+
+		if _, ok := module.ProviderConfigs[provider.Name]; ok && provider.Config != nil && !provider.Config.IsNull() {
+			deviations = append(deviations, fmt.Sprintf("configures the provider %s, which the provider matrix configures", provider.Name))
+		}
+	}
+	sort.Strings(deviations)
+	return deviations, nil
+}
+
+// providerVersionOverridesDeviations returns the providers of the overrides the terraform module in the given dir
+// requires with other version constraints, which the overrides replace.
+func providerVersionOverridesDeviations(dir string, overrides *config.ProviderVersionOverridesConfig) ([]string, error) {
+	// The file generated by the prior runs is not part of the code of the module
+	module, err := config.LoadTerraformModule(dir, filepath.Join(dir, overrides.GeneratedPath()))
+	if err != nil {
+		return nil, err
+	}
+
+	var deviations []string
+	for _, conflict := range overrides.Conflicts(module) {
+		deviations = append(deviations, conflict.String())
+	}
+	sort.Strings(deviations)
+	return deviations, nil
+}
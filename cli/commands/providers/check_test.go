@@ -0,0 +1,100 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestProviderMatrixDeviations(t *testing.T) {
+	t.Parallel()
+
+	version := "~> 5.0"
+	providerConfig := cty.ObjectVal(map[string]cty.Value{"region": cty.StringVal("us-east-1")})
+	matrix := &config.ProviderMatrixConfig{
+		Providers: []config.ProviderMatrixProvider{
+			{Name: "aws", Source: "hashicorp/aws", Version: &version, Config: &providerConfig},
+			{Name: "random", Source: "hashicorp/random"},
+			{Name: "cloudflare", Source: "cloudflare/cloudflare"},
+		},
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 4.0"
+    }
+    cloudflare = {
+      source = "acme/cloudflare"
+    }
+  }
+}
+
+provider "aws" {
+  region = "eu-west-1"
+}
+
+provider "aws" {
+  alias  = "replica"
+  region = "eu-central-1"
+}
+
+provider "random" {}
+`), 0644))
+	// The file generated by a prior run is not checked
+	matrixCode, err := matrix.TerraformJSON()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, config.DefaultProviderMatrixPath), matrixCode, 0644))
+
+	deviations, err := providerMatrixDeviations(dir, matrix)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"configures the provider aws, which the provider matrix configures",
+		"requires the provider cloudflare from acme/cloudflare, instead of cloudflare/cloudflare",
+		`requires the version "~> 4.0" of the provider aws, instead of "~> 5.0"`,
+	}, deviations)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "main.tf")))
+	deviations, err = providerMatrixDeviations(dir, matrix)
+	require.NoError(t, err)
+	assert.Empty(t, deviations)
+}
+
+func TestProviderVersionOverridesDeviations(t *testing.T) {
+	t.Parallel()
+
+	overrides := &config.ProviderVersionOverridesConfig{
+		Providers: []config.ProviderVersionOverride{
+			{Name: "aws", Version: "~> 5.40"},
+			{Name: "random", Version: "~> 3.6"},
+		},
+	}
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = ">= 4.0, < 5.0"
+    }
+    random = {
+      source  = "hashicorp/random"
+      version = "~> 3.6"
+    }
+  }
+}
+`), 0644))
+
+	deviations, err := providerVersionOverridesDeviations(dir, overrides)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`requires the version ">= 4.0, < 5.0" of the provider aws, overridden with "~> 5.40"`}, deviations)
+}
@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName     = "providers"
+	SubCommandWarm  = "warm"
+	SubCommandCheck = "check"
+)
+
+// NewCommand creates the `providers` command. Only the `warm` and `check` subcommands are handled by Terragrunt, the other
+// subcommands, such as `providers lock`, are forwarded to terraform as before.
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Warm the provider plugin cache for the stack or check it against the provider matrix and version overrides, other subcommands are forwarded to terraform.",
+		Subcommands: subCommands(opts).SkipRunning(),
+		Action:      action(opts),
+	}
+}
+
+func subCommands(opts *options.TerragruntOptions) cli.Commands {
+	return cli.Commands{
+		&cli.Command{
+			Name:        SubCommandWarm,
+			Usage:       "Download the providers required by the modules of the stack into the shared plugin cache.",
+			Description: "The command recursively finds the terragrunt modules in the current directory tree, computes the union of the providers they require and downloads them once, so that the modules don't race on downloads when they are initialized in parallel.",
+			Action:      func(ctx *cli.Context) error { return RunWarm(ctx, opts.OptionsFromContext(ctx)) },
+		},
+		&cli.Command{
+			Name:        SubCommandCheck,
+			Usage:       "Check the modules of the stack against the provider matrix and version overrides.",
+			Description: "The command recursively finds the terragrunt modules in the current directory tree, and fails if any of them requires the providers of the provider_matrix block from another source or with other version constraints, configures them with its own provider blocks, or requires the providers of the provider_version_overrides block with other version constraints.",
+			Action:      func(ctx *cli.Context) error { return RunCheck(ctx, opts.OptionsFromContext(ctx)) },
+		},
+	}
+}
+
+func action(opts *options.TerragruntOptions) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		if cmd := ctx.Command.Subcommand(ctx.Args().CommandName()); cmd != nil {
+			return cmd.Action(ctx)
+		}
+		return terraform.NewCommand(opts).Action(ctx)
+	}
+}
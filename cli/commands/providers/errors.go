@@ -0,0 +1,24 @@
+package providers
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands"
+	tf "github.com/gruntwork-io/terragrunt/terraform"
+)
+
+// Custom error types
+
+type MissingPluginCache struct{}
+
+func (err MissingPluginCache) Error() string {
+	return fmt.Sprintf("There is no provider cache to warm: enable the Terragrunt Provider Cache with --%s, or set the plugin cache dir of terraform with %s or plugin_cache_dir in the CLI config.", commands.TerragruntProviderCacheFlagName, tf.EnvNameTFPluginCacheDir)
+}
+
+type ProviderMatrixDeviations struct {
+	Modules int
+}
+
+func (err ProviderMatrixDeviations) Error() string {
+	return fmt.Sprintf("%d modules deviate from the provider matrix or version overrides", err.Modules)
+}
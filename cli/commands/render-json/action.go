@@ -46,6 +46,9 @@ func runRenderJSON(ctx context.Context, opts *options.TerragruntOptions, cfg *co
 		cfg.DependentModulesPath = dependentModulesPath
 		cfg.SetFieldMetadata(config.MetadataDependentModules, map[string]interface{}{config.FoundInFile: opts.TerragruntConfigPath})
 	}
+	// The rendered config is shared with other tools, so the sensitive outputs of the dependencies are masked
+	config.RedactSensitiveValues(opts, cfg)
+
 	var terragruntConfigCty cty.Value
 
 	if opts.RenderJsonWithMetadata {
@@ -2,15 +2,21 @@ package runall
 
 import (
 	"context"
+	"time"
 
 	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/configstack"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/gruntwork-io/terragrunt/telemetry"
 	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
 )
 
+// How long releasing the stack lock may take at the end of the run.
+const stackLockReleaseTimeout = time.Minute
+
 // Known terraform commands that are explicitly not supported in run-all due to the nature of the command. This is
 // tracked as a map that maps the terraform command to the reasoning behind disallowing the command in run-all.
 var runAllDisabledCommands = map[string]string{
@@ -56,6 +62,37 @@ func RunAllOnStack(ctx context.Context, opts *options.TerragruntOptions, stack *
 		return err
 	}
 
+	if err := checkSavedPlans(opts, stack); err != nil {
+		return err
+	}
+
+	// The modules storing the same state must lock it in the same lock table, checked before any module runs
+	if util.ListContainsElement(config.TERRAFORM_COMMANDS_NEED_LOCKING, opts.TerraformCommand) {
+		if _, err := stack.FindLockTableUsage(opts.Logger); err != nil {
+			return err
+		}
+	}
+
+	if opts.QuotaPreflight && opts.TerraformCommand == terraform.CommandNameApply {
+		if err := runQuotaPreflight(ctx, opts, stack); err != nil {
+			return err
+		}
+	}
+
+	if isDestroy(opts) {
+		if err := checkPreventDestroyPaths(opts, stack); err != nil {
+			return err
+		}
+
+		shouldDestroy, err := confirmDestroyWithConsumers(ctx, opts, stack)
+		if err != nil {
+			return err
+		}
+		if !shouldDestroy {
+			return nil
+		}
+	}
+
 	var prompt string
 	switch opts.TerraformCommand {
 	case terraform.CommandNameApply:
@@ -75,10 +112,66 @@ func RunAllOnStack(ctx context.Context, opts *options.TerragruntOptions, stack *
 		}
 	}
 
-	return telemetry.Telemetry(ctx, opts, "run_all_on_stack", map[string]interface{}{
+	if opts.StackLock != "" {
+		lock, err := configstack.NewStackLock(opts.StackLock, opts)
+		if err != nil {
+			return err
+		}
+
+		lockInfo := configstack.NewStackLockInfo(opts)
+		if opts.StealStackLock {
+			opts.Logger.Warnf("Stealing stack lock %s", opts.StackLock)
+		}
+		if err := lock.Acquire(ctx, lockInfo, opts.StealStackLock); err != nil {
+			return err
+		}
+		opts.Logger.Debugf("Acquired stack lock %s", opts.StackLock)
+
+		defer releaseStackLock(opts, lock, lockInfo)
+	}
+
+	snapshot := opts.SnapshotDir != "" && opts.TerraformCommand == terraform.CommandNameApply
+	if snapshot {
+		stack.StartSnapshot(opts)
+	}
+
+	err := telemetry.Telemetry(ctx, opts, "run_all_on_stack", map[string]interface{}{
 		"terraform_command": opts.TerraformCommand,
 		"working_dir":       opts.WorkingDir,
 	}, func(childCtx context.Context) error {
 		return stack.Run(ctx, opts)
 	})
+
+	// The manifest is only written once all the modules are planned, so that a partial set of plans is never applied
+	if opts.SavePlansDir != "" {
+		if err != nil && !configstack.IsPlanWithChanges(opts, err) {
+			opts.Logger.Warnf("Not writing the manifest of the plans saved to %s, as not all the modules were planned", opts.SavePlansDir)
+		} else if manifestErr := stack.WriteSavedPlansManifest(opts); manifestErr != nil {
+			err = manifestErr
+		}
+	}
+
+	if snapshot {
+		if snapshotErr := configstack.FinishSnapshot(opts, err); snapshotErr != nil {
+			if err != nil {
+				opts.Logger.Warnf("Failed to discard the snapshot of the run %s: %v", opts.SnapshotRunID, snapshotErr)
+			} else {
+				err = snapshotErr
+			}
+		}
+	}
+	return err
+}
+
+// releaseStackLock releases the stack lock at the end of the run. The lock is released with a context of its own, as
+// the context of the run is cancelled when the run is interrupted, which is when the lock must not stay held.
+func releaseStackLock(opts *options.TerragruntOptions, lock configstack.StackLock, lockInfo configstack.StackLockInfo) {
+	ctx, cancel := context.WithTimeout(context.Background(), stackLockReleaseTimeout)
+	defer cancel()
+
+	if err := lock.Release(ctx, lockInfo); err != nil {
+		opts.Logger.Errorf("Failed to release stack lock %s: %v", opts.StackLock, err)
+		return
+	}
+	opts.Logger.Debugf("Released stack lock %s", opts.StackLock)
 }
@@ -9,6 +9,8 @@ import (
 	awsproviderpatch "github.com/gruntwork-io/terragrunt/cli/commands/aws-provider-patch"
 	graphdependencies "github.com/gruntwork-io/terragrunt/cli/commands/graph-dependencies"
 	"github.com/gruntwork-io/terragrunt/cli/commands/hclfmt"
+	"github.com/gruntwork-io/terragrunt/cli/commands/licenses"
+	moduledocs "github.com/gruntwork-io/terragrunt/cli/commands/module-docs"
 	renderjson "github.com/gruntwork-io/terragrunt/cli/commands/render-json"
 	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
 	terragruntinfo "github.com/gruntwork-io/terragrunt/cli/commands/terragrunt-info"
@@ -40,6 +42,18 @@ func NewFlags(opts *options.TerragruntOptions) cli.Flags {
 			Destination: &opts.OutputFolder,
 			Usage:       "Directory output files.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        commands.TerragruntSavePlansFlagName,
+			EnvVar:      commands.TerragruntSavePlansFlagEnvVarName,
+			Destination: &opts.SavePlansDir,
+			Usage:       "Save the plans of run-all plan to the given dir, with a manifest of the modules and their order.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        commands.TerragruntUsePlansFlagName,
+			EnvVar:      commands.TerragruntUsePlansFlagEnvVarName,
+			Destination: &opts.UsePlansDir,
+			Usage:       "Apply the plans saved by run-all plan to the given dir, after verifying that the modules and their order match.",
+		},
 	}
 }
 
@@ -53,7 +67,15 @@ func action(opts *options.TerragruntOptions) cli.ActionFunc {
 			return terraform.Run(ctx, opts)
 		}
 
-		return Run(cliCtx.Context, opts.OptionsFromContext(cliCtx))
+		if err := Run(cliCtx.Context, opts.OptionsFromContext(cliCtx)); err != nil {
+			return err
+		}
+
+		// The subcommands that aggregate the results of all the modules, e.g. licenses, finish once the whole stack ran
+		if cmd := cliCtx.Command.Subcommand(opts.TerraformCommand); cmd != nil && cmd.After != nil {
+			return cmd.After(cliCtx)
+		}
+		return nil
 	}
 }
 
@@ -61,10 +83,12 @@ func subCommands(opts *options.TerragruntOptions) cli.Commands {
 	cmds := cli.Commands{
 		terragruntinfo.NewCommand(opts),    // terragrunt-info
 		validateinputs.NewCommand(opts),    // validate-inputs
+		moduledocs.NewCommand(opts),        // module-docs
 		graphdependencies.NewCommand(opts), // graph-dependencies
 		hclfmt.NewCommand(opts),            // hclfmt
 		renderjson.NewCommand(opts),        // render-json
 		awsproviderpatch.NewCommand(opts),  // aws-provider-patch
+		licenses.NewCommand(opts),          // licenses
 	}
 
 	sort.Sort(cmds)
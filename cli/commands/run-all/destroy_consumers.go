@@ -0,0 +1,46 @@
+package runall
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// isDestroy returns true if the run-all destroys the modules, with destroy or `apply -destroy`.
+func isDestroy(opts *options.TerragruntOptions) bool {
+	return opts.TerraformCommand == terraform.CommandNameDestroy ||
+		(opts.TerraformCommand == terraform.CommandNameApply && util.ListContainsElement(opts.TerraformCliArgs, terraform.FlagNameDestroy))
+}
+
+// confirmDestroyWithConsumers returns true if the modules of the stack can be destroyed: either no module which is not
+// destroyed consumes their outputs, or the user confirmed the destroy or passed --terragrunt-allow-destroy-with-consumers.
+// As the confirmation cannot be asked with --terragrunt-non-interactive, the destroy is blocked then.
+func confirmDestroyWithConsumers(ctx context.Context, opts *options.TerragruntOptions, stack *configstack.Stack) (bool, error) {
+	consumers, err := stack.FindOutputConsumers(ctx, opts)
+	if err != nil {
+		return false, err
+	}
+	if len(consumers) == 0 {
+		return true, nil
+	}
+
+	if opts.AllowDestroyWithConsumers {
+		opts.Logger.Warnf("Destroying modules whose outputs are consumed by modules not destroyed, as --terragrunt-allow-destroy-with-consumers is set:\n%s", configstack.OutputConsumersString(consumers))
+		return true, nil
+	}
+
+	if opts.NonInteractive {
+		return false, errors.WithStackTrace(DestroyWithOutputConsumers(configstack.OutputConsumersString(consumers)))
+	}
+
+	if _, err := fmt.Fprintf(opts.ErrWriter, "The outputs of the modules to destroy are consumed by modules which are not destroyed:\n%s\n", configstack.OutputConsumersString(consumers)); err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+	return shell.PromptUserForYesNo("WARNING: Are you sure you want to destroy these modules, stranding their consumers?", opts)
+}
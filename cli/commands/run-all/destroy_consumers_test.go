@@ -0,0 +1,50 @@
+package runall
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmDestroyWithConsumers(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	newStack := func() *configstack.Stack {
+		vpc := &configstack.TerraformModule{Path: filepath.Join(rootDir, "vpc")}
+		app := &configstack.TerraformModule{Path: filepath.Join(rootDir, "app"), Dependencies: []*configstack.TerraformModule{vpc}}
+		return &configstack.Stack{Path: rootDir, Modules: []*configstack.TerraformModule{vpc, app}}
+	}
+
+	newOptions := func() *options.TerragruntOptions {
+		opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "terragrunt.hcl"))
+		require.NoError(t, err)
+		opts.WorkingDir = rootDir
+		opts.NonInteractive = true
+		return opts
+	}
+
+	// Both modules are destroyed
+	shouldDestroy, err := confirmDestroyWithConsumers(context.Background(), newOptions(), newStack())
+	require.NoError(t, err)
+	assert.True(t, shouldDestroy)
+
+	stack := newStack()
+	stack.Modules[1].FlagExcluded = true
+	_, err = confirmDestroyWithConsumers(context.Background(), newOptions(), stack)
+	require.Error(t, err)
+	assert.IsType(t, DestroyWithOutputConsumers(""), errors.Unwrap(err))
+	assert.Contains(t, err.Error(), filepath.Join(rootDir, "app")+" depends on "+filepath.Join(rootDir, "vpc"))
+
+	opts := newOptions()
+	opts.AllowDestroyWithConsumers = true
+	shouldDestroy, err = confirmDestroyWithConsumers(context.Background(), opts, stack)
+	require.NoError(t, err)
+	assert.True(t, shouldDestroy)
+}
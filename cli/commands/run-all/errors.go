@@ -1,6 +1,11 @@
 package runall
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands"
+)
 
 type RunAllDisabledErr struct {
 	command string
@@ -16,3 +21,30 @@ type MissingCommand struct{}
 func (err MissingCommand) Error() string {
 	return "Missing run-all command argument (Example: terragrunt run-all plan)"
 }
+
+type DestroyWithOutputConsumers string
+
+func (consumers DestroyWithOutputConsumers) Error() string {
+	return fmt.Sprintf("Not destroying the stack, as the outputs of its modules are consumed by modules which are not destroyed:\n%s\nDestroy the consumers too, or pass --terragrunt-allow-destroy-with-consumers to destroy the stack anyway.", string(consumers))
+}
+
+type ProtectedModulesDestroyed []string
+
+func (modules ProtectedModulesDestroyed) Error() string {
+	return fmt.Sprintf("Not destroying the stack, as it would destroy the modules protected by prevent_destroy_paths or --terragrunt-prevent-destroy-path: %s. Exclude them from the run to destroy the rest of the stack.", strings.Join(modules, ", "))
+}
+
+type SavedPlansWithCommand struct {
+	Flag    string
+	Command string
+}
+
+func (err SavedPlansWithCommand) Error() string {
+	return fmt.Sprintf("--%s can only be used with run-all %s", err.Flag, err.Command)
+}
+
+type SavedPlansWithOutDir string
+
+func (flag SavedPlansWithOutDir) Error() string {
+	return fmt.Sprintf("--%s cannot be used together with --%s, which also sets the plan files of the modules", string(flag), commands.TerragruntOutDirFlagName)
+}
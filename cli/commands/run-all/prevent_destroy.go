@@ -0,0 +1,39 @@
+package runall
+
+import (
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// checkPreventDestroyPaths returns an error if the run-all destroy would destroy one of the modules protected by the
+// prevent_destroy_paths of the configs of the stack, or by --terragrunt-prevent-destroy-path, so that no module of the
+// stack is destroyed. The paths set by the configs of the modules excluded from the run protect the modules as well.
+func checkPreventDestroyPaths(opts *options.TerragruntOptions, stack *configstack.Stack) error {
+	protected := util.CloneStringList(opts.PreventDestroyPaths)
+	for _, module := range stack.Modules {
+		protected = append(protected, module.Config.PreventDestroyPaths...)
+	}
+	if len(protected) == 0 {
+		return nil
+	}
+
+	var destroyed []string
+	for _, module := range stack.Modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			continue
+		}
+		if util.ListContainsElement(protected, module.Path) {
+			destroyed = append(destroyed, module.Path)
+		}
+	}
+	if len(destroyed) == 0 {
+		return nil
+	}
+
+	sort.Strings(destroyed)
+	return errors.WithStackTrace(ProtectedModulesDestroyed(destroyed))
+}
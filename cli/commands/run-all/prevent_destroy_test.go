@@ -0,0 +1,90 @@
+package runall
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckPreventDestroyPaths(t *testing.T) {
+	t.Parallel()
+
+	newStack := func() *configstack.Stack {
+		return &configstack.Stack{Path: "/live", Modules: []*configstack.TerraformModule{
+			{Path: "/live/vpc"},
+			{Path: "/live/db"},
+			{Path: "/live/app", Config: config.TerragruntConfig{PreventDestroyPaths: []string{"/live/db"}}},
+		}}
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/terragrunt.hcl")
+	require.NoError(t, err)
+
+	err = checkPreventDestroyPaths(opts, newStack())
+	require.Error(t, err)
+	assert.Equal(t, ProtectedModulesDestroyed{"/live/db"}, errors.Unwrap(err))
+
+	// The paths of the config of an excluded module protect the other modules too
+	stack := newStack()
+	stack.Modules[2].FlagExcluded = true
+	require.Error(t, checkPreventDestroyPaths(opts, stack))
+
+	stack = newStack()
+	stack.Modules[1].FlagExcluded = true
+	require.NoError(t, checkPreventDestroyPaths(opts, stack))
+
+	opts.PreventDestroyPaths = []string{"/live/vpc"}
+	err = checkPreventDestroyPaths(opts, stack)
+	require.Error(t, err)
+	assert.Equal(t, ProtectedModulesDestroyed{"/live/vpc"}, errors.Unwrap(err))
+}
+
+func TestCheckPreventDestroyPathsSkipUntargeted(t *testing.T) {
+	t.Parallel()
+
+	tempFolder := t.TempDir()
+	for _, name := range []string{"db", "app"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tempFolder, name), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(tempFolder, name, config.DefaultTerragruntConfigPath), []byte(""), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempFolder, name, "main.tf"), []byte(""), 0644))
+	}
+
+	// run-all destroy --terragrunt-target db:aws_db_instance.main --terragrunt-skip-untargeted
+	newOpts := func(skipUntargeted bool) *options.TerragruntOptions {
+		opts, err := options.NewTerragruntOptionsForTest(filepath.Join(tempFolder, config.DefaultTerragruntConfigPath))
+		require.NoError(t, err)
+		opts.WorkingDir = tempFolder
+		opts.TerraformCommand = "destroy"
+		opts.TerraformCliArgs = []string{"destroy"}
+		opts.Targets = []string{"db:aws_db_instance.main"}
+		opts.SkipUntargeted = skipUntargeted
+		opts.PreventDestroyPaths = []string{filepath.Join(tempFolder, "app")}
+		return opts
+	}
+
+	opts := newOpts(true)
+	stack, err := configstack.FindStackInSubfolders(context.Background(), opts, nil)
+	require.NoError(t, err)
+
+	// The untargeted modules are skipped before the deploy order is logged and the destroy is checked
+	deployOrder, err := stack.JsonModuleDeployOrder(opts.TerraformCommand)
+	require.NoError(t, err)
+	assert.Contains(t, deployOrder, filepath.Join(tempFolder, "db"))
+	assert.NotContains(t, deployOrder, filepath.Join(tempFolder, "app"))
+	require.NoError(t, checkPreventDestroyPaths(opts, stack))
+
+	opts = newOpts(false)
+	stack, err = configstack.FindStackInSubfolders(context.Background(), opts, nil)
+	require.NoError(t, err)
+	err = checkPreventDestroyPaths(opts, stack)
+	require.Error(t, err)
+	assert.Equal(t, ProtectedModulesDestroyed{filepath.Join(tempFolder, "app")}, errors.Unwrap(err))
+}
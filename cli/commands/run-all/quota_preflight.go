@@ -0,0 +1,94 @@
+package runall
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/quota"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// runQuotaPreflight plans every module of the stack and warns about the quotas the resources the plans create would
+// exceed, before anything is applied. The modules that fail to plan, e.g. as their dependencies are not applied yet and
+// have no mock outputs, are left out of the check.
+func runQuotaPreflight(ctx context.Context, opts *options.TerragruntOptions, stack *configstack.Stack) error {
+	client, err := quota.NewAWSClient(opts)
+	if err != nil {
+		opts.Logger.Warnf("Not checking the service quotas, as there are no AWS credentials: %v", err)
+		return nil
+	}
+
+	planned, err := planStackResources(ctx, opts, stack)
+	if err != nil {
+		return err
+	}
+
+	exceeded := quota.Check(ctx, opts, client, quota.AWSResourceQuotas, planned)
+	if len(exceeded) == 0 {
+		opts.Logger.Infof("The resources the stack plans to create are within the service quotas")
+		return nil
+	}
+
+	message := "The resources the stack plans to create would exceed the following service quotas, request an increase before applying:"
+	for _, exceeded := range exceeded {
+		message += fmt.Sprintf("\n  - %s", exceeded)
+	}
+	opts.Logger.Warn(message)
+	return nil
+}
+
+// planStackResources plans every module of the stack to a temporary plan file, and counts the resources the plans
+// create and destroy.
+func planStackResources(ctx context.Context, opts *options.TerragruntOptions, stack *configstack.Stack) (quota.PlannedResources, error) {
+	planDir, err := os.MkdirTemp("", "terragrunt-quota-preflight-")
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer os.RemoveAll(planDir) //nolint:errcheck
+
+	planned := quota.PlannedResources{}
+	for _, module := range stack.Modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			continue
+		}
+		planFile := filepath.Join(planDir, util.FolderPathAsFile(module.Path)) + terraform.TerraformPlanFileExtension
+
+		planOpts := preflightOptions(module.TerragruntOptions, terraform.CommandNamePlan, "-input=false", "-lock=false", "-out="+planFile)
+		opts.Logger.Infof("Planning %s to check the service quotas", module.Path)
+		if err := planOpts.RunTerragrunt(ctx, planOpts); err != nil {
+			opts.Logger.Warnf("Not checking the service quotas of %s, as it failed to plan: %v", module.Path, err)
+			continue
+		}
+
+		var planJSON bytes.Buffer
+		showOpts := preflightOptions(module.TerragruntOptions, terraform.CommandNameShow, "-json", planFile)
+		showOpts.Writer = &planJSON
+		if err := showOpts.RunTerragrunt(ctx, showOpts); err != nil {
+			return nil, err
+		}
+		if err := planned.AddPlan(planJSON.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	return planned, nil
+}
+
+func preflightOptions(moduleOpts *options.TerragruntOptions, command string, args ...string) *options.TerragruntOptions {
+	preflightOpts := moduleOpts.Clone(moduleOpts.TerragruntConfigPath)
+	preflightOpts.TerraformCommand = command
+	preflightOpts.TerraformCliArgs = append([]string{command}, args...)
+	// The dependencies not applied yet are planned with the mock outputs allowed for plan
+	preflightOpts.OriginalTerraformCommand = terraform.CommandNamePlan
+	preflightOpts.Writer = io.Discard
+	// The preflight plans are never recorded in the snapshot of the run
+	preflightOpts.SnapshotRunDir = ""
+	return preflightOpts
+}
@@ -0,0 +1,40 @@
+package runall
+
+import (
+	"os"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/cli/commands"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+)
+
+// checkSavedPlans prepares the two-phase run with the saved plans: run-all plan with --terragrunt-save-plans removes the
+// manifest of the plans saved by a previous run, and run-all apply with --terragrunt-use-plans verifies that the stack
+// matches the manifest of the saved plans, before anything runs.
+func checkSavedPlans(opts *options.TerragruntOptions, stack *configstack.Stack) error {
+	if opts.SavePlansDir != "" {
+		if opts.TerraformCommand != terraform.CommandNamePlan {
+			return errors.WithStackTrace(SavedPlansWithCommand{Flag: commands.TerragruntSavePlansFlagName, Command: terraform.CommandNamePlan})
+		}
+		if opts.OutputFolder != "" {
+			return errors.WithStackTrace(SavedPlansWithOutDir(commands.TerragruntSavePlansFlagName))
+		}
+		if err := os.MkdirAll(opts.SavePlansDir, os.ModePerm); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		return configstack.RemoveSavedPlansManifest(opts)
+	}
+
+	if opts.UsePlansDir != "" {
+		if opts.TerraformCommand != terraform.CommandNameApply {
+			return errors.WithStackTrace(SavedPlansWithCommand{Flag: commands.TerragruntUsePlansFlagName, Command: terraform.CommandNameApply})
+		}
+		if opts.OutputFolder != "" {
+			return errors.WithStackTrace(SavedPlansWithOutDir(commands.TerragruntUsePlansFlagName))
+		}
+		return stack.VerifySavedPlans(opts)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package runall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStackLock is a stack lock whose calls fail once their context is cancelled, as the calls to DynamoDB and GCS do.
+type fakeStackLock struct {
+	held bool
+}
+
+func (lock *fakeStackLock) Acquire(ctx context.Context, info configstack.StackLockInfo, steal bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	lock.held = true
+	return nil
+}
+
+func (lock *fakeStackLock) Release(ctx context.Context, info configstack.StackLockInfo) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	lock.held = false
+	return nil
+}
+
+func TestReleaseStackLockAfterInterrupt(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+	opts.StackLock = "dynamodb://locks/stack"
+
+	lock := &fakeStackLock{}
+	lockInfo := configstack.NewStackLockInfo(opts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, lock.Acquire(ctx, lockInfo, false))
+
+	// The run is interrupted, which cancels its context, before the lock is released
+	cancel()
+	releaseStackLock(opts, lock, lockInfo)
+	assert.False(t, lock.held)
+}
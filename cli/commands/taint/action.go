@@ -0,0 +1,160 @@
+package taint
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The separator of the path of a module and of its workspace, e.g. app@blue, as in the paths of the modules run-all runs
+// in their workspaces.
+const workspaceSeparator = "@"
+
+// Operation is the taint, or untaint, of a resource of a module.
+type Operation struct {
+	// The dir of the module, relative to the working dir
+	ModulePath string
+	Address    string
+}
+
+func (operation Operation) String() string {
+	return operation.ModulePath + " " + operation.Address
+}
+
+// Run taints, or untaints, the resources of the given operations one by one, each in its module, with the full
+// Terragrunt run of the module, which initializes its backend. The operations go on once one fails, so that all the
+// resources of a list are handled, and the failed ones are returned.
+func Run(ctx context.Context, opts *options.TerragruntOptions, command string, operations []Operation) error {
+	terraformFlags := terraformFlags(opts.TerraformCliArgs)
+
+	var failed []string
+	for _, operation := range operations {
+		moduleOpts, err := moduleOptions(opts, operation.ModulePath)
+		if err == nil {
+			moduleOpts.TerraformCommand = command
+			moduleOpts.TerraformCliArgs = append(append([]string{command}, terraformFlags...), operation.Address)
+
+			opts.Logger.Infof("Running %s %s in the module %s", command, operation.Address, operation.ModulePath)
+			err = terraform.Run(ctx, moduleOpts)
+		}
+		if err != nil {
+			opts.Logger.Errorf("Failed to %s %s in the module %s: %v", command, operation.Address, operation.ModulePath, err)
+			failed = append(failed, operation.String())
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.WithStackTrace(OperationsFailed{Command: command, Failed: failed, Total: len(operations)})
+	}
+	return nil
+}
+
+// operationsFromArgs returns the operations of the given args, without the terraform flags: the operation of the module
+// and address args, or the operations of the list file. It returns false if the args are neither, e.g. as the command is
+// run in a module with just the address, so that the command is forwarded to terraform.
+func operationsFromArgs(opts *options.TerragruntOptions, args []string, listFile string) ([]Operation, bool, error) {
+	if listFile != "" {
+		operations, err := ReadListFile(listFile)
+		return operations, true, err
+	}
+
+	var positional []string
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) != 2 {
+		return nil, false, nil
+	}
+
+	if modulePath, _ := splitWorkspace(absModulePath(opts, positional[0])); !util.IsDir(modulePath) {
+		return nil, false, nil
+	}
+	return []Operation{{ModulePath: positional[0], Address: positional[1]}}, true, nil
+}
+
+// ReadListFile reads the operations of the given list file, one `<module path> <address>` per line. The empty lines and
+// the lines starting with # are ignored.
+func ReadListFile(path string) ([]Operation, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	var operations []Operation
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		modulePath, address, ok := strings.Cut(line, " ")
+		address = strings.TrimSpace(address)
+		if !ok || address == "" {
+			return nil, errors.WithStackTrace(InvalidListFileLine{File: path, Line: lineNumber})
+		}
+		operations = append(operations, Operation{ModulePath: modulePath, Address: address})
+	}
+	return operations, errors.WithStackTrace(scanner.Err())
+}
+
+// moduleOptions returns the options to run the command in the module of the given path, relative to the working dir,
+// and optionally suffixed with @ and the workspace to run the command in.
+func moduleOptions(opts *options.TerragruntOptions, path string) (*options.TerragruntOptions, error) {
+	modulePath, workspace := splitWorkspace(absModulePath(opts, path))
+
+	configPath := config.GetDefaultConfigPath(modulePath)
+	if !util.FileExists(configPath) {
+		return nil, errors.WithStackTrace(ModuleNotFound(modulePath))
+	}
+
+	moduleOpts := opts.Clone(configPath)
+	moduleOpts.OriginalTerragruntConfigPath = configPath
+	moduleOpts.DownloadDir = filepath.Join(modulePath, util.TerragruntCacheDir)
+	moduleOpts.TerraformWorkspace = workspace
+	return moduleOpts, nil
+}
+
+func absModulePath(opts *options.TerragruntOptions, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return util.JoinPath(opts.WorkingDir, path)
+}
+
+// splitWorkspace splits the given path of a module into the path and the workspace, if the path is suffixed with @ and
+// the workspace, and the path with the suffix is not a dir.
+func splitWorkspace(path string) (string, string) {
+	index := strings.LastIndex(path, workspaceSeparator)
+	if index <= 0 || util.IsDir(path) || strings.ContainsRune(path[index:], filepath.Separator) {
+		return path, ""
+	}
+	return path[:index], path[index+1:]
+}
+
+// terraformFlags returns the flags of the given terraform CLI args, e.g. -allow-missing, which are passed to every
+// operation.
+func terraformFlags(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	var flags []string
+	for _, arg := range args[1:] {
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		}
+	}
+	return flags
+}
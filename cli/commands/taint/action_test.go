@@ -0,0 +1,111 @@
+package taint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationsFromArgs(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(workingDir, "vpc"), 0755))
+
+	listFile := filepath.Join(workingDir, "incident.txt")
+	list := "# Resources to recreate\nvpc aws_subnet.private\n\napp@blue  module.web.aws_instance.this[\"a b\"]\n"
+	require.NoError(t, os.WriteFile(listFile, []byte(list), 0644))
+
+	testCases := []struct {
+		name       string
+		args       []string
+		listFile   string
+		expected   []Operation
+		expectedOk bool
+	}{
+		{name: "module and address", args: []string{"-allow-missing", "vpc", "aws_subnet.private"}, expected: []Operation{{ModulePath: "vpc", Address: "aws_subnet.private"}}, expectedOk: true},
+		{name: "module workspace and address", args: []string{"vpc@blue", "aws_subnet.private"}, expected: []Operation{{ModulePath: "vpc@blue", Address: "aws_subnet.private"}}, expectedOk: true},
+		{name: "address only", args: []string{"aws_subnet.private"}},
+		{name: "not a module", args: []string{"db", "aws_db_instance.main"}},
+		{
+			name:       "list file",
+			listFile:   listFile,
+			expected:   []Operation{{ModulePath: "vpc", Address: "aws_subnet.private"}, {ModulePath: "app@blue", Address: "module.web.aws_instance.this[\"a b\"]"}},
+			expectedOk: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, config.DefaultTerragruntConfigPath))
+			require.NoError(t, err)
+
+			operations, ok, err := operationsFromArgs(opts, testCase.args, testCase.listFile)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.Equal(t, testCase.expected, operations)
+		})
+	}
+}
+
+func TestReadListFileInvalidLine(t *testing.T) {
+	t.Parallel()
+
+	listFile := filepath.Join(t.TempDir(), "incident.txt")
+	require.NoError(t, os.WriteFile(listFile, []byte("vpc aws_subnet.private\nvpc\n"), 0644))
+
+	_, err := ReadListFile(listFile)
+	var invalidLine InvalidListFileLine
+	require.ErrorAs(t, err, &invalidLine)
+	assert.Equal(t, 2, invalidLine.Line)
+}
+
+func TestSplitWorkspace(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "app@v2"), 0755))
+
+	testCases := []struct {
+		path              string
+		expectedPath      string
+		expectedWorkspace string
+	}{
+		{filepath.Join(dir, "app"), filepath.Join(dir, "app"), ""},
+		{filepath.Join(dir, "app@blue"), filepath.Join(dir, "app"), "blue"},
+		{filepath.Join(dir, "app@v2"), filepath.Join(dir, "app@v2"), ""},
+	}
+
+	for _, testCase := range testCases {
+		path, workspace := splitWorkspace(testCase.path)
+		assert.Equal(t, testCase.expectedPath, path)
+		assert.Equal(t, testCase.expectedWorkspace, workspace)
+	}
+}
+
+func TestRunReportsFailedOperations(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCliArgs = []string{CommandNameTaint}
+
+	operations := []Operation{{ModulePath: "vpc", Address: "aws_subnet.private"}, {ModulePath: "db", Address: "aws_db_instance.main"}}
+	err = Run(context.Background(), opts, CommandNameTaint, operations)
+	require.Error(t, err)
+
+	var failed OperationsFailed
+	require.ErrorAs(t, err, &failed)
+	assert.Equal(t, []string{"vpc aws_subnet.private", "db aws_db_instance.main"}, failed.Failed)
+	assert.Equal(t, 2, failed.Total)
+}
@@ -0,0 +1,52 @@
+package taint
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+	tf "github.com/gruntwork-io/terragrunt/terraform"
+)
+
+const (
+	CommandNameTaint   = tf.CommandNameTaint
+	CommandNameUntaint = tf.CommandNameUntaint
+
+	FlagNameListFile = "list-file"
+)
+
+// NewTaintCommand creates the `taint` command. The command is only handled by Terragrunt when it is given a module and
+// an address, or a list file, otherwise it is forwarded to terraform as before.
+func NewTaintCommand(opts *options.TerragruntOptions) *cli.Command {
+	return newCommand(opts, CommandNameTaint, "Taint a resource of a module of the stack, e.g. `taint vpc aws_subnet.private`, other usages are forwarded to terraform.")
+}
+
+// NewUntaintCommand creates the `untaint` command, handled as the `taint` command.
+func NewUntaintCommand(opts *options.TerragruntOptions) *cli.Command {
+	return newCommand(opts, CommandNameUntaint, "Untaint a resource of a module of the stack, e.g. `untaint vpc aws_subnet.private`, other usages are forwarded to terraform.")
+}
+
+func newCommand(opts *options.TerragruntOptions, name, usage string) *cli.Command {
+	var listFile string
+	return &cli.Command{
+		Name:  name,
+		Usage: usage,
+		Flags: cli.Flags{
+			&cli.GenericFlag[string]{
+				Name:        FlagNameListFile,
+				Destination: &listFile,
+				Usage:       "A file listing the resources to " + name + ", one `<module path> <address>` per line.",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			opts := opts.OptionsFromContext(ctx)
+			operations, ok, err := operationsFromArgs(opts, ctx.Args().Slice(), listFile)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return terraform.NewCommand(opts).Action(ctx)
+			}
+			return Run(ctx, opts, name, operations)
+		},
+	}
+}
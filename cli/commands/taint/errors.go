@@ -0,0 +1,32 @@
+package taint
+
+import (
+	"fmt"
+)
+
+// Custom error types
+
+type InvalidListFileLine struct {
+	File string
+	Line int
+}
+
+func (err InvalidListFileLine) Error() string {
+	return fmt.Sprintf("Invalid line %d of the list file %s: expected <module path> <address>", err.Line, err.File)
+}
+
+type ModuleNotFound string
+
+func (err ModuleNotFound) Error() string {
+	return fmt.Sprintf("No Terragrunt config found in the module %s", string(err))
+}
+
+type OperationsFailed struct {
+	Command string
+	Failed  []string
+	Total   int
+}
+
+func (err OperationsFailed) Error() string {
+	return fmt.Sprintf("Failed to %s %d of the %d resources: %v", err.Command, len(err.Failed), err.Total, err.Failed)
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/cli/commands"
 	"github.com/gruntwork-io/terragrunt/codegen"
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/configstack"
@@ -112,6 +113,11 @@ func runTerraform(ctx context.Context, terragruntOptions *options.TerragruntOpti
 		return nil
 	}
 
+	// The profile is activated before the IAM role is assumed, with the credentials of the profile
+	if err := setProfile(terragruntOptions, terragruntConfig); err != nil {
+		return target.runErrorCallback(terragruntOptions, terragruntConfig, err)
+	}
+
 	// We merge the OriginalIAMRoleOptions into the one from the config, because the CLI passed IAMRoleOptions has
 	// precedence.
 	terragruntOptions.IAMRoleOptions = options.MergeIAMRoleOptions(
@@ -135,6 +141,8 @@ func runTerraform(ctx context.Context, terragruntOptions *options.TerragruntOpti
 		terragruntOptions.DownloadDir = terragruntConfig.DownloadDir
 	}
 
+	terragruntOptions.SuppressWarnings = append(terragruntOptions.SuppressWarnings, terragruntConfig.SuppressWarnings...)
+
 	// Override the default value of retryable errors using the value set in the config file
 	if terragruntConfig.RetryableErrors != nil {
 		terragruntOptions.RetryableErrors = terragruntConfig.RetryableErrors
@@ -147,6 +155,11 @@ func runTerraform(ctx context.Context, terragruntOptions *options.TerragruntOpti
 		terragruntOptions.RetryMaxAttempts = *terragruntConfig.RetryMaxAttempts
 	}
 
+	// The modules found flaky in the run history are retried more than the config sets
+	if terragruntOptions.Flaky && terragruntOptions.FlakyRetryMaxAttempts > terragruntOptions.RetryMaxAttempts {
+		terragruntOptions.RetryMaxAttempts = terragruntOptions.FlakyRetryMaxAttempts
+	}
+
 	if terragruntConfig.RetrySleepIntervalSec != nil {
 		if *terragruntConfig.RetrySleepIntervalSec < 0 {
 			return fmt.Errorf("Cannot sleep for less than 0 seconds, but you specified %d", *terragruntConfig.RetrySleepIntervalSec)
@@ -189,10 +202,17 @@ func runTerraform(ctx context.Context, terragruntOptions *options.TerragruntOpti
 		return target.runCallback(ctx, updatedTerragruntOptions, terragruntConfig)
 	}
 
+	// The binary is selected once the code is generated too, as the generate blocks may set the required_version
+	if isAutoTerraformBinary(terragruntOptions, terragruntConfig.TerraformBinary) {
+		if err := selectTerraformBinary(ctx, terragruntOptions, updatedTerragruntOptions, terragruntConfig); err != nil {
+			return target.runErrorCallback(terragruntOptions, terragruntConfig, err)
+		}
+	}
+
 	// We do the debug file generation here, after all the terragrunt generated terraform files are created so that we
 	// can ensure the tfvars json file only includes the vars that are defined in the module.
 	if updatedTerragruntOptions.Debug {
-		if err := WriteTerragruntDebugFile(updatedTerragruntOptions, terragruntConfig); err != nil {
+		if err := WriteTerragruntDebugFile(ctx, updatedTerragruntOptions, terragruntConfig); err != nil {
 			return target.runErrorCallback(terragruntOptions, terragruntConfig, err)
 		}
 	}
@@ -207,9 +227,33 @@ func runTerraform(ctx context.Context, terragruntOptions *options.TerragruntOpti
 			return nil
 		}
 	}
+
+	var applyFingerprint *ApplyFingerprint
+	if terragruntOptions.SkipUnchanged && util.FirstArg(terragruntOptions.TerraformCliArgs) == terraform.CommandNameApply {
+		if applyFingerprint, err = computeApplyFingerprint(terragruntOptions, updatedTerragruntOptions, terragruntConfig); err != nil {
+			return target.runErrorCallback(terragruntOptions, terragruntConfig, err)
+		}
+
+		lastApplyFingerprint, err := readApplyFingerprint(terragruntOptions)
+		if err != nil {
+			return target.runErrorCallback(terragruntOptions, terragruntConfig, err)
+		}
+
+		if lastApplyFingerprint != nil && *lastApplyFingerprint == *applyFingerprint {
+			terragruntOptions.Logger.Infof("Skipping terragrunt module %s because nothing changed since its last successful apply (--%s).", terragruntOptions.TerragruntConfigPath, commands.TerragruntSkipUnchangedFlagName)
+			return nil
+		}
+	}
+
 	if err := runTerragruntWithConfig(ctx, terragruntOptions, updatedTerragruntOptions, terragruntConfig, target); err != nil {
 		return target.runErrorCallback(terragruntOptions, terragruntConfig, err)
 	}
+
+	if applyFingerprint != nil {
+		if err := writeApplyFingerprint(terragruntOptions, applyFingerprint); err != nil {
+			return target.runErrorCallback(terragruntOptions, terragruntConfig, err)
+		}
+	}
 	return nil
 }
 
@@ -219,8 +263,33 @@ func generateConfig(terragruntConfig *config.TerragruntConfig, updatedTerragrunt
 	defer actualLock.Unlock()
 	actualLock.Lock()
 
-	for _, config := range terragruntConfig.GenerateConfigs {
-		if err := codegen.WriteToFile(updatedTerragruntOptions, updatedTerragruntOptions.WorkingDir, config); err != nil {
+	manifest, err := codegen.ReadGeneratedFilesManifest(updatedTerragruntOptions.WorkingDir)
+	if err != nil {
+		return err
+	}
+	written := map[string]bool{}
+	for name, config := range terragruntConfig.GenerateConfigs {
+		config, err := resolveGenerateSource(updatedTerragruntOptions, terragruntConfig, config)
+		if err != nil {
+			return err
+		}
+		if err := manifest.CheckGeneratedFile(updatedTerragruntOptions, updatedTerragruntOptions.WorkingDir, config); err != nil {
+			return err
+		}
+		if written[name], err = codegen.WriteToFile(updatedTerragruntOptions, updatedTerragruntOptions.WorkingDir, config); err != nil {
+			return err
+		}
+	}
+	if err := manifest.UpdateGeneratedFiles(updatedTerragruntOptions, updatedTerragruntOptions.WorkingDir, terragruntConfig.GenerateConfigs, written); err != nil {
+		return err
+	}
+	if terragruntConfig.ProviderMatrix != nil {
+		if err := terragruntConfig.ProviderMatrix.GenerateTerraformCode(updatedTerragruntOptions); err != nil {
+			return err
+		}
+	}
+	if terragruntConfig.ProviderVersionOverrides != nil {
+		if err := terragruntConfig.ProviderVersionOverrides.GenerateTerraformCode(updatedTerragruntOptions); err != nil {
 			return err
 		}
 	}
@@ -244,6 +313,11 @@ func generateConfig(terragruntConfig *config.TerragruntConfig, updatedTerragrunt
 // This function takes in the "original" terragrunt options which has the unmodified 'WorkingDir' from before downloading the code from the source URL,
 // and the "updated" terragrunt options that will contain the updated 'WorkingDir' into which the code has been downloaded
 func runTerragruntWithConfig(ctx context.Context, originalTerragruntOptions *options.TerragruntOptions, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, target *Target) error {
+	terragruntConfig, err := withCLIExtraArgs(terragruntOptions, terragruntConfig)
+	if err != nil {
+		return err
+	}
+
 	// Add extra_arguments to the command
 	if terragruntConfig.Terraform != nil && terragruntConfig.Terraform.ExtraArgs != nil && len(terragruntConfig.Terraform.ExtraArgs) > 0 {
 		args := filterTerraformExtraArgs(terragruntOptions, terragruntConfig)
@@ -253,10 +327,27 @@ func runTerragruntWithConfig(ctx context.Context, originalTerragruntOptions *opt
 		}
 	}
 
+	setTerraformParallelism(terragruntOptions, terragruntConfig)
+
+	if err := setStateLock(terragruntOptions, terragruntConfig); err != nil {
+		return err
+	}
+
 	if err := setTerragruntInputsAsEnvVars(terragruntOptions, terragruntConfig); err != nil {
 		return err
 	}
 
+	if err := setNetworkConfig(terragruntOptions, terragruntConfig); err != nil {
+		return err
+	}
+
+	if err := setRemoteStateEncryption(ctx, terragruntOptions, terragruntConfig); err != nil {
+		return err
+	}
+
+	ctx, finishExecutor := withTerraformExecutor(ctx, terragruntOptions, terragruntConfig)
+	defer finishExecutor()
+
 	if util.FirstArg(terragruntOptions.TerraformCliArgs) == terraform.CommandNameInit {
 		if err := prepareInitCommand(ctx, terragruntOptions, terragruntConfig); err != nil {
 			return err
@@ -288,8 +379,27 @@ func runTerragruntWithConfig(ctx context.Context, originalTerragruntOptions *opt
 		return err
 	}
 
-	return runActionWithHooks(ctx, "terraform", terragruntOptions, terragruntConfig, func(ctx context.Context) error {
-		runTerraformError := runTerraformWithRetry(ctx, terragruntOptions)
+	if err := checkReadOnlyModule(terragruntOptions, terragruntConfig); err != nil {
+		return err
+	}
+
+	if err := requestApproval(ctx, terragruntOptions, terragruntConfig); err != nil {
+		return err
+	}
+
+	err = runActionWithHooks(ctx, "terraform", terragruntOptions, terragruntConfig, func(ctx context.Context) error {
+		var runTerraformError error
+		if runsInTerraformCloud(terragruntConfig) {
+			runTerraformError = runTerraformWithRetry(ctx, terragruntOptions)
+		} else if isGatedApply(terragruntOptions) {
+			runTerraformError = runGatedApply(ctx, terragruntOptions)
+		} else if isRefreshWithReport(terragruntOptions) {
+			runTerraformError = runRefreshWithReport(ctx, terragruntOptions)
+		} else if isPlanWithSummary(terragruntOptions) {
+			runTerraformError = runPlanWithSummary(ctx, terragruntOptions)
+		} else {
+			runTerraformError = runTerraformWithRetry(ctx, terragruntOptions)
+		}
 
 		var lockFileError error
 		if shouldCopyLockFile(terragruntOptions.TerraformCliArgs) {
@@ -305,6 +415,21 @@ func runTerragruntWithConfig(ctx context.Context, originalTerragruntOptions *opt
 
 		return multierror.Append(runTerraformError, lockFileError).ErrorOrNil()
 	})
+	if err != nil {
+		return err
+	}
+
+	if shouldExportOutputs(terragruntOptions, terragruntConfig) {
+		if err := exportOutputs(ctx, terragruntOptions, terragruntConfig); err != nil {
+			return err
+		}
+	}
+
+	// Record what the module deployed for the snapshot manifest of the run-all apply
+	if terragruntOptions.SnapshotRunDir != "" && util.FirstArg(terragruntOptions.TerraformCliArgs) == terraform.CommandNameApply {
+		return recordSnapshotModule(ctx, terragruntOptions, terragruntConfig)
+	}
+	return nil
 }
 
 // confirmActionWithDependentModules - Show warning with list of dependent modules from current module before destroy
@@ -403,12 +528,15 @@ func setTerragruntInputsAsEnvVars(terragruntOptions *options.TerragruntOptions,
 func runTerraformWithRetry(ctx context.Context, terragruntOptions *options.TerragruntOptions) error {
 	// Retry the command configurable time with sleep in between
 	for i := 0; i < terragruntOptions.RetryMaxAttempts; i++ {
-		if out, err := shell.RunTerraformCommandWithOutput(ctx, terragruntOptions, terragruntOptions.TerraformCliArgs...); err != nil {
+		if out, err := runTerraformWithStateLockRetry(ctx, terragruntOptions); err != nil {
 			if out == nil || !isRetryable(terragruntOptions, out) {
 				terragruntOptions.Logger.Errorf("%s invocation failed in %s", terragruntOptions.TerraformImplementation, terragruntOptions.WorkingDir)
 				return err
 			} else {
 				terragruntOptions.Logger.Infof("Encountered an error eligible for retrying. Sleeping %v before retrying.\n", terragruntOptions.RetrySleepIntervalSec)
+				if terragruntOptions.Retries != nil {
+					terragruntOptions.Retries.Add(1)
+				}
 				select {
 				case <-ctx.Done():
 					return ctx.Err()
@@ -436,7 +564,18 @@ func isRetryable(opts *options.TerragruntOptions, out *shell.CmdOutput) bool {
 // Prepare for running 'terraform init' by initializing remote state storage and adding backend configuration arguments
 // to the TerraformCliArgs
 func prepareInitCommand(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
-	if terragruntConfig.RemoteState != nil {
+	if terragruntOptions.Offline {
+		if remoteState := terragruntConfig.RemoteState; remoteState != nil {
+			if err := terragruntOptions.CheckOfflineFetch("the state of the "+remoteState.Backend+" backend", remoteState.EndpointHost()); err != nil {
+				return err
+			}
+		}
+		if err := terraform.CheckOfflineModules(terragruntOptions); err != nil {
+			return err
+		}
+	}
+
+	if terragruntConfig.RemoteState != nil && !collections.ListContainsElement(terragruntOptions.TerraformCliArgs, terraform.FlagNameBackendFalse) {
 		// Initialize the remote state if necessary  (e.g. create S3 bucket and DynamoDB table)
 		remoteStateNeedsInit, err := remoteStateNeedsInit(terragruntConfig.RemoteState, terragruntOptions)
 		if err != nil {
@@ -506,7 +645,8 @@ func checkTerraformCodeDefinesBackend(terragruntOptions *options.TerragruntOptio
 	return errors.WithStackTrace(BackendNotDefined{Opts: terragruntOptions, BackendType: backendType})
 }
 
-// Prepare for running any command other than 'terraform init' by running 'terraform init' if necessary
+// Prepare for running any command other than 'terraform init' by running 'terraform init' if necessary, and by
+// selecting the workspace of the config
 // This function takes in the "original" terragrunt options which has the unmodified 'WorkingDir' from before downloading the code from the source URL,
 // and the "updated" terragrunt options that will contain the updated 'WorkingDir' into which the code has been downloaded
 func prepareNonInitCommand(ctx context.Context, originalTerragruntOptions *options.TerragruntOptions, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
@@ -520,7 +660,7 @@ func prepareNonInitCommand(ctx context.Context, originalTerragruntOptions *optio
 			return err
 		}
 	}
-	return nil
+	return selectWorkspace(ctx, terragruntOptions, terragruntConfig)
 }
 
 // Determines if 'terraform init' needs to be executed
@@ -541,6 +681,11 @@ func needsInit(terragruntOptions *options.TerragruntOptions, terragruntConfig *c
 		return true, nil
 	}
 
+	// The modules validated with the schema cache are initialized without their backend
+	if terragruntOptions.SchemaCache && util.FirstArg(terragruntOptions.TerraformCliArgs) == terraform.CommandNameValidate {
+		return false, nil
+	}
+
 	return remoteStateNeedsInit(terragruntConfig.RemoteState, terragruntOptions)
 }
 
@@ -569,8 +714,7 @@ func providersNeedInit(terragruntOptions *options.TerragruntOptions) bool {
 func runTerraformInit(ctx context.Context, originalTerragruntOptions *options.TerragruntOptions, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
 	// Prevent Auto-Init if the user has disabled it
 	if util.FirstArg(terragruntOptions.TerraformCliArgs) != terraform.CommandNameInit && !terragruntOptions.AutoInit {
-		terragruntOptions.Logger.Warnf("Detected that init is needed, but Auto-Init is disabled. Continuing with further actions, but subsequent terraform commands may fail.")
-		return nil
+		return terragruntOptions.Warn(options.WarningAutoInitDisabled, "Detected that init is needed, but Auto-Init is disabled. Continuing with further actions, but subsequent terraform commands may fail.")
 	}
 
 	initOptions := prepareInitOptions(terragruntOptions)
@@ -605,6 +749,11 @@ func prepareInitOptions(terragruntOptions *options.TerragruntOptions) *options.T
 		initOptions.TerraformCliArgs = append(initOptions.TerraformCliArgs, terraform.FlagNameNoColor)
 	}
 
+	// Validating the module does not need its state, so the backend, and the remote state, is not initialized
+	if terragruntOptions.SchemaCache && terraformCommand == terraform.CommandNameValidate {
+		initOptions.TerraformCliArgs = append(initOptions.TerraformCliArgs, terraform.FlagNameBackendFalse)
+	}
+
 	return initOptions
 }
 
@@ -652,8 +801,30 @@ func checkProtectedModule(terragruntOptions *options.TerragruntOptions, terragru
 	if !destroyFlag {
 		return nil
 	}
-	if terragruntConfig.PreventDestroy != nil && *terragruntConfig.PreventDestroy {
-		return errors.WithStackTrace(ModuleIsProtected{Opts: terragruntOptions})
+	if terragruntConfig.PreventDestroy == nil || !*terragruntConfig.PreventDestroy {
+		return nil
+	}
+	// The module is allowed to be destroyed only if its dir is given explicitly, so that destroying a parent dir with
+	// run-all never destroys the protected modules in it.
+	moduleDir := filepath.ToSlash(filepath.Dir(terragruntOptions.TerragruntConfigPath))
+	if util.ListContainsElement(terragruntOptions.AllowDestroyDirs, moduleDir) {
+		terragruntOptions.Logger.Warnf("Destroying the module protected by the prevent_destroy flag in %s, as allowed by --%s", terragruntOptions.TerragruntConfigPath, commands.TerragruntAllowDestroyFlagName)
+		return nil
+	}
+	return errors.WithStackTrace(ModuleIsProtected{Opts: terragruntOptions})
+}
+
+// checkReadOnlyModule checks if the command changes the infrastructure or the state of a module in the read-only mode,
+// enabled by the --terragrunt-read-only flag or the "read_only" attribute.
+func checkReadOnlyModule(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	if !terraform.IsMutatingCommand(terragruntOptions.TerraformCliArgs) {
+		return nil
+	}
+	if terragruntOptions.ReadOnly {
+		return errors.WithStackTrace(ReadOnlyCommandNotAllowed{Command: util.FirstArg(terragruntOptions.TerraformCliArgs), Source: "--" + commands.TerragruntReadOnlyFlagName})
+	}
+	if terragruntConfig.ReadOnly != nil && *terragruntConfig.ReadOnly {
+		return errors.WithStackTrace(ReadOnlyCommandNotAllowed{Command: util.FirstArg(terragruntOptions.TerraformCliArgs), Source: terragruntOptions.TerragruntConfigPath})
 	}
 	return nil
 }
@@ -663,6 +834,9 @@ func filterTerraformExtraArgs(terragruntOptions *options.TerragruntOptions, terr
 	cmd := util.FirstArg(terragruntOptions.TerraformCliArgs)
 
 	for _, arg := range terragruntConfig.Terraform.ExtraArgs {
+		if !arg.IsEnabled() {
+			continue
+		}
 		for _, arg_cmd := range arg.Commands {
 			if cmd == arg_cmd {
 				lastArg := util.LastArg(terragruntOptions.TerraformCliArgs)
@@ -703,7 +877,7 @@ func filterTerraformEnvVarsFromExtraArgs(terragruntOptions *options.TerragruntOp
 	cmd := util.FirstArg(terragruntOptions.TerraformCliArgs)
 
 	for _, arg := range terragruntConfig.Terraform.ExtraArgs {
-		if arg.EnvVars == nil {
+		if arg.EnvVars == nil || !arg.IsEnabled() {
 			continue
 		}
 		for _, argcmd := range arg.Commands {
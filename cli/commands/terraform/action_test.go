@@ -422,6 +422,13 @@ func TestFilterTerraformExtraArgs(t *testing.T) {
 			mockExtraArgs([]string{"--foo", "bar"}, []string{"plan", "destroy"}, []string{"required.tfvars"}, []string{"optional.tfvars"}),
 			[]string{},
 		},
+
+		// Disabled extra arguments
+		{
+			mockCmdOptions(t, workingDir, []string{"apply"}),
+			mockDisabledExtraArgs(mockExtraArgs([]string{"--foo", "bar"}, []string{"apply", "plan"}, []string{"required.tfvars"}, []string{})),
+			[]string{},
+		},
 	}
 	for _, testCase := range testCases {
 		config := config.TerragruntConfig{
@@ -454,6 +461,12 @@ func mockExtraArgs(arguments, commands, requiredVarFiles, optionalVarFiles []str
 	return a
 }
 
+func mockDisabledExtraArgs(extraArgs config.TerraformExtraArguments) config.TerraformExtraArguments {
+	enabled := false
+	extraArgs.Enabled = &enabled
+	return extraArgs
+}
+
 func mockOptions(t *testing.T, terragruntConfigPath string, workingDir string, terraformCliArgs []string, nonInteractive bool, terragruntSource string, ignoreDependencyErrors bool, includeExternalDependencies bool, logLevel logrus.Level, debug bool) *options.TerragruntOptions {
 	opts, err := options.NewTerragruntOptionsForTest(terragruntConfigPath)
 	if err != nil {
@@ -480,3 +493,63 @@ func createTempFile(t *testing.T) string {
 
 	return filepath.ToSlash(tmpFile.Name())
 }
+
+func TestCheckProtectedModule(t *testing.T) {
+	t.Parallel()
+
+	preventDestroy := true
+	terragruntConfig := &config.TerragruntConfig{PreventDestroy: &preventDestroy}
+
+	testCases := []struct {
+		args             []string
+		allowDestroyDirs []string
+		expectProtected  bool
+	}{
+		{[]string{"plan"}, nil, false},
+		{[]string{"destroy"}, nil, true},
+		{[]string{"apply", "-destroy"}, nil, true},
+		{[]string{"destroy"}, []string{"/live/prod"}, true},
+		{[]string{"destroy"}, []string{"/live/prod/vpc"}, false},
+	}
+
+	for _, testCase := range testCases {
+		terragruntOptions, err := options.NewTerragruntOptionsForTest("/live/prod/vpc/terragrunt.hcl")
+		require.NoError(t, err)
+		terragruntOptions.TerraformCliArgs = testCase.args
+		terragruntOptions.AllowDestroyDirs = testCase.allowDestroyDirs
+
+		err = checkProtectedModule(terragruntOptions, terragruntConfig)
+		if testCase.expectProtected {
+			assert.IsType(t, ModuleIsProtected{}, errors.Unwrap(err), "%v %v", testCase.args, testCase.allowDestroyDirs)
+		} else {
+			assert.NoError(t, err, "%v %v", testCase.args, testCase.allowDestroyDirs)
+		}
+	}
+}
+
+func TestPrepareInitOptionsWithSchemaCache(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		command      string
+		schemaCache  bool
+		expectedArgs []string
+	}{
+		{"validate", true, []string{"init", "-backend=false"}},
+		{"validate", false, []string{"init"}},
+		{"plan", true, []string{"init"}},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(fmt.Sprintf("%s-%t", testCase.command, testCase.schemaCache), func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+			require.NoError(t, err)
+			opts.TerraformCliArgs = []string{testCase.command}
+			opts.SchemaCache = testCase.schemaCache
+
+			assert.Equal(t, testCase.expectedArgs, prepareInitOptions(opts).TerraformCliArgs)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// The file, in the module download dir, where the fingerprint of the last successful apply is recorded.
+const applyFingerprintFile = ".terragrunt-apply-fingerprint"
+
+// Directories of the working dir that don't affect what an apply does.
+var applyFingerprintSkipDirs = []string{util.TerragruntCacheDir, ".terraform", ".git"}
+
+// Files written by Terragrunt itself into the working dir start with this prefix, they don't affect what an apply does.
+const terragruntMetadataFilePrefix = ".terragrunt-"
+
+// ApplyFingerprint captures everything an apply of a module depends on, so that an apply can be skipped when nothing
+// changed since the last successful one.
+type ApplyFingerprint struct {
+	ConfigHash           string `json:"config_hash"`
+	SourceHash           string `json:"source_hash"`
+	InputsHash           string `json:"inputs_hash"`
+	DependencyOutputHash string `json:"dependency_output_hash"`
+	ArgsHash             string `json:"args_hash"`
+}
+
+// computeApplyFingerprint computes the fingerprint of applying the module with the given config, after the source was
+// downloaded and the code generated into the working dir of the given options.
+func computeApplyFingerprint(originalTerragruntOptions *options.TerragruntOptions, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) (*ApplyFingerprint, error) {
+	configPaths := []string{originalTerragruntOptions.TerragruntConfigPath}
+	for _, include := range terragruntConfig.ProcessedIncludes {
+		configPaths = append(configPaths, include.Path)
+	}
+	sort.Strings(configPaths)
+
+	configHash := sha256.New()
+	for _, path := range configPaths {
+		if err := hashFile(configHash, path); err != nil {
+			return nil, err
+		}
+	}
+
+	sourceHash, err := hashDir(terragruntOptions.WorkingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, err := json.Marshal(terragruntConfig.Inputs)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	dependencyOutputHash := sha256.New()
+	for _, dependency := range terragruntConfig.TerragruntDependencies {
+		if dependency.RenderedOutputs == nil {
+			continue
+		}
+		outputs, err := ctyjson.Marshal(*dependency.RenderedOutputs, dependency.RenderedOutputs.Type())
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		fmt.Fprintf(dependencyOutputHash, "%s=%s\n", dependency.Name, outputs)
+	}
+
+	return &ApplyFingerprint{
+		ConfigHash:           fmt.Sprintf("%x", configHash.Sum(nil)),
+		SourceHash:           sourceHash,
+		InputsHash:           fmt.Sprintf("%x", sha256.Sum256(inputs)),
+		DependencyOutputHash: fmt.Sprintf("%x", dependencyOutputHash.Sum(nil)),
+		ArgsHash:             fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(terragruntOptions.TerraformCliArgs, "\x00")))),
+	}, nil
+}
+
+// readApplyFingerprint reads the fingerprint of the last successful apply, returning nil if there is none.
+func readApplyFingerprint(terragruntOptions *options.TerragruntOptions) (*ApplyFingerprint, error) {
+	path := filepath.Join(terragruntOptions.DownloadDir, applyFingerprintFile)
+	if !util.FileExists(path) {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var fingerprint ApplyFingerprint
+	if err := json.Unmarshal(content, &fingerprint); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return &fingerprint, nil
+}
+
+// writeApplyFingerprint records the fingerprint of a successful apply.
+func writeApplyFingerprint(terragruntOptions *options.TerragruntOptions, fingerprint *ApplyFingerprint) error {
+	content, err := json.MarshalIndent(fingerprint, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.MkdirAll(terragruntOptions.DownloadDir, os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(terragruntOptions.DownloadDir, applyFingerprintFile), content, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+func hashFile(writer io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	if _, err := io.Copy(writer, file); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// hashDir hashes the relative paths and the contents of all files in the given dir.
+func hashDir(dir string) (string, error) {
+	hash := sha256.New()
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if path != dir && util.ListContainsElement(applyFingerprintSkipDirs, entry.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), terragruntMetadataFilePrefix) || entry.Name() == TerragruntTFVarsFile {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close() //nolint:errcheck
+
+		fmt.Fprintf(hash, "%s\x00", filepath.ToSlash(relPath))
+		_, err = io.Copy(hash, file)
+		return err
+	})
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
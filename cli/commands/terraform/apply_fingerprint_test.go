@@ -0,0 +1,62 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFingerprint(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	configPath := filepath.Join(moduleDir, config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`inputs = { name = "test" }`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(`variable "name" {}`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	opts.WorkingDir = moduleDir
+	opts.DownloadDir = filepath.Join(moduleDir, ".terragrunt-cache")
+	opts.TerraformCliArgs = []string{"apply"}
+
+	terragruntConfig := &config.TerragruntConfig{Inputs: map[string]interface{}{"name": "test"}}
+
+	fingerprint, err := computeApplyFingerprint(opts, opts, terragruntConfig)
+	require.NoError(t, err)
+
+	lastFingerprint, err := readApplyFingerprint(opts)
+	require.NoError(t, err)
+	assert.Nil(t, lastFingerprint)
+
+	require.NoError(t, writeApplyFingerprint(opts, fingerprint))
+	lastFingerprint, err = readApplyFingerprint(opts)
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, lastFingerprint)
+
+	// Terraform and Terragrunt metadata doesn't change the fingerprint
+	require.NoError(t, os.MkdirAll(filepath.Join(moduleDir, ".terraform"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, ".terraform", "terraform.tfstate"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, ".terragrunt-source-version"), []byte(`v1`), 0644))
+	unchangedFingerprint, err := computeApplyFingerprint(opts, opts, terragruntConfig)
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, unchangedFingerprint)
+
+	// Changing the code changes the source hash
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "outputs.tf"), []byte(`output "name" { value = var.name }`), 0644))
+	changedFingerprint, err := computeApplyFingerprint(opts, opts, terragruntConfig)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint.SourceHash, changedFingerprint.SourceHash)
+	assert.Equal(t, fingerprint.InputsHash, changedFingerprint.InputsHash)
+
+	// Changing the inputs changes the inputs hash
+	terragruntConfig.Inputs["name"] = "changed"
+	changedFingerprint, err = computeApplyFingerprint(opts, opts, terragruntConfig)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint.InputsHash, changedFingerprint.InputsHash)
+}
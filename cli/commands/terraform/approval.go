@@ -0,0 +1,189 @@
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The statuses of an approval returned by the approval endpoint.
+const (
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusRejected = "rejected"
+)
+
+// How long a single request to the approval endpoint may take.
+const approvalRequestTimeout = time.Minute
+
+// approvalRequest is the body posted to the approval endpoint.
+type approvalRequest struct {
+	Module     string   `json:"module"`
+	ConfigPath string   `json:"config_path"`
+	Command    string   `json:"command"`
+	Args       []string `json:"args"`
+}
+
+// approvalResponse is the body returned by the approval endpoint. While the approval is pending, the endpoint may return
+// the URL to poll for the decision, e.g. the status of a change ticket, otherwise the request is posted again.
+type approvalResponse struct {
+	Status    string `json:"status"`
+	Message   string `json:"message"`
+	StatusURL string `json:"status_url"`
+}
+
+// requestApproval asks the endpoint of the `approval` block for the approval to run the command, waiting until the
+// approval is granted, rejected or the timeout of the block runs out.
+func requestApproval(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	approval := terragruntConfig.Approval
+	command := util.FirstArg(terragruntOptions.TerraformCliArgs)
+	if approval == nil || !approval.RequiredFor(command) {
+		return nil
+	}
+
+	timeout, err := approval.GetTimeout()
+	if err != nil {
+		return err
+	}
+	pollInterval, err := approval.GetPollInterval()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(approvalRequest{
+		Module:     filepath.Dir(terragruntOptions.TerragruntConfigPath),
+		ConfigPath: terragruntOptions.TerragruntConfigPath,
+		Command:    command,
+		Args:       terragruntOptions.TerraformCliArgs,
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	terragruntOptions.Logger.Infof("Requesting approval to run %s in %s from %s", command, terragruntOptions.WorkingDir, approval.URL)
+
+	deadline := time.Now().Add(timeout)
+	statusURL := ""
+	for {
+		var response *approvalResponse
+		if statusURL == "" {
+			response, err = sendApprovalRequest(ctx, approval, http.MethodPost, approval.URL, body)
+		} else {
+			response, err = sendApprovalRequest(ctx, approval, http.MethodGet, statusURL, nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch response.Status {
+		case ApprovalStatusApproved:
+			terragruntOptions.Logger.Infof("Approved to run %s in %s. %s", command, terragruntOptions.WorkingDir, response.Message)
+			return nil
+		case ApprovalStatusRejected:
+			return errors.WithStackTrace(ApprovalRejected{Command: command, ConfigPath: terragruntOptions.TerragruntConfigPath, Message: response.Message})
+		case ApprovalStatusPending:
+			if response.StatusURL != "" {
+				if statusURL, err = approvalStatusURL(approval.URL, response.StatusURL); err != nil {
+					return err
+				}
+			}
+		default:
+			return errors.WithStackTrace(InvalidApprovalStatus{URL: approval.URL, Status: response.Status})
+		}
+
+		if time.Now().Add(pollInterval).After(deadline) {
+			return errors.WithStackTrace(ApprovalTimedOut{Command: command, ConfigPath: terragruntOptions.TerragruntConfigPath, Timeout: timeout})
+		}
+
+		terragruntOptions.Logger.Infof("Approval to run %s in %s is pending, checking again in %s. %s", command, terragruntOptions.WorkingDir, pollInterval, response.Message)
+		select {
+		case <-ctx.Done():
+			return errors.WithStackTrace(ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// approvalStatusURL returns the given status URL returned by the approval endpoint of the given URL, resolved against
+// it. The status URL must have the scheme and the host of the endpoint, as the headers of the block, e.g. the
+// credentials of the endpoint, are sent to it too.
+func approvalStatusURL(endpointURL string, statusURL string) (string, error) {
+	endpoint, err := url.Parse(endpointURL)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	status, err := url.Parse(statusURL)
+	if err != nil {
+		return "", errors.WithStackTrace(ApprovalRequestFailed{URL: endpointURL, Err: err})
+	}
+
+	status = endpoint.ResolveReference(status)
+	if !sameOrigin(endpoint, status) {
+		return "", errors.WithStackTrace(ApprovalStatusURLNotAllowed{URL: endpointURL, StatusURL: statusURL})
+	}
+	return status.String(), nil
+}
+
+func sameOrigin(a *url.URL, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) && strings.EqualFold(a.Host, b.Host)
+}
+
+func sendApprovalRequest(ctx context.Context, approval *config.ApprovalConfig, method string, requestURL string, body []byte) (*approvalResponse, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bodyReader)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for name, value := range approval.Headers {
+		req.Header.Set(name, value)
+	}
+
+	client := &http.Client{
+		Timeout: approvalRequestTimeout,
+		// The headers are sent along on a redirect, only to the origin they were configured for
+		CheckRedirect: func(redirect *http.Request, via []*http.Request) error {
+			if !sameOrigin(via[0].URL, redirect.URL) {
+				return ApprovalStatusURLNotAllowed{URL: requestURL, StatusURL: redirect.URL.String()}
+			}
+			if len(via) >= 10 {
+				return errors.Errorf("stopped after 10 redirects")
+			}
+			return nil
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.WithStackTrace(ApprovalRequestFailed{URL: requestURL, Err: err})
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, errors.WithStackTrace(ApprovalRequestFailed{URL: requestURL, StatusCode: resp.StatusCode})
+	}
+
+	var response approvalResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, errors.WithStackTrace(ApprovalRequestFailed{URL: requestURL, Err: err})
+	}
+	return &response, nil
+}
@@ -0,0 +1,153 @@
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestRequestApproval(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		statuses      []string
+		args          []string
+		timeout       string
+		expectedCalls int32
+		expectedErr   error
+	}{
+		{"approved", []string{ApprovalStatusApproved}, []string{"apply"}, "1m", 1, nil},
+		{"pending then approved", []string{ApprovalStatusPending, ApprovalStatusPending, ApprovalStatusApproved}, []string{"apply"}, "1m", 3, nil},
+		{"rejected", []string{ApprovalStatusRejected}, []string{"destroy"}, "1m", 1, ApprovalRejected{}},
+		{"timed out", []string{ApprovalStatusPending}, []string{"apply"}, "30ms", 2, ApprovalTimedOut{}},
+		{"not required", nil, []string{"plan"}, "1m", 0, nil},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "secret", r.Header.Get("Authorization"))
+
+				call := atomic.AddInt32(&calls, 1)
+				status := testCase.statuses[len(testCase.statuses)-1]
+				if int(call) <= len(testCase.statuses) {
+					status = testCase.statuses[call-1]
+				}
+				if call == 1 {
+					var request approvalRequest
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+					assert.Equal(t, testCase.args[0], request.Command)
+				}
+				assert.NoError(t, json.NewEncoder(w).Encode(approvalResponse{Status: status}))
+			}))
+			defer server.Close()
+
+			terragruntOptions, err := options.NewTerragruntOptionsForTest("/tmp/module/terragrunt.hcl")
+			require.NoError(t, err)
+			terragruntOptions.TerraformCliArgs = testCase.args
+
+			pollInterval := "20ms"
+			terragruntConfig := &config.TerragruntConfig{Approval: &config.ApprovalConfig{
+				URL:          server.URL,
+				Headers:      map[string]string{"Authorization": "secret"},
+				Timeout:      &testCase.timeout,
+				PollInterval: &pollInterval,
+			}}
+
+			err = requestApproval(context.Background(), terragruntOptions, terragruntConfig)
+			if testCase.expectedErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.IsType(t, testCase.expectedErr, errors.Unwrap(err))
+			}
+			assert.Equal(t, testCase.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestRequestApprovalStatusURL(t *testing.T) {
+	t.Parallel()
+
+	var otherOriginCalls int32
+	otherOrigin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&otherOriginCalls, 1)
+		assert.NoError(t, json.NewEncoder(w).Encode(approvalResponse{Status: ApprovalStatusApproved}))
+	}))
+	// Once the parallel subtests are done
+	t.Cleanup(func() {
+		otherOrigin.Close()
+		assert.Equal(t, int32(0), atomic.LoadInt32(&otherOriginCalls))
+	})
+
+	testCases := []struct {
+		name        string
+		statusURL   string
+		expectedErr error
+	}{
+		{"relative", "/status/123", nil},
+		{"same origin", "{endpoint}/status/123", nil},
+		{"other origin", otherOrigin.URL + "/status/123", ApprovalStatusURLNotAllowed{}},
+		{"redirect to other origin", "/redirect", ApprovalRequestFailed{}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+				switch r.URL.Path {
+				case "/status/123":
+					assert.NoError(t, json.NewEncoder(w).Encode(approvalResponse{Status: ApprovalStatusApproved}))
+				case "/redirect":
+					http.Redirect(w, r, otherOrigin.URL+"/status/123", http.StatusFound)
+				default:
+					statusURL := strings.ReplaceAll(testCase.statusURL, "{endpoint}", server.URL)
+					assert.NoError(t, json.NewEncoder(w).Encode(approvalResponse{Status: ApprovalStatusPending, StatusURL: statusURL}))
+				}
+			}))
+			defer server.Close()
+
+			terragruntOptions, err := options.NewTerragruntOptionsForTest("/tmp/module/terragrunt.hcl")
+			require.NoError(t, err)
+			terragruntOptions.TerraformCliArgs = []string{"apply"}
+
+			timeout, pollInterval := "1m", "1ms"
+			terragruntConfig := &config.TerragruntConfig{Approval: &config.ApprovalConfig{
+				URL:          server.URL + "/approvals",
+				Headers:      map[string]string{"X-Api-Key": "secret"},
+				Timeout:      &timeout,
+				PollInterval: &pollInterval,
+			}}
+
+			err = requestApproval(context.Background(), terragruntOptions, terragruntConfig)
+			if testCase.expectedErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.IsType(t, testCase.expectedErr, errors.Unwrap(err))
+			}
+		})
+	}
+}
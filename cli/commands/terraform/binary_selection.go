@@ -0,0 +1,187 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The names of the terraform and tofu binaries, which may be suffixed with their version, e.g. terraform-1.5.7.
+var terraformBinaryNames = []string{"terraform", "tofu"}
+
+// terraformBinaryVersions caches the versions of the binaries found, by path, so that every binary is only run once with
+// --version when the binaries are selected for all the modules of a stack.
+var terraformBinaryVersions = sync.Map{}
+
+// terraformBinary is a terraform or tofu binary found locally.
+type terraformBinary struct {
+	Path           string
+	Version        *version.Version
+	Implementation options.TerraformImplementationType
+}
+
+// isAutoTerraformBinary returns true if the terraform binary is selected per module, i.e. if the selection is enabled and
+// neither the CLI nor the config sets the binary.
+func isAutoTerraformBinary(terragruntOptions *options.TerragruntOptions, terraformBinary string) bool {
+	return terragruntOptions.AutoTerraformBinary && terragruntOptions.TerraformPath == options.DefaultWrappedPath && terraformBinary == ""
+}
+
+// selectTerraformBinary selects the newest terraform or tofu binary matching both the required_version of the terraform
+// code of the module and the terraform_version_constraint of its config, and sets it on the given options. It is called
+// once the terraform code is downloaded into the working dir of the updated options.
+func selectTerraformBinary(ctx context.Context, terragruntOptions *options.TerragruntOptions, updatedTerragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	module, err := config.LoadTerraformModule(updatedTerragruntOptions.WorkingDir)
+	if err != nil {
+		return err
+	}
+
+	constraintStrs := append([]string{}, module.RequiredCore...)
+	if terragruntConfig.TerraformVersionConstraint != "" {
+		constraintStrs = append(constraintStrs, terragruntConfig.TerraformVersionConstraint)
+	} else {
+		constraintStrs = append(constraintStrs, DefaultTerraformVersionConstraint)
+	}
+
+	var constraints []version.Constraints
+	for _, constraintStr := range constraintStrs {
+		constraint, err := version.NewConstraint(constraintStr)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		constraints = append(constraints, constraint)
+	}
+
+	var (
+		selected *terraformBinary
+		found    []string
+	)
+	for _, path := range findTerraformBinaries(terragruntOptions) {
+		binary, err := getTerraformBinary(ctx, terragruntOptions, path)
+		if err != nil {
+			terragruntOptions.Logger.Debugf("Ignoring the binary %s, as its version could not be determined: %v", path, err)
+			continue
+		}
+		found = append(found, binary.String())
+
+		if binary.matches(constraints) && (selected == nil || binary.Version.GreaterThan(selected.Version)) {
+			selected = binary
+		}
+	}
+
+	if selected == nil {
+		return errors.WithStackTrace(NoMatchingTerraformBinary{Constraints: constraintStrs, Binaries: found})
+	}
+
+	terragruntOptions.Logger.Infof("Selected %s for the module %s", selected, terragruntOptions.TerragruntConfigPath)
+	for _, opts := range []*options.TerragruntOptions{terragruntOptions, updatedTerragruntOptions} {
+		opts.TerraformPath = selected.Path
+		opts.TerraformVersion = selected.Version
+		opts.TerraformImplementation = selected.Implementation
+	}
+	return nil
+}
+
+func (binary *terraformBinary) String() string {
+	return string(binary.Implementation) + " v" + binary.Version.String() + " (" + binary.Path + ")"
+}
+
+func (binary *terraformBinary) matches(constraints []version.Constraints) bool {
+	for _, constraint := range constraints {
+		if !constraint.Check(binary.Version) {
+			return false
+		}
+	}
+	return true
+}
+
+// getTerraformBinary returns the binary at the given path, with its version.
+func getTerraformBinary(ctx context.Context, terragruntOptions *options.TerragruntOptions, path string) (*terraformBinary, error) {
+	if binary, ok := terraformBinaryVersions.Load(path); ok {
+		return binary.(*terraformBinary), nil
+	}
+
+	terragruntOptionsCopy := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	terragruntOptionsCopy.TerraformPath = path
+	if err := PopulateTerraformVersion(ctx, terragruntOptionsCopy); err != nil {
+		return nil, err
+	}
+
+	binary := &terraformBinary{Path: path, Version: terragruntOptionsCopy.TerraformVersion, Implementation: terragruntOptionsCopy.TerraformImplementation}
+	terraformBinaryVersions.Store(path, binary)
+	return binary, nil
+}
+
+// findTerraformBinaries returns the paths of the terraform and tofu binaries in the binary dirs and the PATH, in that
+// order, each binary once even if linked from several dirs.
+func findTerraformBinaries(terragruntOptions *options.TerragruntOptions) []string {
+	var dirs []string
+	for _, pattern := range terragruntOptions.TerraformBinaryDirs {
+		if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(pattern, "~/") {
+			pattern = filepath.Join(home, pattern[2:])
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			terragruntOptions.Logger.Warnf("Ignoring the invalid binary dir pattern %s: %v", pattern, err)
+			continue
+		}
+		dirs = append(dirs, matches...)
+	}
+
+	pathEnv, ok := terragruntOptions.Env["PATH"]
+	if !ok {
+		pathEnv = os.Getenv("PATH")
+	}
+	dirs = append(dirs, filepath.SplitList(pathEnv)...)
+
+	var paths []string
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !isTerraformBinaryName(entry.Name()) {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			info, err := os.Stat(path)
+			if err != nil || !info.Mode().IsRegular() || (runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0) {
+				continue
+			}
+
+			realPath, err := filepath.EvalSymlinks(path)
+			if err != nil {
+				realPath = path
+			}
+			if !seen[realPath] {
+				seen[realPath] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	return paths
+}
+
+// isTerraformBinaryName returns true for the names terraform and tofu, optionally suffixed with a version, e.g.
+// terraform-1.5.7 or tofu_1.6.2.
+func isTerraformBinaryName(name string) bool {
+	name = strings.TrimSuffix(name, ".exe")
+	for _, binaryName := range terraformBinaryNames {
+		if name == binaryName || strings.HasPrefix(name, binaryName+"-") || strings.HasPrefix(name, binaryName+"_") {
+			return true
+		}
+	}
+	return false
+}
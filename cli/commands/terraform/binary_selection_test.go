@@ -0,0 +1,87 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectTerraformBinary(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake binaries are shell scripts")
+	}
+
+	binDir := t.TempDir()
+	for name, output := range map[string]string{
+		"terraform-1.5.7": "Terraform v1.5.7",
+		"terraform-1.3.9": "Terraform v1.3.9",
+		"tofu":            "OpenTofu v1.7.2",
+		"terraform-docs":  "not a terraform binary",
+	} {
+		script := fmt.Sprintf("#!/bin/sh\necho '%s'\n", output)
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, name), []byte(script), 0755))
+	}
+
+	testCases := []struct {
+		name              string
+		requiredVersion   string
+		versionConstraint string
+		expectedPath      string
+		expectedErr       bool
+	}{
+		{name: "newest", expectedPath: "tofu"},
+		{name: "required version of the code", requiredVersion: "~> 1.5.0", expectedPath: "terraform-1.5.7"},
+		{name: "required version and constraint of the config", requiredVersion: ">= 1.0", versionConstraint: "< 1.5", expectedPath: "terraform-1.3.9"},
+		{name: "no matching binary", requiredVersion: ">= 2.0", expectedErr: true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			workingDir := t.TempDir()
+			if testCase.requiredVersion != "" {
+				code := fmt.Sprintf("terraform {\n  required_version = %q\n}\n", testCase.requiredVersion)
+				require.NoError(t, os.WriteFile(filepath.Join(workingDir, "main.tf"), []byte(code), 0644))
+			}
+
+			opts, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, config.DefaultTerragruntConfigPath))
+			require.NoError(t, err)
+			opts.WorkingDir = workingDir
+			opts.AutoTerraformBinary = true
+			opts.Env = map[string]string{"PATH": binDir}
+
+			err = selectTerraformBinary(context.Background(), opts, opts, &config.TerragruntConfig{TerraformVersionConstraint: testCase.versionConstraint})
+			if testCase.expectedErr {
+				var noMatch NoMatchingTerraformBinary
+				require.ErrorAs(t, errors.Unwrap(err), &noMatch)
+				assert.Len(t, noMatch.Binaries, 3)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(binDir, testCase.expectedPath), opts.TerraformPath)
+		})
+	}
+}
+
+func TestIsTerraformBinaryName(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isTerraformBinaryName("terraform"))
+	assert.True(t, isTerraformBinaryName("tofu_1.6.2"))
+	assert.True(t, isTerraformBinaryName("terraform-1.5.7.exe"))
+	assert.False(t, isTerraformBinaryName("terraformer"))
+	assert.False(t, isTerraformBinaryName("tflint"))
+}
@@ -0,0 +1,66 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The prefix of the names of the extra_arguments passed via --terragrunt-extra-args, followed by their index.
+const cliExtraArgsNamePrefix = "cli-"
+
+// ParseCLIExtraArgs parses the given values of --terragrunt-extra-args, in the form <commands>:<argument>, where the
+// commands are comma separated, e.g. `plan,apply:-lock-timeout=5m`, into extra_arguments.
+func ParseCLIExtraArgs(values []string) ([]config.TerraformExtraArguments, error) {
+	var extraArgs []config.TerraformExtraArguments
+	for i, value := range values {
+		cmds, arg, found := strings.Cut(value, ":")
+		if !found || arg == "" {
+			return nil, errors.WithStackTrace(InvalidCLIExtraArgs(value))
+		}
+
+		var commandNames []string
+		for _, cmd := range strings.Split(cmds, ",") {
+			if cmd = strings.TrimSpace(cmd); cmd != "" {
+				commandNames = append(commandNames, cmd)
+			}
+		}
+		if len(commandNames) == 0 {
+			return nil, errors.WithStackTrace(InvalidCLIExtraArgs(value))
+		}
+
+		arguments := []string{arg}
+		extraArgs = append(extraArgs, config.TerraformExtraArguments{
+			Name:      fmt.Sprintf("%s%d", cliExtraArgsNamePrefix, i),
+			Commands:  commandNames,
+			Arguments: &arguments,
+		})
+	}
+	return extraArgs, nil
+}
+
+// withCLIExtraArgs returns the given config with the extra_arguments passed via --terragrunt-extra-args added after
+// the extra_arguments of the config, so that they are passed to terraform after them. The config itself is not
+// updated, as it is used for every command run on the module, e.g. the auto init.
+func withCLIExtraArgs(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) (*config.TerragruntConfig, error) {
+	if len(terragruntOptions.ExtraArgs) == 0 {
+		return terragruntConfig, nil
+	}
+
+	cliExtraArgs, err := ParseCLIExtraArgs(terragruntOptions.ExtraArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	configCopy := *terragruntConfig
+	terraformConfig := config.TerraformConfig{}
+	if terragruntConfig.Terraform != nil {
+		terraformConfig = *terragruntConfig.Terraform
+	}
+	terraformConfig.ExtraArgs = append(append([]config.TerraformExtraArguments{}, terraformConfig.ExtraArgs...), cliExtraArgs...)
+	configCopy.Terraform = &terraformConfig
+	return &configCopy, nil
+}
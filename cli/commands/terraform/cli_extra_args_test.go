@@ -0,0 +1,59 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestParseCLIExtraArgs(t *testing.T) {
+	t.Parallel()
+
+	extraArgs, err := ParseCLIExtraArgs([]string{"plan:-refresh=false", "plan, apply:-lock-timeout=5m", "apply:-var=tags={a = 1}"})
+	require.NoError(t, err)
+	require.Len(t, extraArgs, 3)
+	assert.Equal(t, []string{"plan"}, extraArgs[0].Commands)
+	assert.Equal(t, []string{"-refresh=false"}, *extraArgs[0].Arguments)
+	assert.Equal(t, []string{"plan", "apply"}, extraArgs[1].Commands)
+	assert.Equal(t, []string{"-lock-timeout=5m"}, *extraArgs[1].Arguments)
+	assert.Equal(t, []string{"-var=tags={a = 1}"}, *extraArgs[2].Arguments)
+
+	for _, value := range []string{"-refresh=false", "plan:", ":-refresh=false", " , :-refresh=false"} {
+		_, err := ParseCLIExtraArgs([]string{value})
+		var invalid InvalidCLIExtraArgs
+		require.ErrorAs(t, errors.Unwrap(err), &invalid, value)
+	}
+}
+
+func TestWithCLIExtraArgs(t *testing.T) {
+	t.Parallel()
+
+	configArgs := []string{"-lock=false"}
+	terragruntConfig := &config.TerragruntConfig{
+		Terraform: &config.TerraformConfig{
+			ExtraArgs: []config.TerraformExtraArguments{{Name: "lock", Commands: []string{"plan"}, Arguments: &configArgs}},
+		},
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+	require.NoError(t, err)
+	opts.TerraformCliArgs = []string{"plan"}
+	opts.ExtraArgs = []string{"plan:-refresh=false", "apply:-lock-timeout=5m"}
+
+	withArgs, err := withCLIExtraArgs(opts, terragruntConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-lock=false", "-refresh=false"}, filterTerraformExtraArgs(opts, withArgs))
+
+	// The config itself is not updated
+	assert.Len(t, terragruntConfig.Terraform.ExtraArgs, 1)
+
+	// The extra arguments are added to the configs without a terraform block too
+	withArgs, err = withCLIExtraArgs(opts, &config.TerragruntConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"-refresh=false"}, filterTerraformExtraArgs(opts, withArgs))
+}
@@ -0,0 +1,124 @@
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// confirmEachLock makes sure that the prompts of modules running concurrently in run-all are not interleaved.
+var confirmEachLock sync.Mutex
+
+// The answers to the prompt for confirming the changes of a module.
+const (
+	confirmAnswerApprove = "approve"
+	confirmAnswerSkip    = "skip"
+	confirmAnswerAbort   = "abort"
+)
+
+// planJson is the subset of the `terraform show -json` output of a plan used for summarizing it.
+type planJson struct {
+	ResourceChanges []struct {
+		Address string `json:"address"`
+		Change  struct {
+			Actions []string `json:"actions"`
+		} `json:"change"`
+	} `json:"resource_changes"`
+}
+
+// shouldConfirmEach returns true if the user must confirm the changes of each module before they are applied.
+func shouldConfirmEach(terragruntOptions *options.TerragruntOptions) bool {
+	return terragruntOptions.ConfirmEach && !terragruntOptions.NonInteractive
+}
+
+// confirmPlan shows a summary of the given plan and asks the user whether to apply it, skip the module or abort the
+// whole run. For skip and abort, the matching configstack error is returned, so that run-all handles the dependents.
+func confirmPlan(ctx context.Context, terragruntOptions *options.TerragruntOptions, planFile string) error {
+	showOptions := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	showOptions.WorkingDir = terragruntOptions.WorkingDir
+
+	out, err := shell.RunShellCommandWithOutput(ctx, showOptions, "", true, false, terragruntOptions.TerraformPath, "show", "-json", planFile)
+	if err != nil {
+		return err
+	}
+
+	summary, err := summarizePlan([]byte(out.Stdout))
+	if err != nil {
+		return err
+	}
+
+	confirmEachLock.Lock()
+	defer confirmEachLock.Unlock()
+
+	prompt := fmt.Sprintf("\nModule %s has changes:\n%s\nApply these changes? (approve/skip/abort) ", terragruntOptions.WorkingDir, summary)
+	for {
+		answer, err := shell.PromptUserForInput(prompt, terragruntOptions)
+		if err != nil {
+			return err
+		}
+
+		switch strings.ToLower(answer) {
+		case confirmAnswerApprove, "a", "yes", "y":
+			return nil
+		case confirmAnswerSkip, "s":
+			terragruntOptions.Logger.Warnf("Skipping module %s and the modules that depend on it.", terragruntOptions.WorkingDir)
+			return errors.WithStackTrace(configstack.ModuleSkippedByUser{})
+		case confirmAnswerAbort:
+			return errors.WithStackTrace(configstack.RunAbortedByUser{})
+		default:
+			prompt = "Please answer approve, skip or abort: "
+		}
+	}
+}
+
+// summarizePlan lists the resource changes of the given `terraform show -json` plan, followed by the counts of
+// resources to add, change and destroy like `terraform plan` prints them.
+func summarizePlan(showJson []byte) (string, error) {
+	lines, summary, err := planResourceChanges(showJson)
+	if err != nil {
+		return "", err
+	}
+
+	lines = append(lines, fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", summary.Add, summary.Change, summary.Destroy))
+	return strings.Join(lines, "\n"), nil
+}
+
+// planResourceChanges returns the sorted resource changes of the given `terraform show -json` plan, with the counts of
+// resources to add, change and destroy.
+func planResourceChanges(showJson []byte) ([]string, options.PlanSummary, error) {
+	var plan planJson
+	if err := json.Unmarshal(showJson, &plan); err != nil {
+		return nil, options.PlanSummary{}, errors.WithStackTrace(err)
+	}
+
+	var lines []string
+	var summary options.PlanSummary
+	for _, resourceChange := range plan.ResourceChanges {
+		actions := strings.Join(resourceChange.Change.Actions, ",")
+		switch actions {
+		case "no-op", "read":
+			continue
+		case "create":
+			summary.Add++
+		case "update":
+			summary.Change++
+		case "delete":
+			summary.Destroy++
+		case "delete,create", "create,delete":
+			summary.Add++
+			summary.Destroy++
+			actions = "replace"
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", resourceChange.Address, actions))
+	}
+	sort.Strings(lines)
+	return lines, summary, nil
+}
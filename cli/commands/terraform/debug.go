@@ -1,6 +1,7 @@
 package terraform
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,7 +9,9 @@ import (
 	"strings"
 
 	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/cli/commands"
 	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/encryption"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/terraform"
 	"github.com/gruntwork-io/terragrunt/util"
@@ -19,8 +22,9 @@ const TerragruntTFVarsFile = "terragrunt-debug.tfvars.json"
 const defaultPermissions = int(0600)
 
 // WriteTerragruntDebugFile will create a tfvars file that can be used to invoke the terraform module in the same way
-// that terragrunt invokes the module, so that you can debug issues with the terragrunt config.
-func WriteTerragruntDebugFile(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+// that terragrunt invokes the module, so that you can debug issues with the terragrunt config. The inputs containing the
+// values of sensitive outputs of the dependencies are encrypted if a key is configured for the sensitive outputs.
+func WriteTerragruntDebugFile(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
 	terragruntOptions.Logger.Infof(
 		"Debug mode requested: generating debug file %s in working dir %s",
 		TerragruntTFVarsFile,
@@ -36,7 +40,7 @@ func WriteTerragruntDebugFile(terragruntOptions *options.TerragruntOptions, terr
 	terragruntOptions.Logger.Debugf("The following variables were detected in the terraform module:")
 	terragruntOptions.Logger.Debugf("%v", variables)
 
-	fileContents, err := terragruntDebugFileContents(terragruntOptions, terragruntConfig, variables)
+	fileContents, err := terragruntDebugFileContents(ctx, terragruntOptions, terragruntConfig, variables)
 	if err != nil {
 		return err
 	}
@@ -58,10 +62,49 @@ func WriteTerragruntDebugFile(terragruntOptions *options.TerragruntOptions, terr
 	return nil
 }
 
+// encryptSensitiveInputs replaces the values of the inputs containing the values of sensitive outputs of the
+// dependencies with their JSON encoding encrypted, which `terragrunt decrypt` decrypts with the same key.
+func encryptSensitiveInputs(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, jsonValuesByKey map[string]interface{}) error {
+	var sensitiveInputs []string
+	for _, name := range config.SensitiveInputs(terragruntOptions, terragruntConfig) {
+		if _, ok := jsonValuesByKey[name]; ok {
+			sensitiveInputs = append(sensitiveInputs, name)
+		}
+	}
+	if len(sensitiveInputs) == 0 {
+		return nil
+	}
+
+	encrypter, err := encryption.NewEncrypter(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	if encrypter == nil {
+		terragruntOptions.Logger.Warnf(
+			"The inputs %s of the debug file contain the values of sensitive dependency outputs in plaintext. Pass --%s or --%s to encrypt them.",
+			strings.Join(sensitiveInputs, ", "), commands.TerragruntSensitiveOutputsKeyFlagName, commands.TerragruntSensitiveOutputsKMSKeyIDFlagName,
+		)
+		return nil
+	}
+
+	for _, name := range sensitiveInputs {
+		plaintext, err := json.Marshal(jsonValuesByKey[name])
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if jsonValuesByKey[name], err = encryption.EncryptValue(ctx, encrypter, plaintext); err != nil {
+			return err
+		}
+	}
+	terragruntOptions.Logger.Debugf("The inputs %s of the debug file are encrypted, as they contain the values of sensitive dependency outputs", strings.Join(sensitiveInputs, ", "))
+	return nil
+}
+
 // terragruntDebugFileContents will return a tfvars file in json format of all the terragrunt rendered variables values
 // that should be set to invoke the terraform module in the same way as terragrunt. Note that this will only include the
 // values of variables that are actually defined in the module.
 func terragruntDebugFileContents(
+	ctx context.Context,
 	terragruntOptions *options.TerragruntOptions,
 	terragruntConfig *config.TerragruntConfig,
 	moduleVariables []string,
@@ -95,6 +138,9 @@ func terragruntDebugFileContents(
 			)
 		}
 	}
+	if err := encryptSensitiveInputs(ctx, terragruntOptions, terragruntConfig, jsonValuesByKey); err != nil {
+		return nil, err
+	}
 	jsonContent, err := json.MarshalIndent(jsonValuesByKey, "", "  ")
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
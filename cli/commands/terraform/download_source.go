@@ -13,6 +13,7 @@ import (
 	"github.com/gruntwork-io/terragrunt/cli/commands"
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remotecache"
 	"github.com/gruntwork-io/terragrunt/terraform"
 	"github.com/gruntwork-io/terragrunt/util"
 )
@@ -74,6 +75,7 @@ func downloadTerraformSourceIfNecessary(ctx context.Context, terraformSource *te
 	if err != nil {
 		return err
 	}
+	util.CountCacheLookup(util.SourceCache, alreadyLatest)
 
 	if alreadyLatest {
 		if err := validateWorkingDir(terraformSource); err != nil {
@@ -99,7 +101,7 @@ func downloadTerraformSourceIfNecessary(ctx context.Context, terraformSource *te
 	terragruntOptionsForDownload := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
 	terragruntOptionsForDownload.TerraformCommand = terraform.CommandNameInitFromModule
 	downloadErr := runActionWithHooks(ctx, "download source", terragruntOptionsForDownload, terragruntConfig, func(ctx context.Context) error {
-		return downloadSource(terraformSource, terragruntOptions, terragruntConfig)
+		return downloadSource(ctx, terraformSource, terragruntOptions, terragruntConfig)
 	})
 
 	if downloadErr != nil {
@@ -179,6 +181,7 @@ func readVersionFile(terraformSource *terraform.Source) (string, error) {
 //   - Local file path getter is updated to copy the files instead of creating symlinks, which is what go-getter defaults
 //     to.
 //   - Include the customized getter for fetching sources from the Terraform Registry.
+//   - The HTTP and Terraform Registry getters use the proxies, CA bundle and module mirrors of the network block.
 //
 // This creates a closure that returns a function so that we have access to the terragrunt configuration, which is
 // necessary for customizing the behavior of the file getter.
@@ -202,23 +205,140 @@ func updateGetters(terragruntOptions *options.TerragruntOptions, terragruntConfi
 		}
 
 		// Load in custom getters that are only supported in Terragrunt
-		client.Getters["tfr"] = &terraform.RegistryGetter{
+		registryGetter := &terraform.RegistryGetter{
 			TerragruntOptions: terragruntOptions,
 		}
+		client.Getters["tfr"] = registryGetter
+
+		// Download through the proxies and with the mirrors of the network block
+		if network := terragruntConfig.Network; network != nil {
+			httpClient, err := network.HTTPClient()
+			if err != nil {
+				return err
+			}
+			client.Getters["http"] = &getter.HttpGetter{Netrc: true, Client: httpClient}
+			client.Getters["https"] = &getter.HttpGetter{Netrc: true, Client: httpClient}
+			registryGetter.HTTPClient = httpClient
+			registryGetter.ModuleMirrors = network.ModuleMirrors
+		}
 
 		return nil
 	}
 }
 
-// Download the code from the Canonical Source URL into the Download Folder using the go-getter library
-func downloadSource(terraformSource *terraform.Source, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
-	terragruntOptions.Logger.Infof("Downloading Terraform configurations from %s into %s", terraformSource.CanonicalSourceURL, terraformSource.DownloadDir)
+// Download the code from the Canonical Source URL into the Download Folder using the go-getter library. The failed
+// downloads are retried, and then the download mirrors are tried in order. With --terragrunt-remote-cache, the pinned
+// sources are fetched from the remote cache if it has them, and stored in it otherwise, once verified.
+func downloadSource(ctx context.Context, terraformSource *terraform.Source, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	// The registry modules are downloaded into a temp dir first, to verify their checksum before using them
+	downloadDir := terraformSource.DownloadDir
+	if terraformSource.CanonicalSourceURL.Scheme == "tfr" {
+		tempDir, err := os.MkdirTemp("", "terragrunt-registry-module-")
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		defer os.RemoveAll(tempDir) //nolint:errcheck
+		downloadDir = filepath.Join(tempDir, "module")
+	}
+
+	verificationRule := terragruntConfig.SourceVerification.Rule(terraformSource.CanonicalSourceURL.String())
 
-	if err := getter.GetAny(terraformSource.DownloadDir, terraformSource.CanonicalSourceURL.String(), updateGetters(terragruntOptions, terragruntConfig)); err != nil {
-		return errors.WithStackTrace(err)
+	remoteCache, err := remotecache.FromOptions(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	var remoteCacheKey string
+	if remoteCache != nil {
+		remoteCacheKey = sourceRemoteCacheKey(downloadDir, terraformSource, verificationRule)
 	}
 
-	return nil
+	// The source fetched from the remote cache is verified as the source downloaded
+	fromRemoteCache := remoteCacheKey != "" && fetchSourceFromRemoteCache(ctx, remoteCache, remoteCacheKey, downloadDir, terraformSource, terragruntOptions)
+	if !fromRemoteCache {
+		if err := fetchSource(ctx, downloadDir, terraformSource, verificationRule, terragruntOptions, terragruntConfig); err != nil {
+			return err
+		}
+	}
+
+	if verificationRule != nil && verificationRule.Method == config.SourceVerificationMethodGit {
+		if err := verifyGitSource(ctx, downloadDir, terraformSource, verificationRule, terragruntOptions); err != nil {
+			// Don't leave the unverified code around
+			if removeErr := os.RemoveAll(downloadDir); removeErr != nil {
+				terragruntOptions.Logger.Warnf("Failed to remove %s: %v", downloadDir, removeErr)
+			}
+			return err
+		}
+	}
+
+	if downloadDir != terraformSource.DownloadDir {
+		if err := verifySourceChecksum(downloadDir, terraformSource, terragruntOptions, terragruntConfig); err != nil {
+			return err
+		}
+	}
+
+	if remoteCacheKey != "" && !fromRemoteCache {
+		storeSourceInRemoteCache(ctx, remoteCache, remoteCacheKey, downloadDir, terraformSource, terragruntOptions)
+	}
+
+	if downloadDir == terraformSource.DownloadDir {
+		return nil
+	}
+	// The manifest removes the files of the previous version of the module
+	return util.CopyFolderContentsWithFilter(downloadDir, terraformSource.DownloadDir, registryModuleManifestFile, func(path string) bool { return true })
+}
+
+// fetchSource downloads the source from its URL, or from its download mirrors, into the given dir.
+func fetchSource(ctx context.Context, downloadDir string, terraformSource *terraform.Source, verificationRule *config.SourceVerificationRule, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	downloadRetry := terragruntOptions.DownloadRetry()
+
+	var urls []string
+	var offlineErr error
+	for _, sourceURL := range downloadRetry.URLs(terraformSource.CanonicalSourceURL.String()) {
+		// The registry getter checks the registry and the download URL of the module itself
+		if !strings.HasPrefix(sourceURL, "tfr://") {
+			if err := terragruntOptions.CheckOfflineFetch("the terraform source "+sourceURL, options.FetchHost(sourceURL)); err != nil {
+				if offlineErr == nil {
+					offlineErr = err
+				}
+				continue
+			}
+		}
+		urls = append(urls, sourceURL)
+	}
+	if len(urls) == 0 {
+		return offlineErr
+	}
+
+	attempt := 0
+	return downloadRetry.Do(ctx, "Downloading the terraform source", urls, func(sourceURL string) error {
+		attempt++
+		if attempt > 1 {
+			// Start over, rather than updating a partial download
+			if err := os.RemoveAll(downloadDir); err != nil {
+				return util.FatalError{Underlying: errors.WithStackTrace(err)}
+			}
+		}
+
+		terragruntOptions.Logger.Infof("Downloading Terraform configurations from %s into %s", sourceURL, terraformSource.DownloadDir)
+
+		if verificationRule != nil && verificationRule.Method == config.SourceVerificationMethodCosign {
+			return downloadCosignVerifiedSource(ctx, downloadDir, sourceURL, verificationRule, terragruntOptions, terragruntConfig)
+		}
+		err := getter.GetAny(downloadDir, sourceURL, updateGetters(terragruntOptions, terragruntConfig))
+		return downloadAttemptError(err, terraform.IsLocalSource(terraformSource.CanonicalSourceURL))
+	})
+}
+
+// downloadAttemptError returns the given error of a download attempt as a FatalError, which is not retried, unless the
+// error is a network error of a remote source.
+func downloadAttemptError(err error, isLocalSource bool) error {
+	if err == nil {
+		return nil
+	}
+	if isLocalSource || !util.IsTransientDownloadError(err) {
+		return util.FatalError{Underlying: errors.WithStackTrace(err)}
+	}
+	return errors.WithStackTrace(err)
 }
 
 // Check if working terraformSource.WorkingDir exists and is directory
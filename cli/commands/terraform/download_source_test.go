@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/go-commons/env"
 	"github.com/gruntwork-io/go-commons/errors"
@@ -258,6 +259,37 @@ func TestDownloadTerraformSourceIfNecessaryInvalidTerraformSource(t *testing.T)
 	assert.True(t, ok)
 }
 
+func TestFetchSourceInvalidSourceFailsOnFirstAttempt(t *testing.T) {
+	t.Parallel()
+
+	downloadDir := tmpDir(t)
+	defer os.Remove(downloadDir)
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("./should-not-be-used")
+	require.NoError(t, err)
+	// A retry would outlast the context
+	terragruntOptions.DownloadMaxAttempts = 3
+	terragruntOptions.DownloadRetryIntervalSec = 60
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, canonicalUrl := range []string{
+		fmt.Sprintf("file://%s", absPath(t, "../../../test/fixture-download-source/does-not-exist")),
+		"notarealgetter::https://example.com/module.zip",
+	} {
+		terraformSource := &terraform.Source{
+			CanonicalSourceURL: parseUrl(t, canonicalUrl),
+			DownloadDir:        downloadDir,
+			WorkingDir:         downloadDir,
+			Logger:             terragruntOptions.Logger,
+		}
+		err := fetchSource(ctx, downloadDir, terraformSource, nil, terragruntOptions, &config.TerragruntConfig{})
+		require.Error(t, err, canonicalUrl)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded, canonicalUrl)
+	}
+}
+
 func TestInvalidModulePath(t *testing.T) {
 	t.Parallel()
 
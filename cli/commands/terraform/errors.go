@@ -2,8 +2,11 @@ package terraform
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/gruntwork-io/terragrunt/cli/commands"
 	"github.com/gruntwork-io/terragrunt/options"
 )
 
@@ -41,7 +44,77 @@ type ModuleIsProtected struct {
 }
 
 func (err ModuleIsProtected) Error() string {
-	return fmt.Sprintf("Module is protected by the prevent_destroy flag in %s. Set it to false or delete it to allow destroying of the module.", err.Opts.TerragruntConfigPath)
+	return fmt.Sprintf("Module is protected by the prevent_destroy flag in %s. Set it to false or delete it, or pass --%s %s, to allow destroying of the module.", err.Opts.TerragruntConfigPath, commands.TerragruntAllowDestroyFlagName, filepath.Dir(err.Opts.TerragruntConfigPath))
+}
+
+func (err ModuleIsProtected) PolicyViolation() {}
+
+type ReadOnlyCommandNotAllowed struct {
+	Command string
+	Source  string
+}
+
+func (err ReadOnlyCommandNotAllowed) Error() string {
+	return fmt.Sprintf("The command %s changes the infrastructure or the state, which is not allowed by the read-only mode of %s.", err.Command, err.Source)
+}
+
+func (err ReadOnlyCommandNotAllowed) PolicyViolation() {}
+
+type ApprovalRejected struct {
+	Command    string
+	ConfigPath string
+	Message    string
+}
+
+func (err ApprovalRejected) Error() string {
+	msg := fmt.Sprintf("The approval to run %s, required by the approval block in %s, was rejected.", err.Command, err.ConfigPath)
+	if err.Message != "" {
+		msg += " " + err.Message
+	}
+	return msg
+}
+
+func (err ApprovalRejected) PolicyViolation() {}
+
+type ApprovalTimedOut struct {
+	Command    string
+	ConfigPath string
+	Timeout    time.Duration
+}
+
+func (err ApprovalTimedOut) Error() string {
+	return fmt.Sprintf("Timed out after %s waiting for the approval to run %s, required by the approval block in %s.", err.Timeout, err.Command, err.ConfigPath)
+}
+
+type ApprovalRequestFailed struct {
+	URL        string
+	StatusCode int
+	Err        error
+}
+
+func (err ApprovalRequestFailed) Error() string {
+	if err.Err != nil {
+		return fmt.Sprintf("Failed to request the approval from %s: %v", err.URL, err.Err)
+	}
+	return fmt.Sprintf("Failed to request the approval from %s: the endpoint returned HTTP status %d", err.URL, err.StatusCode)
+}
+
+type ApprovalStatusURLNotAllowed struct {
+	URL       string
+	StatusURL string
+}
+
+func (err ApprovalStatusURLNotAllowed) Error() string {
+	return fmt.Sprintf("The approval endpoint %s returned the status URL %s of another origin, to which the headers of the approval block are not sent. The status URL must have the scheme and the host of the endpoint.", err.URL, err.StatusURL)
+}
+
+type InvalidApprovalStatus struct {
+	URL    string
+	Status string
+}
+
+func (err InvalidApprovalStatus) Error() string {
+	return fmt.Sprintf("The approval endpoint %s returned the unknown status %q, must be one of: %s, %s, %s", err.URL, err.Status, ApprovalStatusApproved, ApprovalStatusPending, ApprovalStatusRejected)
 }
 
 type MaxRetriesExceeded struct {
@@ -51,3 +124,134 @@ type MaxRetriesExceeded struct {
 func (err MaxRetriesExceeded) Error() string {
 	return fmt.Sprintf("Exhausted retries (%v) for command %v %v", err.Opts.RetryMaxAttempts, err.Opts.TerraformPath, strings.Join(err.Opts.TerraformCliArgs, " "))
 }
+
+type SourceChecksumMismatch struct {
+	Source     string
+	Checksum   string
+	Expected   string
+	ExpectedBy string
+}
+
+func (err SourceChecksumMismatch) Error() string {
+	return fmt.Sprintf("The registry module %s was downloaded with the checksum %s, but %s expects %s. Its content changed in the registry for the same version; if the change is expected, update the checksum.", err.Source, err.Checksum, err.ExpectedBy, err.Expected)
+}
+
+func (err SourceChecksumMismatch) PolicyViolation() {}
+
+type SourceVerificationNotSupported struct {
+	Source string
+	Method string
+	Reason string
+}
+
+func (err SourceVerificationNotSupported) Error() string {
+	return fmt.Sprintf("The source_verification block requires the %s verification of %s, but %s.", err.Method, err.Source, err.Reason)
+}
+
+func (err SourceVerificationNotSupported) PolicyViolation() {}
+
+type SourceSignatureInvalid struct {
+	Source  string
+	Method  string
+	Details string
+}
+
+func (err SourceSignatureInvalid) Error() string {
+	return fmt.Sprintf("The %s verification of the signature of %s, required by the source_verification block, failed: %s", err.Method, err.Source, err.Details)
+}
+
+func (err SourceSignatureInvalid) PolicyViolation() {}
+
+type SourceSignerNotAllowed struct {
+	Source     string
+	Signers    []string
+	Identities []string
+}
+
+func (err SourceSignerNotAllowed) Error() string {
+	return fmt.Sprintf("The source %s is signed by %s, but the source_verification block requires a signature by one of: %s", err.Source, strings.Join(err.Signers, ", "), strings.Join(err.Identities, ", "))
+}
+
+func (err SourceSignerNotAllowed) PolicyViolation() {}
+
+type GenerateSourceChecksumMismatch struct {
+	Source   string
+	Checksum string
+	Expected string
+}
+
+func (err GenerateSourceChecksumMismatch) Error() string {
+	return fmt.Sprintf("The generate source %s was fetched with the checksum %s, but the generate block expects %s. If the change is expected, update the checksum.", err.Source, err.Checksum, err.Expected)
+}
+
+func (err GenerateSourceChecksumMismatch) PolicyViolation() {}
+
+type GenerateSourceNotAFile struct {
+	Source string
+}
+
+func (err GenerateSourceNotAFile) Error() string {
+	return fmt.Sprintf("The generate source %s does not point to a file. Set the path of the file in the source after a double slash, e.g. git::https://example.com/templates.git//provider.tf?ref=v1.0.0", err.Source)
+}
+
+type NoMatchingTerraformBinary struct {
+	Constraints []string
+	Binaries    []string
+}
+
+func (err NoMatchingTerraformBinary) Error() string {
+	if len(err.Binaries) == 0 {
+		return fmt.Sprintf("No terraform or tofu binary found in the PATH and the binary dirs (--%s) to match the version constraints %s.", commands.TerragruntTerraformBinaryDirFlagName, strings.Join(err.Constraints, ", "))
+	}
+	return fmt.Sprintf("None of the binaries found matches the version constraints %s: %s", strings.Join(err.Constraints, ", "), strings.Join(err.Binaries, ", "))
+}
+
+type InvalidCLIExtraArgs string
+
+func (value InvalidCLIExtraArgs) Error() string {
+	return fmt.Sprintf("Invalid value %q of --%s, expected <commands>:<argument>, e.g. plan,apply:-lock-timeout=5m", string(value), commands.TerragruntExtraArgsFlagName)
+}
+
+type ExportOutputsFailed struct {
+	Name string
+	Err  error
+}
+
+func (err ExportOutputsFailed) Error() string {
+	return fmt.Sprintf("The module was applied, but publishing its outputs with the export_outputs block %q failed: %v", err.Name, err.Err)
+}
+
+func (err ExportOutputsFailed) Unwrap() error {
+	return err.Err
+}
+
+type InvalidState struct {
+	Err error
+}
+
+func (err InvalidState) Error() string {
+	return fmt.Sprintf("Failed to read the state of the module to report the resources the refresh updated: %v", err.Err)
+}
+
+func (err InvalidState) Unwrap() error {
+	return err.Err
+}
+
+type AWSProfileNotFound struct {
+	Profile    string
+	AWSProfile string
+	Files      []string
+}
+
+func (err AWSProfileNotFound) Error() string {
+	return fmt.Sprintf("The AWS profile %q of the profile %q is defined in none of the AWS shared config files %s", err.AWSProfile, err.Profile, strings.Join(err.Files, ", "))
+}
+
+type ProfileCredentialsNotFound struct {
+	Profile string
+	Path    string
+}
+
+func (err ProfileCredentialsNotFound) Error() string {
+	return fmt.Sprintf("The GCP credentials file %s of the profile %q does not exist", err.Path, err.Profile)
+}
@@ -0,0 +1,136 @@
+package terraform
+
+import (
+	"context"
+	"io"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/tfcloud"
+)
+
+// withTerraformExecutor returns the context the terraform commands of the module run with, so that they run with the
+// backend selected in the `execution` block, if any, and the function to call once the module is done.
+func withTerraformExecutor(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) (context.Context, func()) {
+	executor := newTerraformExecutor(terragruntConfig)
+	if executor == nil {
+		return ctx, func() {}
+	}
+
+	terragruntOptions.Logger.Debugf("Running the terraform commands of %s with %s", terragruntOptions.WorkingDir, executor)
+	closeExecutor := func() {
+		if closer, ok := executor.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				terragruntOptions.Logger.Errorf("Failed to finish the %s: %v", executor, err)
+			}
+		}
+	}
+	return shell.ContextWithTerraformExecutor(ctx, executor), closeExecutor
+}
+
+// newTerraformExecutor creates the executor of the execution block of the given config, or returns nil to run the
+// commands locally. The block is already validated when the config is parsed.
+func newTerraformExecutor(terragruntConfig *config.TerragruntConfig) shell.TerraformExecutor {
+	execution := terragruntConfig.Execution
+	if execution == nil {
+		return nil
+	}
+
+	switch execution.Backend {
+	case config.ExecutionBackendSSH:
+		return newSSHExecutor(execution.SSH)
+	case config.ExecutionBackendKubernetes:
+		return newKubernetesExecutor(execution.Kubernetes)
+	case config.ExecutionBackendTerraformCloud:
+		return newTerraformCloudExecutor(execution.TerraformCloud, terragruntConfig.Inputs)
+	default:
+		return nil
+	}
+}
+
+// runsInTerraformCloud returns true if the module runs in a Terraform Cloud workspace, whose runs plan and apply in one
+// go, without the saved plans the gated applies and the plan summaries need.
+func runsInTerraformCloud(terragruntConfig *config.TerragruntConfig) bool {
+	return terragruntConfig.Execution != nil && terragruntConfig.Execution.Backend == config.ExecutionBackendTerraformCloud
+}
+
+func newSSHExecutor(ssh *config.SSHExecutionConfig) *shell.SSHExecutor {
+	executor := &shell.SSHExecutor{
+		Host:       ssh.Host,
+		ForwardEnv: ssh.ForwardEnv,
+		ExtraArgs:  ssh.ExtraArgs,
+	}
+	if ssh.User != nil {
+		executor.User = *ssh.User
+	}
+	if ssh.Port != nil {
+		executor.Port = *ssh.Port
+	}
+	if ssh.IdentityFile != nil {
+		executor.IdentityFile = *ssh.IdentityFile
+	}
+	if ssh.RemoteDir != nil {
+		executor.RemoteDir = *ssh.RemoteDir
+	}
+	if ssh.TerraformPath != nil {
+		executor.TerraformPath = *ssh.TerraformPath
+	}
+	return executor
+}
+
+func newKubernetesExecutor(kubernetes *config.KubernetesExecutionConfig) *shell.KubernetesExecutor {
+	executor := &shell.KubernetesExecutor{
+		Image:      kubernetes.Image,
+		Requests:   kubernetes.Requests,
+		Limits:     kubernetes.Limits,
+		Labels:     kubernetes.Labels,
+		ForwardEnv: kubernetes.ForwardEnv,
+	}
+	if kubernetes.Namespace != nil {
+		executor.Namespace = *kubernetes.Namespace
+	}
+	if kubernetes.Context != nil {
+		executor.Context = *kubernetes.Context
+	}
+	if kubernetes.ServiceAccount != nil {
+		executor.ServiceAccount = *kubernetes.ServiceAccount
+	}
+	if kubernetes.TerraformPath != nil {
+		executor.TerraformPath = *kubernetes.TerraformPath
+	}
+	executor.ActiveDeadline, _ = kubernetes.GetActiveDeadline()
+	executor.TTLAfterFinished, _ = kubernetes.GetTTLAfterFinished()
+	return executor
+}
+
+func newTerraformCloudExecutor(terraformCloud *config.TerraformCloudExecutionConfig, inputs map[string]interface{}) *shell.TerraformCloudExecutor {
+	executor := &shell.TerraformCloudExecutor{
+		Organization:    terraformCloud.Organization,
+		Workspace:       terraformCloud.Workspace,
+		Tags:            terraformCloud.Tags,
+		Inputs:          inputs,
+		SensitiveInputs: terraformCloud.SensitiveInputs,
+	}
+	if terraformCloud.Hostname != nil {
+		executor.Hostname = *terraformCloud.Hostname
+	}
+	if terraformCloud.ProjectID != nil {
+		executor.ProjectID = *terraformCloud.ProjectID
+	}
+	if terraformCloud.TerraformVersion != nil {
+		executor.TerraformVersion = *terraformCloud.TerraformVersion
+	}
+	if terraformCloud.WorkingDirectory != nil {
+		executor.WorkingDirectory = *terraformCloud.WorkingDirectory
+	}
+	if vcsRepo := terraformCloud.VCSRepo; vcsRepo != nil {
+		executor.VCSRepo = &tfcloud.VCSRepo{Identifier: vcsRepo.Identifier, OAuthTokenID: vcsRepo.OAuthTokenID}
+		if vcsRepo.Branch != nil {
+			executor.VCSRepo.Branch = *vcsRepo.Branch
+		}
+	}
+	executor.Timeout, _ = terraformCloud.GetTimeout()
+	executor.PollInterval, _ = terraformCloud.GetPollInterval()
+	return executor
+}
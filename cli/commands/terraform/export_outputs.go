@@ -0,0 +1,48 @@
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/outputstore"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// shouldExportOutputs returns true if the command applies the module, and the config publishes its outputs.
+func shouldExportOutputs(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) bool {
+	args := terragruntOptions.TerraformCliArgs
+	return len(terragruntConfig.ExportOutputs) > 0 && util.FirstArg(args) == terraform.CommandNameApply && !util.ListContainsElement(args, "-destroy")
+}
+
+// exportOutputs publishes the outputs of the applied module to the stores of the export_outputs blocks of its config.
+func exportOutputs(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	output, err := shell.RunShellCommandWithOutput(ctx, quietOptions(terragruntOptions), "", true, false, terragruntOptions.TerraformPath, terraform.CommandNameOutput, "-json")
+	if err != nil {
+		return err
+	}
+
+	var outputs map[string]outputstore.Output
+	if err := json.Unmarshal([]byte(output.Stdout), &outputs); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	for _, block := range terragruntConfig.ExportOutputs {
+		store, err := outputstore.New(block.StoreConfig(), terragruntOptions)
+		if err != nil {
+			return errors.WithStackTrace(ExportOutputsFailed{Name: block.Name, Err: err})
+		}
+
+		keys, err := outputstore.Publish(ctx, store, block.Key, outputs, block.Outputs)
+		if err != nil {
+			return errors.WithStackTrace(ExportOutputsFailed{Name: block.Name, Err: err})
+		}
+		terragruntOptions.Logger.Infof("Published the outputs of the export_outputs block %q to %s: %v", block.Name, block.Backend, keys)
+	}
+	return nil
+}
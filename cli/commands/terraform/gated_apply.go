@@ -0,0 +1,126 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The plan file, in the working dir, of a plan-gated apply.
+const gatedApplyPlanFile = "terragrunt-gated-apply.tfplan"
+
+// The `apply` flags that are still allowed when applying a saved plan. Variables and planning options (e.g. `-target`)
+// are already part of the plan.
+var applySavedPlanFlags = []string{
+	"-auto-approve",
+	"-backup",
+	"-compact-warnings",
+	"-input",
+	"-json",
+	"-lock",
+	"-lock-timeout",
+	"-no-color",
+	"-parallelism",
+	"-state",
+	"-state-out",
+}
+
+// isGatedApply returns true if the apply should be gated by a plan, which is only done if the apply is not already
+// given a plan file.
+func isGatedApply(terragruntOptions *options.TerragruntOptions) bool {
+	args := terragruntOptions.TerraformCliArgs
+	return (terragruntOptions.PlanGatedApply || shouldConfirmEach(terragruntOptions)) &&
+		util.FirstArg(args) == terraform.CommandNameApply &&
+		(len(args) == 1 || !util.IsFile(util.LastArg(args)))
+}
+
+// runGatedApply runs `terraform plan -detailed-exitcode` first and only applies the saved plan if it has changes.
+func runGatedApply(ctx context.Context, terragruntOptions *options.TerragruntOptions) error {
+	planFile := filepath.Join(terragruntOptions.WorkingDir, gatedApplyPlanFile)
+	defer func() {
+		if err := os.Remove(planFile); err != nil && !os.IsNotExist(err) {
+			terragruntOptions.Logger.Debugf("Failed to remove plan file %s: %v", planFile, err)
+		}
+	}()
+
+	planOptions := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	planOptions.WorkingDir = terragruntOptions.WorkingDir
+	planOptions.TerraformCommand = terraform.CommandNamePlan
+	planOptions.TerraformCliArgs = gatedPlanArgs(terragruntOptions.TerraformCliArgs, planFile)
+
+	err := runTerraformWithRetry(ctx, planOptions)
+	if err == nil {
+		terragruntOptions.Logger.Infof("The plan of module %s has no changes, skipping apply.", terragruntOptions.WorkingDir)
+		return nil
+	}
+	if exitCode, exitCodeErr := shell.GetExitCode(err); exitCodeErr != nil || exitCode != terraform.DetailedExitCodeChanges {
+		return err
+	}
+
+	if shouldConfirmEach(terragruntOptions) {
+		if err := confirmPlan(ctx, terragruntOptions, planFile); err != nil {
+			return err
+		}
+	}
+
+	terragruntOptions.Logger.Infof("The plan of module %s has changes, applying it.", terragruntOptions.WorkingDir)
+
+	applyOptions := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	applyOptions.WorkingDir = terragruntOptions.WorkingDir
+	applyOptions.TerraformCliArgs = gatedApplyArgs(terragruntOptions.TerraformCliArgs, planFile)
+
+	return runTerraformWithRetry(ctx, applyOptions)
+}
+
+// gatedPlanArgs converts the args of `terraform apply` to the args of the plan that gates it.
+func gatedPlanArgs(applyArgs []string, planFile string) []string {
+	planArgs := []string{terraform.CommandNamePlan}
+	for _, arg := range applyArgs[1:] {
+		if flagName(arg) == "-auto-approve" {
+			continue
+		}
+		planArgs = append(planArgs, arg)
+	}
+	return append(planArgs, terraform.FlagNameDetailedExitCode, "-out="+planFile)
+}
+
+// gatedApplyArgs converts the args of `terraform apply` to the args that apply the saved plan.
+func gatedApplyArgs(applyArgs []string, planFile string) []string {
+	args := []string{terraform.CommandNameApply}
+	for i := 1; i < len(applyArgs); i++ {
+		arg := applyArgs[i]
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		if !util.ListContainsElement(applySavedPlanFlags, flagName(arg)) {
+			// Also skip the value of flags given as `-var foo=bar`
+			if !strings.Contains(arg, "=") && i+1 < len(applyArgs) && !strings.HasPrefix(applyArgs[i+1], "-") && isFlagWithValue(arg) {
+				i++
+			}
+			continue
+		}
+		args = append(args, arg)
+	}
+	return append(args, planFile)
+}
+
+// flagName returns the name of the given flag, normalizing `--flag` to `-flag` and stripping the `=value` part.
+func flagName(arg string) string {
+	name := strings.SplitN(arg, "=", 2)[0]
+	if strings.HasPrefix(name, "--") {
+		name = name[1:]
+	}
+	return name
+}
+
+// isFlagWithValue returns true for the planning flags of `terraform apply` that take a separate value.
+func isFlagWithValue(arg string) bool {
+	return util.ListContainsElement([]string{"-var", "-var-file", "-target", "-replace"}, flagName(arg))
+}
@@ -0,0 +1,57 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatedApplyArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		applyArgs         []string
+		expectedPlanArgs  []string
+		expectedApplyArgs []string
+	}{
+		{
+			[]string{"apply"},
+			[]string{"plan", "-detailed-exitcode", "-out=plan.tfplan"},
+			[]string{"apply", "plan.tfplan"},
+		},
+		{
+			[]string{"apply", "-input=false", "-auto-approve", "-lock-timeout=5m"},
+			[]string{"plan", "-input=false", "-lock-timeout=5m", "-detailed-exitcode", "-out=plan.tfplan"},
+			[]string{"apply", "-input=false", "-auto-approve", "-lock-timeout=5m", "plan.tfplan"},
+		},
+		{
+			[]string{"apply", "-var", "foo=bar", "-var-file=prod.tfvars", "--target=aws_instance.web", "-no-color"},
+			[]string{"plan", "-var", "foo=bar", "-var-file=prod.tfvars", "--target=aws_instance.web", "-no-color", "-detailed-exitcode", "-out=plan.tfplan"},
+			[]string{"apply", "-no-color", "plan.tfplan"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedPlanArgs, gatedPlanArgs(testCase.applyArgs, "plan.tfplan"))
+		assert.Equal(t, testCase.expectedApplyArgs, gatedApplyArgs(testCase.applyArgs, "plan.tfplan"))
+	}
+}
+
+func TestSummarizePlan(t *testing.T) {
+	t.Parallel()
+
+	showJson := `{"resource_changes": [
+		{"address": "aws_instance.web", "change": {"actions": ["update"]}},
+		{"address": "aws_s3_bucket.logs", "change": {"actions": ["create"]}},
+		{"address": "aws_iam_role.app", "change": {"actions": ["delete", "create"]}},
+		{"address": "aws_vpc.main", "change": {"actions": ["no-op"]}}
+	]}`
+
+	summary, err := summarizePlan([]byte(showJson))
+	require.NoError(t, err)
+	assert.Equal(t, `  aws_iam_role.app: replace
+  aws_instance.web: update
+  aws_s3_bucket.logs: create
+Plan: 2 to add, 1 to change, 1 to destroy.`, summary)
+}
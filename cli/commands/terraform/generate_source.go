@@ -0,0 +1,191 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-getter"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The dir, in the terragrunt cache dir, the pinned generate sources are cached in.
+const generateSourceCacheDir = "generate"
+
+// generateSourceLocks keeps track of the locks of the cached generate sources, as the modules of a stack run
+// concurrently may share the same sources.
+var generateSourceLocks = sync.Map{}
+
+// resolveGenerateSource sets the contents of the given generate config to the contents fetched from its source, if
+// set, and verifies their checksum.
+func resolveGenerateSource(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, genConfig codegen.GenerateConfig) (codegen.GenerateConfig, error) {
+	if genConfig.Source == "" || genConfig.Disable {
+		return genConfig, nil
+	}
+
+	contents, err := fetchGenerateSource(terragruntOptions, terragruntConfig, genConfig.Source, genConfig.Checksum)
+	if err != nil {
+		return genConfig, err
+	}
+	if genConfig.Checksum != "" {
+		if checksum := generateSourceChecksum(contents); !checksumMatches(checksum, genConfig.Checksum) {
+			return genConfig, errors.WithStackTrace(GenerateSourceChecksumMismatch{Source: genConfig.Source, Checksum: checksum, Expected: genConfig.Checksum})
+		}
+	}
+
+	genConfig.Contents = string(contents)
+	return genConfig, nil
+}
+
+// fetchGenerateSource returns the contents of the file at the given source. The remote sources pinned to a ref or a
+// version, or with a checksum, are cached in the terragrunt cache dir and only fetched once; the others are fetched on
+// every run, as their contents may change.
+func fetchGenerateSource(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, source string, checksum string) ([]byte, error) {
+	// The dir of the source is fetched, and the file is read from its subdir, e.g. `git::https://...//provider.tf?ref=v1.0.0`
+	sourceDir, subdir := getter.SourceDirSubdir(source)
+	host := options.FetchHost(sourceDir)
+
+	if host == "" || (checksum == "" && !isPinnedSource(sourceDir)) {
+		tempDir, err := os.MkdirTemp("", "terragrunt-generate-")
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		defer os.RemoveAll(tempDir) //nolint:errcheck
+
+		fetchDir := filepath.Join(tempDir, "source")
+		if err := fetchGenerateSourceDir(terragruntOptions, terragruntConfig, sourceDir, host, fetchDir); err != nil {
+			return nil, err
+		}
+		return readGenerateSourceFile(source, fetchDir, subdir)
+	}
+
+	cacheDir, err := util.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cacheDir = filepath.Join(cacheDir, generateSourceCacheDir, util.EncodeBase64Sha1(sourceDir))
+
+	rawLock, _ := generateSourceLocks.LoadOrStore(cacheDir, &sync.Mutex{})
+	lock := rawLock.(*sync.Mutex)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if util.IsDir(cacheDir) {
+		// The cached source is fetched again if it does not match the checksum, e.g. if it was changed in place
+		contents, err := readGenerateSourceFile(source, cacheDir, subdir)
+		if err == nil && (checksum == "" || checksumMatches(generateSourceChecksum(contents), checksum)) {
+			terragruntOptions.Logger.Debugf("Using the cached generate source %s in %s", source, cacheDir)
+			return contents, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cacheDir), os.ModePerm); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	// The source is fetched into a temporary dir first, so that a failed fetch never leaves a partial source in the cache
+	tempDir, err := os.MkdirTemp(filepath.Dir(cacheDir), filepath.Base(cacheDir)+"-")
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer os.RemoveAll(tempDir) //nolint:errcheck
+
+	fetchDir := filepath.Join(tempDir, "source")
+	if err := fetchGenerateSourceDir(terragruntOptions, terragruntConfig, sourceDir, host, fetchDir); err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if err := os.Rename(fetchDir, cacheDir); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return readGenerateSourceFile(source, cacheDir, subdir)
+}
+
+func fetchGenerateSourceDir(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, sourceDir string, host string, dst string) error {
+	if err := terragruntOptions.CheckOfflineFetch("the generate source "+sourceDir, host); err != nil {
+		return err
+	}
+	terragruntOptions.Logger.Debugf("Fetching the generate source %s", sourceDir)
+
+	client := &getter.Client{
+		Src:  sourceDir,
+		Dst:  dst,
+		Pwd:  filepath.Dir(terragruntOptions.TerragruntConfigPath),
+		Mode: getter.ClientModeAny,
+		Options: []getter.ClientOption{
+			updateGetters(terragruntOptions, terragruntConfig),
+		},
+	}
+	if err := client.Get(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// readGenerateSourceFile reads the file at the given subdir of the fetched source, or the only file of the fetched
+// source without a subdir, e.g. for a file fetched over http.
+func readGenerateSourceFile(source string, dir string, subdir string) ([]byte, error) {
+	path := filepath.Join(dir, subdir)
+	if !util.HasPathPrefix(path, dir) {
+		return nil, errors.WithStackTrace(GenerateSourceNotAFile{Source: source})
+	}
+
+	if subdir == "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		var files []string
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+				files = append(files, entry.Name())
+			}
+		}
+		if len(files) != 1 || len(entries) != 1 {
+			return nil, errors.WithStackTrace(GenerateSourceNotAFile{Source: source})
+		}
+		path = filepath.Join(dir, files[0])
+	}
+
+	if !util.FileExists(path) || util.IsDir(path) {
+		return nil, errors.WithStackTrace(GenerateSourceNotAFile{Source: source})
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return contents, nil
+}
+
+// isPinnedSource returns true if the given source is pinned to a git ref or a version, which are assumed not to change.
+func isPinnedSource(source string) bool {
+	// Trim the forced getter, e.g. `git::https://github.com/...`
+	if _, forcedURL, found := strings.Cut(source, "::"); found {
+		source = forcedURL
+	}
+	parsedURL, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+	query := parsedURL.Query()
+	return query.Get("ref") != "" || query.Get("version") != ""
+}
+
+func generateSourceChecksum(contents []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(contents))
+}
+
+// checksumMatches returns true if the checksum matches the expected one, which may be prefixed with `sha256:`.
+func checksumMatches(checksum string, expected string) bool {
+	return strings.EqualFold(checksum, strings.TrimPrefix(expected, "sha256:"))
+}
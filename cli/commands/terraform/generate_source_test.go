@@ -0,0 +1,95 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveGenerateSource(t *testing.T) {
+	t.Parallel()
+
+	configDir := t.TempDir()
+	templatesDir := filepath.Join(configDir, "templates")
+	require.NoError(t, os.MkdirAll(templatesDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "provider.tf"), []byte("provider \"aws\" {}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(templatesDir, "versions.tf"), []byte("terraform {}\n"), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(configDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntConfig := &config.TerragruntConfig{}
+
+	// The checksum of other contents
+	checksum := "sha256:5e0c3cb3b2d3e1e0db5d2a5dc3a1a0d0a05bb6c1e1d3f764ebb3c55c4fc1a8df"
+	actualChecksum := generateSourceChecksum([]byte("provider \"aws\" {}\n"))
+
+	testCases := []struct {
+		name        string
+		genConfig   codegen.GenerateConfig
+		contents    string
+		expectedErr error
+	}{
+		{
+			name:      "inline contents",
+			genConfig: codegen.GenerateConfig{Path: "provider.tf", Contents: "terraform {}\n"},
+			contents:  "terraform {}\n",
+		},
+		{
+			name:      "file in the subdir of the source",
+			genConfig: codegen.GenerateConfig{Path: "provider.tf", Source: "./templates//provider.tf"},
+			contents:  "provider \"aws\" {}\n",
+		},
+		{
+			name:      "matching checksum",
+			genConfig: codegen.GenerateConfig{Path: "provider.tf", Source: "./templates//provider.tf", Checksum: actualChecksum},
+			contents:  "provider \"aws\" {}\n",
+		},
+		{
+			name:        "checksum mismatch",
+			genConfig:   codegen.GenerateConfig{Path: "provider.tf", Source: "./templates//provider.tf", Checksum: checksum},
+			expectedErr: GenerateSourceChecksumMismatch{Source: "./templates//provider.tf", Checksum: actualChecksum, Expected: checksum},
+		},
+		{
+			name:        "source with several files",
+			genConfig:   codegen.GenerateConfig{Path: "provider.tf", Source: "./templates"},
+			expectedErr: GenerateSourceNotAFile{Source: "./templates"},
+		},
+		{
+			name:        "subdir outside the source",
+			genConfig:   codegen.GenerateConfig{Path: "provider.tf", Source: "./templates//../provider.tf"},
+			expectedErr: GenerateSourceNotAFile{Source: "./templates//../provider.tf"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			genConfig, err := resolveGenerateSource(opts, terragruntConfig, testCase.genConfig)
+			if testCase.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, testCase.expectedErr, errors.Unwrap(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.contents, genConfig.Contents)
+		})
+	}
+}
+
+func TestIsPinnedSource(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isPinnedSource("git::https://github.com/acme/templates.git?ref=v1.0.0"))
+	assert.True(t, isPinnedSource("https://example.com/templates.zip?version=1.0.0"))
+	assert.False(t, isPinnedSource("git::https://github.com/acme/templates.git"))
+	assert.False(t, isPinnedSource("https://example.com/provider.tf"))
+}
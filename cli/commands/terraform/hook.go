@@ -63,12 +63,11 @@ func processErrorHooks(ctx context.Context, hooks []config.ErrorHook, terragrunt
 			actionToExecute := curHook.Execute[0]
 			actionParams := curHook.Execute[1:]
 
-			_, possibleError := shell.RunShellCommandWithOutput(
+			_, possibleError := shell.RunConfigCommandWithOutput(
 				ctx,
 				terragruntOptions,
 				workingDir,
 				suppressStdout,
-				false,
 				actionToExecute, actionParams...,
 			)
 			if possibleError != nil {
@@ -136,16 +135,19 @@ func runHook(ctx context.Context, terragruntOptions *options.TerragruntOptions,
 	actionParams := curHook.Execute[1:]
 
 	if actionToExecute == "tflint" {
+		if err := shell.CheckExecPolicies(terragruntOptions, actionToExecute); err != nil {
+			terragruntOptions.Logger.Errorf("Error running hook %s with message: %s", curHook.Name, err.Error())
+			return err
+		}
 		if err := executeTFLint(ctx, terragruntOptions, terragruntConfig, curHook, workingDir); err != nil {
 			return err
 		}
 	} else {
-		_, possibleError := shell.RunShellCommandWithOutput(
+		_, possibleError := shell.RunConfigCommandWithOutput(
 			ctx,
 			terragruntOptions,
 			workingDir,
 			suppressStdout,
-			false,
 			actionToExecute, actionParams...,
 		)
 		if possibleError != nil {
@@ -0,0 +1,125 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/terraform/cliconfig"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The prefix of the CLI config files, in the download dir, with the mirrors of the network block.
+const networkCLIConfigFilePrefix = ".terraformrc-network-"
+
+// setNetworkConfig makes terraform use the proxies, CA bundle and mirrors of the `network` block, if any. The proxies
+// and the CA bundle override the env vars, so that the block applies consistently. In offline mode terraform installs
+// the providers only from the local provider dirs and the provider mirror, if its host is allowed.
+func setNetworkConfig(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	network := terragruntConfig.Network
+	if network == nil && !terragruntOptions.Offline {
+		return nil
+	}
+
+	if terragruntOptions.Env == nil {
+		terragruntOptions.Env = map[string]string{}
+	}
+	if network != nil {
+		for key, value := range network.Env() {
+			terragruntOptions.Env[key] = value
+		}
+	} else {
+		network = &config.NetworkConfig{}
+	}
+
+	if network.ProviderMirror == nil && len(network.ModuleMirrors) == 0 && !terragruntOptions.Offline {
+		return nil
+	}
+
+	if terragruntOptions.ProviderCache {
+		terragruntOptions.Logger.Warnf("The provider_mirror and module_mirrors of the network block are not used by terraform, as the Terragrunt Provider Cache is enabled")
+		return nil
+	}
+
+	cliConfigFile, err := writeNetworkCLIConfig(terragruntOptions, network)
+	if err != nil {
+		return err
+	}
+	terragruntOptions.Env[terraform.EnvNameTFCLIConfigFile] = cliConfigFile
+	return nil
+}
+
+// writeNetworkCLIConfig writes the CLI config of the user with the mirrors of the network block, returning its path.
+// The file is named after its content, so that the modules of a stack sharing the download dir don't overwrite each
+// other's.
+func writeNetworkCLIConfig(terragruntOptions *options.TerragruntOptions, network *config.NetworkConfig) (string, error) {
+	cfg, err := cliconfig.LoadUserConfig()
+	if err != nil {
+		return "", err
+	}
+
+	for host, mirror := range network.ModuleMirrors {
+		// Setting the services of a host disables their discovery, so the providers of the registry are kept
+		cfg.AddHost(host, map[string]any{
+			"modules.v1":   mirror,
+			"providers.v1": fmt.Sprintf("https://%s/v1/providers/", host),
+		})
+	}
+
+	var mirrors []string
+	if terragruntOptions.Offline {
+		providerDir, err := offlineProviderDir(terragruntOptions, cfg)
+		if err != nil {
+			return "", err
+		}
+		// Without the direct method, terraform never installs the providers from their registries
+		cfg.SetProviderInstallation(cliconfig.NewProviderInstallationFilesystemMirror(providerDir, nil, nil), nil)
+		mirrors = append(mirrors, "offline="+providerDir)
+
+		if network.ProviderMirror != nil {
+			if err := terragruntOptions.CheckOfflineFetch("the providers from the provider_mirror "+*network.ProviderMirror, options.FetchHost(*network.ProviderMirror)); err != nil {
+				return "", err
+			}
+		}
+	}
+	if network.ProviderMirror != nil {
+		cfg.SetProviderInstallationNetworkMirror(cliconfig.NewProviderInstallationNetworkMirror(*network.ProviderMirror, nil, nil))
+		mirrors = append(mirrors, *network.ProviderMirror)
+	}
+	for host, mirror := range network.ModuleMirrors {
+		mirrors = append(mirrors, host+"="+mirror)
+	}
+
+	if err := os.MkdirAll(terragruntOptions.DownloadDir, os.ModePerm); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	sort.Strings(mirrors)
+	cliConfigFile := filepath.Join(terragruntOptions.DownloadDir, networkCLIConfigFilePrefix+util.EncodeBase64Sha1(strings.Join(mirrors, "\n")))
+
+	if err := cfg.Save(cliConfigFile); err != nil {
+		return "", err
+	}
+	return cliConfigFile, nil
+}
+
+// offlineProviderDir returns the local dir terraform installs the providers from in offline mode, the first of: the
+// provider cache dir, the plugin cache dir of the env or of the user CLI config, and the user plugins dir.
+func offlineProviderDir(terragruntOptions *options.TerragruntOptions, cfg *cliconfig.Config) (string, error) {
+	if terragruntOptions.ProviderCacheDir != "" {
+		return terragruntOptions.ProviderCacheDir, nil
+	}
+	if dir := terragruntOptions.Env[terraform.EnvNameTFPluginCacheDir]; dir != "" {
+		return dir, nil
+	}
+	if cfg.PluginCacheDir != "" {
+		return cfg.PluginCacheDir, nil
+	}
+	return cliconfig.UserProviderDir()
+}
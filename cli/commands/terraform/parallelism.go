@@ -0,0 +1,29 @@
+package terraform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The commands throttled by the terraform_parallelism of the config.
+var terraformParallelismCommands = []string{terraform.CommandNamePlan, terraform.CommandNameApply, terraform.CommandNameDestroy}
+
+// setTerraformParallelism passes the terraform_parallelism of the config to terraform as `-parallelism`, for the plan,
+// apply and destroy commands of the module, unless the args already set it, e.g. with extra_arguments.
+func setTerraformParallelism(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) {
+	if terragruntConfig.TerraformParallelism == nil || !util.ListContainsElement(terraformParallelismCommands, util.FirstArg(terragruntOptions.TerraformCliArgs)) {
+		return
+	}
+
+	for _, arg := range terragruntOptions.TerraformCliArgs {
+		if arg == terraform.FlagNameParallelism || strings.HasPrefix(arg, terraform.FlagNameParallelism+"=") {
+			return
+		}
+	}
+	terragruntOptions.InsertTerraformCliArgs(fmt.Sprintf("%s=%d", terraform.FlagNameParallelism, *terragruntConfig.TerraformParallelism))
+}
@@ -0,0 +1,43 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTerraformParallelism(t *testing.T) {
+	t.Parallel()
+
+	parallelism := 5
+	testCases := []struct {
+		name         string
+		parallelism  *int
+		args         []string
+		expectedArgs []string
+	}{
+		{"not set", nil, []string{"apply"}, []string{"apply"}},
+		{"plan", &parallelism, []string{"plan", "-out=plan.tfplan"}, []string{"plan", "-parallelism=5", "-out=plan.tfplan"}},
+		{"apply plan file", &parallelism, []string{"apply", "plan.tfplan"}, []string{"apply", "-parallelism=5", "plan.tfplan"}},
+		{"destroy", &parallelism, []string{"destroy"}, []string{"destroy", "-parallelism=5"}},
+		{"other command", &parallelism, []string{"output", "-json"}, []string{"output", "-json"}},
+		{"set by the args", &parallelism, []string{"apply", "-parallelism=2"}, []string{"apply", "-parallelism=2"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			terragruntOptions, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+			require.NoError(t, err)
+			terragruntOptions.TerraformCliArgs = testCase.args
+
+			setTerraformParallelism(terragruntOptions, &config.TerragruntConfig{TerraformParallelism: testCase.parallelism})
+			assert.Equal(t, testCase.expectedArgs, terragruntOptions.TerraformCliArgs)
+		})
+	}
+}
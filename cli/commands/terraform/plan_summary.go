@@ -0,0 +1,81 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// planSummaryPlanFile is the file the plan of a module is saved to, for `terraform show -json` to summarize it, if the
+// plan is not already saved with -out.
+const planSummaryPlanFile = "terragrunt-plan-summary.tfplan"
+
+// isPlanWithSummary returns true if the module is planned by run-all plan, which prints a summary of the changes of
+// the plans of the modules.
+func isPlanWithSummary(terragruntOptions *options.TerragruntOptions) bool {
+	return terragruntOptions.PlanSummary != nil && util.FirstArg(terragruntOptions.TerraformCliArgs) == terraform.CommandNamePlan
+}
+
+// runPlanWithSummary runs the plan of the module, saving it to a file if it is not saved with -out already, and records
+// the counts of the resources it adds, changes and destroys from `terraform show -json`. The plan is summarized as
+// well if it exits with the code of -detailed-exitcode for a plan with changes.
+func runPlanWithSummary(ctx context.Context, terragruntOptions *options.TerragruntOptions) error {
+	planOptions := terragruntOptions
+	planFile := planOutFile(terragruntOptions.TerraformCliArgs)
+	if planFile == "" {
+		planFile = planSummaryPlanFile
+		defer func() {
+			if err := os.Remove(filepath.Join(terragruntOptions.WorkingDir, planFile)); err != nil && !os.IsNotExist(err) {
+				terragruntOptions.Logger.Debugf("Failed to remove plan file %s: %v", planFile, err)
+			}
+		}()
+
+		planOptions = terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+		planOptions.WorkingDir = terragruntOptions.WorkingDir
+		planOptions.TerraformCliArgs = append(util.CloneStringList(terragruntOptions.TerraformCliArgs), "-out="+planFile)
+	}
+
+	planErr := runTerraformWithRetry(ctx, planOptions)
+	if planErr != nil {
+		if exitCode, err := shell.GetExitCode(planErr); err != nil || exitCode != terraform.DetailedExitCodeChanges {
+			return planErr
+		}
+	}
+
+	output, err := shell.RunShellCommandWithOutput(ctx, quietOptions(terragruntOptions), "", true, false, terragruntOptions.TerraformPath, terraform.CommandNameShow, "-json", planFile)
+	if err != nil {
+		terragruntOptions.Logger.Warnf("Failed to show the plan of module %s to summarize it: %v", terragruntOptions.WorkingDir, err)
+		return planErr
+	}
+
+	_, summary, err := planResourceChanges([]byte(output.Stdout))
+	if err != nil {
+		terragruntOptions.Logger.Warnf("Failed to summarize the plan of module %s: %v", terragruntOptions.WorkingDir, err)
+		return planErr
+	}
+	summary.Recorded = true
+	*terragruntOptions.PlanSummary = summary
+	return planErr
+}
+
+// planOutFile returns the file the plan is saved to with -out in the given args, or an empty string if it is not saved.
+func planOutFile(args []string) string {
+	for i, arg := range args {
+		if flagName(arg) != "-out" {
+			continue
+		}
+		if _, value, ok := strings.Cut(arg, "="); ok {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
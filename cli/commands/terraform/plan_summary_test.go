@@ -0,0 +1,44 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanOutFile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"plan"}, ""},
+		{[]string{"plan", "-out=tfplan"}, "tfplan"},
+		{[]string{"plan", "--out=/tmp/plans/app.tfplan", "-input=false"}, "/tmp/plans/app.tfplan"},
+		{[]string{"plan", "-out", "tfplan"}, "tfplan"},
+		{[]string{"plan", "-var", "output=foo"}, ""},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, planOutFile(testCase.args), "%v", testCase.args)
+	}
+}
+
+func TestPlanResourceChanges(t *testing.T) {
+	t.Parallel()
+
+	_, summary, err := planResourceChanges([]byte(`{
+  "resource_changes": [
+    {"address": "aws_vpc.main", "change": {"actions": ["create"]}},
+    {"address": "aws_subnet.private", "change": {"actions": ["delete", "create"]}},
+    {"address": "aws_route53_record.www", "change": {"actions": ["update"]}},
+    {"address": "data.aws_ami.ubuntu", "change": {"actions": ["read"]}},
+    {"address": "aws_iam_role.app", "change": {"actions": ["no-op"]}}
+  ]
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, options.PlanSummary{Add: 2, Change: 1, Destroy: 1}, summary)
+}
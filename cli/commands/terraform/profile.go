@@ -0,0 +1,84 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"gopkg.in/ini.v1"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// setProfile activates the profile the module selects with use_profile, if any: the env vars of its credentials are
+// set, overriding the ones of the env that would take precedence over them, and its AWS named profile is used by the
+// AWS API calls of Terragrunt. The role of the profile is assumed as the iam_role, by GetIAMRoleOptions.
+func setProfile(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	profile, err := terragruntConfig.ActiveProfile()
+	if err != nil || profile == nil {
+		return err
+	}
+
+	if profile.AWS != nil && profile.AWS.Profile != nil {
+		if err := checkAWSProfile(terragruntOptions, profile.Name, *profile.AWS.Profile); err != nil {
+			return err
+		}
+		terragruntOptions.AWSProfile = *profile.AWS.Profile
+	}
+	if profile.GCP != nil && profile.GCP.Credentials != nil && !util.FileExists(*profile.GCP.Credentials) {
+		return errors.WithStackTrace(ProfileCredentialsNotFound{Profile: profile.Name, Path: *profile.GCP.Credentials})
+	}
+
+	if terragruntOptions.Env == nil {
+		terragruntOptions.Env = map[string]string{}
+	}
+	for _, name := range profile.OverriddenEnv() {
+		delete(terragruntOptions.Env, name)
+	}
+	for key, value := range profile.Env() {
+		terragruntOptions.Env[key] = value
+	}
+
+	terragruntOptions.Logger.Debugf("Using the credentials of the profile %s", profile.Name)
+	return nil
+}
+
+// checkAWSProfile returns an error if the AWS shared config files of the env, the config file and the credentials file,
+// define no named profile with the given name.
+func checkAWSProfile(terragruntOptions *options.TerragruntOptions, profileName string, awsProfile string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	configFile := filepath.Join(homeDir, ".aws", "config")
+	if path := terragruntOptions.Env["AWS_CONFIG_FILE"]; path != "" {
+		configFile = path
+	}
+	credentialsFile := filepath.Join(homeDir, ".aws", "credentials")
+	if path := terragruntOptions.Env["AWS_SHARED_CREDENTIALS_FILE"]; path != "" {
+		credentialsFile = path
+	}
+
+	// The profiles of the config file, except the default one, are in "profile <name>" sections
+	sections := map[string]string{configFile: "profile " + awsProfile, credentialsFile: awsProfile}
+	if awsProfile == "default" {
+		sections[configFile] = awsProfile
+	}
+
+	for _, file := range []string{configFile, credentialsFile} {
+		if !util.FileExists(file) {
+			continue
+		}
+		cfg, err := ini.Load(file)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if cfg.HasSection(sections[file]) {
+			return nil
+		}
+	}
+	return errors.WithStackTrace(AWSProfileNotFound{Profile: profileName, AWSProfile: awsProfile, Files: []string{configFile, credentialsFile}})
+}
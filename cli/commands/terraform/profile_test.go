@@ -0,0 +1,63 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetProfile(t *testing.T) {
+	t.Parallel()
+
+	awsDir := t.TempDir()
+	configFile := filepath.Join(awsDir, "config")
+	require.NoError(t, os.WriteFile(configFile, []byte("[default]\nregion = us-east-1\n\n[profile acme-prod]\nregion = eu-west-1\n"), 0644))
+
+	awsProfile, region := "acme-prod", "eu-west-1"
+	terragruntConfig := &config.TerragruntConfig{
+		Profiles: []config.ProfileConfig{
+			{Name: "prod", AWS: &config.AWSProfileConfig{Profile: &awsProfile, Region: &region}},
+		},
+		UseProfile: "prod",
+	}
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	terragruntOptions.Env = map[string]string{
+		"AWS_CONFIG_FILE":             configFile,
+		"AWS_SHARED_CREDENTIALS_FILE": filepath.Join(awsDir, "credentials"),
+		"AWS_ACCESS_KEY_ID":           "AKIAEXAMPLE",
+		"AWS_SECRET_ACCESS_KEY":       "secret",
+	}
+
+	require.NoError(t, setProfile(terragruntOptions, terragruntConfig))
+	assert.Equal(t, "acme-prod", terragruntOptions.AWSProfile)
+	assert.Equal(t, map[string]string{
+		"AWS_CONFIG_FILE":             configFile,
+		"AWS_SHARED_CREDENTIALS_FILE": filepath.Join(awsDir, "credentials"),
+		"AWS_PROFILE":                 "acme-prod",
+		"AWS_REGION":                  "eu-west-1",
+		"AWS_DEFAULT_REGION":          "eu-west-1",
+	}, terragruntOptions.Env)
+
+	// An AWS profile missing from the shared config files fails the run
+	missingProfile := "acme-staging"
+	terragruntConfig.Profiles[0].AWS.Profile = &missingProfile
+	err = setProfile(terragruntOptions, terragruntConfig)
+	var notFound AWSProfileNotFound
+	require.ErrorAs(t, errors.Unwrap(err), &notFound)
+	assert.Equal(t, "acme-staging", notFound.AWSProfile)
+
+	// The modules which select no profile keep the credentials of the env
+	terragruntOptions, err = options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	terragruntOptions.Env = map[string]string{"AWS_ACCESS_KEY_ID": "AKIAEXAMPLE"}
+	require.NoError(t, setProfile(terragruntOptions, &config.TerragruntConfig{Profiles: terragruntConfig.Profiles}))
+	assert.Equal(t, map[string]string{"AWS_ACCESS_KEY_ID": "AKIAEXAMPLE"}, terragruntOptions.Env)
+}
@@ -0,0 +1,126 @@
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// stateFile is the part of the state of a module, as `terraform state pull` prints it, which the refresh may update.
+type stateFile struct {
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   json.RawMessage `json:"index_key"`
+			Attributes json.RawMessage `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// isRefreshWithReport returns true if the module is refreshed by run-all refresh, which reports the resources whose
+// attributes the refresh updated.
+func isRefreshWithReport(terragruntOptions *options.TerragruntOptions) bool {
+	return terragruntOptions.RefreshedResources != nil && util.FirstArg(terragruntOptions.TerraformCliArgs) == terraform.CommandNameRefresh
+}
+
+// runRefreshWithReport runs the refresh of the module, and records the addresses of the resources whose attributes it
+// updated, added or removed in the state, by comparing the state before and after the refresh.
+func runRefreshWithReport(ctx context.Context, terragruntOptions *options.TerragruntOptions) error {
+	before, err := pullStateAttributes(ctx, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := runTerraformWithRetry(ctx, terragruntOptions); err != nil {
+		return err
+	}
+
+	after, err := pullStateAttributes(ctx, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	*terragruntOptions.RefreshedResources = changedResources(before, after)
+	return nil
+}
+
+// pullStateAttributes returns the compacted JSON attributes of the resource instances in the state of the module, by
+// address.
+func pullStateAttributes(ctx context.Context, terragruntOptions *options.TerragruntOptions) (map[string]string, error) {
+	output, err := shell.RunShellCommandWithOutput(ctx, quietOptions(terragruntOptions), "", true, false, terragruntOptions.TerraformPath, terraform.CommandNameState, terraform.CommandNamePull)
+	if err != nil {
+		return nil, err
+	}
+	return stateAttributes([]byte(output.Stdout))
+}
+
+// stateAttributes returns the compacted JSON attributes of the resource instances in the given state, by address. The
+// state of a module never applied is empty.
+func stateAttributes(content []byte) (map[string]string, error) {
+	attributes := map[string]string{}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return attributes, nil
+	}
+
+	var state stateFile
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, errors.WithStackTrace(InvalidState{Err: err})
+	}
+
+	for _, resource := range state.Resources {
+		address := resource.Type + "." + resource.Name
+		if resource.Mode == "data" {
+			address = "data." + address
+		}
+		if resource.Module != "" {
+			address = resource.Module + "." + address
+		}
+
+		for _, instance := range resource.Instances {
+			instanceAddress := address
+			if len(instance.IndexKey) > 0 {
+				instanceAddress = fmt.Sprintf("%s[%s]", address, instance.IndexKey)
+			}
+
+			var compacted bytes.Buffer
+			if len(instance.Attributes) == 0 {
+				attributes[instanceAddress] = ""
+				continue
+			}
+			if err := json.Compact(&compacted, instance.Attributes); err != nil {
+				return nil, errors.WithStackTrace(InvalidState{Err: err})
+			}
+			attributes[instanceAddress] = compacted.String()
+		}
+	}
+	return attributes, nil
+}
+
+// changedResources returns the sorted addresses of the resource instances whose attributes differ between the given
+// states, including the instances only in one of them, e.g. the resources deleted outside of terraform.
+func changedResources(before, after map[string]string) []string {
+	changed := []string{}
+	for address, attributes := range after {
+		if beforeAttributes, ok := before[address]; !ok || beforeAttributes != attributes {
+			changed = append(changed, address)
+		}
+	}
+	for address := range before {
+		if _, ok := after[address]; !ok {
+			changed = append(changed, address)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
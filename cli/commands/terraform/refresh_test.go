@@ -0,0 +1,54 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedResources(t *testing.T) {
+	t.Parallel()
+
+	before, err := stateAttributes([]byte(`{
+  "version": 4,
+  "serial": 3,
+  "resources": [
+    {"mode": "managed", "type": "aws_vpc", "name": "main", "instances": [{"attributes": {"id": "vpc-1", "tags": {}}}]},
+    {"mode": "managed", "type": "aws_subnet", "name": "private", "instances": [
+      {"index_key": 0, "attributes": {"id": "subnet-1"}},
+      {"index_key": 1, "attributes": {"id": "subnet-2"}}
+    ]},
+    {"module": "module.dns", "mode": "data", "type": "aws_route53_zone", "name": "main", "instances": [{"attributes": {"id": "Z1"}}]}
+  ]
+}`))
+	require.NoError(t, err)
+	assert.Len(t, before, 4)
+
+	after, err := stateAttributes([]byte(`{
+  "version": 4,
+  "serial": 4,
+  "resources": [
+    {"mode": "managed", "type": "aws_vpc", "name": "main", "instances": [{"attributes": {"id": "vpc-1", "tags": {"team": "platform"}}}]},
+    {"mode": "managed", "type": "aws_subnet", "name": "private", "instances": [
+      {"index_key": 0, "attributes": {"id": "subnet-1"}}
+    ]},
+    {"module": "module.dns", "mode": "data", "type": "aws_route53_zone", "name": "main", "instances": [{"attributes": {"id":"Z1"}}]}
+  ]
+}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"aws_subnet.private[1]", "aws_vpc.main"}, changedResources(before, after))
+	assert.Equal(t, []string{}, changedResources(after, after))
+}
+
+func TestStateAttributesOfEmptyState(t *testing.T) {
+	t.Parallel()
+
+	attributes, err := stateAttributes([]byte("\n"))
+	require.NoError(t, err)
+	assert.Empty(t, attributes)
+
+	_, err = stateAttributes([]byte("not a state"))
+	require.Error(t, err)
+}
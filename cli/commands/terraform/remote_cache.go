@@ -0,0 +1,55 @@
+package terraform
+
+import (
+	"context"
+	"os"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remotecache"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// sourceRemoteCacheKey returns the key of the given source in the remote cache, or an empty string if the source is not
+// shared through the remote cache: it is local, or not pinned to a git ref or a version, as the entries are never
+// updated, or verified with cosign while it is downloaded. The source is only fetched from the remote
+// cache, and stored in it, if its download dir does not exist yet, as an existing download dir is updated instead.
+func sourceRemoteCacheKey(downloadDir string, terraformSource *terraform.Source, verificationRule *config.SourceVerificationRule) string {
+	sourceURL := terraformSource.CanonicalSourceURL
+	if terraform.IsLocalSource(sourceURL) || util.FileExists(downloadDir) || !isPinnedSource(sourceURL.String()) {
+		return ""
+	}
+	if verificationRule != nil && verificationRule.Method == config.SourceVerificationMethodCosign {
+		return ""
+	}
+	return remotecache.Key(remotecache.KindSource, sourceURL.String())
+}
+
+// fetchSourceFromRemoteCache extracts the source of the given key in the remote cache into the given dir, and returns
+// false if the remote cache does not have it, or fetching it failed, so that it is downloaded from its URL instead.
+func fetchSourceFromRemoteCache(ctx context.Context, backend remotecache.Backend, key string, downloadDir string, terraformSource *terraform.Source, terragruntOptions *options.TerragruntOptions) bool {
+	hit, err := remotecache.FetchDir(ctx, backend, key, downloadDir)
+	if err != nil {
+		terragruntOptions.Logger.Warnf("Failed to fetch the terraform source %s from the remote cache, downloading it: %v", terraformSource.CanonicalSourceURL, err)
+		if removeErr := os.RemoveAll(downloadDir); removeErr != nil {
+			terragruntOptions.Logger.Warnf("Failed to remove %s: %v", downloadDir, removeErr)
+		}
+		hit = false
+	}
+	util.CountCacheLookup(util.RemoteCache, hit)
+	if hit {
+		terragruntOptions.Logger.Infof("Fetched the terraform source %s from the remote cache into %s", terraformSource.CanonicalSourceURL, terraformSource.DownloadDir)
+	}
+	return hit
+}
+
+// storeSourceInRemoteCache stores the source downloaded into the given dir in the remote cache. The run does not fail
+// if storing it fails, as the source is only downloaded again by the next run.
+func storeSourceInRemoteCache(ctx context.Context, backend remotecache.Backend, key string, downloadDir string, terraformSource *terraform.Source, terragruntOptions *options.TerragruntOptions) {
+	if err := remotecache.StoreDir(ctx, backend, key, downloadDir, nil); err != nil {
+		terragruntOptions.Logger.Warnf("Failed to store the terraform source %s in the remote cache: %v", terraformSource.CanonicalSourceURL, err)
+		return
+	}
+	terragruntOptions.Logger.Debugf("Stored the terraform source %s in the remote cache", terraformSource.CanonicalSourceURL)
+}
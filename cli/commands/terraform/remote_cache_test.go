@@ -0,0 +1,104 @@
+package terraform
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remotecache"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceRemoteCacheKey(t *testing.T) {
+	t.Parallel()
+
+	existingDir := t.TempDir()
+	missingDir := filepath.Join(existingDir, "download")
+	cosign := &config.SourceVerificationRule{Method: config.SourceVerificationMethodCosign}
+
+	testCases := []struct {
+		source      string
+		downloadDir string
+		rule        *config.SourceVerificationRule
+		expected    bool
+	}{
+		{"git::https://github.com/acme/modules.git//vpc?ref=v1.0.0", missingDir, nil, true},
+		{"tfr://registry.terraform.io/acme/vpc/aws?version=1.0.0", missingDir, nil, true},
+		{"git::https://github.com/acme/modules.git//vpc", missingDir, nil, false},
+		{"git::https://github.com/acme/modules.git//vpc?ref=v1.0.0", existingDir, nil, false},
+		{"git::https://github.com/acme/modules.git//vpc?ref=v1.0.0", missingDir, cosign, false},
+		{"file:///modules/vpc", missingDir, nil, false},
+	}
+
+	for _, testCase := range testCases {
+		terraformSource := &terraform.Source{CanonicalSourceURL: parseUrl(t, testCase.source)}
+		key := sourceRemoteCacheKey(testCase.downloadDir, terraformSource, testCase.rule)
+		assert.Equal(t, testCase.expected, key != "", testCase.source)
+	}
+}
+
+func TestDownloadSourceFromRemoteCache(t *testing.T) {
+	t.Parallel()
+
+	entries := map[string][]byte{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPut {
+			entries[r.URL.Path], _ = io.ReadAll(r.Body)
+			return
+		}
+		if content, ok := entries[r.URL.Path]; ok {
+			_, _ = w.Write(content)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	downloadDir := filepath.Join(t.TempDir(), "download")
+	terraformSource := &terraform.Source{
+		CanonicalSourceURL: parseUrl(t, "git::https://github.com/acme/modules.git//vpc?ref=v1.0.0"),
+		DownloadDir:        downloadDir,
+		WorkingDir:         filepath.Join(downloadDir, "vpc"),
+	}
+	terragruntConfig := &config.TerragruntConfig{}
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), "terragrunt.hcl"))
+	require.NoError(t, err)
+	terragruntOptions.RemoteCacheURL = server.URL
+	// The source is never downloaded from its URL
+	terragruntOptions.Offline = true
+	terragruntOptions.OfflineAllowedHosts = []string{"127.0.0.1"}
+
+	moduleDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(moduleDir, "vpc"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "vpc", "main.tf"), []byte(`# vpc`), 0644))
+	backend, err := remotecache.FromOptions(terragruntOptions)
+	require.NoError(t, err)
+	key := sourceRemoteCacheKey(downloadDir, terraformSource, nil)
+	require.NoError(t, remotecache.StoreDir(context.Background(), backend, key, moduleDir, nil))
+
+	require.NoError(t, downloadSource(context.Background(), terraformSource, terragruntOptions, terragruntConfig))
+	content, err := os.ReadFile(filepath.Join(downloadDir, "vpc", "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `# vpc`, string(content))
+
+	// Without the entry, the source is downloaded, which offline mode refuses
+	mu.Lock()
+	delete(entries, "/"+key)
+	mu.Unlock()
+	require.NoError(t, os.RemoveAll(downloadDir))
+	err = downloadSource(context.Background(), terraformSource, terragruntOptions, terragruntConfig)
+	var offlineErr options.OfflineFetchNotAllowed
+	assert.ErrorAs(t, err, &offlineErr)
+}
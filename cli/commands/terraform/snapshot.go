@@ -0,0 +1,163 @@
+package terraform
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// recordSnapshotModule records the part of the snapshot manifest of the module applied in the working dir: the source
+// and the hash of the code applied, the hash of the inputs, the versions of the providers and the outputs.
+func recordSnapshotModule(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	sourceHash, err := hashModuleCode(terragruntOptions.WorkingDir)
+	if err != nil {
+		return err
+	}
+
+	inputs, err := json.Marshal(terragruntConfig.Inputs)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	providers, err := lockedProviderVersions(filepath.Join(terragruntOptions.WorkingDir, util.TerraformLockFile))
+	if err != nil {
+		return err
+	}
+
+	outputs, err := snapshotOutputs(ctx, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	source, err := config.GetTerraformSourceUrl(terragruntOptions, terragruntConfig)
+	if err != nil {
+		return err
+	}
+
+	return configstack.WriteSnapshotModule(terragruntOptions, &configstack.SnapshotModule{
+		Path:       filepath.Dir(terragruntOptions.TerragruntConfigPath),
+		Source:     source,
+		SourceHash: sourceHash,
+		InputsHash: hashBytes(inputs),
+		Providers:  providers,
+		Outputs:    outputs,
+	})
+}
+
+// hashModuleCode returns the hash of the code in the given dir, leaving out the files terraform and terragrunt write
+// there, e.g. the data dir, the state and the dependency lock file.
+func hashModuleCode(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != dir && (name == ".terraform" || name == ".git" || strings.HasPrefix(name, ".terragrunt")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if name == util.TerraformLockFile || strings.HasPrefix(name, ".terragrunt") || strings.Contains(name, ".tfstate") || strings.HasSuffix(name, terraform.TerraformPlanFileExtension) {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(relPath))
+		return nil
+	})
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	hash, err := dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+		return os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+	return hash, errors.WithStackTrace(err)
+}
+
+// lockedProviderVersions returns the versions of the providers, by provider address, in the given dependency lock file.
+func lockedProviderVersions(lockFile string) (map[string]string, error) {
+	providers := map[string]string{}
+	if !util.FileExists(lockFile) {
+		return providers, nil
+	}
+
+	file, diags := hclparse.NewParser().ParseHCLFile(lockFile)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return providers, nil
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type != "provider" || len(block.Labels) != 1 {
+			continue
+		}
+		attr, ok := block.Body.Attributes["version"]
+		if !ok {
+			continue
+		}
+		version, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+		providers[block.Labels[0]] = version.AsString()
+	}
+	return providers, nil
+}
+
+// snapshotOutputs returns the summaries of the outputs of the module in the working dir. The values are only hashed,
+// so that the manifest does not leak the sensitive outputs.
+func snapshotOutputs(ctx context.Context, terragruntOptions *options.TerragruntOptions) (map[string]*configstack.SnapshotOutput, error) {
+	output, err := shell.RunShellCommandWithOutput(ctx, quietOptions(terragruntOptions), "", true, false, terragruntOptions.TerraformPath, terraform.CommandNameOutput, "-json")
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs map[string]struct {
+		Sensitive bool            `json:"sensitive"`
+		Type      json.RawMessage `json:"type"`
+		Value     json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(output.Stdout), &outputs); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	summaries := map[string]*configstack.SnapshotOutput{}
+	for name, output := range outputs {
+		summaries[name] = &configstack.SnapshotOutput{
+			Type:      output.Type,
+			Sensitive: output.Sensitive,
+			ValueHash: hashBytes(output.Value),
+		}
+	}
+	return summaries, nil
+}
+
+func hashBytes(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,60 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashModuleCode(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`resource "null_resource" "this" {}`), 0644))
+
+	hash, err := hashModuleCode(dir)
+	require.NoError(t, err)
+
+	// The files written by terraform and terragrunt do not change the hash
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".terraform", "providers"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".terraform", "terraform.tfstate"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "terraform.tfstate.backup"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".terragrunt-source-version"), []byte("v1"), 0644))
+	unchangedHash, err := hashModuleCode(dir)
+	require.NoError(t, err)
+	assert.Equal(t, hash, unchangedHash)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "outputs.tf"), []byte(`output "id" { value = null_resource.this.id }`), 0644))
+	changedHash, err := hashModuleCode(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+}
+
+func TestLockedProviderVersions(t *testing.T) {
+	t.Parallel()
+
+	lockFile := filepath.Join(t.TempDir(), ".terraform.lock.hcl")
+	require.NoError(t, os.WriteFile(lockFile, []byte(`
+provider "registry.terraform.io/hashicorp/aws" {
+  version     = "5.31.0"
+  constraints = "~> 5.0"
+  hashes = [
+    "h1:abc=",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/random" {
+  version = "3.6.0"
+}
+`), 0644))
+
+	providers, err := lockedProviderVersions(lockFile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"registry.terraform.io/hashicorp/aws":    "5.31.0",
+		"registry.terraform.io/hashicorp/random": "3.6.0",
+	}, providers)
+}
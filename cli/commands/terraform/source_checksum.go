@@ -0,0 +1,71 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+const (
+	// The file, next to the terragrunt config, with the checksums of the registry modules downloaded by the prior runs.
+	sourceChecksumsFile = ".terragrunt-source.lock.json"
+
+	// The manifest of the files of the registry module copied into the download dir.
+	registryModuleManifestFile = ".terragrunt-registry-manifest"
+)
+
+// verifySourceChecksum verifies the checksum of the registry module downloaded into the given dir. The registry tags
+// are mutable, so the checksum must match the source_checksum of the terraform block or, without one, the checksum
+// recorded by the prior runs, which is recorded on the first run.
+func verifySourceChecksum(moduleDir string, terraformSource *terraform.Source, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	source := terraformSource.CanonicalSourceURL.String()
+
+	checksum, err := dirhash.HashDir(moduleDir, "", dirhash.Hash1)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if terragruntConfig.Terraform != nil && terragruntConfig.Terraform.SourceChecksum != nil {
+		if expected := *terragruntConfig.Terraform.SourceChecksum; checksum != expected {
+			return errors.WithStackTrace(SourceChecksumMismatch{Source: source, Checksum: checksum, Expected: expected, ExpectedBy: "the source_checksum of " + terragruntOptions.TerragruntConfigPath})
+		}
+		return nil
+	}
+
+	checksumsFile := filepath.Join(filepath.Dir(terragruntOptions.TerragruntConfigPath), sourceChecksumsFile)
+	checksums := map[string]string{}
+	if util.FileExists(checksumsFile) {
+		content, err := os.ReadFile(checksumsFile)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if err := json.Unmarshal(content, &checksums); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	if expected, ok := checksums[source]; ok {
+		if checksum != expected {
+			return errors.WithStackTrace(SourceChecksumMismatch{Source: source, Checksum: checksum, Expected: expected, ExpectedBy: checksumsFile})
+		}
+		return nil
+	}
+
+	checksums[source] = checksum
+	content, err := json.MarshalIndent(checksums, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(checksumsFile, append(content, '\n'), 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	terragruntOptions.Logger.Infof("Recorded the checksum %s of %s in %s", checksum, source, checksumsFile)
+	return nil
+}
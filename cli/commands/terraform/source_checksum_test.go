@@ -0,0 +1,47 @@
+package terraform
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySourceChecksum(t *testing.T) {
+	t.Parallel()
+
+	configDir := t.TempDir()
+	moduleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(`variable "name" {}`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(configDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	sourceURL, err := url.Parse("tfr:///terraform-aws-modules/vpc/aws?version=5.0.0")
+	require.NoError(t, err)
+	terraformSource := &terraform.Source{CanonicalSourceURL: sourceURL}
+	terragruntConfig := &config.TerragruntConfig{}
+
+	// The first run records the checksum, the next ones verify it
+	require.NoError(t, verifySourceChecksum(moduleDir, terraformSource, opts, terragruntConfig))
+	assert.FileExists(t, filepath.Join(configDir, sourceChecksumsFile))
+	require.NoError(t, verifySourceChecksum(moduleDir, terraformSource, opts, terragruntConfig))
+
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(`variable "changed" {}`), 0644))
+	err = verifySourceChecksum(moduleDir, terraformSource, opts, terragruntConfig)
+	var mismatch SourceChecksumMismatch
+	require.ErrorAs(t, errors.Unwrap(err), &mismatch)
+	assert.Equal(t, filepath.Join(configDir, sourceChecksumsFile), mismatch.ExpectedBy)
+
+	// The pinned checksum takes precedence over the recorded one
+	pinned := mismatch.Checksum
+	terragruntConfig.Terraform = &config.TerraformConfig{SourceChecksum: &pinned}
+	require.NoError(t, verifySourceChecksum(moduleDir, terraformSource, opts, terragruntConfig))
+}
@@ -0,0 +1,158 @@
+package terraform
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/go-getter"
+)
+
+const (
+	cosignCommand = "cosign"
+
+	// The suffix of the URL of the Sigstore bundle of an archive.
+	cosignBundleSuffix = ".bundle"
+)
+
+// verifyGitSource verifies that the annotated git tag of the ref of the source cloned into the given dir or, if the ref
+// is not an annotated tag, the checked out commit, is signed by one of the identities of the rule.
+func verifyGitSource(ctx context.Context, cloneDir string, terraformSource *terraform.Source, rule *config.SourceVerificationRule, terragruntOptions *options.TerragruntOptions) error {
+	source := terraformSource.CanonicalSourceURL.String()
+	if !util.IsDir(filepath.Join(cloneDir, ".git")) {
+		return errors.WithStackTrace(SourceVerificationNotSupported{Source: source, Method: rule.Method, Reason: "the source is not a git repository"})
+	}
+
+	gitOpts := quietOptions(terragruntOptions)
+
+	verifyArgs := []string{"verify-commit", "--raw", "HEAD"}
+	if ref := terraformSource.CanonicalSourceURL.Query().Get("ref"); ref != "" {
+		if _, err := shell.RunShellCommandWithOutput(ctx, gitOpts, cloneDir, true, false, "git", "rev-parse", "--quiet", "--verify", "refs/tags/"+ref+"^{tag}"); err == nil {
+			verifyArgs = []string{"verify-tag", "--raw", ref}
+		}
+	}
+
+	output, err := shell.RunShellCommandWithOutput(ctx, gitOpts, cloneDir, true, false, "git", verifyArgs...)
+	if err != nil {
+		details := err.Error()
+		if output != nil {
+			details = strings.TrimSpace(output.Stderr)
+		}
+		return errors.WithStackTrace(SourceSignatureInvalid{Source: source, Method: rule.Method, Details: details})
+	}
+
+	signers := gitSigners(output.Stderr)
+	for _, signer := range signers {
+		for _, identity := range rule.Identities {
+			if strings.EqualFold(signer, identity) {
+				terragruntOptions.Logger.Debugf("Verified the signature of %s by %s", source, signer)
+				return nil
+			}
+		}
+	}
+	return errors.WithStackTrace(SourceSignerNotAllowed{Source: source, Signers: signers, Identities: rule.Identities})
+}
+
+// gitSigners returns the identities of the signer in the raw output of git verify-tag or verify-commit: the user ID,
+// email, key ID and fingerprint of the GPG signatures, or the principal and key fingerprint of the SSH signatures.
+func gitSigners(rawOutput string) []string {
+	var signers []string
+	for _, line := range strings.Split(rawOutput, "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 3 && fields[0] == "[GNUPG:]" && fields[1] == "GOODSIG":
+			signers = append(signers, fields[2])
+			if userID := strings.Join(fields[3:], " "); userID != "" {
+				signers = append(signers, userID)
+				if start, end := strings.LastIndex(userID, "<"), strings.LastIndex(userID, ">"); start >= 0 && end > start {
+					signers = append(signers, userID[start+1:end])
+				}
+			}
+		case len(fields) >= 3 && fields[0] == "[GNUPG:]" && fields[1] == "VALIDSIG":
+			signers = append(signers, fields[2])
+		case strings.HasPrefix(line, `Good "git" signature for `) && len(fields) >= 5:
+			// Good "git" signature for alice@example.com with ED25519 key SHA256:...
+			signers = append(signers, fields[4], fields[len(fields)-1])
+		}
+	}
+	return signers
+}
+
+// downloadCosignVerifiedSource downloads the archive of the source, verifies its Sigstore bundle with cosign and only
+// then extracts it into the given dir.
+func downloadCosignVerifiedSource(ctx context.Context, downloadDir, sourceURL string, rule *config.SourceVerificationRule, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	archiveURL := sourceURL
+	if forcedGetter, rawURL, found := strings.Cut(sourceURL, "::"); found && (forcedGetter == "http" || forcedGetter == "https") {
+		archiveURL = rawURL
+	}
+	parsedURL, err := url.Parse(archiveURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return util.FatalError{Underlying: errors.WithStackTrace(SourceVerificationNotSupported{Source: sourceURL, Method: rule.Method, Reason: "cosign verifies only the archives downloaded over HTTP(S)"})}
+	}
+
+	tempDir, err := os.MkdirTemp("", "terragrunt-cosign-")
+	if err != nil {
+		return util.FatalError{Underlying: errors.WithStackTrace(err)}
+	}
+	defer os.RemoveAll(tempDir) //nolint:errcheck
+
+	// Keep the name of the archive, go-getter detects the archive format from its extension
+	archiveFile := filepath.Join(tempDir, path.Base(parsedURL.Path))
+	if err := util.FetchFile(ctx, parsedURL.String(), archiveFile); err != nil {
+		return downloadAttemptError(err, false)
+	}
+
+	bundleURL := *parsedURL
+	bundleURL.Path += cosignBundleSuffix
+	bundleFile := filepath.Join(tempDir, "cosign"+cosignBundleSuffix)
+	if err := util.FetchFile(ctx, bundleURL.String(), bundleFile); err != nil {
+		return downloadAttemptError(err, false)
+	}
+
+	var identities []string
+	for _, identity := range rule.Identities {
+		identities = append(identities, regexp.QuoteMeta(identity))
+	}
+	output, err := shell.RunShellCommandWithOutput(ctx, quietOptions(terragruntOptions), tempDir, true, false, cosignCommand,
+		"verify-blob",
+		"--bundle", bundleFile,
+		"--certificate-identity-regexp", "^("+strings.Join(identities, "|")+")$",
+		"--certificate-oidc-issuer", *rule.OIDCIssuer,
+		archiveFile,
+	)
+	if err != nil {
+		details := err.Error()
+		if output != nil {
+			details = strings.TrimSpace(output.Stderr)
+		}
+		return util.FatalError{Underlying: errors.WithStackTrace(SourceSignatureInvalid{Source: sourceURL, Method: rule.Method, Details: details})}
+	}
+	terragruntOptions.Logger.Debugf("Verified the Sigstore bundle of %s", sourceURL)
+
+	localSource := archiveFile
+	if archive := parsedURL.Query().Get("archive"); archive != "" {
+		localSource += "?archive=" + archive
+	}
+	// The archive is already verified and local, a later attempt can't fix its extraction
+	return downloadAttemptError(getter.GetAny(downloadDir, localSource, updateGetters(terragruntOptions, terragruntConfig)), true)
+}
+
+// quietOptions returns a copy of the options discarding the output of the commands, which the verification reads from
+// the returned output instead.
+func quietOptions(terragruntOptions *options.TerragruntOptions) *options.TerragruntOptions {
+	quietOpts := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	quietOpts.Writer = io.Discard
+	quietOpts.ErrWriter = io.Discard
+	return quietOpts
+}
@@ -0,0 +1,89 @@
+package terraform
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitSigners(t *testing.T) {
+	t.Parallel()
+
+	gpgOutput := `[GNUPG:] NEWSIG
+[GNUPG:] GOODSIG 4AEE18F83AFDEB23 Alice <alice@example.com>
+[GNUPG:] VALIDSIG 5DE3E0509C47EA3CF04A42D34AEE18F83AFDEB23 2024-01-01 1704067200 0 4 0 1 10 00 5DE3E0509C47EA3CF04A42D34AEE18F83AFDEB23
+[GNUPG:] TRUST_ULTIMATE 0 pgp`
+	assert.Equal(t, []string{"4AEE18F83AFDEB23", "Alice <alice@example.com>", "alice@example.com", "5DE3E0509C47EA3CF04A42D34AEE18F83AFDEB23"}, gitSigners(gpgOutput))
+
+	sshOutput := `Good "git" signature for bob@example.com with ED25519 key SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8`
+	assert.Equal(t, []string{"bob@example.com", "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"}, gitSigners(sshOutput))
+}
+
+func TestVerifyGitSource(t *testing.T) {
+	t.Parallel()
+
+	repoDir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	runCommand(t, repoDir, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "alice@example.com", "-f", keyFile)
+	publicKey, err := os.ReadFile(keyFile + ".pub")
+	require.NoError(t, err)
+	allowedSignersFile := filepath.Join(repoDir, ".git-allowed-signers")
+	require.NoError(t, os.WriteFile(allowedSignersFile, append([]byte("alice@example.com "), publicKey...), 0644))
+
+	runCommand(t, repoDir, "git", "init", "-q")
+	for key, value := range map[string]string{
+		"user.name":                  "Alice",
+		"user.email":                 "alice@example.com",
+		"gpg.format":                 "ssh",
+		"user.signingkey":            keyFile,
+		"gpg.ssh.allowedSignersFile": allowedSignersFile,
+	} {
+		runCommand(t, repoDir, "git", "config", key, value)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "main.tf"), []byte(`variable "name" {}`), 0644))
+	runCommand(t, repoDir, "git", "add", "main.tf")
+	runCommand(t, repoDir, "git", "commit", "-q", "-m", "Initial commit")
+	runCommand(t, repoDir, "git", "tag", "-s", "-m", "v1.0.0", "v1.0.0")
+	runCommand(t, repoDir, "git", "tag", "v1.0.1")
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(repoDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	sourceFor := func(ref string) *terraform.Source {
+		sourceURL, err := url.Parse("git::https://github.com/acme/modules.git?ref=" + ref)
+		require.NoError(t, err)
+		return &terraform.Source{CanonicalSourceURL: sourceURL}
+	}
+	rule := &config.SourceVerificationRule{Prefix: "git::https://github.com/acme/", Method: config.SourceVerificationMethodGit, Identities: []string{"alice@example.com"}}
+
+	require.NoError(t, verifyGitSource(context.Background(), repoDir, sourceFor("v1.0.0"), rule, opts))
+
+	err = verifyGitSource(context.Background(), repoDir, sourceFor("v1.0.1"), rule, opts)
+	var invalid SourceSignatureInvalid
+	require.ErrorAs(t, errors.Unwrap(err), &invalid)
+
+	otherRule := &config.SourceVerificationRule{Prefix: rule.Prefix, Method: rule.Method, Identities: []string{"mallory@example.com"}}
+	err = verifyGitSource(context.Background(), repoDir, sourceFor("v1.0.0"), otherRule, opts)
+	var notAllowed SourceSignerNotAllowed
+	require.ErrorAs(t, errors.Unwrap(err), &notAllowed)
+	assert.Contains(t, notAllowed.Signers, "alice@example.com")
+}
+
+func runCommand(t *testing.T, dir string, command string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command(command, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
@@ -0,0 +1,34 @@
+package terraform
+
+import (
+	"context"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// setRemoteStateEncryption checks that the key of the OpenTofu state encryption of the `remote_state` block, if any, is
+// available before running the commands reading or writing the state, and passes the encryption config to OpenTofu in
+// the env when the backend is not generated.
+func setRemoteStateEncryption(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	remoteState := terragruntConfig.RemoteState
+	if remoteState == nil || remoteState.Encryption == nil {
+		return nil
+	}
+
+	if util.ListContainsElement(TerraformCommandsThatUseState, util.FirstArg(terragruntOptions.TerraformCliArgs)) {
+		if err := remoteState.CheckEncryptionKey(ctx, terragruntOptions); err != nil {
+			return err
+		}
+	}
+
+	env, err := remoteState.EncryptionEnv()
+	if err != nil {
+		return err
+	}
+	for key, value := range env {
+		terragruntOptions.Env[key] = value
+	}
+	return nil
+}
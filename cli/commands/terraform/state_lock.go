@@ -0,0 +1,159 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The errors of terraform failing to acquire the lock of the state, held by another run.
+var stateLockErrorRegexp = regexp.MustCompile(`Error acquiring the state lock|Error locking state`)
+
+// The fields of the lock info terraform prints with the lock errors, as stored by the backend with the lock. The lines
+// may be prefixed with the box-drawing characters of the diagnostics.
+var stateLockInfoRegexp = regexp.MustCompile(`(?m)^[│\s]*(ID|Path|Operation|Who|Created):[ \t]*(.*?)[ \t]*$`)
+
+// StateLockInfo is the info of the lock of the state held by another run.
+type StateLockInfo struct {
+	ID        string
+	Path      string
+	Operation string
+	Who       string
+	Created   string
+}
+
+func (info *StateLockInfo) String() string {
+	who := info.Who
+	if who == "" {
+		who = "an unknown principal"
+	}
+	details := []string{}
+	if info.Operation != "" {
+		details = append(details, info.Operation)
+	}
+	if info.Created != "" {
+		details = append(details, "since "+info.Created)
+	}
+	if info.ID != "" {
+		details = append(details, "lock ID "+info.ID)
+	}
+	if len(details) == 0 {
+		return who
+	}
+	return fmt.Sprintf("%s (%s)", who, strings.Join(details, ", "))
+}
+
+// parseStateLockInfo returns the info of the lock if the output is the one of a command which failed to acquire the
+// state lock, nil otherwise.
+func parseStateLockInfo(out *shell.CmdOutput) *StateLockInfo {
+	// When -json is enabled, Terraform will send all output, errors included, to stdout.
+	output := out.Stderr + "\n" + out.Stdout
+	if !stateLockErrorRegexp.MatchString(output) {
+		return nil
+	}
+
+	info := &StateLockInfo{}
+	for _, match := range stateLockInfoRegexp.FindAllStringSubmatch(output, -1) {
+		switch match[1] {
+		case "ID":
+			info.ID = match[2]
+		case "Path":
+			info.Path = match[2]
+		case "Operation":
+			info.Operation = match[2]
+		case "Who":
+			info.Who = match[2]
+		case "Created":
+			info.Created = match[2]
+		}
+	}
+	return info
+}
+
+// setStateLock passes the timeout of the state_lock block to terraform as `-lock-timeout`, for the commands which lock
+// the state, unless the args already set it or disable the lock, and sets the retries of the commands failing to
+// acquire the lock.
+func setStateLock(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	if terragruntConfig.StateLock == nil {
+		return nil
+	}
+
+	retryBackoff, err := terragruntConfig.StateLock.GetRetryBackoff()
+	if err != nil {
+		return err
+	}
+	retryMaxBackoff, err := terragruntConfig.StateLock.GetRetryMaxBackoff()
+	if err != nil {
+		return err
+	}
+	terragruntOptions.StateLockRetryMaxAttempts = terragruntConfig.StateLock.GetRetryMaxAttempts()
+	terragruntOptions.StateLockRetryBackoff = retryBackoff
+	terragruntOptions.StateLockRetryMaxBackoff = retryMaxBackoff
+
+	timeout, err := terragruntConfig.StateLock.GetTimeout()
+	if err != nil || timeout == 0 || !util.ListContainsElement(config.TERRAFORM_COMMANDS_NEED_LOCKING, util.FirstArg(terragruntOptions.TerraformCliArgs)) {
+		return err
+	}
+
+	for _, arg := range terragruntOptions.TerraformCliArgs {
+		if arg == terraform.FlagNameLockFalse || arg == terraform.FlagNameLockTimeout || strings.HasPrefix(arg, terraform.FlagNameLockTimeout+"=") {
+			return nil
+		}
+	}
+	terragruntOptions.InsertTerraformCliArgs(fmt.Sprintf("%s=%s", terraform.FlagNameLockTimeout, timeout))
+	return nil
+}
+
+// runTerraformWithStateLockRetry runs the terraform command and, as long as it fails to acquire the state lock held by
+// another run, retries it up to the retries of the state_lock block, with an exponential backoff, reporting who holds
+// the lock.
+func runTerraformWithStateLockRetry(ctx context.Context, terragruntOptions *options.TerragruntOptions) (*shell.CmdOutput, error) {
+	for attempt := 0; ; attempt++ {
+		out, err := shell.RunTerraformCommandWithOutput(ctx, terragruntOptions, terragruntOptions.TerraformCliArgs...)
+		if err == nil || out == nil {
+			return out, err
+		}
+
+		lockInfo := parseStateLockInfo(out)
+		if lockInfo == nil {
+			return out, err
+		}
+		if attempt >= terragruntOptions.StateLockRetryMaxAttempts {
+			terragruntOptions.Logger.Errorf("The state lock of %s is held by %s. Once the other run is done, or if the lock is stale, run `terragrunt force-unlock %s`.", terragruntOptions.WorkingDir, lockInfo, lockInfo.ID)
+			return out, err
+		}
+
+		backoff := stateLockBackoff(terragruntOptions, attempt)
+		terragruntOptions.Logger.Warnf("The state lock of %s is held by %s. Retrying in %v (%d of %d).", terragruntOptions.WorkingDir, lockInfo, backoff, attempt+1, terragruntOptions.StateLockRetryMaxAttempts)
+		if terragruntOptions.Retries != nil {
+			terragruntOptions.Retries.Add(1)
+		}
+		select {
+		case <-ctx.Done():
+			return out, ctx.Err()
+		case <-time.After(backoff):
+			// try again
+		}
+	}
+}
+
+// stateLockBackoff returns the wait before the given retry, starting at the backoff of the state_lock block and doubled
+// on each retry, up to its max backoff.
+func stateLockBackoff(terragruntOptions *options.TerragruntOptions, attempt int) time.Duration {
+	backoff := terragruntOptions.StateLockRetryBackoff
+	for i := 0; i < attempt && backoff < terragruntOptions.StateLockRetryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > terragruntOptions.StateLockRetryMaxBackoff {
+		backoff = terragruntOptions.StateLockRetryMaxBackoff
+	}
+	return backoff
+}
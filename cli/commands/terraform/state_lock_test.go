@@ -0,0 +1,90 @@
+package terraform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStateLockInfo(t *testing.T) {
+	t.Parallel()
+
+	stderr := `
+╷
+│ Error: Error acquiring the state lock
+│ 
+│ Error message: ConditionalCheckFailedException: The conditional request
+│ failed
+│ Lock Info:
+│   ID:        8d2b6f1c-44a1-4e9b-9e0c-2a1c3f4e5d6b
+│   Path:      acme-state/prod/vpc/terraform.tfstate
+│   Operation: OperationTypeApply
+│   Who:       alice@build-01
+│   Version:   1.5.7
+│   Created:   2024-01-02 03:04:05.123456 +0000 UTC
+│   Info:      
+│ 
+│ Terraform acquires a state lock to protect the state from being written
+│ by multiple users at the same time.
+╵
+`
+	info := parseStateLockInfo(&shell.CmdOutput{Stderr: stderr})
+	require.NotNil(t, info)
+	assert.Equal(t, &StateLockInfo{
+		ID:        "8d2b6f1c-44a1-4e9b-9e0c-2a1c3f4e5d6b",
+		Path:      "acme-state/prod/vpc/terraform.tfstate",
+		Operation: "OperationTypeApply",
+		Who:       "alice@build-01",
+		Created:   "2024-01-02 03:04:05.123456 +0000 UTC",
+	}, info)
+	assert.Equal(t, "alice@build-01 (OperationTypeApply, since 2024-01-02 03:04:05.123456 +0000 UTC, lock ID 8d2b6f1c-44a1-4e9b-9e0c-2a1c3f4e5d6b)", info.String())
+
+	assert.Nil(t, parseStateLockInfo(&shell.CmdOutput{Stderr: "Error: Invalid provider configuration"}))
+}
+
+func TestSetStateLock(t *testing.T) {
+	t.Parallel()
+
+	timeout, retryMaxAttempts := "5m", 5
+	terragruntConfig := &config.TerragruntConfig{StateLock: &config.StateLockConfig{Timeout: &timeout, RetryMaxAttempts: &retryMaxAttempts}}
+
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"apply", "-auto-approve"}, []string{"apply", "-lock-timeout=5m0s", "-auto-approve"}},
+		{[]string{"plan", "-lock-timeout=1m"}, []string{"plan", "-lock-timeout=1m"}},
+		{[]string{"plan", "-lock=false"}, []string{"plan", "-lock=false"}},
+		{[]string{"output", "-json"}, []string{"output", "-json"}},
+	}
+	for _, testCase := range testCases {
+		terragruntOptions, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+		require.NoError(t, err)
+		terragruntOptions.TerraformCliArgs = testCase.args
+
+		require.NoError(t, setStateLock(terragruntOptions, terragruntConfig))
+		assert.Equal(t, testCase.expected, terragruntOptions.TerraformCliArgs)
+		assert.Equal(t, 5, terragruntOptions.StateLockRetryMaxAttempts)
+		assert.Equal(t, config.DefaultStateLockRetryBackoff, terragruntOptions.StateLockRetryBackoff)
+	}
+}
+
+func TestStateLockBackoff(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	terragruntOptions.StateLockRetryBackoff = 30 * time.Second
+	terragruntOptions.StateLockRetryMaxBackoff = 2 * time.Minute
+
+	var backoffs []time.Duration
+	for attempt := 0; attempt < 4; attempt++ {
+		backoffs = append(backoffs, stateLockBackoff(terragruntOptions, attempt))
+	}
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 2 * time.Minute}, backoffs)
+}
@@ -50,16 +50,20 @@ func checkVersionConstraints(ctx context.Context, terragruntOptions *options.Ter
 	if terragruntOptions.TerraformPath == options.DefaultWrappedPath && partialTerragruntConfig.TerraformBinary != "" {
 		terragruntOptions.TerraformPath = partialTerragruntConfig.TerraformBinary
 	}
-	if err := PopulateTerraformVersion(ctx, terragruntOptions); err != nil {
-		return err
-	}
 
-	terraformVersionConstraint := DefaultTerraformVersionConstraint
-	if partialTerragruntConfig.TerraformVersionConstraint != "" {
-		terraformVersionConstraint = partialTerragruntConfig.TerraformVersionConstraint
-	}
-	if err := CheckTerraformVersion(terraformVersionConstraint, terragruntOptions); err != nil {
-		return err
+	// The binary selected per module matches the version constraints once the terraform code is downloaded
+	if !isAutoTerraformBinary(terragruntOptions, partialTerragruntConfig.TerraformBinary) {
+		if err := PopulateTerraformVersion(ctx, terragruntOptions); err != nil {
+			return err
+		}
+
+		terraformVersionConstraint := DefaultTerraformVersionConstraint
+		if partialTerragruntConfig.TerraformVersionConstraint != "" {
+			terraformVersionConstraint = partialTerragruntConfig.TerraformVersionConstraint
+		}
+		if err := CheckTerraformVersion(terraformVersionConstraint, terragruntOptions); err != nil {
+			return err
+		}
 	}
 
 	if partialTerragruntConfig.TerragruntVersionConstraint != "" {
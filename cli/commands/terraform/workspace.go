@@ -0,0 +1,66 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The file of the data dir in which terraform records the selected workspace.
+const terraformEnvironmentFile = "environment"
+
+// selectWorkspace selects the workspace the config sets, or the workspace run-all runs the module in, before running a
+// command other than init, creating the workspace if it does not exist yet. Nothing is run if the workspace is already selected, and the workspace commands
+// run as they are. TF_WORKSPACE takes precedence over the config, as terraform does not select a workspace it sets.
+func selectWorkspace(ctx context.Context, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	workspace := terragruntConfig.Workspace
+	if terragruntOptions.TerraformWorkspace != "" {
+		workspace = terragruntOptions.TerraformWorkspace
+	}
+	command := util.FirstArg(terragruntOptions.TerraformCliArgs)
+	if workspace == "" || command == terraform.CommandNameWorkspace || util.ListContainsElement(TerraformCommandsThatDoNotNeedInit, command) {
+		return nil
+	}
+
+	if envWorkspace, ok := terragruntOptions.Env[terraform.EnvNameTFWorkspace]; ok {
+		if envWorkspace != workspace {
+			return terragruntOptions.Warn(options.WarningWorkspaceSelectedByEnv, "Not selecting the workspace %s of the config, as %s selects the workspace %s", workspace, terraform.EnvNameTFWorkspace, envWorkspace)
+		}
+		return nil
+	}
+
+	if selectedWorkspace(terragruntOptions) == workspace {
+		return nil
+	}
+
+	// The output of the select command is discarded, as it fails if the workspace does not exist yet
+	quietOpts := quietOptions(terragruntOptions)
+	terragruntOptions.Logger.Debugf("Selecting the workspace %s", workspace)
+	if _, err := shell.RunTerraformCommandWithOutput(ctx, quietOpts, terraform.CommandNameWorkspace, "select", workspace); err == nil {
+		return nil
+	}
+
+	terragruntOptions.Logger.Infof("Creating the workspace %s, as it does not exist", workspace)
+	_, err := shell.RunTerraformCommandWithOutput(ctx, quietOpts, terraform.CommandNameWorkspace, "new", workspace)
+	return err
+}
+
+// selectedWorkspace returns the workspace selected in the working dir of the module, which is the default workspace
+// until another one is selected.
+func selectedWorkspace(terragruntOptions *options.TerragruntOptions) string {
+	contents, err := os.ReadFile(filepath.Join(terragruntOptions.DataDir(), terraformEnvironmentFile))
+	if err != nil {
+		return terraform.DefaultWorkspace
+	}
+	if workspace := strings.TrimSpace(string(contents)); workspace != "" {
+		return workspace
+	}
+	return terraform.DefaultWorkspace
+}
@@ -0,0 +1,82 @@
+package terraform
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectWorkspace(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake binary is a shell script")
+	}
+
+	testCases := []struct {
+		name              string
+		workspace         string
+		selectedWorkspace string
+		existingWorkspace bool
+		envWorkspace      string
+		args              []string
+		expectedCalls     []string
+	}{
+		{name: "no workspace", args: []string{"plan"}},
+		{name: "existing workspace", workspace: "staging", existingWorkspace: true, args: []string{"plan"}, expectedCalls: []string{"workspace select staging"}},
+		{name: "new workspace", workspace: "staging", args: []string{"plan"}, expectedCalls: []string{"workspace select staging", "workspace new staging"}},
+		{name: "selected workspace", workspace: "staging", selectedWorkspace: "staging", args: []string{"plan"}},
+		{name: "default workspace", workspace: "default", args: []string{"plan"}},
+		{name: "workspace command", workspace: "staging", args: []string{"workspace", "list"}},
+		{name: "TF_WORKSPACE", workspace: "staging", envWorkspace: "prod", args: []string{"plan"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// The fake terraform records its args, and only selects the workspace if it exists
+			workingDir := t.TempDir()
+			callsFile := filepath.Join(workingDir, "calls")
+			selectExitCode := "1"
+			if testCase.existingWorkspace {
+				selectExitCode = "0"
+			}
+			script := "#!/bin/sh\necho \"$@\" >> " + callsFile + "\nif [ \"$2\" = select ]; then exit " + selectExitCode + "; fi\n"
+			terraformPath := filepath.Join(workingDir, "terraform")
+			require.NoError(t, os.WriteFile(terraformPath, []byte(script), 0755))
+
+			if testCase.selectedWorkspace != "" {
+				require.NoError(t, os.MkdirAll(filepath.Join(workingDir, ".terraform"), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(workingDir, ".terraform", terraformEnvironmentFile), []byte(testCase.selectedWorkspace), 0644))
+			}
+
+			opts, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, config.DefaultTerragruntConfigPath))
+			require.NoError(t, err)
+			opts.WorkingDir = workingDir
+			opts.TerraformPath = terraformPath
+			opts.TerraformCliArgs = testCase.args
+			if testCase.envWorkspace != "" {
+				opts.Env["TF_WORKSPACE"] = testCase.envWorkspace
+			}
+
+			err = selectWorkspace(context.Background(), opts, &config.TerragruntConfig{Workspace: testCase.workspace})
+			require.NoError(t, err)
+
+			var calls []string
+			if contents, err := os.ReadFile(callsFile); err == nil {
+				calls = strings.Split(strings.TrimSpace(string(contents)), "\n")
+			}
+			assert.Equal(t, testCase.expectedCalls, calls)
+		})
+	}
+}
@@ -36,7 +36,7 @@ func runValidateInputs(ctx context.Context, opts *options.TerragruntOptions, cfg
 	}
 	allVars := append(required, optional...)
 
-	allInputs, err := getDefinedTerragruntInputs(opts, cfg)
+	allInputs, err := GetDefinedTerragruntInputs(opts, cfg)
 	if err != nil {
 		return err
 	}
@@ -92,7 +92,7 @@ func runValidateInputs(ctx context.Context, opts *options.TerragruntOptions, cfg
 	return nil
 }
 
-// getDefinedTerragruntInputs will return a list of names of all variables that are configured by terragrunt to be
+// GetDefinedTerragruntInputs will return a list of names of all variables that are configured by terragrunt to be
 // passed into terraform. Terragrunt can pass in inputs from:
 // - var files defined on terraform.extra_arguments blocks.
 // - -var and -var-file args passed in on extra_arguments CLI args.
@@ -100,7 +100,7 @@ func runValidateInputs(ctx context.Context, opts *options.TerragruntOptions, cfg
 // - env vars from the external runtime calling terragrunt.
 // - inputs blocks.
 // - automatically injected terraform vars (terraform.tfvars, terraform.tfvars.json, *.auto.tfvars, *.auto.tfvars.json)
-func getDefinedTerragruntInputs(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) ([]string, error) {
+func GetDefinedTerragruntInputs(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) ([]string, error) {
 	envVarTFVars := getTerraformInputNamesFromEnvVar(opts, cfg)
 	inputsTFVars := getTerraformInputNamesFromConfig(cfg)
 	varFileTFVars, err := getTerraformInputNamesFromVarFiles(opts, cfg)
@@ -47,13 +47,16 @@ func replaceDeprecatedCommandFunc(terragruntCommandName, terraformCommandName st
 			deprecatedCommandName := ctx.Command.Name
 			newCommandFriendly := fmt.Sprintf("terragrunt %s %s", terragruntCommandName, strings.Join(args, " "))
 
-			opts.Logger.Warnf(
-				"'%s' is deprecated. Running '%s' instead. Please update your workflows to use '%s', as '%s' may be removed in the future!\n",
+			if err := opts.Warn(
+				options.WarningDeprecatedCommand,
+				"'%s' is deprecated. Running '%s' instead. Please update your workflows to use '%s', as '%s' may be removed in the future!",
 				deprecatedCommandName,
 				newCommandFriendly,
 				newCommandFriendly,
 				deprecatedCommandName,
-			)
+			); err != nil {
+				return err
+			}
 
 			err := command.Run(ctx, args)
 			return err
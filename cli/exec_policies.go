@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// resolveExecPolicies sets the execution policies restricting the commands of hooks and run_cmd, from the
+// --terragrunt-exec-* flags and from the `exec_policy` block of the config given by --terragrunt-exec-policy-config.
+// The policy of the flags is resolved first, so that it applies to the run_cmd calls of the policy config as well.
+func resolveExecPolicies(ctx context.Context, opts *options.TerragruntOptions) error {
+	opts.ExecAllow = splitCommaSeparated(opts.ExecAllow)
+	opts.ExecDeny = splitCommaSeparated(opts.ExecDeny)
+
+	if len(opts.ExecAllow) > 0 || len(opts.ExecDeny) > 0 || opts.ExecNoNetwork {
+		opts.ExecPolicies = append(opts.ExecPolicies, options.ExecPolicy{
+			Source:    fmt.Sprintf("the --%s, --%s and --%s flags", commands.TerragruntExecAllowFlagName, commands.TerragruntExecDenyFlagName, commands.TerragruntExecNoNetworkFlagName),
+			Allow:     opts.ExecAllow,
+			Deny:      opts.ExecDeny,
+			NoNetwork: opts.ExecNoNetwork,
+		})
+	}
+
+	if opts.ExecPolicyConfigPath == "" {
+		return restrictExecPolicies(opts)
+	}
+
+	configPath, err := filepath.Abs(opts.ExecPolicyConfigPath)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	opts.ExecPolicyConfigPath = filepath.ToSlash(configPath)
+
+	policy, err := config.ReadExecPolicy(ctx, opts, opts.ExecPolicyConfigPath)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return errors.WithStackTrace(MissingExecPolicy(opts.ExecPolicyConfigPath))
+	}
+
+	opts.Logger.Debugf("Enforcing the execution policy of %s", opts.ExecPolicyConfigPath)
+	opts.ExecPolicies = append(opts.ExecPolicies, *policy)
+	return restrictExecPolicies(opts)
+}
+
+// restrictExecPolicies denies all the commands of hooks and run_cmd in the restricted mode, unless an allowlist is set.
+func restrictExecPolicies(opts *options.TerragruntOptions) error {
+	if !opts.Restricted {
+		return nil
+	}
+
+	for _, policy := range opts.ExecPolicies {
+		if len(policy.Allow) > 0 {
+			return nil
+		}
+	}
+
+	opts.ExecPolicies = append(opts.ExecPolicies, options.ExecPolicy{
+		Source: fmt.Sprintf("--%s, which only allows the commands of --%s or of --%s", commands.TerragruntRestrictedFlagName, commands.TerragruntExecAllowFlagName, commands.TerragruntExecPolicyConfigFlagName),
+		Deny:   []string{"*"},
+	})
+	return nil
+}
+
+// splitCommaSeparated splits the comma separated values of a slice flag.
+func splitCommaSeparated(values []string) []string {
+	var split []string
+	for _, value := range values {
+		split = append(split, strings.Split(value, ",")...)
+	}
+	return split
+}
+
+// Custom error types
+
+type MissingExecPolicy string
+
+func (err MissingExecPolicy) Error() string {
+	return fmt.Sprintf("The exec policy config %s does not define an exec_policy block", string(err))
+}
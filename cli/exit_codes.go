@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The values of --terragrunt-exit-code-mode.
+const (
+	// Exit with the exit code of Terraform, as Terragrunt always did.
+	ExitCodeModeTerraform = "terraform"
+	// Exit with a distinct exit code for each outcome, see DefaultDetailedExitCodes.
+	ExitCodeModeDetailed = "detailed"
+)
+
+var exitCodeModes = []string{ExitCodeModeTerraform, ExitCodeModeDetailed}
+
+// The outcomes of a command that have a distinct exit code in the detailed exit code mode.
+const (
+	OutcomeError           = "error"
+	OutcomeDriftDetected   = "drift-detected"
+	OutcomeModulesFailed   = "modules-failed"
+	OutcomeConfigError     = "config-error"
+	OutcomePolicyViolation = "policy-violation"
+	OutcomeInterrupted     = "interrupted"
+)
+
+// DefaultDetailedExitCodes are the exit codes of the detailed exit code mode. Drift matches the exit code of
+// `terraform plan -detailed-exitcode` and modules failed matches the exit code of --terragrunt-continue-on-error.
+var DefaultDetailedExitCodes = map[string]int{
+	OutcomeError:           1,
+	OutcomeDriftDetected:   2,
+	OutcomeModulesFailed:   3,
+	OutcomeConfigError:     6,
+	OutcomePolicyViolation: 7,
+	OutcomeInterrupted:     130,
+}
+
+// policyViolation is implemented by the errors of Terragrunt refusing to do something the configuration forbids.
+type policyViolation interface {
+	PolicyViolation()
+}
+
+// parseExitCodes parses the `<outcome>=<exit code>` overrides of the detailed exit codes, returning the exit codes.
+func parseExitCodes(overrides []string) (map[string]int, error) {
+	exitCodes := map[string]int{}
+	for outcome, exitCode := range DefaultDetailedExitCodes {
+		exitCodes[outcome] = exitCode
+	}
+
+	for _, override := range overrides {
+		outcome, value, found := strings.Cut(override, "=")
+		if _, known := DefaultDetailedExitCodes[outcome]; !found || !known {
+			return nil, errors.WithStackTrace(InvalidExitCodeOverride(override))
+		}
+
+		exitCode, err := strconv.Atoi(value)
+		if err != nil || exitCode < 1 || exitCode > 255 {
+			return nil, errors.WithStackTrace(InvalidExitCodeOverride(override))
+		}
+		exitCodes[outcome] = exitCode
+	}
+
+	return exitCodes, nil
+}
+
+// withDetailedExitCode wraps the given error of the command with the exit code of its outcome, if the detailed exit code
+// mode is enabled.
+func withDetailedExitCode(opts *options.TerragruntOptions, err error, interrupted bool) error {
+	if err == nil || opts.ExitCodeMode != ExitCodeModeDetailed {
+		return err
+	}
+
+	exitCodes, parseErr := parseExitCodes(opts.ExitCodes)
+	if parseErr != nil {
+		return parseErr
+	}
+
+	outcome := commandOutcome(opts, err, interrupted)
+	opts.Logger.Debugf("The command finished with outcome %s, exiting with code %d", outcome, exitCodes[outcome])
+	return errors.WithStackTrace(DetailedExitCodeError{Err: err, Outcome: outcome, ExitCode: exitCodes[outcome]})
+}
+
+// commandOutcome returns the outcome of the command that finished with the given error. The outcomes are checked from
+// the most to the least specific.
+func commandOutcome(opts *options.TerragruntOptions, err error, interrupted bool) string {
+	if interrupted {
+		return OutcomeInterrupted
+	}
+
+	allErrs := flattenErrors(err)
+
+	isDrift := len(allErrs) > 0
+	for _, err := range allErrs {
+		switch err.(type) {
+		case policyViolation:
+			return OutcomePolicyViolation
+		case hcl.Diagnostics:
+			return OutcomeConfigError
+		}
+		if !configstack.IsPlanWithChanges(opts, err) {
+			isDrift = false
+		}
+	}
+
+	switch {
+	case isDrift:
+		return OutcomeDriftDetected
+	case isModulesError(err):
+		return OutcomeModulesFailed
+	default:
+		return OutcomeError
+	}
+}
+
+// flattenErrors returns the errors the given error consists of, looking into the errors of run-all modules and the
+// errors of their dependencies.
+func flattenErrors(err error) []error {
+	switch err := errors.Unwrap(err).(type) {
+	case nil:
+		return nil
+	case *multierror.Error:
+		var allErrs []error
+		for _, err := range err.Errors {
+			allErrs = append(allErrs, flattenErrors(err)...)
+		}
+		return allErrs
+	case configstack.DependencyFinishedWithError:
+		return flattenErrors(err.Err)
+	case interface{ Unwrap() error }:
+		return flattenErrors(err.Unwrap())
+	default:
+		return []error{err}
+	}
+}
+
+// isModulesError returns true if the given error is the error of the modules of a run-all.
+func isModulesError(err error) bool {
+	switch err := errors.Unwrap(err).(type) {
+	case *multierror.Error:
+		return true
+	case configstack.RunReportError:
+		return true
+	case interface{ Unwrap() error }:
+		return isModulesError(err.Unwrap())
+	default:
+		return false
+	}
+}
+
+// exitCodeOutcomes returns the outcomes with a distinct exit code, sorted by name.
+func exitCodeOutcomes() []string {
+	var outcomes []string
+	for outcome := range DefaultDetailedExitCodes {
+		outcomes = append(outcomes, outcome)
+	}
+	sort.Strings(outcomes)
+	return outcomes
+}
+
+// Custom error types
+
+type DetailedExitCodeError struct {
+	Err      error
+	Outcome  string
+	ExitCode int
+}
+
+func (err DetailedExitCodeError) Error() string {
+	return err.Err.Error()
+}
+
+func (err DetailedExitCodeError) ExitStatus() (int, error) {
+	return err.ExitCode, nil
+}
+
+type UnknownExitCodeMode string
+
+func (err UnknownExitCodeMode) Error() string {
+	return fmt.Sprintf("Unknown exit code mode %s, must be one of: %s", string(err), strings.Join(exitCodeModes, ", "))
+}
+
+type InvalidExitCodeOverride string
+
+func (err InvalidExitCodeOverride) Error() string {
+	return fmt.Sprintf("Invalid exit code %s, must be <outcome>=<exit code from 1 to 255>, with outcome one of: %s", string(err), strings.Join(exitCodeOutcomes(), ", "))
+}
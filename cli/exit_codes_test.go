@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	terraformCmd "github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// exitStatusError is an error of a command that exited with the given exit code.
+type exitStatusError int
+
+func (err exitStatusError) Error() string {
+	return "exit status"
+}
+
+func (err exitStatusError) ExitStatus() (int, error) {
+	return int(err), nil
+}
+
+func TestCommandOutcome(t *testing.T) {
+	t.Parallel()
+
+	planOpts, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+	planOpts.TerraformCommand = "plan"
+	planOpts.TerraformCliArgs = []string{"plan", "-detailed-exitcode"}
+
+	testCases := []struct {
+		name        string
+		err         error
+		interrupted bool
+		expected    string
+	}{
+		{"error", errors.WithStackTrace(assert.AnError), false, OutcomeError},
+		{"interrupted", errors.WithStackTrace(assert.AnError), true, OutcomeInterrupted},
+		{"config-error", errors.WithStackTrace(hcl.Diagnostics{{Severity: hcl.DiagError, Summary: "Invalid"}}), false, OutcomeConfigError},
+		{"policy-violation", multierror.Append(nil, errors.WithStackTrace(terraformCmd.ModuleIsProtected{Opts: planOpts})), false, OutcomePolicyViolation},
+		{"modules-failed", multierror.Append(nil, errors.WithStackTrace(assert.AnError), exitStatusError(2)), false, OutcomeModulesFailed},
+		{"drift-detected", multierror.Append(nil, exitStatusError(2), configstack.DependencyFinishedWithError{Err: exitStatusError(2)}), false, OutcomeDriftDetected},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, commandOutcome(planOpts, testCase.err, testCase.interrupted))
+		})
+	}
+}
+
+func TestWithDetailedExitCode(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	// The terraform mode keeps the exit code of the error
+	assert.Equal(t, exitStatusError(4), withDetailedExitCode(opts, exitStatusError(4), false))
+
+	opts.ExitCodeMode = ExitCodeModeDetailed
+	opts.ExitCodes = []string{"error=42"}
+	exitCode, err := shell.GetExitCode(withDetailedExitCode(opts, exitStatusError(4), false))
+	require.NoError(t, err)
+	assert.Equal(t, 42, exitCode)
+}
+
+func TestParseExitCodesInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, override := range []string{"error", "unknown=3", "error=0", "error=abc"} {
+		_, err := parseExitCodes([]string{override})
+		assert.Error(t, err, override)
+	}
+}
@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// emitGitHubAnnotations writes the GitHub Actions annotations of the error the command finished with. Errors of run-all
+// modules are already annotated by the stack, so the command error itself is only annotated if nothing else was.
+func emitGitHubAnnotations(opts *options.TerragruntOptions, err error) {
+	alreadyAnnotated := util.GitHubAnnotationsEmittedCount() > 0
+
+	annotations := configstack.GitHubAnnotationsForError(opts, err)
+	if alreadyAnnotated && len(annotations) == 1 && annotations[0].Line == 0 {
+		return
+	}
+	util.EmitGitHubAnnotations(opts.Writer, annotations...)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/pkg/log"
+	"github.com/gruntwork-io/terragrunt/remotecache"
 	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/gruntwork-io/terragrunt/terraform"
 	"github.com/gruntwork-io/terragrunt/terraform/cache"
@@ -79,6 +80,11 @@ func InitProviderCacheServer(opts *options.TerragruntOptions) (*ProviderCacheSer
 		return nil, err
 	}
 
+	remoteCache, err := remotecache.FromOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	server := cache.NewServer(
 		cache.WithHostname(opts.ProviderCacheHostname),
 		cache.WithPort(opts.ProviderCachePort),
@@ -87,6 +93,8 @@ func InitProviderCacheServer(opts *options.TerragruntOptions) (*ProviderCacheSer
 		cache.WithProviderCacheDir(opts.ProviderCacheDir),
 		cache.WithProviderArchiveDir(opts.ProviderCacheArchiveDir),
 		cache.WithDisablePartialLockFile(opts.ProviderCacheDisablePartialLockFile),
+		cache.WithDownloadRetry(opts.DownloadRetry()),
+		cache.WithRemoteCache(remoteCache),
 	)
 
 	// We need to start listening earlier (not during web server startup) in order to determine/reserve a free port, which we then use in the CLI config file.
@@ -9,9 +9,11 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsimple"
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/gruntwork-io/go-commons/errors"
@@ -26,6 +28,18 @@ const (
 
 	// The default prefix to use for comments in the generated file
 	DefaultCommentPrefix = "# "
+
+	// The keys of the encryption config of the remote state that configure the `encryption` block rather than the key
+	// provider.
+	EncryptionKeyProviderKey = "key_provider"
+	EncryptionMethodKey      = "method"
+	EncryptionEnforcedKey    = "enforced"
+
+	// The method encrypting the state and the plans if the encryption config sets none.
+	DefaultEncryptionMethod = "aes_gcm"
+
+	// The name of the key provider and of the method the encryption config renders.
+	encryptionBlockName = "default"
 )
 
 // An enum to represent valid values for if_exists
@@ -36,6 +50,7 @@ const (
 	ExistsSkip
 	ExistsOverwrite
 	ExistsOverwriteTerragrunt
+	ExistsSkipIfIdentical
 	ExistsUnknown
 )
 
@@ -44,17 +59,22 @@ const (
 	ExistsSkipStr                = "skip"
 	ExistsOverwriteStr           = "overwrite"
 	ExistsOverwriteTerragruntStr = "overwrite_terragrunt"
+	ExistsSkipIfIdenticalStr     = "skip_if_identical"
 
 	assumeRoleConfigKey = "assume_role"
 )
 
 // Configuration for generating code
 type GenerateConfig struct {
-	Path             string `cty:"path"`
-	IfExists         GenerateConfigExists
-	IfExistsStr      string `cty:"if_exists"`
-	CommentPrefix    string `cty:"comment_prefix"`
-	Contents         string `cty:"contents"`
+	Path          string `cty:"path"`
+	IfExists      GenerateConfigExists
+	IfExistsStr   string `cty:"if_exists"`
+	CommentPrefix string `cty:"comment_prefix"`
+	Contents      string `cty:"contents"`
+	// The go-getter URL of the file the contents are fetched from, instead of the inline contents.
+	Source string `cty:"source"`
+	// The SHA256 checksum the contents fetched from the source must match.
+	Checksum         string `cty:"checksum"`
 	DisableSignature bool   `cty:"disable_signature"`
 	Disable          bool   `cty:"disable"`
 }
@@ -64,11 +84,13 @@ type GenerateConfig struct {
 // - if ExistsError, return an error.
 // - if ExistsSkip, do nothing and return
 // - if ExistsOverwrite, overwrite the existing file
-func WriteToFile(terragruntOptions *options.TerragruntOptions, basePath string, config GenerateConfig) error {
+// - if ExistsSkipIfIdentical, do nothing if the existing file has the same contents, overwrite it otherwise
+// Returns true if the file was written.
+func WriteToFile(terragruntOptions *options.TerragruntOptions, basePath string, config GenerateConfig) (bool, error) {
 	// If this GenerateConfig is disabled then skip further processing.
 	if config.Disable {
 		terragruntOptions.Logger.Debugf("Skipping generating file at %s because it is disabled", config.Path)
-		return nil
+		return false, nil
 	}
 
 	// Figure out thee target path to generate the code in. If relative, merge with basePath.
@@ -79,12 +101,8 @@ func WriteToFile(terragruntOptions *options.TerragruntOptions, basePath string,
 		targetPath = filepath.Join(basePath, config.Path)
 	}
 
-	targetFileExists := util.FileExists(targetPath)
-	if targetFileExists {
-		shouldContinue, err := shouldContinueWithFileExists(terragruntOptions, targetPath, config.IfExists)
-		if err != nil || !shouldContinue {
-			return err
-		}
+	if terragruntOptions.Restricted && !util.HasPathPrefix(targetPath, basePath) {
+		return false, errors.WithStackTrace(GenerateOutsideModuleDirNotAllowed{path: targetPath})
 	}
 
 	// Add the signature as a prefix to the file, unless it is disabled.
@@ -94,16 +112,24 @@ func WriteToFile(terragruntOptions *options.TerragruntOptions, basePath string,
 	}
 	contentsToWrite := fmt.Sprintf("%s%s", prefix, config.Contents)
 
+	targetFileExists := util.FileExists(targetPath)
+	if targetFileExists {
+		shouldContinue, err := shouldContinueWithFileExists(terragruntOptions, targetPath, config.IfExists, contentsToWrite)
+		if err != nil || !shouldContinue {
+			return false, err
+		}
+	}
+
 	if err := os.WriteFile(targetPath, []byte(contentsToWrite), 0644); err != nil {
-		return errors.WithStackTrace(err)
+		return false, errors.WithStackTrace(err)
 	}
 	terragruntOptions.Logger.Debugf("Generated file %s.", targetPath)
-	return nil
+	return true, nil
 }
 
 // Whether or not file generation should continue if the file path already exists. The answer depends on the
 // ifExists configuration.
-func shouldContinueWithFileExists(terragruntOptions *options.TerragruntOptions, path string, ifExists GenerateConfigExists) (bool, error) {
+func shouldContinueWithFileExists(terragruntOptions *options.TerragruntOptions, path string, ifExists GenerateConfigExists, contents string) (bool, error) {
 	switch ifExists {
 	case ExistsError:
 		return false, errors.WithStackTrace(GenerateFileExistsError{path: path})
@@ -130,6 +156,18 @@ func shouldContinueWithFileExists(terragruntOptions *options.TerragruntOptions,
 		// Since file was generated by terragrunt, continue.
 		terragruntOptions.Logger.Debugf("The file path %s already exists, but was a previously generated file by terragrunt. Since if_exists for code generation is set to \"overwrite_terragrunt\", regenerating file.", path)
 		return true, nil
+	case ExistsSkipIfIdentical:
+		// Leave the file untouched if it has the same contents, so that its modification time does not change.
+		existingContents, err := os.ReadFile(path)
+		if err != nil {
+			return false, errors.WithStackTrace(err)
+		}
+		if string(existingContents) == contents {
+			terragruntOptions.Logger.Debugf("The file path %s already exists with the same contents and if_exists for code generation set to \"skip_if_identical\". Will not regenerate file.", path)
+			return false, nil
+		}
+		terragruntOptions.Logger.Debugf("The file path %s already exists with different contents and if_exists for code generation set to \"skip_if_identical\". Regenerating file.", path)
+		return true, nil
 	default:
 		// This shouldn't happen, but we add this case anyway for defensive coding.
 		return false, errors.WithStackTrace(UnknownGenerateIfExistsVal{""})
@@ -154,10 +192,12 @@ func fileWasGeneratedByTerragrunt(path string) (bool, error) {
 	return strings.HasSuffix(strings.TrimSpace(firstLine), TerragruntGeneratedSignature), nil
 }
 
-// Convert the arbitrary map that represents a remote state config into HCL code to configure that remote state.
-func RemoteStateConfigToTerraformCode(backend string, config map[string]interface{}) ([]byte, error) {
+// Convert the arbitrary map that represents a remote state config into HCL code to configure that remote state, along
+// with the OpenTofu state and plan encryption, if the encryption config is not nil.
+func RemoteStateConfigToTerraformCode(backend string, config map[string]interface{}, encryption map[string]interface{}) ([]byte, error) {
 	f := hclwrite.NewEmptyFile()
-	backendBlock := f.Body().AppendNewBlock("terraform", nil).Body().AppendNewBlock("backend", []string{backend})
+	terraformBlockBody := f.Body().AppendNewBlock("terraform", nil).Body()
+	backendBlock := terraformBlockBody.AppendNewBlock("backend", []string{backend})
 	backendBlockBody := backendBlock.Body()
 	var backendKeys []string
 
@@ -201,9 +241,79 @@ func RemoteStateConfigToTerraformCode(backend string, config map[string]interfac
 		backendBlockBody.SetAttributeValue(key, ctyVal.Value)
 	}
 
+	if encryption != nil {
+		if err := appendEncryptionConfig(terraformBlockBody.AppendNewBlock("encryption", nil).Body(), encryption); err != nil {
+			return nil, err
+		}
+	}
+
+	return f.Bytes(), nil
+}
+
+// EncryptionConfigToTerraformCode converts the map that represents the encryption config of the remote state into the
+// HCL code of the body of the OpenTofu `encryption` block, as set in the `TF_ENCRYPTION` env var.
+func EncryptionConfigToTerraformCode(encryption map[string]interface{}) ([]byte, error) {
+	f := hclwrite.NewEmptyFile()
+	if err := appendEncryptionConfig(f.Body(), encryption); err != nil {
+		return nil, err
+	}
 	return f.Bytes(), nil
 }
 
+// appendEncryptionConfig renders the encryption config, i.e. the key provider given by the `key_provider` key configured
+// with the other keys, and the method, `aes_gcm` unless given by the `method` key, encrypting both the state and the
+// plans with the key, as OpenTofu `encryption` block code:
+//
+//	key_provider "pbkdf2" "default" {
+//	  passphrase = "..."
+//	}
+//	method "aes_gcm" "default" {
+//	  keys = key_provider.pbkdf2.default
+//	}
+//	state {
+//	  method = method.aes_gcm.default
+//	}
+//	plan {
+//	  method = method.aes_gcm.default
+//	}
+func appendEncryptionConfig(body *hclwrite.Body, encryption map[string]interface{}) error {
+	keyProvider, _ := encryption[EncryptionKeyProviderKey].(string)
+	method, _ := encryption[EncryptionMethodKey].(string)
+	if method == "" {
+		method = DefaultEncryptionMethod
+	}
+	enforced, _ := encryption[EncryptionEnforcedKey].(bool)
+
+	var keys []string
+	for key := range encryption {
+		if key != EncryptionKeyProviderKey && key != EncryptionMethodKey && key != EncryptionEnforcedKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	keyProviderBody := body.AppendNewBlock("key_provider", []string{keyProvider, encryptionBlockName}).Body()
+	for _, key := range keys {
+		ctyVal, err := convertValue(encryption[key])
+		if err != nil {
+			return err
+		}
+		keyProviderBody.SetAttributeValue(key, ctyVal.Value)
+	}
+
+	body.AppendNewBlock("method", []string{method, encryptionBlockName}).Body().
+		SetAttributeTraversal("keys", hcl.Traversal{hcl.TraverseRoot{Name: "key_provider"}, hcl.TraverseAttr{Name: keyProvider}, hcl.TraverseAttr{Name: encryptionBlockName}})
+
+	for _, target := range []string{"state", "plan"} {
+		targetBody := body.AppendNewBlock(target, nil).Body()
+		targetBody.SetAttributeTraversal("method", hcl.Traversal{hcl.TraverseRoot{Name: "method"}, hcl.TraverseAttr{Name: method}, hcl.TraverseAttr{Name: encryptionBlockName}})
+		if enforced {
+			targetBody.SetAttributeValue("enforced", cty.True)
+		}
+	}
+	return nil
+}
+
 func convertValue(v interface{}) (ctyjson.SimpleJSONValue, error) {
 	jsonBytes, err := json.Marshal(v)
 	if err != nil {
@@ -229,6 +339,8 @@ func GenerateConfigExistsFromString(val string) (GenerateConfigExists, error) {
 		return ExistsOverwrite, nil
 	case ExistsOverwriteTerragruntStr:
 		return ExistsOverwriteTerragrunt, nil
+	case ExistsSkipIfIdenticalStr:
+		return ExistsSkipIfIdentical, nil
 	}
 	return ExistsUnknown, errors.WithStackTrace(UnknownGenerateIfExistsVal{val: val})
 }
@@ -253,3 +365,14 @@ type GenerateFileExistsError struct {
 func (err GenerateFileExistsError) Error() string {
 	return fmt.Sprintf("Can not generate terraform file: %s already exists", err.path)
 }
+
+type GenerateOutsideModuleDirNotAllowed struct {
+	path string
+}
+
+func (err GenerateOutsideModuleDirNotAllowed) Error() string {
+	return fmt.Sprintf("Can not generate terraform file: %s is outside of the module dir, which is not allowed in the restricted mode", err.path)
+}
+
+// PolicyViolation marks the error as Terragrunt refusing to do something the options forbid.
+func (err GenerateOutsideModuleDirNotAllowed) PolicyViolation() {}
@@ -3,8 +3,12 @@ package codegen
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/stretchr/testify/require"
@@ -51,7 +55,7 @@ func TestRemoteStateConfigToTerraformCode(t *testing.T) {
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			output, err := RemoteStateConfigToTerraformCode(testCase.backend, testCase.config)
+			output, err := RemoteStateConfigToTerraformCode(testCase.backend, testCase.config, nil)
 			// validates the first output.
 			require.True(t, bytes.Contains(output, []byte(testCase.backend)))
 			require.Equal(t, testCase.expected, output)
@@ -60,7 +64,7 @@ func TestRemoteStateConfigToTerraformCode(t *testing.T) {
 			// runs the function a few of times again. All the outputs must be
 			// equal to the first output.
 			for i := 0; i < 20; i++ {
-				actual, _ := RemoteStateConfigToTerraformCode(testCase.backend, testCase.config)
+				actual, _ := RemoteStateConfigToTerraformCode(testCase.backend, testCase.config, nil)
 				require.Equal(t, output, actual)
 			}
 		})
@@ -108,8 +112,9 @@ func TestGenerateDisabling(t *testing.T) {
 			require.Nil(t, err)
 			require.NotNil(t, opts)
 
-			err = WriteToFile(opts, "", config)
+			written, err := WriteToFile(opts, "", config)
 			require.Nil(t, err)
+			require.Equal(t, !testCase.disabled, written)
 
 			if testCase.disabled {
 				require.True(t, util.FileNotExists(testCase.path))
@@ -119,3 +124,111 @@ func TestGenerateDisabling(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateRestrictedOutsideModuleDir(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := filepath.Join(t.TempDir(), "module")
+	require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+
+	opts, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+	require.NoError(t, err)
+	opts.Restricted = true
+
+	_, err = WriteToFile(opts, moduleDir, GenerateConfig{Path: "provider.tf", IfExists: ExistsError, Contents: "# provider"})
+	require.NoError(t, err)
+	require.True(t, util.FileExists(filepath.Join(moduleDir, "provider.tf")))
+
+	_, err = WriteToFile(opts, moduleDir, GenerateConfig{Path: "../outside.tf", IfExists: ExistsError, Contents: "# outside"})
+	require.IsType(t, GenerateOutsideModuleDirNotAllowed{}, errors.Unwrap(err))
+	require.True(t, util.FileNotExists(filepath.Join(moduleDir, "..", "outside.tf")))
+}
+
+func TestGenerateSkipIfIdentical(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	targetPath := filepath.Join(moduleDir, "provider.tf")
+
+	opts, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+	require.NoError(t, err)
+
+	config := GenerateConfig{Path: "provider.tf", IfExists: ExistsSkipIfIdentical, CommentPrefix: DefaultCommentPrefix, Contents: "# provider"}
+	written, err := WriteToFile(opts, moduleDir, config)
+	require.NoError(t, err)
+	require.True(t, written)
+
+	// The identical file is left untouched
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(targetPath, past, past))
+	written, err = WriteToFile(opts, moduleDir, config)
+	require.NoError(t, err)
+	require.False(t, written)
+	info, err := os.Stat(targetPath)
+	require.NoError(t, err)
+	require.True(t, info.ModTime().Equal(past))
+
+	// The different file is overwritten
+	config.Contents = "# changed provider"
+	written, err = WriteToFile(opts, moduleDir, config)
+	require.NoError(t, err)
+	require.True(t, written)
+	contents, err := os.ReadFile(targetPath)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "# changed provider")
+}
+
+func TestRemoteStateConfigToTerraformCodeWithEncryption(t *testing.T) {
+	t.Parallel()
+
+	expected := `terraform {
+  backend "s3" {
+    bucket = "state"
+  }
+  encryption {
+    key_provider "aws_kms" "default" {
+      key_spec   = "AES_256"
+      kms_key_id = "alias/state"
+      region     = "us-east-1"
+    }
+    method "aes_gcm" "default" {
+      keys = key_provider.aws_kms.default
+    }
+    state {
+      method   = method.aes_gcm.default
+      enforced = true
+    }
+    plan {
+      method   = method.aes_gcm.default
+      enforced = true
+    }
+  }
+}
+`
+	output, err := RemoteStateConfigToTerraformCode("s3", map[string]interface{}{"bucket": "state"}, map[string]interface{}{
+		"key_provider": "aws_kms",
+		"kms_key_id":   "alias/state",
+		"key_spec":     "AES_256",
+		"region":       "us-east-1",
+		"enforced":     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, string(output))
+
+	expectedBody := `key_provider "pbkdf2" "default" {
+  passphrase = "correct-horse-battery-staple"
+}
+method "aes_gcm" "default" {
+  keys = key_provider.pbkdf2.default
+}
+state {
+  method = method.aes_gcm.default
+}
+plan {
+  method = method.aes_gcm.default
+}
+`
+	output, err = EncryptionConfigToTerraformCode(map[string]interface{}{"key_provider": "pbkdf2", "passphrase": "correct-horse-battery-staple"})
+	require.NoError(t, err)
+	require.Equal(t, expectedBody, string(output))
+}
@@ -0,0 +1,295 @@
+package codegen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The manifest of the files generated by the generate blocks, in the working dir of the module.
+const GeneratedFilesManifestFile = ".terragrunt-generated.json"
+
+// The statuses of the generated files of a module.
+const (
+	// The file is up to date with its generate block.
+	GeneratedFileStatusOK = "ok"
+	// The file was modified since it was generated.
+	GeneratedFileStatusModified = "modified"
+	// The file was removed since it was generated.
+	GeneratedFileStatusMissing = "missing"
+	// The generate block of the file was removed or renamed, or now generates another file.
+	GeneratedFileStatusStale = "stale"
+	// The file of the generate block was not generated yet.
+	GeneratedFileStatusNotGenerated = "not-generated"
+)
+
+// GeneratedFilesManifest is the manifest of the files the generate blocks of a module generated in its working dir, so
+// that the files of the blocks which were removed or renamed, and the files modified by hand, can be found.
+type GeneratedFilesManifest struct {
+	Files []GeneratedFile `json:"files"`
+}
+
+// GeneratedFile is a file in the manifest of the generated files.
+type GeneratedFile struct {
+	// The path of the file, relative to the working dir
+	Path string `json:"path"`
+	// The name of the generate block of the file
+	Block string `json:"block"`
+	// The SHA256 of the contents of the file, as generated
+	Checksum string `json:"checksum"`
+}
+
+// GeneratedFileStatus is the status of a generated file of a module.
+type GeneratedFileStatus struct {
+	GeneratedFile
+	Status string
+}
+
+// ReadGeneratedFilesManifest reads the manifest of the generated files of the given working dir, empty if there is
+// none.
+func ReadGeneratedFilesManifest(workingDir string) (*GeneratedFilesManifest, error) {
+	manifestFile := filepath.Join(workingDir, GeneratedFilesManifestFile)
+	if !util.FileExists(manifestFile) {
+		return &GeneratedFilesManifest{}, nil
+	}
+
+	content, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	manifest := &GeneratedFilesManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, errors.WithStackTrace(InvalidGeneratedFilesManifest{Path: manifestFile, Err: err})
+	}
+	return manifest, nil
+}
+
+// Write writes the manifest to the given working dir, or removes it if it lists no file.
+func (manifest *GeneratedFilesManifest) Write(workingDir string) error {
+	manifestFile := filepath.Join(workingDir, GeneratedFilesManifestFile)
+	if len(manifest.Files) == 0 {
+		if err := os.Remove(manifestFile); err != nil && !os.IsNotExist(err) {
+			return errors.WithStackTrace(err)
+		}
+		return nil
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(manifestFile, content, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// file returns the file of the manifest at the given path, relative to the working dir, or nil if there is none.
+func (manifest *GeneratedFilesManifest) file(path string) *GeneratedFile {
+	for i := range manifest.Files {
+		if manifest.Files[i].Path == path {
+			return &manifest.Files[i]
+		}
+	}
+	return nil
+}
+
+// Status returns the status of the files of the manifest and of the files of the given generate blocks, by block name,
+// in the given working dir, sorted by path.
+func (manifest *GeneratedFilesManifest) Status(workingDir string, configs map[string]GenerateConfig) ([]GeneratedFileStatus, error) {
+	blockPaths := generateBlockPaths(workingDir, configs)
+
+	var statuses []GeneratedFileStatus
+	for _, file := range manifest.Files {
+		status := GeneratedFileStatus{GeneratedFile: file, Status: GeneratedFileStatusOK}
+		checksum, err := generatedFileChecksum(filepath.Join(workingDir, file.Path))
+		_, generated := blockPaths[file.Path]
+		switch {
+		case err != nil:
+			return nil, err
+		case !generated:
+			status.Status = GeneratedFileStatusStale
+		case checksum == "":
+			status.Status = GeneratedFileStatusMissing
+		case checksum != file.Checksum:
+			status.Status = GeneratedFileStatusModified
+		}
+		statuses = append(statuses, status)
+	}
+
+	for path, name := range blockPaths {
+		if manifest.file(path) == nil {
+			statuses = append(statuses, GeneratedFileStatus{GeneratedFile: GeneratedFile{Path: path, Block: name}, Status: GeneratedFileStatusNotGenerated})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Path < statuses[j].Path
+	})
+	return statuses, nil
+}
+
+// CheckGeneratedFile logs a warning if the file the given generate block generates was modified by hand since it was
+// generated, as its changes are lost once it is generated again.
+func (manifest *GeneratedFilesManifest) CheckGeneratedFile(terragruntOptions *options.TerragruntOptions, workingDir string, config GenerateConfig) error {
+	// The files of the blocks which don't overwrite them are not generated again
+	file := manifest.file(generatedFileRelPath(workingDir, config.Path))
+	if file == nil || config.Disable || config.IfExists == ExistsSkip || config.IfExists == ExistsError {
+		return nil
+	}
+
+	checksum, err := generatedFileChecksum(filepath.Join(workingDir, file.Path))
+	if err != nil {
+		return err
+	}
+	if checksum != "" && checksum != file.Checksum {
+		terragruntOptions.Logger.Warnf("The file %s generated by the generate block %q was modified by hand since it was generated. Move the changes to the generate block, as the file is generated again from it.", file.Path, file.Block)
+	}
+	return nil
+}
+
+// UpdateGeneratedFiles records the files the given generate blocks, by block name, wrote in the given working dir, as
+// set in written by block name. The files the blocks did not write, e.g. the existing files of the blocks which skip
+// them, are only kept if they were generated before, as the others were not created by Terragrunt. The files of the
+// blocks which were removed or renamed, which are stale, are kept until they are cleaned, with a warning.
+func (manifest *GeneratedFilesManifest) UpdateGeneratedFiles(terragruntOptions *options.TerragruntOptions, workingDir string, configs map[string]GenerateConfig, written map[string]bool) error {
+	blockPaths := generateBlockPaths(workingDir, configs)
+
+	files := []GeneratedFile{}
+	for path, name := range blockPaths {
+		if !written[name] {
+			if file := manifest.file(path); file != nil && util.FileExists(filepath.Join(workingDir, path)) {
+				files = append(files, GeneratedFile{Path: path, Block: name, Checksum: file.Checksum})
+			}
+			continue
+		}
+
+		checksum, err := generatedFileChecksum(filepath.Join(workingDir, path))
+		if err != nil {
+			return err
+		}
+		if checksum != "" {
+			files = append(files, GeneratedFile{Path: path, Block: name, Checksum: checksum})
+		}
+	}
+
+	for _, file := range manifest.Files {
+		if _, generated := blockPaths[file.Path]; generated {
+			continue
+		}
+		if !util.FileExists(filepath.Join(workingDir, file.Path)) {
+			continue
+		}
+		terragruntOptions.Logger.Warnf("The file %s was generated by the generate block %q, which was removed or renamed. Run `terragrunt generate clean` to remove it.", file.Path, file.Block)
+		files = append(files, file)
+	}
+
+	manifest.Files = files
+	return manifest.Write(workingDir)
+}
+
+// Clean removes the stale files of the manifest, the files of the generate blocks which were removed or renamed, except
+// the files modified by hand, returning the paths of the removed files.
+func (manifest *GeneratedFilesManifest) Clean(terragruntOptions *options.TerragruntOptions, workingDir string, configs map[string]GenerateConfig) ([]string, error) {
+	statuses, err := manifest.Status(workingDir, configs)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, status := range statuses {
+		if status.Status != GeneratedFileStatusStale {
+			continue
+		}
+		path := filepath.Join(workingDir, status.Path)
+		checksum, err := generatedFileChecksum(path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case checksum == "":
+			// Already removed
+		case checksum != status.Checksum:
+			terragruntOptions.Logger.Warnf("Not removing the stale file %s, as it was modified by hand since it was generated by the generate block %q", status.Path, status.Block)
+			continue
+		default:
+			if err := os.Remove(path); err != nil {
+				return nil, errors.WithStackTrace(err)
+			}
+			removed = append(removed, status.Path)
+		}
+
+		for i := range manifest.Files {
+			if manifest.Files[i].Path == status.Path {
+				manifest.Files = append(manifest.Files[:i], manifest.Files[i+1:]...)
+				break
+			}
+		}
+	}
+	return removed, manifest.Write(workingDir)
+}
+
+// generateBlockPaths returns the names of the enabled generate blocks of the given ones, by the path of their file
+// relative to the working dir.
+func generateBlockPaths(workingDir string, configs map[string]GenerateConfig) map[string]string {
+	blockPaths := map[string]string{}
+	for name, config := range configs {
+		if !config.Disable {
+			blockPaths[generatedFileRelPath(workingDir, config.Path)] = name
+		}
+	}
+	return blockPaths
+}
+
+// generatedFileRelPath returns the path of the file of a generate block relative to the working dir, as the path of
+// the block is relative to it, unless absolute.
+func generatedFileRelPath(workingDir string, path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	if relPath, err := filepath.Rel(workingDir, path); err == nil {
+		return filepath.ToSlash(relPath)
+	}
+	return path
+}
+
+// generatedFileChecksum returns the SHA256 of the contents of the given file, empty if it does not exist.
+func generatedFileChecksum(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Custom error types
+
+type InvalidGeneratedFilesManifest struct {
+	Path string
+	Err  error
+}
+
+func (err InvalidGeneratedFilesManifest) Error() string {
+	return fmt.Sprintf("Failed to read the manifest of the generated files %s: %v", err.Path, err.Err)
+}
+
+func (err InvalidGeneratedFilesManifest) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,107 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateFilesForTest(t *testing.T, terragruntOptions *options.TerragruntOptions, workingDir string, configs map[string]GenerateConfig) {
+	t.Helper()
+
+	manifest, err := ReadGeneratedFilesManifest(workingDir)
+	require.NoError(t, err)
+	written := map[string]bool{}
+	for name, config := range configs {
+		require.NoError(t, manifest.CheckGeneratedFile(terragruntOptions, workingDir, config))
+		written[name], err = WriteToFile(terragruntOptions, workingDir, config)
+		require.NoError(t, err)
+	}
+	require.NoError(t, manifest.UpdateGeneratedFiles(terragruntOptions, workingDir, configs, written))
+}
+
+func TestGeneratedFilesManifest(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	configs := map[string]GenerateConfig{
+		"provider": {Path: "provider.tf", IfExists: ExistsOverwrite, CommentPrefix: DefaultCommentPrefix, Contents: "provider \"aws\" {}\n"},
+		"versions": {Path: "versions.tf", IfExists: ExistsOverwriteTerragrunt, CommentPrefix: DefaultCommentPrefix, Contents: "terraform {}\n"},
+	}
+	generateFilesForTest(t, terragruntOptions, workingDir, configs)
+
+	manifest, err := ReadGeneratedFilesManifest(workingDir)
+	require.NoError(t, err)
+	statuses, err := manifest.Status(workingDir, configs)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "provider.tf", statuses[0].Path)
+	assert.Equal(t, GeneratedFileStatusOK, statuses[0].Status)
+	assert.Len(t, statuses[0].Checksum, 64)
+
+	// The versions block is removed, and the provider file modified by hand
+	delete(configs, "versions")
+	configs["backend"] = GenerateConfig{Path: "backend.tf", Contents: "terraform {}\n"}
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "provider.tf"), []byte("provider \"google\" {}\n"), 0644))
+
+	statuses, err = manifest.Status(workingDir, configs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{GeneratedFileStatusNotGenerated, GeneratedFileStatusModified, GeneratedFileStatusStale}, fileStatuses(statuses))
+
+	// The stale file is kept by the next generation, until it is cleaned
+	generateFilesForTest(t, terragruntOptions, workingDir, configs)
+	manifest, err = ReadGeneratedFilesManifest(workingDir)
+	require.NoError(t, err)
+	statuses, err = manifest.Status(workingDir, configs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{GeneratedFileStatusOK, GeneratedFileStatusOK, GeneratedFileStatusStale}, fileStatuses(statuses))
+
+	removed, err := manifest.Clean(terragruntOptions, workingDir, configs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"versions.tf"}, removed)
+	assert.NoFileExists(t, filepath.Join(workingDir, "versions.tf"))
+
+	manifest, err = ReadGeneratedFilesManifest(workingDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backend.tf", "provider.tf"}, []string{manifest.Files[0].Path, manifest.Files[1].Path})
+}
+
+func fileStatuses(statuses []GeneratedFileStatus) []string {
+	var result []string
+	for _, status := range statuses {
+		result = append(result, status.Status)
+	}
+	return result
+}
+
+func TestGeneratedFilesManifestSkippedFiles(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	// The file is written by hand, and the block skips it
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "provider.tf"), []byte("provider \"aws\" {}\n"), 0644))
+	configs := map[string]GenerateConfig{
+		"provider": {Path: "provider.tf", IfExists: ExistsSkip, CommentPrefix: DefaultCommentPrefix, Contents: "provider \"google\" {}\n"},
+	}
+	generateFilesForTest(t, terragruntOptions, workingDir, configs)
+
+	manifest, err := ReadGeneratedFilesManifest(workingDir)
+	require.NoError(t, err)
+	assert.Empty(t, manifest.Files)
+
+	// Once the block is removed, the file is not cleaned, as Terragrunt never generated it
+	removed, err := manifest.Clean(terragruntOptions, workingDir, map[string]GenerateConfig{})
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.FileExists(t, filepath.Join(workingDir, "provider.tf"))
+}
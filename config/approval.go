@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/terraform"
+)
+
+// The defaults of the approval block.
+const (
+	DefaultApprovalTimeout      = 30 * time.Minute
+	DefaultApprovalPollInterval = 30 * time.Second
+)
+
+var defaultApprovalCommands = []string{terraform.CommandNameApply, terraform.CommandNameDestroy}
+
+// ApprovalConfig is the `approval` block, requiring an external approval, recorded by the given HTTP endpoint, before the
+// given commands run.
+type ApprovalConfig struct {
+	URL          string            `hcl:"url,attr" cty:"url"`
+	Commands     []string          `hcl:"commands,optional" cty:"commands"`
+	Headers      map[string]string `hcl:"headers,optional" cty:"headers"`
+	Timeout      *string           `hcl:"timeout,optional" cty:"timeout"`
+	PollInterval *string           `hcl:"poll_interval,optional" cty:"poll_interval"`
+}
+
+func (conf *ApprovalConfig) String() string {
+	return fmt.Sprintf("Approval{URL = %v, Commands = %v}", conf.URL, conf.Commands)
+}
+
+// RequiredFor returns true if the given terraform command requires the approval.
+func (conf *ApprovalConfig) RequiredFor(command string) bool {
+	commands := conf.Commands
+	if commands == nil {
+		commands = defaultApprovalCommands
+	}
+	for _, requiredCommand := range commands {
+		if requiredCommand == command {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTimeout returns how long to wait for the approval.
+func (conf *ApprovalConfig) GetTimeout() (time.Duration, error) {
+	return parseApprovalDuration("timeout", conf.Timeout, DefaultApprovalTimeout)
+}
+
+// GetPollInterval returns how often to check if the pending approval was decided.
+func (conf *ApprovalConfig) GetPollInterval() (time.Duration, error) {
+	return parseApprovalDuration("poll_interval", conf.PollInterval, DefaultApprovalPollInterval)
+}
+
+func parseApprovalDuration(name string, value *string, defaultValue time.Duration) (time.Duration, error) {
+	if value == nil {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(*value)
+	if err != nil || duration <= 0 {
+		return 0, errors.WithStackTrace(InvalidApprovalDuration{Name: name, Value: *value})
+	}
+	return duration, nil
+}
+
+// Custom error types
+
+type InvalidApprovalDuration struct {
+	Name  string
+	Value string
+}
+
+func (err InvalidApprovalDuration) Error() string {
+	return fmt.Sprintf("Invalid %s %q of the approval block, must be a positive duration such as \"30s\" or \"1h\"", err.Name, err.Value)
+}
@@ -44,8 +44,33 @@ const (
 	MetadataDependencies                = "dependencies"
 	MetadataDependency                  = "dependency"
 	MetadataDownloadDir                 = "download_dir"
+	MetadataWorkspace                   = "workspace"
+	MetadataWorkspaces                  = "workspaces"
+	MetadataRunAfter                    = "run_after"
+	MetadataSuppressWarnings            = "suppress_warnings"
+	MetadataTags                        = "tags"
 	MetadataPreventDestroy              = "prevent_destroy"
+	MetadataPreventDestroyPaths         = "prevent_destroy_paths"
 	MetadataSkip                        = "skip"
+	MetadataExclude                     = "exclude"
+	MetadataPriority                    = "priority"
+	MetadataTerraformParallelism        = "terraform_parallelism"
+	MetadataOwner                       = "owner"
+	MetadataConcurrencyGroup            = "concurrency_group"
+	MetadataCommandAliases              = "command_aliases"
+	MetadataExecPolicy                  = "exec_policy"
+	MetadataReadOnly                    = "read_only"
+	MetadataApproval                    = "approval"
+	MetadataExportOutputs               = "export_outputs"
+	MetadataExecution                   = "execution"
+	MetadataNetwork                     = "network"
+	MetadataStateLock                   = "state_lock"
+	MetadataSourceVerification          = "source_verification"
+	MetadataProviderMatrix              = "provider_matrix"
+	MetadataProviderVersionOverrides    = "provider_version_overrides"
+	MetadataApprovedProviders           = "approved_providers"
+	MetadataProfile                     = "profile"
+	MetadataUseProfile                  = "use_profile"
 	MetadataIamRole                     = "iam_role"
 	MetadataIamAssumeRoleDuration       = "iam_assume_role_duration"
 	MetadataIamAssumeRoleSessionName    = "iam_assume_role_session_name"
@@ -87,8 +112,33 @@ type TerragruntConfig struct {
 	RemoteState                 *remote.RemoteState
 	Dependencies                *ModuleDependencies
 	DownloadDir                 string
+	Workspace                   string
+	Workspaces                  []string
+	RunAfter                    []string
+	SuppressWarnings            []string
+	Tags                        []string
 	PreventDestroy              *bool
+	PreventDestroyPaths         []string
 	Skip                        bool
+	Exclude                     *ExcludeConfig
+	Priority                    *int
+	TerraformParallelism        *int
+	Owner                       string
+	ConcurrencyGroup            string
+	CommandAliases              map[string][]string
+	ExecPolicy                  *ExecPolicyConfig
+	ReadOnly                    *bool
+	Approval                    *ApprovalConfig
+	ExportOutputs               []ExportOutputsConfig
+	Execution                   *ExecutionConfig
+	Network                     *NetworkConfig
+	StateLock                   *StateLockConfig
+	SourceVerification          *SourceVerificationConfig
+	ProviderMatrix              *ProviderMatrixConfig
+	ProviderVersionOverrides    *ProviderVersionOverridesConfig
+	ApprovedProviders           []string
+	Profiles                    []ProfileConfig
+	UseProfile                  string
 	IamRole                     string
 	IamAssumeRoleDuration       *int64
 	IamAssumeRoleSessionName    string
@@ -119,10 +169,14 @@ func (conf *TerragruntConfig) String() string {
 }
 
 // GetIAMRoleOptions is a helper function that converts the Terragrunt config IAM role attributes to
-// options.IAMRoleOptions struct.
+// options.IAMRoleOptions struct. Without iam_role, the role is the one of the profile selected by use_profile, if any.
 func (conf *TerragruntConfig) GetIAMRoleOptions() options.IAMRoleOptions {
+	roleARN := conf.IamRole
+	if roleARN == "" {
+		roleARN = conf.profileRoleARN()
+	}
 	configIAMRoleOptions := options.IAMRoleOptions{
-		RoleARN:               conf.IamRole,
+		RoleARN:               roleARN,
 		AssumeRoleSessionName: conf.IamAssumeRoleSessionName,
 	}
 	if conf.IamAssumeRoleDuration != nil {
@@ -157,14 +211,39 @@ type terragruntConfigFile struct {
 	RemoteState     *remoteStateConfigFile `hcl:"remote_state,block"`
 	RemoteStateAttr *cty.Value             `hcl:"remote_state,optional"`
 
-	Dependencies             *ModuleDependencies `hcl:"dependencies,block"`
-	DownloadDir              *string             `hcl:"download_dir,attr"`
-	PreventDestroy           *bool               `hcl:"prevent_destroy,attr"`
-	Skip                     *bool               `hcl:"skip,attr"`
-	IamRole                  *string             `hcl:"iam_role,attr"`
-	IamAssumeRoleDuration    *int64              `hcl:"iam_assume_role_duration,attr"`
-	IamAssumeRoleSessionName *string             `hcl:"iam_assume_role_session_name,attr"`
-	TerragruntDependencies   []Dependency        `hcl:"dependency,block"`
+	Dependencies             *ModuleDependencies             `hcl:"dependencies,block"`
+	DownloadDir              *string                         `hcl:"download_dir,attr"`
+	Workspace                *string                         `hcl:"workspace,attr"`
+	Workspaces               []string                        `hcl:"workspaces,optional"`
+	RunAfter                 []string                        `hcl:"run_after,optional"`
+	SuppressWarnings         []string                        `hcl:"suppress_warnings,optional"`
+	Tags                     []string                        `hcl:"tags,optional"`
+	PreventDestroy           *bool                           `hcl:"prevent_destroy,attr"`
+	PreventDestroyPaths      []string                        `hcl:"prevent_destroy_paths,optional"`
+	Skip                     *bool                           `hcl:"skip,attr"`
+	Exclude                  *ExcludeConfig                  `hcl:"exclude,block"`
+	Priority                 *int                            `hcl:"priority,attr"`
+	TerraformParallelism     *int                            `hcl:"terraform_parallelism,attr"`
+	Owner                    *string                         `hcl:"owner,attr"`
+	ConcurrencyGroup         *string                         `hcl:"concurrency_group,attr"`
+	CommandAliases           map[string][]string             `hcl:"command_aliases,optional"`
+	ExecPolicy               *ExecPolicyConfig               `hcl:"exec_policy,block"`
+	ReadOnly                 *bool                           `hcl:"read_only,attr"`
+	Approval                 *ApprovalConfig                 `hcl:"approval,block"`
+	ExportOutputs            []ExportOutputsConfig           `hcl:"export_outputs,block"`
+	Execution                *ExecutionConfig                `hcl:"execution,block"`
+	Network                  *NetworkConfig                  `hcl:"network,block"`
+	StateLock                *StateLockConfig                `hcl:"state_lock,block"`
+	SourceVerification       *SourceVerificationConfig       `hcl:"source_verification,block"`
+	ProviderMatrix           *ProviderMatrixConfig           `hcl:"provider_matrix,block"`
+	ProviderVersionOverrides *ProviderVersionOverridesConfig `hcl:"provider_version_overrides,block"`
+	ApprovedProviders        []string                        `hcl:"approved_providers,optional"`
+	Profiles                 []ProfileConfig                 `hcl:"profile,block"`
+	UseProfile               *string                         `hcl:"use_profile,attr"`
+	IamRole                  *string                         `hcl:"iam_role,attr"`
+	IamAssumeRoleDuration    *int64                          `hcl:"iam_assume_role_duration,attr"`
+	IamAssumeRoleSessionName *string                         `hcl:"iam_assume_role_session_name,attr"`
+	TerragruntDependencies   []Dependency                    `hcl:"dependency,block"`
 
 	// We allow users to configure code generation via blocks:
 	//
@@ -215,6 +294,7 @@ type remoteStateConfigFile struct {
 	DisableDependencyOptimization *bool                      `hcl:"disable_dependency_optimization,attr"`
 	Generate                      *remoteStateConfigGenerate `hcl:"generate,attr"`
 	Config                        cty.Value                  `hcl:"config,attr"`
+	Encryption                    *cty.Value                 `hcl:"encryption,attr"`
 }
 
 func (remoteState *remoteStateConfigFile) String() string {
@@ -239,6 +319,13 @@ func (remoteState *remoteStateConfigFile) toConfig() (*remote.RemoteState, error
 	}
 	config.Config = remoteStateConfig
 
+	if remoteState.Encryption != nil && !remoteState.Encryption.IsNull() {
+		config.Encryption, err = parseCtyValueToMap(*remoteState.Encryption)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if remoteState.DisableInit != nil {
 		config.DisableInit = *remoteState.DisableInit
 	}
@@ -266,9 +353,12 @@ type terragruntGenerateBlock struct {
 	Path             string  `hcl:"path,attr" mapstructure:"path"`
 	IfExists         string  `hcl:"if_exists,attr" mapstructure:"if_exists"`
 	CommentPrefix    *string `hcl:"comment_prefix,attr" mapstructure:"comment_prefix"`
-	Contents         string  `hcl:"contents,attr" mapstructure:"contents"`
+	Contents         string  `hcl:"contents,optional" mapstructure:"contents"`
+	Source           string  `hcl:"source,optional" mapstructure:"source"`
+	Checksum         string  `hcl:"checksum,optional" mapstructure:"checksum"`
 	DisableSignature *bool   `hcl:"disable_signature,attr" mapstructure:"disable_signature"`
 	Disable          *bool   `hcl:"disable,attr" mapstructure:"disable"`
+	Enabled          *bool   `hcl:"enabled,attr" mapstructure:"enabled"`
 }
 
 type IncludeConfigs map[string]IncludeConfig
@@ -291,6 +381,10 @@ type IncludeConfig struct {
 	Path          string  `hcl:"path,attr"`
 	Expose        *bool   `hcl:"expose,attr"`
 	MergeStrategy *string `hcl:"merge_strategy,attr"`
+
+	// MergeStrategies are the merge strategies of blocks and attributes, by name, overriding MergeStrategy for them, e.g.
+	// { inputs = "deep", before_hook = "replace" }.
+	MergeStrategies map[string]string `hcl:"merge_strategies,optional"`
 }
 
 func (cfg *IncludeConfig) String() string {
@@ -406,6 +500,10 @@ type TerraformConfig struct {
 	// Ideally we can avoid the pointer to list slice, but if it is not a pointer, Terraform requires the attribute to
 	// be defined and we want to make this optional.
 	IncludeInCopy *[]string `hcl:"include_in_copy,attr"`
+
+	// The checksum the registry module of the source must have, e.g. "h1:...", instead of the one recorded by the prior
+	// runs.
+	SourceChecksum *string `hcl:"source_checksum,attr"`
 }
 
 func (conf *TerraformConfig) String() string {
@@ -462,6 +560,13 @@ type TerraformExtraArguments struct {
 	OptionalVarFiles *[]string          `hcl:"optional_var_files,attr" cty:"optional_var_files"`
 	Commands         []string           `hcl:"commands,attr" cty:"commands"`
 	EnvVars          *map[string]string `hcl:"env_vars,attr" cty:"env_vars"`
+	Enabled          *bool              `hcl:"enabled,attr" cty:"enabled"`
+}
+
+// IsEnabled returns true unless the extra arguments set `enabled = false`, e.g. with an expression toggling them per
+// environment.
+func (conf *TerraformExtraArguments) IsEnabled() bool {
+	return conf.Enabled == nil || *conf.Enabled
 }
 
 func (conf *TerraformExtraArguments) String() string {
@@ -709,7 +814,9 @@ func ParseConfigFile(opts *options.TerragruntOptions, ctx *ParsingContext, confi
 		}
 		var file *hclparse.File
 		var cacheKey = fmt.Sprintf("parse-config-%v-%v-%v-%v-%v-%v", configPath, childKey, decodeListKey, opts.WorkingDir, dir, fileInfo.ModTime().UnixMicro())
-		if cacheConfig, found := hclCache.Get(cacheKey); found {
+		cacheConfig, found := hclCache.Get(cacheKey)
+		util.CountCacheLookup(util.ParsedConfigCache, found)
+		if found {
 			file = cacheConfig
 		} else {
 			// Parse the HCL file into an AST body that can be decoded multiple times later without having to re-parse
@@ -792,11 +899,12 @@ func ParseConfig(ctx *ParsingContext, file *hclparse.File, includeFromChild *Inc
 	if ctx.DecodedDependencies == nil {
 		// Decode just the `dependency` blocks, retrieving the outputs from the target terragrunt config in the
 		// process.
-		retrievedOutputs, err := decodeAndRetrieveOutputs(ctx, file)
+		retrievedOutputs, dependencyBlocks, err := decodeAndRetrieveOutputs(ctx, file)
 		if err != nil {
 			return nil, err
 		}
 		ctx.DecodedDependencies = retrievedOutputs
+		ctx.DecodedDependencyBlocks = dependencyBlocks
 	}
 
 	evalContext, err := createTerragruntEvalContext(ctx, file.ConfigPath)
@@ -872,6 +980,11 @@ func setIAMRole(ctx *ParsingContext, file *hclparse.File, includeFromChild *Incl
 func decodeAsTerragruntConfigFile(ctx *ParsingContext, file *hclparse.File, evalContext *hcl.EvalContext) (*terragruntConfigFile, error) {
 	terragruntConfig := terragruntConfigFile{}
 
+	// The references to the outputs of the dependencies are described here, as only the full config references them
+	file.WithOptions(hclparse.WithDiagnosticsUpdate(func(diags hcl.Diagnostics) hcl.Diagnostics {
+		return describeMissingDependencyOutputs(ctx, diags)
+	}))
+
 	if err := file.Decode(&terragruntConfig, evalContext); err != nil {
 		diagErr, ok := errors.Unwrap(err).(hcl.Diagnostics)
 
@@ -996,6 +1109,15 @@ func convertToTerragruntConfig(ctx *ParsingContext, configPath string, terragrun
 		terragruntConfig.SetFieldMetadataWithType(MetadataDependency, dep.Name, defaultMetadata)
 	}
 
+	// An untrusted config could run any binary, or ship the module and its env to any host
+	if ctx.TerragruntOptions.Restricted && (terragruntConfigFromFile.TerraformBinary != nil || terragruntConfigFromFile.Execution != nil) {
+		setting := MetadataTerraformBinary
+		if terragruntConfigFromFile.Execution != nil {
+			setting = MetadataExecution
+		}
+		return nil, errors.WithStackTrace(RestrictedConfigNotAllowed{Setting: setting, ConfigPath: configPath})
+	}
+
 	if terragruntConfigFromFile.TerraformBinary != nil {
 		terragruntConfig.TerraformBinary = *terragruntConfigFromFile.TerraformBinary
 		terragruntConfig.SetFieldMetadata(MetadataTerraformBinary, defaultMetadata)
@@ -1021,6 +1143,34 @@ func convertToTerragruntConfig(ctx *ParsingContext, configPath string, terragrun
 		terragruntConfig.SetFieldMetadata(MetadataDownloadDir, defaultMetadata)
 	}
 
+	if terragruntConfigFromFile.Workspace != nil {
+		terragruntConfig.Workspace = *terragruntConfigFromFile.Workspace
+		terragruntConfig.SetFieldMetadata(MetadataWorkspace, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Workspaces != nil {
+		terragruntConfig.Workspaces = terragruntConfigFromFile.Workspaces
+		terragruntConfig.SetFieldMetadata(MetadataWorkspaces, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.RunAfter != nil {
+		terragruntConfig.RunAfter = terragruntConfigFromFile.RunAfter
+		terragruntConfig.SetFieldMetadata(MetadataRunAfter, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.SuppressWarnings != nil {
+		if err := options.ValidateWarningCodes(terragruntConfigFromFile.SuppressWarnings); err != nil {
+			return nil, err
+		}
+		terragruntConfig.SuppressWarnings = terragruntConfigFromFile.SuppressWarnings
+		terragruntConfig.SetFieldMetadata(MetadataSuppressWarnings, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Tags != nil {
+		terragruntConfig.Tags = terragruntConfigFromFile.Tags
+		terragruntConfig.SetFieldMetadata(MetadataTags, defaultMetadata)
+	}
+
 	if terragruntConfigFromFile.TerraformVersionConstraint != nil {
 		terragruntConfig.TerraformVersionConstraint = *terragruntConfigFromFile.TerraformVersionConstraint
 		terragruntConfig.SetFieldMetadata(MetadataTerraformVersionConstraint, defaultMetadata)
@@ -1036,11 +1186,140 @@ func convertToTerragruntConfig(ctx *ParsingContext, configPath string, terragrun
 		terragruntConfig.SetFieldMetadata(MetadataPreventDestroy, defaultMetadata)
 	}
 
+	if terragruntConfigFromFile.PreventDestroyPaths != nil {
+		if terragruntConfig.PreventDestroyPaths, err = canonicalPreventDestroyPaths(configPath, terragruntConfigFromFile.PreventDestroyPaths); err != nil {
+			return nil, err
+		}
+		terragruntConfig.SetFieldMetadata(MetadataPreventDestroyPaths, defaultMetadata)
+	}
+
 	if terragruntConfigFromFile.Skip != nil {
 		terragruntConfig.Skip = *terragruntConfigFromFile.Skip
 		terragruntConfig.SetFieldMetadata(MetadataSkip, defaultMetadata)
 	}
 
+	if terragruntConfigFromFile.Exclude != nil {
+		terragruntConfig.Exclude = terragruntConfigFromFile.Exclude
+		terragruntConfig.SetFieldMetadata(MetadataExclude, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Priority != nil {
+		terragruntConfig.Priority = terragruntConfigFromFile.Priority
+		terragruntConfig.SetFieldMetadata(MetadataPriority, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.TerraformParallelism != nil {
+		if *terragruntConfigFromFile.TerraformParallelism < 1 {
+			return nil, errors.WithStackTrace(InvalidTerraformParallelism(*terragruntConfigFromFile.TerraformParallelism))
+		}
+		terragruntConfig.TerraformParallelism = terragruntConfigFromFile.TerraformParallelism
+		terragruntConfig.SetFieldMetadata(MetadataTerraformParallelism, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Owner != nil {
+		terragruntConfig.Owner = *terragruntConfigFromFile.Owner
+		terragruntConfig.SetFieldMetadata(MetadataOwner, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ConcurrencyGroup != nil {
+		terragruntConfig.ConcurrencyGroup = *terragruntConfigFromFile.ConcurrencyGroup
+		terragruntConfig.SetFieldMetadata(MetadataConcurrencyGroup, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.CommandAliases != nil {
+		terragruntConfig.CommandAliases = terragruntConfigFromFile.CommandAliases
+		terragruntConfig.SetFieldMetadata(MetadataCommandAliases, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ExecPolicy != nil {
+		terragruntConfig.ExecPolicy = terragruntConfigFromFile.ExecPolicy
+		terragruntConfig.SetFieldMetadata(MetadataExecPolicy, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ReadOnly != nil {
+		terragruntConfig.ReadOnly = terragruntConfigFromFile.ReadOnly
+		terragruntConfig.SetFieldMetadata(MetadataReadOnly, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Approval != nil {
+		terragruntConfig.Approval = terragruntConfigFromFile.Approval
+		terragruntConfig.SetFieldMetadata(MetadataApproval, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ExportOutputs != nil {
+		if err := validateExportOutputs(terragruntConfigFromFile.ExportOutputs); err != nil {
+			return nil, err
+		}
+		terragruntConfig.ExportOutputs = terragruntConfigFromFile.ExportOutputs
+		terragruntConfig.SetFieldMetadata(MetadataExportOutputs, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Execution != nil {
+		if err := terragruntConfigFromFile.Execution.Validate(); err != nil {
+			return nil, err
+		}
+		terragruntConfig.Execution = terragruntConfigFromFile.Execution
+		terragruntConfig.SetFieldMetadata(MetadataExecution, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Network != nil {
+		if err := terragruntConfigFromFile.Network.Validate(); err != nil {
+			return nil, err
+		}
+		terragruntConfig.Network = terragruntConfigFromFile.Network
+		terragruntConfig.SetFieldMetadata(MetadataNetwork, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.StateLock != nil {
+		if err := terragruntConfigFromFile.StateLock.Validate(); err != nil {
+			return nil, err
+		}
+		terragruntConfig.StateLock = terragruntConfigFromFile.StateLock
+		terragruntConfig.SetFieldMetadata(MetadataStateLock, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.SourceVerification != nil {
+		if err := terragruntConfigFromFile.SourceVerification.Validate(); err != nil {
+			return nil, err
+		}
+		terragruntConfig.SourceVerification = terragruntConfigFromFile.SourceVerification
+		terragruntConfig.SetFieldMetadata(MetadataSourceVerification, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ProviderMatrix != nil {
+		if err := terragruntConfigFromFile.ProviderMatrix.Validate(); err != nil {
+			return nil, err
+		}
+		terragruntConfig.ProviderMatrix = terragruntConfigFromFile.ProviderMatrix
+		terragruntConfig.SetFieldMetadata(MetadataProviderMatrix, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ProviderVersionOverrides != nil {
+		if err := terragruntConfigFromFile.ProviderVersionOverrides.Validate(); err != nil {
+			return nil, err
+		}
+		terragruntConfig.ProviderVersionOverrides = terragruntConfigFromFile.ProviderVersionOverrides
+		terragruntConfig.SetFieldMetadata(MetadataProviderVersionOverrides, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.ApprovedProviders != nil {
+		terragruntConfig.ApprovedProviders = terragruntConfigFromFile.ApprovedProviders
+		terragruntConfig.SetFieldMetadata(MetadataApprovedProviders, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Profiles != nil {
+		if err := validateProfiles(terragruntConfigFromFile.Profiles); err != nil {
+			return nil, err
+		}
+		terragruntConfig.Profiles = terragruntConfigFromFile.Profiles
+		terragruntConfig.SetFieldMetadata(MetadataProfile, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.UseProfile != nil {
+		terragruntConfig.UseProfile = *terragruntConfigFromFile.UseProfile
+		terragruntConfig.SetFieldMetadata(MetadataUseProfile, defaultMetadata)
+	}
+
 	if terragruntConfigFromFile.IamRole != nil {
 		terragruntConfig.IamRole = *terragruntConfigFromFile.IamRole
 		terragruntConfig.SetFieldMetadata(MetadataIamRole, defaultMetadata)
@@ -1087,6 +1366,8 @@ func convertToTerragruntConfig(ctx *ParsingContext, configPath string, terragrun
 			IfExists:    ifExists,
 			IfExistsStr: block.IfExists,
 			Contents:    block.Contents,
+			Source:      block.Source,
+			Checksum:    block.Checksum,
 		}
 		if block.CommentPrefix == nil {
 			genConfig.CommentPrefix = codegen.DefaultCommentPrefix
@@ -1103,6 +1384,10 @@ func convertToTerragruntConfig(ctx *ParsingContext, configPath string, terragrun
 		} else {
 			genConfig.Disable = *block.Disable
 		}
+		// The block is disabled by either `disable = true` or `enabled = false`
+		if block.Enabled != nil && !*block.Enabled {
+			genConfig.Disable = true
+		}
 		terragruntConfig.GenerateConfigs[block.Name] = genConfig
 		terragruntConfig.SetFieldMetadataWithType(MetadataGenerateConfigs, block.Name, defaultMetadata)
 	}
@@ -1129,6 +1414,20 @@ func convertToTerragruntConfig(ctx *ParsingContext, configPath string, terragrun
 	return terragruntConfig, nil
 }
 
+// canonicalPreventDestroyPaths returns the canonical paths of the modules matching the given prevent_destroy_paths
+// globs, relative to the dir of the config declaring them, so that the paths keep their meaning when the config is
+// included.
+func canonicalPreventDestroyPaths(configPath string, paths []string) ([]string, error) {
+	canonicalPaths, err := util.GlobCanonicalPath(filepath.Dir(configPath), paths...)
+	if err != nil {
+		return nil, err
+	}
+	if canonicalPaths == nil {
+		canonicalPaths = []string{}
+	}
+	return canonicalPaths, nil
+}
+
 // Iterate over dependencies paths and check if directories exists, return error with all missing dependencies
 func validateDependencies(ctx *ParsingContext, dependencies *ModuleDependencies) error {
 	var missingDependencies []string
@@ -1163,6 +1462,13 @@ func validateGenerateBlocks(blocks *[]terragruntGenerateBlock) error {
 			continue
 		}
 		blockNames[block.Name] = true
+
+		if block.Source != "" && block.Contents != "" {
+			return errors.WithStackTrace(GenerateContentsAndSourceError{block.Name})
+		}
+		if block.Checksum != "" && block.Source == "" {
+			return errors.WithStackTrace(GenerateChecksumWithoutSourceError{block.Name})
+		}
 	}
 	if len(duplicatedGenerateBlockNames) != 0 {
 		return DuplicatedGenerateBlocksError{duplicatedGenerateBlockNames}
@@ -24,6 +24,9 @@ func TerragruntConfigAsCty(config *TerragruntConfig) (cty.Value, error) {
 	output[MetadataTerraformVersionConstraint] = gostringToCty(config.TerraformVersionConstraint)
 	output[MetadataTerragruntVersionConstraint] = gostringToCty(config.TerragruntVersionConstraint)
 	output[MetadataDownloadDir] = gostringToCty(config.DownloadDir)
+	output[MetadataWorkspace] = gostringToCty(config.Workspace)
+	output[MetadataOwner] = gostringToCty(config.Owner)
+	output[MetadataConcurrencyGroup] = gostringToCty(config.ConcurrencyGroup)
 	output[MetadataIamRole] = gostringToCty(config.IamRole)
 	output[MetadataSkip] = goboolToCty(config.Skip)
 	output[MetadataIamAssumeRoleSessionName] = gostringToCty(config.IamAssumeRoleSessionName)
@@ -88,6 +91,70 @@ func TerragruntConfigAsCty(config *TerragruntConfig) (cty.Value, error) {
 		output[MetadataRetryableErrors] = retryableCty
 	}
 
+	approvedProvidersCty, err := goTypeToCty(config.ApprovedProviders)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if approvedProvidersCty != cty.NilVal {
+		output[MetadataApprovedProviders] = approvedProvidersCty
+	}
+
+	profilesCty, err := profileBlocksAsCty(config.Profiles)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if profilesCty != cty.NilVal {
+		output[MetadataProfile] = profilesCty
+	}
+
+	useProfileCty, err := goTypeToCty(config.UseProfile)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if useProfileCty != cty.NilVal {
+		output[MetadataUseProfile] = useProfileCty
+	}
+
+	workspacesCty, err := goTypeToCty(config.Workspaces)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if workspacesCty != cty.NilVal {
+		output[MetadataWorkspaces] = workspacesCty
+	}
+
+	runAfterCty, err := goTypeToCty(config.RunAfter)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if runAfterCty != cty.NilVal {
+		output[MetadataRunAfter] = runAfterCty
+	}
+
+	suppressWarningsCty, err := goTypeToCty(config.SuppressWarnings)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if suppressWarningsCty != cty.NilVal {
+		output[MetadataSuppressWarnings] = suppressWarningsCty
+	}
+
+	tagsCty, err := goTypeToCty(config.Tags)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if tagsCty != cty.NilVal {
+		output[MetadataTags] = tagsCty
+	}
+
+	preventDestroyPathsCty, err := goTypeToCty(config.PreventDestroyPaths)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if preventDestroyPathsCty != cty.NilVal {
+		output[MetadataPreventDestroyPaths] = preventDestroyPathsCty
+	}
+
 	iamAssumeRoleDurationCty, err := goTypeToCty(config.IamAssumeRoleDuration)
 	if err != nil {
 		return cty.NilVal, err
@@ -105,6 +172,118 @@ func TerragruntConfigAsCty(config *TerragruntConfig) (cty.Value, error) {
 		output[MetadataRetryMaxAttempts] = retryMaxAttemptsCty
 	}
 
+	priorityCty, err := goTypeToCty(config.Priority)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if priorityCty != cty.NilVal {
+		output[MetadataPriority] = priorityCty
+	}
+
+	terraformParallelismCty, err := goTypeToCty(config.TerraformParallelism)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if terraformParallelismCty != cty.NilVal {
+		output[MetadataTerraformParallelism] = terraformParallelismCty
+	}
+
+	commandAliasesCty, err := goTypeToCty(config.CommandAliases)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if commandAliasesCty != cty.NilVal {
+		output[MetadataCommandAliases] = commandAliasesCty
+	}
+
+	execPolicyCty, err := goTypeToCty(config.ExecPolicy)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if execPolicyCty != cty.NilVal {
+		output[MetadataExecPolicy] = execPolicyCty
+	}
+
+	readOnlyCty, err := goTypeToCty(config.ReadOnly)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if readOnlyCty != cty.NilVal {
+		output[MetadataReadOnly] = readOnlyCty
+	}
+
+	excludeCty, err := goTypeToCty(config.Exclude)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if excludeCty != cty.NilVal {
+		output[MetadataExclude] = excludeCty
+	}
+
+	approvalCty, err := goTypeToCty(config.Approval)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if approvalCty != cty.NilVal {
+		output[MetadataApproval] = approvalCty
+	}
+
+	exportOutputsCty, err := exportOutputsBlocksAsCty(config.ExportOutputs)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if exportOutputsCty != cty.NilVal {
+		output[MetadataExportOutputs] = exportOutputsCty
+	}
+
+	executionCty, err := goTypeToCty(config.Execution)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if executionCty != cty.NilVal {
+		output[MetadataExecution] = executionCty
+	}
+
+	networkCty, err := goTypeToCty(config.Network)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if networkCty != cty.NilVal {
+		output[MetadataNetwork] = networkCty
+	}
+
+	stateLockCty, err := goTypeToCty(config.StateLock)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if stateLockCty != cty.NilVal {
+		output[MetadataStateLock] = stateLockCty
+	}
+
+	sourceVerificationCty, err := goTypeToCty(config.SourceVerification)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if sourceVerificationCty != cty.NilVal {
+		output[MetadataSourceVerification] = sourceVerificationCty
+	}
+
+	providerMatrixCty, err := providerMatrixAsCty(config.ProviderMatrix)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if providerMatrixCty != cty.NilVal {
+		output[MetadataProviderMatrix] = providerMatrixCty
+	}
+
+	providerVersionOverridesCty, err := providerVersionOverridesAsCty(config.ProviderVersionOverrides)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if providerVersionOverridesCty != cty.NilVal {
+		output[MetadataProviderVersionOverrides] = providerVersionOverridesCty
+	}
+
 	retrySleepIntervalSecCty, err := goTypeToCty(config.RetrySleepIntervalSec)
 	if err != nil {
 		return cty.NilVal, err
@@ -162,6 +341,18 @@ func TerragruntConfigAsCtyWithMetadata(config *TerragruntConfig) (cty.Value, err
 		return cty.NilVal, err
 	}
 
+	if err := wrapWithMetadata(config, config.Workspace, MetadataWorkspace, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.Owner, MetadataOwner, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.ConcurrencyGroup, MetadataConcurrencyGroup, &output); err != nil {
+		return cty.NilVal, err
+	}
+
 	if err := wrapWithMetadata(config, config.IamRole, MetadataIamRole, &output); err != nil {
 		return cty.NilVal, err
 	}
@@ -184,6 +375,44 @@ func TerragruntConfigAsCtyWithMetadata(config *TerragruntConfig) (cty.Value, err
 		return cty.NilVal, err
 	}
 
+	if err := wrapWithMetadata(config, config.ApprovedProviders, MetadataApprovedProviders, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if config.Profiles != nil {
+		profilesCty, err := profileBlocksAsCty(config.Profiles)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if err := wrapWithMetadata(config, profilesCty, MetadataProfile, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if err := wrapWithMetadata(config, config.UseProfile, MetadataUseProfile, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.Workspaces, MetadataWorkspaces, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.RunAfter, MetadataRunAfter, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.SuppressWarnings, MetadataSuppressWarnings, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.Tags, MetadataTags, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.PreventDestroyPaths, MetadataPreventDestroyPaths, &output); err != nil {
+		return cty.NilVal, err
+	}
+
 	if err := wrapWithMetadata(config, config.IamAssumeRoleDuration, MetadataIamAssumeRoleDuration, &output); err != nil {
 		return cty.NilVal, err
 	}
@@ -195,6 +424,80 @@ func TerragruntConfigAsCtyWithMetadata(config *TerragruntConfig) (cty.Value, err
 		return cty.NilVal, err
 	}
 
+	if err := wrapWithMetadata(config, config.Priority, MetadataPriority, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.TerraformParallelism, MetadataTerraformParallelism, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.CommandAliases, MetadataCommandAliases, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.ExecPolicy, MetadataExecPolicy, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.ReadOnly, MetadataReadOnly, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.Exclude, MetadataExclude, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.Approval, MetadataApproval, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if config.ExportOutputs != nil {
+		exportOutputsCty, err := exportOutputsBlocksAsCty(config.ExportOutputs)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if err := wrapWithMetadata(config, exportOutputsCty, MetadataExportOutputs, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if err := wrapWithMetadata(config, config.Execution, MetadataExecution, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.Network, MetadataNetwork, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.StateLock, MetadataStateLock, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, config.SourceVerification, MetadataSourceVerification, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if config.ProviderMatrix != nil {
+		providerMatrixCty, err := providerMatrixAsCty(config.ProviderMatrix)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if err := wrapWithMetadata(config, providerMatrixCty, MetadataProviderMatrix, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if config.ProviderVersionOverrides != nil {
+		providerVersionOverridesCty, err := providerVersionOverridesAsCty(config.ProviderVersionOverrides)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		if err := wrapWithMetadata(config, providerVersionOverridesCty, MetadataProviderVersionOverrides, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
 	if err := wrapWithMetadata(config, config.DependentModulesPath, MetadataDependentModules, &output); err != nil {
 		return cty.NilVal, err
 	}
@@ -397,12 +700,13 @@ func catalogConfigAsCty(config *CatalogConfig) (cty.Value, error) {
 // ctyTerraformConfig is an alternate representation of TerraformConfig that converts internal blocks into a map that
 // maps the name to the underlying struct, as opposed to a list representation.
 type ctyTerraformConfig struct {
-	ExtraArgs     map[string]TerraformExtraArguments `cty:"extra_arguments"`
-	Source        *string                            `cty:"source"`
-	IncludeInCopy *[]string                          `cty:"include_in_copy"`
-	BeforeHooks   map[string]Hook                    `cty:"before_hook"`
-	AfterHooks    map[string]Hook                    `cty:"after_hook"`
-	ErrorHooks    map[string]ErrorHook               `cty:"error_hook"`
+	ExtraArgs      map[string]TerraformExtraArguments `cty:"extra_arguments"`
+	Source         *string                            `cty:"source"`
+	SourceChecksum *string                            `cty:"source_checksum"`
+	IncludeInCopy  *[]string                          `cty:"include_in_copy"`
+	BeforeHooks    map[string]Hook                    `cty:"before_hook"`
+	AfterHooks     map[string]Hook                    `cty:"after_hook"`
+	ErrorHooks     map[string]ErrorHook               `cty:"error_hook"`
 }
 
 // Serialize TerraformConfig to a cty Value, but with maps instead of lists for the blocks.
@@ -412,12 +716,13 @@ func terraformConfigAsCty(config *TerraformConfig) (cty.Value, error) {
 	}
 
 	configCty := ctyTerraformConfig{
-		Source:        config.Source,
-		IncludeInCopy: config.IncludeInCopy,
-		ExtraArgs:     map[string]TerraformExtraArguments{},
-		BeforeHooks:   map[string]Hook{},
-		AfterHooks:    map[string]Hook{},
-		ErrorHooks:    map[string]ErrorHook{},
+		Source:         config.Source,
+		SourceChecksum: config.SourceChecksum,
+		IncludeInCopy:  config.IncludeInCopy,
+		ExtraArgs:      map[string]TerraformExtraArguments{},
+		BeforeHooks:    map[string]Hook{},
+		AfterHooks:     map[string]Hook{},
+		ErrorHooks:     map[string]ErrorHook{},
 	}
 
 	for _, arg := range config.ExtraArgs {
@@ -460,6 +765,14 @@ func remoteStateAsCty(remoteState *remote.RemoteState) (cty.Value, error) {
 	}
 	output["config"] = ctyJsonVal
 
+	if remoteState.Encryption != nil {
+		encryptionCty, err := convertToCtyWithJson(remoteState.Encryption)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output["encryption"] = encryptionCty
+	}
+
 	return convertValuesMapToCtyVal(output)
 }
 
@@ -476,6 +789,94 @@ func dependencyBlocksAsCty(dependencyBlocks []Dependency) (cty.Value, error) {
 	return convertValuesMapToCtyVal(out)
 }
 
+// exportOutputsBlocksAsCty converts the export_outputs blocks to a cty Value as a map of the blocks by name.
+func exportOutputsBlocksAsCty(blocks []ExportOutputsConfig) (cty.Value, error) {
+	if blocks == nil {
+		return cty.NilVal, nil
+	}
+
+	out := map[string]cty.Value{}
+	for _, block := range blocks {
+		blockCty, err := goTypeToCty(block)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		out[block.Name] = blockCty
+	}
+	return convertValuesMapToCtyVal(out)
+}
+
+// profileBlocksAsCty converts the profile blocks to a cty Value as a map of the blocks by name.
+func profileBlocksAsCty(blocks []ProfileConfig) (cty.Value, error) {
+	if blocks == nil {
+		return cty.NilVal, nil
+	}
+
+	out := map[string]cty.Value{}
+	for _, block := range blocks {
+		blockCty, err := goTypeToCty(block)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		out[block.Name] = blockCty
+	}
+	return convertValuesMapToCtyVal(out)
+}
+
+// providerMatrixAsCty converts the provider matrix to a cty Value, with the providers in a map by name, as the configs
+// of the providers have different types, which a list cannot hold.
+func providerMatrixAsCty(matrix *ProviderMatrixConfig) (cty.Value, error) {
+	if matrix == nil {
+		return cty.NilVal, nil
+	}
+
+	providers := map[string]cty.Value{}
+	for _, provider := range matrix.Providers {
+		providerCty, err := goTypeToCty(provider)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		providers[provider.Name] = providerCty
+	}
+
+	out := map[string]cty.Value{"path": gostringToCty(matrix.GeneratedPath())}
+	if len(providers) > 0 {
+		providersCty, err := convertValuesMapToCtyVal(providers)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		out["provider"] = providersCty
+	}
+	return convertValuesMapToCtyVal(out)
+}
+
+// providerVersionOverridesAsCty converts the provider version overrides to a cty Value, with the providers in a map by
+// name, as in the configuration.
+func providerVersionOverridesAsCty(overrides *ProviderVersionOverridesConfig) (cty.Value, error) {
+	if overrides == nil {
+		return cty.NilVal, nil
+	}
+
+	providers := map[string]cty.Value{}
+	for _, provider := range overrides.Providers {
+		providerCty, err := goTypeToCty(provider)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		providers[provider.Name] = providerCty
+	}
+
+	out := map[string]cty.Value{"path": gostringToCty(overrides.GeneratedPath())}
+	if len(providers) > 0 {
+		providersCty, err := convertValuesMapToCtyVal(providers)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		out["provider"] = providersCty
+	}
+	return convertValuesMapToCtyVal(out)
+}
+
 // Converts arbitrary go types that are json serializable to a cty Value by using json as an intermediary
 // representation. This avoids the strict type nature of cty, where you need to know the output type beforehand to
 // serialize to cty.
@@ -19,6 +19,9 @@ func TestTerragruntConfigAsCtyDrift(t *testing.T) {
 	testSource := "./foo"
 	testTrue := true
 	testFalse := false
+	testPriority := 10
+	testTerraformParallelism := 4
+	testLockTimeout := "5m"
 	mockOutputs := cty.Zero
 	mockOutputsAllowedTerraformCommands := []string{"init"}
 	dependentModulesPath := []*string{&testSource}
@@ -73,13 +76,61 @@ func TestTerragruntConfigAsCtyDrift(t *testing.T) {
 		Dependencies: &ModuleDependencies{
 			Paths: []string{"foo"},
 		},
-		DownloadDir:    ".terragrunt-cache",
-		PreventDestroy: &testTrue,
-		Skip:           true,
-		IamRole:        "terragruntRole",
+		DownloadDir:          ".terragrunt-cache",
+		Workspace:            "staging",
+		Workspaces:           []string{"blue", "green"},
+		RunAfter:             []string{"../dns"},
+		SuppressWarnings:     []string{"TG2001"},
+		Tags:                 []string{"networking", "prod"},
+		PreventDestroy:       &testTrue,
+		PreventDestroyPaths:  []string{"/live/prod/db"},
+		Skip:                 true,
+		Priority:             &testPriority,
+		TerraformParallelism: &testTerraformParallelism,
+		Owner:                "team-network",
+		ConcurrencyGroup:     "route53",
+		IamRole:              "terragruntRole",
 		Inputs: map[string]interface{}{
 			"aws_region": "us-east-1",
 		},
+		CommandAliases: map[string][]string{
+			"preview": {"plan", "-lock=false"},
+		},
+		ExecPolicy: &ExecPolicyConfig{
+			Allow: []string{"tflint"},
+		},
+		ReadOnly: &testTrue,
+		Exclude:  &ExcludeConfig{If: true, Reason: "The region is not enabled yet"},
+		Approval: &ApprovalConfig{
+			URL: "https://approvals.example.com",
+		},
+		ExportOutputs: []ExportOutputsConfig{
+			{Name: "ssm", Backend: "ssm", Key: "/prod/vpc/{output}", Outputs: []string{"vpc_id"}},
+		},
+		Execution: &ExecutionConfig{
+			Backend: ExecutionBackendSSH,
+			SSH:     &SSHExecutionConfig{Host: "bastion.example.com"},
+		},
+		Network: &NetworkConfig{
+			ModuleMirrors: map[string]string{"registry.terraform.io": "https://mirror.example.com/modules/v1/"},
+		},
+		StateLock: &StateLockConfig{
+			Timeout: &testLockTimeout,
+		},
+		SourceVerification: &SourceVerificationConfig{
+			Rules: []SourceVerificationRule{{Prefix: "git::https://github.com/acme/", Method: SourceVerificationMethodGit, Identities: []string{"security@acme.com"}}},
+		},
+		ProviderMatrix: &ProviderMatrixConfig{
+			Providers: []ProviderMatrixProvider{{Name: "aws", Source: "hashicorp/aws"}},
+		},
+		ProviderVersionOverrides: &ProviderVersionOverridesConfig{
+			Providers: []ProviderVersionOverride{{Name: "aws", Version: "~> 5.40"}},
+		},
+		ApprovedProviders: []string{"hashicorp/*"},
+		Profiles: []ProfileConfig{
+			{Name: "prod", AWS: &AWSProfileConfig{Profile: ptr("acme-prod")}},
+		},
+		UseProfile: "prod",
 		Locals: map[string]interface{}{
 			"quote": "the answer is 42",
 		},
@@ -144,6 +195,9 @@ func TestRemoteStateAsCtyDrift(t *testing.T) {
 		Config: map[string]interface{}{
 			"bar": "baz",
 		},
+		Encryption: map[string]interface{}{
+			"key_provider": "pbkdf2",
+		},
 	}
 
 	ctyVal, err := remoteStateAsCty(&testConfig)
@@ -200,8 +254,20 @@ func terragruntConfigStructFieldToMapKey(t *testing.T, fieldName string) (string
 		return "dependencies", true
 	case "DownloadDir":
 		return "download_dir", true
+	case "Workspace":
+		return "workspace", true
+	case "Workspaces":
+		return "workspaces", true
+	case "RunAfter":
+		return "run_after", true
+	case "SuppressWarnings":
+		return "suppress_warnings", true
+	case "Tags":
+		return "tags", true
 	case "PreventDestroy":
 		return "prevent_destroy", true
+	case "PreventDestroyPaths":
+		return "prevent_destroy_paths", true
 	case "Skip":
 		return "skip", true
 	case "IamRole":
@@ -230,6 +296,44 @@ func terragruntConfigStructFieldToMapKey(t *testing.T, fieldName string) (string
 		return "retry_max_attempts", true
 	case "RetrySleepIntervalSec":
 		return "retry_sleep_interval_sec", true
+	case "Owner":
+		return "owner", true
+	case "ConcurrencyGroup":
+		return "concurrency_group", true
+	case "Priority":
+		return "priority", true
+	case "TerraformParallelism":
+		return "terraform_parallelism", true
+	case "CommandAliases":
+		return "command_aliases", true
+	case "ExecPolicy":
+		return "exec_policy", true
+	case "ReadOnly":
+		return "read_only", true
+	case "Exclude":
+		return "exclude", true
+	case "Approval":
+		return "approval", true
+	case "ExportOutputs":
+		return "export_outputs", true
+	case "Execution":
+		return "execution", true
+	case "Network":
+		return "network", true
+	case "StateLock":
+		return "state_lock", true
+	case "SourceVerification":
+		return "source_verification", true
+	case "ProviderMatrix":
+		return "provider_matrix", true
+	case "ProviderVersionOverrides":
+		return "provider_version_overrides", true
+	case "ApprovedProviders":
+		return "approved_providers", true
+	case "Profiles":
+		return "profile", true
+	case "UseProfile":
+		return "use_profile", true
 	case "DependentModulesPath":
 		return "dependent_modules", true
 	default:
@@ -251,6 +355,8 @@ func remoteStateStructFieldToMapKey(t *testing.T, fieldName string) (string, boo
 		return "generate", true
 	case "Config":
 		return "config", true
+	case "Encryption":
+		return "encryption", true
 	default:
 		t.Fatalf("Unknown struct property: %s", fieldName)
 		// This should not execute
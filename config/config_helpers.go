@@ -58,6 +58,8 @@ const (
 	FuncNameGetTerraformCommandsThatNeedParallelism = "get_terraform_commands_that_need_parallelism"
 	FuncNameSopsDecryptFile                         = "sops_decrypt_file"
 	FuncNameGetTerragruntSourceCLIFlag              = "get_terragrunt_source_cli_flag"
+	FuncNameGetTerragruntSource                     = "get_terragrunt_source"
+	FuncNameGetRunID                                = "get_run_id"
 	FuncNameGetDefaultRetryableErrors               = "get_default_retryable_errors"
 	FuncNameReadTfvarsFile                          = "read_tfvars_file"
 	FuncNameGetWorkingDir                           = "get_working_dir"
@@ -159,6 +161,8 @@ func createTerragruntEvalContext(ctx *ParsingContext, configPath string) (*hcl.E
 		FuncNameGetTerraformCommandsThatNeedParallelism: wrapStaticValueToStringSliceAsFuncImpl(TERRAFORM_COMMANDS_NEED_PARALLELISM),
 		FuncNameSopsDecryptFile:                         wrapStringSliceToStringAsFuncImpl(ctx, sopsDecryptFile),
 		FuncNameGetTerragruntSourceCLIFlag:              wrapVoidToStringAsFuncImpl(ctx, getTerragruntSourceCliFlag),
+		FuncNameGetTerragruntSource:                     wrapVoidToStringAsFuncImpl(ctx, getTerragruntSource),
+		FuncNameGetRunID:                                wrapVoidToStringAsFuncImpl(ctx, getRunID),
 		FuncNameGetDefaultRetryableErrors:               wrapVoidToStringSliceAsFuncImpl(ctx, getDefaultRetryableErrors),
 		FuncNameReadTfvarsFile:                          wrapStringSliceToStringAsFuncImpl(ctx, readTFVarsFile),
 		FuncNameGetWorkingDir:                           wrapVoidToStringAsFuncImpl(ctx, getWorkingDir),
@@ -347,7 +351,7 @@ func runCommand(ctx *ParsingContext, args []string) (string, error) {
 		return cachedValue, nil
 	}
 
-	cmdOutput, err := shell.RunShellCommandWithOutput(ctx, ctx.TerragruntOptions, currentPath, suppressOutput, false, args[0], args[1:]...)
+	cmdOutput, err := shell.RunConfigCommandWithOutput(ctx, ctx.TerragruntOptions, currentPath, suppressOutput, args[0], args[1:]...)
 	if err != nil {
 		return "", errors.WithStackTrace(err)
 	}
@@ -575,8 +579,9 @@ func getAWSCallerIdentityUserID(ctx *ParsingContext) (string, error) {
 }
 
 // Parse the terragrunt config and return a representation that can be used as a reference. If given a default value,
-// this will return the default if the terragrunt config file does not exist.
-func readTerragruntConfig(ctx *ParsingContext, configPath string, defaultVal *cty.Value) (cty.Value, error) {
+// this will return the default if the terragrunt config file does not exist. If dataOnly is true, only the locals and
+// the inputs of the config are parsed.
+func readTerragruntConfig(ctx *ParsingContext, configPath string, defaultVal *cty.Value, dataOnly bool) (cty.Value, error) {
 	// target config check: make sure the target config exists. If the file does not exist, and there is no default val,
 	// return an error. If the file does not exist but there is a default val, return the default val. Otherwise,
 	// proceed to parse the file as a terragrunt config file.
@@ -588,6 +593,10 @@ func readTerragruntConfig(ctx *ParsingContext, configPath string, defaultVal *ct
 		return *defaultVal, nil
 	}
 
+	if dataOnly {
+		return readTerragruntConfigAsData(ctx, targetConfig)
+	}
+
 	// We update the ctx of terragruntOptions to the config being read in.
 	ctx = ctx.WithTerragruntOptions(ctx.TerragruntOptions.Clone(targetConfig))
 	config, err := ParseConfigFile(ctx.TerragruntOptions, ctx, targetConfig, nil)
@@ -615,14 +624,14 @@ func readTerragruntConfigAsFuncImpl(ctx *ParsingContext) function.Function {
 	return function.New(&function.Spec{
 		// Takes one required string param
 		Params: []function.Parameter{function.Parameter{Type: cty.String}},
-		// And optional param that takes anything
-		VarParam: &function.Parameter{Type: cty.DynamicPseudoType},
+		// And optional params that take anything: the default value and the options
+		VarParam: &function.Parameter{Type: cty.DynamicPseudoType, AllowNull: true, AllowDynamicType: true},
 		// We don't know the return type until we parse the terragrunt config, so we use a dynamic type
 		Type: function.StaticReturnType(cty.DynamicPseudoType),
 		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
 			numParams := len(args)
-			if numParams == 0 || numParams > 2 {
-				return cty.NilVal, errors.WithStackTrace(WrongNumberOfParamsError{Func: "read_terragrunt_config", Expected: "1 or 2", Actual: numParams})
+			if numParams == 0 || numParams > 3 {
+				return cty.NilVal, errors.WithStackTrace(WrongNumberOfParamsError{Func: "read_terragrunt_config", Expected: "1, 2 or 3", Actual: numParams})
 			}
 
 			strArgs, err := ctySliceToStringSlice(args[:1])
@@ -632,11 +641,26 @@ func readTerragruntConfigAsFuncImpl(ctx *ParsingContext) function.Function {
 
 			var defaultVal *cty.Value = nil
 			if numParams == matchedPats {
+				// The null default value is only allowed before the options, as the params did not allow null before
+				if args[1].IsNull() {
+					return cty.NilVal, function.NewArgErrorf(1, "argument must not be null")
+				}
 				defaultVal = &args[1]
 			}
 
+			// With the options, a null default value errors out if the config does not exist, as without a default value
+			dataOnly := false
+			if numParams > matchedPats {
+				if !args[1].IsNull() {
+					defaultVal = &args[1]
+				}
+				if dataOnly, err = parseReadTerragruntConfigOptions(args[2]); err != nil {
+					return cty.NilVal, err
+				}
+			}
+
 			targetConfigPath := strArgs[0]
-			return readTerragruntConfig(ctx, targetConfigPath, defaultVal)
+			return readTerragruntConfig(ctx, targetConfigPath, defaultVal, dataOnly)
 		},
 	})
 }
@@ -789,6 +813,32 @@ func getTerragruntSourceCliFlag(ctx *ParsingContext) (string, error) {
 	return ctx.TerragruntOptions.Source, nil
 }
 
+// getTerragruntSource returns the source the terraform code of the module is downloaded from: the source of the
+// terraform block, unless overridden by the --terragrunt-source or --terragrunt-source-map CLI flags.
+func getTerragruntSource(ctx *ParsingContext) (string, error) {
+	if ctx.TerragruntOptions.Source != "" {
+		return ctx.TerragruntOptions.Source, nil
+	}
+
+	// The source is read from a partial parse of the config, in which the function returns an empty string, as the
+	// source itself may call it.
+	ctx = ctx.WithDecodeList(TerraformSource)
+	ctx.PredefinedFunctions = map[string]function.Function{
+		FuncNameGetTerragruntSource: wrapVoidToEmptyStringAsFuncImpl(),
+	}
+
+	terragruntConfig, err := PartialParseConfigFile(ctx, ctx.TerragruntOptions.TerragruntConfigPath, nil)
+	if err != nil {
+		return "", err
+	}
+	return GetTerraformSourceUrl(ctx.TerragruntOptions, terragruntConfig)
+}
+
+// getRunID returns the ID of the run, shared by all the modules the run runs.
+func getRunID(ctx *ParsingContext) (string, error) {
+	return ctx.TerragruntOptions.RunID, nil
+}
+
 // Return the selected include block based on a label passed in as a function param. Note that the assumption is that:
 //   - If the Original attribute is set, we are in the parent ctx so return that.
 //   - If there are no include blocks, no param is required and nil is returned.
@@ -595,6 +595,49 @@ func TestResolveCliArgsInterpolationConfigString(t *testing.T) {
 	}
 }
 
+func TestGetTerragruntSourceAndRunID(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+terraform {
+  source = "git::git@github.com:acme/modules.git//app?ref=v1.2.0${get_terragrunt_source()}"
+}
+
+inputs = {
+  source = get_terragrunt_source()
+  run_id = get_run_id()
+}
+`), 0644))
+
+	testCases := []struct {
+		name           string
+		source         string
+		sourceMap      map[string]string
+		expectedSource string
+	}{
+		{"terraform block", "", nil, "git::git@github.com:acme/modules.git//app?ref=v1.2.0"},
+		{"source flag", "/local/modules//app", nil, "/local/modules//app"},
+		{"source map flag", "", map[string]string{"git::git@github.com:acme/modules.git": "/local/modules"}, "/local/modules//app"},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := terragruntOptionsForTest(t, configPath)
+			opts.Source = testCase.source
+			opts.SourceMap = testCase.sourceMap
+			opts.RunID = "20240102T030405Z-abc123"
+
+			cfg, err := ParseConfigFile(opts, NewParsingContext(context.Background(), opts), configPath, nil)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedSource, cfg.Inputs["source"])
+			assert.Equal(t, "20240102T030405Z-abc123", cfg.Inputs["run_id"])
+		})
+	}
+}
+
 func toStringSlice(t *testing.T, value interface{}) []string {
 	asInterfaceSlice, isInterfaceSlice := value.([]interface{})
 	require.True(t, isInterfaceSlice)
@@ -865,7 +908,7 @@ func TestReadTerragruntConfigInputs(t *testing.T) {
 	options := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
 
 	ctx := NewParsingContext(context.Background(), options)
-	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture-inputs/terragrunt.hcl", nil)
+	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture-inputs/terragrunt.hcl", nil, false)
 	require.NoError(t, err)
 
 	tgConfigMap, err := parseCtyValueToMap(tgConfigCty)
@@ -907,7 +950,7 @@ func TestReadTerragruntConfigRemoteState(t *testing.T) {
 
 	options := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
 	ctx := NewParsingContext(context.Background(), options)
-	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture/terragrunt.hcl", nil)
+	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture/terragrunt.hcl", nil, false)
 	require.NoError(t, err)
 
 	tgConfigMap, err := parseCtyValueToMap(tgConfigCty)
@@ -940,7 +983,7 @@ func TestReadTerragruntConfigHooks(t *testing.T) {
 
 	options := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
 	ctx := NewParsingContext(context.Background(), options)
-	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture-hooks/before-after-and-on-error/terragrunt.hcl", nil)
+	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture-hooks/before-after-and-on-error/terragrunt.hcl", nil, false)
 	require.NoError(t, err)
 
 	tgConfigMap, err := parseCtyValueToMap(tgConfigCty)
@@ -983,7 +1026,7 @@ func TestReadTerragruntConfigLocals(t *testing.T) {
 
 	options := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
 	ctx := NewParsingContext(context.Background(), options)
-	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture-locals/canonical/terragrunt.hcl", nil)
+	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture-locals/canonical/terragrunt.hcl", nil, false)
 	require.NoError(t, err)
 
 	tgConfigMap, err := parseCtyValueToMap(tgConfigCty)
@@ -1167,7 +1210,7 @@ func TestReadTFVarsFiles(t *testing.T) {
 
 	options := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
 	ctx := NewParsingContext(context.Background(), options)
-	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture-read-tf-vars/terragrunt.hcl", nil)
+	tgConfigCty, err := readTerragruntConfig(ctx, "../test/fixture-read-tf-vars/terragrunt.hcl", nil, false)
 	require.NoError(t, err)
 
 	tgConfigMap, err := parseCtyValueToMap(tgConfigCty)
@@ -25,6 +25,8 @@ const (
 	TerragruntInputs
 	TerragruntVersionConstraints
 	RemoteStateBlock
+	ExecPolicyBlock
+	ExportOutputsBlock
 )
 
 // terragruntIncludeMultiple is a struct that can be used to only decode the include block with labels.
@@ -58,12 +60,39 @@ type terraformConfigSourceOnly struct {
 	Remain hcl.Body `hcl:",remain"`
 }
 
-// terragruntFlags is a struct that can be used to only decode the flag attributes (skip and prevent_destroy)
+// terragruntFlags is a struct that can be used to only decode the flag attributes (skip, prevent_destroy, priority,
+// command_aliases and read_only)
 type terragruntFlags struct {
-	IamRole        *string  `hcl:"iam_role,attr"`
-	PreventDestroy *bool    `hcl:"prevent_destroy,attr"`
-	Skip           *bool    `hcl:"skip,attr"`
-	Remain         hcl.Body `hcl:",remain"`
+	IamRole             *string             `hcl:"iam_role,attr"`
+	PreventDestroy      *bool               `hcl:"prevent_destroy,attr"`
+	PreventDestroyPaths []string            `hcl:"prevent_destroy_paths,optional"`
+	Skip                *bool               `hcl:"skip,attr"`
+	Exclude             *ExcludeConfig      `hcl:"exclude,block"`
+	Priority            *int                `hcl:"priority,attr"`
+	Owner               *string             `hcl:"owner,attr"`
+	ConcurrencyGroup    *string             `hcl:"concurrency_group,attr"`
+	CommandAliases      map[string][]string `hcl:"command_aliases,optional"`
+	ReadOnly            *bool               `hcl:"read_only,attr"`
+	Workspace           *string             `hcl:"workspace,attr"`
+	Workspaces          []string            `hcl:"workspaces,optional"`
+	RunAfter            []string            `hcl:"run_after,optional"`
+	SuppressWarnings    []string            `hcl:"suppress_warnings,optional"`
+	Tags                []string            `hcl:"tags,optional"`
+	Profiles            []ProfileConfig     `hcl:"profile,block"`
+	UseProfile          *string             `hcl:"use_profile,attr"`
+	Remain              hcl.Body            `hcl:",remain"`
+}
+
+// terragruntExecPolicy is a struct that can be used to only decode the exec_policy block.
+type terragruntExecPolicy struct {
+	ExecPolicy *ExecPolicyConfig `hcl:"exec_policy,block"`
+	Remain     hcl.Body          `hcl:",remain"`
+}
+
+// terragruntExportOutputs is a struct that can be used to only decode the export_outputs blocks.
+type terragruntExportOutputs struct {
+	ExportOutputs []ExportOutputsConfig `hcl:"export_outputs,block"`
+	Remain        hcl.Body              `hcl:",remain"`
 }
 
 // terragruntVersionConstraints is a struct that can be used to only decode the attributes related to constraining the
@@ -151,7 +180,9 @@ func TerragruntConfigFromPartialConfig(ctx *ParsingContext, file *hclparse.File,
 	var cacheKey = fmt.Sprintf("%#v-%#v-%#v-%#v", file.ConfigPath, file.Content(), includeFromChild, ctx.PartialParseDecodeList)
 
 	if ctx.TerragruntOptions.UsePartialParseConfigCache {
-		if config, found := terragruntConfigCache.Get(cacheKey); found {
+		config, found := terragruntConfigCache.Get(cacheKey)
+		util.CountCacheLookup(util.ParsedConfigCache, found)
+		if found {
 			ctx.TerragruntOptions.Logger.Debugf("Cache hit for '%s' (partial parsing), decodeList: '%v'.", file.ConfigPath, ctx.PartialParseDecodeList)
 			return &config, nil
 		}
@@ -178,10 +209,13 @@ func TerragruntConfigFromPartialConfig(ctx *ParsingContext, file *hclparse.File,
 //   - DependenciesBlock: Parses the `dependencies` block in the config
 //   - DependencyBlock: Parses the `dependency` block in the config
 //   - TerraformBlock: Parses the `terraform` block in the config
-//   - TerragruntFlags: Parses the boolean flags `prevent_destroy` and `skip` in the config
+//   - TerragruntFlags: Parses the flags `prevent_destroy`, `skip`, `priority`, `command_aliases`, `read_only`,
+//     `workspace`, `workspaces`, `profile` and `use_profile` in the config
 //   - TerragruntVersionConstraints: Parses the attributes related to constraining terragrunt and terraform versions in
 //     the config.
 //   - RemoteStateBlock: Parses the `remote_state` block in the config
+//   - ExecPolicyBlock: Parses the `exec_policy` block in the config
+//   - ExportOutputsBlock: Parses the `export_outputs` blocks in the config
 //
 // Note that the following blocks are always decoded:
 // - locals
@@ -283,25 +317,77 @@ func PartialParseConfig(ctx *ParsingContext, file *hclparse.File, includeFromChi
 			if decoded.PreventDestroy != nil {
 				output.PreventDestroy = decoded.PreventDestroy
 			}
+			if decoded.PreventDestroyPaths != nil {
+				if output.PreventDestroyPaths, err = canonicalPreventDestroyPaths(file.ConfigPath, decoded.PreventDestroyPaths); err != nil {
+					return nil, err
+				}
+			}
 			if decoded.Skip != nil {
 				output.Skip = *decoded.Skip
 			}
+			if decoded.Exclude != nil {
+				output.Exclude = decoded.Exclude
+			}
+			if decoded.Priority != nil {
+				output.Priority = decoded.Priority
+			}
+			if decoded.Owner != nil {
+				output.Owner = *decoded.Owner
+			}
+			if decoded.ConcurrencyGroup != nil {
+				output.ConcurrencyGroup = *decoded.ConcurrencyGroup
+			}
+			if decoded.CommandAliases != nil {
+				output.CommandAliases = decoded.CommandAliases
+			}
+			if decoded.ReadOnly != nil {
+				output.ReadOnly = decoded.ReadOnly
+			}
+			if decoded.Workspace != nil {
+				output.Workspace = *decoded.Workspace
+			}
+			if decoded.Workspaces != nil {
+				output.Workspaces = decoded.Workspaces
+			}
+			if decoded.RunAfter != nil {
+				output.RunAfter = decoded.RunAfter
+			}
+			if decoded.SuppressWarnings != nil {
+				output.SuppressWarnings = decoded.SuppressWarnings
+			}
+			if decoded.Tags != nil {
+				output.Tags = decoded.Tags
+			}
 			if decoded.IamRole != nil {
 				output.IamRole = *decoded.IamRole
 			}
+			if decoded.Profiles != nil {
+				if err := validateProfiles(decoded.Profiles); err != nil {
+					return nil, err
+				}
+				output.Profiles = decoded.Profiles
+			}
+			if decoded.UseProfile != nil {
+				output.UseProfile = *decoded.UseProfile
+			}
 
 		case TerragruntInputs:
 			decoded := terragruntInputs{}
 
-			if _, ok := evalParsingContext.Variables[MetadataDependency]; !ok {
+			// The configs read as data don't retrieve the outputs of their dependencies
+			if _, ok := evalParsingContext.Variables[MetadataDependency]; !ok && !ctx.DataOnly {
 				// Decode just the `dependency` blocks, retrieving the outputs from the target terragrunt config in the process.
-				retrievedOutputs, err := decodeAndRetrieveOutputs(ctx, file)
+				retrievedOutputs, dependencyBlocks, err := decodeAndRetrieveOutputs(ctx, file)
 				if err != nil {
 					return nil, err
 				}
 				evalParsingContext.Variables[MetadataDependency] = *retrievedOutputs
+				ctx.DecodedDependencyBlocks = dependencyBlocks
 			}
 
+			file.WithOptions(hclparse.WithDiagnosticsUpdate(func(diags hcl.Diagnostics) hcl.Diagnostics {
+				return describeMissingDependencyOutputs(ctx, diags)
+			}))
 			if err := file.Decode(&decoded, evalParsingContext); err != nil {
 				diagErr, ok := errors.Unwrap(err).(hcl.Diagnostics)
 
@@ -339,6 +425,9 @@ func PartialParseConfig(ctx *ParsingContext, file *hclparse.File, includeFromChi
 				output.TerraformVersionConstraint = *decoded.TerraformVersionConstraint
 			}
 			if decoded.TerraformBinary != nil {
+				if ctx.TerragruntOptions.Restricted {
+					return nil, errors.WithStackTrace(RestrictedConfigNotAllowed{Setting: MetadataTerraformBinary, ConfigPath: file.ConfigPath})
+				}
 				output.TerraformBinary = *decoded.TerraformBinary
 			}
 
@@ -356,6 +445,22 @@ func PartialParseConfig(ctx *ParsingContext, file *hclparse.File, includeFromChi
 				output.RemoteState = remoteState
 			}
 
+		case ExecPolicyBlock:
+			decoded := terragruntExecPolicy{}
+			err := file.Decode(&decoded, evalParsingContext)
+			if err != nil {
+				return nil, err
+			}
+			output.ExecPolicy = decoded.ExecPolicy
+
+		case ExportOutputsBlock:
+			decoded := terragruntExportOutputs{}
+			err := file.Decode(&decoded, evalParsingContext)
+			if err != nil {
+				return nil, err
+			}
+			output.ExportOutputs = decoded.ExportOutputs
+
 		default:
 			return nil, InvalidPartialBlockName{decode}
 		}
@@ -369,6 +474,10 @@ func PartialParseConfig(ctx *ParsingContext, file *hclparse.File, includeFromChi
 		}
 		// Saving processed includes into configuration, direct assignment since nested includes aren't supported
 		config.ProcessedIncludes = ctx.TrackInclude.CurrentMap
+		// As in the full parse, the locals of a config read as data are not merged in, so that they remain local in scope
+		if ctx.DataOnly {
+			config.Locals = output.Locals
+		}
 		return config, nil
 	}
 	return output, nil
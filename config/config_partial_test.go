@@ -355,3 +355,42 @@ dependency "ec2" {
 	require.NoError(t, err)
 	assert.Len(t, terragruntConfig.Dependencies.Paths, 1)
 }
+
+func TestPartialParseWorkspace(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  env = "staging"
+}
+
+workspace = local.env
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t)).WithDecodeList(TerragruntFlags)
+	terragruntConfig, err := PartialParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "staging", terragruntConfig.Workspace)
+}
+
+func TestPartialParseExclude(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  region          = "ap-south-2"
+  enabled_regions = ["us-east-1", "eu-west-1"]
+}
+
+exclude {
+  if     = !contains(local.enabled_regions, local.region)
+  reason = "The region ${local.region} is not enabled yet"
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t)).WithDecodeList(TerragruntFlags)
+	terragruntConfig, err := PartialParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &ExcludeConfig{If: true, Reason: "The region ap-south-2 is not enabled yet"}, terragruntConfig.Exclude)
+	assert.True(t, terragruntConfig.Exclude.IsExcluded())
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/codegen"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/stretchr/testify/assert"
@@ -62,6 +64,97 @@ remote_state = {
 	}
 }
 
+func TestParseTerragruntConfigRemoteStateEncryption(t *testing.T) {
+	t.Parallel()
+
+	config := `
+remote_state {
+  backend = "s3"
+  config  = {}
+
+  encryption = {
+    key_provider = "pbkdf2"
+    passphrase   = "correct-horse-battery-staple"
+  }
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+
+	if assert.NotNil(t, terragruntConfig.RemoteState) {
+		assert.Equal(t, map[string]interface{}{"key_provider": "pbkdf2", "passphrase": "correct-horse-battery-staple"}, terragruntConfig.RemoteState.Encryption)
+	}
+
+	_, err = ParseConfigString(ctx, DefaultTerragruntConfigPath, strings.Replace(config, "correct-horse-battery-staple", "hunter2", 1), nil)
+	require.Error(t, err)
+}
+
+func TestParseTerragruntConfigGenerateSource(t *testing.T) {
+	t.Parallel()
+
+	config := `
+generate "provider" {
+  path      = "provider.tf"
+  if_exists = "overwrite"
+  source    = "git::https://github.com/acme/templates.git//provider.tf?ref=v1.0.0"
+  checksum  = "sha256:0123456789abcdef"
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+
+	if assert.Contains(t, terragruntConfig.GenerateConfigs, "provider") {
+		genConfig := terragruntConfig.GenerateConfigs["provider"]
+		assert.Equal(t, "git::https://github.com/acme/templates.git//provider.tf?ref=v1.0.0", genConfig.Source)
+		assert.Equal(t, "sha256:0123456789abcdef", genConfig.Checksum)
+		assert.Empty(t, genConfig.Contents)
+	}
+
+	_, err = ParseConfigString(ctx, DefaultTerragruntConfigPath, strings.Replace(config, "if_exists", "contents = \"terraform {}\"\n  if_exists", 1), nil)
+	var contentsAndSource GenerateContentsAndSourceError
+	require.ErrorAs(t, errors.Unwrap(err), &contentsAndSource)
+
+	_, err = ParseConfigString(ctx, DefaultTerragruntConfigPath, strings.Replace(config, "source", "contents", 1), nil)
+	var checksumWithoutSource GenerateChecksumWithoutSourceError
+	require.ErrorAs(t, errors.Unwrap(err), &checksumWithoutSource)
+}
+
+func TestParseTerragruntConfigGenerateEnabled(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  env = "dev"
+}
+
+generate "provider" {
+  path      = "provider.tf"
+  if_exists = "skip_if_identical"
+  enabled   = local.env == "prod"
+  contents  = "provider \"aws\" {}"
+}
+
+generate "versions" {
+  path      = "versions.tf"
+  if_exists = "overwrite"
+  enabled   = local.env != "prod"
+  contents  = "terraform {}"
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+
+	assert.True(t, terragruntConfig.GenerateConfigs["provider"].Disable)
+	assert.Equal(t, codegen.ExistsSkipIfIdentical, terragruntConfig.GenerateConfigs["provider"].IfExists)
+	assert.False(t, terragruntConfig.GenerateConfigs["versions"].Disable)
+}
+
 func TestParseTerragruntJsonConfigRemoteStateMinimalConfig(t *testing.T) {
 	t.Parallel()
 
@@ -1162,6 +1255,79 @@ prevent_destroy = false
 	assert.Equal(t, false, *terragruntConfig.PreventDestroy)
 }
 
+func TestParseTerragruntConfigTags(t *testing.T) {
+	t.Parallel()
+
+	config := `
+tags = ["networking", "prod"]
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"networking", "prod"}, terragruntConfig.Tags)
+
+	terragruntConfig, err = PartialParseConfigString(ctx.WithDecodeList(TerragruntFlags), DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"networking", "prod"}, terragruntConfig.Tags)
+}
+
+func TestParseTerragruntConfigPreventDestroyPaths(t *testing.T) {
+	t.Parallel()
+
+	rootDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	for _, dir := range []string{"prod/db", "prod/cache", "prod/app"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, dir), os.ModePerm))
+	}
+
+	config := `
+prevent_destroy_paths = ["prod/db", "prod/c*", "prod/missing"]
+`
+	configPath := filepath.Join(rootDir, "root.hcl")
+	expected := []string{filepath.Join(rootDir, "prod", "db"), filepath.Join(rootDir, "prod", "cache")}
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTestWithConfigPath(t, configPath))
+	terragruntConfig, err := ParseConfigString(ctx, configPath, config, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expected, terragruntConfig.PreventDestroyPaths)
+
+	terragruntConfig, err = PartialParseConfigString(ctx.WithDecodeList(TerragruntFlags), configPath, config, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, expected, terragruntConfig.PreventDestroyPaths)
+}
+
+func TestParseTerragruntConfigSuppressWarnings(t *testing.T) {
+	t.Parallel()
+
+	config := `
+suppress_warnings = ["TG2001", "TG2003"]
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{options.WarningAutoInitDisabled, options.WarningStateBucketNotVersioned}, terragruntConfig.SuppressWarnings)
+
+	_, err = ParseConfigString(ctx, DefaultTerragruntConfigPath, `suppress_warnings = ["TG9999"]`, nil)
+	var unknown options.UnknownWarningCode
+	require.ErrorAs(t, err, &unknown)
+	assert.Equal(t, options.UnknownWarningCode("TG9999"), unknown)
+}
+
+func TestParseTerragruntConfigTerraformParallelism(t *testing.T) {
+	t.Parallel()
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, `terraform_parallelism = 4`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 4, *terragruntConfig.TerraformParallelism)
+
+	_, err = ParseConfigString(ctx, DefaultTerragruntConfigPath, `terraform_parallelism = 0`, nil)
+	var invalid InvalidTerraformParallelism
+	require.ErrorAs(t, err, &invalid)
+}
+
 func TestParseTerragruntConfigSkipTrue(t *testing.T) {
 	t.Parallel()
 
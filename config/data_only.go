@@ -0,0 +1,79 @@
+package config
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// The options of read_terragrunt_config, passed in its third param, e.g. `{ data_only = true }`.
+const readTerragruntConfigOptionDataOnly = "data_only"
+
+// dataOnlyUnavailableFunctions are the functions which are not run when a config is read with data_only, as they have
+// side effects, call out to external services or parse the whole config of the module.
+var dataOnlyUnavailableFunctions = []string{
+	FuncNameRunCmd,
+	FuncNameSopsDecryptFile,
+	FuncNameGetAWSAccountID,
+	FuncNameGetAWSCallerIdentityArn,
+	FuncNameGetAWSCallerIdentityUserID,
+	FuncNameGetWorkingDir,
+}
+
+// readTerragruntConfigAsData parses only the locals and the inputs of the terragrunt config at the given path, and of
+// the configs it includes, without retrieving the outputs of its dependencies or running the functions with side
+// effects. This is the fast path to read the configs holding data, e.g. the common variables of an environment.
+func readTerragruntConfigAsData(ctx *ParsingContext, targetConfig string) (cty.Value, error) {
+	ctx = ctx.WithTerragruntOptions(ctx.TerragruntOptions.Clone(targetConfig)).WithDecodeList(TerragruntInputs)
+	ctx.DataOnly = true
+
+	functions := map[string]function.Function{}
+	for name, fn := range ctx.PredefinedFunctions {
+		functions[name] = fn
+	}
+	for _, name := range dataOnlyUnavailableFunctions {
+		functions[name] = dataOnlyUnavailableFuncImpl(name)
+	}
+	ctx.PredefinedFunctions = functions
+
+	config, err := PartialParseConfigFile(ctx, targetConfig, nil)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return TerragruntConfigAsCty(config)
+}
+
+// dataOnlyUnavailableFuncImpl returns a cty Function that fails, in place of the function with the given name which is
+// not run when a config is read with data_only.
+func dataOnlyUnavailableFuncImpl(name string) function.Function {
+	return function.New(&function.Spec{
+		VarParam: &function.Parameter{Type: cty.DynamicPseudoType, AllowNull: true, AllowUnknown: true},
+		Type:     function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.NilVal, errors.WithStackTrace(DataOnlyUnavailableFunctionError(name))
+		},
+	})
+}
+
+// parseReadTerragruntConfigOptions returns whether the given options of read_terragrunt_config set data_only.
+func parseReadTerragruntConfigOptions(opts cty.Value) (bool, error) {
+	if opts.IsNull() {
+		return false, nil
+	}
+	if !opts.IsKnown() || !(opts.Type().IsObjectType() || opts.Type().IsMapType()) {
+		return false, errors.WithStackTrace(InvalidParameterTypeError{Expected: "object", Actual: opts.Type().FriendlyName()})
+	}
+
+	dataOnly := false
+	for name, value := range opts.AsValueMap() {
+		if name != readTerragruntConfigOptionDataOnly {
+			return false, errors.WithStackTrace(InvalidReadTerragruntConfigOptionError(name))
+		}
+		if !value.IsKnown() || value.IsNull() || value.Type() != cty.Bool {
+			return false, errors.WithStackTrace(InvalidParameterTypeError{Expected: "bool", Actual: value.Type().FriendlyName()})
+		}
+		dataOnly = value.True()
+	}
+	return dataOnly, nil
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestReadTerragruntConfigDataOnly(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "common.hcl"), []byte(`
+locals {
+  region = "us-east-1"
+}
+
+inputs = {
+  region = local.region
+  tags   = { team = "platform" }
+}
+`), 0644))
+
+	// The dependency is not applied and the other blocks run commands, which would fail a full parse
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "env.hcl"), []byte(`
+include "common" {
+  path = "common.hcl"
+}
+
+locals {
+  env = "dev"
+}
+
+dependency "vpc" {
+  config_path = "./does-not-exist"
+}
+
+terraform {
+  source = run_cmd("false")
+}
+
+inputs = {
+  env = local.env
+}
+`), 0644))
+
+	parseChild := func(t *testing.T, config string) (*TerragruntConfig, error) {
+		t.Helper()
+
+		childPath := filepath.Join(t.TempDir(), DefaultTerragruntConfigPath)
+		require.NoError(t, os.WriteFile(childPath, []byte(config), 0644))
+		opts, err := options.NewTerragruntOptionsForTest(childPath)
+		require.NoError(t, err)
+		return ParseConfigFile(opts, NewParsingContext(context.Background(), opts), childPath, nil)
+	}
+
+	cfg, err := parseChild(t, `
+locals {
+  env = read_terragrunt_config("`+filepath.Join(dir, "env.hcl")+`", null, { data_only = true })
+}
+
+inputs = {
+  env    = local.env.inputs.env
+  region = local.env.inputs.region
+  local  = local.env.locals.env
+}
+`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"env": "dev", "region": "us-east-1", "local": "dev"}, cfg.Inputs)
+
+	// The default value is returned if the config does not exist, as without the options
+	cfg, err = parseChild(t, `
+inputs = read_terragrunt_config("`+filepath.Join(dir, "missing.hcl")+`", { inputs = { env = "none" } }, { data_only = true }).inputs
+`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"env": "none"}, cfg.Inputs)
+
+	_, err = parseChild(t, `
+inputs = read_terragrunt_config("`+filepath.Join(dir, "missing.hcl")+`", null, { data_only = true }).inputs
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Terragrunt config "+filepath.Join(dir, "missing.hcl")+" not found")
+
+	// Without the options, the default value cannot be null
+	_, err = parseChild(t, `
+inputs = read_terragrunt_config("`+filepath.Join(dir, "missing.hcl")+`", null).inputs
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "argument must not be null")
+
+	_, err = parseChild(t, `
+inputs = read_terragrunt_config("`+filepath.Join(dir, "env.hcl")+`", null, { partial = true }).inputs
+`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), InvalidReadTerragruntConfigOptionError("partial").Error())
+}
+
+func TestReadTerragruntConfigDataOnlyUnavailableFunction(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "common.hcl"), []byte(`
+locals {
+  account = run_cmd("echo", "123456789012")
+}
+`), 0644))
+
+	childPath := filepath.Join(dir, "child", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(childPath), 0755))
+	require.NoError(t, os.WriteFile(childPath, []byte(`
+locals {
+  common = read_terragrunt_config("../common.hcl", null, { data_only = true })
+}
+`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(childPath)
+	require.NoError(t, err)
+	_, err = ParseConfigFile(opts, NewParsingContext(context.Background(), opts), childPath, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), DataOnlyUnavailableFunctionError(FuncNameRunCmd).Error())
+}
+
+func TestReadTerragruntConfigDataOnlyDependencyInputs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "env.hcl"), []byte(`
+dependency "vpc" {
+  config_path = "./vpc"
+}
+
+inputs = {
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`), 0644))
+
+	childPath := filepath.Join(dir, "child", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(childPath), 0755))
+	require.NoError(t, os.WriteFile(childPath, []byte(`
+locals {
+  env = read_terragrunt_config("../env.hcl", null, { data_only = true })
+}
+`), 0644))
+
+	// The outputs of the dependencies are not retrieved, so the inputs referencing them fail
+	opts, err := options.NewTerragruntOptionsForTest(childPath)
+	require.NoError(t, err)
+	_, err = ParseConfigFile(opts, NewParsingContext(context.Background(), opts), childPath, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `There is no variable named "dependency"`)
+}
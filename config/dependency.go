@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -36,11 +37,14 @@ const renderJsonCommand = "render-json"
 type Dependency struct {
 	Name                                string     `hcl:",label" cty:"name"`
 	Enabled                             *bool      `hcl:"enabled,attr" cty:"enabled"`
-	ConfigPath                          string     `hcl:"config_path,attr" cty:"config_path"`
+	ConfigPath                          string     `hcl:"config_path,optional" cty:"config_path"`
 	SkipOutputs                         *bool      `hcl:"skip_outputs,attr" cty:"skip"`
 	MockOutputs                         *cty.Value `hcl:"mock_outputs,attr" cty:"mock_outputs"`
 	MockOutputsAllowedTerraformCommands *[]string  `hcl:"mock_outputs_allowed_terraform_commands,attr" cty:"mock_outputs_allowed_terraform_commands"`
 
+	// Read the outputs from the parameter store the dependency publishes them to, instead of from its state
+	PublishedOutputs *PublishedOutputsConfig `hcl:"published_outputs,block" cty:"published_outputs"`
+
 	// MockOutputsMergeWithState is deprecated. Use MockOutputsMergeStrategyWithState
 	MockOutputsMergeWithState         *bool              `hcl:"mock_outputs_merge_with_state,attr" cty:"mock_outputs_merge_with_state"`
 	MockOutputsMergeStrategyWithState *MergeStrategyType `hcl:"mock_outputs_merge_strategy_with_state" cty:"mock_outputs_merge_strategy_with_state"`
@@ -71,6 +75,10 @@ func (targetDepConfig *Dependency) DeepMerge(sourceDepConfig Dependency) error {
 		targetDepConfig.SkipOutputs = sourceDepConfig.SkipOutputs
 	}
 
+	if sourceDepConfig.PublishedOutputs != nil {
+		targetDepConfig.PublishedOutputs = sourceDepConfig.PublishedOutputs
+	}
+
 	if sourceDepConfig.MockOutputs != nil {
 		if targetDepConfig.MockOutputs == nil {
 			targetDepConfig.MockOutputs = sourceDepConfig.MockOutputs
@@ -167,26 +175,32 @@ var outputLocks = sync.Map{}
 // NOTE FOR MAINTAINER: When implementing importation of other config blocks (e.g referencing inputs), carefully
 //
 //	consider whether or not the implementation of the cyclic dependency detection still makes sense.
-func decodeAndRetrieveOutputs(ctx *ParsingContext, file *hclparse.File) (*cty.Value, error) {
+func decodeAndRetrieveOutputs(ctx *ParsingContext, file *hclparse.File) (*cty.Value, []Dependency, error) {
 	evalParsingContext, err := createTerragruntEvalContext(ctx, file.ConfigPath)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	decodedDependency := terragruntDependency{}
 	if err := file.Decode(&decodedDependency, evalParsingContext); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// The dependencies in other repos are read from the repos fetched locally
+	decodedDependency.Dependencies, err = resolveRemoteDependencies(ctx, decodedDependency.Dependencies)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if err := checkForDependencyBlockCycles(ctx, file.ConfigPath, decodedDependency); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Mark skipped dependencies as disabled
 	updatedDependencies := terragruntDependency{}
 	for _, dep := range decodedDependency.Dependencies {
 		depPath := getCleanedTargetConfigPath(dep.ConfigPath, ctx.TerragruntOptions.TerragruntConfigPath)
-		if dep.isEnabled() && util.FileExists(depPath) {
+		if dep.isEnabled() && dep.ConfigPath != "" && util.FileExists(depPath) {
 			depOpts := cloneTerragruntOptionsForDependency(ctx, depPath)
 			depCtx := ctx.WithDecodeList(TerragruntFlags, TerragruntInputs).WithTerragruntOptions(depOpts)
 
@@ -198,7 +212,7 @@ func decodeAndRetrieveOutputs(ctx *ParsingContext, file *hclparse.File) (*cty.Va
 
 				inputsCty, err := convertToCtyWithJson(depConfig.Inputs)
 				if err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				dep.Inputs = &inputsCty
 
@@ -215,12 +229,26 @@ func decodeAndRetrieveOutputs(ctx *ParsingContext, file *hclparse.File) (*cty.Va
 	if ctx.TrackInclude != nil {
 		mergedDecodedDependency, err := handleIncludeForDependency(ctx, decodedDependency)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		decodedDependency = *mergedDecodedDependency
+
+		// The included dependency blocks may be in other repos too
+		decodedDependency.Dependencies, err = resolveRemoteDependencies(ctx, decodedDependency.Dependencies)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := validateDependencyBlocks(decodedDependency.Dependencies); err != nil {
+		return nil, nil, err
 	}
 
-	return dependencyBlocksToCtyValue(ctx, decodedDependency.Dependencies)
+	retrievedOutputs, err := dependencyBlocksToCtyValue(ctx, decodedDependency.Dependencies)
+	if err != nil {
+		return nil, nil, err
+	}
+	return retrievedOutputs, decodedDependency.Dependencies, nil
 }
 
 // Convert the list of parsed Dependency blocks into a list of module dependencies. Each output block should
@@ -232,8 +260,9 @@ func dependencyBlocksToModuleDependencies(decodedDependencyBlocks []Dependency)
 
 	paths := []string{}
 	for _, decodedDependencyBlock := range decodedDependencyBlocks {
-		// skip dependency if is not enabled
-		if !decodedDependencyBlock.isEnabled() {
+		// skip dependency if is not enabled, if it is in another repo, as it is never run, or if it only reads the
+		// published outputs
+		if !decodedDependencyBlock.isEnabled() || IsRemoteDependencyPath(decodedDependencyBlock.ConfigPath) || decodedDependencyBlock.ConfigPath == "" {
 			continue
 		}
 		paths = append(paths, decodedDependencyBlock.ConfigPath)
@@ -248,7 +277,7 @@ func checkForDependencyBlockCycles(ctx *ParsingContext, configPath string, decod
 	visitedPaths := []string{}
 	currentTraversalPaths := []string{configPath}
 	for _, dependency := range decodedDependency.Dependencies {
-		if dependency.isDisabled() {
+		if dependency.isDisabled() || dependency.ConfigPath == "" {
 			continue
 		}
 		dependencyPath := getCleanedTargetConfigPath(dependency.ConfigPath, configPath)
@@ -393,7 +422,11 @@ func getTerragruntOutputIfAppliedElseConfiguredDefault(ctx *ParsingContext, depe
 		return dependencyConfig.MockOutputs, nil
 	}
 	if dependencyConfig.shouldGetOutputs() {
-		outputVal, isEmpty, err := getTerragruntOutput(ctx, dependencyConfig)
+		getOutput := getTerragruntOutput
+		if dependencyConfig.PublishedOutputs != nil {
+			getOutput = getPublishedOutputs
+		}
+		outputVal, isEmpty, err := getOutput(ctx, dependencyConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -420,6 +453,9 @@ func getTerragruntOutputIfAppliedElseConfiguredDefault(ctx *ParsingContext, depe
 	// applied. In either case, check if there are default output values to return. If yes, return that. Else,
 	// return error.
 	targetConfig := getCleanedTargetConfigPath(dependencyConfig.ConfigPath, ctx.TerragruntOptions.TerragruntConfigPath)
+	if dependencyConfig.PublishedOutputs != nil {
+		targetConfig = dependencyConfig.PublishedOutputs.Key
+	}
 	currentConfig := ctx.TerragruntOptions.TerragruntConfigPath
 	if dependencyConfig.shouldReturnMockOutputs(ctx) {
 		ctx.TerragruntOptions.Logger.Debugf("WARNING: config %s is a dependency of %s that has no outputs, but mock outputs provided and returning those in dependency output.",
@@ -432,6 +468,9 @@ func getTerragruntOutputIfAppliedElseConfiguredDefault(ctx *ParsingContext, depe
 	// At this point, we expect outputs to exist because there is a `dependency` block without skip_outputs = true, and
 	// returning mocks is not allowed. So return a useful error message indicating that we expected outputs, but they
 	// did not exist.
+	if dependencyConfig.PublishedOutputs != nil {
+		return nil, errors.WithStackTrace(PublishedOutputsNotFound{Dependency: dependencyConfig.Name, Key: targetConfig, CurrentConfig: currentConfig})
+	}
 	err := TerragruntOutputTargetNoOutputs{
 		targetConfig:  targetConfig,
 		currentConfig: currentConfig,
@@ -509,6 +548,7 @@ func getOutputJsonWithCaching(ctx *ParsingContext, targetConfig string) ([]byte,
 
 	// Look up if we have already run terragrunt output for this target config
 	rawJsonBytes, hasRun := jsonOutputCache.Load(targetConfig)
+	util.CountCacheLookup(util.DependencyOutputCache, hasRun)
 	if hasRun {
 		// Cache hit, so return cached output
 		ctx.TerragruntOptions.Logger.Debugf("%s was run before. Using cached output.", targetConfig)
@@ -622,6 +662,8 @@ func cloneTerragruntOptionsForDependencyOutput(ctx *ParsingContext, targetConfig
 // by directly pulling down the state file. Otherwise, terragrunt will fallback to running `terragrunt output` on the
 // target module.
 func getTerragruntOutputJson(ctx *ParsingContext, targetConfig string) ([]byte, error) {
+	mutating := terraform.IsMutatingCommand(ctx.TerragruntOptions.TerraformCliArgs)
+
 	// Make a copy of the terragruntOptions so that we can reuse the same execution environment, but in the ctx of
 	// the target config.
 	targetTGOptions, err := cloneTerragruntOptionsForDependencyOutput(ctx, targetConfig)
@@ -634,22 +676,32 @@ func getTerragruntOutputJson(ctx *ParsingContext, targetConfig string) ([]byte,
 	// proceed to routine that fetches remote state directly. Otherwise, fallback to calling `terragrunt output`
 	// directly.
 	remoteStateTGConfig, err := PartialParseConfigFile(ctx.WithDecodeList(RemoteStateBlock, TerragruntFlags), targetConfig, nil)
+	if err == nil && remoteStateTGConfig.RemoteState != nil {
+		// All the ways of getting the outputs read the state from its backend
+		remoteState := remoteStateTGConfig.RemoteState
+		what := fmt.Sprintf("the outputs of the dependency %s from the %s backend", targetConfig, remoteState.Backend)
+		if err := ctx.TerragruntOptions.CheckOfflineFetch(what, remoteState.EndpointHost()); err != nil {
+			return nil, err
+		}
+	}
 	if err != nil || !canGetRemoteState(remoteStateTGConfig.RemoteState) {
 		ctx.TerragruntOptions.Logger.Debugf("Could not parse remote_state block from target config %s", targetConfig)
 		ctx.TerragruntOptions.Logger.Debugf("Falling back to terragrunt output.")
 		return runTerragruntOutputJson(ctx, targetConfig)
 	}
 
-	// In optimization mode, see if there is already an init-ed folder that terragrunt can use, and if so, run
-	// `terraform output` in the working directory.
-	isInit, workingDir, err := terragruntAlreadyInit(targetTGOptions, targetConfig, ctx)
-	if err != nil {
-		return nil, err
-	}
-	if isInit {
-		return getTerragruntOutputJsonFromInitFolder(ctx, workingDir, remoteStateTGConfig.GetIAMRoleOptions())
-	}
-	return getTerragruntOutputJsonFromRemoteState(ctx, targetConfig, remoteStateTGConfig.RemoteState, remoteStateTGConfig.GetIAMRoleOptions())
+	return getOutputJsonWithRemoteCache(ctx, mutating, remoteStateTGConfig.RemoteState, remoteStateTGConfig.Workspace, func() ([]byte, error) {
+		// In optimization mode, see if there is already an init-ed folder that terragrunt can use, and if so, run
+		// `terraform output` in the working directory.
+		isInit, workingDir, err := terragruntAlreadyInit(targetTGOptions, targetConfig, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if isInit {
+			return getTerragruntOutputJsonFromInitFolder(ctx, workingDir, remoteStateTGConfig.GetIAMRoleOptions(), remoteStateTGConfig.Workspace)
+		}
+		return getTerragruntOutputJsonFromRemoteState(ctx, targetConfig, remoteStateTGConfig.RemoteState, remoteStateTGConfig.GetIAMRoleOptions(), remoteStateTGConfig.Workspace)
+	})
 }
 
 // canGetRemoteState returns true if the remote state block is not nil and dependency optimization is not disabled
@@ -697,12 +749,12 @@ func terragruntAlreadyInit(terragruntOptions *options.TerragruntOptions, configP
 
 // getTerragruntOutputJsonFromInitFolder will retrieve the outputs directly from the module's working directory without
 // running init.
-func getTerragruntOutputJsonFromInitFolder(ctx *ParsingContext, terraformWorkingDir string, iamRoleOpts options.IAMRoleOptions) ([]byte, error) {
+func getTerragruntOutputJsonFromInitFolder(ctx *ParsingContext, terraformWorkingDir string, iamRoleOpts options.IAMRoleOptions, workspace string) ([]byte, error) {
 	targetConfigPath := ctx.TerragruntOptions.TerragruntConfigPath
 
 	ctx.TerragruntOptions.Logger.Debugf("Detected module %s is already init-ed. Retrieving outputs directly from working directory.", targetConfigPath)
 
-	targetTGOptions, err := setupTerragruntOptionsForBareTerraform(ctx, terraformWorkingDir, targetConfigPath, iamRoleOpts)
+	targetTGOptions, err := setupTerragruntOptionsForBareTerraform(ctx, terraformWorkingDir, targetConfigPath, iamRoleOpts, workspace)
 	if err != nil {
 		return nil, err
 	}
@@ -713,7 +765,7 @@ func getTerragruntOutputJsonFromInitFolder(ctx *ParsingContext, terraformWorking
 	}
 	jsonString := out.Stdout
 	jsonBytes := []byte(strings.TrimSpace(jsonString))
-	ctx.TerragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfigPath, jsonString)
+	ctx.TerragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfigPath, redactedOutputsJson(ctx.TerragruntOptions, []byte(jsonString)))
 	return jsonBytes, nil
 }
 
@@ -732,6 +784,7 @@ func getTerragruntOutputJsonFromRemoteState(
 	targetConfigPath string,
 	remoteState *remote.RemoteState,
 	iamRoleOpts options.IAMRoleOptions,
+	workspace string,
 ) ([]byte, error) {
 	ctx.TerragruntOptions.Logger.Debugf("Detected remote state block with generate config. Resolving dependency by pulling remote state.")
 	// Create working directory where we will run terraform in. We will create the temporary directory in the download
@@ -752,14 +805,15 @@ func getTerragruntOutputJsonFromRemoteState(
 	}(tempWorkDir)
 	ctx.TerragruntOptions.Logger.Debugf("Setting dependency working directory to %s", tempWorkDir)
 
-	targetTGOptions, err := setupTerragruntOptionsForBareTerraform(ctx, tempWorkDir, targetConfigPath, iamRoleOpts)
+	targetTGOptions, err := setupTerragruntOptionsForBareTerraform(ctx, tempWorkDir, targetConfigPath, iamRoleOpts, workspace)
 	if err != nil {
 		return nil, err
 	}
 	ctx = ctx.WithTerragruntOptions(targetTGOptions)
 
 	// To speed up dependencies processing it is possible to retrieve its output directly from the backend without init dependencies
-	if ctx.TerragruntOptions.FetchDependencyOutputFromState {
+	// The state encrypted by OpenTofu can only be read by OpenTofu
+	if ctx.TerragruntOptions.FetchDependencyOutputFromState && remoteState.Encryption == nil {
 		switch backend := remoteState.Backend; backend {
 		case "s3":
 			jsonBytes, err := getTerragruntOutputJsonFromRemoteStateS3(
@@ -769,7 +823,7 @@ func getTerragruntOutputJsonFromRemoteState(
 			if err != nil {
 				return nil, err
 			}
-			ctx.TerragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s using s3 bucket", targetConfigPath, jsonBytes)
+			ctx.TerragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s using s3 bucket", targetConfigPath, redactedOutputsJson(ctx.TerragruntOptions, jsonBytes))
 			return jsonBytes, nil
 		default:
 			ctx.TerragruntOptions.Logger.Errorf("FetchDependencyOutputFromState is not supported for backend %s, falling back to normal method", backend)
@@ -807,7 +861,7 @@ func getTerragruntOutputJsonFromRemoteState(
 	}
 	jsonString := out.Stdout
 	jsonBytes := []byte(strings.TrimSpace(jsonString))
-	ctx.TerragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfigPath, jsonString)
+	ctx.TerragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfigPath, redactedOutputsJson(ctx.TerragruntOptions, []byte(jsonString)))
 
 	return jsonBytes, nil
 
@@ -815,7 +869,8 @@ func getTerragruntOutputJsonFromRemoteState(
 
 // getTerragruntOutputJsonFromRemoteStateS3 pulls the output directly from an S3 bucket without calling Terraform
 func getTerragruntOutputJsonFromRemoteStateS3(terragruntOptions *options.TerragruntOptions, remoteState *remote.RemoteState) ([]byte, error) {
-	terragruntOptions.Logger.Debugf("Fetching outputs directly from s3://%s/%s", remoteState.Config["bucket"], remoteState.Config["key"])
+	key := s3WorkspaceStateKey(remoteState, terragruntOptions.Env[terraform.EnvNameTFWorkspace])
+	terragruntOptions.Logger.Debugf("Fetching outputs directly from s3://%s/%s", remoteState.Config["bucket"], key)
 
 	s3ConfigExtended, err := remote.ParseExtendedS3Config(remoteState.Config)
 	if err != nil {
@@ -831,7 +886,7 @@ func getTerragruntOutputJsonFromRemoteStateS3(terragruntOptions *options.Terragr
 
 	result, err := s3Client.GetObject(&s3.GetObjectInput{
 		Bucket: aws.String(fmt.Sprintf("%s", remoteState.Config["bucket"])),
-		Key:    aws.String(fmt.Sprintf("%s", remoteState.Config["key"])),
+		Key:    aws.String(key),
 	})
 
 	if err != nil {
@@ -861,9 +916,25 @@ func getTerragruntOutputJsonFromRemoteStateS3(terragruntOptions *options.Terragr
 	return jsonOutputs, nil
 }
 
+// s3WorkspaceStateKey returns the key of the state of the given workspace in the S3 bucket: the key of the config for
+// the default workspace, and the key prefixed with the workspace_key_prefix, "env:" by default, and the workspace for
+// the other workspaces, as the S3 backend stores them.
+func s3WorkspaceStateKey(remoteState *remote.RemoteState, workspace string) string {
+	key := fmt.Sprintf("%s", remoteState.Config["key"])
+	if workspace == "" || workspace == terraform.DefaultWorkspace {
+		return key
+	}
+
+	prefix := "env:"
+	if configPrefix, ok := remoteState.Config["workspace_key_prefix"]; ok {
+		prefix = fmt.Sprintf("%s", configPrefix)
+	}
+	return path.Join(prefix, workspace, key)
+}
+
 // setupTerragruntOptionsForBareTerraform sets up a new TerragruntOptions struct that can be used to run terraform
 // without going through the full RunTerragrunt operation.
-func setupTerragruntOptionsForBareTerraform(ctx *ParsingContext, workingDir string, configPath string, iamRoleOpts options.IAMRoleOptions) (*options.TerragruntOptions, error) {
+func setupTerragruntOptionsForBareTerraform(ctx *ParsingContext, workingDir string, configPath string, iamRoleOpts options.IAMRoleOptions, workspace string) (*options.TerragruntOptions, error) {
 	// Here we clone the terragrunt options again since we need to make further modifications to it to allow running
 	// terraform directly.
 	// Set the terraform working dir to the tempdir, and set stdout writer to io.Discard so that output content is
@@ -876,6 +947,11 @@ func setupTerragruntOptionsForBareTerraform(ctx *ParsingContext, workingDir stri
 	// the one we retrieved from the config.
 	targetTGOptions.IAMRoleOptions = options.MergeIAMRoleOptions(iamRoleOpts, targetTGOptions.OriginalIAMRoleOptions)
 
+	// The outputs are read from the state of the workspace of the target config, if it sets one
+	if workspace != "" {
+		targetTGOptions.Env[terraform.EnvNameTFWorkspace] = workspace
+	}
+
 	// Make sure to assume any roles set by TERRAGRUNT_IAM_ROLE
 	if err := aws_helper.AssumeRoleAndUpdateEnvIfNecessary(targetTGOptions); err != nil {
 		return nil, err
@@ -908,7 +984,7 @@ func runTerragruntOutputJson(ctx *ParsingContext, targetConfig string) ([]byte,
 	}
 	jsonString := stdoutBuffer.String()
 	jsonBytes := []byte(strings.TrimSpace(jsonString))
-	ctx.TerragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfig, jsonString)
+	ctx.TerragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfig, redactedOutputsJson(ctx.TerragruntOptions, []byte(jsonString)))
 	return jsonBytes, nil
 }
 
@@ -947,6 +1023,7 @@ func terraformOutputJsonToCtyValueMap(targetConfigPath string, jsonBytes []byte)
 // ClearOutputCache clears the output cache. Useful during testing.
 func ClearOutputCache() {
 	jsonOutputCache = sync.Map{}
+	publishedOutputsCache = sync.Map{}
 }
 
 // runTerraformInitForDependencyOutput will run terraform init in a mode that doesn't pull down plugins or modules. Note
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/remotecache"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// remoteCachedOutputs is the entry of the outputs of a dependency in the remote cache.
+type remoteCachedOutputs struct {
+	StoredAt time.Time `json:"stored_at"`
+	Outputs  []byte    `json:"outputs"`
+}
+
+// dependencyOutputsRemoteCacheKey returns the key of the outputs of the state of the given remote state and workspace
+// in the remote cache, so that every module reading the state shares the entry, wherever it is checked out.
+func dependencyOutputsRemoteCacheKey(remoteState *remote.RemoteState, workspace string, envWorkspace string) (string, error) {
+	id, err := json.Marshal(map[string]interface{}{
+		"backend":       remoteState.Backend,
+		"config":        remoteState.Config,
+		"workspace":     workspace,
+		"env_workspace": envWorkspace,
+	})
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return remotecache.Key(remotecache.KindOutputs, string(id)), nil
+}
+
+// hasSensitiveOutputs returns true if any of the given outputs, in the `terraform output -json` format, is sensitive,
+// or if the outputs can't be read to tell.
+func hasSensitiveOutputs(outputs []byte) bool {
+	var decoded map[string]struct {
+		Sensitive bool `json:"sensitive"`
+	}
+	if err := json.Unmarshal(outputs, &decoded); err != nil {
+		return true
+	}
+	for _, output := range decoded {
+		if output.Sensitive {
+			return true
+		}
+	}
+	return false
+}
+
+// getOutputJsonWithRemoteCache returns the outputs of the state of the given remote state from the remote cache if it
+// has them, stored less than --terragrunt-remote-cache-outputs-ttl-sec ago, and reads them with the given function and
+// stores them in the remote cache otherwise. The outputs are only read from the remote cache for the commands which do
+// not change the infrastructure, as the dependencies applied by the same run-all apply change their outputs, but they
+// are stored by every command, unless some are sensitive, as anyone who can read the remote cache could read them.
+// Failing to use the remote cache does not fail the run.
+func getOutputJsonWithRemoteCache(ctx *ParsingContext, mutating bool, remoteState *remote.RemoteState, workspace string, getOutputJson func() ([]byte, error)) ([]byte, error) {
+	opts := ctx.TerragruntOptions
+	if opts.RemoteCacheOutputsTTLSec <= 0 {
+		return getOutputJson()
+	}
+	backend, err := remotecache.FromOptions(opts)
+	if err != nil || backend == nil {
+		return getOutputJson()
+	}
+	key, err := dependencyOutputsRemoteCacheKey(remoteState, workspace, opts.Env[terraform.EnvNameTFWorkspace])
+	if err != nil {
+		return nil, err
+	}
+
+	if !mutating {
+		var entry remoteCachedOutputs
+		content, hit, err := remotecache.FetchBytes(ctx, backend, key)
+		if err == nil && hit {
+			if err := json.Unmarshal(content, &entry); err != nil {
+				opts.Logger.Warnf("Ignoring the invalid outputs of %s in the remote cache: %v", opts.TerragruntConfigPath, err)
+				hit = false
+			}
+		}
+		if err != nil {
+			opts.Logger.Warnf("Failed to fetch the outputs of %s from the remote cache, reading them: %v", opts.TerragruntConfigPath, err)
+		}
+
+		hit = hit && time.Since(entry.StoredAt) < time.Duration(opts.RemoteCacheOutputsTTLSec)*time.Second
+		util.CountCacheLookup(util.RemoteCache, hit)
+		if hit {
+			opts.Logger.Debugf("Using the outputs of %s stored in the remote cache at %s", opts.TerragruntConfigPath, entry.StoredAt.Format(time.RFC3339))
+			return entry.Outputs, nil
+		}
+	}
+
+	outputs, err := getOutputJson()
+	if err != nil {
+		return nil, err
+	}
+	if hasSensitiveOutputs(outputs) {
+		opts.Logger.Debugf("Not storing the outputs of %s in the remote cache, as some are sensitive", opts.TerragruntConfigPath)
+		return outputs, nil
+	}
+
+	content, err := json.Marshal(remoteCachedOutputs{StoredAt: time.Now().UTC(), Outputs: outputs})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if err := remotecache.StoreBytes(ctx, backend, key, content); err != nil {
+		opts.Logger.Warnf("Failed to store the outputs of %s in the remote cache: %v", opts.TerragruntConfigPath, err)
+	}
+	return outputs, nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOutputJsonWithRemoteCache(t *testing.T) {
+	t.Parallel()
+
+	entries := map[string][]byte{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPut {
+			entries[r.URL.Path], _ = io.ReadAll(r.Body)
+			return
+		}
+		if content, ok := entries[r.URL.Path]; ok {
+			_, _ = w.Write(content)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/vpc/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.RemoteCacheURL = server.URL
+	opts.RemoteCacheOutputsTTLSec = 60
+	ctx := NewParsingContext(context.Background(), opts)
+
+	remoteState := &remote.RemoteState{Backend: "s3", Config: map[string]interface{}{"bucket": "acme-state", "key": "vpc/terraform.tfstate"}}
+	reads := 0
+	getOutputJson := func() ([]byte, error) {
+		reads++
+		return []byte(`{"vpc_id":{"value":"vpc-123"}}`), nil
+	}
+
+	// The outputs are read and stored on a miss, and for the commands changing the infrastructure
+	for _, mutating := range []bool{false, true} {
+		outputs, err := getOutputJsonWithRemoteCache(ctx, mutating, remoteState, "", getOutputJson)
+		require.NoError(t, err)
+		assert.Equal(t, `{"vpc_id":{"value":"vpc-123"}}`, string(outputs))
+	}
+	assert.Equal(t, 2, reads)
+	require.Len(t, entries, 1)
+
+	outputs, err := getOutputJsonWithRemoteCache(ctx, false, remoteState, "", getOutputJson)
+	require.NoError(t, err)
+	assert.Equal(t, `{"vpc_id":{"value":"vpc-123"}}`, string(outputs))
+	assert.Equal(t, 2, reads)
+
+	// Another workspace is another state
+	_, err = getOutputJsonWithRemoteCache(ctx, false, remoteState, "staging", getOutputJson)
+	require.NoError(t, err)
+	assert.Equal(t, 3, reads)
+
+	// The outputs stored before the TTL are read again
+	expired, err := json.Marshal(remoteCachedOutputs{StoredAt: time.Now().Add(-time.Hour), Outputs: []byte(`{}`)})
+	require.NoError(t, err)
+	mu.Lock()
+	for path := range entries {
+		entries[path] = expired
+	}
+	mu.Unlock()
+	_, err = getOutputJsonWithRemoteCache(ctx, false, remoteState, "", getOutputJson)
+	require.NoError(t, err)
+	assert.Equal(t, 4, reads)
+}
+
+func TestGetOutputJsonWithRemoteCacheSensitiveOutputs(t *testing.T) {
+	t.Parallel()
+
+	stored := 0
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			mu.Lock()
+			stored++
+			mu.Unlock()
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/db/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.RemoteCacheURL = server.URL
+	opts.RemoteCacheOutputsTTLSec = 60
+	ctx := NewParsingContext(context.Background(), opts)
+
+	remoteState := &remote.RemoteState{Backend: "s3", Config: map[string]interface{}{"bucket": "acme-state", "key": "db/terraform.tfstate"}}
+	getOutputJson := func() ([]byte, error) {
+		return []byte(`{"endpoint":{"value":"db.internal"},"password":{"sensitive":true,"value":"hunter2"}}`), nil
+	}
+
+	outputs, err := getOutputJsonWithRemoteCache(ctx, false, remoteState, "", getOutputJson)
+	require.NoError(t, err)
+	assert.Contains(t, string(outputs), "hunter2")
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 0, stored)
+}
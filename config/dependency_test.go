@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
 
 	"github.com/gruntwork-io/go-commons/env"
 	"github.com/gruntwork-io/terragrunt/config/hclparse"
@@ -147,3 +148,29 @@ dependency "vpc" {
 	require.NoError(t, file.Decode(&decoded, &hcl.EvalContext{}))
 	assert.Equal(t, len(decoded.Dependencies), 2)
 }
+
+func TestS3WorkspaceStateKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		config    map[string]interface{}
+		workspace string
+		expected  string
+	}{
+		{"no workspace", map[string]interface{}{"key": "vpc/terraform.tfstate"}, "", "vpc/terraform.tfstate"},
+		{"default workspace", map[string]interface{}{"key": "vpc/terraform.tfstate"}, "default", "vpc/terraform.tfstate"},
+		{"workspace", map[string]interface{}{"key": "vpc/terraform.tfstate"}, "staging", "env:/staging/vpc/terraform.tfstate"},
+		{"workspace key prefix", map[string]interface{}{"key": "vpc/terraform.tfstate", "workspace_key_prefix": "workspaces"}, "staging", "workspaces/staging/vpc/terraform.tfstate"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			remoteState := &remote.RemoteState{Backend: "s3", Config: testCase.config}
+			assert.Equal(t, testCase.expected, s3WorkspaceStateKey(remoteState, testCase.workspace))
+		})
+	}
+}
@@ -68,6 +68,22 @@ func (err DuplicatedGenerateBlocksError) Error() string {
 	)
 }
 
+type GenerateContentsAndSourceError struct {
+	BlockName string
+}
+
+func (err GenerateContentsAndSourceError) Error() string {
+	return fmt.Sprintf("The generate block %s sets both contents and source, only one of them can be set", err.BlockName)
+}
+
+type GenerateChecksumWithoutSourceError struct {
+	BlockName string
+}
+
+func (err GenerateChecksumWithoutSourceError) Error() string {
+	return fmt.Sprintf("The generate block %s sets a checksum without a source, the checksum is only verified for the contents fetched from a source", err.BlockName)
+}
+
 type TFVarFileNotFoundError struct {
 	File  string
 	Cause string
@@ -243,3 +259,54 @@ type DependencyCycle []string
 func (err DependencyCycle) Error() string {
 	return fmt.Sprintf("Found a dependency cycle between modules: %s", strings.Join([]string(err), " -> "))
 }
+
+type DataOnlyUnavailableFunctionError string
+
+func (err DataOnlyUnavailableFunctionError) Error() string {
+	return fmt.Sprintf("The function %s can't be called in a config read with the data_only option of read_terragrunt_config, as it has side effects. Read the config without data_only to call it.", string(err))
+}
+
+type InvalidReadTerragruntConfigOptionError string
+
+func (err InvalidReadTerragruntConfigOptionError) Error() string {
+	return fmt.Sprintf("Unknown option %s of read_terragrunt_config. Valid options are: %s", string(err), readTerragruntConfigOptionDataOnly)
+}
+
+type RemoteDependencyFetchError struct {
+	ConfigPath string
+	Err        error
+}
+
+func (err RemoteDependencyFetchError) Error() string {
+	return fmt.Sprintf("Failed to fetch the repo of the dependency %s: %v", err.ConfigPath, err.Err)
+}
+
+func (err RemoteDependencyFetchError) Unwrap() error {
+	return err.Err
+}
+
+type RemoteDependencyWithoutRemoteState struct {
+	ConfigPath string
+}
+
+func (err RemoteDependencyWithoutRemoteState) Error() string {
+	return fmt.Sprintf("The dependency %s is in another repo, so its outputs are read from its state, but its config has no remote_state block, or disables the dependency optimization.", err.ConfigPath)
+}
+
+type InvalidTerraformParallelism int
+
+func (parallelism InvalidTerraformParallelism) Error() string {
+	return fmt.Sprintf("The terraform_parallelism must be at least 1, but is %d", int(parallelism))
+}
+
+type RestrictedConfigNotAllowed struct {
+	Setting    string
+	ConfigPath string
+}
+
+func (err RestrictedConfigNotAllowed) Error() string {
+	return fmt.Sprintf("The %s of the config %s is not allowed with --terragrunt-restricted, which runs untrusted configs", err.Setting, err.ConfigPath)
+}
+
+// PolicyViolation marks the error as Terragrunt refusing to do something the options forbid.
+func (err RestrictedConfigNotAllowed) PolicyViolation() {}
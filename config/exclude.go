@@ -0,0 +1,19 @@
+package config
+
+import "fmt"
+
+// ExcludeConfig is the `exclude` block, removing the module from the runs of the stacks it is in when the condition
+// holds, e.g. while the region of the module is not enabled yet.
+type ExcludeConfig struct {
+	If     bool   `hcl:"if,attr" cty:"if"`
+	Reason string `hcl:"reason,optional" cty:"reason"`
+}
+
+func (conf *ExcludeConfig) String() string {
+	return fmt.Sprintf("Exclude{If = %v, Reason = %v}", conf.If, conf.Reason)
+}
+
+// IsExcluded returns true if the given exclude block, which may be nil, excludes the module.
+func (conf *ExcludeConfig) IsExcluded() bool {
+	return conf != nil && conf.If
+}
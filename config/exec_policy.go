@@ -0,0 +1,41 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// ExecPolicyConfig is the `exec_policy` block, restricting the commands that hooks and run_cmd may execute. The block
+// is only enforced when read from the trusted config given by --terragrunt-exec-policy-config, as the configs being run
+// could simply remove it.
+type ExecPolicyConfig struct {
+	Allow     []string `hcl:"allow,optional" cty:"allow"`
+	Deny      []string `hcl:"deny,optional" cty:"deny"`
+	NoNetwork bool     `hcl:"no_network,optional" cty:"no_network"`
+}
+
+func (conf *ExecPolicyConfig) String() string {
+	return fmt.Sprintf("ExecPolicy{Allow = %v, Deny = %v, NoNetwork = %v}", conf.Allow, conf.Deny, conf.NoNetwork)
+}
+
+// ReadExecPolicy reads the `exec_policy` block of the given config, returning nil if the config doesn't define one.
+func ReadExecPolicy(ctx context.Context, opts *options.TerragruntOptions, configPath string) (*options.ExecPolicy, error) {
+	parsingCtx := NewParsingContext(ctx, opts.Clone(configPath)).WithDecodeList(ExecPolicyBlock)
+
+	terragruntConfig, err := PartialParseConfigFile(parsingCtx, configPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if terragruntConfig.ExecPolicy == nil {
+		return nil, nil
+	}
+
+	return &options.ExecPolicy{
+		Source:    configPath,
+		Allow:     terragruntConfig.ExecPolicy.Allow,
+		Deny:      terragruntConfig.ExecPolicy.Deny,
+		NoNetwork: terragruntConfig.ExecPolicy.NoNetwork,
+	}, nil
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// The backends of the execution block.
+const (
+	ExecutionBackendLocal      = "local"
+	ExecutionBackendSSH        = "ssh"
+	ExecutionBackendKubernetes = "kubernetes"
+	// The terraform_cloud backend maps the module to a Terraform Cloud or Terraform Enterprise workspace.
+	ExecutionBackendTerraformCloud = "terraform_cloud"
+)
+
+// The defaults of the terraform_cloud block.
+const (
+	DefaultTerraformCloudRunTimeout   = 2 * time.Hour
+	DefaultTerraformCloudPollInterval = 10 * time.Second
+)
+
+var executionBackends = []string{ExecutionBackendLocal, ExecutionBackendSSH, ExecutionBackendKubernetes, ExecutionBackendTerraformCloud}
+
+// ExecutionConfig is the `execution` block, selecting where the terraform commands of the module run, e.g. on a remote
+// host inside a network enclave.
+type ExecutionConfig struct {
+	Backend        string                         `hcl:"backend,attr" cty:"backend"`
+	SSH            *SSHExecutionConfig            `hcl:"ssh,block" cty:"ssh"`
+	Kubernetes     *KubernetesExecutionConfig     `hcl:"kubernetes,block" cty:"kubernetes"`
+	TerraformCloud *TerraformCloudExecutionConfig `hcl:"terraform_cloud,block" cty:"terraform_cloud"`
+}
+
+// SSHExecutionConfig configures running the terraform commands of the module on a remote host over SSH.
+type SSHExecutionConfig struct {
+	Host         string  `hcl:"host,attr" cty:"host"`
+	User         *string `hcl:"user,optional" cty:"user"`
+	Port         *int    `hcl:"port,optional" cty:"port"`
+	IdentityFile *string `hcl:"identity_file,optional" cty:"identity_file"`
+	// The dir on the remote host the working dir is copied to, defaults to a dir per module in the home dir.
+	RemoteDir     *string  `hcl:"remote_dir,optional" cty:"remote_dir"`
+	TerraformPath *string  `hcl:"terraform_path,optional" cty:"terraform_path"`
+	ForwardEnv    []string `hcl:"forward_env,optional" cty:"forward_env"`
+	ExtraArgs     []string `hcl:"extra_args,optional" cty:"extra_args"`
+}
+
+// KubernetesExecutionConfig configures running the terraform commands of the module in the pod of a Kubernetes Job.
+type KubernetesExecutionConfig struct {
+	Image          string            `hcl:"image,attr" cty:"image"`
+	Namespace      *string           `hcl:"namespace,optional" cty:"namespace"`
+	Context        *string           `hcl:"context,optional" cty:"context"`
+	ServiceAccount *string           `hcl:"service_account,optional" cty:"service_account"`
+	Requests       map[string]string `hcl:"requests,optional" cty:"requests"`
+	Limits         map[string]string `hcl:"limits,optional" cty:"limits"`
+	Labels         map[string]string `hcl:"labels,optional" cty:"labels"`
+	// How long the Job may run, and how long the finished Job is kept, e.g. "2h".
+	ActiveDeadline   *string  `hcl:"active_deadline,optional" cty:"active_deadline"`
+	TTLAfterFinished *string  `hcl:"ttl_after_finished,optional" cty:"ttl_after_finished"`
+	TerraformPath    *string  `hcl:"terraform_path,optional" cty:"terraform_path"`
+	ForwardEnv       []string `hcl:"forward_env,optional" cty:"forward_env"`
+}
+
+// TerraformCloudExecutionConfig configures running the plans and applies of the module as the runs of a Terraform Cloud
+// or Terraform Enterprise workspace, which terragrunt creates or updates with the inputs of the module as variables.
+type TerraformCloudExecutionConfig struct {
+	Organization string  `hcl:"organization,attr" cty:"organization"`
+	Workspace    string  `hcl:"workspace,attr" cty:"workspace"`
+	Hostname     *string `hcl:"hostname,optional" cty:"hostname"`
+	ProjectID    *string `hcl:"project_id,optional" cty:"project_id"`
+	// The terraform version and the dir of the repo the workspace runs in, left as they are if not set.
+	TerraformVersion *string  `hcl:"terraform_version,optional" cty:"terraform_version"`
+	WorkingDirectory *string  `hcl:"working_directory,optional" cty:"working_directory"`
+	Tags             []string `hcl:"tags,optional" cty:"tags"`
+	// The names of the inputs set as sensitive variables of the workspace.
+	SensitiveInputs []string `hcl:"sensitive_inputs,optional" cty:"sensitive_inputs"`
+	// The repo of a VCS-driven workspace. Without it, the workspace is CLI-driven: the working dir is uploaded for every run.
+	VCSRepo *TerraformCloudVCSRepoConfig `hcl:"vcs_repo,block" cty:"vcs_repo"`
+	// How long to wait for a run to finish, e.g. "1h", and how often to check its status.
+	Timeout      *string `hcl:"timeout,optional" cty:"timeout"`
+	PollInterval *string `hcl:"poll_interval,optional" cty:"poll_interval"`
+}
+
+// TerraformCloudVCSRepoConfig is the repo a VCS-driven workspace plans the commits of.
+type TerraformCloudVCSRepoConfig struct {
+	Identifier   string  `hcl:"identifier,attr" cty:"identifier"`
+	Branch       *string `hcl:"branch,optional" cty:"branch"`
+	OAuthTokenID string  `hcl:"oauth_token_id,attr" cty:"oauth_token_id"`
+}
+
+// GetTimeout returns how long to wait for a run to finish.
+func (conf *TerraformCloudExecutionConfig) GetTimeout() (time.Duration, error) {
+	return parseExecutionDurationWithDefault(ExecutionBackendTerraformCloud, "timeout", conf.Timeout, DefaultTerraformCloudRunTimeout)
+}
+
+// GetPollInterval returns how often to check the status of a run.
+func (conf *TerraformCloudExecutionConfig) GetPollInterval() (time.Duration, error) {
+	return parseExecutionDurationWithDefault(ExecutionBackendTerraformCloud, "poll_interval", conf.PollInterval, DefaultTerraformCloudPollInterval)
+}
+
+// GetActiveDeadline returns how long the Job may run, zero for the default.
+func (conf *KubernetesExecutionConfig) GetActiveDeadline() (time.Duration, error) {
+	return parseExecutionDuration(ExecutionBackendKubernetes, "active_deadline", conf.ActiveDeadline)
+}
+
+// GetTTLAfterFinished returns how long the finished Job is kept, zero to keep it.
+func (conf *KubernetesExecutionConfig) GetTTLAfterFinished() (time.Duration, error) {
+	return parseExecutionDuration(ExecutionBackendKubernetes, "ttl_after_finished", conf.TTLAfterFinished)
+}
+
+func parseExecutionDuration(block string, name string, value *string) (time.Duration, error) {
+	return parseExecutionDurationWithDefault(block, name, value, 0)
+}
+
+func parseExecutionDurationWithDefault(block string, name string, value *string, defaultValue time.Duration) (time.Duration, error) {
+	if value == nil {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(*value)
+	if err != nil || duration <= 0 {
+		return 0, errors.WithStackTrace(InvalidExecutionDuration{Block: block, Name: name, Value: *value})
+	}
+	return duration, nil
+}
+
+func (conf *ExecutionConfig) String() string {
+	return fmt.Sprintf("Execution{Backend = %v}", conf.Backend)
+}
+
+// Validate checks that the block of the selected backend is set.
+func (conf *ExecutionConfig) Validate() error {
+	switch conf.Backend {
+	case ExecutionBackendLocal:
+		return nil
+	case ExecutionBackendSSH:
+		if conf.SSH == nil {
+			return errors.WithStackTrace(MissingExecutionBackendBlock(conf.Backend))
+		}
+		return nil
+	case ExecutionBackendKubernetes:
+		if conf.Kubernetes == nil {
+			return errors.WithStackTrace(MissingExecutionBackendBlock(conf.Backend))
+		}
+		if _, err := conf.Kubernetes.GetActiveDeadline(); err != nil {
+			return err
+		}
+		_, err := conf.Kubernetes.GetTTLAfterFinished()
+		return err
+	case ExecutionBackendTerraformCloud:
+		if conf.TerraformCloud == nil {
+			return errors.WithStackTrace(MissingExecutionBackendBlock(conf.Backend))
+		}
+		if _, err := conf.TerraformCloud.GetTimeout(); err != nil {
+			return err
+		}
+		_, err := conf.TerraformCloud.GetPollInterval()
+		return err
+	default:
+		return errors.WithStackTrace(UnknownExecutionBackend(conf.Backend))
+	}
+}
+
+// Custom error types
+
+type UnknownExecutionBackend string
+
+func (backend UnknownExecutionBackend) Error() string {
+	return fmt.Sprintf("Unknown execution backend %s, must be one of: %s", string(backend), strings.Join(executionBackends, ", "))
+}
+
+type MissingExecutionBackendBlock string
+
+func (backend MissingExecutionBackendBlock) Error() string {
+	return fmt.Sprintf("The execution backend %s requires the %s block in the execution block", string(backend), string(backend))
+}
+
+type InvalidExecutionDuration struct {
+	Block string
+	Name  string
+	Value string
+}
+
+func (err InvalidExecutionDuration) Error() string {
+	return fmt.Sprintf("Invalid %s %q of the %s block, must be a positive duration such as \"30m\" or \"2h\"", err.Name, err.Value, err.Block)
+}
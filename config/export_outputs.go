@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/outputstore"
+)
+
+// ExportOutputsConfig is an `export_outputs` block, publishing the outputs of the module to a parameter store after each
+// successful apply, so that the consumers without access to the state can read them.
+type ExportOutputsConfig struct {
+	Name    string `hcl:"name,label" cty:"name"`
+	Backend string `hcl:"backend,attr" cty:"backend"`
+	// The key of each output, with the `{output}` placeholder, or the key of all the outputs as a JSON object
+	Key      string   `hcl:"key,attr" cty:"key"`
+	Outputs  []string `hcl:"outputs,optional" cty:"outputs"`
+	Region   *string  `hcl:"region,optional" cty:"region"`
+	Bucket   *string  `hcl:"bucket,optional" cty:"bucket"`
+	KmsKeyID *string  `hcl:"kms_key_id,optional" cty:"kms_key_id"`
+}
+
+func (conf *ExportOutputsConfig) String() string {
+	return fmt.Sprintf("ExportOutputs{Name = %v, Backend = %v, Key = %v}", conf.Name, conf.Backend, conf.Key)
+}
+
+// StoreConfig returns the config of the store the outputs are published to.
+func (conf *ExportOutputsConfig) StoreConfig() outputstore.Config {
+	storeConfig := outputstore.Config{Backend: conf.Backend}
+	if conf.Region != nil {
+		storeConfig.Region = *conf.Region
+	}
+	if conf.Bucket != nil {
+		storeConfig.Bucket = *conf.Bucket
+	}
+	if conf.KmsKeyID != nil {
+		storeConfig.KmsKeyID = *conf.KmsKeyID
+	}
+	return storeConfig
+}
+
+// Validate checks that the store of the block is supported and that the block has a key.
+func (conf *ExportOutputsConfig) Validate() error {
+	if conf.Key == "" {
+		return errors.WithStackTrace(MissingExportOutputsKey(conf.Name))
+	}
+	if err := conf.StoreConfig().Validate(); err != nil {
+		return errors.WithStackTrace(InvalidExportOutputs{Name: conf.Name, Err: err})
+	}
+	return nil
+}
+
+// validateExportOutputs validates the given export_outputs blocks, whose names must be unique.
+func validateExportOutputs(blocks []ExportOutputsConfig) error {
+	names := map[string]bool{}
+	for i := range blocks {
+		if names[blocks[i].Name] {
+			return errors.WithStackTrace(DuplicateExportOutputs(blocks[i].Name))
+		}
+		names[blocks[i].Name] = true
+
+		if err := blocks[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeExportOutputs merges the export_outputs blocks of the child into the blocks of the parent by name, the block
+// of the child overriding the block of the parent with the same name.
+func mergeExportOutputs(childBlocks []ExportOutputsConfig, parentBlocks []ExportOutputsConfig) []ExportOutputsConfig {
+	result := append([]ExportOutputsConfig(nil), parentBlocks...)
+	for _, child := range childBlocks {
+		found := false
+		for i := range result {
+			if result[i].Name == child.Name {
+				result[i] = child
+				found = true
+			}
+		}
+		if !found {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// Custom error types
+
+type MissingExportOutputsKey string
+
+func (name MissingExportOutputsKey) Error() string {
+	return fmt.Sprintf("The export_outputs block %q has an empty key", string(name))
+}
+
+type InvalidExportOutputs struct {
+	Name string
+	Err  error
+}
+
+func (err InvalidExportOutputs) Error() string {
+	return fmt.Sprintf("Invalid export_outputs block %q: %v", err.Name, err.Err)
+}
+
+func (err InvalidExportOutputs) Unwrap() error {
+	return err.Err
+}
+
+type DuplicateExportOutputs string
+
+func (name DuplicateExportOutputs) Error() string {
+	return fmt.Sprintf("Multiple export_outputs blocks are named %q, the names must be unique", string(name))
+}
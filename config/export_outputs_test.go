@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/outputstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerragruntConfigExportOutputs(t *testing.T) {
+	t.Parallel()
+
+	config := `
+export_outputs "ssm" {
+  backend = "ssm"
+  key     = "/prod/vpc/{output}"
+  outputs = ["vpc_id", "subnet_ids"]
+  region  = "eu-west-1"
+}
+
+export_outputs "gcs" {
+  backend = "gcs"
+  bucket  = "platform-outputs"
+  key     = "prod/vpc.json"
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+
+	require.Len(t, terragruntConfig.ExportOutputs, 2)
+	assert.Equal(t, "ssm", terragruntConfig.ExportOutputs[0].Name)
+	assert.Equal(t, []string{"vpc_id", "subnet_ids"}, terragruntConfig.ExportOutputs[0].Outputs)
+	assert.Equal(t, outputstore.Config{Backend: outputstore.BackendSSM, Region: "eu-west-1"}, terragruntConfig.ExportOutputs[0].StoreConfig())
+	assert.Nil(t, terragruntConfig.ExportOutputs[1].Outputs)
+	assert.Equal(t, outputstore.Config{Backend: outputstore.BackendGCS, Bucket: "platform-outputs"}, terragruntConfig.ExportOutputs[1].StoreConfig())
+}
+
+func TestParseTerragruntConfigInvalidExportOutputs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		config   string
+		expected error
+	}{
+		{
+			config: `
+export_outputs "ssm" {
+  backend = "consul"
+  key     = "vpc"
+}
+`,
+			expected: outputstore.UnsupportedBackend("consul"),
+		},
+		{
+			config: `
+export_outputs "gcs" {
+  backend = "gcs"
+  key     = "vpc"
+}
+`,
+			expected: outputstore.MissingBucket{},
+		},
+		{
+			config: `
+export_outputs "ssm" {
+  backend = "ssm"
+  key     = ""
+}
+`,
+			expected: MissingExportOutputsKey("ssm"),
+		},
+		{
+			config: `
+export_outputs "ssm" {
+  backend = "ssm"
+  key     = "/vpc/{output}"
+}
+export_outputs "ssm" {
+  backend = "ssm"
+  key     = "/network/{output}"
+}
+`,
+			expected: DuplicateExportOutputs("ssm"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+		_, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, testCase.config, nil)
+		require.Error(t, err, testCase.config)
+
+		cause := errors.Unwrap(err)
+		if invalid, ok := cause.(InvalidExportOutputs); ok {
+			cause = errors.Unwrap(invalid.Err)
+		}
+		assert.Equal(t, testCase.expected, cause, testCase.config)
+	}
+}
+
+func TestMergeExportOutputs(t *testing.T) {
+	t.Parallel()
+
+	parent := []ExportOutputsConfig{
+		{Name: "ssm", Backend: "ssm", Key: "/vpc/{output}"},
+		{Name: "gcs", Backend: "gcs", Key: "vpc.json"},
+	}
+	child := []ExportOutputsConfig{
+		{Name: "ssm", Backend: "ssm", Key: "/prod/vpc/{output}"},
+		{Name: "secrets", Backend: "secrets_manager", Key: "prod/vpc"},
+	}
+
+	assert.Equal(t, []ExportOutputsConfig{
+		{Name: "ssm", Backend: "ssm", Key: "/prod/vpc/{output}"},
+		{Name: "gcs", Backend: "gcs", Key: "vpc.json"},
+		{Name: "secrets", Backend: "secrets_manager", Key: "prod/vpc"},
+	}, mergeExportOutputs(child, parent))
+	// The blocks of the parent are not modified
+	assert.Equal(t, "/vpc/{output}", parent[0].Key)
+}
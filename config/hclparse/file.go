@@ -128,6 +128,10 @@ func (file *File) diagnosticsError(diags hcl.Diagnostics) error {
 		return nil
 	}
 
+	if fn := file.Parser.diagnosticsUpdateFunc; fn != nil {
+		diags = fn(diags)
+	}
+
 	if fn := file.Parser.diagnosticsErrorFunc; fn != nil {
 		var err error
 		if diags, err = fn(file, diags); err != nil || diags == nil {
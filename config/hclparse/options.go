@@ -22,6 +22,15 @@ func WithFileUpdate(fn func(*File) error) Option {
 	}
 }
 
+// WithDiagnosticsUpdate sets the `diagnosticsUpdateFunc` func which is run on the diagnostic errors before they are
+// handled and logged, e.g. to add details to them.
+func WithDiagnosticsUpdate(fn func(hcl.Diagnostics) hcl.Diagnostics) Option {
+	return func(parser Parser) Parser {
+		parser.diagnosticsUpdateFunc = fn
+		return parser
+	}
+}
+
 // WithHaltOnErrorOnlyForBlocks configures a diagnostic error handler that runs when diagnostic errors occur.
 // If errors occur in the given `blockNames` blocks, parser returns the error to its caller, otherwise it skips the error.
 func WithHaltOnErrorOnlyForBlocks(blockNames []string) Option {
@@ -18,6 +18,7 @@ type Parser struct {
 	*hclparse.Parser
 	logger                *logrus.Entry
 	diagnosticsErrorFunc  func(*File, hcl.Diagnostics) (hcl.Diagnostics, error)
+	diagnosticsUpdateFunc func(hcl.Diagnostics) hcl.Diagnostics
 	fileUpdateHandlerFunc func(*File) error
 }
 
@@ -76,6 +76,11 @@ func parseIncludedConfig(ctx *ParsingContext, includedConfig *IncludeConfig) (*T
 	// NOTE: To make the logic easier to implement, we implement the inverse here, where we check whether the included
 	// config has a dependency block, and if we are in the middle of a partial parse, we perform a partial parse of the
 	// included config.
+	// The includes of a config read as data are read as data too
+	if ctx.DataOnly {
+		return PartialParseConfigFile(ctx, includePath, includedConfig)
+	}
+
 	hasDependency, err := configFileHasDependencyBlock(includePath)
 	if err != nil {
 		return nil, err
@@ -125,6 +130,20 @@ func handleInclude(ctx *ParsingContext, config *TerragruntConfig, isPartial bool
 			return nil, err
 		}
 
+		// The fields with their own merge strategy are copied before the merge updates the included config
+		fieldStrategies, err := includeConfig.GetMergeStrategies()
+		if err != nil {
+			return nil, err
+		}
+		fieldMerges := newFieldMergeStrategies(fieldStrategies, parsedIncludeConfig, baseConfig)
+
+		var step *IncludeMergeStep
+		if ctx.TraceIncludeMergeFunc != nil {
+			if step, err = newIncludeMergeStep(ctx, includeConfig, mergeStrategy, fieldStrategies, isPartial, baseConfig, parsedIncludeConfig); err != nil {
+				return nil, err
+			}
+		}
+
 		switch mergeStrategy {
 		case NoMerge:
 			ctx.TerragruntOptions.Logger.Debugf("%sIncluded config %s has strategy no merge: not merging config in.", logPrefix, includeConfig.Path)
@@ -143,6 +162,17 @@ func handleInclude(ctx *ParsingContext, config *TerragruntConfig, isPartial bool
 		default:
 			return nil, fmt.Errorf("You reached an impossible condition. This is most likely a bug in terragrunt. Please open an issue at github.com/gruntwork-io/terragrunt with this error message. Code: UNKNOWN_MERGE_STRATEGY_%s", mergeStrategy)
 		}
+
+		if err := fieldMerges.apply(baseConfig, ctx.TerragruntOptions); err != nil {
+			return nil, err
+		}
+
+		if step != nil {
+			if step.After, err = TerragruntConfigAsCty(baseConfig); err != nil {
+				return nil, err
+			}
+			ctx.TraceIncludeMergeFunc(*step)
+		}
 	}
 	return baseConfig, nil
 }
@@ -165,6 +195,10 @@ func handleIncludeForDependency(ctx *ParsingContext, childDecodedDependency terr
 		if err != nil {
 			return nil, err
 		}
+		mergeStrategy, err = includeConfig.dependencyMergeStrategy(mergeStrategy)
+		if err != nil {
+			return nil, err
+		}
 
 		includedPartialParse, err := partialParseIncludedConfig(ctx.WithDecodeList(DependencyBlock), &includeConfig)
 		if err != nil {
@@ -185,6 +219,11 @@ func handleIncludeForDependency(ctx *ParsingContext, childDecodedDependency terr
 				return nil, err
 			}
 			baseDependencyBlock = mergedDependencyBlock
+		case ReplaceMerge:
+			ctx.TerragruntOptions.Logger.Debugf("Included config %s has strategy replace for dependency: replacing the included dependency blocks with those of the child, if any.", includeConfig.Path)
+			if len(baseDependencyBlock) == 0 {
+				baseDependencyBlock = includedPartialParse.TerragruntDependencies
+			}
 		default:
 			return nil, fmt.Errorf("You reached an impossible condition. This is most likely a bug in terragrunt. Please open an issue at github.com/gruntwork-io/terragrunt with this error message. Code: UNKNOWN_MERGE_STRATEGY_%s_DEPENDENCY", mergeStrategy)
 		}
@@ -207,6 +246,10 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		targetConfig.DownloadDir = sourceConfig.DownloadDir
 	}
 
+	if sourceConfig.Workspace != "" {
+		targetConfig.Workspace = sourceConfig.Workspace
+	}
+
 	if sourceConfig.IamRole != "" {
 		targetConfig.IamRole = sourceConfig.IamRole
 	}
@@ -231,6 +274,68 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		targetConfig.RetryMaxAttempts = sourceConfig.RetryMaxAttempts
 	}
 
+	if sourceConfig.Priority != nil {
+		targetConfig.Priority = sourceConfig.Priority
+	}
+
+	if sourceConfig.TerraformParallelism != nil {
+		targetConfig.TerraformParallelism = sourceConfig.TerraformParallelism
+	}
+
+	if sourceConfig.Owner != "" {
+		targetConfig.Owner = sourceConfig.Owner
+	}
+
+	if sourceConfig.ConcurrencyGroup != "" {
+		targetConfig.ConcurrencyGroup = sourceConfig.ConcurrencyGroup
+	}
+
+	for name, args := range sourceConfig.CommandAliases {
+		if targetConfig.CommandAliases == nil {
+			targetConfig.CommandAliases = map[string][]string{}
+		}
+		targetConfig.CommandAliases[name] = args
+	}
+
+	if sourceConfig.ExecPolicy != nil {
+		targetConfig.ExecPolicy = sourceConfig.ExecPolicy
+	}
+
+	if sourceConfig.ReadOnly != nil {
+		targetConfig.ReadOnly = sourceConfig.ReadOnly
+	}
+
+	if sourceConfig.Approval != nil {
+		targetConfig.Approval = sourceConfig.Approval
+	}
+
+	// The export_outputs blocks are shallow merged by name
+	targetConfig.ExportOutputs = mergeExportOutputs(sourceConfig.ExportOutputs, targetConfig.ExportOutputs)
+
+	if sourceConfig.Execution != nil {
+		targetConfig.Execution = sourceConfig.Execution
+	}
+
+	if sourceConfig.Network != nil {
+		targetConfig.Network = sourceConfig.Network
+	}
+
+	if sourceConfig.StateLock != nil {
+		targetConfig.StateLock = sourceConfig.StateLock
+	}
+
+	if sourceConfig.SourceVerification != nil {
+		targetConfig.SourceVerification = sourceConfig.SourceVerification
+	}
+
+	if sourceConfig.ProviderMatrix != nil {
+		targetConfig.ProviderMatrix = sourceConfig.ProviderMatrix
+	}
+
+	if sourceConfig.ProviderVersionOverrides != nil {
+		targetConfig.ProviderVersionOverrides = sourceConfig.ProviderVersionOverrides
+	}
+
 	if sourceConfig.RetrySleepIntervalSec != nil {
 		targetConfig.RetrySleepIntervalSec = sourceConfig.RetrySleepIntervalSec
 	}
@@ -242,6 +347,10 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 	// Skip has to be set specifically in each file that should be skipped
 	targetConfig.Skip = sourceConfig.Skip
 
+	if sourceConfig.Exclude != nil {
+		targetConfig.Exclude = sourceConfig.Exclude
+	}
+
 	if sourceConfig.RemoteState != nil {
 		targetConfig.RemoteState = sourceConfig.RemoteState
 	}
@@ -252,6 +361,11 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		} else {
 			if sourceConfig.Terraform.Source != nil {
 				targetConfig.Terraform.Source = sourceConfig.Terraform.Source
+				// The checksum is pinned for the source next to it
+				targetConfig.Terraform.SourceChecksum = sourceConfig.Terraform.SourceChecksum
+			}
+			if sourceConfig.Terraform.SourceChecksum != nil {
+				targetConfig.Terraform.SourceChecksum = sourceConfig.Terraform.SourceChecksum
 			}
 			mergeExtraArgs(terragruntOptions, sourceConfig.Terraform.ExtraArgs, &targetConfig.Terraform.ExtraArgs)
 
@@ -278,6 +392,37 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		targetConfig.RetryableErrors = sourceConfig.RetryableErrors
 	}
 
+	if sourceConfig.ApprovedProviders != nil {
+		targetConfig.ApprovedProviders = sourceConfig.ApprovedProviders
+	}
+
+	// The profile blocks are shallow merged by name
+	targetConfig.Profiles = mergeProfiles(sourceConfig.Profiles, targetConfig.Profiles)
+
+	if sourceConfig.UseProfile != "" {
+		targetConfig.UseProfile = sourceConfig.UseProfile
+	}
+
+	if sourceConfig.Workspaces != nil {
+		targetConfig.Workspaces = sourceConfig.Workspaces
+	}
+
+	if sourceConfig.RunAfter != nil {
+		targetConfig.RunAfter = sourceConfig.RunAfter
+	}
+
+	if sourceConfig.SuppressWarnings != nil {
+		targetConfig.SuppressWarnings = sourceConfig.SuppressWarnings
+	}
+
+	if sourceConfig.Tags != nil {
+		targetConfig.Tags = sourceConfig.Tags
+	}
+
+	if sourceConfig.PreventDestroyPaths != nil {
+		targetConfig.PreventDestroyPaths = sourceConfig.PreventDestroyPaths
+	}
+
 	// Merge the generate configs. This is a shallow merge. Meaning, if the child has the same name generate block, then the
 	// child's generate block will override the parent's block.
 
@@ -308,6 +453,7 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 //     following structs have this limitation:
 //   - remote_state
 //   - generate
+//   - export_outputs
 //   - Note that the following attributes are deliberately omitted from the merge operation, as they are handled
 //     differently in the parser:
 //   - dependency blocks (TerragruntDependencies) [These blocks need to retrieve outputs, so we need to merge during
@@ -319,6 +465,10 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		targetConfig.DownloadDir = sourceConfig.DownloadDir
 	}
 
+	if sourceConfig.Workspace != "" {
+		targetConfig.Workspace = sourceConfig.Workspace
+	}
+
 	if sourceConfig.IamRole != "" {
 		targetConfig.IamRole = sourceConfig.IamRole
 	}
@@ -343,6 +493,68 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		targetConfig.RetryMaxAttempts = sourceConfig.RetryMaxAttempts
 	}
 
+	if sourceConfig.Priority != nil {
+		targetConfig.Priority = sourceConfig.Priority
+	}
+
+	if sourceConfig.TerraformParallelism != nil {
+		targetConfig.TerraformParallelism = sourceConfig.TerraformParallelism
+	}
+
+	if sourceConfig.Owner != "" {
+		targetConfig.Owner = sourceConfig.Owner
+	}
+
+	if sourceConfig.ConcurrencyGroup != "" {
+		targetConfig.ConcurrencyGroup = sourceConfig.ConcurrencyGroup
+	}
+
+	for name, args := range sourceConfig.CommandAliases {
+		if targetConfig.CommandAliases == nil {
+			targetConfig.CommandAliases = map[string][]string{}
+		}
+		targetConfig.CommandAliases[name] = args
+	}
+
+	if sourceConfig.ExecPolicy != nil {
+		targetConfig.ExecPolicy = sourceConfig.ExecPolicy
+	}
+
+	if sourceConfig.ReadOnly != nil {
+		targetConfig.ReadOnly = sourceConfig.ReadOnly
+	}
+
+	if sourceConfig.Approval != nil {
+		targetConfig.Approval = sourceConfig.Approval
+	}
+
+	// The export_outputs blocks are shallow merged by name
+	targetConfig.ExportOutputs = mergeExportOutputs(sourceConfig.ExportOutputs, targetConfig.ExportOutputs)
+
+	if sourceConfig.Execution != nil {
+		targetConfig.Execution = sourceConfig.Execution
+	}
+
+	if sourceConfig.Network != nil {
+		targetConfig.Network = sourceConfig.Network
+	}
+
+	if sourceConfig.StateLock != nil {
+		targetConfig.StateLock = sourceConfig.StateLock
+	}
+
+	if sourceConfig.SourceVerification != nil {
+		targetConfig.SourceVerification = sourceConfig.SourceVerification
+	}
+
+	if sourceConfig.ProviderMatrix != nil {
+		targetConfig.ProviderMatrix = sourceConfig.ProviderMatrix
+	}
+
+	if sourceConfig.ProviderVersionOverrides != nil {
+		targetConfig.ProviderVersionOverrides = sourceConfig.ProviderVersionOverrides
+	}
+
 	if sourceConfig.RetrySleepIntervalSec != nil {
 		targetConfig.RetrySleepIntervalSec = sourceConfig.RetrySleepIntervalSec
 	}
@@ -354,6 +566,10 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 	// Skip has to be set specifically in each file that should be skipped
 	targetConfig.Skip = sourceConfig.Skip
 
+	if sourceConfig.Exclude != nil {
+		targetConfig.Exclude = sourceConfig.Exclude
+	}
+
 	// Copy only dependencies which doesn't exist in source
 	if sourceConfig.Dependencies != nil {
 		resultModuleDependencies := &ModuleDependencies{}
@@ -398,6 +614,37 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		targetConfig.RetryableErrors = append(targetConfig.RetryableErrors, sourceConfig.RetryableErrors...)
 	}
 
+	if sourceConfig.ApprovedProviders != nil {
+		targetConfig.ApprovedProviders = append(targetConfig.ApprovedProviders, sourceConfig.ApprovedProviders...)
+	}
+
+	// The profile blocks are shallow merged by name
+	targetConfig.Profiles = mergeProfiles(sourceConfig.Profiles, targetConfig.Profiles)
+
+	if sourceConfig.UseProfile != "" {
+		targetConfig.UseProfile = sourceConfig.UseProfile
+	}
+
+	if sourceConfig.Workspaces != nil {
+		targetConfig.Workspaces = append(targetConfig.Workspaces, sourceConfig.Workspaces...)
+	}
+
+	if sourceConfig.RunAfter != nil {
+		targetConfig.RunAfter = append(targetConfig.RunAfter, sourceConfig.RunAfter...)
+	}
+
+	if sourceConfig.SuppressWarnings != nil {
+		targetConfig.SuppressWarnings = append(targetConfig.SuppressWarnings, sourceConfig.SuppressWarnings...)
+	}
+
+	if sourceConfig.Tags != nil {
+		targetConfig.Tags = append(targetConfig.Tags, sourceConfig.Tags...)
+	}
+
+	if sourceConfig.PreventDestroyPaths != nil {
+		targetConfig.PreventDestroyPaths = append(targetConfig.PreventDestroyPaths, sourceConfig.PreventDestroyPaths...)
+	}
+
 	// Handle complex structs by recursively merging the structs together
 	if sourceConfig.Terraform != nil {
 		if targetConfig.Terraform == nil {
@@ -405,6 +652,11 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		} else {
 			if sourceConfig.Terraform.Source != nil {
 				targetConfig.Terraform.Source = sourceConfig.Terraform.Source
+				// The checksum is pinned for the source next to it
+				targetConfig.Terraform.SourceChecksum = sourceConfig.Terraform.SourceChecksum
+			}
+			if sourceConfig.Terraform.SourceChecksum != nil {
+				targetConfig.Terraform.SourceChecksum = sourceConfig.Terraform.SourceChecksum
 			}
 
 			if sourceConfig.Terraform.IncludeInCopy != nil {
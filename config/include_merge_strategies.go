@@ -0,0 +1,340 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// ReplaceMerge is the merge strategy of a block or attribute replacing the one of the included config when the child
+// config sets it, instead of being merged into it. It is only valid in the merge_strategies of an include.
+const ReplaceMerge MergeStrategyType = "replace"
+
+// The names of the blocks of the terraform block which can have their own merge strategy.
+const (
+	mergeFieldBeforeHook     = "before_hook"
+	mergeFieldAfterHook      = "after_hook"
+	mergeFieldErrorHook      = "error_hook"
+	mergeFieldExtraArguments = "extra_arguments"
+)
+
+// mergeableField is a block or attribute of the config which can have its own merge strategy in an include.
+type mergeableField struct {
+	// isSet returns true if the given config sets the field.
+	isSet func(cfg *TerragruntConfig) bool
+	// copy sets the field of the target config to a copy of the field of the source config, which the merges can update
+	// without updating the source config.
+	copy func(target, source *TerragruntConfig)
+}
+
+// mergeableFields are the blocks and attributes which can have their own merge strategy, by name: the blocks and the
+// attributes holding collections of the config, and the blocks of the terraform block.
+var mergeableFields = map[string]mergeableField{
+	MetadataInputs: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.Inputs != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.Inputs = nil
+			if source.Inputs != nil {
+				target.Inputs = mergeInputs(source.Inputs, nil)
+			}
+		},
+	},
+	MetadataTerraform: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.Terraform != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.Terraform = nil
+			if source.Terraform != nil {
+				terraform := *source.Terraform
+				terraform.ExtraArgs = append([]TerraformExtraArguments(nil), source.Terraform.ExtraArgs...)
+				terraform.BeforeHooks = append([]Hook(nil), source.Terraform.BeforeHooks...)
+				terraform.AfterHooks = append([]Hook(nil), source.Terraform.AfterHooks...)
+				terraform.ErrorHooks = append([]ErrorHook(nil), source.Terraform.ErrorHooks...)
+				target.Terraform = &terraform
+			}
+		},
+	},
+	mergeFieldBeforeHook: {
+		isSet: func(cfg *TerragruntConfig) bool { return len(cfg.Terraform.GetBeforeHooks()) > 0 },
+		copy: func(target, source *TerragruntConfig) {
+			setTerraformBlocks(target, source, func(terraform *TerraformConfig, source *TerraformConfig) {
+				terraform.BeforeHooks = append([]Hook(nil), source.GetBeforeHooks()...)
+			})
+		},
+	},
+	mergeFieldAfterHook: {
+		isSet: func(cfg *TerragruntConfig) bool { return len(cfg.Terraform.GetAfterHooks()) > 0 },
+		copy: func(target, source *TerragruntConfig) {
+			setTerraformBlocks(target, source, func(terraform *TerraformConfig, source *TerraformConfig) {
+				terraform.AfterHooks = append([]Hook(nil), source.GetAfterHooks()...)
+			})
+		},
+	},
+	mergeFieldErrorHook: {
+		isSet: func(cfg *TerragruntConfig) bool { return len(cfg.Terraform.GetErrorHooks()) > 0 },
+		copy: func(target, source *TerragruntConfig) {
+			setTerraformBlocks(target, source, func(terraform *TerraformConfig, source *TerraformConfig) {
+				terraform.ErrorHooks = append([]ErrorHook(nil), source.GetErrorHooks()...)
+			})
+		},
+	},
+	mergeFieldExtraArguments: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.Terraform != nil && len(cfg.Terraform.ExtraArgs) > 0 },
+		copy: func(target, source *TerragruntConfig) {
+			setTerraformBlocks(target, source, func(terraform *TerraformConfig, source *TerraformConfig) {
+				terraform.ExtraArgs = nil
+				if source != nil {
+					terraform.ExtraArgs = append([]TerraformExtraArguments(nil), source.ExtraArgs...)
+				}
+			})
+		},
+	},
+	MetadataRemoteState: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.RemoteState != nil },
+		copy:  func(target, source *TerragruntConfig) { target.RemoteState = source.RemoteState },
+	},
+	MetadataGenerateConfigs: {
+		isSet: func(cfg *TerragruntConfig) bool { return len(cfg.GenerateConfigs) > 0 },
+		copy: func(target, source *TerragruntConfig) {
+			target.GenerateConfigs = map[string]codegen.GenerateConfig{}
+			for name, generateConfig := range source.GenerateConfigs {
+				target.GenerateConfigs[name] = generateConfig
+			}
+		},
+	},
+	MetadataDependency: {
+		isSet: func(cfg *TerragruntConfig) bool { return len(cfg.TerragruntDependencies) > 0 },
+		copy: func(target, source *TerragruntConfig) {
+			target.TerragruntDependencies = append([]Dependency(nil), source.TerragruntDependencies...)
+		},
+	},
+	MetadataExportOutputs: {
+		isSet: func(cfg *TerragruntConfig) bool { return len(cfg.ExportOutputs) > 0 },
+		copy: func(target, source *TerragruntConfig) {
+			target.ExportOutputs = append([]ExportOutputsConfig(nil), source.ExportOutputs...)
+		},
+	},
+	MetadataDependencies: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.Dependencies != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.Dependencies = nil
+			if source.Dependencies != nil {
+				target.Dependencies = &ModuleDependencies{Paths: append([]string(nil), source.Dependencies.Paths...)}
+			}
+		},
+	},
+	MetadataRetryableErrors: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.RetryableErrors != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.RetryableErrors = copyStrings(source.RetryableErrors)
+		},
+	},
+	MetadataApprovedProviders: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.ApprovedProviders != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.ApprovedProviders = copyStrings(source.ApprovedProviders)
+		},
+	},
+	MetadataProfile: {
+		isSet: func(cfg *TerragruntConfig) bool { return len(cfg.Profiles) > 0 },
+		copy: func(target, source *TerragruntConfig) {
+			target.Profiles = append([]ProfileConfig(nil), source.Profiles...)
+		},
+	},
+	MetadataWorkspaces: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.Workspaces != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.Workspaces = copyStrings(source.Workspaces)
+		},
+	},
+	MetadataRunAfter: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.RunAfter != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.RunAfter = copyStrings(source.RunAfter)
+		},
+	},
+	MetadataSuppressWarnings: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.SuppressWarnings != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.SuppressWarnings = copyStrings(source.SuppressWarnings)
+		},
+	},
+	MetadataTags: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.Tags != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.Tags = copyStrings(source.Tags)
+		},
+	},
+	MetadataPreventDestroyPaths: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.PreventDestroyPaths != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.PreventDestroyPaths = copyStrings(source.PreventDestroyPaths)
+		},
+	},
+	MetadataCommandAliases: {
+		isSet: func(cfg *TerragruntConfig) bool { return cfg.CommandAliases != nil },
+		copy: func(target, source *TerragruntConfig) {
+			target.CommandAliases = nil
+			for name, args := range source.CommandAliases {
+				if target.CommandAliases == nil {
+					target.CommandAliases = map[string][]string{}
+				}
+				target.CommandAliases[name] = args
+			}
+		},
+	},
+}
+
+// GetMergeStrategies returns the merge strategies of the blocks and attributes overriding the merge strategy of the
+// include, by the name of the block or attribute.
+func (cfg *IncludeConfig) GetMergeStrategies() (map[string]MergeStrategyType, error) {
+	strategies := map[string]MergeStrategyType{}
+	for field, strategy := range cfg.MergeStrategies {
+		if _, ok := mergeableFields[field]; !ok {
+			return nil, errors.WithStackTrace(InvalidMergeStrategiesField(field))
+		}
+
+		switch MergeStrategyType(strategy) {
+		case NoMerge, ShallowMerge, DeepMerge, ReplaceMerge:
+			strategies[field] = MergeStrategyType(strategy)
+		default:
+			return nil, errors.WithStackTrace(InvalidFieldMergeStrategy{Field: field, Strategy: strategy})
+		}
+	}
+	return strategies, nil
+}
+
+// fieldMergeStrategies merges the included and child configs field by field, for the fields with their own merge
+// strategy. The values of the fields are copied before the included config is merged with the merge strategy of the
+// include, as the merge updates it.
+type fieldMergeStrategies struct {
+	strategies map[string]MergeStrategyType
+	included   *TerragruntConfig
+	child      *TerragruntConfig
+}
+
+// newFieldMergeStrategies copies the fields with their own merge strategy of the given included and child configs.
+func newFieldMergeStrategies(strategies map[string]MergeStrategyType, includedConfig, childConfig *TerragruntConfig) *fieldMergeStrategies {
+	merges := &fieldMergeStrategies{strategies: strategies, included: newMergeConfig(), child: newMergeConfig()}
+	for field := range strategies {
+		mergeableFields[field].copy(merges.included, includedConfig)
+		mergeableFields[field].copy(merges.child, childConfig)
+	}
+	return merges
+}
+
+// apply sets the fields with their own merge strategy of the given config, which is the included and child configs
+// merged with the merge strategy of the include.
+func (merges *fieldMergeStrategies) apply(mergedConfig *TerragruntConfig, terragruntOptions *options.TerragruntOptions) error {
+	// The terraform block is merged before its blocks, which override it
+	fields := make([]string, 0, len(merges.strategies))
+	for field := range merges.strategies {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if (fields[i] == MetadataTerraform) != (fields[j] == MetadataTerraform) {
+			return fields[i] == MetadataTerraform
+		}
+		return fields[i] < fields[j]
+	})
+
+	for _, field := range fields {
+		strategy := merges.strategies[field]
+		terragruntOptions.Logger.Debugf("Merging %s of the included config with strategy %s", field, strategy)
+
+		mergeableField := mergeableFields[field]
+		switch strategy {
+		case NoMerge:
+			mergeableField.copy(mergedConfig, merges.child)
+		case ReplaceMerge:
+			if mergeableField.isSet(merges.child) {
+				mergeableField.copy(mergedConfig, merges.child)
+			} else {
+				mergeableField.copy(mergedConfig, merges.included)
+			}
+		case ShallowMerge, DeepMerge:
+			target, source := newMergeConfig(), newMergeConfig()
+			mergeableField.copy(target, merges.included)
+			mergeableField.copy(source, merges.child)
+
+			var err error
+			if strategy == ShallowMerge {
+				err = target.Merge(source, terragruntOptions)
+			} else {
+				err = target.DeepMerge(source, terragruntOptions)
+			}
+			if err != nil {
+				return err
+			}
+			mergeableField.copy(mergedConfig, target)
+		}
+	}
+	return nil
+}
+
+// dependencyMergeStrategy returns the merge strategy of the dependency blocks: their own merge strategy, if any, or
+// the given merge strategy of the include.
+func (cfg *IncludeConfig) dependencyMergeStrategy(mergeStrategy MergeStrategyType) (MergeStrategyType, error) {
+	strategies, err := cfg.GetMergeStrategies()
+	if err != nil {
+		return NoMerge, err
+	}
+	if strategy, ok := strategies[MetadataDependency]; ok {
+		return strategy, nil
+	}
+	return mergeStrategy, nil
+}
+
+// newMergeConfig returns an empty config to merge fields in.
+func newMergeConfig() *TerragruntConfig {
+	return &TerragruntConfig{GenerateConfigs: map[string]codegen.GenerateConfig{}}
+}
+
+// setTerraformBlocks sets blocks of the terraform block of the target config with the given func, creating the terraform
+// block if the source config has one.
+func setTerraformBlocks(target, source *TerragruntConfig, set func(terraform *TerraformConfig, source *TerraformConfig)) {
+	if target.Terraform == nil {
+		if source.Terraform == nil {
+			return
+		}
+		target.Terraform = &TerraformConfig{}
+	}
+	set(target.Terraform, source.Terraform)
+}
+
+func copyStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append([]string{}, values...)
+}
+
+// mergeableFieldNames returns the names of the blocks and attributes which can have their own merge strategy.
+func mergeableFieldNames() string {
+	names := make([]string, 0, len(mergeableFields))
+	for name := range mergeableFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Custom error types
+
+type InvalidMergeStrategiesField string
+
+func (err InvalidMergeStrategiesField) Error() string {
+	return fmt.Sprintf("The merge_strategies of the include can't set the merge strategy of %s. Valid blocks and attributes are: %s", string(err), mergeableFieldNames())
+}
+
+type InvalidFieldMergeStrategy struct {
+	Field    string
+	Strategy string
+}
+
+func (err InvalidFieldMergeStrategy) Error() string {
+	return fmt.Sprintf("Merge strategy %s of %s is unknown. Valid strategies are: %s, %s, %s, %s", err.Strategy, err.Field, NoMerge, ShallowMerge, DeepMerge, ReplaceMerge)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// IncludeMergeStep is a step of the merge of the included configs into a config: the config before the step, the
+// included config, and the config once the included config is merged in. The configs are encoded as cty values, as the
+// merge updates them.
+type IncludeMergeStep struct {
+	// The path of the config the included config is merged into.
+	ConfigPath      string
+	Include         IncludeConfig
+	MergeStrategy   MergeStrategyType
+	FieldStrategies map[string]MergeStrategyType
+	// True if the configs are only partially parsed, e.g. when the stack is built.
+	IsPartial bool
+
+	Before   cty.Value
+	Included cty.Value
+	After    cty.Value
+}
+
+// newIncludeMergeStep returns the step merging the given included config into the given config, before the merge.
+func newIncludeMergeStep(ctx *ParsingContext, includeConfig IncludeConfig, mergeStrategy MergeStrategyType, fieldStrategies map[string]MergeStrategyType, isPartial bool, config, includedConfig *TerragruntConfig) (*IncludeMergeStep, error) {
+	before, err := TerragruntConfigAsCty(config)
+	if err != nil {
+		return nil, err
+	}
+	included, err := TerragruntConfigAsCty(includedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IncludeMergeStep{
+		ConfigPath:      ctx.TerragruntOptions.TerragruntConfigPath,
+		Include:         includeConfig,
+		MergeStrategy:   mergeStrategy,
+		FieldStrategies: fieldStrategies,
+		IsPartial:       isPartial,
+		Before:          before,
+		Included:        included,
+	}, nil
+}
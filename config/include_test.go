@@ -1,8 +1,13 @@
 package config
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/remote"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -286,3 +291,182 @@ func TestDeepMergeConfigIntoIncludedConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeStrategiesOfInclude(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "root.hcl"), []byte(`
+inputs = {
+  region = "us-east-1"
+  tags   = { team = "platform" }
+}
+
+terraform {
+  source = "../modules//app"
+
+  before_hook "lint" {
+    commands = ["plan"]
+    execute  = ["tflint"]
+  }
+}
+
+retryable_errors = ["(?s).*throttled.*"]
+`), 0644))
+
+	childPath := filepath.Join(dir, "app", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(childPath), 0755))
+
+	parseChild := func(t *testing.T, config string) (*TerragruntConfig, error) {
+		t.Helper()
+
+		require.NoError(t, os.WriteFile(childPath, []byte(config), 0644))
+		opts, err := options.NewTerragruntOptionsForTest(childPath)
+		require.NoError(t, err)
+		return ParseConfigFile(opts, NewParsingContext(context.Background(), opts), childPath, nil)
+	}
+
+	child := `
+include "root" {
+  path = "../root.hcl"
+  merge_strategies = {
+    inputs           = "deep"
+    before_hook      = "replace"
+    retryable_errors = "no_merge"
+  }
+}
+
+inputs = {
+  tags = { env = "dev" }
+}
+
+terraform {
+  before_hook "fmt" {
+    commands = ["plan"]
+    execute  = ["terraform", "fmt", "-check"]
+  }
+}
+`
+	cfg, err := parseChild(t, child)
+	require.NoError(t, err)
+
+	// The inputs are deep merged, the hooks are replaced and the retryable errors are not merged, while the rest is shallow
+	// merged
+	assert.Equal(t, map[string]interface{}{
+		"region": "us-east-1",
+		"tags":   map[string]interface{}{"team": "platform", "env": "dev"},
+	}, cfg.Inputs)
+	require.NotNil(t, cfg.Terraform)
+	assert.Equal(t, "../modules//app", *cfg.Terraform.Source)
+	require.Len(t, cfg.Terraform.BeforeHooks, 1)
+	assert.Equal(t, "fmt", cfg.Terraform.BeforeHooks[0].Name)
+	assert.Nil(t, cfg.RetryableErrors)
+
+	// Replacing keeps the included blocks the child does not set
+	cfg, err = parseChild(t, `
+include "root" {
+  path             = "../root.hcl"
+  merge_strategy   = "no_merge"
+  merge_strategies = { before_hook = "replace", inputs = "shallow" }
+}
+
+inputs = {
+  tags = { env = "dev" }
+}
+`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"region": "us-east-1",
+		"tags":   map[string]interface{}{"env": "dev"},
+	}, cfg.Inputs)
+	require.NotNil(t, cfg.Terraform)
+	assert.Nil(t, cfg.Terraform.Source)
+	require.Len(t, cfg.Terraform.BeforeHooks, 1)
+	assert.Equal(t, "lint", cfg.Terraform.BeforeHooks[0].Name)
+	assert.Nil(t, cfg.RetryableErrors)
+
+	_, err = parseChild(t, `
+include "root" {
+  path             = "../root.hcl"
+  merge_strategies = { locals = "deep" }
+}
+`)
+	var invalidField InvalidMergeStrategiesField
+	require.ErrorAs(t, errors.Unwrap(err), &invalidField)
+
+	_, err = parseChild(t, `
+include "root" {
+  path             = "../root.hcl"
+  merge_strategies = { inputs = "deep_map_only" }
+}
+`)
+	var invalidStrategy InvalidFieldMergeStrategy
+	require.ErrorAs(t, errors.Unwrap(err), &invalidStrategy)
+}
+
+func TestExposedIncludeLocals(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "root.hcl"), []byte(`
+locals {
+  region      = "us-east-1"
+  name_prefix = "platform-${local.region}"
+}
+
+dependency "vpc" {
+  config_path  = "${get_terragrunt_dir()}/../vpc"
+  skip_outputs = true
+  mock_outputs = { vpc_id = "mock" }
+}
+
+inputs = {
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`), 0644))
+
+	for _, dependencyDir := range []string{"vpc", filepath.Join("us-east-1", "db")} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, dependencyDir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, dependencyDir, DefaultTerragruntConfigPath), []byte(""), 0644))
+	}
+
+	childPath := filepath.Join(dir, "app", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(childPath), 0755))
+	require.NoError(t, os.WriteFile(childPath, []byte(`
+include "root" {
+  path   = "../root.hcl"
+  expose = true
+}
+
+locals {
+  name = "${include.root.locals.name_prefix}-app"
+}
+
+dependency "db" {
+  config_path  = "../${include.root.locals.region}/db"
+  skip_outputs = true
+}
+
+inputs = {
+  name   = local.name
+  region = include.root.locals.region
+}
+`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(childPath)
+	require.NoError(t, err)
+
+	// The locals of the included config are exposed while building the dependency graph too, even though the included
+	// config has a dependency block
+	ctx := NewParsingContext(context.Background(), opts).WithDecodeList(DependencyBlock)
+	cfg, err := PartialParseConfigFile(ctx, childPath, nil)
+	require.NoError(t, err)
+	require.Len(t, cfg.TerragruntDependencies, 2)
+	assert.Equal(t, "../us-east-1/db", findDependencyBlock(cfg.TerragruntDependencies, "db").ConfigPath)
+
+	cfg, err = ParseConfigFile(opts, NewParsingContext(context.Background(), opts), childPath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "platform-us-east-1-app", cfg.Inputs["name"])
+	assert.Equal(t, "us-east-1", cfg.Inputs["region"])
+	assert.Equal(t, "mock", cfg.Inputs["vpc_id"])
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agext/levenshtein"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// The most outputs suggested for a missing output.
+const maxOutputSuggestions = 3
+
+// describeMissingDependencyOutputs adds to the errors of the references to missing outputs of the dependencies, e.g.
+// `dependency.vpc.outputs.vpc_idd`, the outputs available on the dependency, the outputs with a close name and whether
+// the outputs are read from the state of the dependency or are its mock_outputs, e.g. as it has not been applied yet.
+func describeMissingDependencyOutputs(ctx *ParsingContext, diags hcl.Diagnostics) hcl.Diagnostics {
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError || diag.Expression == nil || diag.EvalContext == nil {
+			continue
+		}
+
+		traversal, traversalDiags := hcl.AbsTraversalForExpr(diag.Expression)
+		if traversalDiags.HasErrors() || len(traversal) < 3 || traversal.RootName() != MetadataDependency {
+			continue
+		}
+
+		if detail := missingDependencyOutputDetail(ctx, diag.EvalContext, traversal); detail != "" {
+			diag.Detail = strings.TrimSpace(diag.Detail + " " + detail)
+		}
+	}
+	return diags
+}
+
+// missingDependencyOutputDetail returns the description of the output of the dependency the given traversal references,
+// or an empty string if the traversal does not reference a missing output.
+func missingDependencyOutputDetail(ctx *ParsingContext, evalContext *hcl.EvalContext, traversal hcl.Traversal) string {
+	name, ok := traversalStepName(traversal[1])
+	if !ok {
+		return ""
+	}
+	if step, ok := traversalStepName(traversal[2]); !ok || step != "outputs" {
+		return ""
+	}
+
+	dependencies, ok := evalContextVariable(evalContext, MetadataDependency)
+	if !ok || !dependencies.Type().IsObjectType() || !dependencies.Type().HasAttribute(name) {
+		return ""
+	}
+	dependency := dependencies.GetAttr(name)
+
+	dependencyBlock := findDependencyBlock(ctx.DecodedDependencyBlocks, name)
+
+	if !dependency.Type().IsObjectType() || !dependency.Type().HasAttribute("outputs") {
+		if dependencyBlock == nil {
+			return ""
+		}
+		if dependencyBlock.isDisabled() {
+			return fmt.Sprintf("The dependency %q is disabled and has no mock_outputs, so it has no outputs.", name)
+		}
+		return fmt.Sprintf("The dependency %q sets skip_outputs and has no mock_outputs for the command %s, so it has no outputs.", name, ctx.TerragruntOptions.OriginalTerraformCommand)
+	}
+
+	if len(traversal) < 4 {
+		return ""
+	}
+	outputName, ok := traversalStepName(traversal[3])
+	if !ok {
+		return ""
+	}
+
+	outputs := dependency.GetAttr("outputs")
+	if outputs.IsNull() || !outputs.IsKnown() || !(outputs.Type().IsObjectType() || outputs.Type().IsMapType()) {
+		return ""
+	}
+	var available []string
+	for key := range outputs.AsValueMap() {
+		available = append(available, key)
+	}
+	sort.Strings(available)
+	// The output exists, the error is in the rest of the traversal
+	if outputs.Type().IsObjectType() && outputs.Type().HasAttribute(outputName) || outputs.Type().IsMapType() && outputs.HasIndex(cty.StringVal(outputName)).True() {
+		return ""
+	}
+
+	details := []string{fmt.Sprintf("The dependency %q has no output named %q.", name, outputName)}
+	if suggestions := closeOutputNames(outputName, available); len(suggestions) > 0 {
+		details = append(details, fmt.Sprintf("Did you mean %s?", quoteAndJoin(suggestions, " or ")))
+	}
+	if len(available) > 0 {
+		details = append(details, fmt.Sprintf("The available outputs are %s.", quoteAndJoin(available, ", ")))
+	} else {
+		details = append(details, "The dependency has no outputs.")
+	}
+	if dependencyBlock != nil {
+		if status := dependencyOutputsStatus(ctx, dependencyBlock); status != "" {
+			details = append(details, status)
+		}
+	}
+	return strings.Join(details, " ")
+}
+
+// dependencyOutputsStatus describes where the outputs of the given dependency come from: the state of the dependency, or
+// its mock_outputs as it is disabled, sets skip_outputs or has not been applied yet.
+func dependencyOutputsStatus(ctx *ParsingContext, dependencyBlock *Dependency) string {
+	switch {
+	case dependencyBlock.isDisabled():
+		return "The dependency is disabled, so its outputs are its mock_outputs."
+	case !dependencyBlock.shouldGetOutputs():
+		return "The dependency sets skip_outputs, so its outputs are its mock_outputs."
+	case dependencyBlock.PublishedOutputs != nil:
+		return fmt.Sprintf("The outputs are read from the outputs the dependency publishes to the key %s. If the output was added to the published outputs since the dependency was applied, apply the dependency again.", dependencyBlock.PublishedOutputs.Key)
+	}
+
+	targetConfig := getCleanedTargetConfigPath(dependencyBlock.ConfigPath, ctx.TerragruntOptions.TerragruntConfigPath)
+	rawJsonBytes, hasRun := jsonOutputCache.Load(targetConfig)
+	if !hasRun {
+		return ""
+	}
+
+	var stateOutputs map[string]interface{}
+	if err := json.Unmarshal(rawJsonBytes.([]byte), &stateOutputs); err != nil {
+		return ""
+	}
+	if len(stateOutputs) == 0 {
+		return fmt.Sprintf("The dependency %s has not been applied yet, so its outputs are its mock_outputs. Apply the dependency, or add the output to its mock_outputs.", targetConfig)
+	}
+	return fmt.Sprintf("The outputs are read from the state of the dependency %s. If the output was added to its module since it was applied, apply the dependency again.", targetConfig)
+}
+
+// closeOutputNames returns the names of the available outputs close to the given name, the closest first.
+func closeOutputNames(name string, available []string) []string {
+	maxDistance := len(name) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	distances := map[string]int{}
+	var matches []string
+	for _, candidate := range available {
+		distance := levenshtein.Distance(strings.ToLower(name), strings.ToLower(candidate), nil)
+		if distance <= maxDistance || strings.Contains(strings.ToLower(candidate), strings.ToLower(name)) {
+			distances[candidate] = distance
+			matches = append(matches, candidate)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return distances[matches[i]] < distances[matches[j]]
+	})
+	if len(matches) > maxOutputSuggestions {
+		matches = matches[:maxOutputSuggestions]
+	}
+	return matches
+}
+
+func findDependencyBlock(dependencyBlocks []Dependency, name string) *Dependency {
+	for i := range dependencyBlocks {
+		if dependencyBlocks[i].Name == name {
+			return &dependencyBlocks[i]
+		}
+	}
+	return nil
+}
+
+// traversalStepName returns the name of the attribute, or the string key, the given traversal step accesses.
+func traversalStepName(step hcl.Traverser) (string, bool) {
+	switch step := step.(type) {
+	case hcl.TraverseRoot:
+		return step.Name, true
+	case hcl.TraverseAttr:
+		return step.Name, true
+	case hcl.TraverseIndex:
+		if step.Key.IsKnown() && !step.Key.IsNull() && step.Key.Type() == cty.String {
+			return step.Key.AsString(), true
+		}
+	}
+	return "", false
+}
+
+// evalContextVariable returns the variable with the given name of the given eval context or of its parents.
+func evalContextVariable(evalContext *hcl.EvalContext, name string) (cty.Value, bool) {
+	for ; evalContext != nil; evalContext = evalContext.Parent() {
+		if value, ok := evalContext.Variables[name]; ok {
+			return value, true
+		}
+	}
+	return cty.NilVal, false
+}
+
+func quoteAndJoin(values []string, separator string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, fmt.Sprintf("%q", value))
+	}
+	return strings.Join(quoted, separator)
+}
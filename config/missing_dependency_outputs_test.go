@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeMissingDependencyOutputs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, module := range []string{"vpc", "db", "app"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, module), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, module, DefaultTerragruntConfigPath), []byte(""), 0644))
+	}
+	jsonOutputCache.Store(filepath.Join(dir, "vpc", DefaultTerragruntConfigPath), []byte(`{
+  "vpc_id": {"sensitive": false, "type": "string", "value": "vpc-123"},
+  "private_subnet_ids": {"sensitive": false, "type": ["list", "string"], "value": ["subnet-1"]}
+}`))
+	jsonOutputCache.Store(filepath.Join(dir, "db", DefaultTerragruntConfigPath), []byte(`{}`))
+
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			`dependency.vpc.outputs.vpc_idd`,
+			[]string{
+				`The dependency "vpc" has no output named "vpc_idd".`,
+				`Did you mean "vpc_id"?`,
+				`The available outputs are "private_subnet_ids", "vpc_id".`,
+				"The outputs are read from the state of the dependency " + filepath.Join(dir, "vpc", DefaultTerragruntConfigPath),
+			},
+		},
+		{
+			`dependency.db.outputs["endpont"]`,
+			[]string{
+				`The dependency "db" has no output named "endpont".`,
+				`Did you mean "endpoint"?`,
+				"The dependency " + filepath.Join(dir, "db", DefaultTerragruntConfigPath) + " has not been applied yet, so its outputs are its mock_outputs.",
+			},
+		},
+		{
+			`dependency.skipped.outputs.id`,
+			[]string{`The dependency "skipped" sets skip_outputs and has no mock_outputs`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.input, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := filepath.Join(dir, "app", DefaultTerragruntConfigPath)
+			opts, err := options.NewTerragruntOptionsForTest(configPath)
+			require.NoError(t, err)
+
+			config := `
+dependency "vpc" {
+  config_path = "../vpc"
+}
+
+dependency "db" {
+  config_path  = "../db"
+  mock_outputs = {
+    endpoint = "db.internal"
+    port     = 5432
+  }
+}
+
+dependency "skipped" {
+  config_path  = "../vpc"
+  skip_outputs = true
+}
+
+inputs = {
+  value = ` + testCase.input + `
+}
+`
+			ctx := NewParsingContext(context.Background(), opts)
+			_, err = ParseConfigString(ctx, configPath, config, nil)
+			require.Error(t, err)
+			for _, expected := range testCase.expected {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestCloseOutputNames(t *testing.T) {
+	t.Parallel()
+
+	available := []string{"private_subnet_ids", "public_subnet_ids", "vpc_cidr", "vpc_id"}
+	assert.Equal(t, []string{"vpc_id", "vpc_cidr"}, closeOutputNames("vpc_idd", available))
+	assert.Equal(t, []string{"public_subnet_ids"}, closeOutputNames("public_subnets_ids", available))
+	assert.Equal(t, []string{"public_subnet_ids", "private_subnet_ids"}, closeOutputNames("subnet", available))
+	assert.Empty(t, closeOutputNames("database_url", available))
+}
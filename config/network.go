@@ -0,0 +1,147 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-cleanhttp"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// NetworkConfig is the `network` block, configuring the proxies, registry mirrors and CA bundle that both the downloads
+// of Terragrunt and the terraform commands use.
+type NetworkConfig struct {
+	HTTPProxy  *string  `hcl:"http_proxy,optional" cty:"http_proxy"`
+	HTTPSProxy *string  `hcl:"https_proxy,optional" cty:"https_proxy"`
+	NoProxy    []string `hcl:"no_proxy,optional" cty:"no_proxy"`
+	// The PEM file of the CAs to trust, in addition to the CAs of the system.
+	CABundle *string `hcl:"ca_bundle,optional" cty:"ca_bundle"`
+	// The URL of the network mirror terraform installs all the providers from.
+	ProviderMirror *string `hcl:"provider_mirror,optional" cty:"provider_mirror"`
+	// The base URLs of the module registry APIs mirroring the module registries, by registry host.
+	ModuleMirrors map[string]string `hcl:"module_mirrors,optional" cty:"module_mirrors"`
+}
+
+func (conf *NetworkConfig) String() string {
+	return fmt.Sprintf("Network{HTTPProxy = %v, HTTPSProxy = %v, ProviderMirror = %v}", conf.HTTPProxy, conf.HTTPSProxy, conf.ProviderMirror)
+}
+
+// Validate checks that the proxies and mirrors are URLs and that the CA bundle can be loaded.
+func (conf *NetworkConfig) Validate() error {
+	urls := map[string]*string{"http_proxy": conf.HTTPProxy, "https_proxy": conf.HTTPSProxy, "provider_mirror": conf.ProviderMirror}
+	for host, mirror := range conf.ModuleMirrors {
+		mirror := mirror
+		urls[fmt.Sprintf("module_mirrors[%q]", host)] = &mirror
+	}
+	for name, value := range urls {
+		if value == nil {
+			continue
+		}
+		if parsedURL, err := url.Parse(*value); err != nil || parsedURL.Host == "" {
+			return errors.WithStackTrace(InvalidNetworkURL{Name: name, Value: *value})
+		}
+	}
+
+	if conf.CABundle != nil {
+		if _, err := conf.certPool(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Env returns the env vars that make terraform, and the tools it runs, use the proxies and the CA bundle.
+func (conf *NetworkConfig) Env() map[string]string {
+	env := map[string]string{}
+	if conf.HTTPProxy != nil {
+		env["HTTP_PROXY"] = *conf.HTTPProxy
+		env["http_proxy"] = *conf.HTTPProxy
+	}
+	if conf.HTTPSProxy != nil {
+		env["HTTPS_PROXY"] = *conf.HTTPSProxy
+		env["https_proxy"] = *conf.HTTPSProxy
+	}
+	if conf.NoProxy != nil {
+		env["NO_PROXY"] = strings.Join(conf.NoProxy, ",")
+		env["no_proxy"] = strings.Join(conf.NoProxy, ",")
+	}
+	if conf.CABundle != nil {
+		env["SSL_CERT_FILE"] = *conf.CABundle
+		env["GIT_SSL_CAINFO"] = *conf.CABundle
+		env["AWS_CA_BUNDLE"] = *conf.CABundle
+	}
+	return env
+}
+
+// HTTPClient returns the client the downloads of Terragrunt use, going through the proxies and trusting the CA bundle.
+// The proxies that are not set are taken from the env vars, as by the default client.
+func (conf *NetworkConfig) HTTPClient() (*http.Client, error) {
+	proxyConfig := httpproxy.FromEnvironment()
+	if conf.HTTPProxy != nil {
+		proxyConfig.HTTPProxy = *conf.HTTPProxy
+	}
+	if conf.HTTPSProxy != nil {
+		proxyConfig.HTTPSProxy = *conf.HTTPSProxy
+	}
+	if conf.NoProxy != nil {
+		proxyConfig.NoProxy = strings.Join(conf.NoProxy, ",")
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+
+	if conf.CABundle != nil {
+		pool, err := conf.certPool()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+func (conf *NetworkConfig) certPool() (*x509.CertPool, error) {
+	pem, err := os.ReadFile(*conf.CABundle)
+	if err != nil {
+		return nil, errors.WithStackTrace(InvalidNetworkCABundle{Path: *conf.CABundle, Reason: err.Error()})
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.WithStackTrace(InvalidNetworkCABundle{Path: *conf.CABundle, Reason: "no PEM certificates found"})
+	}
+	return pool, nil
+}
+
+// Custom error types
+
+type InvalidNetworkURL struct {
+	Name  string
+	Value string
+}
+
+func (err InvalidNetworkURL) Error() string {
+	return fmt.Sprintf("Invalid %s %q of the network block, must be a URL such as \"http://proxy.example.com:3128\"", err.Name, err.Value)
+}
+
+type InvalidNetworkCABundle struct {
+	Path   string
+	Reason string
+}
+
+func (err InvalidNetworkCABundle) Error() string {
+	return fmt.Sprintf("Failed to load the ca_bundle %s of the network block: %s", err.Path, err.Reason)
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkConfigHTTPClientUsesProxy(t *testing.T) {
+	t.Parallel()
+
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		_, _ = w.Write([]byte("proxied"))
+	}))
+	defer proxy.Close()
+
+	network := &NetworkConfig{HTTPProxy: &proxy.URL, NoProxy: []string{"internal.example.com"}}
+	require.NoError(t, network.Validate())
+
+	client, err := network.HTTPClient()
+	require.NoError(t, err)
+
+	resp, err := client.Get("http://modules.example.com/vpc.zip")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, "proxied", string(body))
+	assert.Equal(t, "http://modules.example.com/vpc.zip", proxiedURL)
+
+	assert.Equal(t, map[string]string{
+		"HTTP_PROXY": proxy.URL,
+		"http_proxy": proxy.URL,
+		"NO_PROXY":   "internal.example.com",
+		"no_proxy":   "internal.example.com",
+	}, network.Env())
+}
+
+func TestNetworkConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	invalidProxy := "proxy.example.com"
+	err := (&NetworkConfig{HTTPSProxy: &invalidProxy}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "https_proxy")
+
+	err = (&NetworkConfig{ModuleMirrors: map[string]string{"registry.terraform.io": "/modules/v1/"}}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "registry.terraform.io")
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caBundle, []byte("not a certificate"), 0644))
+	err = (&NetworkConfig{CABundle: &caBundle}).Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no PEM certificates found")
+}
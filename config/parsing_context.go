@@ -28,6 +28,10 @@ type ParsingContext struct {
 	// - outputs: The map of outputs from the terraform state obtained by running `terragrunt output` on that target config.
 	DecodedDependencies *cty.Value
 
+	// DecodedDependencyBlocks are the dependency blocks the DecodedDependencies are retrieved from, used to describe the
+	// references to the outputs missing in DecodedDependencies.
+	DecodedDependencyBlocks []Dependency
+
 	// PartialParseDecodeList is the list of sections that are being decoded in the current config. This can be used to
 	// indicate/detect that the current parsing ctx is partial, meaning that not all configuration values are
 	// expected to be available.
@@ -42,6 +46,14 @@ type ParsingContext struct {
 	// Set a custom converter to TerragruntConfig.
 	// Used to read a "catalog" configuration where only certain blocks (`catalog`, `locals`) do not need to be converted, avoiding errors if any of the remaining blocks were not evaluated correctly.
 	ConvertToTerragruntConfigFunc func(ctx *ParsingContext, configPath string, terragruntConfigFromFile *terragruntConfigFile) (cfg *TerragruntConfig, err error)
+
+	// DataOnly is true when the config is read with the data_only option of read_terragrunt_config: only the locals and
+	// the inputs of the config and of its includes are parsed, without retrieving the outputs of the dependencies.
+	DataOnly bool
+
+	// Set a func called on every merge of an included config, to trace how the includes transform the config.
+	// Used by the `debug merge` command.
+	TraceIncludeMergeFunc func(step IncludeMergeStep)
 }
 
 func NewParsingContext(ctx context.Context, opts *options.TerragruntOptions) *ParsingContext {
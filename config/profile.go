@@ -0,0 +1,245 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+var (
+	profileRoleARNPattern = regexp.MustCompile(`^arn:[a-z-]+:iam::[0-9]{12}:role/.+$`)
+	profileUUIDPattern    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// ProfileConfig is a `profile` block, a named bundle of the credentials of the clouds the modules of an environment
+// deploy to, which the modules select with the use_profile attribute. The profiles are usually defined in the root
+// config that the modules include.
+type ProfileConfig struct {
+	Name  string              `hcl:"name,label" cty:"name"`
+	AWS   *AWSProfileConfig   `hcl:"aws,block" cty:"aws"`
+	GCP   *GCPProfileConfig   `hcl:"gcp,block" cty:"gcp"`
+	Azure *AzureProfileConfig `hcl:"azure,block" cty:"azure"`
+}
+
+// AWSProfileConfig is the `aws` block of a profile.
+type AWSProfileConfig struct {
+	// The named profile of the AWS shared config files the credentials are read from.
+	Profile *string `hcl:"profile,optional" cty:"profile"`
+	// The IAM role assumed, as by iam_role, when the module does not set its own.
+	RoleARN *string `hcl:"role_arn,optional" cty:"role_arn"`
+	Region  *string `hcl:"region,optional" cty:"region"`
+}
+
+// GCPProfileConfig is the `gcp` block of a profile.
+type GCPProfileConfig struct {
+	Project                   *string `hcl:"project,optional" cty:"project"`
+	ImpersonateServiceAccount *string `hcl:"impersonate_service_account,optional" cty:"impersonate_service_account"`
+	// The path of the key file of the service account the credentials are read from.
+	Credentials *string `hcl:"credentials,optional" cty:"credentials"`
+	Region      *string `hcl:"region,optional" cty:"region"`
+}
+
+// AzureProfileConfig is the `azure` block of a profile.
+type AzureProfileConfig struct {
+	SubscriptionID *string `hcl:"subscription_id,optional" cty:"subscription_id"`
+	TenantID       *string `hcl:"tenant_id,optional" cty:"tenant_id"`
+	ClientID       *string `hcl:"client_id,optional" cty:"client_id"`
+}
+
+func (conf *ProfileConfig) String() string {
+	return fmt.Sprintf("Profile{Name = %v, AWS = %v, GCP = %v, Azure = %v}", conf.Name, conf.AWS != nil, conf.GCP != nil, conf.Azure != nil)
+}
+
+// Validate checks that the profile has the credentials of at least one cloud and that its role, service account and
+// Azure IDs are well-formed.
+func (conf *ProfileConfig) Validate() error {
+	if conf.AWS == nil && conf.GCP == nil && conf.Azure == nil {
+		return errors.WithStackTrace(EmptyProfile(conf.Name))
+	}
+
+	type check struct {
+		attribute string
+		value     *string
+		valid     func(string) bool
+		expected  string
+	}
+	var checks []check
+	if conf.AWS != nil {
+		checks = append(checks, check{"aws.role_arn", conf.AWS.RoleARN, profileRoleARNPattern.MatchString, "an IAM role ARN such as \"arn:aws:iam::123456789012:role/terraform\""})
+	}
+	if conf.GCP != nil {
+		isServiceAccount := func(value string) bool { return strings.Contains(value, "@") }
+		checks = append(checks, check{"gcp.impersonate_service_account", conf.GCP.ImpersonateServiceAccount, isServiceAccount, "the email of a service account"})
+	}
+	if conf.Azure != nil {
+		for attribute, value := range map[string]*string{"azure.subscription_id": conf.Azure.SubscriptionID, "azure.tenant_id": conf.Azure.TenantID, "azure.client_id": conf.Azure.ClientID} {
+			checks = append(checks, check{attribute, value, profileUUIDPattern.MatchString, "a UUID"})
+		}
+	}
+
+	sort.Slice(checks, func(i, j int) bool { return checks[i].attribute < checks[j].attribute })
+	for _, check := range checks {
+		if check.value != nil && !check.valid(*check.value) {
+			return errors.WithStackTrace(InvalidProfileAttribute{Profile: conf.Name, Attribute: check.attribute, Value: *check.value, Expected: check.expected})
+		}
+	}
+	return nil
+}
+
+// Env returns the env vars that make terraform, the providers and the CLIs of the clouds use the credentials of the
+// profile.
+func (conf *ProfileConfig) Env() map[string]string {
+	env := map[string]string{}
+	setEnv := func(value *string, names ...string) {
+		if value == nil {
+			return
+		}
+		for _, name := range names {
+			env[name] = *value
+		}
+	}
+
+	if conf.AWS != nil {
+		setEnv(conf.AWS.Profile, "AWS_PROFILE")
+		setEnv(conf.AWS.Region, "AWS_REGION", "AWS_DEFAULT_REGION")
+	}
+	if conf.GCP != nil {
+		setEnv(conf.GCP.Project, "GOOGLE_PROJECT", "GOOGLE_CLOUD_PROJECT", "CLOUDSDK_CORE_PROJECT")
+		setEnv(conf.GCP.ImpersonateServiceAccount, "GOOGLE_IMPERSONATE_SERVICE_ACCOUNT", "CLOUDSDK_AUTH_IMPERSONATE_SERVICE_ACCOUNT")
+		setEnv(conf.GCP.Credentials, "GOOGLE_APPLICATION_CREDENTIALS", "CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE")
+		setEnv(conf.GCP.Region, "GOOGLE_REGION", "CLOUDSDK_COMPUTE_REGION")
+	}
+	if conf.Azure != nil {
+		setEnv(conf.Azure.SubscriptionID, "ARM_SUBSCRIPTION_ID", "AZURE_SUBSCRIPTION_ID")
+		setEnv(conf.Azure.TenantID, "ARM_TENANT_ID", "AZURE_TENANT_ID")
+		setEnv(conf.Azure.ClientID, "ARM_CLIENT_ID", "AZURE_CLIENT_ID")
+	}
+	return env
+}
+
+// OverriddenEnv returns the env vars that take precedence over the credentials of the profile, which must be unset for
+// the profile to apply.
+func (conf *ProfileConfig) OverriddenEnv() []string {
+	var names []string
+	if conf.AWS != nil && conf.AWS.Profile != nil {
+		// The static credentials of the env take precedence over AWS_PROFILE
+		names = append(names, "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_SECURITY_TOKEN")
+	}
+	if conf.GCP != nil && conf.GCP.Credentials != nil {
+		// The google provider reads GOOGLE_CREDENTIALS before GOOGLE_APPLICATION_CREDENTIALS
+		names = append(names, "GOOGLE_CREDENTIALS", "GOOGLE_CLOUD_KEYFILE_JSON", "GCLOUD_KEYFILE_JSON")
+	}
+	return names
+}
+
+// ActiveProfile returns the profile the config selects with use_profile, nil if it selects none, or an error if no
+// profile has the selected name.
+func (conf *TerragruntConfig) ActiveProfile() (*ProfileConfig, error) {
+	if conf.UseProfile == "" {
+		return nil, nil
+	}
+
+	if profile := conf.profile(conf.UseProfile); profile != nil {
+		return profile, nil
+	}
+
+	var names []string
+	for _, profile := range conf.Profiles {
+		names = append(names, profile.Name)
+	}
+	sort.Strings(names)
+	return nil, errors.WithStackTrace(UnknownProfile{Name: conf.UseProfile, Known: names})
+}
+
+func (conf *TerragruntConfig) profile(name string) *ProfileConfig {
+	for i := range conf.Profiles {
+		if conf.Profiles[i].Name == name {
+			return &conf.Profiles[i]
+		}
+	}
+	return nil
+}
+
+// profileRoleARN returns the IAM role of the aws block of the selected profile, empty if none.
+func (conf *TerragruntConfig) profileRoleARN() string {
+	profile := conf.profile(conf.UseProfile)
+	if profile == nil || profile.AWS == nil || profile.AWS.RoleARN == nil {
+		return ""
+	}
+	return *profile.AWS.RoleARN
+}
+
+// validateProfiles validates the given profile blocks, whose names must be unique.
+func validateProfiles(blocks []ProfileConfig) error {
+	names := map[string]bool{}
+	for i := range blocks {
+		if names[blocks[i].Name] {
+			return errors.WithStackTrace(DuplicateProfile(blocks[i].Name))
+		}
+		names[blocks[i].Name] = true
+
+		if err := blocks[i].Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeProfiles merges the profile blocks of the child into the blocks of the parent by name, the block of the child
+// overriding the block of the parent with the same name.
+func mergeProfiles(childBlocks []ProfileConfig, parentBlocks []ProfileConfig) []ProfileConfig {
+	result := append([]ProfileConfig(nil), parentBlocks...)
+	for _, child := range childBlocks {
+		found := false
+		for i := range result {
+			if result[i].Name == child.Name {
+				result[i] = child
+				found = true
+			}
+		}
+		if !found {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// Custom error types
+
+type EmptyProfile string
+
+func (name EmptyProfile) Error() string {
+	return fmt.Sprintf("The profile %q has none of the aws, gcp and azure blocks", string(name))
+}
+
+type InvalidProfileAttribute struct {
+	Profile   string
+	Attribute string
+	Value     string
+	Expected  string
+}
+
+func (err InvalidProfileAttribute) Error() string {
+	return fmt.Sprintf("Invalid %s %q of the profile %q, must be %s", err.Attribute, err.Value, err.Profile, err.Expected)
+}
+
+type DuplicateProfile string
+
+func (name DuplicateProfile) Error() string {
+	return fmt.Sprintf("Multiple profile blocks are named %q, the names must be unique", string(name))
+}
+
+type UnknownProfile struct {
+	Name  string
+	Known []string
+}
+
+func (err UnknownProfile) Error() string {
+	if len(err.Known) == 0 {
+		return fmt.Sprintf("The use_profile %q does not match any profile, no profile block is defined in the config or its includes", err.Name)
+	}
+	return fmt.Sprintf("The use_profile %q does not match any profile, expected one of %s", err.Name, strings.Join(err.Known, ", "))
+}
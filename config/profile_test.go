@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerragruntConfigProfileFromInclude(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	rootConfig := `
+profile "prod" {
+  aws {
+    profile  = "acme-prod"
+    role_arn = "arn:aws:iam::123456789012:role/terraform"
+    region   = "eu-west-1"
+  }
+  gcp {
+    project = "acme-prod"
+  }
+}
+
+profile "dev" {
+  azure {
+    subscription_id = "00000000-0000-0000-0000-000000000001"
+  }
+}
+`
+	childConfig := `
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+use_profile = "prod"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "root.hcl"), []byte(rootConfig), 0644))
+	childDir := filepath.Join(rootDir, "vpc")
+	require.NoError(t, os.MkdirAll(childDir, os.ModePerm))
+	configPath := filepath.Join(childDir, DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(childConfig), 0644))
+
+	opts := mockOptionsForTestWithConfigPath(t, configPath)
+	terragruntConfig, err := ParseConfigFile(opts, NewParsingContext(context.Background(), opts), configPath, nil)
+	require.NoError(t, err)
+
+	profile, err := terragruntConfig.ActiveProfile()
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	assert.Equal(t, "prod", profile.Name)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/terraform", terragruntConfig.GetIAMRoleOptions().RoleARN)
+	assert.Equal(t, map[string]string{
+		"AWS_PROFILE":           "acme-prod",
+		"AWS_REGION":            "eu-west-1",
+		"AWS_DEFAULT_REGION":    "eu-west-1",
+		"GOOGLE_PROJECT":        "acme-prod",
+		"GOOGLE_CLOUD_PROJECT":  "acme-prod",
+		"CLOUDSDK_CORE_PROJECT": "acme-prod",
+	}, profile.Env())
+
+	// The iam_role of the module takes precedence over the role of the profile
+	terragruntConfig.IamRole = "arn:aws:iam::123456789012:role/admin"
+	assert.Equal(t, "arn:aws:iam::123456789012:role/admin", terragruntConfig.GetIAMRoleOptions().RoleARN)
+}
+
+func TestActiveProfileUnknown(t *testing.T) {
+	t.Parallel()
+
+	terragruntConfig := &TerragruntConfig{
+		Profiles:   []ProfileConfig{{Name: "prod"}, {Name: "dev"}},
+		UseProfile: "staging",
+	}
+	_, err := terragruntConfig.ActiveProfile()
+	assert.Equal(t, UnknownProfile{Name: "staging", Known: []string{"dev", "prod"}}, errors.Unwrap(err))
+
+	profile, err := (&TerragruntConfig{}).ActiveProfile()
+	require.NoError(t, err)
+	assert.Nil(t, profile)
+}
+
+func TestParseTerragruntConfigInvalidProfile(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		config   string
+		expected error
+	}{
+		{
+			config:   `profile "prod" {}`,
+			expected: EmptyProfile("prod"),
+		},
+		{
+			config: `
+profile "prod" {
+  aws {
+    role_arn = "terraform"
+  }
+}
+`,
+			expected: InvalidProfileAttribute{Profile: "prod", Attribute: "aws.role_arn", Value: "terraform", Expected: "an IAM role ARN such as \"arn:aws:iam::123456789012:role/terraform\""},
+		},
+		{
+			config: `
+profile "prod" {
+  azure {
+    tenant_id = "acme"
+  }
+}
+`,
+			expected: InvalidProfileAttribute{Profile: "prod", Attribute: "azure.tenant_id", Value: "acme", Expected: "a UUID"},
+		},
+		{
+			config: `
+profile "prod" {
+  gcp {
+    project = "acme-prod"
+  }
+}
+
+profile "prod" {
+  gcp {
+    project = "acme-prod-2"
+  }
+}
+`,
+			expected: DuplicateProfile("prod"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+		_, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, testCase.config, nil)
+		require.Error(t, err)
+		assert.Equal(t, testCase.expected, errors.Unwrap(err))
+	}
+}
+
+func TestMergeProfiles(t *testing.T) {
+	t.Parallel()
+
+	project := "acme-prod"
+	parent := []ProfileConfig{{Name: "prod", AWS: &AWSProfileConfig{}}, {Name: "dev", AWS: &AWSProfileConfig{}}}
+	child := []ProfileConfig{{Name: "prod", GCP: &GCPProfileConfig{Project: &project}}, {Name: "staging"}}
+
+	merged := mergeProfiles(child, parent)
+	assert.Equal(t, []ProfileConfig{
+		{Name: "prod", GCP: &GCPProfileConfig{Project: &project}},
+		{Name: "dev", AWS: &AWSProfileConfig{}},
+		{Name: "staging"},
+	}, merged)
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The file the provider matrix is generated into, in the JSON syntax, as it is the only syntax in which the arguments
+// of the provider blocks can be generated without knowing which of them are nested blocks.
+const DefaultProviderMatrixPath = "terragrunt_providers.tf.json"
+
+// ProviderMatrixConfig is the `provider_matrix` block, generating the same `required_providers` and provider blocks
+// into every module, instead of every module requiring and configuring the providers on its own.
+type ProviderMatrixConfig struct {
+	// The path of the generated file, relative to the terragrunt working dir. Defaults to DefaultProviderMatrixPath.
+	Path      *string                  `hcl:"path,attr" cty:"path"`
+	Providers []ProviderMatrixProvider `hcl:"provider,block" cty:"provider"`
+}
+
+// ProviderMatrixProvider is a provider of the matrix, with its version constraint and the arguments of its provider
+// block, which can differ per environment, e.g. with locals read from an env config.
+type ProviderMatrixProvider struct {
+	// The local name of the provider, e.g. "aws".
+	Name    string  `hcl:",label" cty:"name"`
+	Source  string  `hcl:"source,attr" cty:"source"`
+	Version *string `hcl:"version,attr" cty:"version"`
+	// The arguments of the provider block. No provider block is generated if not set.
+	Config *cty.Value `hcl:"config,attr" cty:"config"`
+}
+
+func (conf *ProviderMatrixConfig) String() string {
+	return fmt.Sprintf("ProviderMatrix{Providers = %d}", len(conf.Providers))
+}
+
+// GeneratedPath returns the path of the generated file, relative to the terragrunt working dir.
+func (conf *ProviderMatrixConfig) GeneratedPath() string {
+	if conf.Path == nil || *conf.Path == "" {
+		return DefaultProviderMatrixPath
+	}
+	return *conf.Path
+}
+
+// Provider returns the provider of the matrix with the given local name, or nil if there is none.
+func (conf *ProviderMatrixConfig) Provider(name string) *ProviderMatrixProvider {
+	for i := range conf.Providers {
+		if conf.Providers[i].Name == name {
+			return &conf.Providers[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that every provider has a source and is declared once, and that the configs are objects.
+func (conf *ProviderMatrixConfig) Validate() error {
+	names := map[string]bool{}
+	for _, provider := range conf.Providers {
+		switch {
+		case names[provider.Name]:
+			return errors.WithStackTrace(InvalidProviderMatrixProvider{Name: provider.Name, Reason: "the provider is declared more than once"})
+		case provider.Source == "":
+			return errors.WithStackTrace(InvalidProviderMatrixProvider{Name: provider.Name, Reason: "the source must not be empty"})
+		case provider.Config != nil && !provider.Config.IsNull() && !provider.Config.Type().IsObjectType() && !provider.Config.Type().IsMapType():
+			return errors.WithStackTrace(InvalidProviderMatrixProvider{Name: provider.Name, Reason: "the config must be an object of the arguments of the provider block"})
+		}
+		names[provider.Name] = true
+	}
+	return nil
+}
+
+// TerraformJSON returns the terraform code, in the JSON syntax, requiring and configuring the providers of the matrix.
+func (conf *ProviderMatrixConfig) TerraformJSON() ([]byte, error) {
+	requiredProviders := map[string]interface{}{}
+	providerBlocks := map[string]json.RawMessage{}
+
+	for _, provider := range conf.Providers {
+		requirement := map[string]string{"source": provider.Source}
+		if provider.Version != nil && *provider.Version != "" {
+			requirement["version"] = *provider.Version
+		}
+		requiredProviders[provider.Name] = requirement
+
+		if provider.Config == nil || provider.Config.IsNull() {
+			continue
+		}
+		providerJSON, err := ctyjson.SimpleJSONValue{Value: *provider.Config}.MarshalJSON()
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		providerBlocks[provider.Name] = providerJSON
+	}
+
+	code := map[string]interface{}{
+		// The JSON syntax ignores the properties named "//", which are comments
+		"//":        codegen.TerragruntGeneratedSignature,
+		"terraform": map[string]interface{}{"required_providers": requiredProviders},
+	}
+	if len(providerBlocks) > 0 {
+		code["provider"] = providerBlocks
+	}
+
+	content, err := json.MarshalIndent(code, "", "  ")
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return append(content, '\n'), nil
+}
+
+// GenerateTerraformCode writes the terraform code of the provider matrix into the working dir. The file is overwritten
+// on every run if it was generated by terragrunt, and never if it was not.
+func (conf *ProviderMatrixConfig) GenerateTerraformCode(terragruntOptions *options.TerragruntOptions) error {
+	content, err := conf.TerraformJSON()
+	if err != nil {
+		return err
+	}
+
+	// The signature is in the content, as a JSON file has no comment lines, so the generated files are checked here
+	targetPath := util.JoinPath(terragruntOptions.WorkingDir, conf.GeneratedPath())
+	if util.FileExists(targetPath) {
+		existingContent, err := os.ReadFile(targetPath)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if !bytes.Contains(existingContent, []byte(codegen.TerragruntGeneratedSignature)) {
+			return errors.WithStackTrace(ProviderMatrixFileExists{Path: targetPath})
+		}
+	}
+
+	_, err = codegen.WriteToFile(terragruntOptions, terragruntOptions.WorkingDir, codegen.GenerateConfig{
+		Path:             conf.GeneratedPath(),
+		IfExists:         codegen.ExistsOverwrite,
+		IfExistsStr:      codegen.ExistsOverwriteStr,
+		Contents:         string(content),
+		DisableSignature: true,
+	})
+	return err
+}
+
+// Custom error types
+
+type InvalidProviderMatrixProvider struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidProviderMatrixProvider) Error() string {
+	return fmt.Sprintf("Invalid provider %q of the provider_matrix block: %s", err.Name, err.Reason)
+}
+
+type ProviderMatrixFileExists struct {
+	Path string
+}
+
+func (err ProviderMatrixFileExists) Error() string {
+	return fmt.Sprintf("Not generating the provider matrix in %s, as the file already exists and was not generated by terragrunt. Set the path of the provider_matrix block to another file.", err.Path)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderMatrixGenerateTerraformCode(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  env = "prod"
+}
+
+provider_matrix {
+  provider "aws" {
+    source  = "hashicorp/aws"
+    version = "~> 5.0"
+    config = {
+      region = local.env == "prod" ? "us-east-1" : "eu-west-1"
+      default_tags = {
+        tags = { env = local.env }
+      }
+    }
+  }
+
+  provider "random" {
+    source = "hashicorp/random"
+  }
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+	require.NotNil(t, terragruntConfig.ProviderMatrix)
+
+	code, err := terragruntConfig.ProviderMatrix.TerraformJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "//": "`+codegen.TerragruntGeneratedSignature+`",
+  "terraform": {
+    "required_providers": {
+      "aws": {"source": "hashicorp/aws", "version": "~> 5.0"},
+      "random": {"source": "hashicorp/random"}
+    }
+  },
+  "provider": {
+    "aws": {"region": "us-east-1", "default_tags": {"tags": {"env": "prod"}}}
+  }
+}`, string(code))
+
+	opts := mockOptionsForTest(t)
+	opts.WorkingDir = t.TempDir()
+	generatedPath := filepath.Join(opts.WorkingDir, DefaultProviderMatrixPath)
+
+	// The generated file is regenerated, a file not generated by terragrunt is never overwritten
+	require.NoError(t, terragruntConfig.ProviderMatrix.GenerateTerraformCode(opts))
+	require.NoError(t, terragruntConfig.ProviderMatrix.GenerateTerraformCode(opts))
+	generatedCode, err := os.ReadFile(generatedPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(code), string(generatedCode))
+
+	require.NoError(t, os.WriteFile(generatedPath, []byte("{}"), 0644))
+	err = terragruntConfig.ProviderMatrix.GenerateTerraformCode(opts)
+	var fileExists ProviderMatrixFileExists
+	require.ErrorAs(t, errors.Unwrap(err), &fileExists)
+}
+
+func TestProviderMatrixValidate(t *testing.T) {
+	t.Parallel()
+
+	config := `
+provider_matrix {
+  provider "aws" {
+    source = "hashicorp/aws"
+  }
+  provider "aws" {
+    source = "hashicorp/aws"
+  }
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	_, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	var invalidProvider InvalidProviderMatrixProvider
+	require.ErrorAs(t, errors.Unwrap(err), &invalidProvider)
+	assert.Equal(t, "aws", invalidProvider.Name)
+}
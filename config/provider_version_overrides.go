@@ -0,0 +1,244 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The file the provider version overrides are generated into. Terraform merges the files named with the _override
+// suffix into the rest of the module, replacing the requirements of the module for the same providers.
+const DefaultProviderVersionOverridesPath = "terragrunt_provider_versions_override.tf.json"
+
+// ProviderVersionOverridesConfig is the `provider_version_overrides` block, enforcing the version constraints of the
+// providers across all the modules of a stack, usually from a root config included by all the modules.
+type ProviderVersionOverridesConfig struct {
+	// The path of the generated file, relative to the terragrunt working dir. Defaults to
+	// DefaultProviderVersionOverridesPath. It must end with _override.tf.json.
+	Path      *string                   `hcl:"path,attr" cty:"path"`
+	Providers []ProviderVersionOverride `hcl:"provider,block" cty:"provider"`
+}
+
+// ProviderVersionOverride is the version constraint enforced for a provider.
+type ProviderVersionOverride struct {
+	// The local name of the provider, e.g. "aws".
+	Name    string `hcl:",label" cty:"name"`
+	Version string `hcl:"version,attr" cty:"version"`
+	// The source of the provider. Defaults to the source the module requires the provider from.
+	Source *string `hcl:"source,attr" cty:"source"`
+}
+
+// ProviderVersionConflict is a provider a module requires with other version constraints than the override.
+type ProviderVersionConflict struct {
+	Provider string
+	// The version constraints of the module, e.g. ">= 4.0, < 5.0".
+	Version  string
+	Override string
+}
+
+func (conflict ProviderVersionConflict) String() string {
+	return fmt.Sprintf("requires the version %q of the provider %s, overridden with %q", conflict.Version, conflict.Provider, conflict.Override)
+}
+
+func (conf *ProviderVersionOverridesConfig) String() string {
+	return fmt.Sprintf("ProviderVersionOverrides{Providers = %d}", len(conf.Providers))
+}
+
+// GeneratedPath returns the path of the generated file, relative to the terragrunt working dir.
+func (conf *ProviderVersionOverridesConfig) GeneratedPath() string {
+	if conf.Path == nil || *conf.Path == "" {
+		return DefaultProviderVersionOverridesPath
+	}
+	return *conf.Path
+}
+
+// Validate checks that every provider has a version constraint and is declared once, and that the generated file is an
+// override file.
+func (conf *ProviderVersionOverridesConfig) Validate() error {
+	if !strings.HasSuffix(conf.GeneratedPath(), "_override.tf.json") {
+		return errors.WithStackTrace(InvalidProviderVersionOverridesPath{Path: conf.GeneratedPath()})
+	}
+
+	names := map[string]bool{}
+	for _, provider := range conf.Providers {
+		switch {
+		case names[provider.Name]:
+			return errors.WithStackTrace(InvalidProviderVersionOverride{Name: provider.Name, Reason: "the provider is declared more than once"})
+		case provider.Version == "":
+			return errors.WithStackTrace(InvalidProviderVersionOverride{Name: provider.Name, Reason: "the version must not be empty"})
+		}
+		names[provider.Name] = true
+	}
+	return nil
+}
+
+// Conflicts returns the providers the given module requires with other version constraints than the overrides.
+func (conf *ProviderVersionOverridesConfig) Conflicts(module *tfconfig.Module) []ProviderVersionConflict {
+	var conflicts []ProviderVersionConflict
+	for _, provider := range conf.Providers {
+		requirement, ok := module.RequiredProviders[provider.Name]
+		if !ok {
+			continue
+		}
+		if version := strings.Join(requirement.VersionConstraints, ", "); version != "" && version != provider.Version {
+			conflicts = append(conflicts, ProviderVersionConflict{Provider: provider.Name, Version: version, Override: provider.Version})
+		}
+	}
+	return conflicts
+}
+
+// TerraformJSON returns the terraform code, in the JSON syntax, overriding the requirements of the given module for the
+// overridden providers, or nil if the module requires none of them.
+func (conf *ProviderVersionOverridesConfig) TerraformJSON(module *tfconfig.Module) ([]byte, error) {
+	requiredProviders := map[string]interface{}{}
+	for _, provider := range conf.Providers {
+		requirement, ok := module.RequiredProviders[provider.Name]
+		if !ok {
+			continue
+		}
+
+		// The overrides replace the whole requirement, so the source of the module is kept unless overridden
+		source := requirement.Source
+		if provider.Source != nil && *provider.Source != "" {
+			source = *provider.Source
+		}
+		if source == "" {
+			source = "hashicorp/" + provider.Name
+		}
+		requiredProviders[provider.Name] = map[string]string{"source": source, "version": provider.Version}
+	}
+	if len(requiredProviders) == 0 {
+		return nil, nil
+	}
+
+	content, err := json.MarshalIndent(map[string]interface{}{
+		// The JSON syntax ignores the properties named "//", which are comments
+		"//":        codegen.TerragruntGeneratedSignature,
+		"terraform": map[string]interface{}{"required_providers": requiredProviders},
+	}, "", "  ")
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return append(content, '\n'), nil
+}
+
+// GenerateTerraformCode writes the provider version overrides for the module in the working dir, and warns about the
+// providers whose version constraints in the module the overrides replace. The file is overwritten on every run if it
+// was generated by terragrunt, and never if it was not. It is removed if the module requires none of the providers.
+func (conf *ProviderVersionOverridesConfig) GenerateTerraformCode(terragruntOptions *options.TerragruntOptions) error {
+	targetPath := util.JoinPath(terragruntOptions.WorkingDir, conf.GeneratedPath())
+
+	module, err := LoadTerraformModule(terragruntOptions.WorkingDir, targetPath)
+	if err != nil {
+		return err
+	}
+
+	for _, conflict := range conf.Conflicts(module) {
+		terragruntOptions.Logger.Warnf("The module in %s %s", terragruntOptions.WorkingDir, conflict)
+	}
+
+	content, err := conf.TerraformJSON(module)
+	if err != nil {
+		return err
+	}
+
+	// The signature is in the content, as a JSON file has no comment lines, so the generated files are checked here
+	if util.FileExists(targetPath) {
+		existingContent, err := os.ReadFile(targetPath)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if !bytes.Contains(existingContent, []byte(codegen.TerragruntGeneratedSignature)) {
+			return errors.WithStackTrace(ProviderVersionOverridesFileExists{Path: targetPath})
+		}
+		if content == nil {
+			terragruntOptions.Logger.Debugf("Removing the provider version overrides %s, as the module requires none of the providers", targetPath)
+			return errors.WithStackTrace(os.Remove(targetPath))
+		}
+	}
+	if content == nil {
+		return nil
+	}
+
+	_, err = codegen.WriteToFile(terragruntOptions, terragruntOptions.WorkingDir, codegen.GenerateConfig{
+		Path:             conf.GeneratedPath(),
+		IfExists:         codegen.ExistsOverwrite,
+		IfExistsStr:      codegen.ExistsOverwriteStr,
+		Contents:         string(content),
+		DisableSignature: true,
+	})
+	return err
+}
+
+// LoadTerraformModule loads the terraform module in the given dir, without the given files, e.g. the files generated by
+// the prior runs, which are not part of the code of the module.
+func LoadTerraformModule(dir string, excludedPaths ...string) (*tfconfig.Module, error) {
+	excluded := make([]string, 0, len(excludedPaths))
+	for _, path := range excludedPaths {
+		excluded = append(excluded, filepath.Clean(path))
+	}
+
+	module, diags := tfconfig.LoadModuleFromFilesystem(&excludingFS{FS: tfconfig.NewOsFs(), excluded: excluded}, dir)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+	return module, nil
+}
+
+// excludingFS is the filesystem without the excluded files.
+type excludingFS struct {
+	tfconfig.FS
+	excluded []string
+}
+
+func (fs *excludingFS) ReadDir(dirname string) ([]os.FileInfo, error) {
+	infos, err := fs.FS.ReadDir(dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []os.FileInfo
+	for _, info := range infos {
+		if !util.ListContainsElement(fs.excluded, filepath.Clean(filepath.Join(dirname, info.Name()))) {
+			filtered = append(filtered, info)
+		}
+	}
+	return filtered, nil
+}
+
+// Custom error types
+
+type InvalidProviderVersionOverride struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidProviderVersionOverride) Error() string {
+	return fmt.Sprintf("Invalid provider %q of the provider_version_overrides block: %s", err.Name, err.Reason)
+}
+
+type InvalidProviderVersionOverridesPath struct {
+	Path string
+}
+
+func (err InvalidProviderVersionOverridesPath) Error() string {
+	return fmt.Sprintf("Invalid path %q of the provider_version_overrides block: terraform only merges the files named with the _override.tf.json suffix into the module.", err.Path)
+}
+
+type ProviderVersionOverridesFileExists struct {
+	Path string
+}
+
+func (err ProviderVersionOverridesFileExists) Error() string {
+	return fmt.Sprintf("Not generating the provider version overrides in %s, as the file already exists and was not generated by terragrunt. Set the path of the provider_version_overrides block to another file.", err.Path)
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderVersionOverridesGenerateTerraformCode(t *testing.T) {
+	t.Parallel()
+
+	config := `
+provider_version_overrides {
+  provider "aws" {
+    version = "~> 5.40"
+  }
+
+  provider "random" {
+    source  = "acme/random"
+    version = "3.6.0"
+  }
+
+  provider "google" {
+    version = "~> 5.0"
+  }
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+	overrides := terragruntConfig.ProviderVersionOverrides
+	require.NotNil(t, overrides)
+
+	opts := mockOptionsForTest(t)
+	opts.WorkingDir = t.TempDir()
+	generatedPath := filepath.Join(opts.WorkingDir, DefaultProviderVersionOverridesPath)
+	require.NoError(t, os.WriteFile(filepath.Join(opts.WorkingDir, "main.tf"), []byte(`
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = ">= 4.0"
+    }
+    random = {
+      source = "hashicorp/random"
+    }
+  }
+}
+`), 0644))
+
+	// The overrides are generated for the providers the module requires, and regenerated on every run
+	require.NoError(t, overrides.GenerateTerraformCode(opts))
+	require.NoError(t, overrides.GenerateTerraformCode(opts))
+	generatedCode, err := os.ReadFile(generatedPath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "//": "`+codegen.TerragruntGeneratedSignature+`",
+  "terraform": {
+    "required_providers": {
+      "aws": {"source": "hashicorp/aws", "version": "~> 5.40"},
+      "random": {"source": "acme/random", "version": "3.6.0"}
+    }
+  }
+}`, string(generatedCode))
+
+	module, err := LoadTerraformModule(opts.WorkingDir, generatedPath)
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderVersionConflict{{Provider: "aws", Version: ">= 4.0", Override: "~> 5.40"}}, overrides.Conflicts(module))
+
+	// The overrides are removed once the module requires none of the providers
+	require.NoError(t, os.WriteFile(filepath.Join(opts.WorkingDir, "main.tf"), []byte("output \"hello\" {\n  value = \"world\"\n}\n"), 0644))
+	require.NoError(t, overrides.GenerateTerraformCode(opts))
+	assert.NoFileExists(t, generatedPath)
+
+	// A file not generated by terragrunt is never overwritten
+	require.NoError(t, os.WriteFile(generatedPath, []byte("{}"), 0644))
+	err = overrides.GenerateTerraformCode(opts)
+	var fileExists ProviderVersionOverridesFileExists
+	require.ErrorAs(t, errors.Unwrap(err), &fileExists)
+}
+
+func TestProviderVersionOverridesValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		config      string
+		expectedErr error
+	}{
+		{
+			name: "missing version",
+			config: `
+provider_version_overrides {
+  provider "aws" {
+    version = ""
+  }
+}
+`,
+			expectedErr: InvalidProviderVersionOverride{Name: "aws", Reason: "the version must not be empty"},
+		},
+		{
+			name: "not an override file",
+			config: `
+provider_version_overrides {
+  path = "versions.tf.json"
+  provider "aws" {
+    version = "~> 5.40"
+  }
+}
+`,
+			expectedErr: InvalidProviderVersionOverridesPath{Path: "versions.tf.json"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+			_, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, testCase.config, nil)
+			require.Error(t, err)
+			assert.Equal(t, testCase.expectedErr, errors.Unwrap(err))
+		})
+	}
+}
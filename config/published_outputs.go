@@ -0,0 +1,164 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/outputstore"
+)
+
+// PublishedOutputsConfig is the `published_outputs` block of a dependency, reading the outputs of the dependency from
+// the parameter store an `export_outputs` block publishes them to, instead of from its state, so that the dependency
+// can be owned by another team, and its state be out of reach.
+type PublishedOutputsConfig struct {
+	Backend string `hcl:"backend,attr" cty:"backend"`
+	// The key of each output, with the `{output}` placeholder, or the key of all the outputs as a JSON object
+	Key     string   `hcl:"key,attr" cty:"key"`
+	Outputs []string `hcl:"outputs,optional" cty:"outputs"`
+	Region  *string  `hcl:"region,optional" cty:"region"`
+	Bucket  *string  `hcl:"bucket,optional" cty:"bucket"`
+}
+
+// StoreConfig returns the config of the store the outputs are read from.
+func (conf *PublishedOutputsConfig) StoreConfig() outputstore.Config {
+	storeConfig := outputstore.Config{Backend: conf.Backend}
+	if conf.Region != nil {
+		storeConfig.Region = *conf.Region
+	}
+	if conf.Bucket != nil {
+		storeConfig.Bucket = *conf.Bucket
+	}
+	return storeConfig
+}
+
+// cacheKey returns the key of the outputs the block reads in the cache of the published outputs.
+func (conf *PublishedOutputsConfig) cacheKey() string {
+	return fmt.Sprintf("%+v|%s|%s", conf.StoreConfig(), conf.Key, strings.Join(conf.Outputs, ","))
+}
+
+// Validate checks that the store of the block is supported, and that the names of the outputs are set when each output
+// is published to its own key.
+func (conf *PublishedOutputsConfig) Validate(dependencyName string) error {
+	if strings.Contains(conf.Key, outputstore.OutputPlaceholder) && conf.Outputs == nil {
+		return errors.WithStackTrace(InvalidPublishedOutputs{Dependency: dependencyName, Err: outputstore.MissingOutputNames(conf.Key)})
+	}
+	if err := conf.StoreConfig().Validate(); err != nil {
+		return errors.WithStackTrace(InvalidPublishedOutputs{Dependency: dependencyName, Err: err})
+	}
+	return nil
+}
+
+// validateDependencyBlocks checks that the enabled dependency blocks either have a config path or read the published
+// outputs, and that their published_outputs blocks are valid.
+func validateDependencyBlocks(dependencyConfigs []Dependency) error {
+	for _, dependencyConfig := range dependencyConfigs {
+		if dependencyConfig.PublishedOutputs != nil {
+			if err := dependencyConfig.PublishedOutputs.Validate(dependencyConfig.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if dependencyConfig.isEnabled() && dependencyConfig.ConfigPath == "" {
+			return errors.WithStackTrace(MissingDependencyConfigPath(dependencyConfig.Name))
+		}
+	}
+	return nil
+}
+
+// publishedOutputsCache maps the stores and keys of the published outputs to the outputs read, so that the outputs
+// read by multiple modules are read once.
+var publishedOutputsCache = sync.Map{}
+
+// getPublishedOutputs reads the outputs of the given dependency from the published outputs, returning true if they are
+// not published, e.g. as the dependency has not been applied yet.
+func getPublishedOutputs(ctx *ParsingContext, dependencyConfig Dependency) (*cty.Value, bool, error) {
+	published := dependencyConfig.PublishedOutputs
+	cacheKey := published.cacheKey()
+
+	rawValues, hasRun := publishedOutputsCache.Load(cacheKey)
+	if !hasRun {
+		store, err := outputstore.New(published.StoreConfig(), ctx.TerragruntOptions)
+		if err != nil {
+			return nil, true, err
+		}
+
+		ctx.TerragruntOptions.Logger.Debugf("Reading the published outputs of dependency %s from the key %s of %s", dependencyConfig.Name, published.Key, published.Backend)
+		values, err := outputstore.Read(ctx, store, published.Key, published.Outputs)
+		if _, ok := errors.Unwrap(err).(outputstore.KeyNotFound); ok {
+			ctx.TerragruntOptions.Logger.Debugf("The outputs of dependency %s are not published: %v", dependencyConfig.Name, err)
+			return nil, true, nil
+		}
+		if err != nil {
+			return nil, true, err
+		}
+		rawValues, _ = publishedOutputsCache.LoadOrStore(cacheKey, values)
+	}
+
+	outputMap, err := publishedValuesToCtyValueMap(published.Key, rawValues.(map[string]json.RawMessage))
+	if err != nil {
+		return nil, true, err
+	}
+
+	convertedOutput, err := gocty.ToCtyValue(outputMap, generateTypeFromValuesMap(outputMap))
+	if err != nil {
+		err = TerragruntOutputEncodingError{Path: published.Key, Err: err}
+	}
+	return &convertedOutput, len(outputMap) == 0, errors.WithStackTrace(err)
+}
+
+// publishedValuesToCtyValueMap converts the JSON values of the published outputs to cty values, the types of the
+// outputs being implied by their values.
+func publishedValuesToCtyValueMap(key string, values map[string]json.RawMessage) (map[string]cty.Value, error) {
+	outputs := map[string]cty.Value{}
+	for name, value := range values {
+		valueType, err := ctyjson.ImpliedType(value)
+		if err != nil {
+			return nil, errors.WithStackTrace(TerragruntOutputParsingError{Path: key, Err: err})
+		}
+		outputVal, err := ctyjson.Unmarshal(value, valueType)
+		if err != nil {
+			return nil, errors.WithStackTrace(TerragruntOutputParsingError{Path: key, Err: err})
+		}
+		outputs[name] = outputVal
+	}
+	return outputs, nil
+}
+
+// Custom error types
+
+type InvalidPublishedOutputs struct {
+	Dependency string
+	Err        error
+}
+
+func (err InvalidPublishedOutputs) Error() string {
+	return fmt.Sprintf("Invalid published_outputs block of dependency %q: %v", err.Dependency, err.Err)
+}
+
+func (err InvalidPublishedOutputs) Unwrap() error {
+	return err.Err
+}
+
+type MissingDependencyConfigPath string
+
+func (name MissingDependencyConfigPath) Error() string {
+	return fmt.Sprintf("The dependency %q has no config_path. Set the config_path of the dependency, or read its outputs from the outputs it publishes with a published_outputs block.", string(name))
+}
+
+type PublishedOutputsNotFound struct {
+	Dependency    string
+	Key           string
+	CurrentConfig string
+}
+
+func (err PublishedOutputsNotFound) Error() string {
+	return fmt.Sprintf("The outputs of dependency %q of %s are not published to the key %s yet. Apply the dependency to publish its outputs, or set mock_outputs on the dependency block.", err.Dependency, err.CurrentConfig, err.Key)
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/outputstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerragruntConfigDependencyPublishedOutputs(t *testing.T) {
+	t.Parallel()
+
+	published := &PublishedOutputsConfig{Backend: outputstore.BackendSSM, Key: "/published-outputs-test/vpc/{output}", Outputs: []string{"vpc_id", "subnet_ids"}}
+	publishedOutputsCache.Store(published.cacheKey(), map[string]json.RawMessage{
+		"vpc_id":     json.RawMessage(`"vpc-123"`),
+		"subnet_ids": json.RawMessage(`["subnet-1","subnet-2"]`),
+	})
+
+	config := `
+dependency "vpc" {
+  published_outputs {
+    backend = "ssm"
+    key     = "/published-outputs-test/vpc/{output}"
+    outputs = ["vpc_id", "subnet_ids"]
+  }
+}
+
+inputs = {
+  vpc_id    = dependency.vpc.outputs.vpc_id
+  subnet_id = dependency.vpc.outputs.subnet_ids[0]
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{"vpc_id": "vpc-123", "subnet_id": "subnet-1"}, terragruntConfig.Inputs)
+	// The dependency is not run, as it has no config path
+	assert.Nil(t, terragruntConfig.Dependencies)
+}
+
+func TestParseTerragruntConfigDependencyPublishedOutputsMocks(t *testing.T) {
+	t.Parallel()
+
+	// The key of all the outputs was published without outputs
+	published := &PublishedOutputsConfig{Backend: outputstore.BackendSecretsManager, Key: "published-outputs-test/db"}
+	publishedOutputsCache.Store(published.cacheKey(), map[string]json.RawMessage{})
+
+	config := `
+dependency "db" {
+  published_outputs {
+    backend = "secrets_manager"
+    key     = "published-outputs-test/db"
+  }
+%s
+}
+
+inputs = {
+  endpoint = dependency.db.outputs.endpoint
+}
+`
+
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, fmt.Sprintf(config, `  mock_outputs = { endpoint = "mock" }`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"endpoint": "mock"}, terragruntConfig.Inputs)
+
+	ctx = NewParsingContext(context.Background(), mockOptionsForTest(t))
+	_, err = ParseConfigString(ctx, DefaultTerragruntConfigPath, fmt.Sprintf(config, ""), nil)
+	var notFound PublishedOutputsNotFound
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, "published-outputs-test/db", notFound.Key)
+}
+
+func TestParseTerragruntConfigDependencyInvalidPublishedOutputs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		config   string
+		expected error
+	}{
+		{
+			config: `
+dependency "vpc" {
+  mock_outputs = {
+    vpc_id = "mock"
+  }
+}
+`,
+			expected: MissingDependencyConfigPath("vpc"),
+		},
+		{
+			config: `
+dependency "vpc" {
+  published_outputs {
+    backend = "ssm"
+    key     = "/prod/vpc/{output}"
+  }
+}
+`,
+			expected: outputstore.MissingOutputNames("/prod/vpc/{output}"),
+		},
+		{
+			config: `
+dependency "vpc" {
+  published_outputs {
+    backend = "consul"
+    key     = "prod/vpc"
+  }
+}
+`,
+			expected: outputstore.UnsupportedBackend("consul"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+		_, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, testCase.config, nil)
+		require.Error(t, err, testCase.config)
+
+		cause := errors.Unwrap(err)
+		if invalid, ok := cause.(InvalidPublishedOutputs); ok {
+			cause = errors.Unwrap(invalid.Err)
+		}
+		assert.Equal(t, testCase.expected, cause, testCase.config)
+	}
+}
+
+func TestPublishedValuesToCtyValueMap(t *testing.T) {
+	t.Parallel()
+
+	outputs, err := publishedValuesToCtyValueMap("prod/vpc", map[string]json.RawMessage{
+		"vpc_id": json.RawMessage(`"vpc-123"`),
+		"tags":   json.RawMessage(`{"team": "platform"}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-123", outputs["vpc_id"].AsString())
+	assert.Equal(t, "platform", outputs["tags"].GetAttr("team").AsString())
+}
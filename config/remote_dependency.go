@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-getter"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The dir, under the cache dir of the module, the repos of its remote dependencies are fetched to.
+const remoteDependenciesDir = "remote-dependencies"
+
+// remoteDependencyRepos caches the dirs the repos of the remote dependencies are fetched to, by the URL of the repo, so
+// that every repo is fetched once per run whichever module depends on it.
+var remoteDependencyRepos = sync.Map{}
+
+type remoteDependencyRepo struct {
+	once sync.Once
+	dir  string
+	err  error
+}
+
+// IsRemoteDependencyPath returns true if the given config_path of a dependency block points at a unit of another repo,
+// e.g. `git::https://github.com/acme/platform.git//live/vpc?ref=v1.2.0`, rather than at a local dir.
+func IsRemoteDependencyPath(configPath string) bool {
+	return strings.Contains(configPath, "::") ||
+		options.FetchHost(configPath) != "" ||
+		strings.HasPrefix(configPath, "git@") ||
+		strings.HasPrefix(configPath, "github.com/") ||
+		strings.HasPrefix(configPath, "bitbucket.org/")
+}
+
+// resolveRemoteDependencies fetches the repos of the given dependency blocks pointing at units of other repos, and
+// points the blocks at the config of the unit in the fetched repo, so that the outputs of the unit are read from its
+// state as for a local dependency. The remote units are always external: they are never run, only their outputs are
+// read, so they must have a remote_state block that the outputs can be read from.
+func resolveRemoteDependencies(ctx *ParsingContext, dependencies []Dependency) ([]Dependency, error) {
+	resolved := make([]Dependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		if dependency.isEnabled() && IsRemoteDependencyPath(dependency.ConfigPath) {
+			configPath, err := fetchRemoteDependency(ctx, dependency)
+			if err != nil {
+				return nil, err
+			}
+			dependency.ConfigPath = configPath
+		}
+		resolved = append(resolved, dependency)
+	}
+	return resolved, nil
+}
+
+// fetchRemoteDependency fetches the repo of the given remote dependency and returns the path of the config of the unit
+// in the fetched repo. The whole repo is fetched, rather than the dir of the unit, so that the configs the unit includes
+// are found.
+func fetchRemoteDependency(ctx *ParsingContext, dependency Dependency) (string, error) {
+	repoURL, subdir := getter.SourceDirSubdir(dependency.ConfigPath)
+
+	what := fmt.Sprintf("the repo of the dependency %q", dependency.Name)
+	if err := ctx.TerragruntOptions.CheckOfflineFetch(what, options.FetchHost(repoURL)); err != nil {
+		return "", err
+	}
+
+	value, _ := remoteDependencyRepos.LoadOrStore(repoURL, &remoteDependencyRepo{})
+	repo := value.(*remoteDependencyRepo)
+	repo.once.Do(func() {
+		repo.dir = filepath.Join(filepath.Dir(ctx.TerragruntOptions.TerragruntConfigPath), util.TerragruntCacheDir, remoteDependenciesDir, util.EncodeBase64Sha1(repoURL))
+		ctx.TerragruntOptions.Logger.Infof("Fetching %s for the dependency %q of %s", repoURL, dependency.Name, ctx.TerragruntOptions.TerragruntConfigPath)
+
+		// The repo is fetched again on every run, as the ref may point at another commit since the last run
+		if err := os.RemoveAll(repo.dir); err != nil {
+			repo.err = errors.WithStackTrace(err)
+			return
+		}
+		if err := getter.Get(repo.dir, repoURL, getter.WithContext(ctx)); err != nil {
+			repo.err = errors.WithStackTrace(RemoteDependencyFetchError{ConfigPath: dependency.ConfigPath, Err: err})
+		}
+	})
+	if repo.err != nil {
+		return "", repo.err
+	}
+
+	configPath := getCleanedTargetConfigPath(filepath.Join(repo.dir, filepath.FromSlash(subdir)), ctx.TerragruntOptions.TerragruntConfigPath)
+	if !util.FileExists(configPath) {
+		return "", errors.WithStackTrace(DependencyConfigNotFound{Path: dependency.ConfigPath})
+	}
+
+	remoteStateConfig, err := PartialParseConfigFile(ctx.WithDecodeList(RemoteStateBlock).WithTerragruntOptions(cloneTerragruntOptionsForDependency(ctx, configPath)), configPath, nil)
+	if err != nil {
+		return "", err
+	}
+	if !canGetRemoteState(remoteStateConfig.RemoteState) {
+		return "", errors.WithStackTrace(RemoteDependencyWithoutRemoteState{ConfigPath: dependency.ConfigPath})
+	}
+	return configPath, nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRemoteDependencyPath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		configPath string
+		expected   bool
+	}{
+		{"../vpc", false},
+		{"/live/vpc", false},
+		{"C:/live/vpc", false},
+		{"git::https://github.com/acme/platform.git//live/vpc?ref=v1.2.0", true},
+		{"git::git@github.com:acme/platform.git//live/vpc", true},
+		{"https://example.com/platform.zip//live/vpc", true},
+		{"github.com/acme/platform//live/vpc", true},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, IsRemoteDependencyPath(testCase.configPath), testCase.configPath)
+	}
+}
+
+func TestDependencyBlocksToModuleDependenciesSkipsRemote(t *testing.T) {
+	t.Parallel()
+
+	dependencies := dependencyBlocksToModuleDependencies([]Dependency{
+		{Name: "vpc", ConfigPath: "../vpc"},
+		{Name: "platform", ConfigPath: "git::https://github.com/acme/platform.git//live/dns"},
+	})
+	assert.Equal(t, &ModuleDependencies{Paths: []string{"../vpc"}}, dependencies)
+}
+
+func TestFetchRemoteDependency(t *testing.T) {
+	t.Parallel()
+
+	repoDir := t.TempDir()
+	writeFile := func(path, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(repoDir, path)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, path), []byte(content), 0644))
+	}
+	writeFile("root.hcl", `
+remote_state {
+  backend = "s3"
+  config = {
+    bucket = "platform-state"
+    key    = "${path_relative_to_include()}/terraform.tfstate"
+    region = "us-east-1"
+  }
+}
+`)
+	writeFile("live/dns/terragrunt.hcl", `
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+`)
+	writeFile("live/local-state/terragrunt.hcl", "")
+	for _, args := range [][]string{{"init", "-q"}, {"add", "."}, {"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	appConfigPath := filepath.Join(t.TempDir(), "app", DefaultTerragruntConfigPath)
+	opts, err := options.NewTerragruntOptionsForTest(appConfigPath)
+	require.NoError(t, err)
+	ctx := NewParsingContext(context.Background(), opts)
+
+	configPath, err := fetchRemoteDependency(ctx, Dependency{Name: "dns", ConfigPath: "git::file://" + repoDir + "//live/dns"})
+	require.NoError(t, err)
+	assert.FileExists(t, configPath)
+	assert.FileExists(t, filepath.Join(filepath.Dir(configPath), "..", "..", "root.hcl"))
+	assert.Equal(t, filepath.Join(filepath.Dir(appConfigPath), ".terragrunt-cache", remoteDependenciesDir), filepath.Dir(filepath.Dir(filepath.Dir(filepath.Dir(configPath)))))
+
+	_, err = fetchRemoteDependency(ctx, Dependency{Name: "local", ConfigPath: "git::file://" + repoDir + "//live/local-state"})
+	var withoutRemoteState RemoteDependencyWithoutRemoteState
+	require.ErrorAs(t, err, &withoutRemoteState)
+}
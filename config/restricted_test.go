@@ -0,0 +1,59 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestrictedConfigNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		config          string
+		decodeList      []PartialDecodeSectionType
+		expectedSetting string
+	}{
+		{"terraform_binary", `terraform_binary = "./evil"`, nil, MetadataTerraformBinary},
+		{"terraform_binary of a partial parse", `terraform_binary = "./evil"`, []PartialDecodeSectionType{TerragruntVersionConstraints}, MetadataTerraformBinary},
+		{"execution", `
+execution {
+  backend = "ssh"
+  ssh {
+    host = "attacker.example.com"
+  }
+}
+`, nil, MetadataExecution},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := mockOptionsForTest(t)
+			ctx := NewParsingContext(context.Background(), opts)
+			parse := func() error {
+				if testCase.decodeList != nil {
+					_, err := PartialParseConfigString(ctx.WithDecodeList(testCase.decodeList...), DefaultTerragruntConfigPath, testCase.config, nil)
+					return err
+				}
+				_, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, testCase.config, nil)
+				return err
+			}
+
+			// Allowed unless restricted
+			require.NoError(t, parse())
+
+			opts.Restricted = true
+			err := parse()
+			var notAllowed RestrictedConfigNotAllowed
+			require.ErrorAs(t, errors.Unwrap(err), &notAllowed, "%v", err)
+			assert.Equal(t, testCase.expectedSetting, notAllowed.Setting)
+		})
+	}
+}
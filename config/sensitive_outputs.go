@@ -0,0 +1,216 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SensitiveInputs returns the names of the inputs of the config that contain the values of sensitive outputs of its
+// dependencies, e.g. an input set to `dependency.db.outputs.password` or interpolating it in a connection string. The
+// values are matched, rather than the expressions, so that the inputs set from locals or through functions keeping the
+// values, e.g. `merge`, are found as well. Only the outputs read in this run are known, as the outputs are not cached
+// across runs.
+func SensitiveInputs(opts *options.TerragruntOptions, cfg *TerragruntConfig) []string {
+	var sensitiveValues []string
+	for _, dependency := range cfg.TerragruntDependencies {
+		if dependency.ConfigPath == "" {
+			continue
+		}
+		targetConfig := getCleanedTargetConfigPath(dependency.ConfigPath, opts.TerragruntConfigPath)
+		sensitiveValues = append(sensitiveValues, sensitiveOutputValues(targetConfig)...)
+	}
+	if len(sensitiveValues) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name, value := range cfg.Inputs {
+		if containsSensitiveValue(value, sensitiveValues) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sensitiveOutputValues returns the scalar values of the sensitive outputs of the target config, as read by the
+// dependency blocks referencing it.
+func sensitiveOutputValues(targetConfig string) []string {
+	rawJsonBytes, hasRun := jsonOutputCache.Load(targetConfig)
+	if !hasRun {
+		return nil
+	}
+
+	var outputs map[string]struct {
+		Sensitive bool        `json:"sensitive"`
+		Value     interface{} `json:"value"`
+	}
+	if err := json.Unmarshal(rawJsonBytes.([]byte), &outputs); err != nil {
+		return nil
+	}
+
+	var values []string
+	for _, output := range outputs {
+		if output.Sensitive {
+			values = append(values, scalarValues(output.Value)...)
+		}
+	}
+	return values
+}
+
+// scalarValues returns the string, number and bool values nested in the value, formatted as strings.
+func scalarValues(value interface{}) []string {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		var values []string
+		for _, item := range value {
+			values = append(values, scalarValues(item)...)
+		}
+		return values
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			values = append(values, scalarValues(item)...)
+		}
+		return values
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
+
+// containsSensitiveValue returns true if a scalar value nested in the value contains one of the sensitive values.
+func containsSensitiveValue(value interface{}, sensitiveValues []string) bool {
+	for _, scalar := range scalarValues(normalizeJSONValue(value)) {
+		for _, sensitive := range sensitiveValues {
+			if strings.Contains(scalar, sensitive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normalizeJSONValue converts the value to the generic types of a JSON value, e.g. `map[string]interface{}`, as the
+// inputs are decoded to arbitrary go types.
+func normalizeJSONValue(value interface{}) interface{} {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(jsonBytes, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+// SensitiveValuePlaceholder replaces the values of the sensitive outputs of the dependencies in the debug logs and the
+// rendered config, as terraform shows them.
+const SensitiveValuePlaceholder = "(sensitive value)"
+
+// redactedOutputsJson returns the given `terraform output -json` outputs with the values of the sensitive outputs
+// masked, for logging them, unless --terragrunt-show-sensitive is set. The outputs are returned as is if they cannot be
+// parsed, as the error of parsing them is reported when they are used.
+func redactedOutputsJson(opts *options.TerragruntOptions, jsonBytes []byte) string {
+	if opts.ShowSensitive {
+		return string(jsonBytes)
+	}
+
+	var outputs map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(jsonBytes, &outputs); err != nil {
+		return string(jsonBytes)
+	}
+
+	placeholder, _ := json.Marshal(SensitiveValuePlaceholder)
+	redacted := false
+	for _, output := range outputs {
+		var sensitive bool
+		if err := json.Unmarshal(output["sensitive"], &sensitive); err == nil && sensitive {
+			output["value"] = placeholder
+			redacted = true
+		}
+	}
+	if !redacted {
+		return string(jsonBytes)
+	}
+
+	redactedBytes, err := json.Marshal(outputs)
+	if err != nil {
+		return string(jsonBytes)
+	}
+	return string(redactedBytes)
+}
+
+// RedactSensitiveValues masks the values of the sensitive outputs of the dependencies of the config, as read in this
+// run, in the rendered outputs of the dependencies and in the inputs containing them, unless --terragrunt-show-sensitive
+// is set. The config is updated in place, so it must only be used for showing it, e.g. with render-json.
+func RedactSensitiveValues(opts *options.TerragruntOptions, cfg *TerragruntConfig) {
+	if opts.ShowSensitive {
+		return
+	}
+
+	for _, name := range SensitiveInputs(opts, cfg) {
+		cfg.Inputs[name] = SensitiveValuePlaceholder
+	}
+
+	for i := range cfg.TerragruntDependencies {
+		dependency := &cfg.TerragruntDependencies[i]
+		if dependency.ConfigPath == "" || dependency.RenderedOutputs == nil {
+			continue
+		}
+		outputs := *dependency.RenderedOutputs
+		if !outputs.IsKnown() || outputs.IsNull() || !(outputs.Type().IsObjectType() || outputs.Type().IsMapType()) {
+			continue
+		}
+
+		sensitiveNames := sensitiveOutputNames(getCleanedTargetConfigPath(dependency.ConfigPath, opts.TerragruntConfigPath))
+		if len(sensitiveNames) == 0 {
+			continue
+		}
+		values := outputs.AsValueMap()
+		for _, name := range sensitiveNames {
+			if _, ok := values[name]; ok {
+				values[name] = cty.StringVal(SensitiveValuePlaceholder)
+			}
+		}
+		redacted := cty.ObjectVal(values)
+		dependency.RenderedOutputs = &redacted
+	}
+}
+
+// sensitiveOutputNames returns the names of the sensitive outputs of the target config, as read by the dependency
+// blocks referencing it.
+func sensitiveOutputNames(targetConfig string) []string {
+	rawJsonBytes, hasRun := jsonOutputCache.Load(targetConfig)
+	if !hasRun {
+		return nil
+	}
+
+	var outputs map[string]struct {
+		Sensitive bool `json:"sensitive"`
+	}
+	if err := json.Unmarshal(rawJsonBytes.([]byte), &outputs); err != nil {
+		return nil
+	}
+
+	var names []string
+	for name, output := range outputs {
+		if output.Sensitive {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
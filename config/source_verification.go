@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// The methods of verifying the module sources.
+const (
+	// Verify the signature of the git tag of the ref or, if the ref is not a tag, of the commit.
+	SourceVerificationMethodGit = "git"
+	// Verify the Sigstore bundle, published next to the archive as `<archive URL>.bundle`, with cosign.
+	SourceVerificationMethodCosign = "cosign"
+)
+
+var sourceVerificationMethods = []string{SourceVerificationMethodGit, SourceVerificationMethodCosign}
+
+// SourceVerificationConfig is the `source_verification` block, requiring the module sources to be signed by the given
+// identities before they are used.
+type SourceVerificationConfig struct {
+	Rules []SourceVerificationRule `hcl:"rule,block" cty:"rule"`
+}
+
+// SourceVerificationRule requires the signature of the sources starting with the prefix.
+type SourceVerificationRule struct {
+	// The prefix of the canonical source URLs, e.g. "git::https://github.com/acme/".
+	Prefix string `hcl:"prefix,attr" cty:"prefix"`
+	Method string `hcl:"method,attr" cty:"method"`
+	// The signers the signature must be from: the emails, user IDs, key IDs or key fingerprints of the git signers, or
+	// the certificate identities of cosign.
+	Identities []string `hcl:"identities,attr" cty:"identities"`
+	// The OIDC issuer of the cosign certificates, e.g. "https://token.actions.githubusercontent.com".
+	OIDCIssuer *string `hcl:"oidc_issuer,optional" cty:"oidc_issuer"`
+}
+
+func (conf *SourceVerificationConfig) String() string {
+	return fmt.Sprintf("SourceVerification{Rules = %d}", len(conf.Rules))
+}
+
+// Rule returns the rule with the longest prefix of the given source URL, or nil if no rule matches it.
+func (conf *SourceVerificationConfig) Rule(sourceURL string) *SourceVerificationRule {
+	if conf == nil {
+		return nil
+	}
+
+	var match *SourceVerificationRule
+	for i := range conf.Rules {
+		rule := &conf.Rules[i]
+		if strings.HasPrefix(sourceURL, rule.Prefix) && (match == nil || len(rule.Prefix) > len(match.Prefix)) {
+			match = rule
+		}
+	}
+	return match
+}
+
+// Validate checks that every rule has a prefix, a known method and identities, and that the cosign rules have the OIDC
+// issuer.
+func (conf *SourceVerificationConfig) Validate() error {
+	for _, rule := range conf.Rules {
+		switch {
+		case rule.Prefix == "":
+			return errors.WithStackTrace(InvalidSourceVerificationRule{Prefix: rule.Prefix, Reason: "the prefix must not be empty"})
+		case rule.Method != SourceVerificationMethodGit && rule.Method != SourceVerificationMethodCosign:
+			return errors.WithStackTrace(InvalidSourceVerificationRule{Prefix: rule.Prefix, Reason: fmt.Sprintf("unknown method %s, must be one of: %s", rule.Method, strings.Join(sourceVerificationMethods, ", "))})
+		case len(rule.Identities) == 0:
+			return errors.WithStackTrace(InvalidSourceVerificationRule{Prefix: rule.Prefix, Reason: "the identities must not be empty"})
+		case rule.Method == SourceVerificationMethodCosign && (rule.OIDCIssuer == nil || *rule.OIDCIssuer == ""):
+			return errors.WithStackTrace(InvalidSourceVerificationRule{Prefix: rule.Prefix, Reason: "the cosign method requires the oidc_issuer"})
+		}
+	}
+	return nil
+}
+
+// Custom error types
+
+type InvalidSourceVerificationRule struct {
+	Prefix string
+	Reason string
+}
+
+func (err InvalidSourceVerificationRule) Error() string {
+	return fmt.Sprintf("Invalid rule %q of the source_verification block: %s", err.Prefix, err.Reason)
+}
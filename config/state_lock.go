@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+const (
+	DefaultStateLockRetryMaxAttempts = 3
+	DefaultStateLockRetryBackoff     = 30 * time.Second
+	DefaultStateLockRetryMaxBackoff  = 5 * time.Minute
+)
+
+// StateLockConfig is the `state_lock` block, usually set in the root config of the stack, configuring how long the
+// terraform commands of the modules wait for the lock of their state, and how they are retried when the lock is held
+// by another run.
+type StateLockConfig struct {
+	// How long terraform waits for the lock, passed as `-lock-timeout`. Terraform fails at once if not set.
+	Timeout *string `hcl:"timeout,optional" cty:"timeout"`
+	// The retries of the commands which still failed to acquire the lock, zero to not retry them.
+	RetryMaxAttempts *int `hcl:"retry_max_attempts,optional" cty:"retry_max_attempts"`
+	// The wait before the first retry, doubled on each retry up to retry_max_backoff.
+	RetryBackoff    *string `hcl:"retry_backoff,optional" cty:"retry_backoff"`
+	RetryMaxBackoff *string `hcl:"retry_max_backoff,optional" cty:"retry_max_backoff"`
+}
+
+func (conf *StateLockConfig) String() string {
+	return fmt.Sprintf("StateLock{Timeout = %v, RetryMaxAttempts = %v}", conf.Timeout, conf.RetryMaxAttempts)
+}
+
+// GetTimeout returns the `-lock-timeout` of the terraform commands, zero if not set.
+func (conf *StateLockConfig) GetTimeout() (time.Duration, error) {
+	return parseExecutionDuration(MetadataStateLock, "timeout", conf.Timeout)
+}
+
+// GetRetryMaxAttempts returns how many times the commands failing to acquire the lock are retried.
+func (conf *StateLockConfig) GetRetryMaxAttempts() int {
+	if conf.RetryMaxAttempts == nil {
+		return DefaultStateLockRetryMaxAttempts
+	}
+	return *conf.RetryMaxAttempts
+}
+
+// GetRetryBackoff returns the wait before the first retry.
+func (conf *StateLockConfig) GetRetryBackoff() (time.Duration, error) {
+	return parseExecutionDurationWithDefault(MetadataStateLock, "retry_backoff", conf.RetryBackoff, DefaultStateLockRetryBackoff)
+}
+
+// GetRetryMaxBackoff returns the max wait between two retries.
+func (conf *StateLockConfig) GetRetryMaxBackoff() (time.Duration, error) {
+	return parseExecutionDurationWithDefault(MetadataStateLock, "retry_max_backoff", conf.RetryMaxBackoff, DefaultStateLockRetryMaxBackoff)
+}
+
+// Validate checks that the durations are positive and that the retries are not negative.
+func (conf *StateLockConfig) Validate() error {
+	if conf.RetryMaxAttempts != nil && *conf.RetryMaxAttempts < 0 {
+		return errors.WithStackTrace(InvalidStateLockRetryMaxAttempts(*conf.RetryMaxAttempts))
+	}
+	if _, err := conf.GetTimeout(); err != nil {
+		return err
+	}
+	if _, err := conf.GetRetryBackoff(); err != nil {
+		return err
+	}
+	_, err := conf.GetRetryMaxBackoff()
+	return err
+}
+
+// Custom error types
+
+type InvalidStateLockRetryMaxAttempts int
+
+func (attempts InvalidStateLockRetryMaxAttempts) Error() string {
+	return fmt.Sprintf("The retry_max_attempts of the state_lock block cannot be negative, but is %d", int(attempts))
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerragruntConfigStateLock(t *testing.T) {
+	t.Parallel()
+
+	config := `
+state_lock {
+  timeout       = "10m"
+  retry_backoff = "1m"
+}
+`
+	ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+	terragruntConfig, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, config, nil)
+	require.NoError(t, err)
+	require.NotNil(t, terragruntConfig.StateLock)
+
+	timeout, err := terragruntConfig.StateLock.GetTimeout()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, timeout)
+	retryBackoff, err := terragruntConfig.StateLock.GetRetryBackoff()
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, retryBackoff)
+	retryMaxBackoff, err := terragruntConfig.StateLock.GetRetryMaxBackoff()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultStateLockRetryMaxBackoff, retryMaxBackoff)
+	assert.Equal(t, DefaultStateLockRetryMaxAttempts, terragruntConfig.StateLock.GetRetryMaxAttempts())
+}
+
+func TestParseTerragruntConfigInvalidStateLock(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		config   string
+		expected error
+	}{
+		{
+			config:   `state_lock { timeout = "forever" }`,
+			expected: InvalidExecutionDuration{Block: MetadataStateLock, Name: "timeout", Value: "forever"},
+		},
+		{
+			config:   `state_lock { retry_max_attempts = -1 }`,
+			expected: InvalidStateLockRetryMaxAttempts(-1),
+		},
+	}
+
+	for _, testCase := range testCases {
+		ctx := NewParsingContext(context.Background(), mockOptionsForTest(t))
+		_, err := ParseConfigString(ctx, DefaultTerragruntConfigPath, testCase.config, nil)
+		require.Error(t, err)
+		assert.Equal(t, testCase.expected, errors.Unwrap(err))
+	}
+}
@@ -0,0 +1,75 @@
+package configstack
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// flushOutputLock makes sure the buffered output of modules finishing at the same time is not interleaved.
+var flushOutputLock sync.Mutex
+
+// outputChunk is a piece of output written by a module to one of its streams.
+type outputChunk struct {
+	writer io.Writer
+	data   []byte
+}
+
+// moduleOutputBuffer buffers the stdout and stderr of a module in the order they were written, so that the output can
+// be flushed as a contiguous block once the module finishes, while still going to the right stream.
+type moduleOutputBuffer struct {
+	mutex  sync.Mutex
+	chunks []outputChunk
+}
+
+// streamWriter writes to the buffer on behalf of one of the streams of the module.
+type streamWriter struct {
+	buffer *moduleOutputBuffer
+	writer io.Writer
+}
+
+func (stream *streamWriter) Write(data []byte) (int, error) {
+	stream.buffer.mutex.Lock()
+	defer stream.buffer.mutex.Unlock()
+
+	stream.buffer.chunks = append(stream.buffer.chunks, outputChunk{writer: stream.writer, data: append([]byte{}, data...)})
+	return len(data), nil
+}
+
+// bufferOutput redirects the stdout and stderr of the module to a buffer, returning the function that flushes the
+// buffered output to the original streams, wrapped in a header and a footer.
+func (module *runningModule) bufferOutput() func(moduleErr error) {
+	opts := module.Module.TerragruntOptions
+	writer, errWriter := opts.Writer, opts.ErrWriter
+
+	buffer := &moduleOutputBuffer{}
+	opts.Writer = &streamWriter{buffer: buffer, writer: writer}
+	opts.ErrWriter = &streamWriter{buffer: buffer, writer: errWriter}
+
+	return func(moduleErr error) {
+		opts.Writer, opts.ErrWriter = writer, errWriter
+
+		flushOutputLock.Lock()
+		defer flushOutputLock.Unlock()
+
+		result := "succeeded"
+		if moduleErr != nil {
+			result = "failed"
+		}
+
+		separator := strings.Repeat("=", 80)
+		fmt.Fprintf(writer, "%s\nModule %s (%s)\n%s\n", separator, module.Module.Path, opts.TerraformCommand, separator)
+
+		buffer.mutex.Lock()
+		defer buffer.mutex.Unlock()
+		for _, chunk := range buffer.chunks {
+			if _, err := chunk.writer.Write(chunk.data); err != nil {
+				opts.Logger.Errorf("Failed to write the output of module %s: %v", module.Module.Path, err)
+				return
+			}
+		}
+
+		fmt.Fprintf(writer, "%s\nModule %s (%s) %s\n%s\n", separator, module.Module.Path, opts.TerraformCommand, result, separator)
+	}
+}
@@ -0,0 +1,71 @@
+package configstack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lockedBuffer is a bytes.Buffer that can be written to concurrently.
+type lockedBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (buffer *lockedBuffer) Write(data []byte) (int, error) {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+	return buffer.buffer.Write(data)
+}
+
+func TestRunModulesBufferOutputIsContiguous(t *testing.T) {
+	t.Parallel()
+
+	output := &lockedBuffer{}
+	started := &sync.WaitGroup{}
+	started.Add(2)
+
+	newModule := func(path string) *TerraformModule {
+		opts, err := options.NewTerragruntOptionsForTest(path)
+		require.NoError(t, err)
+		opts.BufferOutput = true
+		opts.Writer = output
+		opts.ErrWriter = output
+		opts.RunTerragrunt = func(_ context.Context, opts *options.TerragruntOptions) error {
+			// Make sure both modules write their output at the same time.
+			started.Done()
+			started.Wait()
+			for i := 0; i < 10; i++ {
+				fmt.Fprintf(opts.Writer, "%s stdout %d\n", path, i)
+				fmt.Fprintf(opts.ErrWriter, "%s stderr %d\n", path, i)
+			}
+			return nil
+		}
+		return &TerraformModule{Path: path, TerragruntOptions: opts}
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	err = RunModules(context.Background(), opts, []*TerraformModule{newModule("a"), newModule("b")}, options.DefaultParallelism)
+	require.NoError(t, err)
+
+	var modulesInOrder []string
+	for _, line := range strings.Split(strings.TrimSpace(output.buffer.String()), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && (fields[1] == "stdout" || fields[1] == "stderr") {
+			if len(modulesInOrder) == 0 || modulesInOrder[len(modulesInOrder)-1] != fields[0] {
+				modulesInOrder = append(modulesInOrder, fields[0])
+			}
+		}
+	}
+	assert.ElementsMatch(t, []string{"a", "b"}, modulesInOrder)
+	assert.Contains(t, output.buffer.String(), "Module a () succeeded")
+}
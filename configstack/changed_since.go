@@ -0,0 +1,204 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// flagModulesNotChangedSince flags the modules none of whose files changed since the git ref given with
+// --terragrunt-changed-since as excluded, unless they depend, directly or not, on a module whose files changed. The files
+// of a module are the files of its dir, the files it includes and the files of its local terraform source.
+func flagModulesNotChangedSince(ctx context.Context, modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) ([]*TerraformModule, error) {
+	if terragruntOptions.ChangedSince == "" {
+		return modules, nil
+	}
+
+	changedFiles, err := filesChangedSince(ctx, terragruntOptions, terragruntOptions.ChangedSince)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := changedModules(modules, changedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, module := range modules {
+		if changed[module.Path] {
+			continue
+		}
+		if dependsOnChangedModule(module, changed, map[string]bool{}) {
+			terragruntOptions.Logger.Debugf("Module %s depends on a module changed since %s", module.Path, terragruntOptions.ChangedSince)
+			continue
+		}
+		module.FlagExcluded = true
+	}
+	return modules, nil
+}
+
+// dependsOnChangedModule returns true if the given module depends, directly or through its dependencies, on one of the
+// changed modules. The modules the module only runs after are not dependencies, as the module does not read their
+// outputs.
+func dependsOnChangedModule(module *TerraformModule, changed map[string]bool, visited map[string]bool) bool {
+	if visited[module.Path] {
+		return false
+	}
+	visited[module.Path] = true
+
+	for _, dependency := range module.Dependencies {
+		if module.runAfterOnly[dependency.Path] {
+			continue
+		}
+		if changed[dependency.Path] || dependsOnChangedModule(dependency, changed, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// changedModules returns the paths of the modules one of whose files is in the given absolute paths of the changed
+// files. A changed file in the dir of a module belongs to the module, unless it is in the dir of another module nested
+// in it.
+func changedModules(modules []*TerraformModule, changedFiles []string) (map[string]bool, error) {
+	changed := map[string]bool{}
+
+	for _, module := range modules {
+		dirs := []string{}
+		if sourceDir := localSourceDir(module); sourceDir != "" {
+			dirs = append(dirs, sourceDir)
+		}
+
+		includes := []string{}
+		for _, includeConfig := range module.Config.ProcessedIncludes {
+			includePath, err := util.CanonicalPath(includeConfig.Path, module.Path)
+			if err != nil {
+				return nil, err
+			}
+			includes = append(includes, includePath)
+		}
+
+		for _, file := range changedFiles {
+			if util.ListContainsElement(includes, file) || isInAnyDir(file, dirs) || isInModuleDir(file, module, modules) {
+				changed[module.Path] = true
+				break
+			}
+		}
+	}
+	return changed, nil
+}
+
+// localSourceDir returns the dir of the terraform source of the given module if it is local, or an empty string
+// otherwise. The dir of a source with a double-slash is the dir before the double-slash, as the whole dir is copied.
+func localSourceDir(module *TerraformModule) string {
+	if module.Config.Terraform == nil || module.Config.Terraform.Source == nil || *module.Config.Terraform.Source == "" {
+		return ""
+	}
+
+	sourceURL, err := terraform.ToSourceUrl(*module.Config.Terraform.Source, module.Path)
+	if err != nil || !terraform.IsLocalSource(sourceURL) {
+		return ""
+	}
+	sourceDir, _, _ := strings.Cut(sourceURL.Path, "//")
+	return filepath.Clean(sourceDir)
+}
+
+// isInModuleDir returns true if the given file is in the dir of the given module, and not in the dir of another of the
+// modules nested in it.
+func isInModuleDir(file string, module *TerraformModule, modules []*TerraformModule) bool {
+	if !isInAnyDir(file, []string{module.Path}) {
+		return false
+	}
+	for _, other := range modules {
+		if len(other.Path) > len(module.Path) && isInAnyDir(other.Path, []string{module.Path}) && isInAnyDir(file, []string{other.Path}) {
+			return false
+		}
+	}
+	return true
+}
+
+func isInAnyDir(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// filesChangedSince returns the absolute paths of the files of the repo of the working dir which differ between the
+// given git ref and the working tree, including the files deleted, renamed and not tracked yet.
+func filesChangedSince(ctx context.Context, terragruntOptions *options.TerragruntOptions, ref string) ([]string, error) {
+	workingDir, err := filepath.EvalSymlinks(terragruntOptions.WorkingDir)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	gitRoot, err := shell.GitTopLevelDir(ctx, terragruntOptions, workingDir)
+	if err != nil {
+		return nil, err
+	}
+	// The symlinks of the paths of the modules are not resolved, so the files are joined to the root as seen from the
+	// working dir
+	relGitRoot, err := filepath.Rel(workingDir, gitRoot)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	gitRoot = filepath.Join(terragruntOptions.WorkingDir, relGitRoot)
+
+	diff, err := runGitForOutput(ctx, terragruntOptions, gitRoot, "diff", "--name-only", "--no-renames", ref)
+	if err != nil {
+		return nil, errors.WithStackTrace(GitDiffFailed{Ref: ref, Err: err})
+	}
+	untracked, err := runGitForOutput(ctx, terragruntOptions, gitRoot, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, errors.WithStackTrace(GitDiffFailed{Ref: ref, Err: err})
+	}
+
+	var files []string
+	for _, line := range strings.Split(diff+"\n"+untracked, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, filepath.Join(gitRoot, filepath.FromSlash(line)))
+		}
+	}
+	terragruntOptions.Logger.Debugf("Files changed since %s: %s", ref, strings.Join(files, ", "))
+	return files, nil
+}
+
+// runGitForOutput runs git in the given dir and returns its stdout, or its stderr as the error if it fails.
+func runGitForOutput(ctx context.Context, terragruntOptions *options.TerragruntOptions, dir string, args ...string) (string, error) {
+	gitOpts := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	gitOpts.Writer = io.Discard
+	gitOpts.ErrWriter = io.Discard
+
+	output, err := shell.RunShellCommandWithOutput(ctx, gitOpts, dir, true, false, "git", args...)
+	if err != nil {
+		if output != nil && strings.TrimSpace(output.Stderr) != "" {
+			return "", errors.WithStackTrace(fmt.Errorf("%s", strings.TrimSpace(output.Stderr)))
+		}
+		return "", err
+	}
+	return output.Stdout, nil
+}
+
+// Custom error types
+
+type GitDiffFailed struct {
+	Ref string
+	Err error
+}
+
+func (err GitDiffFailed) Error() string {
+	return fmt.Sprintf("Failed to list the files changed since the git ref %s for --terragrunt-changed-since: %v", err.Ref, err.Err)
+}
+
+func (err GitDiffFailed) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,112 @@
+package configstack
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedModules(t *testing.T) {
+	t.Parallel()
+
+	vpc := &TerraformModule{Path: "/live/vpc", Config: config.TerragruntConfig{
+		Terraform: &config.TerraformConfig{Source: ptr("../../modules//vpc")},
+	}}
+	subnet := &TerraformModule{Path: "/live/vpc/subnet"}
+	app := &TerraformModule{Path: "/live/app", Config: config.TerragruntConfig{
+		ProcessedIncludes: config.IncludeConfigs{"root": {Name: "root", Path: "../root.hcl"}},
+	}}
+	remote := &TerraformModule{Path: "/live/db", Config: config.TerragruntConfig{
+		Terraform: &config.TerraformConfig{Source: ptr("git::https://example.com/modules.git//db?ref=v1.0.0")},
+	}}
+	modules := []*TerraformModule{vpc, subnet, app, remote}
+
+	testCases := []struct {
+		changedFile string
+		expected    map[string]bool
+	}{
+		{"/live/vpc/terragrunt.hcl", map[string]bool{"/live/vpc": true}},
+		{"/live/vpc/subnet/terragrunt.hcl", map[string]bool{"/live/vpc/subnet": true}},
+		{"/modules/vpc/main.tf", map[string]bool{"/live/vpc": true}},
+		{"/modules/common/variables.tf", map[string]bool{"/live/vpc": true}},
+		{"/live/root.hcl", map[string]bool{"/live/app": true}},
+		{"/live/vpc-old/terragrunt.hcl", map[string]bool{}},
+		{"/README.md", map[string]bool{}},
+	}
+
+	for _, testCase := range testCases {
+		changed, err := changedModules(modules, []string{testCase.changedFile})
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, changed, testCase.changedFile)
+	}
+}
+
+func TestFlagModulesNotChangedSince(t *testing.T) {
+	t.Parallel()
+
+	repoDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	runGitCommand(t, repoDir, "init", "--quiet")
+
+	writeFile := func(path string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(repoDir, path)), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(repoDir, path), []byte(path), 0644))
+	}
+	writeFile("live/vpc/terragrunt.hcl")
+	writeFile("live/app/terragrunt.hcl")
+	writeFile("live/dns/terragrunt.hcl")
+	runGitCommand(t, repoDir, "add", "--all")
+	runGitCommand(t, repoDir, "commit", "--quiet", "--message", "first")
+
+	writeFile("live/vpc/main.tf")
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(repoDir, "live", "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = filepath.Join(repoDir, "live")
+	opts.ChangedSince = "HEAD"
+
+	vpc := &TerraformModule{Path: filepath.Join(repoDir, "live", "vpc")}
+	app := &TerraformModule{Path: filepath.Join(repoDir, "live", "app"), Dependencies: []*TerraformModule{vpc}}
+	dns := &TerraformModule{Path: filepath.Join(repoDir, "live", "dns"), Dependencies: []*TerraformModule{vpc}, runAfterOnly: map[string]bool{vpc.Path: true}}
+
+	modules, err := flagModulesNotChangedSince(context.Background(), []*TerraformModule{vpc, app, dns}, opts)
+	require.NoError(t, err)
+	require.Len(t, modules, 3)
+	assert.False(t, vpc.FlagExcluded)
+	// app depends on vpc, while dns only runs after it
+	assert.False(t, app.FlagExcluded)
+	assert.True(t, dns.FlagExcluded)
+}
+
+func TestFlagModulesNotChangedSinceUnknownRef(t *testing.T) {
+	t.Parallel()
+
+	repoDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	runGitCommand(t, repoDir, "init", "--quiet")
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(repoDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = repoDir
+	opts.ChangedSince = "no-such-ref"
+
+	_, err = flagModulesNotChangedSince(context.Background(), []*TerraformModule{{Path: repoDir}}, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Failed to list the files changed since the git ref no-such-ref")
+}
+
+func runGitCommand(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
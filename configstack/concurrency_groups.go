@@ -0,0 +1,39 @@
+package configstack
+
+import (
+	"sync"
+)
+
+// concurrencyGroups serializes the runs of the modules sharing a concurrency_group, e.g. the modules updating the same
+// DNS zone, even if the dependency graph would run them at the same time.
+type concurrencyGroups struct {
+	mutex sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newConcurrencyGroups() *concurrencyGroups {
+	return &concurrencyGroups{locks: map[string]*sync.Mutex{}}
+}
+
+// Acquire blocks until no other module of the concurrency group of the given module runs, and returns the func releasing
+// the group. The modules without a concurrency group are not blocked.
+func (groups *concurrencyGroups) Acquire(module *runningModule) func() {
+	group := module.Module.Config.ConcurrencyGroup
+	if group == "" {
+		return func() {}
+	}
+
+	groups.mutex.Lock()
+	lock, ok := groups.locks[group]
+	if !ok {
+		lock = &sync.Mutex{}
+		groups.locks[group] = lock
+	}
+	groups.mutex.Unlock()
+
+	if !lock.TryLock() {
+		module.Module.TerragruntOptions.Logger.Infof("Module %s waits for the module of the concurrency group %s running to finish", module.Module.Path, group)
+		lock.Lock()
+	}
+	return lock.Unlock
+}
@@ -0,0 +1,54 @@
+package configstack
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunModulesSerializesConcurrencyGroups(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+
+	var running, maxRunning, ran atomic.Int32
+	newModule := func(path, group string) *TerraformModule {
+		moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+			ran.Add(1)
+			if group == "" {
+				return nil
+			}
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}
+		return &TerraformModule{Path: path, Config: config.TerragruntConfig{ConcurrencyGroup: group}, TerragruntOptions: moduleOpts}
+	}
+
+	modules := []*TerraformModule{
+		newModule("/live/dns-a", "route53"),
+		newModule("/live/dns-b", "route53"),
+		newModule("/live/dns-c", "route53"),
+		newModule("/live/app", ""),
+	}
+	require.NoError(t, RunModules(context.Background(), opts, modules, 10))
+
+	assert.Equal(t, int32(4), ran.Load())
+	assert.Equal(t, int32(1), maxRunning.Load())
+}
@@ -0,0 +1,295 @@
+package configstack
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The kinds of the declarations of a dependency, from the cheapest to break to the most expensive: the run_after entries
+// only order the runs of the modules run together, the dependencies entries only order the runs, the dependency blocks
+// skipping the outputs only order the runs too, while the other dependency blocks pass outputs.
+const (
+	declarationRunAfter = iota
+	declarationDependenciesPaths
+	declarationDependencyBlockSkipOutputs
+	declarationDependencyBlock
+)
+
+// CycleEdge is an edge of a dependency cycle: the module From depends on the module To.
+type CycleEdge struct {
+	From         string
+	To           string
+	Declarations []DependencyDeclaration
+}
+
+// DependencyDeclaration is where a module declares a dependency, e.g. the `dependency "vpc"` block in the terragrunt.hcl
+// of the module or of a config it includes.
+type DependencyDeclaration struct {
+	// The declaration, e.g. `dependency "vpc"` or `dependencies.paths`
+	Entry string
+	File  string
+	// The line of the declaration in the file, or 0 if it is unknown
+	Line int
+	kind int
+}
+
+func (declaration DependencyDeclaration) String() string {
+	if declaration.Line == 0 {
+		return fmt.Sprintf("%s in %s", declaration.Entry, declaration.File)
+	}
+	return fmt.Sprintf("%s at %s:%d", declaration.Entry, declaration.File, declaration.Line)
+}
+
+// diagnoseDependencyCycle returns the edges of the given cycle found in the given modules, with where each edge is
+// declared, and the edges suggested to break to remove the cycle.
+func diagnoseDependencyCycle(modules []*TerraformModule, cycle DependencyCycle) DependencyCycleDiagnostics {
+	modulesByPath := map[string]*TerraformModule{}
+	for _, module := range modules {
+		addModulesByPath(module, modulesByPath)
+	}
+
+	diagnostics := DependencyCycleDiagnostics{Cycle: cycle}
+	for i := 0; i+1 < len(cycle); i++ {
+		edge := CycleEdge{From: cycle[i], To: cycle[i+1]}
+		if module, ok := modulesByPath[edge.From]; ok {
+			edge.Declarations = findDependencyDeclarations(module, edge.To)
+		}
+		diagnostics.Edges = append(diagnostics.Edges, edge)
+	}
+	diagnostics.Suggested = suggestEdgesToBreak(modulesByPath, diagnostics.Edges)
+	return diagnostics
+}
+
+func addModulesByPath(module *TerraformModule, modulesByPath map[string]*TerraformModule) {
+	if _, ok := modulesByPath[module.Path]; ok {
+		return
+	}
+	modulesByPath[module.Path] = module
+	for _, dependency := range module.Dependencies {
+		addModulesByPath(dependency, modulesByPath)
+	}
+}
+
+// suggestEdgesToBreak returns the edges of the cycle to break: the cheapest edge whose removal makes the whole graph
+// acyclic, or the cheapest edge if breaking a single edge of the cycle leaves other cycles.
+func suggestEdgesToBreak(modulesByPath map[string]*TerraformModule, edges []CycleEdge) []CycleEdge {
+	if len(edges) == 0 {
+		return nil
+	}
+
+	candidates := make([]CycleEdge, len(edges))
+	copy(candidates, edges)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return edgeCost(candidates[i]) < edgeCost(candidates[j])
+	})
+
+	for _, edge := range candidates {
+		if isAcyclicWithout(modulesByPath, edge) {
+			return []CycleEdge{edge}
+		}
+	}
+	return candidates[:1]
+}
+
+// edgeCost returns the cost of breaking the given edge, i.e. the most expensive kind of its declarations.
+func edgeCost(edge CycleEdge) int {
+	cost := declarationRunAfter
+	for _, declaration := range edge.Declarations {
+		if declaration.kind > cost {
+			cost = declaration.kind
+		}
+	}
+	return cost
+}
+
+// isAcyclicWithout returns true if the graph of the given modules has no cycle once the given edge is removed.
+func isAcyclicWithout(modulesByPath map[string]*TerraformModule, removed CycleEdge) bool {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := map[string]int{}
+
+	var visit func(path string) bool
+	visit = func(path string) bool {
+		switch state[path] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+		state[path] = visiting
+		if module, ok := modulesByPath[path]; ok {
+			for _, dependency := range module.Dependencies {
+				if path == removed.From && dependency.Path == removed.To {
+					continue
+				}
+				if !visit(dependency.Path) {
+					return false
+				}
+			}
+		}
+		state[path] = visited
+		return true
+	}
+
+	paths := make([]string, 0, len(modulesByPath))
+	for path := range modulesByPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if !visit(path) {
+			return false
+		}
+	}
+	return true
+}
+
+// findDependencyDeclarations returns where the given module declares its dependency on the module in the given dir: the
+// dependency blocks and the dependencies entries, in the config of the module or in the configs it includes.
+func findDependencyDeclarations(module *TerraformModule, dependencyPath string) []DependencyDeclaration {
+	isDependency := func(configPath string) bool {
+		path, err := util.CanonicalPath(configPath, module.Path)
+		return err == nil && path == dependencyPath
+	}
+
+	// The dependency blocks are matched by their evaluated config_path, whichever file declares them
+	blockKinds := map[string]int{}
+	for _, dependency := range module.Config.TerragruntDependencies {
+		if !isDependency(dependency.ConfigPath) {
+			continue
+		}
+		blockKinds[dependency.Name] = declarationDependencyBlock
+		if dependency.SkipOutputs != nil && *dependency.SkipOutputs {
+			blockKinds[dependency.Name] = declarationDependencyBlockSkipOutputs
+		}
+	}
+
+	var declarations []DependencyDeclaration
+	for _, file := range moduleConfigFiles(module) {
+		declarations = append(declarations, findDependencyDeclarationsInFile(file, blockKinds, isDependency)...)
+	}
+
+	// The declarations could not be located in the files, e.g. as they are in the JSON syntax
+	if len(declarations) == 0 && module.TerragruntOptions != nil {
+		names := make([]string, 0, len(blockKinds))
+		for name := range blockKinds {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			declarations = append(declarations, DependencyDeclaration{Entry: fmt.Sprintf("dependency %q", name), File: module.TerragruntOptions.TerragruntConfigPath, kind: blockKinds[name]})
+		}
+		if len(names) == 0 && module.runAfterOnly[dependencyPath] {
+			declarations = append(declarations, DependencyDeclaration{Entry: "run_after", File: module.TerragruntOptions.TerragruntConfigPath, kind: declarationRunAfter})
+		} else if len(names) == 0 {
+			declarations = append(declarations, DependencyDeclaration{Entry: "dependencies.paths", File: module.TerragruntOptions.TerragruntConfigPath, kind: declarationDependenciesPaths})
+		}
+	}
+	return declarations
+}
+
+// moduleConfigFiles returns the config file of the module, followed by the files it includes.
+func moduleConfigFiles(module *TerraformModule) []string {
+	var files []string
+	if module.TerragruntOptions != nil {
+		files = append(files, module.TerragruntOptions.TerragruntConfigPath)
+	}
+
+	var includePaths []string
+	for _, include := range module.Config.ProcessedIncludes {
+		includePath := include.Path
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(module.Path, includePath)
+		}
+		includePaths = append(includePaths, filepath.Clean(includePath))
+	}
+	sort.Strings(includePaths)
+
+	return append(files, includePaths...)
+}
+
+// findDependencyDeclarationsInFile returns the dependency blocks with the given names and the dependencies entries
+// matching the dependency declared in the given file.
+func findDependencyDeclarationsInFile(file string, blockKinds map[string]int, isDependency func(string) bool) []DependencyDeclaration {
+	if !util.FileExists(file) || strings.HasSuffix(file, ".json") {
+		return nil
+	}
+
+	hclFile, diags := hclparse.NewParser().ParseHCLFile(file)
+	if diags.HasErrors() {
+		return nil
+	}
+	body, ok := hclFile.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	var declarations []DependencyDeclaration
+	if attr, ok := body.Attributes["run_after"]; ok {
+		if line, found := findDependencyPathLine(attr, isDependency); found {
+			declarations = append(declarations, DependencyDeclaration{Entry: "run_after", File: file, Line: line, kind: declarationRunAfter})
+		}
+	}
+	for _, block := range body.Blocks {
+		switch {
+		case block.Type == "dependency" && len(block.Labels) == 1:
+			if kind, ok := blockKinds[block.Labels[0]]; ok {
+				declarations = append(declarations, DependencyDeclaration{
+					Entry: fmt.Sprintf("dependency %q", block.Labels[0]),
+					File:  file,
+					Line:  block.DefRange().Start.Line,
+					kind:  kind,
+				})
+			}
+
+		case block.Type == "dependencies":
+			attr, ok := block.Body.Attributes["paths"]
+			if !ok {
+				continue
+			}
+			if line, found := findDependencyPathLine(attr, isDependency); found {
+				declarations = append(declarations, DependencyDeclaration{Entry: "dependencies.paths", File: file, Line: line, kind: declarationDependenciesPaths})
+			}
+		}
+	}
+	return declarations
+}
+
+// findDependencyPathLine returns the line of the entry of the paths attribute of the dependencies block matching the
+// dependency. The paths that can't be evaluated without the config, e.g. with functions, are matched on the line of the
+// attribute, once the dependency is not found in the other paths.
+func findDependencyPathLine(attr *hclsyntax.Attribute, isDependency func(string) bool) (int, bool) {
+	tuple, ok := attr.Expr.(*hclsyntax.TupleConsExpr)
+	if !ok {
+		return attr.SrcRange.Start.Line, true
+	}
+
+	hasDynamicPaths := false
+	for _, expr := range tuple.Exprs {
+		value, diags := expr.Value(&hcl.EvalContext{})
+		if diags.HasErrors() || !value.IsKnown() || value.IsNull() || !value.Type().Equals(cty.String) {
+			hasDynamicPaths = true
+			continue
+		}
+		if isDependency(value.AsString()) {
+			return expr.Range().Start.Line, true
+		}
+	}
+	if hasDynamicPaths {
+		return attr.SrcRange.Start.Line, true
+	}
+	return 0, false
+}
@@ -0,0 +1,71 @@
+package configstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// flagDependencyClosureAssumedApplied adds the dependencies, direct or not, of the modules included with
+// --terragrunt-include-dir to the run as already applied, so that the included modules read their outputs without
+// running them, and logs the modules added with the included module which needs them. The modules the included modules
+// only run after are not added, as their outputs are not read.
+func flagDependencyClosureAssumedApplied(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
+	var queue []*TerraformModule
+	for _, module := range modules {
+		if !module.FlagExcluded {
+			queue = append(queue, module)
+		}
+	}
+
+	// The chain of modules through which each module added is a dependency of an included module, from the dependent
+	// of the module added to the included module
+	neededBy := map[string][]string{}
+	for len(queue) > 0 {
+		module := queue[0]
+		queue = queue[1:]
+
+		for _, dependency := range module.Dependencies {
+			if module.runAfterOnly[dependency.Path] || !dependency.FlagExcluded {
+				continue
+			}
+			dependency.FlagExcluded = false
+			dependency.AssumeAlreadyApplied = true
+			neededBy[dependency.Path] = append([]string{module.Path}, neededBy[module.Path]...)
+			queue = append(queue, dependency)
+		}
+	}
+
+	logDependencyClosure(terragruntOptions, neededBy)
+	return modules
+}
+
+// logDependencyClosure logs the modules added to the run as dependencies of the included modules, with the chain of
+// modules which needs each of them.
+func logDependencyClosure(terragruntOptions *options.TerragruntOptions, neededBy map[string][]string) {
+	if len(neededBy) == 0 {
+		return
+	}
+
+	relPath := func(path string) string {
+		if rel, err := util.GetPathRelativeTo(path, terragruntOptions.WorkingDir); err == nil {
+			return rel
+		}
+		return path
+	}
+
+	lines := make([]string, 0, len(neededBy))
+	for path, chain := range neededBy {
+		reasons := make([]string, 0, len(chain))
+		for _, dependent := range chain {
+			reasons = append(reasons, "a dependency of "+relPath(dependent))
+		}
+		lines = append(lines, fmt.Sprintf("  - %s: %s", relPath(path), strings.Join(reasons, ", ")))
+	}
+	sort.Strings(lines)
+
+	terragruntOptions.Logger.Infof("Added %d modules to the run to read their outputs, without running them:\n%s", len(neededBy), strings.Join(lines, "\n"))
+}
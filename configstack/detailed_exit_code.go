@@ -0,0 +1,39 @@
+package configstack
+
+import (
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// isPlanWithDetailedExitCode returns true if the run is a run-all plan with -detailed-exitcode, whose modules exit with
+// DetailedExitCodeChanges when their plan has changes.
+func isPlanWithDetailedExitCode(opts *options.TerragruntOptions) bool {
+	return isPlanRun(opts) && util.ListContainsElement(opts.TerraformCliArgs, terraform.FlagNameDetailedExitCode)
+}
+
+// withPlanDetailedExitCode aggregates the exit codes of the modules of a run-all plan with -detailed-exitcode into the
+// error of the run, given the errors of the modules: nil if all the modules are clean, an error exiting with
+// DetailedExitCodeChanges if some have changes, and an error exiting with 1 if any failed.
+func withPlanDetailedExitCode(opts *options.TerragruntOptions, modules map[string]*runningModule, err error) error {
+	if err == nil || !isPlanWithDetailedExitCode(opts) {
+		return err
+	}
+
+	var changed []string
+	for path, module := range modules {
+		if _, skipped := errors.Unwrap(module.Err).(ModuleSkippedByUser); module.Err == nil || skipped {
+			continue
+		}
+		if !IsPlanWithChanges(module.Module.TerragruntOptions, module.Err) {
+			return errors.WithStackTrace(PlanFailedWithDetailedExitCode{Err: err})
+		}
+		changed = append(changed, path)
+	}
+	sort.Strings(changed)
+	return errors.WithStackTrace(PlanHasChanges{Modules: changed})
+}
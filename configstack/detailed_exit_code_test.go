@@ -0,0 +1,92 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// planExitCode is the error of a plan exiting with the given exit code.
+type planExitCode int
+
+func (err planExitCode) Error() string {
+	return fmt.Sprintf("exit status %d", int(err))
+}
+
+func (err planExitCode) ExitStatus() (int, error) {
+	return int(err), nil
+}
+
+func planModuleWithDetailedExitCode(t *testing.T, path string, planErr error, executed *bool, dependencies ...*TerraformModule) *TerraformModule {
+	t.Helper()
+
+	opts := optionsWithMockTerragruntCommand(t, path, planErr, executed)
+	opts.TerraformCommand = "plan"
+	opts.TerraformCliArgs = []string{"plan", "-detailed-exitcode"}
+	return &TerraformModule{Path: path, Dependencies: dependencies, TerragruntOptions: opts}
+}
+
+func TestRunModulesPlanDetailedExitCode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		errA             error
+		errC             error
+		continueOnError  bool
+		expectedExitCode int
+	}{
+		{"clean", nil, nil, false, 0},
+		{"changes", planExitCode(2), nil, false, 2},
+		{"failure", planExitCode(2), planExitCode(1), false, 1},
+		{"clean with continue on error", nil, nil, true, 0},
+		{"changes with continue on error", planExitCode(2), nil, true, 2},
+		{"failure with continue on error", planExitCode(2), planExitCode(1), true, ExitCodeSomeFailed},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			aRan, bRan, cRan := false, false, false
+			moduleA := planModuleWithDetailedExitCode(t, "a", testCase.errA, &aRan)
+			// The dependents of the modules with changes are planned too
+			moduleB := planModuleWithDetailedExitCode(t, "b", nil, &bRan, moduleA)
+			moduleC := planModuleWithDetailedExitCode(t, "c", testCase.errC, &cRan)
+
+			opts, err := options.NewTerragruntOptionsForTest("")
+			require.NoError(t, err)
+			opts.TerraformCommand = "plan"
+			opts.TerraformCliArgs = []string{"plan", "-detailed-exitcode"}
+			opts.ContinueOnError = testCase.continueOnError
+
+			err = RunModules(context.Background(), opts, []*TerraformModule{moduleA, moduleB, moduleC}, options.DefaultParallelism)
+			assert.True(t, aRan && bRan && cRan)
+			if testCase.expectedExitCode == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			exitCode, exitCodeErr := shell.GetExitCode(err)
+			require.NoError(t, exitCodeErr)
+			assert.Equal(t, testCase.expectedExitCode, exitCode)
+			if testCase.continueOnError {
+				// The modules with changes succeeded
+				reportErr, isReportErr := errors.Unwrap(err).(RunReportError)
+				require.True(t, isReportErr)
+				assert.Equal(t, []string{"a"}, reportErr.Report.Changed)
+				assert.Contains(t, reportErr.Report.Succeeded, "a")
+				assert.NotContains(t, reportErr.Report.Failed, "a")
+			} else if testCase.expectedExitCode == 2 {
+				assert.Equal(t, PlanHasChanges{Modules: []string{"a"}}, errors.Unwrap(err))
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package configstack
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/terraform"
+)
 
 // Custom error types
 
@@ -32,3 +37,95 @@ type InfiniteRecursion struct {
 func (err InfiniteRecursion) Error() string {
 	return fmt.Sprintf("Hit what seems to be an infinite recursion after going %d levels deep. Please check for a circular dependency! Modules involved: %v", err.RecursionLevel, err.Modules)
 }
+
+type InvalidRunHistory struct {
+	Path string
+	Line int
+	Err  error
+}
+
+func (err InvalidRunHistory) Error() string {
+	return fmt.Sprintf("Line %d of the run history %s is not a valid run: %v", err.Line, err.Path, err.Err)
+}
+
+func (err InvalidRunHistory) Unwrap() error {
+	return err.Err
+}
+
+type SavedPlansManifestNotFound string
+
+func (dir SavedPlansManifestNotFound) Error() string {
+	return fmt.Sprintf("No plans were saved to %s: the manifest %s is missing, run run-all plan with --terragrunt-save-plans %s first, and make sure all its modules were planned", string(dir), SavedPlansManifestFile, string(dir))
+}
+
+type InvalidSavedPlansManifest struct {
+	Path string
+	Err  error
+}
+
+func (err InvalidSavedPlansManifest) Error() string {
+	return fmt.Sprintf("The manifest of the saved plans %s is not valid: %v", err.Path, err.Err)
+}
+
+func (err InvalidSavedPlansManifest) Unwrap() error {
+	return err.Err
+}
+
+type SavedPlansMismatch struct {
+	Dir         string
+	Differences []string
+}
+
+func (err SavedPlansMismatch) Error() string {
+	return fmt.Sprintf("The stack does not match the plans saved to %s, plan it again:\n  - %s", err.Dir, strings.Join(err.Differences, "\n  - "))
+}
+
+type SavedPlanNotFound struct {
+	Module   string
+	PlanFile string
+}
+
+func (err SavedPlanNotFound) Error() string {
+	return fmt.Sprintf("The plan file %s of the module %s is missing", err.PlanFile, err.Module)
+}
+
+type SavedPlanChecksumMismatch struct {
+	Module   string
+	PlanFile string
+}
+
+func (err SavedPlanChecksumMismatch) Error() string {
+	return fmt.Sprintf("The plan file %s of the module %s was modified since it was saved", err.PlanFile, err.Module)
+}
+
+// PlanHasChanges is the error of a run-all plan with -detailed-exitcode in which some of the modules have changes,
+// exiting with the exit code of terraform for the plans with changes.
+type PlanHasChanges struct {
+	Modules []string
+}
+
+func (err PlanHasChanges) Error() string {
+	return fmt.Sprintf("The plans of %d modules have changes: %s", len(err.Modules), strings.Join(err.Modules, ", "))
+}
+
+func (err PlanHasChanges) ExitStatus() (int, error) {
+	return terraform.DetailedExitCodeChanges, nil
+}
+
+// PlanFailedWithDetailedExitCode is the error of a run-all plan with -detailed-exitcode in which some of the modules
+// failed, exiting with 1 even if other modules have changes.
+type PlanFailedWithDetailedExitCode struct {
+	Err error
+}
+
+func (err PlanFailedWithDetailedExitCode) Error() string {
+	return err.Err.Error()
+}
+
+func (err PlanFailedWithDetailedExitCode) Unwrap() error {
+	return err.Err
+}
+
+func (err PlanFailedWithDetailedExitCode) ExitStatus() (int, error) {
+	return 1, nil
+}
@@ -0,0 +1,90 @@
+package configstack
+
+import (
+	"sort"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	// How many of the last runs of the run history are analyzed to detect the flaky modules.
+	flakyHistoryRuns = 20
+	// A module is flaky if its failures were resolved by retrying in at least this many of the analyzed runs...
+	flakyMinResolvedRuns = 2
+	// ...and in at least this share of the analyzed runs which ran it.
+	flakyMinResolvedRatio = 0.2
+)
+
+// FlakyModule is a module whose failures are frequently resolved by retrying it.
+type FlakyModule struct {
+	Path string
+	// The number of the analyzed runs which ran the module
+	Runs int
+	// The number of the analyzed runs in which the module succeeded once retried
+	ResolvedByRetry int
+}
+
+// FlakyModules returns the flaky modules of the last runs of the given run history, by path relative to the working dir:
+// the modules which succeeded once retried in several of the runs.
+func FlakyModules(runs []*HistoryRun) map[string]FlakyModule {
+	if len(runs) > flakyHistoryRuns {
+		runs = runs[len(runs)-flakyHistoryRuns:]
+	}
+
+	modules := map[string]*FlakyModule{}
+	for _, run := range runs {
+		for _, module := range run.Modules {
+			if module.Outcome != OutcomeSucceeded && module.Outcome != OutcomeFailed {
+				continue
+			}
+
+			flaky, ok := modules[module.Path]
+			if !ok {
+				flaky = &FlakyModule{Path: module.Path}
+				modules[module.Path] = flaky
+			}
+			flaky.Runs++
+			if module.Outcome == OutcomeSucceeded && module.Retries > 0 {
+				flaky.ResolvedByRetry++
+			}
+		}
+	}
+
+	flakyModules := map[string]FlakyModule{}
+	for path, module := range modules {
+		if module.ResolvedByRetry >= flakyMinResolvedRuns && float64(module.ResolvedByRetry) >= flakyMinResolvedRatio*float64(module.Runs) {
+			flakyModules[path] = *module
+		}
+	}
+	return flakyModules
+}
+
+// handleFlakyModules warns about the given modules found flaky in the run history, and raises how many times they are
+// retried if --terragrunt-flaky-retry-max-attempts is set.
+func handleFlakyModules(opts *options.TerragruntOptions, modules map[string]*runningModule, flakyModules map[string]FlakyModule) {
+	var paths []string
+	for path := range modules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		module := modules[path]
+		relPath, err := util.GetPathRelativeTo(module.Module.Path, opts.WorkingDir)
+		if err != nil {
+			continue
+		}
+		flaky, ok := flakyModules[relPath]
+		if !ok || module.Module.TerragruntOptions == nil {
+			continue
+		}
+
+		if opts.FlakyRetryMaxAttempts > 0 {
+			opts.Logger.Infof("The module %s is flaky, its failures were resolved by retrying it in %d of its last %d runs: retrying it up to %d times", module.Module.Path, flaky.ResolvedByRetry, flaky.Runs, opts.FlakyRetryMaxAttempts)
+			module.Module.TerragruntOptions.Flaky = true
+		} else {
+			opts.Logger.Warnf("The module %s is flaky, its failures were resolved by retrying it in %d of its last %d runs. Add its transient errors to retryable_errors, or retry it more with --terragrunt-flaky-retry-max-attempts.", module.Module.Path, flaky.ResolvedByRetry, flaky.Runs)
+		}
+	}
+}
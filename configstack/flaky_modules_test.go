@@ -0,0 +1,55 @@
+package configstack
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlakyModules(t *testing.T) {
+	t.Parallel()
+
+	var runs []*HistoryRun
+	for i := 0; i < 10; i++ {
+		run := &HistoryRun{Modules: []*HistoryModule{
+			{Path: "vpc", Outcome: OutcomeSucceeded},
+			{Path: "app", Outcome: OutcomeSucceeded},
+			{Path: "dns", Outcome: OutcomeSucceeded},
+		}}
+		switch i {
+		case 2, 6:
+			// vpc succeeded once retried twice out of 10 runs
+			run.Modules[0].Retries = 1
+			// dns was retried but kept failing, which is not resolved by retrying
+			run.Modules[2] = &HistoryModule{Path: "dns", Outcome: OutcomeFailed, Retries: 2}
+		case 4:
+			// app succeeded once retried only once
+			run.Modules[1].Retries = 3
+		}
+		runs = append(runs, run)
+	}
+
+	assert.Equal(t, map[string]FlakyModule{"vpc": {Path: "vpc", Runs: 10, ResolvedByRetry: 2}}, FlakyModules(runs))
+}
+
+func TestHandleFlakyModules(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.FlakyRetryMaxAttempts = 10
+
+	newModule := func(path string) *runningModule {
+		return &runningModule{Module: &TerraformModule{Path: path, TerragruntOptions: opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))}}
+	}
+	modules := map[string]*runningModule{"/live/vpc": newModule("/live/vpc"), "/live/app": newModule("/live/app")}
+
+	handleFlakyModules(opts, modules, map[string]FlakyModule{"vpc": {Path: "vpc", Runs: 10, ResolvedByRetry: 3}})
+	assert.True(t, modules["/live/vpc"].Module.TerragruntOptions.Flaky)
+	assert.False(t, modules["/live/app"].Module.TerragruntOptions.Flaky)
+}
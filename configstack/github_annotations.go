@@ -0,0 +1,74 @@
+package configstack
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The env var GitHub Actions sets to the dir of the checked out repo.
+const gitHubWorkspaceEnvVar = "GITHUB_WORKSPACE"
+
+// emitGitHubAnnotations writes a GitHub Actions annotation for every module of the run that failed or drifted. Modules
+// that did not run because of a dependency or the user are not annotated, as the cause is annotated already.
+func emitGitHubAnnotations(opts *options.TerragruntOptions, modules map[string]*runningModule) {
+	var annotations []util.GitHubAnnotation
+
+	for _, module := range modules {
+		if module.Err == nil {
+			continue
+		}
+
+		switch errors.Unwrap(module.Err).(type) {
+		case DependencyFinishedWithError, ModuleSkippedByUser, RunAbortedByUser:
+			continue
+		}
+
+		annotations = append(annotations, GitHubAnnotationsForError(module.Module.TerragruntOptions, module.Err)...)
+	}
+
+	util.EmitGitHubAnnotations(opts.Writer, annotations...)
+}
+
+// GitHubAnnotationsForError returns the GitHub Actions annotations of the given error of running the module of the
+// given options: a warning if the error is a plan with changes, the HCL diagnostics of the error, with their file and
+// line, if any, or an error on the config of the module otherwise.
+func GitHubAnnotationsForError(terragruntOptions *options.TerragruntOptions, err error) []util.GitHubAnnotation {
+	workspaceDir := terragruntOptions.Env[gitHubWorkspaceEnvVar]
+	configPath := util.GitHubAnnotationPath(terragruntOptions.TerragruntConfigPath, workspaceDir)
+
+	if IsPlanWithChanges(terragruntOptions, err) {
+		return []util.GitHubAnnotation{{
+			Level:   util.GitHubAnnotationWarning,
+			File:    configPath,
+			Title:   "Drift detected",
+			Message: fmt.Sprintf("The plan of module %s has changes.", terragruntOptions.WorkingDir),
+		}}
+	}
+
+	if annotations := util.GitHubAnnotationsForDiagnostics(err, workspaceDir); len(annotations) > 0 {
+		return annotations
+	}
+
+	return []util.GitHubAnnotation{{
+		Level:   util.GitHubAnnotationError,
+		File:    configPath,
+		Title:   "Module failed",
+		Message: fmt.Sprintf("Module %s failed: %v", terragruntOptions.WorkingDir, err),
+	}}
+}
+
+// IsPlanWithChanges returns true if the given error is `terraform plan -detailed-exitcode` reporting that the plan has
+// changes, rather than a failure.
+func IsPlanWithChanges(terragruntOptions *options.TerragruntOptions, err error) bool {
+	if terragruntOptions.TerraformCommand != terraform.CommandNamePlan || !util.ListContainsElement(terragruntOptions.TerraformCliArgs, terraform.FlagNameDetailedExitCode) {
+		return false
+	}
+	exitCode, exitCodeErr := shell.GetExitCode(err)
+	return exitCodeErr == nil && exitCode == terraform.DetailedExitCodeChanges
+}
@@ -1,10 +1,15 @@
 package configstack
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCheckForCycles(t *testing.T) {
@@ -85,3 +90,76 @@ func TestCheckForCycles(t *testing.T) {
 		}
 	}
 }
+
+func TestStackCheckForCyclesDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	vpcDir := filepath.Join(rootDir, "vpc")
+	appDir := filepath.Join(rootDir, "app")
+	dbDir := filepath.Join(rootDir, "db")
+	files := map[string]string{
+		filepath.Join(vpcDir, config.DefaultTerragruntConfigPath): `
+dependencies {
+  paths = [
+    "../db",
+    "../app",
+  ]
+}
+`,
+		filepath.Join(appDir, config.DefaultTerragruntConfigPath): `
+dependency "db" {
+  config_path = "../db"
+}
+`,
+		filepath.Join(dbDir, config.DefaultTerragruntConfigPath): `
+dependency "vpc" {
+  config_path = "../vpc"
+}
+`,
+	}
+	for path, contents := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+
+	newModule := func(dir string, cfg config.TerragruntConfig) *TerraformModule {
+		opts, err := options.NewTerragruntOptionsForTest(filepath.Join(dir, config.DefaultTerragruntConfigPath))
+		require.NoError(t, err)
+		return &TerraformModule{Path: dir, Config: cfg, TerragruntOptions: opts}
+	}
+	vpc := newModule(vpcDir, config.TerragruntConfig{Dependencies: &config.ModuleDependencies{Paths: []string{"../db", "../app"}}})
+	app := newModule(appDir, config.TerragruntConfig{TerragruntDependencies: []config.Dependency{{Name: "db", ConfigPath: "../db"}}})
+	db := newModule(dbDir, config.TerragruntConfig{TerragruntDependencies: []config.Dependency{{Name: "vpc", ConfigPath: "../vpc"}}})
+	vpc.Dependencies = []*TerraformModule{db, app}
+	app.Dependencies = []*TerraformModule{db}
+	db.Dependencies = []*TerraformModule{vpc}
+
+	stack := &Stack{Path: rootDir, Modules: []*TerraformModule{vpc, app, db}}
+	err := stack.CheckForCycles()
+	require.Error(t, err)
+
+	var cycle DependencyCycle
+	require.ErrorAs(t, err, &cycle)
+	assert.Equal(t, DependencyCycle{vpcDir, dbDir, vpcDir}, cycle)
+
+	var diagnostics DependencyCycleDiagnostics
+	require.ErrorAs(t, err, &diagnostics)
+	require.Len(t, diagnostics.Edges, 2)
+	assert.Equal(t, []DependencyDeclaration{{Entry: "dependencies.paths", File: filepath.Join(vpcDir, config.DefaultTerragruntConfigPath), Line: 4, kind: declarationDependenciesPaths}}, diagnostics.Edges[0].Declarations)
+	assert.Equal(t, []DependencyDeclaration{{Entry: `dependency "vpc"`, File: filepath.Join(dbDir, config.DefaultTerragruntConfigPath), Line: 2, kind: declarationDependencyBlock}}, diagnostics.Edges[1].Declarations)
+
+	// Breaking the cheaper dependencies entry of vpc on db leaves the cycle through app, unlike the dependency of db on vpc
+	require.Len(t, diagnostics.Suggested, 1)
+	assert.Equal(t, dbDir, diagnostics.Suggested[0].From)
+	assert.Equal(t, vpcDir, diagnostics.Suggested[0].To)
+	assert.Contains(t, err.Error(), "To break the cycle, remove the dependency of "+dbDir+" on "+vpcDir+" (dependency \"vpc\" at "+filepath.Join(dbDir, config.DefaultTerragruntConfigPath)+":2).")
+
+	// Without the cycle through app, the dependencies entry is the cheapest to break
+	vpc.Dependencies = []*TerraformModule{db}
+	err = stack.CheckForCycles()
+	require.ErrorAs(t, err, &diagnostics)
+	require.Len(t, diagnostics.Suggested, 1)
+	assert.Equal(t, vpcDir, diagnostics.Suggested[0].From)
+	assert.Equal(t, dbDir, diagnostics.Suggested[0].To)
+}
@@ -33,9 +33,21 @@ func WriteDot(w io.Writer, terragruntOptions *options.TerragruntOptions, modules
 
 	for _, source := range modules {
 		// apply a different coloring for excluded nodes
-		style := ""
+		var attrs []string
 		if source.FlagExcluded {
-			style = "[color=red]"
+			attrs = append(attrs, "color=red")
+		}
+		// draw the modules which are never run, e.g. the units of other repos, with a dashed outline
+		if source.AssumeAlreadyApplied {
+			attrs = append(attrs, "style=dashed")
+		}
+		// show the owner of the module under its path
+		if source.Config.Owner != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", strings.TrimPrefix(source.Path, prefix)+"\n"+source.Config.Owner))
+		}
+		style := ""
+		if len(attrs) > 0 {
+			style = "[" + strings.Join(attrs, ", ") + "]"
 		}
 
 		nodeLine := fmt.Sprintf("\t\"%s\" %s;\n",
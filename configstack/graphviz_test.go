@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
 )
@@ -113,3 +114,23 @@ digraph {
 `)
 	assert.True(t, strings.Contains(stdout.String(), expected))
 }
+
+func TestGraphOwner(t *testing.T) {
+	a := &TerraformModule{Path: "a", Config: config.TerragruntConfig{Owner: "team-network"}}
+	b := &TerraformModule{Path: "b", FlagExcluded: true, Config: config.TerragruntConfig{Owner: "team-data"}, Dependencies: []*TerraformModule{a}}
+	c := &TerraformModule{Path: "c", Dependencies: []*TerraformModule{a}}
+
+	var stdout bytes.Buffer
+	terragruntOptions, _ := options.NewTerragruntOptionsForTest("/terragrunt.hcl")
+	WriteDot(&stdout, terragruntOptions, []*TerraformModule{a, b, c})
+	expected := strings.TrimSpace(`
+digraph {
+	"a" [label="a\nteam-network"];
+	"b" [color=red, label="b\nteam-data"];
+	"b" -> "a";
+	"c" ;
+	"c" -> "a";
+}
+`)
+	assert.True(t, strings.Contains(stdout.String(), expected))
+}
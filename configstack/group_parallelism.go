@@ -0,0 +1,38 @@
+package configstack
+
+import (
+	"sync"
+)
+
+// groupSemaphores limits the number of modules of the same run group running at the same time, so that a large group,
+// e.g. of many leaf modules, does not hit the rate limits of the provider APIs, while the small groups still run fully
+// in parallel.
+type groupSemaphores struct {
+	parallelism int
+	mutex       sync.Mutex
+	semaphores  map[int]*moduleSemaphore
+}
+
+func newGroupSemaphores(parallelism int) *groupSemaphores {
+	return &groupSemaphores{parallelism: parallelism, semaphores: map[int]*moduleSemaphore{}}
+}
+
+// Acquire blocks until a slot of the run group of the given module is available, and returns the func releasing it. The
+// modules are not limited if the parallelism is not set, or if they are not run as a stack, and so have no run group.
+func (groups *groupSemaphores) Acquire(module *runningModule) func() {
+	group := module.Module.runGroup
+	if groups.parallelism <= 0 || group == 0 {
+		return func() {}
+	}
+
+	groups.mutex.Lock()
+	semaphore, ok := groups.semaphores[group]
+	if !ok {
+		semaphore = newModuleSemaphore(groups.parallelism)
+		groups.semaphores[group] = semaphore
+	}
+	groups.mutex.Unlock()
+
+	semaphore.Acquire(module)
+	return semaphore.Release
+}
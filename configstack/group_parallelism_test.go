@@ -0,0 +1,63 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunModulesLimitsGroupParallelism(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+	opts.GroupParallelism = 2
+
+	var running, maxRunning, maxRunningGroups, ran atomic.Int32
+	runningGroups := map[int]*atomic.Int32{1: {}, 2: {}}
+	newModule := func(path string, runGroup int) *TerraformModule {
+		moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+			ran.Add(1)
+			current := runningGroups[runGroup].Add(1)
+			defer runningGroups[runGroup].Add(-1)
+			updateMax(&maxRunning, running.Add(1))
+			defer running.Add(-1)
+			if runGroup == 1 {
+				updateMax(&maxRunningGroups, current)
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}
+		return &TerraformModule{Path: path, TerragruntOptions: moduleOpts, runGroup: runGroup}
+	}
+
+	var modules []*TerraformModule
+	for i := 0; i < 6; i++ {
+		modules = append(modules, newModule(fmt.Sprintf("/live/leaf-%d", i), 1))
+	}
+	modules = append(modules, newModule("/live/app", 2))
+	require.NoError(t, RunModules(context.Background(), opts, modules, 10))
+
+	assert.Equal(t, int32(7), ran.Load())
+	assert.Equal(t, int32(2), maxRunningGroups.Load())
+	// The module of the other group is not blocked by the limit of the first group
+	assert.Equal(t, int32(3), maxRunning.Load())
+}
+
+func updateMax(max *atomic.Int32, current int32) {
+	for {
+		previous := max.Load()
+		if current <= previous || max.CompareAndSwap(previous, current) {
+			return
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package configstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/sirupsen/logrus"
+)
+
+// LockTableUsage maps the name of a DynamoDB lock table to the paths of the modules that lock their state in it.
+type LockTableUsage map[string][]string
+
+// LockTablesCommand is the command reporting the lock tables of the modules of the stack.
+const LockTablesCommand = "lock-tables"
+
+// resolvesRemoteState returns true if the stack resolves the remote_state block of its modules for the given command,
+// the commands locking the state checking the lock tables before any module runs.
+func resolvesRemoteState(terraformCommand string) bool {
+	return terraformCommand == LockTablesCommand || util.ListContainsElement(config.TERRAFORM_COMMANDS_NEED_LOCKING, terraformCommand)
+}
+
+// FindLockTableUsage returns which modules of the stack use which lock table, from the remote_state blocks resolved
+// with the stack, so that expressions in the `dynamodb_table` attribute (e.g. interpolating the environment, account or
+// region) are evaluated per module. An error is returned if modules that store the same state, the same key of the same
+// S3 bucket, resolve to different lock tables, as their runs would not lock each other out. The modules whose
+// remote_state block could not be resolved are not checked, as logged when the stack was resolved.
+func (stack *Stack) FindLockTableUsage(logger *logrus.Entry) (LockTableUsage, error) {
+	remoteStates := map[string]*remote.RemoteState{}
+
+	for _, module := range stack.Modules {
+		if module.FlagExcluded {
+			continue
+		}
+		remoteStates[module.Path] = module.Config.RemoteState
+	}
+
+	return lockTableUsage(logger, remoteStates)
+}
+
+// lockTableUsage groups the given modules (module path -> remote state) by the DynamoDB lock table they use. Modules
+// that don't use the s3 backend or don't configure a lock table are not included, nor are the modules with an invalid
+// s3 config, which fail when they run. The states of a bucket may be locked
+// in different tables, e.g. per environment, but a state must be locked in a single table.
+func lockTableUsage(logger *logrus.Entry, remoteStates map[string]*remote.RemoteState) (LockTableUsage, error) {
+	usage := LockTableUsage{}
+	tablesByState := map[string]map[string][]string{}
+
+	for modulePath, remoteState := range remoteStates {
+		if remoteState == nil || remoteState.Backend != "s3" {
+			continue
+		}
+
+		s3ConfigExtended, err := remote.ParseExtendedS3Config(remoteState.Config)
+		if err != nil {
+			logger.Warnf("Not checking the lock table of the module %s, as its remote_state block is invalid: %v", modulePath, err)
+			continue
+		}
+
+		s3Config := s3ConfigExtended.GetRemoteStateConfigS3()
+
+		tableName := s3Config.GetLockTableName()
+		if tableName == "" {
+			continue
+		}
+
+		usage[tableName] = append(usage[tableName], modulePath)
+
+		state := fmt.Sprintf("s3://%s/%s", s3Config.Bucket, s3Config.Key)
+		if _, ok := tablesByState[state]; !ok {
+			tablesByState[state] = map[string][]string{}
+		}
+		tablesByState[state][tableName] = append(tablesByState[state][tableName], modulePath)
+	}
+
+	for _, modulePaths := range usage {
+		sort.Strings(modulePaths)
+	}
+
+	states := make([]string, 0, len(tablesByState))
+	for state := range tablesByState {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	for _, state := range states {
+		if tables := tablesByState[state]; len(tables) > 1 {
+			for _, modulePaths := range tables {
+				sort.Strings(modulePaths)
+			}
+			return usage, errors.WithStackTrace(InconsistentLockTables{State: state, Tables: tables})
+		}
+	}
+
+	return usage, nil
+}
+
+// Custom error types
+
+type InconsistentLockTables struct {
+	State  string
+	Tables map[string][]string
+}
+
+func (err InconsistentLockTables) Error() string {
+	tableNames := []string{}
+	for tableName := range err.Tables {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	details := []string{}
+	for _, tableName := range tableNames {
+		details = append(details, fmt.Sprintf("%s (%s)", tableName, strings.Join(err.Tables[tableName], ", ")))
+	}
+
+	return fmt.Sprintf("Modules storing the state %s resolve to different DynamoDB lock tables: %s", err.State, strings.Join(details, "; "))
+}
@@ -0,0 +1,123 @@
+package configstack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockTableUsage(t *testing.T) {
+	t.Parallel()
+
+	remoteStates := map[string]*remote.RemoteState{
+		"/stage/vpc": {Backend: "s3", Config: map[string]interface{}{"bucket": "stage-state", "dynamodb_table": "stage-locks"}},
+		"/stage/app": {Backend: "s3", Config: map[string]interface{}{"bucket": "stage-state", "dynamodb_table": "stage-locks"}},
+		"/prod/vpc":  {Backend: "s3", Config: map[string]interface{}{"bucket": "prod-state", "lock_table": "prod-locks"}},
+		"/prod/gcs":  {Backend: "gcs", Config: map[string]interface{}{"bucket": "prod-state"}},
+		"/prod/none": {Backend: "s3", Config: map[string]interface{}{"bucket": "prod-state"}},
+		"/local":     nil,
+	}
+
+	usage, err := lockTableUsage(logrus.NewEntry(logrus.New()), remoteStates)
+	require.NoError(t, err)
+
+	expected := LockTableUsage{
+		"stage-locks": {"/stage/app", "/stage/vpc"},
+		"prod-locks":  {"/prod/vpc"},
+	}
+	assert.Equal(t, expected, usage)
+}
+
+func TestLockTableUsageInconsistentTables(t *testing.T) {
+	t.Parallel()
+
+	remoteStates := map[string]*remote.RemoteState{
+		"/stage/vpc":        {Backend: "s3", Config: map[string]interface{}{"bucket": "state", "key": "vpc/terraform.tfstate", "dynamodb_table": "stage-locks"}},
+		"/stage/vpc-legacy": {Backend: "s3", Config: map[string]interface{}{"bucket": "state", "key": "vpc/terraform.tfstate", "dynamodb_table": "prod-locks"}},
+	}
+
+	_, err := lockTableUsage(logrus.NewEntry(logrus.New()), remoteStates)
+	require.Error(t, err)
+
+	inconsistentErr, ok := errors.Unwrap(err).(InconsistentLockTables)
+	require.True(t, ok)
+	assert.Equal(t, "s3://state/vpc/terraform.tfstate", inconsistentErr.State)
+	assert.Equal(t, map[string][]string{"stage-locks": {"/stage/vpc"}, "prod-locks": {"/stage/vpc-legacy"}}, inconsistentErr.Tables)
+}
+
+func TestLockTableUsageSharedBucket(t *testing.T) {
+	t.Parallel()
+
+	// The environments have their own lock tables, but share the state bucket
+	remoteStates := map[string]*remote.RemoteState{
+		"/stage/vpc": {Backend: "s3", Config: map[string]interface{}{"bucket": "state", "key": "stage/vpc/terraform.tfstate", "dynamodb_table": "stage-locks"}},
+		"/prod/vpc":  {Backend: "s3", Config: map[string]interface{}{"bucket": "state", "key": "prod/vpc/terraform.tfstate", "dynamodb_table": "prod-locks"}},
+	}
+
+	usage, err := lockTableUsage(logrus.NewEntry(logrus.New()), remoteStates)
+	require.NoError(t, err)
+	assert.Equal(t, LockTableUsage{"stage-locks": {"/stage/vpc"}, "prod-locks": {"/prod/vpc"}}, usage)
+}
+
+func TestFindLockTableUsageWithoutS3(t *testing.T) {
+	t.Parallel()
+
+	tempFolder := t.TempDir()
+	configs := map[string]string{
+		"local": `
+remote_state {
+  backend = "local"
+  config = {
+    path = "terraform.tfstate"
+  }
+}
+`,
+		// Resolvable only when the module runs
+		"gcs": `
+dependency "local" {
+  config_path = "../local"
+}
+
+remote_state {
+  backend = "gcs"
+  config = {
+    bucket = dependency.local.outputs.bucket
+  }
+}
+`,
+		"none": ``,
+	}
+	for name, content := range configs {
+		require.NoError(t, os.MkdirAll(filepath.Join(tempFolder, name), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(tempFolder, name, config.DefaultTerragruntConfigPath), []byte(content), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempFolder, name, "main.tf"), []byte(""), 0644))
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(tempFolder, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = tempFolder
+	opts.TerraformCommand = "apply"
+
+	stack, err := FindStackInSubfolders(context.Background(), opts, nil)
+	require.NoError(t, err)
+	require.Len(t, stack.Modules, 3)
+
+	usage, err := stack.FindLockTableUsage(opts.Logger)
+	require.NoError(t, err)
+	assert.Empty(t, usage)
+
+	for _, module := range stack.Modules {
+		if filepath.Base(module.Path) == "gcs" {
+			assert.Equal(t, []string{"../local"}, module.Config.Dependencies.Paths)
+		}
+	}
+}
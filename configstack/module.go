@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/gruntwork-io/terragrunt/telemetry"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/go-commons/files"
 	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/config/hclparse"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/gruntwork-io/terragrunt/util"
@@ -32,6 +34,15 @@ type TerraformModule struct {
 	TerragruntOptions    *options.TerragruntOptions
 	AssumeAlreadyApplied bool
 	FlagExcluded         bool
+
+	// Shared by the modules run in the workspaces of the same module, so that they don't run at the same time
+	runLock *sync.Mutex
+
+	// The paths of the dependencies only set by run_after, which order the runs without being dependencies
+	runAfterOnly map[string]bool
+
+	// The run group of the module, as logged by LogModuleDeployOrder, or 0 if the modules are not run as a stack
+	runGroup int
 }
 
 // Render this module as a human-readable string
@@ -126,11 +137,80 @@ func ResolveTerraformModules(ctx context.Context, terragruntConfigPaths []string
 		return nil, err
 	}
 
+	err = telemetry.Telemetry(ctx, terragruntOptions, "flag_excluded_by_config", map[string]interface{}{
+		"working_dir": terragruntOptions.WorkingDir,
+	}, func(childCtx context.Context) error {
+		includedModulesWithExcluded = flagExcludedByConfig(includedModulesWithExcluded, terragruntOptions)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = telemetry.Telemetry(ctx, terragruntOptions, "flag_modules_not_owned", map[string]interface{}{
+		"working_dir": terragruntOptions.WorkingDir,
+	}, func(childCtx context.Context) error {
+		includedModulesWithExcluded = flagModulesNotOwned(includedModulesWithExcluded, terragruntOptions)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = telemetry.Telemetry(ctx, terragruntOptions, "flag_modules_by_tags", map[string]interface{}{
+		"working_dir": terragruntOptions.WorkingDir,
+	}, func(childCtx context.Context) error {
+		includedModulesWithExcluded = flagModulesByTags(includedModulesWithExcluded, terragruntOptions)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = telemetry.Telemetry(ctx, terragruntOptions, "flag_modules_not_changed_since", map[string]interface{}{
+		"working_dir": terragruntOptions.WorkingDir,
+	}, func(childCtx context.Context) error {
+		result, err := flagModulesNotChangedSince(childCtx, includedModulesWithExcluded, terragruntOptions)
+		if err != nil {
+			return err
+		}
+		includedModulesWithExcluded = result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = telemetry.Telemetry(ctx, terragruntOptions, "flag_modules_not_failed", map[string]interface{}{
+		"working_dir": terragruntOptions.WorkingDir,
+	}, func(childCtx context.Context) error {
+		result, err := flagModulesNotFailed(includedModulesWithExcluded, terragruntOptions)
+		if err != nil {
+			return err
+		}
+		includedModulesWithExcluded = result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var modulesWithAssumedApplied []*TerraformModule
+	err = telemetry.Telemetry(ctx, terragruntOptions, "flag_assumed_applied_dirs", map[string]interface{}{
+		"working_dir": terragruntOptions.WorkingDir,
+	}, func(childCtx context.Context) error {
+		modulesWithAssumedApplied = flagAssumedAppliedDirs(includedModulesWithExcluded, terragruntOptions)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	var finalModules []*TerraformModule
 	err = telemetry.Telemetry(ctx, terragruntOptions, "flag_modules_that_dont_include", map[string]interface{}{
 		"working_dir": terragruntOptions.WorkingDir,
 	}, func(childCtx context.Context) error {
-		result, err := flagModulesThatDontInclude(includedModulesWithExcluded, terragruntOptions)
+		result, err := flagModulesThatDontInclude(modulesWithAssumedApplied, terragruntOptions)
 		if err != nil {
 			return err
 		}
@@ -141,9 +221,96 @@ func ResolveTerraformModules(ctx context.Context, terragruntConfigPaths []string
 		return nil, err
 	}
 
+	err = telemetry.Telemetry(ctx, terragruntOptions, "add_remote_dependencies", map[string]interface{}{
+		"working_dir": terragruntOptions.WorkingDir,
+	}, func(childCtx context.Context) error {
+		finalModules = addRemoteDependencies(finalModules, terragruntOptions)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return finalModules, nil
 }
 
+// flagExcludedByConfig flags the modules whose exclude block holds as excluded, logging the reason of the exclusion.
+func flagExcludedByConfig(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
+	for _, module := range modules {
+		if module.FlagExcluded || !module.Config.Exclude.IsExcluded() {
+			continue
+		}
+		module.FlagExcluded = true
+		terragruntOptions.Logger.Infof("Excluding the module %s%s", module.Path, excludeReasonSuffix(module.Config.Exclude.Reason))
+	}
+	return modules
+}
+
+// flagModulesNotOwned flags the modules whose owner is not one of the owners given with --terragrunt-owner as
+// excluded, including the modules without an owner.
+func flagModulesNotOwned(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
+	if len(terragruntOptions.Owners) == 0 {
+		return modules
+	}
+
+	for _, module := range modules {
+		if !util.ListContainsElement(terragruntOptions.Owners, module.Config.Owner) {
+			module.FlagExcluded = true
+		}
+	}
+	return modules
+}
+
+// flagModulesByTags flags the modules without any of the tags given with --terragrunt-include-tags, if any, and the
+// modules with one of the tags given with --terragrunt-exclude-tags as excluded, the tags being set by the tags
+// attribute of their config.
+func flagModulesByTags(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
+	if len(terragruntOptions.IncludeTags) == 0 && len(terragruntOptions.ExcludeTags) == 0 {
+		return modules
+	}
+
+	for _, module := range modules {
+		if len(terragruntOptions.IncludeTags) > 0 && !hasAnyTag(module, terragruntOptions.IncludeTags) {
+			module.FlagExcluded = true
+		}
+		if hasAnyTag(module, terragruntOptions.ExcludeTags) {
+			module.FlagExcluded = true
+		}
+	}
+	return modules
+}
+
+func hasAnyTag(module *TerraformModule, tags []string) bool {
+	for _, tag := range tags {
+		if util.ListContainsElement(module.Config.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludedByConfig returns the reasons of the exclude blocks of the given modules which are excluded by them, by module
+// path.
+func excludedByConfig(modules []*TerraformModule) map[string]string {
+	var reasons map[string]string
+	for _, module := range modules {
+		if module.FlagExcluded && module.Config.Exclude.IsExcluded() {
+			if reasons == nil {
+				reasons = map[string]string{}
+			}
+			reasons[module.Path] = module.Config.Exclude.Reason
+		}
+	}
+	return reasons
+}
+
+func excludeReasonSuffix(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return ": " + reason
+}
+
 // flagExcludedDirs iterates over a module slice and flags all entries as excluded, which should be ignored via the terragrunt-exclude-dir CLI flag.
 func flagExcludedDirs(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
 	for _, module := range modules {
@@ -163,6 +330,19 @@ func flagExcludedDirs(modules []*TerraformModule, terragruntOptions *options.Ter
 	return modules
 }
 
+// flagAssumedAppliedDirs iterates over a module slice and flags all entries specified via the terragrunt-assume-applied
+// CLI flag as already applied, so that their outputs are used but they are not run.
+func flagAssumedAppliedDirs(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
+	for _, module := range modules {
+		if findModuleInPath(module, terragruntOptions.AssumeAppliedDirs) {
+			terragruntOptions.Logger.Infof("Module %s is assumed to be already applied because of --terragrunt-assume-applied", module.Path)
+			module.AssumeAlreadyApplied = true
+		}
+	}
+
+	return modules
+}
+
 // flagIncludedDirs iterates over a module slice and flags all entries not in the list specified via the terragrunt-include-dir CLI flag as excluded.
 func flagIncludedDirs(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
 
@@ -184,12 +364,24 @@ func flagIncludedDirs(modules []*TerraformModule, terragruntOptions *options.Ter
 		}
 	}
 
+	if terragruntOptions.IncludeDependencyClosure {
+		return flagDependencyClosureAssumedApplied(modules, terragruntOptions)
+	}
+
 	// Mark all affected dependencies as included before proceeding if not in strict include mode.
 	if !terragruntOptions.StrictInclude {
 		for _, module := range modules {
 			if !module.FlagExcluded {
 				for _, dependency := range module.Dependencies {
+					if module.runAfterOnly[dependency.Path] {
+						continue
+					}
 					dependency.FlagExcluded = false
+					// The outputs of read-only dependencies are read, but the command is not run against them
+					if terragruntOptions.ReadOnlyExternalDependencies && !findModuleInPath(dependency, terragruntOptions.IncludeDirs) && !dependency.AssumeAlreadyApplied {
+						terragruntOptions.Logger.Infof("Module %s is a dependency of module %s outside of --terragrunt-include-dir, reading its outputs without running it because of --terragrunt-read-only-external-dependencies", dependency.Path, module.Path)
+						dependency.AssumeAlreadyApplied = true
+					}
 				}
 			}
 		}
@@ -354,23 +546,37 @@ func resolveTerraformModule(terragruntConfigPath string, moduleMap map[string]*T
 		return &TerraformModule{Path: modulePath, TerragruntOptions: opts, FlagExcluded: true}, nil
 	}
 
-	configContext := config.NewParsingContext(context.Background(), opts).WithDecodeList(
+	decodeList := []config.PartialDecodeSectionType{
 		// Need for initializing the modules
 		config.TerraformSource,
 
 		// Need for parsing out the dependencies
 		config.DependenciesBlock,
 		config.DependencyBlock,
-	)
+
+		// Need for the scheduling priority
+		config.TerragruntFlags,
+	}
 
 	// We only partially parse the config, only using the pieces that we need in this section. This config will be fully
 	// parsed at a later stage right before the action is run. This is to delay interpolation of functions until right
 	// before we call out to terraform.
-	terragruntConfig, err := config.PartialParseConfigFile(
-		configContext,
-		terragruntConfigPath,
-		includeConfig,
-	)
+	var terragruntConfig *config.TerragruntConfig
+	if resolvesRemoteState(terragruntOptions.TerraformCommand) {
+		// Need for checking the lock tables, see FindLockTableUsage
+		configContext := config.NewParsingContext(context.Background(), opts).WithDecodeList(append(decodeList, config.RemoteStateBlock)...)
+		// The diagnostics are logged in the warning below
+		configContext.ParserOptions = append(configContext.ParserOptions, hclparse.WithLogger(nil))
+		terragruntConfig, err = config.PartialParseConfigFile(configContext, terragruntConfigPath, includeConfig)
+		if err != nil {
+			// The remote_state block may not be resolvable until the module runs, e.g. reading a dependency
+			terragruntOptions.Logger.Warnf("Not checking the lock table of the module %s, as its remote_state block could not be resolved: %v", modulePath, err)
+		}
+	}
+	if terragruntConfig == nil {
+		configContext := config.NewParsingContext(context.Background(), opts).WithDecodeList(decodeList...)
+		terragruntConfig, err = config.PartialParseConfigFile(configContext, terragruntConfigPath, includeConfig)
+	}
 	if err != nil {
 		return nil, errors.WithStackTrace(ErrorProcessingModule{UnderlyingError: err, HowThisModuleWasFound: howThisModuleWasFound, ModulePath: terragruntConfigPath})
 	}
@@ -411,6 +617,9 @@ func resolveTerraformModule(terragruntConfigPath string, moduleMap map[string]*T
 		opts.OutputPrefix = fmt.Sprintf("[%v] ", modulePath)
 	}
 
+	// The warnings suppressed by the config of the module are suppressed for the module only
+	opts.SuppressWarnings = append(opts.SuppressWarnings, terragruntConfig.SuppressWarnings...)
+
 	return &TerraformModule{Path: modulePath, Config: *terragruntConfig, TerragruntOptions: opts}, nil
 }
 
@@ -510,11 +719,17 @@ func resolveDependenciesForModule(ctx context.Context, module *TerraformModule,
 }
 
 // Confirm with the user whether they want Terragrunt to assume the given dependency of the given module is already
-// applied. If the user selects "yes", then Terragrunt will apply that module as well.
+// applied. If the user selects "yes", then Terragrunt will apply that module as well. The external dependencies are
+// always assumed to be already applied with --terragrunt-read-only-external-dependencies.
 // Note that we skip the prompt for `run-all destroy` calls. Given the destructive and irreversible nature of destroy, we don't
 // want to provide any risk to the user of accidentally destroying an external dependency unless explicitly included
 // with the --terragrunt-include-external-dependencies or --terragrunt-include-dir flags.
 func confirmShouldApplyExternalDependency(module *TerraformModule, dependency *TerraformModule, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	if terragruntOptions.ReadOnlyExternalDependencies {
+		terragruntOptions.Logger.Debugf("The --terragrunt-read-only-external-dependencies flag is set, so will read the outputs of module %s, which is a dependency of module %s, without running this command against it.", dependency.Path, module.Path)
+		return false, nil
+	}
+
 	if terragruntOptions.IncludeExternalDependencies {
 		terragruntOptions.Logger.Debugf("The --terragrunt-include-external-dependencies flag is set, so automatically including all external dependencies, and will run this command against module %s, which is a dependency of module %s.", dependency.Path, module.Path)
 		return true, nil
@@ -564,7 +779,7 @@ func crosslinkDependencies(moduleMap map[string]*TerraformModule, canonicalTerra
 			return modules, err
 		}
 
-		module.Dependencies = dependencies
+		module.Dependencies = append(dependencies, getRunAfterForModule(module, moduleMap, dependencies)...)
 		modules = append(modules, module)
 	}
 
@@ -604,6 +819,50 @@ func getDependenciesForModule(module *TerraformModule, moduleMap map[string]*Ter
 	return dependencies, nil
 }
 
+// getRunAfterForModule returns the modules the given module runs after, as set by its run_after attribute, which are not
+// already among the given dependencies of the module. Unlike the dependencies, the modules outside of the stack are
+// ignored rather than resolved as external dependencies, as run_after only orders the modules run together.
+func getRunAfterForModule(module *TerraformModule, moduleMap map[string]*TerraformModule, dependencies []*TerraformModule) []*TerraformModule {
+	var runAfter []*TerraformModule
+	for _, runAfterPath := range module.Config.RunAfter {
+		runAfterModulePath, err := util.CanonicalPath(runAfterPath, module.Path)
+		if err != nil {
+			continue
+		}
+
+		if files.FileExists(runAfterModulePath) && !files.IsDir(runAfterModulePath) {
+			runAfterModulePath = filepath.Dir(runAfterModulePath)
+		}
+
+		runAfterModule, foundModule := moduleMap[runAfterModulePath]
+		if !foundModule {
+			if module.TerragruntOptions != nil {
+				module.TerragruntOptions.Logger.Debugf("Module %s runs after %s, which is not part of the stack, ignoring it", module.Path, runAfterPath)
+			}
+			continue
+		}
+
+		if module.runAfterOnly[runAfterModulePath] || containsModule(dependencies, runAfterModulePath) {
+			continue
+		}
+		if module.runAfterOnly == nil {
+			module.runAfterOnly = map[string]bool{}
+		}
+		module.runAfterOnly[runAfterModulePath] = true
+		runAfter = append(runAfter, runAfterModule)
+	}
+	return runAfter
+}
+
+func containsModule(modules []*TerraformModule, path string) bool {
+	for _, module := range modules {
+		if module.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
 // Return the keys for the given map in sorted order. This is used to ensure we always iterate over maps of modules
 // in a consistent order (Go does not guarantee iteration order for maps, and usually makes it random)
 func getSortedKeys(modules map[string]*TerraformModule) []string {
@@ -639,24 +898,7 @@ func FindWhereWorkingDirIsIncluded(ctx context.Context, terragruntOptions *optio
 	}
 
 	for _, dir := range pathsToCheck { // iterate over detected paths, build stacks and filter modules by working dir
-		dir += filepath.FromSlash("/")
-		cfgOptions, err := options.NewTerragruntOptionsWithConfigPath(dir)
-		if err != nil {
-			terragruntOptions.Logger.Debugf("Failed to build terragrunt options from %s %v", dir, err)
-			continue
-		}
-
-		cfgOptions.Env = terragruntOptions.Env
-		cfgOptions.LogLevel = terragruntOptions.LogLevel
-		cfgOptions.OriginalTerragruntConfigPath = terragruntOptions.OriginalTerragruntConfigPath
-		cfgOptions.TerraformCommand = terragruntOptions.TerraformCommand
-		cfgOptions.NonInteractive = true
-
-		var hook = NewForceLogLevelHook(logrus.DebugLevel)
-		cfgOptions.Logger.Logger.AddHook(hook)
-
-		// build stack from config directory
-		stack, err := FindStackInSubfolders(ctx, cfgOptions, terragruntConfig)
+		stack, err := findStackInDir(ctx, terragruntOptions, dir, terragruntConfig)
 		if err != nil {
 			// log error as debug since in some cases stack building may fail because parent files can be designed
 			// to work with relative paths from downstream modules
@@ -687,6 +929,26 @@ func FindWhereWorkingDirIsIncluded(ctx context.Context, terragruntOptions *optio
 	return matchedModules
 }
 
+// findStackInDir builds the stack of all the modules in the given dir, e.g. the root of the repo, without the filters of
+// the given options, and with its log messages forced to the debug level.
+func findStackInDir(ctx context.Context, terragruntOptions *options.TerragruntOptions, dir string, terragruntConfig *config.TerragruntConfig) (*Stack, error) {
+	cfgOptions, err := options.NewTerragruntOptionsWithConfigPath(dir + filepath.FromSlash("/"))
+	if err != nil {
+		return nil, err
+	}
+
+	cfgOptions.Env = terragruntOptions.Env
+	cfgOptions.LogLevel = terragruntOptions.LogLevel
+	cfgOptions.OriginalTerragruntConfigPath = terragruntOptions.OriginalTerragruntConfigPath
+	cfgOptions.TerraformCommand = terragruntOptions.TerraformCommand
+	cfgOptions.NonInteractive = true
+
+	var hook = NewForceLogLevelHook(logrus.DebugLevel)
+	cfgOptions.Logger.Logger.AddHook(hook)
+
+	return FindStackInSubfolders(ctx, cfgOptions, terragruntConfig)
+}
+
 // ForceLogLevelHook - log hook which can change log level for messages which contains specific substrings
 type ForceLogLevelHook struct {
 	TriggerLevels []logrus.Level
@@ -0,0 +1,154 @@
+package configstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The key of the modules that are not run, i.e. the excluded and assume applied ones, in the details of the module
+// groups.
+const skippedModuleGroupKey = "Skipped"
+
+// ModuleGroupDetails are the details of a module in the JSON module groups, for the schedulers running the modules
+// of the groups themselves.
+type ModuleGroupDetails struct {
+	Path string `json:"path"`
+	// The paths of the modules this module depends on directly, sorted
+	Dependencies []string `json:"dependencies"`
+	// The paths of the modules of the stack which depend directly on this module, sorted
+	Dependents []string `json:"dependents"`
+	Excluded   bool     `json:"excluded"`
+	// True if the module is outside of the dir of the stack, i.e. it is a dependency of a module of the stack
+	External      bool `json:"external"`
+	AssumeApplied bool `json:"assume_applied"`
+	// The owner of the module, as set by the owner attribute of its config
+	Owner string `json:"owner,omitempty"`
+	// How long the module took to run the last time, in seconds, if recorded in the module durations file
+	EstimatedDuration *float64 `json:"estimated_duration,omitempty"`
+}
+
+// orderedModuleGroups marshals the run groups as a JSON object keyed by `Group <number>`, with the groups in run order
+// instead of sorted by key as a map is, where `Group 10` comes before `Group 2`.
+type orderedModuleGroups struct {
+	groups []interface{}
+	// The modules that are not run, added after the groups if not empty
+	skipped []ModuleGroupDetails
+}
+
+func (groups orderedModuleGroups) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{")
+
+	writeEntry := func(key string, value interface{}) error {
+		if buf.Len() > 1 {
+			buf.WriteString(",")
+		}
+		keyJSON, err := json.Marshal(key)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		buf.Write(keyJSON)
+		buf.WriteString(":")
+		buf.Write(valueJSON)
+		return nil
+	}
+
+	for i, group := range groups.groups {
+		if err := writeEntry(fmt.Sprintf("Group %d", i+1), group); err != nil {
+			return nil, err
+		}
+	}
+	if len(groups.skipped) > 0 {
+		if err := writeEntry(skippedModuleGroupKey, groups.skipped); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// JsonModuleDeployOrderDetails returns the modules that will be deployed by the given command, in the order that the
+// operations happen, as JsonModuleDeployOrder does, but with the details of each module instead of its path. The
+// modules that are not run are listed after the groups, under the `Skipped` key.
+func (stack *Stack) JsonModuleDeployOrderDetails(terraformCommand string, durations ModuleDurations) (string, error) {
+	runGraph, err := stack.getModuleRunGraph(terraformCommand)
+	if err != nil {
+		return "", err
+	}
+
+	dependents := stack.moduleDependents()
+
+	groups := orderedModuleGroups{}
+	for _, group := range runGraph {
+		details := make([]ModuleGroupDetails, 0, len(group))
+		for _, module := range group {
+			details = append(details, stack.moduleGroupDetails(module, dependents, durations))
+		}
+		groups.groups = append(groups.groups, details)
+	}
+
+	for _, module := range stack.Modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			groups.skipped = append(groups.skipped, stack.moduleGroupDetails(module, dependents, durations))
+		}
+	}
+	sort.Slice(groups.skipped, func(i, j int) bool {
+		return groups.skipped[i].Path < groups.skipped[j].Path
+	})
+
+	return marshalModuleGroups(groups)
+}
+
+// moduleDependents returns the paths of the modules of the stack which depend directly on each module, by module path.
+func (stack *Stack) moduleDependents() map[string][]string {
+	dependents := map[string][]string{}
+	for _, module := range stack.Modules {
+		for _, dependency := range module.Dependencies {
+			dependents[dependency.Path] = append(dependents[dependency.Path], module.Path)
+		}
+	}
+	return dependents
+}
+
+func (stack *Stack) moduleGroupDetails(module *TerraformModule, dependents map[string][]string, durations ModuleDurations) ModuleGroupDetails {
+	details := ModuleGroupDetails{
+		Path:          module.Path,
+		Dependencies:  []string{},
+		Dependents:    append([]string{}, dependents[module.Path]...),
+		Excluded:      module.FlagExcluded,
+		External:      !util.HasPathPrefix(module.Path, stack.Path),
+		AssumeApplied: module.AssumeAlreadyApplied,
+		Owner:         module.Config.Owner,
+	}
+
+	for _, dependency := range module.Dependencies {
+		details.Dependencies = append(details.Dependencies, dependency.Path)
+	}
+	sort.Strings(details.Dependencies)
+	sort.Strings(details.Dependents)
+
+	if relPath, err := util.GetPathRelativeTo(module.Path, stack.Path); err == nil {
+		if duration, ok := durations[relPath]; ok {
+			details.EstimatedDuration = &duration
+		}
+	}
+	return details
+}
+
+func marshalModuleGroups(groups orderedModuleGroups) (string, error) {
+	j, err := json.MarshalIndent(groups, "", "  ")
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return string(j), nil
+}
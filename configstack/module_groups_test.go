@@ -0,0 +1,74 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJsonModuleDeployOrderGroupsInRunOrder(t *testing.T) {
+	t.Parallel()
+
+	// A chain of 11 modules, each depending on the previous one, runs in 11 groups
+	stack := &Stack{Path: "/stage/mystack"}
+	var previous *TerraformModule
+	for i := 1; i <= 11; i++ {
+		module := &TerraformModule{Path: filepath.Join(stack.Path, fmt.Sprintf("module-%02d", i))}
+		if previous != nil {
+			module.Dependencies = []*TerraformModule{previous}
+		}
+		stack.Modules = append(stack.Modules, module)
+		previous = module
+	}
+
+	js, err := stack.JsonModuleDeployOrder("apply")
+	require.NoError(t, err)
+
+	assert.Less(t, strings.Index(js, `"Group 9"`), strings.Index(js, `"Group 10"`))
+	assert.Less(t, strings.Index(js, `"Group 10"`), strings.Index(js, `"Group 11"`))
+
+	var groups map[string][]string
+	require.NoError(t, json.Unmarshal([]byte(js), &groups))
+	assert.Equal(t, []string{filepath.Join(stack.Path, "module-10")}, groups["Group 10"])
+}
+
+func TestJsonModuleDeployOrderDetails(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+	// An external dependency of mysql, outside of the dir of the stack
+	kms := &TerraformModule{Path: "/mgmt/kms"}
+	stack.Modules[3].Dependencies = append(stack.Modules[3].Dependencies, kms)
+	stack.Modules = append(stack.Modules, kms)
+
+	js, err := stack.JsonModuleDeployOrderDetails("apply", ModuleDurations{"vpc": 12.5})
+	require.NoError(t, err)
+
+	var groups map[string][]ModuleGroupDetails
+	require.NoError(t, json.Unmarshal([]byte(js), &groups))
+
+	vpcDuration := 12.5
+	assert.Equal(t, map[string][]ModuleGroupDetails{
+		"Group 1": {
+			{Path: "/mgmt/kms", Dependencies: []string{}, Dependents: []string{"/stage/mystack/mysql"}, External: true},
+			{Path: "/stage/mystack/vpc", Dependencies: []string{"/stage/mystack/account-baseline"}, Dependents: []string{"/stage/mystack/lambda", "/stage/mystack/mysql", "/stage/mystack/redis"}, EstimatedDuration: &vpcDuration},
+		},
+		"Group 2": {
+			{Path: "/stage/mystack/mysql", Dependencies: []string{"/mgmt/kms", "/stage/mystack/vpc"}, Dependents: []string{"/stage/mystack/myapp"}},
+			{Path: "/stage/mystack/redis", Dependencies: []string{"/stage/mystack/vpc"}, Dependents: []string{"/stage/mystack/myapp"}},
+		},
+		"Group 3": {
+			{Path: "/stage/mystack/myapp", Dependencies: []string{"/stage/mystack/mysql", "/stage/mystack/redis"}, Dependents: []string{}},
+		},
+		"Skipped": {
+			{Path: "/stage/mystack/account-baseline", Dependencies: []string{}, Dependents: []string{"/stage/mystack/vpc"}, Excluded: true},
+			{Path: "/stage/mystack/lambda", Dependencies: []string{"/stage/mystack/vpc"}, Dependents: []string{}, AssumeApplied: true},
+		},
+	}, groups)
+	assert.Less(t, strings.Index(js, `"Group 3"`), strings.Index(js, `"Skipped"`))
+}
@@ -0,0 +1,175 @@
+package configstack
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"golang.org/x/term"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The themes of the module prefixes, mapping to the ANSI color codes the prefixes cycle through.
+const (
+	PrefixThemeDefault = "default"
+	PrefixThemeBright  = "bright"
+	PrefixThemeNone    = "none"
+)
+
+var prefixThemes = map[string][]string{
+	PrefixThemeDefault: {"36", "32", "33", "34", "35", "31"},
+	PrefixThemeBright:  {"96", "92", "93", "94", "95", "91"},
+	PrefixThemeNone:    nil,
+}
+
+// Args that make terraform print output meant to be parsed, which must not be prefixed.
+var machineReadableOutputArgs = []string{"-json", "-raw"}
+
+// modulePrefixWriter prefixes every line written by a module. Lines are only written once they are complete, so that
+// the lines of modules running concurrently never get mixed up, even if a module writes a line in several chunks.
+type modulePrefixWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	prefix string
+	line   []byte
+}
+
+func (prefixWriter *modulePrefixWriter) Write(data []byte) (int, error) {
+	prefixWriter.mutex.Lock()
+	defer prefixWriter.mutex.Unlock()
+
+	var buf bytes.Buffer
+	for _, b := range data {
+		prefixWriter.line = append(prefixWriter.line, b)
+		if b == '\n' {
+			buf.WriteString(prefixWriter.prefix)
+			buf.Write(prefixWriter.line)
+			prefixWriter.line = prefixWriter.line[:0]
+		}
+	}
+
+	if buf.Len() > 0 {
+		if _, err := prefixWriter.writer.Write(buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// Flush writes the last line of the module, if it did not end with a newline.
+func (prefixWriter *modulePrefixWriter) Flush() error {
+	prefixWriter.mutex.Lock()
+	defer prefixWriter.mutex.Unlock()
+
+	if len(prefixWriter.line) == 0 {
+		return nil
+	}
+
+	_, err := fmt.Fprintf(prefixWriter.writer, "%s%s\n", prefixWriter.prefix, prefixWriter.line)
+	prefixWriter.line = prefixWriter.line[:0]
+	return err
+}
+
+// setModuleOutputPrefixes prefixes every line of terraform output of each module with the path of the module, colored
+// the same way on every run, so that the interleaved output of modules running concurrently can be told apart. It
+// returns the function flushing the output left in the prefix writers once the run finishes.
+func (stack *Stack) setModuleOutputPrefixes(terragruntOptions *options.TerragruntOptions) (func(), error) {
+	colors, ok := prefixThemes[terragruntOptions.PrefixTheme]
+	if !ok {
+		return nil, errors.WithStackTrace(UnknownPrefixTheme(terragruntOptions.PrefixTheme))
+	}
+
+	var prefixWriters []*modulePrefixWriter
+	flush := func() {
+		for _, prefixWriter := range prefixWriters {
+			if err := prefixWriter.Flush(); err != nil {
+				terragruntOptions.Logger.Debugf("Failed to flush the output of a module: %v", err)
+			}
+		}
+	}
+
+	if !shouldPrefixModuleOutput(terragruntOptions, stack.Modules) {
+		return flush, nil
+	}
+
+	if terragruntOptions.DisableLogColors || util.ListContainsElement(terragruntOptions.TerraformCliArgs, terraform.FlagNameNoColor) {
+		colors = nil
+	}
+
+	for _, module := range stack.Modules {
+		path, err := util.GetPathRelativeTo(module.Path, terragruntOptions.WorkingDir)
+		if err != nil {
+			path = module.Path
+		}
+
+		plainPrefix := fmt.Sprintf("[%s] ", path)
+		coloredPrefix := plainPrefix
+		if len(colors) > 0 {
+			pathHash := fnv.New32a()
+			pathHash.Write([]byte(path)) //nolint:errcheck
+			coloredPrefix = fmt.Sprintf("\x1b[%sm%s\x1b[0m", colors[pathHash.Sum32()%uint32(len(colors))], plainPrefix)
+		}
+
+		moduleOptions := module.TerragruntOptions
+		writer := &modulePrefixWriter{writer: moduleOptions.Writer, prefix: prefixFor(moduleOptions.Writer, plainPrefix, coloredPrefix)}
+		errWriter := &modulePrefixWriter{writer: moduleOptions.ErrWriter, prefix: prefixFor(moduleOptions.ErrWriter, plainPrefix, coloredPrefix)}
+		moduleOptions.Writer, moduleOptions.ErrWriter = writer, errWriter
+		prefixWriters = append(prefixWriters, writer, errWriter)
+	}
+
+	return flush, nil
+}
+
+// shouldPrefixModuleOutput returns true if the output of the modules should get prefixed, which is not done for a
+// single module, for output meant to be parsed or if the output is already prefixed by --terragrunt-include-module-prefix.
+func shouldPrefixModuleOutput(terragruntOptions *options.TerragruntOptions, modules []*TerraformModule) bool {
+	if terragruntOptions.NoPrefix || terragruntOptions.IncludeModulePrefix || len(modules) < 2 {
+		return false
+	}
+
+	if terragruntOptions.StructuredLogs() && terragruntOptions.TerraformLogsToJson {
+		return false
+	}
+
+	for _, arg := range terragruntOptions.TerraformCliArgs {
+		if util.ListContainsElement(machineReadableOutputArgs, strings.ToLower(arg)) {
+			return false
+		}
+	}
+	return true
+}
+
+// prefixFor returns the colored prefix only if the given writer is a terminal.
+func prefixFor(writer io.Writer, plainPrefix, coloredPrefix string) string {
+	if file, ok := writer.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		return coloredPrefix
+	}
+	return plainPrefix
+}
+
+// PrefixThemeNames returns the names of all the module prefix themes.
+func PrefixThemeNames() []string {
+	var names []string
+	for name := range prefixThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Custom error types
+
+type UnknownPrefixTheme string
+
+func (err UnknownPrefixTheme) Error() string {
+	return fmt.Sprintf("Unknown module prefix theme %s, must be one of: %s", string(err), strings.Join(PrefixThemeNames(), ", "))
+}
@@ -0,0 +1,84 @@
+package configstack
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModulePrefixWriterOnlyWritesCompleteLines(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	writer := &modulePrefixWriter{writer: &output, prefix: "[a] "}
+
+	fmt.Fprint(writer, "first ")
+	assert.Empty(t, output.String())
+
+	fmt.Fprint(writer, "line\nsecond line\nthird")
+	assert.Equal(t, "[a] first line\n[a] second line\n", output.String())
+
+	require.NoError(t, writer.Flush())
+	assert.Equal(t, "[a] first line\n[a] second line\n[a] third\n", output.String())
+}
+
+func TestSetModuleOutputPrefixes(t *testing.T) {
+	t.Parallel()
+
+	newModule := func(path string, output *bytes.Buffer) *TerraformModule {
+		opts, err := options.NewTerragruntOptionsForTest(path)
+		require.NoError(t, err)
+		opts.Writer = output
+		opts.ErrWriter = output
+		return &TerraformModule{Path: path, TerragruntOptions: opts}
+	}
+
+	testCases := []struct {
+		name           string
+		noPrefix       bool
+		args           []string
+		expectedOutput string
+	}{
+		{"prefixed", false, []string{"plan"}, "[a] out\n"},
+		{"no-prefix", true, []string{"plan"}, "out\n"},
+		{"json", false, []string{"output", "-json"}, "out\n"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest("")
+			require.NoError(t, err)
+			opts.WorkingDir = "/stack"
+			opts.NoPrefix = testCase.noPrefix
+			opts.TerraformCliArgs = testCase.args
+
+			var outputA, outputB bytes.Buffer
+			stack := &Stack{Modules: []*TerraformModule{newModule("/stack/a", &outputA), newModule("/stack/b", &outputB)}}
+
+			flush, err := stack.setModuleOutputPrefixes(opts)
+			require.NoError(t, err)
+
+			fmt.Fprint(stack.Modules[0].TerragruntOptions.Writer, "out\n")
+			flush()
+			assert.Equal(t, testCase.expectedOutput, outputA.String())
+		})
+	}
+}
+
+func TestSetModuleOutputPrefixesUnknownTheme(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+	opts.PrefixTheme = "rainbow"
+
+	_, err = (&Stack{}).setModuleOutputPrefixes(opts)
+	assert.Error(t, err)
+}
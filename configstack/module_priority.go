@@ -0,0 +1,149 @@
+package configstack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// ModuleDurations maps module paths, relative to the working dir, to how long the module took to run the last time,
+// in seconds.
+type ModuleDurations map[string]float64
+
+// moduleSemaphore limits the number of modules running at the same time. Unlike a plain buffered channel, when a slot
+// frees up it is handed to the waiting module with the highest priority, so that slow modules are not left for last.
+type moduleSemaphore struct {
+	mutex     sync.Mutex
+	available int
+	waiting   []*semaphoreWaiter
+}
+
+type semaphoreWaiter struct {
+	module *runningModule
+	ready  chan struct{}
+}
+
+func newModuleSemaphore(parallelism int) *moduleSemaphore {
+	return &moduleSemaphore{available: parallelism}
+}
+
+// Acquire blocks until a slot is available for the given module.
+func (semaphore *moduleSemaphore) Acquire(module *runningModule) {
+	semaphore.mutex.Lock()
+	if semaphore.available > 0 {
+		semaphore.available--
+		semaphore.mutex.Unlock()
+		return
+	}
+
+	waiter := &semaphoreWaiter{module: module, ready: make(chan struct{})}
+	semaphore.waiting = append(semaphore.waiting, waiter)
+	semaphore.mutex.Unlock()
+
+	<-waiter.ready
+}
+
+// Release frees up the slot of a finished module, passing it on to the waiting module with the highest priority.
+func (semaphore *moduleSemaphore) Release() {
+	semaphore.mutex.Lock()
+	defer semaphore.mutex.Unlock()
+
+	if len(semaphore.waiting) == 0 {
+		semaphore.available++
+		return
+	}
+
+	next := 0
+	for i, waiter := range semaphore.waiting {
+		if waiter.module.runsBefore(semaphore.waiting[next].module) {
+			next = i
+		}
+	}
+
+	waiter := semaphore.waiting[next]
+	semaphore.waiting = append(semaphore.waiting[:next], semaphore.waiting[next+1:]...)
+	close(waiter.ready)
+}
+
+// runsBefore returns true if this module should be started before the other one when both are ready to run: modules
+// with a higher `priority` go first, and among modules with the same priority, the ones that took longer to run the
+// last time go first.
+func (module *runningModule) runsBefore(other *runningModule) bool {
+	if module.Priority != other.Priority {
+		return module.Priority > other.Priority
+	}
+	return module.ExpectedDuration > other.ExpectedDuration
+}
+
+// Set the scheduling priority and expected duration of the given modules from their config and the recorded durations.
+func setSchedulingHints(modules map[string]*runningModule, durations ModuleDurations, workingDir string) {
+	for _, module := range modules {
+		if module.Module.Config.Priority != nil {
+			module.Priority = *module.Module.Config.Priority
+		}
+
+		if relPath, err := util.GetPathRelativeTo(module.Module.Path, workingDir); err == nil {
+			module.ExpectedDuration = time.Duration(durations[relPath] * float64(time.Second))
+		}
+	}
+}
+
+// ReadModuleDurations reads the module durations recorded by a previous run from the given file. A missing file is not
+// an error, as there is nothing recorded before the first run.
+func ReadModuleDurations(path string) (ModuleDurations, error) {
+	durations := ModuleDurations{}
+	if !util.FileExists(path) {
+		return durations, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	if err := json.Unmarshal(content, &durations); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return durations, nil
+}
+
+// Record how long each module that ran successfully took into the given durations file, keeping the durations of the
+// modules that did not run this time.
+func writeModuleDurations(path string, modules map[string]*runningModule, terragruntOptions *options.TerragruntOptions) error {
+	durations, err := ReadModuleDurations(path)
+	if err != nil {
+		return err
+	}
+
+	for _, module := range modules {
+		if module.Err != nil || module.Module.AssumeAlreadyApplied || module.Duration == 0 {
+			continue
+		}
+
+		relPath, err := util.GetPathRelativeTo(module.Module.Path, terragruntOptions.WorkingDir)
+		if err != nil {
+			return err
+		}
+		durations[relPath] = module.Duration.Seconds()
+	}
+
+	content, err := json.MarshalIndent(durations, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package configstack
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleSemaphoreStartsHighestPriorityFirst(t *testing.T) {
+	t.Parallel()
+
+	semaphore := newModuleSemaphore(1)
+	semaphore.Acquire(&runningModule{})
+
+	low := &runningModule{Module: &TerraformModule{Path: "low"}, Priority: 1}
+	slow := &runningModule{Module: &TerraformModule{Path: "slow"}, Priority: 5, ExpectedDuration: time.Hour}
+	high := &runningModule{Module: &TerraformModule{Path: "high"}, Priority: 5, ExpectedDuration: time.Minute}
+
+	var mutex sync.Mutex
+	var order []string
+	var waitGroup sync.WaitGroup
+
+	for _, module := range []*runningModule{low, high, slow} {
+		waitGroup.Add(1)
+		go func(module *runningModule) {
+			defer waitGroup.Done()
+			semaphore.Acquire(module)
+			mutex.Lock()
+			order = append(order, module.Module.Path)
+			mutex.Unlock()
+			semaphore.Release()
+		}(module)
+	}
+
+	// Wait for all the modules to queue up before freeing the only slot
+	require.Eventually(t, func() bool {
+		semaphore.mutex.Lock()
+		defer semaphore.mutex.Unlock()
+		return len(semaphore.waiting) == 3
+	}, time.Second, time.Millisecond)
+	semaphore.Release()
+
+	waitGroup.Wait()
+	assert.Equal(t, []string{"slow", "high", "low"}, order)
+}
+
+func TestRunModulesRecordsDurations(t *testing.T) {
+	t.Parallel()
+
+	durationsFile := filepath.Join(t.TempDir(), "durations.json")
+
+	opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.ModuleDurationsFile = durationsFile
+
+	priority := 10
+	var mutex sync.Mutex
+	var order []string
+	newModule := func(path string, modulePriority *int) *TerraformModule {
+		moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			order = append(order, path)
+			return nil
+		}
+		return &TerraformModule{Path: path, Config: config.TerragruntConfig{Priority: modulePriority}, TerragruntOptions: moduleOpts}
+	}
+
+	modules := []*TerraformModule{newModule("/live/a", nil), newModule("/live/b", &priority)}
+	require.NoError(t, RunModules(context.Background(), opts, modules, 1))
+
+	durations, err := ReadModuleDurations(durationsFile)
+	require.NoError(t, err)
+	assert.Contains(t, durations, "a")
+	assert.Contains(t, durations, "b")
+	assert.Len(t, order, 2)
+}
@@ -0,0 +1,174 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// ModuleReport is the report of a run of run-all written to --terragrunt-report-file, for the CI pipelines to show the
+// result of each module.
+type ModuleReport struct {
+	RunID      string              `json:"run_id"`
+	Command    string              `json:"command"`
+	WorkingDir string              `json:"working_dir"`
+	Modules    []*ModuleReportItem `json:"modules"`
+}
+
+// ModuleReportItem is the result of a module in the report of a run of run-all.
+type ModuleReportItem struct {
+	// The path of the module, relative to the working dir
+	Path    string `json:"path"`
+	Outcome string `json:"outcome"`
+	// The run group of the module, as logged by LogModuleDeployOrder, omitted if the modules are not run as a stack
+	RunGroup   int        `json:"run_group,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// How many times the terraform command of the module was run, with the retries, or 0 if the module did not run
+	Attempts int64 `json:"attempts"`
+	// The exit code of the module, omitted if the module did not run
+	ExitCode *int `json:"exit_code,omitempty"`
+	// The first line of the error of the module
+	Error string `json:"error,omitempty"`
+	// The addresses of the resources whose state the refresh of the module updated, in the report of run-all refresh
+	RefreshedResources []string `json:"refreshed_resources,omitempty"`
+	// The last run of the module in its Terraform Cloud workspace, with the execution backend terraform_cloud
+	TerraformCloudRun *options.TerraformCloudRun `json:"terraform_cloud_run,omitempty"`
+}
+
+// newModuleReport returns the report of the given modules which ran in the run with the given options.
+func newModuleReport(opts *options.TerragruntOptions, modules map[string]*runningModule) (*ModuleReport, error) {
+	report := &ModuleReport{RunID: opts.RunID, Command: opts.TerraformCommand, WorkingDir: opts.WorkingDir, Modules: []*ModuleReportItem{}}
+
+	for _, module := range modules {
+		if module.OtherShard {
+			continue
+		}
+
+		relPath, err := util.GetPathRelativeTo(module.Module.Path, opts.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+
+		item := &ModuleReportItem{Path: relPath, Outcome: moduleOutcome(module), RunGroup: module.Module.runGroup, Error: errorSummary(module.Err)}
+		if !module.StartedAt.IsZero() {
+			startedAt, finishedAt := module.StartedAt.UTC(), module.FinishedAt.UTC()
+			item.StartedAt, item.FinishedAt = &startedAt, &finishedAt
+			item.Attempts = 1
+			if moduleOpts := module.Module.TerragruntOptions; moduleOpts != nil && moduleOpts.Retries != nil {
+				item.Attempts += moduleOpts.Retries.Load()
+			}
+			item.ExitCode = moduleExitCode(module.Err)
+			item.RefreshedResources = refreshedResources(module)
+			item.TerraformCloudRun = terraformCloudRun(module)
+		}
+		report.Modules = append(report.Modules, item)
+	}
+
+	sort.Slice(report.Modules, func(i, j int) bool {
+		return report.Modules[i].Path < report.Modules[j].Path
+	})
+	return report, nil
+}
+
+// writeModuleReport writes the report of the given modules to the given path, in JSON.
+func writeModuleReport(path string, opts *options.TerragruntOptions, modules map[string]*runningModule) error {
+	report, err := newModuleReport(opts, modules)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// ReadModuleReport reads the report of a run of run-all written to the given path.
+func ReadModuleReport(path string) (*ModuleReport, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	report := &ModuleReport{}
+	if err := json.Unmarshal(content, report); err != nil {
+		return nil, errors.WithStackTrace(InvalidModuleReport{Path: path, Err: err})
+	}
+	return report, nil
+}
+
+// trackTerraformCloudRuns sets the run of the Terraform Cloud workspace on the options of each of the given modules,
+// filled in by the modules which run in a workspace.
+func trackTerraformCloudRuns(modules map[string]*runningModule) {
+	for _, module := range modules {
+		if module.Module.TerragruntOptions != nil {
+			module.Module.TerragruntOptions.TerraformCloudRun = &options.TerraformCloudRun{}
+		}
+	}
+}
+
+// terraformCloudRun returns the run of the given module in its Terraform Cloud workspace, or nil if the module did not
+// trigger a run.
+func terraformCloudRun(module *runningModule) *options.TerraformCloudRun {
+	opts := module.Module.TerragruntOptions
+	if opts == nil || opts.TerraformCloudRun == nil || opts.TerraformCloudRun.ID == "" {
+		return nil
+	}
+	return opts.TerraformCloudRun
+}
+
+// moduleExitCode returns the exit code of the terraform command of a module which ran with the given error: 0 if it
+// succeeded, the exit code of the command if it failed, or 1 if it failed without running the command.
+func moduleExitCode(err error) *int {
+	exitCode := 0
+	if err != nil {
+		var exitCodeErr error
+		if exitCode, exitCodeErr = shell.GetExitCode(err); exitCodeErr != nil {
+			exitCode = 1
+		}
+	}
+	return &exitCode
+}
+
+// errorSummary returns the first line of the given error, or an empty string if the error is nil.
+func errorSummary(err error) string {
+	if err == nil {
+		return ""
+	}
+	summary, _, _ := strings.Cut(strings.TrimSpace(err.Error()), "\n")
+	return summary
+}
+
+// Custom error types
+
+type InvalidModuleReport struct {
+	Path string
+	Err  error
+}
+
+func (err InvalidModuleReport) Error() string {
+	return fmt.Sprintf("The report %s is not a valid report of a run: %v", err.Path, err.Err)
+}
+
+func (err InvalidModuleReport) Unwrap() error {
+	return err.Err
+}
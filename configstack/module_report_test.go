@@ -0,0 +1,77 @@
+package configstack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunModulesWritesModuleReport(t *testing.T) {
+	t.Parallel()
+
+	reportFile := filepath.Join(t.TempDir(), "reports", "report.json")
+
+	opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.ReportFile = reportFile
+	opts.RunID = "run-1"
+	opts.TerraformCommand = "apply"
+
+	newModule := func(path string, runGroup int, runErr error) *TerraformModule {
+		moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+			opts.Retries.Add(1)
+			*opts.TerraformCloudRun = options.TerraformCloudRun{ID: "run-" + filepath.Base(path), Workspace: filepath.Base(path), Status: "errored"}
+			return runErr
+		}
+		return &TerraformModule{Path: path, TerragruntOptions: moduleOpts, runGroup: runGroup}
+	}
+
+	vpc := newModule("/live/vpc", 1, fmt.Errorf("apply failed\nError: the VPC quota is exceeded"))
+	app := newModule("/live/app", 2, nil)
+	app.Dependencies = []*TerraformModule{vpc}
+
+	require.Error(t, RunModules(context.Background(), opts, []*TerraformModule{vpc, app}, 1))
+
+	content, err := os.ReadFile(reportFile)
+	require.NoError(t, err)
+
+	var report ModuleReport
+	require.NoError(t, json.Unmarshal(content, &report))
+	assert.Equal(t, "run-1", report.RunID)
+	assert.Equal(t, "apply", report.Command)
+	require.Len(t, report.Modules, 2)
+
+	skipped := report.Modules[0]
+	assert.Equal(t, &ModuleReportItem{Path: "app", Outcome: OutcomeSkippedUpstreamFailure, RunGroup: 2, Error: skipped.Error}, skipped)
+	assert.Contains(t, skipped.Error, "finished with an error: apply failed")
+
+	failed := report.Modules[1]
+	assert.Equal(t, "vpc", failed.Path)
+	assert.Equal(t, OutcomeFailed, failed.Outcome)
+	assert.Equal(t, 1, failed.RunGroup)
+	assert.Equal(t, int64(2), failed.Attempts)
+	require.NotNil(t, failed.ExitCode)
+	assert.Equal(t, 1, *failed.ExitCode)
+	assert.Equal(t, "apply failed", failed.Error)
+	assert.Equal(t, &options.TerraformCloudRun{ID: "run-vpc", Workspace: "vpc", Status: "errored"}, failed.TerraformCloudRun)
+	require.NotNil(t, failed.StartedAt)
+	require.NotNil(t, failed.FinishedAt)
+	assert.False(t, failed.FinishedAt.Before(*failed.StartedAt))
+}
+
+func TestModuleExitCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, *moduleExitCode(nil))
+	assert.Equal(t, 1, *moduleExitCode(fmt.Errorf("failed to read the config")))
+}
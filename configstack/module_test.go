@@ -126,7 +126,17 @@ func TestResolveTerraformModulesReadConfigFromParentConfig(t *testing.T) {
 		assert.NoError(t, err)
 
 		localsConfigs[name] = map[string]interface{}{
+			"command_aliases":               interface{}(nil),
+			"approval":                      interface{}(nil),
+			"exclude":                       interface{}(nil),
+			"approved_providers":            interface{}(nil),
+			"use_profile":                   "",
+			"execution":                     interface{}(nil),
+			"network":                       interface{}(nil),
+			"state_lock":                    interface{}(nil),
+			"source_verification":           interface{}(nil),
 			"dependencies":                  interface{}(nil),
+			"exec_policy":                   interface{}(nil),
 			"download_dir":                  "",
 			"generate":                      map[string]interface{}{},
 			"iam_assume_role_duration":      interface{}(nil),
@@ -134,13 +144,24 @@ func TestResolveTerraformModulesReadConfigFromParentConfig(t *testing.T) {
 			"iam_role":                      "",
 			"inputs":                        interface{}(nil),
 			"locals":                        cfg.Locals,
+			"priority":                      interface{}(nil),
+			"read_only":                     interface{}(nil),
 			"retry_max_attempts":            interface{}(nil),
 			"retry_sleep_interval_sec":      interface{}(nil),
 			"retryable_errors":              interface{}(nil),
 			"skip":                          false,
 			"terraform_binary":              "",
+			"terraform_parallelism":         nil,
 			"terraform_version_constraint":  "",
 			"terragrunt_version_constraint": "",
+			"workspace":                     "",
+			"owner":                         "",
+			"concurrency_group":             "",
+			"workspaces":                    interface{}(nil),
+			"run_after":                     interface{}(nil),
+			"suppress_warnings":             interface{}(nil),
+			"tags":                          interface{}(nil),
+			"prevent_destroy_paths":         interface{}(nil),
 		}
 	}
 
@@ -373,6 +394,47 @@ func TestResolveTerraformModulesTwoModulesWithDependenciesExcludedDirsWithDepend
 	assertModuleListsEqual(t, expected, actualModules)
 }
 
+func TestResolveTerraformModulesTwoModulesWithDependenciesAssumeAppliedDirs(t *testing.T) {
+	t.Parallel()
+
+	opts, _ := options.NewTerragruntOptionsForTest("running_module_test")
+	opts.AssumeAppliedDirs = []string{canonical(t, "../test/fixture-modules/module-a")}
+
+	moduleA := &TerraformModule{
+		Path:         canonical(t, "../test/fixture-modules/module-a"),
+		Dependencies: []*TerraformModule{},
+		Config: config.TerragruntConfig{
+			Terraform:       &config.TerraformConfig{Source: ptr("test")},
+			IsPartial:       true,
+			GenerateConfigs: make(map[string]codegen.GenerateConfig),
+		},
+		TerragruntOptions: opts.Clone(canonical(t, "../test/fixture-modules/module-a/"+config.DefaultTerragruntConfigPath)),
+	}
+
+	moduleC := &TerraformModule{
+		Path:         canonical(t, "../test/fixture-modules/module-c"),
+		Dependencies: []*TerraformModule{moduleA},
+		Config: config.TerragruntConfig{
+			Dependencies:    &config.ModuleDependencies{Paths: []string{"../module-a"}},
+			Terraform:       &config.TerraformConfig{Source: ptr("temp")},
+			IsPartial:       true,
+			GenerateConfigs: make(map[string]codegen.GenerateConfig),
+		},
+		TerragruntOptions: opts.Clone(canonical(t, "../test/fixture-modules/module-c/"+config.DefaultTerragruntConfigPath)),
+	}
+
+	configPaths := []string{"../test/fixture-modules/module-a/" + config.DefaultTerragruntConfigPath, "../test/fixture-modules/module-c/" + config.DefaultTerragruntConfigPath}
+
+	actualModules, actualErr := ResolveTerraformModules(context.Background(), configPaths, opts, nil, mockHowThesePathsWereFound)
+
+	// construct the expected list
+	moduleA.AssumeAlreadyApplied = true
+	expected := []*TerraformModule{moduleA, moduleC}
+
+	assert.Nil(t, actualErr, "Unexpected error: %v", actualErr)
+	assertModuleListsEqual(t, expected, actualModules)
+}
+
 func TestResolveTerraformModulesTwoModulesWithDependenciesExcludedDirsWithDependencyAndConflictingNaming(t *testing.T) {
 	t.Parallel()
 
@@ -1030,3 +1092,171 @@ func TestCircularDependencies(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, result)
 	}
 }
+
+func TestFlagExcludedByConfig(t *testing.T) {
+	t.Parallel()
+
+	vpc := &TerraformModule{Path: "/stage/vpc", Config: config.TerragruntConfig{Exclude: &config.ExcludeConfig{If: false, Reason: "Never excluded"}}}
+	mysql := &TerraformModule{Path: "/stage/mysql", Dependencies: []*TerraformModule{vpc}, Config: config.TerragruntConfig{Exclude: &config.ExcludeConfig{If: true, Reason: "The region is not enabled yet"}}}
+	redis := &TerraformModule{Path: "/stage/redis", Dependencies: []*TerraformModule{vpc}, Config: config.TerragruntConfig{Exclude: &config.ExcludeConfig{If: true}}}
+	app := &TerraformModule{Path: "/stage/app", Dependencies: []*TerraformModule{mysql, redis}}
+
+	modules := flagExcludedByConfig([]*TerraformModule{vpc, mysql, redis, app}, mockOptions)
+	assert.False(t, vpc.FlagExcluded)
+	assert.True(t, mysql.FlagExcluded)
+	assert.True(t, redis.FlagExcluded)
+	assert.False(t, app.FlagExcluded)
+
+	assert.Equal(t, map[string]string{"/stage/mysql": "The region is not enabled yet", "/stage/redis": ""}, excludedByConfig(modules))
+}
+
+func TestFlagModulesNotOwned(t *testing.T) {
+	t.Parallel()
+
+	vpc := &TerraformModule{Path: "/stage/vpc", Config: config.TerragruntConfig{Owner: "team-network"}}
+	mysql := &TerraformModule{Path: "/stage/mysql", Dependencies: []*TerraformModule{vpc}, Config: config.TerragruntConfig{Owner: "team-data"}}
+	app := &TerraformModule{Path: "/stage/app", Dependencies: []*TerraformModule{mysql}}
+
+	opts, err := options.NewTerragruntOptionsForTest("/stage/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.Owners = []string{"team-data", "team-apps"}
+
+	flagModulesNotOwned([]*TerraformModule{vpc, mysql, app}, opts)
+	assert.True(t, vpc.FlagExcluded)
+	assert.False(t, mysql.FlagExcluded)
+	assert.True(t, app.FlagExcluded)
+}
+
+func TestFlagModulesByTags(t *testing.T) {
+	t.Parallel()
+
+	newModules := func() []*TerraformModule {
+		return []*TerraformModule{
+			{Path: "/stage/vpc", Config: config.TerragruntConfig{Tags: []string{"networking", "prod"}}},
+			{Path: "/stage/dns", Config: config.TerragruntConfig{Tags: []string{"networking", "legacy"}}},
+			{Path: "/stage/app", Config: config.TerragruntConfig{Tags: []string{"apps"}}},
+			{Path: "/stage/tmp"},
+		}
+	}
+	excluded := func(modules []*TerraformModule) []string {
+		paths := []string{}
+		for _, module := range modules {
+			if module.FlagExcluded {
+				paths = append(paths, module.Path)
+			}
+		}
+		return paths
+	}
+
+	testCases := []struct {
+		includeTags []string
+		excludeTags []string
+		expected    []string
+	}{
+		{nil, nil, []string{}},
+		{[]string{"networking"}, nil, []string{"/stage/app", "/stage/tmp"}},
+		{[]string{"networking"}, []string{"legacy"}, []string{"/stage/dns", "/stage/app", "/stage/tmp"}},
+		{nil, []string{"networking"}, []string{"/stage/vpc", "/stage/dns"}},
+	}
+
+	for _, testCase := range testCases {
+		opts, err := options.NewTerragruntOptionsForTest("/stage/terragrunt.hcl")
+		require.NoError(t, err)
+		opts.IncludeTags = testCase.includeTags
+		opts.ExcludeTags = testCase.excludeTags
+
+		assert.Equal(t, testCase.expected, excluded(flagModulesByTags(newModules(), opts)), "include %v, exclude %v", testCase.includeTags, testCase.excludeTags)
+	}
+}
+
+func TestFlagIncludedDirsWithDependencyClosure(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("/stage/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.IncludeDirs = []string{"/stage/app"}
+	opts.IncludeDependencyClosure = true
+
+	vpc := &TerraformModule{Path: "/stage/vpc"}
+	db := &TerraformModule{Path: "/stage/db", Dependencies: []*TerraformModule{vpc}}
+	dns := &TerraformModule{Path: "/stage/dns"}
+	app := &TerraformModule{Path: "/stage/app", Dependencies: []*TerraformModule{db, dns}, runAfterOnly: map[string]bool{"/stage/dns": true}}
+	other := &TerraformModule{Path: "/stage/other", Dependencies: []*TerraformModule{vpc}}
+
+	flagIncludedDirs([]*TerraformModule{vpc, db, dns, app, other}, opts)
+
+	assert.False(t, app.FlagExcluded)
+	assert.False(t, app.AssumeAlreadyApplied)
+	// db and vpc are needed for their outputs, but not run
+	for _, module := range []*TerraformModule{db, vpc} {
+		assert.False(t, module.FlagExcluded, module.Path)
+		assert.True(t, module.AssumeAlreadyApplied, module.Path)
+	}
+	// app only runs after dns, and other is not a dependency of app
+	assert.True(t, dns.FlagExcluded)
+	assert.True(t, other.FlagExcluded)
+}
+
+func TestFlagIncludedDirsWithReadOnlyExternalDependencies(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("/stage/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.IncludeDirs = []string{"/stage/app", "/stage/db"}
+	opts.ReadOnlyExternalDependencies = true
+
+	vpc := &TerraformModule{Path: "/stage/vpc"}
+	db := &TerraformModule{Path: "/stage/db", Dependencies: []*TerraformModule{vpc}}
+	app := &TerraformModule{Path: "/stage/app", Dependencies: []*TerraformModule{db}}
+
+	flagIncludedDirs([]*TerraformModule{app, db, vpc}, opts)
+
+	for _, module := range []*TerraformModule{app, db, vpc} {
+		assert.False(t, module.FlagExcluded, module.Path)
+	}
+	// db is included itself, so it runs although it is a dependency of app
+	assert.False(t, app.AssumeAlreadyApplied)
+	assert.False(t, db.AssumeAlreadyApplied)
+	assert.True(t, vpc.AssumeAlreadyApplied)
+}
+
+func TestConfirmShouldApplyExternalDependencyReadOnly(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("/stage/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.ReadOnlyExternalDependencies = true
+	opts.IncludeExternalDependencies = true
+
+	shouldApply, err := confirmShouldApplyExternalDependency(&TerraformModule{Path: "/stage/app"}, &TerraformModule{Path: "/shared/vpc"}, opts)
+	require.NoError(t, err)
+	assert.False(t, shouldApply)
+}
+
+func TestCrosslinkDependenciesRunAfter(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("/stage/terragrunt.hcl")
+	require.NoError(t, err)
+
+	vpc := &TerraformModule{Path: "/stage/vpc", TerragruntOptions: opts}
+	dns := &TerraformModule{Path: "/stage/dns", TerragruntOptions: opts}
+	app := &TerraformModule{Path: "/stage/app", TerragruntOptions: opts, Config: config.TerragruntConfig{
+		Dependencies: &config.ModuleDependencies{Paths: []string{"../vpc"}},
+		// The module outside of the stack is ignored, the dependency is not added twice
+		RunAfter: []string{"../dns", "../vpc", "../../prod/dns"},
+	}}
+	moduleMap := map[string]*TerraformModule{vpc.Path: vpc, dns.Path: dns, app.Path: app}
+
+	_, err = crosslinkDependencies(moduleMap, []string{"/stage/vpc/terragrunt.hcl", "/stage/dns/terragrunt.hcl", "/stage/app/terragrunt.hcl"})
+	require.NoError(t, err)
+	assert.Equal(t, []*TerraformModule{vpc, dns}, app.Dependencies)
+	assert.Equal(t, map[string]bool{"/stage/dns": true}, app.runAfterOnly)
+
+	// The modules the included modules only run after are not included
+	opts.IncludeDirs = []string{"/stage/app"}
+	flagIncludedDirs([]*TerraformModule{vpc, dns, app}, opts)
+	assert.False(t, app.FlagExcluded)
+	assert.False(t, vpc.FlagExcluded)
+	assert.True(t, dns.FlagExcluded)
+}
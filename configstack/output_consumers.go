@@ -0,0 +1,148 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// OutputConsumer is a module which is not destroyed, but consumes the outputs of a module being destroyed, so that
+// destroying the module strands it.
+type OutputConsumer struct {
+	Module string
+	// The module being destroyed whose outputs the module consumes
+	Dependency string
+	// The key of the published outputs the module reads, or an empty string if the module reads the outputs from the
+	// state of the dependency
+	PublishedKey string
+}
+
+func (consumer *OutputConsumer) String() string {
+	if consumer.PublishedKey != "" {
+		return fmt.Sprintf("%s reads the outputs %s publishes to the key %s", consumer.Module, consumer.Dependency, consumer.PublishedKey)
+	}
+	return fmt.Sprintf("%s depends on %s", consumer.Module, consumer.Dependency)
+}
+
+// FindOutputConsumers returns the modules which consume the outputs of the modules of the stack which are destroyed,
+// but are not destroyed themselves: the modules of the repo of the working dir which depend on them, including the
+// modules of the stack which are excluded, and the modules which read the outputs they publish with export_outputs.
+func (stack *Stack) FindOutputConsumers(ctx context.Context, terragruntOptions *options.TerragruntOptions) ([]*OutputConsumer, error) {
+	var destroyed []*TerraformModule
+	for _, module := range stack.Modules {
+		if !module.FlagExcluded && !module.AssumeAlreadyApplied {
+			destroyed = append(destroyed, module)
+		}
+	}
+	if len(destroyed) == 0 {
+		return nil, nil
+	}
+
+	candidates := stack.Modules
+	if gitTopLevelDir, err := shell.GitTopLevelDir(ctx, terragruntOptions, terragruntOptions.WorkingDir); err == nil {
+		if repoStack, err := findStackInDir(ctx, terragruntOptions, gitTopLevelDir, nil); err == nil {
+			candidates = append(repoStack.Modules, stack.Modules...)
+		} else {
+			terragruntOptions.Logger.Debugf("Failed to build the stack of the repo %s to find the consumers of the outputs of the modules destroyed, finding them in the stack only: %v", gitTopLevelDir, err)
+		}
+	}
+
+	exports := map[string][]config.ExportOutputsConfig{}
+	for _, module := range destroyed {
+		moduleExports, err := parseExportOutputs(module)
+		if err != nil {
+			return nil, err
+		}
+		exports[module.Path] = moduleExports
+	}
+
+	return outputConsumers(destroyed, candidates, exports), nil
+}
+
+// parseExportOutputs returns the export_outputs blocks of the config of the given module.
+func parseExportOutputs(module *TerraformModule) ([]config.ExportOutputsConfig, error) {
+	opts := module.TerragruntOptions
+	if opts == nil {
+		return nil, nil
+	}
+	ctx := config.NewParsingContext(context.Background(), opts).WithDecodeList(config.ExportOutputsBlock)
+	moduleConfig, err := config.PartialParseConfigFile(ctx, opts.TerragruntConfigPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return moduleConfig.ExportOutputs, nil
+}
+
+// outputConsumers returns the given candidate modules which are not destroyed, but depend on one of the destroyed
+// modules, or read the outputs one of them publishes with its export_outputs blocks, given by module path.
+func outputConsumers(destroyed []*TerraformModule, candidates []*TerraformModule, exports map[string][]config.ExportOutputsConfig) []*OutputConsumer {
+	isDestroyed := map[string]bool{}
+	for _, module := range destroyed {
+		isDestroyed[module.Path] = true
+	}
+
+	var consumers []*OutputConsumer
+	found := map[string]bool{}
+	addConsumer := func(consumer *OutputConsumer) {
+		if key := consumer.String(); !found[key] {
+			found[key] = true
+			consumers = append(consumers, consumer)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if isDestroyed[candidate.Path] {
+			continue
+		}
+
+		for _, dependency := range candidate.Dependencies {
+			if isDestroyed[dependency.Path] && !candidate.runAfterOnly[dependency.Path] {
+				addConsumer(&OutputConsumer{Module: candidate.Path, Dependency: dependency.Path})
+			}
+		}
+
+		for _, dependencyConfig := range candidate.Config.TerragruntDependencies {
+			published := dependencyConfig.PublishedOutputs
+			if published == nil {
+				continue
+			}
+			for _, module := range destroyed {
+				for i := range exports[module.Path] {
+					if publishesTo(&exports[module.Path][i], published) {
+						addConsumer(&OutputConsumer{Module: candidate.Path, Dependency: module.Path, PublishedKey: published.Key})
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(consumers, func(i, j int) bool {
+		return consumers[i].String() < consumers[j].String()
+	})
+	return consumers
+}
+
+// publishesTo returns true if the given export_outputs block publishes the outputs the given published_outputs block
+// reads. The regions are only compared if both blocks set them, as a block without a region uses the default region
+// of the environment.
+func publishesTo(export *config.ExportOutputsConfig, published *config.PublishedOutputsConfig) bool {
+	exportStore, publishedStore := export.StoreConfig(), published.StoreConfig()
+	if exportStore.Backend != publishedStore.Backend || export.Key != published.Key || exportStore.Bucket != publishedStore.Bucket {
+		return false
+	}
+	return exportStore.Region == "" || publishedStore.Region == "" || exportStore.Region == publishedStore.Region
+}
+
+// OutputConsumersString returns the given consumers, one per line.
+func OutputConsumersString(consumers []*OutputConsumer) string {
+	lines := make([]string, 0, len(consumers))
+	for _, consumer := range consumers {
+		lines = append(lines, "  - "+consumer.String())
+	}
+	return strings.Join(lines, "\n")
+}
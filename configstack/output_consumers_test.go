@@ -0,0 +1,83 @@
+package configstack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputConsumers(t *testing.T) {
+	t.Parallel()
+
+	vpc := &TerraformModule{Path: "/live/vpc"}
+	db := &TerraformModule{Path: "/live/db", Dependencies: []*TerraformModule{vpc}}
+	app := &TerraformModule{Path: "/live/app", Dependencies: []*TerraformModule{vpc, db}}
+	// Only runs after the vpc, without reading its outputs
+	dns := &TerraformModule{Path: "/live/dns", Dependencies: []*TerraformModule{vpc}, runAfterOnly: map[string]bool{"/live/vpc": true}}
+	reader := &TerraformModule{Path: "/other/reader", Config: config.TerragruntConfig{TerragruntDependencies: []config.Dependency{
+		{Name: "vpc", PublishedOutputs: &config.PublishedOutputsConfig{Backend: "ssm", Key: "/vpc/{output}", Region: ptr("eu-west-1")}},
+		{Name: "other", PublishedOutputs: &config.PublishedOutputsConfig{Backend: "ssm", Key: "/other/{output}"}},
+	}}}
+
+	exports := map[string][]config.ExportOutputsConfig{
+		"/live/vpc": {{Name: "ssm", Backend: "ssm", Key: "/vpc/{output}"}},
+	}
+
+	consumers := outputConsumers([]*TerraformModule{vpc, db}, []*TerraformModule{vpc, db, app, dns, reader}, exports)
+	assert.Equal(t, []*OutputConsumer{
+		{Module: "/live/app", Dependency: "/live/db"},
+		{Module: "/live/app", Dependency: "/live/vpc"},
+		{Module: "/other/reader", Dependency: "/live/vpc", PublishedKey: "/vpc/{output}"},
+	}, consumers)
+
+	assert.Empty(t, outputConsumers([]*TerraformModule{vpc, db, app}, []*TerraformModule{vpc, db, app, dns}, exports))
+}
+
+func TestPublishesTo(t *testing.T) {
+	t.Parallel()
+
+	export := &config.ExportOutputsConfig{Name: "ssm", Backend: "ssm", Key: "/vpc", Region: ptr("eu-west-1")}
+	assert.True(t, publishesTo(export, &config.PublishedOutputsConfig{Backend: "ssm", Key: "/vpc"}))
+	assert.True(t, publishesTo(export, &config.PublishedOutputsConfig{Backend: "ssm", Key: "/vpc", Region: ptr("eu-west-1")}))
+	assert.False(t, publishesTo(export, &config.PublishedOutputsConfig{Backend: "ssm", Key: "/vpc", Region: ptr("us-east-1")}))
+	assert.False(t, publishesTo(export, &config.PublishedOutputsConfig{Backend: "secrets_manager", Key: "/vpc"}))
+	assert.False(t, publishesTo(export, &config.PublishedOutputsConfig{Backend: "ssm", Key: "/vpc/{output}"}))
+}
+
+func TestFindOutputConsumersOfExcludedModules(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	vpcConfigPath := filepath.Join(rootDir, "vpc", config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(vpcConfigPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(vpcConfigPath, []byte(`
+export_outputs "ssm" {
+  backend = "ssm"
+  key     = "/vpc"
+}
+`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = rootDir
+
+	vpc := &TerraformModule{Path: filepath.Dir(vpcConfigPath), TerragruntOptions: opts.Clone(vpcConfigPath)}
+	app := &TerraformModule{Path: filepath.Join(rootDir, "app"), Dependencies: []*TerraformModule{vpc}, FlagExcluded: true}
+	reader := &TerraformModule{Path: filepath.Join(rootDir, "reader"), FlagExcluded: true, Config: config.TerragruntConfig{TerragruntDependencies: []config.Dependency{
+		{Name: "vpc", PublishedOutputs: &config.PublishedOutputsConfig{Backend: "ssm", Key: "/vpc"}},
+	}}}
+
+	stack := &Stack{Path: rootDir, Modules: []*TerraformModule{vpc, app, reader}}
+	consumers, err := stack.FindOutputConsumers(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []*OutputConsumer{
+		{Module: app.Path, Dependency: vpc.Path},
+		{Module: reader.Path, Dependency: vpc.Path, PublishedKey: "/vpc"},
+	}, consumers)
+}
@@ -0,0 +1,91 @@
+package configstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// isPlanRun returns true if the run is a run-all plan, which prints a summary of the changes of the plans of the
+// modules.
+func isPlanRun(opts *options.TerragruntOptions) bool {
+	return opts.TerraformCommand == terraform.CommandNamePlan
+}
+
+// trackPlanSummaries sets the summary of the plan on the options of each of the given modules, filled in once they
+// planned.
+func trackPlanSummaries(modules map[string]*runningModule) {
+	for _, module := range modules {
+		if module.Module.TerragruntOptions != nil {
+			module.Module.TerragruntOptions.PlanSummary = &options.PlanSummary{}
+		}
+	}
+}
+
+// planSummary returns the summary of the plan of the given module, or nil if the module was not planned or its plan
+// could not be summarized.
+func planSummary(module *runningModule) *options.PlanSummary {
+	opts := module.Module.TerragruntOptions
+	if module.StartedAt.IsZero() || opts == nil || opts.PlanSummary == nil || !opts.PlanSummary.Recorded {
+		return nil
+	}
+	return opts.PlanSummary
+}
+
+// logPlanSummary logs a table of the counts of the resources the plan of each module adds, changes and destroys, with
+// the totals of the stack, so that the changes of the stack can be reviewed without scrolling through the interleaved
+// plans. The modules which failed to plan are listed after the table.
+func logPlanSummary(opts *options.TerragruntOptions, modules map[string]*runningModule) {
+	summaries := map[string]*options.PlanSummary{}
+	var notPlanned []string
+	for _, module := range modules {
+		if module.OtherShard || module.Module.AssumeAlreadyApplied || module.StartedAt.IsZero() {
+			continue
+		}
+		relPath, err := util.GetPathRelativeTo(module.Module.Path, opts.WorkingDir)
+		if err != nil {
+			relPath = module.Module.Path
+		}
+		if summary := planSummary(module); summary != nil {
+			summaries[relPath] = summary
+		} else {
+			notPlanned = append(notPlanned, relPath)
+		}
+	}
+	if len(summaries) == 0 && len(notPlanned) == 0 {
+		return
+	}
+
+	paths := make([]string, 0, len(summaries))
+	for path := range summaries {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	sort.Strings(notPlanned)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Plan summary of %d modules:\n", len(summaries))
+	writer := tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "MODULE\tADD\tCHANGE\tDESTROY")
+	var total options.PlanSummary
+	for _, path := range paths {
+		summary := summaries[path]
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\n", path, summary.Add, summary.Change, summary.Destroy)
+		total.Add += summary.Add
+		total.Change += summary.Change
+		total.Destroy += summary.Destroy
+	}
+	fmt.Fprintf(writer, "TOTAL\t%d\t%d\t%d\n", total.Add, total.Change, total.Destroy)
+	// Flushing a tabwriter writing to a strings.Builder does not fail
+	_ = writer.Flush()
+
+	if len(notPlanned) > 0 {
+		fmt.Fprintf(&out, "The plans of %d modules could not be summarized: %s\n", len(notPlanned), strings.Join(notPlanned, ", "))
+	}
+	opts.Logger.Info(strings.TrimSuffix(out.String(), "\n"))
+}
@@ -0,0 +1,46 @@
+package configstack
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogPlanSummary(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	logger := logrus.New()
+	logger.Out = &out
+	logger.Formatter = &logrus.TextFormatter{DisableQuote: true, DisableTimestamp: true}
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.Logger = logrus.NewEntry(logger)
+
+	newModule := func(path string, summary *options.PlanSummary) *runningModule {
+		moduleOpts := opts.Clone(path + "/terragrunt.hcl")
+		moduleOpts.PlanSummary = summary
+		return &runningModule{Module: &TerraformModule{Path: path, TerragruntOptions: moduleOpts}, StartedAt: time.Now()}
+	}
+
+	modules := map[string]*runningModule{
+		"/live/vpc":    newModule("/live/vpc", &options.PlanSummary{Add: 2, Destroy: 1, Recorded: true}),
+		"/live/app":    newModule("/live/app", &options.PlanSummary{Change: 3, Recorded: true}),
+		"/live/broken": newModule("/live/broken", &options.PlanSummary{}),
+		"/live/other":  {Module: &TerraformModule{Path: "/live/other"}},
+	}
+
+	logPlanSummary(opts, modules)
+
+	assert.Contains(t, out.String(), "Plan summary of 2 modules:")
+	assert.Contains(t, out.String(), "MODULE  ADD  CHANGE  DESTROY\napp     0    3       0\nvpc     2    0       1\nTOTAL   2    3       1\n")
+	assert.Contains(t, out.String(), "The plans of 1 modules could not be summarized: broken")
+	assert.NotContains(t, out.String(), "other")
+}
@@ -0,0 +1,76 @@
+package configstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// isRefreshRun returns true if the run is a run-all refresh, which reports the modules the refresh updated.
+func isRefreshRun(opts *options.TerragruntOptions) bool {
+	return opts.TerraformCommand == terraform.CommandNameRefresh
+}
+
+// trackRefreshedResources sets the list of the resources the refresh updated on the options of each of the given
+// modules, filled in once they ran.
+func trackRefreshedResources(modules map[string]*runningModule) {
+	for _, module := range modules {
+		if module.Module.TerragruntOptions != nil {
+			module.Module.TerragruntOptions.RefreshedResources = &[]string{}
+		}
+	}
+}
+
+// refreshedResources returns the addresses of the resources the refresh of the given module updated, or nil if the
+// refresh of the module did not succeed.
+func refreshedResources(module *runningModule) []string {
+	opts := module.Module.TerragruntOptions
+	if module.Err != nil || module.StartedAt.IsZero() || opts == nil || opts.RefreshedResources == nil {
+		return nil
+	}
+	return *opts.RefreshedResources
+}
+
+// logRefreshReport logs the modules whose state the refresh updated, with the resources it updated, so that the
+// changes made outside of terraform, e.g. by an account-wide tagging, can be reviewed without a plan.
+func logRefreshReport(opts *options.TerragruntOptions, modules map[string]*runningModule) {
+	updated := map[string][]string{}
+	unchanged := 0
+	for _, module := range modules {
+		if module.OtherShard {
+			continue
+		}
+		resources := refreshedResources(module)
+		if len(resources) > 0 {
+			relPath, err := util.GetPathRelativeTo(module.Module.Path, opts.WorkingDir)
+			if err != nil {
+				relPath = module.Module.Path
+			}
+			updated[relPath] = resources
+		} else if module.Err == nil && !module.StartedAt.IsZero() {
+			unchanged++
+		}
+	}
+
+	if len(updated) == 0 {
+		opts.Logger.Infof("The refresh did not update the state of any of the %d modules refreshed", unchanged)
+		return
+	}
+
+	paths := make([]string, 0, len(updated))
+	for path := range updated {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "The refresh updated the state of %d modules, and did not update the state of %d modules:\n", len(updated), unchanged)
+	for _, path := range paths {
+		fmt.Fprintf(&out, "  %s: %s\n", path, strings.Join(updated[path], ", "))
+	}
+	opts.Logger.Info(strings.TrimSuffix(out.String(), "\n"))
+}
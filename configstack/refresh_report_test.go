@@ -0,0 +1,52 @@
+package configstack
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunModulesRefreshReportsRefreshedResources(t *testing.T) {
+	t.Parallel()
+
+	reportFile := filepath.Join(t.TempDir(), "report.json")
+
+	opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.ReportFile = reportFile
+	opts.TerraformCommand = "refresh"
+
+	newModule := func(path string, refreshed []string) *TerraformModule {
+		moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+			require.NotNil(t, opts.RefreshedResources)
+			*opts.RefreshedResources = refreshed
+			return nil
+		}
+		return &TerraformModule{Path: path, TerragruntOptions: moduleOpts}
+	}
+
+	vpc := newModule("/live/vpc", []string{"aws_vpc.main"})
+	app := newModule("/live/app", []string{})
+
+	require.NoError(t, RunModules(context.Background(), opts, []*TerraformModule{vpc, app}, 2))
+
+	content, err := os.ReadFile(reportFile)
+	require.NoError(t, err)
+
+	var report ModuleReport
+	require.NoError(t, json.Unmarshal(content, &report))
+	require.Len(t, report.Modules, 2)
+	assert.Equal(t, "app", report.Modules[0].Path)
+	assert.Empty(t, report.Modules[0].RefreshedResources)
+	assert.Equal(t, "vpc", report.Modules[1].Path)
+	assert.Equal(t, []string{"aws_vpc.main"}, report.Modules[1].RefreshedResources)
+}
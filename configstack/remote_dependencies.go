@@ -0,0 +1,49 @@
+package configstack
+
+import (
+	"sort"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// addRemoteDependencies adds the units of other repos the given modules depend on, with the dependency blocks whose
+// config_path is the URL of a unit, as external modules which are already applied, so that they show in the graph and
+// the deploy order with the modules depending on them. Their outputs are read from their state, but they are never
+// run, so they are excluded as well, for the commands reading the code of the modules not to read them. The path of
+// each of them is its URL.
+func addRemoteDependencies(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) []*TerraformModule {
+	remoteModules := map[string]*TerraformModule{}
+	for _, module := range modules {
+		for _, dependency := range module.Config.TerragruntDependencies {
+			if (dependency.Enabled != nil && !*dependency.Enabled) || !config.IsRemoteDependencyPath(dependency.ConfigPath) {
+				continue
+			}
+
+			remoteModule, ok := remoteModules[dependency.ConfigPath]
+			if !ok {
+				remoteModule = &TerraformModule{
+					Path:                 dependency.ConfigPath,
+					TerragruntOptions:    terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath),
+					FlagExcluded:         true,
+					AssumeAlreadyApplied: true,
+				}
+				remoteModules[dependency.ConfigPath] = remoteModule
+			}
+			if !containsModule(module.Dependencies, remoteModule.Path) {
+				module.Dependencies = append(module.Dependencies, remoteModule)
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(remoteModules))
+	for path := range remoteModules {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		terragruntOptions.Logger.Debugf("Module %s is in another repo, so its outputs are read from its state, but it is never run", path)
+		modules = append(modules, remoteModules[path])
+	}
+	return modules
+}
@@ -0,0 +1,49 @@
+package configstack
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRemoteDependencies(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/terragrunt.hcl")
+	require.NoError(t, err)
+
+	const dnsURL = "git::https://github.com/acme/platform.git//live/prod/dns?ref=v1.2.0"
+	disabled := false
+
+	vpc := &TerraformModule{Path: "/live/vpc"}
+	app := &TerraformModule{Path: "/live/app", Dependencies: []*TerraformModule{vpc}, Config: config.TerragruntConfig{
+		TerragruntDependencies: []config.Dependency{
+			{Name: "vpc", ConfigPath: "../vpc"},
+			{Name: "dns", ConfigPath: dnsURL},
+			{Name: "legacy", ConfigPath: "git::https://github.com/acme/legacy.git//dns", Enabled: &disabled},
+		},
+	}}
+	cdn := &TerraformModule{Path: "/live/cdn", Config: config.TerragruntConfig{
+		TerragruntDependencies: []config.Dependency{{Name: "dns", ConfigPath: dnsURL}},
+	}}
+
+	modules := addRemoteDependencies([]*TerraformModule{vpc, app, cdn}, opts)
+	require.Len(t, modules, 4)
+
+	dns := modules[3]
+	assert.Equal(t, dnsURL, dns.Path)
+	assert.True(t, dns.AssumeAlreadyApplied)
+	assert.True(t, dns.FlagExcluded)
+	assert.NotNil(t, dns.TerragruntOptions)
+	assert.Equal(t, []*TerraformModule{vpc, dns}, app.Dependencies)
+	assert.Equal(t, []*TerraformModule{dns}, cdn.Dependencies)
+
+	var stdout bytes.Buffer
+	require.NoError(t, WriteDot(&stdout, opts, modules))
+	assert.Contains(t, stdout.String(), "\t\""+dnsURL+"\" [color=red, style=dashed];\n")
+	assert.Contains(t, stdout.String(), "\t\"app\" -> \""+dnsURL+"\";\n")
+}
@@ -0,0 +1,109 @@
+package configstack
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// flagModulesNotFailed flags the modules which did not fail in the previous run of the command as excluded with
+// --terragrunt-retry-failed, so that only the modules which failed run again, with the modules depending on them which
+// did not run because of their failure. The previous run is read from its report in --terragrunt-report-file, before the
+// run overwrites it.
+func flagModulesNotFailed(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) ([]*TerraformModule, error) {
+	if !terragruntOptions.RetryFailed {
+		return modules, nil
+	}
+	if terragruntOptions.ReportFile == "" {
+		return nil, errors.WithStackTrace(RetryFailedWithoutReport{})
+	}
+	if !util.FileExists(terragruntOptions.ReportFile) {
+		return nil, errors.WithStackTrace(PreviousReportNotFound(terragruntOptions.ReportFile))
+	}
+
+	report, err := ReadModuleReport(terragruntOptions.ReportFile)
+	if err != nil {
+		return nil, err
+	}
+	if report.Command != terragruntOptions.TerraformCommand {
+		return nil, errors.WithStackTrace(PreviousReportOfAnotherCommand{Path: terragruntOptions.ReportFile, Command: report.Command, Expected: terragruntOptions.TerraformCommand})
+	}
+
+	outcomes := map[string]string{}
+	for _, item := range report.Modules {
+		outcomes[item.Path] = item.Outcome
+	}
+
+	modulesOutcomes := map[string]string{}
+	failed := map[string]bool{}
+	for _, module := range modules {
+		relPath, err := util.GetPathRelativeTo(module.Path, terragruntOptions.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+		modulesOutcomes[module.Path] = outcomes[relPath]
+		if outcomes[relPath] == OutcomeFailed {
+			failed[module.Path] = true
+		}
+	}
+
+	retried, skipped := 0, 0
+	for _, module := range modules {
+		if module.FlagExcluded {
+			continue
+		}
+		switch {
+		case failed[module.Path]:
+			retried++
+		case modulesOutcomes[module.Path] == OutcomeSkippedUpstreamFailure && dependsOnFailedModule(module, failed, map[string]bool{}):
+			skipped++
+		default:
+			module.FlagExcluded = true
+		}
+	}
+
+	terragruntOptions.Logger.Infof("Retrying the %d modules which failed in the previous run %s recorded in %s, and the %d modules which did not run because of their failure", retried, report.RunID, terragruntOptions.ReportFile, skipped)
+	return modules, nil
+}
+
+// dependsOnFailedModule returns true if the given module depends, directly or through its dependencies, on one of the
+// failed modules. The modules it only runs after count too, as their failure skips it as well.
+func dependsOnFailedModule(module *TerraformModule, failed map[string]bool, visited map[string]bool) bool {
+	if visited[module.Path] {
+		return false
+	}
+	visited[module.Path] = true
+
+	for _, dependency := range module.Dependencies {
+		if failed[dependency.Path] || dependsOnFailedModule(dependency, failed, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Custom error types
+
+type RetryFailedWithoutReport struct{}
+
+func (err RetryFailedWithoutReport) Error() string {
+	return "--terragrunt-retry-failed requires the report of the previous run, set --terragrunt-report-file to the file the previous run wrote its report to"
+}
+
+type PreviousReportNotFound string
+
+func (path PreviousReportNotFound) Error() string {
+	return fmt.Sprintf("The report %s of the previous run for --terragrunt-retry-failed does not exist", string(path))
+}
+
+type PreviousReportOfAnotherCommand struct {
+	Path     string
+	Command  string
+	Expected string
+}
+
+func (err PreviousReportOfAnotherCommand) Error() string {
+	return fmt.Sprintf("The report %s is the report of a run of %s, not of %s, so it does not tell which modules failed to run %s", err.Path, err.Command, err.Expected, err.Expected)
+}
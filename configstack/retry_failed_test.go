@@ -0,0 +1,65 @@
+package configstack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlagModulesNotFailed(t *testing.T) {
+	t.Parallel()
+
+	reportFile := filepath.Join(t.TempDir(), "report.json")
+	report := &ModuleReport{RunID: "run-1", Command: "apply", Modules: []*ModuleReportItem{
+		{Path: "vpc", Outcome: OutcomeSucceeded},
+		{Path: "db", Outcome: OutcomeFailed},
+		{Path: "app", Outcome: OutcomeSkippedUpstreamFailure},
+		{Path: "dns", Outcome: OutcomeSucceeded},
+		{Path: "cdn", Outcome: OutcomeSkippedByUser},
+	}}
+	content, err := json.Marshal(report)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(reportFile, content, 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.TerraformCommand = "apply"
+	opts.ReportFile = reportFile
+	opts.RetryFailed = true
+
+	vpc := &TerraformModule{Path: "/live/vpc"}
+	db := &TerraformModule{Path: "/live/db", Dependencies: []*TerraformModule{vpc}}
+	app := &TerraformModule{Path: "/live/app", Dependencies: []*TerraformModule{db}}
+	dns := &TerraformModule{Path: "/live/dns"}
+	cdn := &TerraformModule{Path: "/live/cdn", Dependencies: []*TerraformModule{dns}}
+	queue := &TerraformModule{Path: "/live/queue"}
+
+	modules, err := flagModulesNotFailed([]*TerraformModule{vpc, db, app, dns, cdn, queue}, opts)
+	require.NoError(t, err)
+	require.Len(t, modules, 6)
+	assert.True(t, vpc.FlagExcluded)
+	assert.False(t, db.FlagExcluded)
+	assert.False(t, app.FlagExcluded)
+	assert.True(t, dns.FlagExcluded)
+	assert.True(t, cdn.FlagExcluded)
+	// A module not in the previous run did not fail
+	assert.True(t, queue.FlagExcluded)
+
+	opts.TerraformCommand = "plan"
+	_, err = flagModulesNotFailed([]*TerraformModule{{Path: "/live/vpc"}}, opts)
+	assert.ErrorContains(t, err, "is the report of a run of apply, not of plan")
+
+	opts.ReportFile = filepath.Join(t.TempDir(), "missing.json")
+	_, err = flagModulesNotFailed([]*TerraformModule{{Path: "/live/vpc"}}, opts)
+	assert.ErrorContains(t, err, "does not exist")
+
+	opts.ReportFile = ""
+	_, err = flagModulesNotFailed([]*TerraformModule{{Path: "/live/vpc"}}, opts)
+	assert.ErrorContains(t, err, "requires the report of the previous run")
+}
@@ -0,0 +1,187 @@
+package configstack
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The outcomes of a module in the run history, as sorted by the run report.
+const (
+	OutcomeSucceeded              = "succeeded"
+	OutcomeFailed                 = "failed"
+	OutcomeSkippedUpstreamFailure = "skipped_upstream_failure"
+	OutcomeSkippedByUser          = "skipped_by_user"
+)
+
+// How many of the last successful runs of a module are averaged to estimate how long it takes to run.
+const historyDurationRuns = 5
+
+// RunHistoryStore stores the results of the runs of run-all, oldest first. The file store is the default one, other
+// stores, e.g. a database shared by the CI runners, only have to implement this interface.
+type RunHistoryStore interface {
+	Append(run *HistoryRun) error
+	Runs() ([]*HistoryRun, error)
+}
+
+// HistoryRun is the result of a run of run-all.
+type HistoryRun struct {
+	RunID      string    `json:"run_id"`
+	StartedAt  time.Time `json:"started_at"`
+	Command    string    `json:"command"`
+	WorkingDir string    `json:"working_dir"`
+	// How long the run took, in seconds
+	Duration float64          `json:"duration"`
+	Modules  []*HistoryModule `json:"modules"`
+}
+
+// HistoryModule is the result of a module in a run of run-all.
+type HistoryModule struct {
+	// The path of the module, relative to the working dir
+	Path    string `json:"path"`
+	Outcome string `json:"outcome"`
+	// How long the module took to run, in seconds, or 0 if it did not run
+	Duration float64 `json:"duration"`
+	Retries  int64   `json:"retries"`
+}
+
+// NewRunHistoryStore returns the store of the run history at the given path.
+func NewRunHistoryStore(path string) RunHistoryStore {
+	return &fileRunHistoryStore{path: path}
+}
+
+// fileRunHistoryStore stores the runs in a file, one JSON object per line, so that a run is recorded by appending it
+// to the file without reading the previous runs.
+type fileRunHistoryStore struct {
+	path string
+}
+
+func (store *fileRunHistoryStore) Append(run *HistoryRun) error {
+	content, err := json.Marshal(run)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(store.path), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	file, err := os.OpenFile(store.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	if _, err := file.Write(append(content, '\n')); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// Runs returns the recorded runs, oldest first. A missing file is not an error, as there is nothing recorded before the
+// first run.
+func (store *fileRunHistoryStore) Runs() ([]*HistoryRun, error) {
+	var runs []*HistoryRun
+	if !util.FileExists(store.path) {
+		return runs, nil
+	}
+
+	file, err := os.Open(store.path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		run := &HistoryRun{}
+		if err := json.Unmarshal(scanner.Bytes(), run); err != nil {
+			return nil, errors.WithStackTrace(InvalidRunHistory{Path: store.path, Line: line, Err: err})
+		}
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return runs, nil
+}
+
+// HistoryDurations returns how long each module is expected to run from the given runs: the average duration of its
+// last successful runs, keyed by the module path relative to the working dir as the module durations.
+func HistoryDurations(runs []*HistoryRun) ModuleDurations {
+	durations := map[string][]float64{}
+	for i := len(runs) - 1; i >= 0; i-- {
+		for _, module := range runs[i].Modules {
+			if module.Outcome == OutcomeSucceeded && module.Duration > 0 && len(durations[module.Path]) < historyDurationRuns {
+				durations[module.Path] = append(durations[module.Path], module.Duration)
+			}
+		}
+	}
+
+	averages := ModuleDurations{}
+	for path, moduleDurations := range durations {
+		var total float64
+		for _, duration := range moduleDurations {
+			total += duration
+		}
+		averages[path] = total / float64(len(moduleDurations))
+	}
+	return averages
+}
+
+// trackModuleRetries sets a retries counter on the options of each of the given modules, read once they ran.
+func trackModuleRetries(modules map[string]*runningModule) {
+	for _, module := range modules {
+		if module.Module.TerragruntOptions != nil {
+			module.Module.TerragruntOptions.Retries = &atomic.Int64{}
+		}
+	}
+}
+
+// recordRunHistory appends the results of the given modules to the given run history.
+func recordRunHistory(history RunHistoryStore, opts *options.TerragruntOptions, startedAt time.Time, modules map[string]*runningModule) error {
+	run, err := newHistoryRun(opts, startedAt, modules)
+	if err != nil {
+		return err
+	}
+	return history.Append(run)
+}
+
+// newHistoryRun returns the results of the given modules which ran in the run with the given options.
+func newHistoryRun(opts *options.TerragruntOptions, startedAt time.Time, modules map[string]*runningModule) (*HistoryRun, error) {
+	run := &HistoryRun{RunID: opts.RunID, StartedAt: startedAt.UTC(), Command: opts.TerraformCommand, WorkingDir: opts.WorkingDir, Duration: time.Since(startedAt).Seconds()}
+
+	for _, module := range modules {
+		if module.OtherShard || module.AlreadySucceeded {
+			continue
+		}
+
+		relPath, err := util.GetPathRelativeTo(module.Module.Path, opts.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+
+		historyModule := &HistoryModule{Path: relPath, Outcome: moduleOutcome(module), Duration: module.Duration.Seconds()}
+		if moduleOpts := module.Module.TerragruntOptions; moduleOpts != nil && moduleOpts.Retries != nil {
+			historyModule.Retries = moduleOpts.Retries.Load()
+		}
+		run.Modules = append(run.Modules, historyModule)
+	}
+
+	sort.Slice(run.Modules, func(i, j int) bool {
+		return run.Modules[i].Path < run.Modules[j].Path
+	})
+	return run, nil
+}
@@ -0,0 +1,101 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHistoryStore(t *testing.T) {
+	t.Parallel()
+
+	historyFile := filepath.Join(t.TempDir(), "history", "runs.jsonl")
+	store := NewRunHistoryStore(historyFile)
+
+	runs, err := store.Runs()
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+
+	first := &HistoryRun{RunID: "1", StartedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Command: "apply", Modules: []*HistoryModule{{Path: "vpc", Outcome: OutcomeSucceeded, Duration: 12.5}}}
+	second := &HistoryRun{RunID: "2", StartedAt: time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC), Command: "plan", Modules: []*HistoryModule{{Path: "vpc", Outcome: OutcomeFailed, Retries: 2}}}
+	require.NoError(t, store.Append(first))
+	require.NoError(t, store.Append(second))
+
+	runs, err = store.Runs()
+	require.NoError(t, err)
+	assert.Equal(t, []*HistoryRun{first, second}, runs)
+}
+
+func TestRunHistoryStoreInvalidLine(t *testing.T) {
+	t.Parallel()
+
+	historyFile := filepath.Join(t.TempDir(), "runs.jsonl")
+	require.NoError(t, os.WriteFile(historyFile, []byte("{\"run_id\": \"1\"}\nnot json\n"), 0644))
+
+	_, err := NewRunHistoryStore(historyFile).Runs()
+	var invalid InvalidRunHistory
+	require.ErrorAs(t, err, &invalid)
+	assert.Equal(t, 2, invalid.Line)
+}
+
+func TestHistoryDurations(t *testing.T) {
+	t.Parallel()
+
+	var runs []*HistoryRun
+	for i := 1; i <= historyDurationRuns+2; i++ {
+		runs = append(runs, &HistoryRun{Modules: []*HistoryModule{
+			{Path: "vpc", Outcome: OutcomeSucceeded, Duration: float64(i)},
+			{Path: "app", Outcome: OutcomeFailed, Duration: 100},
+		}})
+	}
+	runs = append(runs, &HistoryRun{Modules: []*HistoryModule{{Path: "vpc", Outcome: OutcomeFailed, Duration: 100}}})
+
+	// Only the last successful runs are averaged
+	assert.Equal(t, ModuleDurations{"vpc": 5}, HistoryDurations(runs))
+}
+
+func TestRunModulesRecordsHistory(t *testing.T) {
+	t.Parallel()
+
+	historyFile := filepath.Join(t.TempDir(), "runs.jsonl")
+
+	opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.HistoryFile = historyFile
+	opts.RunID = "run-1"
+	opts.TerraformCommand = "apply"
+
+	newModule := func(path string, runErr error) *TerraformModule {
+		moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+			opts.Retries.Add(1)
+			return runErr
+		}
+		return &TerraformModule{Path: path, TerragruntOptions: moduleOpts}
+	}
+
+	vpc := newModule("/live/vpc", fmt.Errorf("apply failed"))
+	app := newModule("/live/app", nil)
+	app.Dependencies = []*TerraformModule{vpc}
+
+	require.Error(t, RunModules(context.Background(), opts, []*TerraformModule{vpc, app}, 1))
+
+	runs, err := NewRunHistoryStore(historyFile).Runs()
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "run-1", runs[0].RunID)
+	assert.Equal(t, "apply", runs[0].Command)
+	assert.Equal(t, []*HistoryModule{
+		{Path: "app", Outcome: OutcomeSkippedUpstreamFailure},
+		{Path: "vpc", Outcome: OutcomeFailed, Duration: runs[0].Modules[1].Duration, Retries: 1},
+	}, runs[0].Modules)
+}
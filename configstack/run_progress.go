@@ -0,0 +1,142 @@
+package configstack
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// How often the progress of a run-all is logged.
+const progressInterval = 30 * time.Second
+
+// runProgress tracks the modules of a run-all that are done and running, to periodically log how far the run is, with
+// an estimate of the time remaining based on the durations of previous runs.
+type runProgress struct {
+	mutex       sync.Mutex
+	parallelism int
+	total       int
+	done        int
+	running     map[*runningModule]time.Time
+	waiting     map[*runningModule]bool
+	// The total duration and count of the modules that ran in this run, used to estimate modules without history.
+	ranDuration time.Duration
+	ran         int
+	now         func() time.Time
+}
+
+func newRunProgress(modules map[string]*runningModule, parallelism int) *runProgress {
+	progress := &runProgress{
+		parallelism: parallelism,
+		total:       len(modules),
+		running:     map[*runningModule]time.Time{},
+		waiting:     map[*runningModule]bool{},
+		now:         time.Now,
+	}
+	for _, module := range modules {
+		progress.waiting[module] = true
+	}
+	return progress
+}
+
+func (progress *runProgress) moduleStarted(module *runningModule) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	delete(progress.waiting, module)
+	progress.running[module] = progress.now()
+}
+
+func (progress *runProgress) moduleFinished(module *runningModule) {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	delete(progress.waiting, module)
+	delete(progress.running, module)
+	progress.done++
+	if module.Duration > 0 {
+		progress.ranDuration += module.Duration
+		progress.ran++
+	}
+}
+
+// estimateRemaining estimates the time left in the run, by spreading the expected duration left of every module that is
+// not done over the modules that can run at the same time. Returns false if there is nothing to base the estimate on.
+func (progress *runProgress) estimateRemaining() (time.Duration, bool) {
+	var averageDuration time.Duration
+	if progress.ran > 0 {
+		averageDuration = progress.ranDuration / time.Duration(progress.ran)
+	}
+
+	expectedDuration := func(module *runningModule) (time.Duration, bool) {
+		switch {
+		case module.ExpectedDuration > 0:
+			return module.ExpectedDuration, true
+		case averageDuration > 0:
+			return averageDuration, true
+		default:
+			return 0, false
+		}
+	}
+
+	var remaining time.Duration
+	for module, startTime := range progress.running {
+		expected, ok := expectedDuration(module)
+		if !ok {
+			return 0, false
+		}
+		if elapsed := progress.now().Sub(startTime); elapsed < expected {
+			remaining += expected - elapsed
+		}
+	}
+	for module := range progress.waiting {
+		expected, ok := expectedDuration(module)
+		if !ok {
+			return 0, false
+		}
+		remaining += expected
+	}
+
+	workers := progress.total - progress.done
+	if progress.parallelism < workers {
+		workers = progress.parallelism
+	}
+	if workers <= 0 {
+		return 0, true
+	}
+	return remaining / time.Duration(workers), true
+}
+
+func (progress *runProgress) String() string {
+	progress.mutex.Lock()
+	defer progress.mutex.Unlock()
+
+	str := fmt.Sprintf("%d of %d modules complete, %d running", progress.done, progress.total, len(progress.running))
+	if remaining, ok := progress.estimateRemaining(); ok {
+		str += fmt.Sprintf(", about %s remaining", remaining.Round(time.Second))
+	}
+	return str
+}
+
+// logPeriodically logs the progress every interval, until the returned function is called.
+func (progress *runProgress) logPeriodically(logger *logrus.Entry, interval time.Duration) func() {
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				logger.Infof("Progress: %s", progress)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+	}
+}
@@ -0,0 +1,41 @@
+package configstack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunProgress(t *testing.T) {
+	t.Parallel()
+
+	moduleA := &runningModule{Module: &TerraformModule{Path: "a"}, ExpectedDuration: 2 * time.Minute}
+	moduleB := &runningModule{Module: &TerraformModule{Path: "b"}, ExpectedDuration: time.Minute}
+	moduleC := &runningModule{Module: &TerraformModule{Path: "c"}}
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	progress := newRunProgress(map[string]*runningModule{"a": moduleA, "b": moduleB, "c": moduleC}, 2)
+	progress.now = func() time.Time { return now }
+
+	// Module c has no history, so there is nothing to base an estimate on yet
+	assert.Equal(t, "0 of 3 modules complete, 0 running", progress.String())
+
+	progress.moduleStarted(moduleA)
+	progress.moduleStarted(moduleB)
+	now = now.Add(time.Minute)
+	moduleB.Duration = time.Minute
+	progress.moduleFinished(moduleB)
+
+	// a has 1m left and c is estimated from the average of the modules that ran, split over the 2 modules left
+	assert.Equal(t, "1 of 3 modules complete, 1 running, about 1m0s remaining", progress.String())
+
+	progress.moduleStarted(moduleC)
+	now = now.Add(time.Minute)
+	moduleA.Duration = 2 * time.Minute
+	progress.moduleFinished(moduleA)
+	moduleC.Duration = time.Minute
+	progress.moduleFinished(moduleC)
+
+	assert.Equal(t, "3 of 3 modules complete, 0 running, about 0s remaining", progress.String())
+}
@@ -0,0 +1,289 @@
+package configstack
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The version of the run queue file format, bumped on incompatible changes.
+const runQueueVersion = 1
+
+// The file, in the working dir, that --terragrunt-resume saves the run queue to as the checkpoint of the run.
+const CheckpointFile = ".terragrunt-checkpoint.json"
+
+// The statuses of a module in the run queue, for each command run on the queue.
+const (
+	RunQueueStatusPending   = "pending"
+	RunQueueStatusSucceeded = "succeeded"
+	RunQueueStatusFailed    = "failed"
+	RunQueueStatusSkipped   = "skipped"
+)
+
+// RunQueue is the run graph of a run-all and the status of each module, saved to a file so that the run can be resumed
+// on another runner, or continued with another command in a later CI job, e.g. the apply job after the plan job. The
+// modules are keyed by their path relative to the working dir and carry a hash of their config, so that the run is
+// refused if the configs changed in between.
+type RunQueue struct {
+	Version int                        `json:"version"`
+	Modules map[string]*RunQueueModule `json:"modules"`
+}
+
+// RunQueueModule is a module of the run queue.
+type RunQueueModule struct {
+	ConfigHash   string   `json:"config_hash"`
+	Dependencies []string `json:"dependencies"`
+	// The status of the module for each command run on the queue, e.g. {"plan": "succeeded", "apply": "pending"}.
+	Status map[string]string `json:"status"`
+}
+
+// runQueue tracks the run queue of a run-all, saving it every time a module finishes, so that the progress is not lost
+// if the runner goes away in the middle of the run.
+type runQueue struct {
+	mutex      sync.Mutex
+	path       string
+	command    string
+	workingDir string
+	queue      *RunQueue
+}
+
+// openRunQueue reads the run queue from the given file and checks that it matches the given modules, or starts a new
+// queue if the file does not exist. Modules that already succeeded running the command are marked to be skipped.
+func openRunQueue(path string, modules map[string]*runningModule, opts *options.TerragruntOptions) (*runQueue, error) {
+	current, err := newRunQueue(modules, opts.WorkingDir)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &runQueue{path: path, command: opts.TerraformCommand, workingDir: opts.WorkingDir, queue: current}
+
+	if util.FileExists(path) {
+		saved, err := ReadRunQueue(path)
+		if err != nil {
+			return nil, err
+		}
+		if changed := saved.changedModules(current); len(changed) > 0 {
+			return nil, errors.WithStackTrace(RunQueueConfigChanged{Path: path, Modules: changed})
+		}
+		queue.queue = saved
+	}
+
+	for _, module := range modules {
+		relPath, err := util.GetPathRelativeTo(module.Module.Path, opts.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+
+		status := queue.queue.Modules[relPath].Status
+		if status[queue.command] == RunQueueStatusSucceeded {
+			opts.Logger.Infof("Module %s already succeeded running %s according to the run queue %s, skipping it.", module.Module.Path, queue.command, path)
+			module.AlreadySucceeded = true
+		} else {
+			status[queue.command] = RunQueueStatusPending
+		}
+	}
+
+	return queue, queue.save()
+}
+
+// runQueueFile returns the file the run queue of the given run is saved to: the file of --terragrunt-run-queue-file, or
+// the checkpoint file in the working dir with --terragrunt-resume, or an empty string if the run queue is not saved.
+func runQueueFile(opts *options.TerragruntOptions) string {
+	if opts.RunQueueFile != "" {
+		return opts.RunQueueFile
+	}
+	if opts.Resume {
+		return filepath.Join(opts.WorkingDir, CheckpointFile)
+	}
+	return ""
+}
+
+// removeCheckpointIfSucceeded removes the checkpoint of --terragrunt-resume once all the given modules succeeded, so that
+// the next run starts over instead of skipping all the modules. The run queue of --terragrunt-run-queue-file is kept, as
+// it is continued with other commands.
+func (queue *runQueue) removeCheckpointIfSucceeded(opts *options.TerragruntOptions, modules map[string]*runningModule) {
+	if opts.RunQueueFile != "" || collectErrors(modules) != nil {
+		return
+	}
+
+	opts.Logger.Debugf("All the modules succeeded, removing the checkpoint %s", queue.path)
+	if err := os.Remove(queue.path); err != nil && !os.IsNotExist(err) {
+		opts.Logger.Warnf("Failed to remove the checkpoint %s: %v", queue.path, err)
+	}
+}
+
+// newRunQueue creates the run queue of the given modules, with no command run yet.
+func newRunQueue(modules map[string]*runningModule, workingDir string) (*RunQueue, error) {
+	queue := &RunQueue{Version: runQueueVersion, Modules: map[string]*RunQueueModule{}}
+
+	for _, module := range modules {
+		relPath, err := util.GetPathRelativeTo(module.Module.Path, workingDir)
+		if err != nil {
+			return nil, err
+		}
+
+		configHash, err := hashModuleConfig(module.Module)
+		if err != nil {
+			return nil, err
+		}
+
+		dependencies := []string{}
+		for _, dependency := range module.Module.Dependencies {
+			relDependencyPath, err := util.GetPathRelativeTo(dependency.Path, workingDir)
+			if err != nil {
+				return nil, err
+			}
+			dependencies = append(dependencies, relDependencyPath)
+		}
+		sort.Strings(dependencies)
+
+		queue.Modules[relPath] = &RunQueueModule{ConfigHash: configHash, Dependencies: dependencies, Status: map[string]string{}}
+	}
+
+	return queue, nil
+}
+
+// Hash the contents of the config of the given module and of the configs it includes. Only the contents are hashed,
+// not the paths, so that the hash is the same on runners with the repo checked out in a different dir.
+func hashModuleConfig(module *TerraformModule) (string, error) {
+	configPaths := []string{module.TerragruntOptions.TerragruntConfigPath}
+	for _, include := range module.Config.ProcessedIncludes {
+		configPaths = append(configPaths, include.Path)
+	}
+	sort.Strings(configPaths[1:])
+
+	hash := sha256.New()
+	for _, path := range configPaths {
+		file, err := os.Open(path)
+		if err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		_, err = io.Copy(hash, file)
+		file.Close() //nolint:errcheck
+		if err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// changedModules returns the modules that were added, removed, or whose config or dependencies changed in the current
+// queue compared to this one, sorted by path.
+func (queue *RunQueue) changedModules(current *RunQueue) []string {
+	var changed []string
+
+	for path, module := range current.Modules {
+		saved, ok := queue.Modules[path]
+		if !ok || saved.ConfigHash != module.ConfigHash || !util.ListEquals(saved.Dependencies, module.Dependencies) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range queue.Modules {
+		if _, ok := current.Modules[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// moduleFinished records the status of the given finished module and saves the queue.
+func (queue *runQueue) moduleFinished(module *runningModule) error {
+	relPath, err := util.GetPathRelativeTo(module.Module.Path, queue.workingDir)
+	if err != nil {
+		return err
+	}
+
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	queue.queue.Modules[relPath].Status[queue.command] = module.runStatus()
+	return queue.save()
+}
+
+// runStatus returns the status of the finished module: modules that did not run because a dependency failed or was
+// skipped, or because the run was aborted, are skipped.
+func (module *runningModule) runStatus() string {
+	switch errors.Unwrap(module.Err).(type) {
+	case nil:
+		return RunQueueStatusSucceeded
+	case DependencyFinishedWithError, ModuleSkippedByUser, RunAbortedByUser:
+		return RunQueueStatusSkipped
+	default:
+		return RunQueueStatusFailed
+	}
+}
+
+func (queue *runQueue) save() error {
+	content, err := json.MarshalIndent(queue.queue, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(queue.path), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// Write to a temporary file first, so that the queue is never left half written.
+	tmpPath := queue.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.Rename(tmpPath, queue.path); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// ReadRunQueue reads the run queue saved in the given file.
+func ReadRunQueue(path string) (*RunQueue, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	queue := &RunQueue{}
+	if err := json.Unmarshal(content, queue); err != nil {
+		return nil, errors.WithStackTrace(InvalidRunQueue{Path: path, Err: err})
+	}
+	if queue.Version != runQueueVersion {
+		return nil, errors.WithStackTrace(InvalidRunQueue{Path: path, Err: fmt.Errorf("unsupported version %d, expected %d", queue.Version, runQueueVersion)})
+	}
+	for _, module := range queue.Modules {
+		if module.Status == nil {
+			module.Status = map[string]string{}
+		}
+	}
+	return queue, nil
+}
+
+// Custom error types
+
+type RunQueueConfigChanged struct {
+	Path    string
+	Modules []string
+}
+
+func (err RunQueueConfigChanged) Error() string {
+	return fmt.Sprintf("The modules or their configs changed since the run queue %s was saved, refusing to continue the run. Changed modules: %s. Delete the run queue to start a new run.", err.Path, strings.Join(err.Modules, ", "))
+}
+
+type InvalidRunQueue struct {
+	Path string
+	Err  error
+}
+
+func (err InvalidRunQueue) Error() string {
+	return fmt.Sprintf("Invalid run queue %s: %v", err.Path, err.Err)
+}
@@ -0,0 +1,135 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunModulesResumesRunQueue(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	queueFile := filepath.Join(t.TempDir(), "queue.json")
+
+	var mutex sync.Mutex
+	var ran []string
+	failing := map[string]bool{}
+
+	run := func(command string) error {
+		opts, err := options.NewTerragruntOptionsForTest("run_queue_test")
+		require.NoError(t, err)
+		opts.WorkingDir = workingDir
+		opts.TerraformCommand = command
+		opts.RunQueueFile = queueFile
+
+		newModule := func(name string, dependencies ...*TerraformModule) *TerraformModule {
+			path := filepath.Join(workingDir, name)
+			moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+			moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				ran = append(ran, command+" "+name)
+				if failing[name] {
+					return fmt.Errorf("%s failed", name)
+				}
+				return nil
+			}
+			return &TerraformModule{Path: path, Dependencies: dependencies, TerragruntOptions: moduleOpts}
+		}
+
+		a := newModule("a")
+		b := newModule("b", a)
+		return RunModules(context.Background(), opts, []*TerraformModule{a, b}, 1)
+	}
+
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(workingDir, name), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(workingDir, name, config.DefaultTerragruntConfigPath), []byte("inputs = {}\n"), 0644))
+	}
+
+	require.NoError(t, run("plan"))
+
+	failing["b"] = true
+	require.Error(t, run("apply"))
+
+	queue, err := ReadRunQueue(queueFile)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"plan": RunQueueStatusSucceeded, "apply": RunQueueStatusSucceeded}, queue.Modules["a"].Status)
+	assert.Equal(t, map[string]string{"plan": RunQueueStatusSucceeded, "apply": RunQueueStatusFailed}, queue.Modules["b"].Status)
+	assert.Equal(t, []string{"a"}, queue.Modules["b"].Dependencies)
+
+	failing["b"] = false
+	require.NoError(t, run("apply"))
+	assert.Equal(t, []string{"plan a", "plan b", "apply a", "apply b", "apply b"}, ran)
+
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "a", config.DefaultTerragruntConfigPath), []byte("inputs = { changed = true }\n"), 0644))
+	err = run("apply")
+	require.Error(t, err)
+	changedErr, ok := errors.Unwrap(err).(RunQueueConfigChanged)
+	require.True(t, ok)
+	assert.Equal(t, []string{"a"}, changedErr.Modules)
+}
+
+func TestRunModulesResumesFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	checkpointFile := filepath.Join(workingDir, CheckpointFile)
+
+	var mutex sync.Mutex
+	var ran []string
+	failing := map[string]bool{}
+
+	run := func() error {
+		opts, err := options.NewTerragruntOptionsForTest("run_queue_test")
+		require.NoError(t, err)
+		opts.WorkingDir = workingDir
+		opts.TerraformCommand = "apply"
+		opts.Resume = true
+
+		newModule := func(name string, dependencies ...*TerraformModule) *TerraformModule {
+			path := filepath.Join(workingDir, name)
+			moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+			moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				ran = append(ran, name)
+				if failing[name] {
+					return fmt.Errorf("%s failed", name)
+				}
+				return nil
+			}
+			return &TerraformModule{Path: path, Dependencies: dependencies, TerragruntOptions: moduleOpts}
+		}
+
+		a := newModule("a")
+		b := newModule("b", a)
+		return RunModules(context.Background(), opts, []*TerraformModule{a, b}, 1)
+	}
+
+	for _, name := range []string{"a", "b"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(workingDir, name), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(workingDir, name, config.DefaultTerragruntConfigPath), []byte("inputs = {}\n"), 0644))
+	}
+
+	failing["b"] = true
+	require.Error(t, run())
+	assert.FileExists(t, checkpointFile)
+
+	failing["b"] = false
+	require.NoError(t, run())
+	assert.NoFileExists(t, checkpointFile)
+
+	require.NoError(t, run())
+	assert.Equal(t, []string{"a", "b", "b", "a", "b"}, ran)
+}
@@ -0,0 +1,239 @@
+package configstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Exit codes of run-all with --terragrunt-continue-on-error, so that CI can tell the outcome of the run apart without
+// parsing the logs. Codes 1 and 2 are left to Terraform, which uses them for errors and for plans with changes.
+const (
+	ExitCodeAllSucceeded      = 0
+	ExitCodeSomeFailed        = 3
+	ExitCodeSomeFailedSkipped = 4
+	ExitCodeNothingSucceeded  = 5
+)
+
+// RunReport sorts the modules of a run-all by the outcome of running them.
+type RunReport struct {
+	Succeeded []string
+	// Modules whose `plan -detailed-exitcode` has changes, which count as succeeded.
+	Changed []string
+	Failed  []string
+	// Modules that did not run because one of their dependencies, direct or not, failed.
+	SkippedUpstreamFailure []string
+	// Modules the user chose to skip, and the modules depending on them.
+	SkippedByUser []string
+	// Modules removed from the run by their exclude block, with the reason of the block, by module.
+	ExcludedByConfig map[string]string
+	// The owners of the modules, as set by the owner attribute of their config, by module.
+	Owners map[string]string
+	// The terraform binaries selected for the modules with --terragrunt-auto-terraform-binary, by module.
+	Binaries map[string]string
+	// The hits and misses of the caches during the run, by cache name.
+	Caches map[string]util.CacheStats
+}
+
+func newRunReport(modules map[string]*runningModule) *RunReport {
+	report := &RunReport{}
+
+	for path, module := range modules {
+		if owner := module.Module.Config.Owner; owner != "" {
+			if report.Owners == nil {
+				report.Owners = map[string]string{}
+			}
+			report.Owners[path] = owner
+		}
+
+		if opts := module.Module.TerragruntOptions; opts != nil && opts.AutoTerraformBinary && opts.TerraformVersion != nil {
+			if report.Binaries == nil {
+				report.Binaries = map[string]string{}
+			}
+			report.Binaries[path] = fmt.Sprintf("%s v%s (%s)", opts.TerraformImplementation, opts.TerraformVersion, opts.TerraformPath)
+		}
+
+		switch moduleOutcome(module) {
+		case OutcomeSucceeded:
+			report.Succeeded = append(report.Succeeded, path)
+			if module.Err != nil {
+				report.Changed = append(report.Changed, path)
+			}
+		case OutcomeSkippedByUser:
+			report.SkippedByUser = append(report.SkippedByUser, path)
+		case OutcomeSkippedUpstreamFailure:
+			report.SkippedUpstreamFailure = append(report.SkippedUpstreamFailure, path)
+		default:
+			report.Failed = append(report.Failed, path)
+		}
+	}
+
+	sort.Strings(report.Succeeded)
+	sort.Strings(report.Changed)
+	sort.Strings(report.Failed)
+	sort.Strings(report.SkippedUpstreamFailure)
+	sort.Strings(report.SkippedByUser)
+
+	return report
+}
+
+// moduleOutcome returns whether the given module succeeded, failed or was skipped, and why. A plan with
+// -detailed-exitcode reporting changes succeeded.
+func moduleOutcome(module *runningModule) string {
+	_, isDependencyErr := errors.Unwrap(module.Err).(DependencyFinishedWithError)
+	_, isSkippedByUser := errors.Unwrap(module.Err).(ModuleSkippedByUser)
+	switch {
+	case module.Err == nil || (module.Module.TerragruntOptions != nil && IsPlanWithChanges(module.Module.TerragruntOptions, module.Err)):
+		return OutcomeSucceeded
+	case isSkippedByUser:
+		return OutcomeSkippedByUser
+	case isDependencyErr:
+		return OutcomeSkippedUpstreamFailure
+	default:
+		return OutcomeFailed
+	}
+}
+
+// ExitCode returns the exit code matching the outcome of the run, DetailedExitCodeChanges if all the modules succeeded
+// but some plans have changes.
+func (report *RunReport) ExitCode() int {
+	switch {
+	case len(report.Failed) == 0 && len(report.SkippedUpstreamFailure) == 0 && len(report.Changed) > 0:
+		return terraform.DetailedExitCodeChanges
+	case len(report.Failed) == 0 && len(report.SkippedUpstreamFailure) == 0:
+		return ExitCodeAllSucceeded
+	case len(report.Succeeded) == 0:
+		return ExitCodeNothingSucceeded
+	case len(report.SkippedUpstreamFailure) > 0:
+		return ExitCodeSomeFailedSkipped
+	default:
+		return ExitCodeSomeFailed
+	}
+}
+
+func (report *RunReport) String() string {
+	var str strings.Builder
+
+	for _, section := range []struct {
+		title   string
+		modules []string
+	}{
+		{"Succeeded", report.Succeeded},
+		{"Failed", report.Failed},
+		{"Skipped due to upstream failure", report.SkippedUpstreamFailure},
+		{"Skipped by user", report.SkippedByUser},
+	} {
+		str.WriteString(fmt.Sprintf("%s (%d):\n", section.title, len(section.modules)))
+		for _, module := range section.modules {
+			str.WriteString("  - " + module + report.ownerSuffix(module) + "\n")
+		}
+	}
+
+	if len(report.Changed) > 0 {
+		str.WriteString(fmt.Sprintf("Planned changes (%d):\n", len(report.Changed)))
+		for _, module := range report.Changed {
+			str.WriteString("  - " + module + "\n")
+		}
+	}
+
+	if len(report.ExcludedByConfig) > 0 {
+		modules := make([]string, 0, len(report.ExcludedByConfig))
+		for module := range report.ExcludedByConfig {
+			modules = append(modules, module)
+		}
+		sort.Strings(modules)
+
+		str.WriteString(fmt.Sprintf("Excluded by config (%d):\n", len(modules)))
+		for _, module := range modules {
+			str.WriteString("  - " + module + excludeReasonSuffix(report.ExcludedByConfig[module]) + "\n")
+		}
+	}
+
+	if len(report.Binaries) > 0 {
+		modules := make([]string, 0, len(report.Binaries))
+		for module := range report.Binaries {
+			modules = append(modules, module)
+		}
+		sort.Strings(modules)
+
+		str.WriteString("Terraform binaries:\n")
+		for _, module := range modules {
+			str.WriteString("  - " + module + ": " + report.Binaries[module] + "\n")
+		}
+	}
+
+	if len(report.Caches) > 0 {
+		str.WriteString("Caches:\n")
+		str.WriteString(util.CacheStatsString(report.Caches))
+	}
+
+	return str.String()
+}
+
+func (report *RunReport) ownerSuffix(module string) string {
+	if owner, ok := report.Owners[module]; ok {
+		return " (owner: " + owner + ")"
+	}
+	return ""
+}
+
+// FailedByOwner returns the failed modules, and the modules skipped due to their failure, by owner. The modules without
+// an owner are under the empty owner.
+func (report *RunReport) FailedByOwner() map[string][]string {
+	failed := map[string][]string{}
+	for _, modules := range [][]string{report.Failed, report.SkippedUpstreamFailure} {
+		for _, module := range modules {
+			owner := report.Owners[module]
+			failed[owner] = append(failed[owner], module)
+		}
+	}
+	return failed
+}
+
+// Log the report, with the stats of the caches and the failures of each owner, and return an error carrying its exit code if not all modules succeeded.
+func (report *RunReport) finish(terragruntOptions *options.TerragruntOptions, runErr error) error {
+	report.Caches = util.GetCacheStats()
+	terragruntOptions.Logger.Infof("Run summary of the run %s:\n%s", terragruntOptions.RunID, report)
+
+	// The failures are logged per owner too, so that each owner can find theirs in the logs of a shared stack
+	failedByOwner := report.FailedByOwner()
+	owners := make([]string, 0, len(failedByOwner))
+	for owner := range failedByOwner {
+		if owner != "" {
+			owners = append(owners, owner)
+		}
+	}
+	sort.Strings(owners)
+	for _, owner := range owners {
+		terragruntOptions.Logger.Errorf("Modules of the owner %s failed: %s", owner, strings.Join(failedByOwner[owner], ", "))
+	}
+
+	if runErr == nil {
+		return nil
+	}
+	return errors.WithStackTrace(RunReportError{Report: report, Err: runErr})
+}
+
+// Custom error types
+
+type RunReportError struct {
+	Report *RunReport
+	Err    error
+}
+
+func (err RunReportError) Error() string {
+	return err.Err.Error()
+}
+
+func (err RunReportError) Unwrap() error {
+	return err.Err
+}
+
+func (err RunReportError) ExitStatus() (int, error) {
+	return err.Report.ExitCode(), nil
+}
@@ -0,0 +1,145 @@
+package configstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunReport(t *testing.T) {
+	t.Parallel()
+
+	vpc := &TerraformModule{Path: "vpc"}
+	failure := fmt.Errorf("apply failed")
+
+	testCases := []struct {
+		name             string
+		modules          map[string]*runningModule
+		expectedReport   RunReport
+		expectedExitCode int
+	}{
+		{
+			"all succeeded",
+			map[string]*runningModule{
+				"vpc": {Module: vpc},
+				"app": {Module: &TerraformModule{Path: "app"}},
+			},
+			RunReport{Succeeded: []string{"app", "vpc"}},
+			ExitCodeAllSucceeded,
+		},
+		{
+			"failed without dependents",
+			map[string]*runningModule{
+				"vpc": {Module: vpc, Err: failure},
+				"dns": {Module: &TerraformModule{Path: "dns"}},
+			},
+			RunReport{Succeeded: []string{"dns"}, Failed: []string{"vpc"}},
+			ExitCodeSomeFailed,
+		},
+		{
+			"failed with skipped dependents",
+			map[string]*runningModule{
+				"vpc": {Module: vpc, Err: failure},
+				"app": {Module: &TerraformModule{Path: "app"}, Err: DependencyFinishedWithError{Dependency: vpc, Err: failure}},
+				"dns": {Module: &TerraformModule{Path: "dns"}},
+			},
+			RunReport{Succeeded: []string{"dns"}, Failed: []string{"vpc"}, SkippedUpstreamFailure: []string{"app"}},
+			ExitCodeSomeFailedSkipped,
+		},
+		{
+			"nothing succeeded",
+			map[string]*runningModule{
+				"vpc": {Module: vpc, Err: failure},
+				"app": {Module: &TerraformModule{Path: "app"}, Err: DependencyFinishedWithError{Dependency: vpc, Err: failure}},
+			},
+			RunReport{Failed: []string{"vpc"}, SkippedUpstreamFailure: []string{"app"}},
+			ExitCodeNothingSucceeded,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			report := newRunReport(testCase.modules)
+			assert.Equal(t, testCase.expectedReport, *report)
+			assert.Equal(t, testCase.expectedExitCode, report.ExitCode())
+		})
+	}
+}
+
+func TestRunReportBinaries(t *testing.T) {
+	t.Parallel()
+
+	vpcOptions, err := options.NewTerragruntOptionsForTest("vpc/terragrunt.hcl")
+	require.NoError(t, err)
+	vpcOptions.AutoTerraformBinary = true
+	vpcOptions.TerraformPath = "/usr/local/bin/tofu-1.7.2"
+	vpcOptions.TerraformVersion = version.Must(version.NewVersion("1.7.2"))
+	vpcOptions.TerraformImplementation = options.OpenTofuImpl
+
+	modules := map[string]*runningModule{
+		"vpc": {Module: &TerraformModule{Path: "vpc", TerragruntOptions: vpcOptions}},
+		"app": {Module: &TerraformModule{Path: "app"}},
+	}
+
+	report := newRunReport(modules)
+	assert.Equal(t, map[string]string{"vpc": "tofu v1.7.2 (/usr/local/bin/tofu-1.7.2)"}, report.Binaries)
+	assert.Contains(t, report.String(), "Terraform binaries:\n  - vpc: tofu v1.7.2 (/usr/local/bin/tofu-1.7.2)\n")
+}
+
+func TestRunReportErrorExitCode(t *testing.T) {
+	t.Parallel()
+
+	modules := map[string]*runningModule{
+		"vpc": {Module: &TerraformModule{Path: "vpc", TerragruntOptions: mockOptions}, Err: fmt.Errorf("apply failed")},
+		"dns": {Module: &TerraformModule{Path: "dns", TerragruntOptions: mockOptions}},
+	}
+
+	err := newRunReport(modules).finish(mockOptions, collectErrors(modules))
+	require.Error(t, err)
+
+	exitCode, err := shell.GetExitCode(err)
+	require.NoError(t, err)
+	assert.Equal(t, ExitCodeSomeFailed, exitCode)
+}
+
+func TestRunReportCaches(t *testing.T) {
+	t.Parallel()
+
+	report := &RunReport{Succeeded: []string{"vpc"}, Caches: map[string]util.CacheStats{
+		util.SourceCache:       {Hits: 1, Misses: 3},
+		util.ParsedConfigCache: {Hits: 9, Misses: 1},
+	}}
+	assert.Contains(t, report.String(), "Caches:\n  - parsed_config: 9 hits, 1 misses (90% hit rate)\n  - source: 1 hits, 3 misses (25% hit rate)\n")
+}
+
+func TestRunReportExcludedByConfig(t *testing.T) {
+	t.Parallel()
+
+	report := &RunReport{Succeeded: []string{"vpc"}, ExcludedByConfig: map[string]string{"mysql": "The region is not enabled yet", "redis": ""}}
+	assert.Contains(t, report.String(), "Excluded by config (2):\n  - mysql: The region is not enabled yet\n  - redis\n")
+}
+
+func TestRunReportOwners(t *testing.T) {
+	t.Parallel()
+
+	modules := map[string]*runningModule{
+		"vpc": {Module: &TerraformModule{Path: "vpc", Config: config.TerragruntConfig{Owner: "team-network"}}, Err: fmt.Errorf("apply failed")},
+		"app": {Module: &TerraformModule{Path: "app", Config: config.TerragruntConfig{Owner: "team-apps"}}, Err: DependencyFinishedWithError{}},
+		"dns": {Module: &TerraformModule{Path: "dns"}, Err: fmt.Errorf("apply failed")},
+	}
+
+	report := newRunReport(modules)
+	assert.Equal(t, map[string]string{"vpc": "team-network", "app": "team-apps"}, report.Owners)
+	assert.Contains(t, report.String(), "Failed (2):\n  - dns\n  - vpc (owner: team-network)\n")
+	assert.Equal(t, map[string][]string{"": {"dns"}, "team-network": {"vpc"}, "team-apps": {"app"}}, report.FailedByOwner())
+}
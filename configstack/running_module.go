@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gruntwork-io/terragrunt/options"
 
@@ -11,6 +13,7 @@ import (
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/hashicorp/go-multierror"
 )
 
@@ -33,6 +36,22 @@ type runningModule struct {
 	Dependencies   map[string]*runningModule
 	NotifyWhenDone []*runningModule
 	FlagExcluded   bool
+
+	// Scheduling hints used to pick which of the modules ready to run is started first
+	Priority         int
+	ExpectedDuration time.Duration
+
+	// How long the module took to run
+	Duration time.Duration
+	// When the module started and finished running, zero if it did not run
+	StartedAt  time.Time
+	FinishedAt time.Time
+
+	// Set if the module already succeeded running the command in a previous run of the run queue
+	AlreadySucceeded bool
+
+	// Set if the module is run by another shard of a sharded run-all, this shard only waits for it to finish
+	OtherShard bool
 }
 
 // This controls in what order dependencies should be enforced between modules
@@ -66,7 +85,7 @@ func RunModules(ctx context.Context, opts *options.TerragruntOptions, modules []
 	if err != nil {
 		return err
 	}
-	return runModules(ctx, opts, runningModules, parallelism)
+	return runModules(ctx, opts, runningModules, excludedByConfig(modules), parallelism)
 }
 
 // Run the given map of module path to runningModule. To "run" a module, execute the RunTerragrunt command in its
@@ -77,7 +96,7 @@ func RunModulesReverseOrder(ctx context.Context, opts *options.TerragruntOptions
 	if err != nil {
 		return err
 	}
-	return runModules(ctx, opts, runningModules, parallelism)
+	return runModules(ctx, opts, runningModules, excludedByConfig(modules), parallelism)
 }
 
 // Run the given map of module path to runningModule. To "run" a module, execute the RunTerragrunt command in its
@@ -87,7 +106,7 @@ func RunModulesIgnoreOrder(ctx context.Context, opts *options.TerragruntOptions,
 	if err != nil {
 		return err
 	}
-	return runModules(ctx, opts, runningModules, parallelism)
+	return runModules(ctx, opts, runningModules, excludedByConfig(modules), parallelism)
 }
 
 // Convert the list of modules to a map from module path to a runningModule struct. This struct contains information
@@ -167,22 +186,144 @@ func removeFlagExcluded(modules map[string]*runningModule) map[string]*runningMo
 
 // Run the given map of module path to runningModule. To "run" a module, execute the RunTerragrunt command in its
 // TerragruntOptions object. The modules will be executed in an order determined by their inter-dependencies, using
-// as much concurrency as possible.
-func runModules(ctx context.Context, opts *options.TerragruntOptions, modules map[string]*runningModule, parallelism int) error {
+// as much concurrency as possible. The modules excluded by their exclude block, with its reason, are listed in the
+// run report.
+func runModules(ctx context.Context, opts *options.TerragruntOptions, modules map[string]*runningModule, excluded map[string]string, parallelism int) error {
+	var shard *runShard
+	if opts.Shard != "" {
+		var err error
+		if modules, shard, err = shardModules(opts, modules); err != nil {
+			return err
+		}
+	}
+
 	var waitGroup sync.WaitGroup
-	var semaphore = make(chan struct{}, parallelism) // Make a semaphore from a buffered channel
+	var semaphore = newModuleSemaphore(parallelism)
+	var groups = newConcurrencyGroups()
+	var groupSemaphores = newGroupSemaphores(opts.GroupParallelism)
+	var aborted atomic.Bool // Set once the user aborted the run, so that no new module is started
+
+	durations := ModuleDurations{}
+	if opts.ModuleDurationsFile != "" {
+		var err error
+		if durations, err = ReadModuleDurations(opts.ModuleDurationsFile); err != nil {
+			return err
+		}
+	}
+
+	var history RunHistoryStore
+	if opts.HistoryFile != "" {
+		history = NewRunHistoryStore(opts.HistoryFile)
+		if runs, err := history.Runs(); err != nil {
+			opts.Logger.Warnf("Failed to read the run history from %s: %v", opts.HistoryFile, err)
+		} else {
+			// The durations recorded by the previous runs are the estimates of the modules without a recorded duration
+			for path, duration := range HistoryDurations(runs) {
+				if _, ok := durations[path]; !ok {
+					durations[path] = duration
+				}
+			}
+			handleFlakyModules(opts, modules, FlakyModules(runs))
+		}
+	}
+	if opts.HistoryFile != "" || opts.ReportFile != "" {
+		trackModuleRetries(modules)
+	}
+	if isRefreshRun(opts) {
+		trackRefreshedResources(modules)
+	}
+	if isPlanRun(opts) {
+		trackPlanSummaries(modules)
+	}
+	if opts.ReportFile != "" {
+		trackTerraformCloudRuns(modules)
+	}
+	setSchedulingHints(modules, durations, opts.WorkingDir)
+	startedAt := time.Now()
+
+	var queue *runQueue
+	if queueFile := runQueueFile(opts); queueFile != "" {
+		var err error
+		if queue, err = openRunQueue(queueFile, modules, opts); err != nil {
+			return err
+		}
+	}
+
+	progress := newRunProgress(modules, parallelism)
+	stopProgress := func() {}
+	if !opts.NoProgress && len(modules) > 1 {
+		stopProgress = progress.logPeriodically(opts.Logger, progressInterval)
+	}
 
 	for _, module := range modules {
 		waitGroup.Add(1)
 		go func(module *runningModule) {
 			defer waitGroup.Done()
-			module.runModuleWhenReady(ctx, opts, semaphore)
+			if module.OtherShard {
+				shard.waitForModuleWhenReady(ctx, module, progress)
+			} else {
+				module.runModuleWhenReady(ctx, opts, semaphore, groupSemaphores, groups, &aborted, progress)
+			}
+			if shard != nil {
+				if err := shard.moduleFinished(ctx, module); err != nil {
+					opts.Logger.Errorf("Failed to record the status of module %s in the shard ledger %s: %v", module.Module.Path, opts.ShardLedger, err)
+				}
+			}
+			if queue != nil {
+				if err := queue.moduleFinished(module); err != nil {
+					opts.Logger.Warnf("Failed to save the run queue to %s: %v", queue.path, err)
+				}
+			}
 		}(module)
 	}
 
 	waitGroup.Wait()
+	stopProgress()
+
+	if opts.GitHubAnnotations {
+		emitGitHubAnnotations(opts, modules)
+	}
 
-	return collectErrors(modules)
+	if opts.ModuleDurationsFile != "" {
+		if err := writeModuleDurations(opts.ModuleDurationsFile, modules, opts); err != nil {
+			opts.Logger.Warnf("Failed to record module durations to %s: %v", opts.ModuleDurationsFile, err)
+		}
+	}
+
+	if history != nil {
+		if err := recordRunHistory(history, opts, startedAt, modules); err != nil {
+			opts.Logger.Warnf("Failed to record the run to the run history %s: %v", opts.HistoryFile, err)
+		}
+	}
+
+	if isRefreshRun(opts) {
+		logRefreshReport(opts, modules)
+	}
+
+	if isPlanRun(opts) {
+		logPlanSummary(opts, modules)
+	}
+
+	if opts.ReportFile != "" {
+		if err := writeModuleReport(opts.ReportFile, opts, modules); err != nil {
+			opts.Logger.Warnf("Failed to write the run report to %s: %v", opts.ReportFile, err)
+		}
+	}
+
+	if queue != nil {
+		queue.removeCheckpointIfSucceeded(opts, modules)
+	}
+
+	if stats := util.GetCacheStats(); len(stats) > 0 {
+		opts.Logger.Debugf("Caches:\n%s", util.CacheStatsString(stats))
+	}
+
+	if opts.ContinueOnError {
+		report := newRunReport(modules)
+		report.ExcludedByConfig = excluded
+		return report.finish(opts, withPlanDetailedExitCode(opts, modules, collectErrors(modules)))
+	}
+	return withPlanDetailedExitCode(opts, modules, collectErrors(modules))
 }
 
 // Collect the errors from the given modules and return a single error object to represent them, or nil if no errors
@@ -190,7 +331,8 @@ func runModules(ctx context.Context, opts *options.TerragruntOptions, modules ma
 func collectErrors(modules map[string]*runningModule) error {
 	var result *multierror.Error
 	for _, module := range modules {
-		if module.Err != nil {
+		// Modules skipped by the user are not an error
+		if _, skipped := errors.Unwrap(module.Err).(ModuleSkippedByUser); module.Err != nil && !skipped {
 			result = multierror.Append(result, module.Err)
 		}
 	}
@@ -199,7 +341,7 @@ func collectErrors(modules map[string]*runningModule) error {
 }
 
 // Run a module once all of its dependencies have finished executing.
-func (module *runningModule) runModuleWhenReady(ctx context.Context, opts *options.TerragruntOptions, semaphore chan struct{}) {
+func (module *runningModule) runModuleWhenReady(ctx context.Context, opts *options.TerragruntOptions, semaphore *moduleSemaphore, groupSemaphores *groupSemaphores, groups *concurrencyGroups, aborted *atomic.Bool, progress *runProgress) {
 
 	err := telemetry.Telemetry(ctx, opts, "wait_for_module_ready", map[string]interface{}{
 		"path":             module.Module.Path,
@@ -208,11 +350,22 @@ func (module *runningModule) runModuleWhenReady(ctx context.Context, opts *optio
 		return module.waitForDependencies()
 	})
 
-	semaphore <- struct{}{} // Add one to the buffered channel. Will block if parallelism limit is met
-	defer func() {
-		<-semaphore // Remove one from the buffered channel
-	}()
+	// The concurrency group and the slot of the run group are acquired first, so that the module waiting for them does
+	// not hold a parallelism slot
+	if err == nil {
+		release := groups.Acquire(module)
+		defer release()
+		releaseGroupSlot := groupSemaphores.Acquire(module)
+		defer releaseGroupSlot()
+	}
+
+	semaphore.Acquire(module) // Will block if parallelism limit is met
+	defer semaphore.Release()
+	if err == nil && aborted.Load() {
+		err = RunAbortedByUser{}
+	}
 	if err == nil {
+		progress.moduleStarted(module)
 		err = telemetry.Telemetry(ctx, opts, "run_module", map[string]interface{}{
 			"path":             module.Module.Path,
 			"terraformCommand": module.Module.TerragruntOptions.TerraformCommand,
@@ -220,7 +373,11 @@ func (module *runningModule) runModuleWhenReady(ctx context.Context, opts *optio
 			return module.runNow(ctx)
 		})
 	}
+	if _, isAbort := errors.Unwrap(err).(RunAbortedByUser); isAbort {
+		aborted.Store(true)
+	}
 	module.moduleFinished(err)
+	progress.moduleFinished(module)
 }
 
 // Wait for all of this modules dependencies to finish executing. Return an error if any of those dependencies complete
@@ -231,7 +388,18 @@ func (module *runningModule) waitForDependencies() error {
 		doneDependency := <-module.DependencyDone
 		delete(module.Dependencies, doneDependency.Module.Path)
 
-		if doneDependency.Err != nil {
+		switch err := errors.Unwrap(doneDependency.Err).(type) {
+		case RunAbortedByUser:
+			return err
+		case ModuleSkippedByUser:
+			if warnErr := module.Module.TerragruntOptions.Warn(options.WarningModuleSkippedWithDependency, "Dependency %s of module %s was skipped, so module %s is skipped too.", doneDependency.Module.Path, module.Module.Path, module.Module.Path); warnErr != nil {
+				return warnErr
+			}
+			return ModuleSkippedByUser{Module: module.Module, SkippedDependency: doneDependency.Module}
+		}
+
+		// The plan of the dependency having changes is not a failure of the dependency
+		if doneDependency.Err != nil && !IsPlanWithChanges(doneDependency.Module.TerragruntOptions, doneDependency.Err) {
 			if module.Module.TerragruntOptions.IgnoreDependencyErrors {
 				module.Module.TerragruntOptions.Logger.Errorf("Dependency %s of module %s just finished with an error. Module %s will have to return an error too. However, because of --terragrunt-ignore-dependency-errors, module %s will run anyway.", doneDependency.Module.Path, module.Module.Path, module.Module.Path, module.Module.Path)
 			} else {
@@ -253,8 +421,28 @@ func (module *runningModule) runNow(ctx context.Context) error {
 	if module.Module.AssumeAlreadyApplied {
 		module.Module.TerragruntOptions.Logger.Debugf("Assuming module %s has already been applied and skipping it", module.Module.Path)
 		return nil
+	} else if module.AlreadySucceeded {
+		module.Module.TerragruntOptions.Logger.Debugf("Module %s already succeeded in the run queue, skipping it", module.Module.Path)
+		return nil
 	} else {
+		if module.Module.runLock != nil {
+			module.Module.runLock.Lock()
+			defer module.Module.runLock.Unlock()
+		}
+
 		module.Module.TerragruntOptions.Logger.Debugf("Running module %s now", module.Module.Path)
+		module.StartedAt = time.Now()
+		defer func() {
+			module.FinishedAt = time.Now()
+			module.Duration = module.FinishedAt.Sub(module.StartedAt)
+		}()
+
+		if module.Module.TerragruntOptions.BufferOutput {
+			flushOutput := module.bufferOutput()
+			err := module.Module.TerragruntOptions.RunTerragrunt(ctx, module.Module.TerragruntOptions)
+			flushOutput(err)
+			return err
+		}
 		return module.Module.TerragruntOptions.RunTerragrunt(ctx, module.Module.TerragruntOptions)
 	}
 }
@@ -262,9 +450,11 @@ func (module *runningModule) runNow(ctx context.Context) error {
 // Record that a module has finished executing and notify all of this module's dependencies
 func (module *runningModule) moduleFinished(moduleErr error) {
 	if moduleErr == nil {
-		module.Module.TerragruntOptions.Logger.Debugf("Module %s has finished successfully!", module.Module.Path)
+		module.Module.TerragruntOptions.Logger.WithField(util.LogFieldDuration, module.Duration.String()).Debugf("Module %s has finished successfully!", module.Module.Path)
+	} else if IsPlanWithChanges(module.Module.TerragruntOptions, moduleErr) {
+		module.Module.TerragruntOptions.Logger.WithField(util.LogFieldDuration, module.Duration.String()).Infof("Module %s has finished with changes in its plan", module.Module.Path)
 	} else {
-		module.Module.TerragruntOptions.Logger.Errorf("Module %s has finished with an error: %v", module.Module.Path, moduleErr)
+		module.Module.TerragruntOptions.Logger.WithField(util.LogFieldDuration, module.Duration.String()).Errorf("Module %s has finished with an error: %v", module.Module.Path, moduleErr)
 	}
 
 	module.Status = Finished
@@ -294,6 +484,31 @@ func (this DependencyFinishedWithError) ExitStatus() (int, error) {
 	return -1, this
 }
 
+// ModuleSkippedByUser is returned for modules the user chose to skip when asked to confirm their changes, and for the
+// modules depending on them.
+type ModuleSkippedByUser struct {
+	Module *TerraformModule
+	// The skipped dependency, if the module is skipped because one of its dependencies was skipped
+	SkippedDependency *TerraformModule
+}
+
+func (err ModuleSkippedByUser) Error() string {
+	if err.SkippedDependency != nil {
+		return fmt.Sprintf("Module %s was skipped because its dependency %s was skipped", err.Module.Path, err.SkippedDependency.Path)
+	}
+	if err.Module == nil {
+		return "Module was skipped by the user"
+	}
+	return fmt.Sprintf("Module %s was skipped by the user", err.Module.Path)
+}
+
+// RunAbortedByUser is returned when the user aborts the run when asked to confirm the changes of a module.
+type RunAbortedByUser struct{}
+
+func (err RunAbortedByUser) Error() string {
+	return "The run was aborted by the user"
+}
+
 type DependencyNotFoundWhileCrossLinking struct {
 	Module     *runningModule
 	Dependency *TerraformModule
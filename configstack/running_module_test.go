@@ -3,11 +3,14 @@ package configstack
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var mockOptions, _ = options.NewTerragruntOptionsForTest("running_module_test")
@@ -1076,6 +1079,73 @@ func TestRunModulesMultipleModulesWithDependenciesOneFailure(t *testing.T) {
 	assert.False(t, cRan)
 }
 
+func TestRunModulesMultipleModulesWithDependenciesOneSkippedByUser(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "a", ModuleSkippedByUser{}, &aRan),
+	}
+
+	bRan := false
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{moduleA},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "b", nil, &bRan),
+	}
+
+	cRan := false
+	moduleC := &TerraformModule{
+		Path:              "c",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "c", nil, &cRan),
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest("")
+	assert.NoError(t, err)
+
+	err = RunModules(context.Background(), opts, []*TerraformModule{moduleA, moduleB, moduleC}, options.DefaultParallelism)
+	assert.NoError(t, err)
+
+	assert.True(t, aRan)
+	assert.False(t, bRan)
+	assert.True(t, cRan)
+}
+
+func TestRunModulesAbortedByUser(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "a", RunAbortedByUser{}, &aRan),
+	}
+
+	bRan := false
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{moduleA},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "b", nil, &bRan),
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest("")
+	assert.NoError(t, err)
+
+	err = RunModules(context.Background(), opts, []*TerraformModule{moduleA, moduleB}, options.DefaultParallelism)
+	assertMultiErrorContains(t, err, RunAbortedByUser{}, RunAbortedByUser{})
+
+	assert.True(t, aRan)
+	assert.False(t, bRan)
+}
+
 func TestRunModulesMultipleModulesWithDependenciesOneFailureIgnoreDependencyErrors(t *testing.T) {
 	t.Parallel()
 
@@ -1783,3 +1853,85 @@ func TestRemoveFlagExcludedOneExcludeWithDependencies(t *testing.T) {
 
 	assertRunningModuleMapsEqual(t, expected, actual, true)
 }
+
+func TestRunModulesDoesNotWaitForUnrelatedModulesOfPreviousGroup(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+	require.NoError(t, err)
+
+	childRan := make(chan struct{})
+	newModule := func(path string, run func() error) *TerraformModule {
+		moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+			return run()
+		}
+		return &TerraformModule{Path: path, TerragruntOptions: moduleOpts}
+	}
+
+	// The slow module of the first group only finishes once the child of the fast module of the same group ran
+	slow := newModule("/live/slow", func() error {
+		select {
+		case <-childRan:
+			return nil
+		case <-time.After(5 * time.Second):
+			return fmt.Errorf("the child of the fast module waited for the slow module")
+		}
+	})
+	fast := newModule("/live/fast", func() error { return nil })
+	child := newModule("/live/child", func() error {
+		close(childRan)
+		return nil
+	})
+	child.Dependencies = []*TerraformModule{fast}
+
+	require.NoError(t, RunModules(context.Background(), opts, []*TerraformModule{slow, fast, child}, options.DefaultParallelism))
+}
+
+func TestRunModulesSkippedDependencyWarning(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		warningsAsErrors bool
+		suppressWarnings []string
+		expectWarningErr bool
+	}{
+		{"warning", false, nil, false},
+		{"warning-as-error", true, nil, true},
+		{"suppressed-warning-as-error", true, []string{options.WarningModuleSkippedWithDependency}, false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest("running_module_test")
+			require.NoError(t, err)
+			opts.WarningsAsErrors = testCase.warningsAsErrors
+			opts.SuppressWarnings = testCase.suppressWarnings
+
+			newModule := func(path string, runErr error) *TerraformModule {
+				moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+				moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+					return runErr
+				}
+				return &TerraformModule{Path: path, TerragruntOptions: moduleOpts}
+			}
+
+			vpc := newModule("/live/vpc", ModuleSkippedByUser{})
+			app := newModule("/live/app", nil)
+			app.Dependencies = []*TerraformModule{vpc}
+
+			err = RunModules(context.Background(), opts, []*TerraformModule{vpc, app}, options.DefaultParallelism)
+			if !testCase.expectWarningErr {
+				require.NoError(t, err)
+				return
+			}
+			var warningErr options.WarningAsError
+			require.ErrorAs(t, err, &warningErr)
+			assert.Equal(t, options.WarningModuleSkippedWithDependency, warningErr.Code)
+		})
+	}
+}
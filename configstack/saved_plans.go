@@ -0,0 +1,216 @@
+package configstack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The manifest of the plans saved with --terragrunt-save-plans, in the plans dir.
+const SavedPlansManifestFile = "manifest.json"
+
+// SavedPlansManifest is the manifest of the plans of the modules saved by run-all plan with --terragrunt-save-plans,
+// which run-all apply with --terragrunt-use-plans verifies the stack against before applying the plans, so that the
+// apply runs the exact plans, of the same modules, in the same order.
+type SavedPlansManifest struct {
+	RunID     string    `json:"run_id"`
+	CreatedAt time.Time `json:"created_at"`
+	// The modules in run order
+	Modules []*SavedPlan `json:"modules"`
+}
+
+// SavedPlan is the plan of a module in the manifest of the saved plans.
+type SavedPlan struct {
+	// The path of the module, relative to the working dir
+	Path     string `json:"path"`
+	RunGroup int    `json:"run_group"`
+	// The paths of the dependencies of the module, relative to the working dir
+	Dependencies []string `json:"dependencies"`
+	// The plan file, relative to the plans dir, and the SHA256 of its content
+	PlanFile string `json:"plan_file"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// savedPlanFile returns the file, in the given plans dir, the plan of the module at the given path, relative to the
+// working dir, is saved to.
+func savedPlanFile(plansDir string, relPath string) string {
+	return filepath.Join(plansDir, util.FolderPathAsFile(relPath)+terraform.TerraformPlanFileExtension)
+}
+
+// newSavedPlansManifest returns the manifest of the plans of the modules of the stack which run, without their checksums.
+func (stack *Stack) newSavedPlansManifest(terragruntOptions *options.TerragruntOptions) (*SavedPlansManifest, error) {
+	runGraph, err := stack.getModuleRunGraph(terragruntOptions.TerraformCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &SavedPlansManifest{RunID: terragruntOptions.RunID, Modules: []*SavedPlan{}}
+	for i, group := range runGraph {
+		for _, module := range group {
+			relPath, err := util.GetPathRelativeTo(module.Path, terragruntOptions.WorkingDir)
+			if err != nil {
+				return nil, err
+			}
+
+			dependencies := []string{}
+			for _, dependency := range module.Dependencies {
+				relDependency, err := util.GetPathRelativeTo(dependency.Path, terragruntOptions.WorkingDir)
+				if err != nil {
+					return nil, err
+				}
+				dependencies = append(dependencies, relDependency)
+			}
+			sort.Strings(dependencies)
+
+			manifest.Modules = append(manifest.Modules, &SavedPlan{
+				Path:         relPath,
+				RunGroup:     i + 1,
+				Dependencies: dependencies,
+				PlanFile:     filepath.Base(savedPlanFile("", relPath)),
+			})
+		}
+	}
+	return manifest, nil
+}
+
+// RemoveSavedPlansManifest removes the manifest of the plans saved to the plans dir of --terragrunt-save-plans by a
+// previous run, so that the plans are only applied once all the modules of this run are planned.
+func RemoveSavedPlansManifest(terragruntOptions *options.TerragruntOptions) error {
+	if err := os.Remove(filepath.Join(terragruntOptions.SavePlansDir, SavedPlansManifestFile)); err != nil && !os.IsNotExist(err) {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// WriteSavedPlansManifest writes the manifest of the plans of the stack, which run-all plan saved to the plans dir of
+// --terragrunt-save-plans, with the checksums of the plan files.
+func (stack *Stack) WriteSavedPlansManifest(terragruntOptions *options.TerragruntOptions) error {
+	manifest, err := stack.newSavedPlansManifest(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	manifest.CreatedAt = time.Now().UTC()
+
+	for _, plan := range manifest.Modules {
+		if plan.Checksum, err = fileChecksum(filepath.Join(terragruntOptions.SavePlansDir, plan.PlanFile)); err != nil {
+			return err
+		}
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(filepath.Join(terragruntOptions.SavePlansDir, SavedPlansManifestFile), content, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	terragruntOptions.Logger.Infof("Saved the plans of %d modules to %s", len(manifest.Modules), terragruntOptions.SavePlansDir)
+	return nil
+}
+
+// ReadSavedPlansManifest reads the manifest of the plans saved to the given plans dir.
+func ReadSavedPlansManifest(plansDir string) (*SavedPlansManifest, error) {
+	manifestFile := filepath.Join(plansDir, SavedPlansManifestFile)
+	if !util.FileExists(manifestFile) {
+		return nil, errors.WithStackTrace(SavedPlansManifestNotFound(plansDir))
+	}
+
+	content, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	manifest := &SavedPlansManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, errors.WithStackTrace(InvalidSavedPlansManifest{Path: manifestFile, Err: err})
+	}
+	return manifest, nil
+}
+
+// VerifySavedPlans returns an error if the modules of the stack which run, their run groups or their dependencies,
+// differ from the ones of the manifest of the plans saved to the plans dir of --terragrunt-use-plans, or if a plan file
+// is missing or was modified since it was saved.
+func (stack *Stack) VerifySavedPlans(terragruntOptions *options.TerragruntOptions) error {
+	saved, err := ReadSavedPlansManifest(terragruntOptions.UsePlansDir)
+	if err != nil {
+		return err
+	}
+
+	current, err := stack.newSavedPlansManifest(terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if differences := savedPlansDifferences(saved, current); len(differences) > 0 {
+		return errors.WithStackTrace(SavedPlansMismatch{Dir: terragruntOptions.UsePlansDir, Differences: differences})
+	}
+
+	for _, plan := range saved.Modules {
+		planFile := filepath.Join(terragruntOptions.UsePlansDir, plan.PlanFile)
+		if !util.FileExists(planFile) {
+			return errors.WithStackTrace(SavedPlanNotFound{Module: plan.Path, PlanFile: planFile})
+		}
+		checksum, err := fileChecksum(planFile)
+		if err != nil {
+			return err
+		}
+		if checksum != plan.Checksum {
+			return errors.WithStackTrace(SavedPlanChecksumMismatch{Module: plan.Path, PlanFile: planFile})
+		}
+	}
+
+	terragruntOptions.Logger.Infof("Applying the plans of %d modules saved by the run %s to %s", len(saved.Modules), saved.RunID, terragruntOptions.UsePlansDir)
+	return nil
+}
+
+// savedPlansDifferences returns the differences between the modules of the saved manifest and of the current one: the
+// modules planned but no longer in the stack, the modules of the stack which were not planned, and the modules whose
+// run group or dependencies changed.
+func savedPlansDifferences(saved *SavedPlansManifest, current *SavedPlansManifest) []string {
+	savedPlans := map[string]*SavedPlan{}
+	for _, plan := range saved.Modules {
+		savedPlans[plan.Path] = plan
+	}
+
+	var differences []string
+	currentPaths := map[string]bool{}
+	for _, plan := range current.Modules {
+		currentPaths[plan.Path] = true
+
+		savedPlan, ok := savedPlans[plan.Path]
+		switch {
+		case !ok:
+			differences = append(differences, fmt.Sprintf("%s was not planned", plan.Path))
+		case savedPlan.RunGroup != plan.RunGroup:
+			differences = append(differences, fmt.Sprintf("%s runs in group %d, but was planned in group %d", plan.Path, plan.RunGroup, savedPlan.RunGroup))
+		case !util.ListEquals(savedPlan.Dependencies, plan.Dependencies):
+			differences = append(differences, fmt.Sprintf("%s depends on %v, but was planned depending on %v", plan.Path, plan.Dependencies, savedPlan.Dependencies))
+		}
+	}
+	for _, plan := range saved.Modules {
+		if !currentPaths[plan.Path] {
+			differences = append(differences, fmt.Sprintf("%s was planned, but is not in the stack", plan.Path))
+		}
+	}
+	return differences
+}
+
+func fileChecksum(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
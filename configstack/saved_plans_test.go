@@ -0,0 +1,114 @@
+package configstack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSavedPlansTestStack(t *testing.T) (*Stack, *options.TerragruntOptions) {
+	t.Helper()
+
+	workingDir := t.TempDir()
+	vpc := &TerraformModule{Path: filepath.Join(workingDir, "vpc")}
+	db := &TerraformModule{Path: filepath.Join(workingDir, "db"), Dependencies: []*TerraformModule{vpc}}
+	app := &TerraformModule{Path: filepath.Join(workingDir, "app"), Dependencies: []*TerraformModule{vpc, db}}
+	stack := &Stack{Path: workingDir, Modules: []*TerraformModule{vpc, db, app}}
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = workingDir
+	terragruntOptions.RunID = "20240102T030405Z-abc123"
+	terragruntOptions.SavePlansDir = filepath.Join(t.TempDir(), "plans")
+	terragruntOptions.TerraformCommand = "plan"
+	require.NoError(t, os.MkdirAll(terragruntOptions.SavePlansDir, os.ModePerm))
+
+	// The plans of the modules, as run-all plan saves them
+	for _, path := range []string{"vpc", "db", "app"} {
+		require.NoError(t, os.WriteFile(savedPlanFile(terragruntOptions.SavePlansDir, path), []byte("plan of "+path), 0644))
+	}
+	return stack, terragruntOptions
+}
+
+func TestSavedPlansManifest(t *testing.T) {
+	t.Parallel()
+
+	stack, terragruntOptions := newSavedPlansTestStack(t)
+	require.NoError(t, stack.WriteSavedPlansManifest(terragruntOptions))
+
+	manifest, err := ReadSavedPlansManifest(terragruntOptions.SavePlansDir)
+	require.NoError(t, err)
+	assert.Equal(t, "20240102T030405Z-abc123", manifest.RunID)
+	require.Len(t, manifest.Modules, 3)
+	assert.Equal(t, &SavedPlan{Path: "vpc", RunGroup: 1, Dependencies: []string{}, PlanFile: "vpc.tfplan", Checksum: manifest.Modules[0].Checksum}, manifest.Modules[0])
+	assert.Equal(t, &SavedPlan{Path: "app", RunGroup: 3, Dependencies: []string{"db", "vpc"}, PlanFile: "app.tfplan", Checksum: manifest.Modules[2].Checksum}, manifest.Modules[2])
+	assert.Len(t, manifest.Modules[0].Checksum, 64)
+
+	// The apply verifies the same stack against the manifest
+	applyOptions := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	applyOptions.SavePlansDir, applyOptions.UsePlansDir, applyOptions.TerraformCommand = "", terragruntOptions.SavePlansDir, "apply"
+	require.NoError(t, stack.VerifySavedPlans(applyOptions))
+
+	require.NoError(t, RemoveSavedPlansManifest(terragruntOptions))
+	_, err = ReadSavedPlansManifest(terragruntOptions.SavePlansDir)
+	assert.Equal(t, SavedPlansManifestNotFound(terragruntOptions.SavePlansDir), errors.Unwrap(err))
+}
+
+func TestVerifySavedPlansMismatch(t *testing.T) {
+	t.Parallel()
+
+	stack, terragruntOptions := newSavedPlansTestStack(t)
+	require.NoError(t, stack.WriteSavedPlansManifest(terragruntOptions))
+
+	applyOptions := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	applyOptions.SavePlansDir, applyOptions.UsePlansDir, applyOptions.TerraformCommand = "", terragruntOptions.SavePlansDir, "apply"
+
+	// A new module, and app no longer depending on db, so that app runs in another group
+	cache := &TerraformModule{Path: filepath.Join(stack.Path, "cache")}
+	stack.Modules[2].Dependencies = []*TerraformModule{stack.Modules[0]}
+	stack.Modules = append(stack.Modules, cache)
+
+	err := stack.VerifySavedPlans(applyOptions)
+	var mismatch SavedPlansMismatch
+	require.ErrorAs(t, errors.Unwrap(err), &mismatch)
+	assert.Equal(t, []string{
+		"cache was not planned",
+		"app runs in group 2, but was planned in group 3",
+	}, mismatch.Differences)
+
+	// A plan file modified since it was saved
+	stack.Modules[2].Dependencies = []*TerraformModule{stack.Modules[0], stack.Modules[1]}
+	stack.Modules = stack.Modules[:3]
+	require.NoError(t, os.WriteFile(savedPlanFile(terragruntOptions.SavePlansDir, "db"), []byte("another plan"), 0644))
+
+	err = stack.VerifySavedPlans(applyOptions)
+	assert.Equal(t, SavedPlanChecksumMismatch{Module: "db", PlanFile: savedPlanFile(terragruntOptions.SavePlansDir, "db")}, errors.Unwrap(err))
+}
+
+func TestSyncTerraformCliArgsSavedPlans(t *testing.T) {
+	t.Parallel()
+
+	stack, terragruntOptions := newSavedPlansTestStack(t)
+	for _, module := range stack.Modules {
+		module.TerragruntOptions = terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	}
+	terragruntOptions.TerraformCliArgs = []string{"plan", "-input=false"}
+
+	stack.syncTerraformCliArgs(terragruntOptions)
+	assert.Equal(t, []string{"plan", "-input=false", "-out=" + savedPlanFile(terragruntOptions.SavePlansDir, "db")}, stack.Modules[1].TerragruntOptions.TerraformCliArgs)
+
+	plansDir := terragruntOptions.SavePlansDir
+	terragruntOptions.SavePlansDir, terragruntOptions.UsePlansDir = "", plansDir
+	terragruntOptions.TerraformCliArgs = []string{"apply", "-input=false", "-auto-approve"}
+	for _, module := range stack.Modules {
+		module.TerragruntOptions.TerraformCommand = "apply"
+	}
+
+	stack.syncTerraformCliArgs(terragruntOptions)
+	assert.Equal(t, []string{"apply", "-input=false", "-auto-approve", savedPlanFile(plansDir, "db")}, stack.Modules[1].TerragruntOptions.TerraformCliArgs)
+}
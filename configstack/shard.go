@@ -0,0 +1,382 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/dynamodb"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/util"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	// Prefix of the LockID of ledger items, so that a Terraform lock table can be reused for the shard ledger.
+	shardLedgerIDPrefix = "terragrunt-shard-ledger/"
+
+	shardLedgerStatusAttr = "Status"
+
+	// How often a worker checks the ledger for a module run by another shard.
+	shardLedgerPollInterval = 10 * time.Second
+)
+
+// Shard is the part of the modules of a run-all that a worker runs, set with --terragrunt-shard N/M.
+type Shard struct {
+	// The 1-based index of the shard.
+	Index int
+	Count int
+}
+
+// ParseShard parses the shard in the N/M format, e.g. 2/4 for the second of four shards.
+func ParseShard(value string) (Shard, error) {
+	indexStr, countStr, found := strings.Cut(value, "/")
+	if !found {
+		return Shard{}, errors.WithStackTrace(InvalidShard(value))
+	}
+
+	index, indexErr := strconv.Atoi(indexStr)
+	count, countErr := strconv.Atoi(countStr)
+	if indexErr != nil || countErr != nil || count < 1 || index < 1 || index > count {
+		return Shard{}, errors.WithStackTrace(InvalidShard(value))
+	}
+	return Shard{Index: index, Count: count}, nil
+}
+
+// Owns returns true if the module with the given path, relative to the working dir, belongs to the shard. Modules are
+// assigned by a hash of their path so that every worker agrees on the assignment without coordinating.
+func (shard Shard) Owns(relPath string) bool {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(filepath.ToSlash(relPath)))
+	return int(hash.Sum32()%uint32(shard.Count)) == shard.Index-1
+}
+
+func (shard Shard) String() string {
+	return fmt.Sprintf("%d/%d", shard.Index, shard.Count)
+}
+
+// ShardLedger records which modules finished, and how, so that the workers of the other shards can run the modules
+// depending on them.
+type ShardLedger interface {
+	// SetStatus records the status of the given module, keyed by its path relative to the working dir.
+	SetStatus(ctx context.Context, module string, status string) error
+	// Status returns the recorded status of the given module, or an empty string if none is recorded yet.
+	Status(ctx context.Context, module string) (string, error)
+}
+
+// NewShardLedger creates a shard ledger for the given command from the given location, which can be:
+//
+//   - dynamodb://<table>/<ledger-id>?region=<region> - items in a DynamoDB table with a `LockID` primary key, such as
+//     a Terraform lock table.
+//   - gcs://<bucket>/<ledger-id> - objects in a GCS bucket.
+//   - a path to a dir shared by the workers, e.g. on a network file system.
+//
+// The ledger ID should be unique to the run, e.g. the ID of the CI pipeline, so that the statuses of previous runs are
+// not picked up.
+func NewShardLedger(location string, command string, terragruntOptions *options.TerragruntOptions) (ShardLedger, error) {
+	if !strings.Contains(location, "://") {
+		return &dirShardLedger{dir: filepath.Join(location, command)}, nil
+	}
+
+	parsedURL, err := url.Parse(location)
+	if err != nil || parsedURL.Host == "" {
+		return nil, errors.WithStackTrace(InvalidShardLedgerLocation(location))
+	}
+	ledgerID := path.Join(strings.Trim(parsedURL.Path, "/"), command)
+
+	switch parsedURL.Scheme {
+	case stackLockDynamoDBScheme:
+		return &dynamoDBShardLedger{
+			tableName:         parsedURL.Host,
+			ledgerID:          shardLedgerIDPrefix + ledgerID,
+			region:            parsedURL.Query().Get("region"),
+			terragruntOptions: terragruntOptions,
+		}, nil
+	case stackLockGCSScheme:
+		return &gcsShardLedger{bucket: parsedURL.Host, prefix: shardLedgerIDPrefix + ledgerID}, nil
+	default:
+		return nil, errors.WithStackTrace(InvalidShardLedgerLocation(location))
+	}
+}
+
+type dirShardLedger struct {
+	dir string
+}
+
+func (ledger *dirShardLedger) SetStatus(ctx context.Context, module string, status string) error {
+	statusPath := filepath.Join(ledger.dir, module, "status")
+	if err := os.MkdirAll(filepath.Dir(statusPath), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(statusPath, []byte(status), 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+func (ledger *dirShardLedger) Status(ctx context.Context, module string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(ledger.dir, module, "status"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return string(content), nil
+}
+
+type dynamoDBShardLedger struct {
+	tableName         string
+	ledgerID          string
+	region            string
+	terragruntOptions *options.TerragruntOptions
+}
+
+func (ledger *dynamoDBShardLedger) key(module string) map[string]*awsdynamodb.AttributeValue {
+	return map[string]*awsdynamodb.AttributeValue{
+		dynamodb.ATTR_LOCK_ID: {S: aws.String(ledger.ledgerID + "/" + filepath.ToSlash(module))},
+	}
+}
+
+func (ledger *dynamoDBShardLedger) SetStatus(ctx context.Context, module string, status string) error {
+	client, err := dynamodb.CreateDynamoDbClient(&aws_helper.AwsSessionConfig{Region: ledger.region}, ledger.terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	item := ledger.key(module)
+	item[shardLedgerStatusAttr] = &awsdynamodb.AttributeValue{S: aws.String(status)}
+	if _, err := client.PutItemWithContext(ctx, &awsdynamodb.PutItemInput{TableName: aws.String(ledger.tableName), Item: item}); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+func (ledger *dynamoDBShardLedger) Status(ctx context.Context, module string) (string, error) {
+	client, err := dynamodb.CreateDynamoDbClient(&aws_helper.AwsSessionConfig{Region: ledger.region}, ledger.terragruntOptions)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := client.GetItemWithContext(ctx, &awsdynamodb.GetItemInput{
+		TableName:      aws.String(ledger.tableName),
+		Key:            ledger.key(module),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	if output.Item == nil || output.Item[shardLedgerStatusAttr] == nil {
+		return "", nil
+	}
+	return aws.StringValue(output.Item[shardLedgerStatusAttr].S), nil
+}
+
+type gcsShardLedger struct {
+	bucket string
+	prefix string
+}
+
+func (ledger *gcsShardLedger) object(module string) string {
+	return ledger.prefix + "/" + filepath.ToSlash(module)
+}
+
+func (ledger *gcsShardLedger) SetStatus(ctx context.Context, module string, status string) error {
+	client, err := remote.CreateGCSClient(remote.RemoteStateConfigGCS{})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer client.Close() //nolint:errcheck
+
+	writer := client.Bucket(ledger.bucket).Object(ledger.object(module)).NewWriter(ctx)
+	writer.ContentType = "text/plain"
+	if _, err := writer.Write([]byte(status)); err != nil {
+		_ = writer.Close()
+		return errors.WithStackTrace(err)
+	}
+	if err := writer.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+func (ledger *gcsShardLedger) Status(ctx context.Context, module string) (string, error) {
+	client, err := remote.CreateGCSClient(remote.RemoteStateConfigGCS{})
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	defer client.Close() //nolint:errcheck
+
+	reader, err := client.Bucket(ledger.bucket).Object(ledger.object(module)).NewReader(ctx)
+	if err != nil {
+		if apiErr, ok := err.(*googleapi.Error); err == storage.ErrObjectNotExist || (ok && apiErr.Code == http.StatusNotFound) {
+			return "", nil
+		}
+		return "", errors.WithStackTrace(err)
+	}
+	defer reader.Close() //nolint:errcheck
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return string(content), nil
+}
+
+// runShard tracks the modules of a sharded run-all: the modules of this shard are run and their status recorded in the
+// ledger, the modules of the other shards that this shard depends on are waited for in the ledger.
+type runShard struct {
+	shard      Shard
+	ledger     ShardLedger
+	workingDir string
+}
+
+// shardModules keeps the modules of the given shard and the modules of other shards they depend on, directly or not,
+// dropping the rest. The modules of other shards are set to wait for their status in the ledger instead of running.
+func shardModules(opts *options.TerragruntOptions, modules map[string]*runningModule) (map[string]*runningModule, *runShard, error) {
+	shard, err := ParseShard(opts.Shard)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sharded := map[string]*runningModule{}
+	var addWithDependencies func(module *runningModule)
+	addWithDependencies = func(module *runningModule) {
+		if _, ok := sharded[module.Module.Path]; ok {
+			return
+		}
+		sharded[module.Module.Path] = module
+		for _, dependency := range module.Dependencies {
+			if dependency, ok := modules[dependency.Module.Path]; ok {
+				addWithDependencies(dependency)
+			}
+		}
+	}
+
+	owned := map[string]bool{}
+	for path, module := range modules {
+		relPath, err := util.GetPathRelativeTo(path, opts.WorkingDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		if shard.Owns(relPath) {
+			owned[path] = true
+			addWithDependencies(module)
+		}
+	}
+
+	run := &runShard{shard: shard, workingDir: opts.WorkingDir}
+	if len(sharded) > len(owned) || opts.ShardLedger != "" {
+		if opts.ShardLedger == "" {
+			return nil, nil, errors.WithStackTrace(MissingShardLedger(shard.String()))
+		}
+		if run.ledger, err = NewShardLedger(opts.ShardLedger, opts.TerraformCommand, opts); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for path, module := range sharded {
+		if !owned[path] {
+			module.OtherShard = true
+		}
+	}
+
+	opts.Logger.Infof("Running %d of %d modules in shard %s, waiting for %d modules of other shards", len(owned), len(modules), shard, len(sharded)-len(owned))
+	return sharded, run, nil
+}
+
+// waitForModule waits until the given module of another shard is recorded in the ledger as finished.
+func (run *runShard) waitForModule(ctx context.Context, module *runningModule) error {
+	relPath, err := util.GetPathRelativeTo(module.Module.Path, run.workingDir)
+	if err != nil {
+		return err
+	}
+
+	module.Module.TerragruntOptions.Logger.Infof("Waiting for module %s to finish in another shard", module.Module.Path)
+	for {
+		status, err := run.ledger.Status(ctx, relPath)
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case RunQueueStatusSucceeded:
+			module.Module.TerragruntOptions.Logger.Debugf("Module %s finished successfully in another shard", module.Module.Path)
+			return nil
+		case RunQueueStatusFailed, RunQueueStatusSkipped:
+			return errors.WithStackTrace(ShardModuleFailed{Module: module.Module.Path, Status: status})
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.WithStackTrace(ctx.Err())
+		case <-time.After(shardLedgerPollInterval):
+		}
+	}
+}
+
+// waitForModuleWhenReady stands in for running the given module of another shard: the module is finished when it is
+// recorded as finished in the ledger.
+func (run *runShard) waitForModuleWhenReady(ctx context.Context, module *runningModule, progress *runProgress) {
+	err := run.waitForModule(ctx, module)
+	module.moduleFinished(err)
+	progress.moduleFinished(module)
+}
+
+// moduleFinished records the status of the given module of this shard in the ledger.
+func (run *runShard) moduleFinished(ctx context.Context, module *runningModule) error {
+	if run.ledger == nil || module.OtherShard {
+		return nil
+	}
+
+	relPath, err := util.GetPathRelativeTo(module.Module.Path, run.workingDir)
+	if err != nil {
+		return err
+	}
+
+	return run.ledger.SetStatus(ctx, relPath, module.runStatus())
+}
+
+// Custom error types
+
+type InvalidShard string
+
+func (shard InvalidShard) Error() string {
+	return fmt.Sprintf("Invalid shard %s. Expected N/M, the shard N of M shards, with N from 1 to M.", string(shard))
+}
+
+type InvalidShardLedgerLocation string
+
+func (location InvalidShardLedgerLocation) Error() string {
+	return fmt.Sprintf("Invalid shard ledger location %s. Expected dynamodb://<table>/<ledger-id>[?region=<region>], gcs://<bucket>/<ledger-id> or the path to a shared dir.", string(location))
+}
+
+type MissingShardLedger string
+
+func (shard MissingShardLedger) Error() string {
+	return fmt.Sprintf("The modules of shard %s depend on modules of other shards, --terragrunt-shard-ledger is required to wait for them.", string(shard))
+}
+
+type ShardModuleFailed struct {
+	Module string
+	Status string
+}
+
+func (err ShardModuleFailed) Error() string {
+	return fmt.Sprintf("Module %s was %s in another shard", err.Module, err.Status)
+}
@@ -0,0 +1,105 @@
+package configstack
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShard(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value       string
+		expected    Shard
+		expectedErr bool
+	}{
+		{"1/1", Shard{Index: 1, Count: 1}, false},
+		{"2/4", Shard{Index: 2, Count: 4}, false},
+		{"0/4", Shard{}, true},
+		{"5/4", Shard{}, true},
+		{"1/0", Shard{}, true},
+		{"2", Shard{}, true},
+		{"a/b", Shard{}, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.value, func(t *testing.T) {
+			t.Parallel()
+
+			shard, err := ParseShard(testCase.value)
+			if testCase.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, shard)
+		})
+	}
+}
+
+func TestShardModules(t *testing.T) {
+	t.Parallel()
+
+	ledgerDir := t.TempDir()
+
+	opts, err := options.NewTerragruntOptionsForTest("shard_test")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.TerraformCommand = "apply"
+
+	// With 2 shards, a and c belong to the first shard and b to the second one
+	a := &TerraformModule{Path: "/live/a", TerragruntOptions: opts}
+	b := &TerraformModule{Path: "/live/b", Dependencies: []*TerraformModule{a}, TerragruntOptions: opts}
+	c := &TerraformModule{Path: "/live/c", Dependencies: []*TerraformModule{b}, TerragruntOptions: opts}
+
+	newModules := func() map[string]*runningModule {
+		modules, err := toRunningModules([]*TerraformModule{a, b, c}, NormalOrder)
+		require.NoError(t, err)
+		return modules
+	}
+
+	opts.Shard = "2/2"
+	_, _, err = shardModules(opts, newModules())
+	require.Error(t, err)
+	assert.IsType(t, MissingShardLedger(""), errors.Unwrap(err))
+
+	opts.ShardLedger = ledgerDir
+	modules, shard, err := shardModules(opts, newModules())
+	require.NoError(t, err)
+	assert.Len(t, modules, 2)
+	assert.True(t, modules["/live/a"].OtherShard)
+	assert.False(t, modules["/live/b"].OtherShard)
+
+	ledger, err := NewShardLedger(ledgerDir, "apply", opts)
+	require.NoError(t, err)
+	require.NoError(t, ledger.SetStatus(context.Background(), "a", RunQueueStatusSucceeded))
+	require.NoError(t, shard.waitForModule(context.Background(), modules["/live/a"]))
+
+	require.NoError(t, ledger.SetStatus(context.Background(), "a", RunQueueStatusFailed))
+	err = shard.waitForModule(context.Background(), modules["/live/a"])
+	require.Error(t, err)
+	assert.IsType(t, ShardModuleFailed{}, errors.Unwrap(err))
+
+	modules["/live/b"].Err = fmt.Errorf("b failed")
+	require.NoError(t, shard.moduleFinished(context.Background(), modules["/live/b"]))
+	status, err := ledger.Status(context.Background(), "b")
+	require.NoError(t, err)
+	assert.Equal(t, RunQueueStatusFailed, status)
+	assert.FileExists(t, filepath.Join(ledgerDir, "apply", "b", "status"))
+
+	opts.Shard = "1/2"
+	modules, _, err = shardModules(opts, newModules())
+	require.NoError(t, err)
+	assert.Len(t, modules, 3)
+	assert.True(t, modules["/live/b"].OtherShard)
+	assert.False(t, modules["/live/c"].OtherShard)
+}
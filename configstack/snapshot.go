@@ -0,0 +1,176 @@
+package configstack
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	snapshotManifestFile = "manifest.json"
+
+	// The dir, in the dir of the run, where every applied module records its part of the manifest.
+	snapshotModulesDir = "modules"
+)
+
+// SnapshotManifest pins exactly what a run-all apply deployed: the source, inputs, providers and outputs of every
+// applied module.
+type SnapshotManifest struct {
+	RunID      string            `json:"run_id"`
+	CreatedAt  string            `json:"created_at"`
+	WorkingDir string            `json:"working_dir"`
+	Modules    []*SnapshotModule `json:"modules"`
+}
+
+// SnapshotModule is the part of the snapshot manifest of an applied module.
+type SnapshotModule struct {
+	// The dir of the module, relative to the working dir of the run-all in the manifest.
+	Path string `json:"path"`
+	// The terraform source of the module, including the ref, empty for the code in the dir of the module.
+	Source string `json:"source,omitempty"`
+	// The hash of the terraform code applied, including the generated files.
+	SourceHash string `json:"source_hash"`
+	// The hash of the inputs of the module.
+	InputsHash string `json:"inputs_hash"`
+	// The versions of the providers, by provider address, from the dependency lock file.
+	Providers map[string]string          `json:"providers"`
+	Outputs   map[string]*SnapshotOutput `json:"outputs"`
+}
+
+// SnapshotOutput summarizes an output of a module without its value, which may be sensitive.
+type SnapshotOutput struct {
+	Type      json.RawMessage `json:"type"`
+	Sensitive bool            `json:"sensitive"`
+	ValueHash string          `json:"value_hash"`
+}
+
+// StartSnapshot sets the dir of the run, under the snapshot dir, in the options of the modules of the stack, so that
+// every applied module records its part of the snapshot manifest.
+func (stack *Stack) StartSnapshot(terragruntOptions *options.TerragruntOptions) {
+	if terragruntOptions.SnapshotRunID == "" {
+		terragruntOptions.SnapshotRunID = terragruntOptions.RunID
+	}
+	if terragruntOptions.SnapshotRunID == "" {
+		terragruntOptions.SnapshotRunID = options.NewRunID()
+	}
+	terragruntOptions.SnapshotRunDir = filepath.Join(terragruntOptions.SnapshotDir, terragruntOptions.SnapshotRunID)
+
+	for _, module := range stack.Modules {
+		module.TerragruntOptions.SnapshotRunID = terragruntOptions.SnapshotRunID
+		module.TerragruntOptions.SnapshotRunDir = terragruntOptions.SnapshotRunDir
+	}
+}
+
+// WriteSnapshotModule records the part of the snapshot manifest of the module applied with the given options, to be
+// merged into the manifest once the whole run succeeded.
+func WriteSnapshotModule(terragruntOptions *options.TerragruntOptions, module *SnapshotModule) error {
+	modulesDir := filepath.Join(terragruntOptions.SnapshotRunDir, snapshotModulesDir)
+	if err := os.MkdirAll(modulesDir, os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	content, err := json.Marshal(module)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(os.WriteFile(filepath.Join(modulesDir, util.EncodeBase64Sha1(module.Path)+".json"), content, 0644))
+}
+
+// writeSnapshotManifest merges the parts recorded by the applied modules into the manifest of the run.
+func writeSnapshotManifest(terragruntOptions *options.TerragruntOptions) (string, error) {
+	runDir := terragruntOptions.SnapshotRunDir
+	modulesDir := filepath.Join(runDir, snapshotModulesDir)
+
+	manifest := SnapshotManifest{
+		RunID:      terragruntOptions.SnapshotRunID,
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		WorkingDir: terragruntOptions.WorkingDir,
+		Modules:    []*SnapshotModule{},
+	}
+
+	files, err := filepath.Glob(filepath.Join(modulesDir, "*.json"))
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		module := &SnapshotModule{}
+		if err := json.Unmarshal(content, module); err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		if relPath, err := util.GetPathRelativeTo(module.Path, terragruntOptions.WorkingDir); err == nil {
+			module.Path = relPath
+		}
+		manifest.Modules = append(manifest.Modules, module)
+	}
+	sort.Slice(manifest.Modules, func(i, j int) bool {
+		return manifest.Modules[i].Path < manifest.Modules[j].Path
+	})
+
+	if err := os.MkdirAll(runDir, os.ModePerm); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	manifestFile := filepath.Join(runDir, snapshotManifestFile)
+	if err := os.WriteFile(manifestFile, append(content, '\n'), 0644); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return manifestFile, errors.WithStackTrace(os.RemoveAll(modulesDir))
+}
+
+// FinishSnapshot writes the snapshot manifest of the run if it succeeded, and otherwise discards the parts recorded
+// by the modules, as only the successful runs are snapshotted.
+func FinishSnapshot(terragruntOptions *options.TerragruntOptions, runErr error) error {
+	if runErr != nil {
+		terragruntOptions.Logger.Debugf("Not writing the snapshot manifest of the run %s, as the run failed", terragruntOptions.SnapshotRunID)
+		return errors.WithStackTrace(os.RemoveAll(terragruntOptions.SnapshotRunDir))
+	}
+
+	manifestFile, err := writeSnapshotManifest(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	terragruntOptions.Logger.Infof("Wrote the snapshot manifest of the run %s to %s", terragruntOptions.SnapshotRunID, manifestFile)
+	return nil
+}
+
+// ReadSnapshotManifest reads the snapshot manifest at the given path, which is either the manifest file or the dir of
+// the run it is in.
+func ReadSnapshotManifest(path string) (*SnapshotManifest, error) {
+	if util.IsDir(path) {
+		path = filepath.Join(path, snapshotManifestFile)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	manifest := &SnapshotManifest{}
+	if err := json.Unmarshal(content, manifest); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return manifest, nil
+}
+
+// FindModule returns the module of the manifest in the given dir, or nil if the module was not applied in the run.
+func (manifest *SnapshotManifest) FindModule(dir string) *SnapshotModule {
+	for _, module := range manifest.Modules {
+		if filepath.Join(manifest.WorkingDir, module.Path) == filepath.Clean(dir) {
+			return module
+		}
+	}
+	return nil
+}
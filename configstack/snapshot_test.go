@@ -0,0 +1,77 @@
+package configstack
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotManifest(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.WorkingDir = "/live"
+	opts.SnapshotDir = t.TempDir()
+	opts.SnapshotRunID = "run-1"
+
+	moduleOpts := opts.Clone("/live/vpc/terragrunt.hcl")
+	stack := &Stack{Modules: []*TerraformModule{{Path: "/live/vpc", TerragruntOptions: moduleOpts}}}
+	stack.StartSnapshot(opts)
+	assert.Equal(t, filepath.Join(opts.SnapshotDir, "run-1"), moduleOpts.SnapshotRunDir)
+
+	require.NoError(t, WriteSnapshotModule(moduleOpts, &SnapshotModule{Path: "/live/vpc", Source: "git::https://example.com/modules.git//vpc?ref=v1.0.0", Providers: map[string]string{"registry.terraform.io/hashicorp/aws": "5.31.0"}}))
+	require.NoError(t, WriteSnapshotModule(moduleOpts, &SnapshotModule{Path: "/live/app"}))
+	require.NoError(t, FinishSnapshot(opts, nil))
+
+	content, err := os.ReadFile(filepath.Join(opts.SnapshotDir, "run-1", snapshotManifestFile))
+	require.NoError(t, err)
+	var manifest SnapshotManifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	assert.Equal(t, "run-1", manifest.RunID)
+	require.Len(t, manifest.Modules, 2)
+	assert.Equal(t, "app", manifest.Modules[0].Path)
+	assert.Equal(t, "vpc", manifest.Modules[1].Path)
+	assert.Equal(t, "5.31.0", manifest.Modules[1].Providers["registry.terraform.io/hashicorp/aws"])
+	assert.NoDirExists(t, filepath.Join(opts.SnapshotDir, "run-1", snapshotModulesDir))
+
+	readManifest, err := ReadSnapshotManifest(filepath.Join(opts.SnapshotDir, "run-1"))
+	require.NoError(t, err)
+	assert.Equal(t, manifest, *readManifest)
+	assert.Equal(t, "git::https://example.com/modules.git//vpc?ref=v1.0.0", readManifest.FindModule("/live/vpc/").Source)
+	assert.Nil(t, readManifest.FindModule("/live/db"))
+}
+
+func TestSnapshotDiscardedOnFailure(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.SnapshotDir = t.TempDir()
+
+	(&Stack{}).StartSnapshot(opts)
+	assert.NotEmpty(t, opts.SnapshotRunID)
+
+	require.NoError(t, WriteSnapshotModule(opts, &SnapshotModule{Path: "/live/vpc"}))
+	require.NoError(t, FinishSnapshot(opts, errors.New("apply failed")))
+	assert.NoDirExists(t, opts.SnapshotRunDir)
+}
+
+func TestSnapshotRunIDDefaultsToRunID(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("/live/terragrunt.hcl")
+	require.NoError(t, err)
+	opts.SnapshotDir = t.TempDir()
+	opts.RunID = "20240501T100000Z-a1b2c3"
+
+	(&Stack{}).StartSnapshot(opts)
+	assert.Equal(t, "20240501T100000Z-a1b2c3", opts.SnapshotRunID)
+	assert.Equal(t, filepath.Join(opts.SnapshotDir, "20240501T100000Z-a1b2c3"), opts.SnapshotRunDir)
+}
@@ -3,7 +3,6 @@ package configstack
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -67,20 +66,32 @@ func (stack *Stack) JsonModuleDeployOrder(terraformCommand string) (string, erro
 		return "", err
 	}
 	// Convert the module paths to a string array for JSON marshalling
-	// The index should be the group number, and the value should be an array of module paths
-	jsonGraph := make(map[string][]string)
-	for i, group := range runGraph {
-		groupNum := "Group " + fmt.Sprintf("%d", i+1)
-		jsonGraph[groupNum] = make([]string, len(group))
+	// The key should be the group number, and the value should be an array of module paths
+	groups := orderedModuleGroups{}
+	for _, group := range runGraph {
+		paths := make([]string, len(group))
 		for j, module := range group {
-			jsonGraph[groupNum][j] = module.Path
+			paths[j] = module.Path
 		}
+		groups.groups = append(groups.groups, paths)
 	}
-	j, _ := json.MarshalIndent(jsonGraph, "", "  ")
+	return marshalModuleGroups(groups)
+}
+
+// setModuleRunGroups adds the run group of each module, as logged by LogModuleDeployOrder, to the logs of the module
+// and to its run report.
+func (stack *Stack) setModuleRunGroups(terragruntOptions *options.TerragruntOptions) {
+	runGraph, err := stack.getModuleRunGraph(terragruntOptions.TerraformCommand)
 	if err != nil {
-		return "", err
+		terragruntOptions.Logger.Debugf("Failed to compute the run groups of the modules: %v", err)
+		return
+	}
+	for i, group := range runGraph {
+		for _, module := range group {
+			module.runGroup = i + 1
+			module.TerragruntOptions.Logger = module.TerragruntOptions.Logger.WithField(util.LogFieldGroup, i+1)
+		}
 	}
-	return string(j), nil
 }
 
 // Graph creates a graphviz representation of the modules
@@ -94,6 +105,14 @@ func (stack *Stack) Graph(terragruntOptions *options.TerragruntOptions) {
 func (stack *Stack) Run(ctx context.Context, terragruntOptions *options.TerragruntOptions) error {
 	stackCmd := terragruntOptions.TerraformCommand
 
+	flushModuleOutput, err := stack.setModuleOutputPrefixes(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	defer flushModuleOutput()
+
+	stack.setModuleRunGroups(terragruntOptions)
+
 	// For any command that needs input, run in non-interactive mode to avoid cominglint stdin across multiple
 	// concurrent runs.
 	if util.ListContainsElement(config.TERRAFORM_COMMANDS_NEED_INPUT, stackCmd) {
@@ -127,6 +146,10 @@ func (stack *Stack) Run(ctx context.Context, terragruntOptions *options.Terragru
 		defer stack.summarizePlanAllErrors(terragruntOptions, errorStreams)
 	}
 
+	if err := stack.setModuleTargets(terragruntOptions); err != nil {
+		return err
+	}
+
 	switch {
 	case terragruntOptions.IgnoreDependencyOrder:
 		return RunModulesIgnoreOrder(ctx, terragruntOptions, stack.Modules, terragruntOptions.Parallelism)
@@ -166,9 +189,14 @@ func (stack *Stack) summarizePlanAllErrors(terragruntOptions *options.Terragrunt
 	}
 }
 
-// Return an error if there is a dependency cycle in the modules of this stack.
+// Return an error if there is a dependency cycle in the modules of this stack, with where each dependency of the cycle
+// is declared and the dependencies to break to remove it.
 func (stack *Stack) CheckForCycles() error {
-	return CheckForCycles(stack.Modules)
+	err := CheckForCycles(stack.Modules)
+	if cycle, ok := errors.Unwrap(err).(DependencyCycle); ok {
+		return errors.WithStackTrace(diagnoseDependencyCycle(stack.Modules, cycle))
+	}
+	return err
 }
 
 // Find all the Terraform modules in the subfolders of the working directory of the given TerragruntOptions and
@@ -210,13 +238,24 @@ func (stack *Stack) syncTerraformCliArgs(terragruntOptions *options.TerragruntOp
 				module.TerragruntOptions.TerraformCliArgs = util.StringListInsert(module.TerragruntOptions.TerraformCliArgs, planFile, len(module.TerragruntOptions.TerraformCliArgs))
 			}
 		}
+
+		// pass the saved plans, whose manifest RunAllOnStack verified
+		if relPath, err := util.GetPathRelativeTo(module.Path, terragruntOptions.WorkingDir); err == nil {
+			switch {
+			case terragruntOptions.SavePlansDir != "" && module.TerragruntOptions.TerraformCommand == terraform.CommandNamePlan:
+				module.TerragruntOptions.TerraformCliArgs = append(module.TerragruntOptions.TerraformCliArgs, "-out="+savedPlanFile(terragruntOptions.SavePlansDir, relPath))
+			case terragruntOptions.UsePlansDir != "" && module.TerragruntOptions.TerraformCommand == terraform.CommandNameApply:
+				module.TerragruntOptions.TerraformCliArgs = append(module.TerragruntOptions.TerraformCliArgs, savedPlanFile(terragruntOptions.UsePlansDir, relPath))
+			}
+		}
 	}
 }
 
 // getModuleRunGraph converts the module list to a graph that shows the order in which the modules will be
 // applied/destroyed. The return structure is a list of lists, where the nested list represents modules that can be
 // deployed concurrently, and the outer list indicates the order. This will only include those modules that do NOT have
-// the exclude flag set.
+// the exclude flag set. The groups are only shown to the user and used as the run groups of the modules: runModules
+// starts each module as soon as its own dependencies finish, without waiting for the whole previous group.
 func (stack *Stack) getModuleRunGraph(terraformCommand string) ([][]*TerraformModule, error) {
 	var moduleRunGraph map[string]*runningModule
 	var graphErr error
@@ -326,6 +365,13 @@ func createStackForTerragruntConfigPaths(ctx context.Context, path string, terra
 		return nil, errors.WithStackTrace(err)
 	}
 
+	// The workspaces are expanded once the cycles are checked, so that the cycles are reported between the modules
+	stack.Modules = expandModuleWorkspaces(stack.Modules)
+
+	if err := stack.flagUntargetedModules(terragruntOptions); err != nil {
+		return nil, err
+	}
+
 	return stack, nil
 }
 
@@ -338,3 +384,41 @@ type DependencyCycle []string
 func (err DependencyCycle) Error() string {
 	return fmt.Sprintf("Found a dependency cycle between modules: %s", strings.Join([]string(err), " -> "))
 }
+
+// DependencyCycleDiagnostics is a dependency cycle, with where each of its edges is declared and the edges suggested to
+// break.
+type DependencyCycleDiagnostics struct {
+	Cycle     DependencyCycle
+	Edges     []CycleEdge
+	Suggested []CycleEdge
+}
+
+func (err DependencyCycleDiagnostics) Error() string {
+	var str strings.Builder
+	str.WriteString(err.Cycle.Error())
+
+	for _, edge := range err.Edges {
+		str.WriteString(fmt.Sprintf("\n  - %s depends on %s", edge.From, edge.To))
+		for i, declaration := range edge.Declarations {
+			if i == 0 {
+				str.WriteString(": ")
+			} else {
+				str.WriteString(", ")
+			}
+			str.WriteString(declaration.String())
+		}
+	}
+
+	for _, edge := range err.Suggested {
+		str.WriteString(fmt.Sprintf("\nTo break the cycle, remove the dependency of %s on %s", edge.From, edge.To))
+		if len(edge.Declarations) > 0 {
+			str.WriteString(fmt.Sprintf(" (%s)", edge.Declarations[0]))
+		}
+		str.WriteString(".")
+	}
+	return str.String()
+}
+
+func (err DependencyCycleDiagnostics) Unwrap() error {
+	return err.Cycle
+}
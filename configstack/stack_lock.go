@@ -0,0 +1,339 @@
+package configstack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/dynamodb"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/util"
+	"google.golang.org/api/googleapi"
+)
+
+const (
+	stackLockDynamoDBScheme = "dynamodb"
+	stackLockGCSScheme      = "gcs"
+
+	// Prefix of the LockID of stack lock items, so that a Terraform lock table can be reused for stack locks.
+	stackLockIDPrefix = "terragrunt-stack-lock/"
+
+	stackLockInfoAttr = "Info"
+)
+
+// Environment variables that identify the CI job running Terragrunt, in order of preference.
+var ciJobEnvVars = []string{
+	"GITHUB_RUN_ID",
+	"CI_JOB_URL",
+	"BUILD_URL",
+	"CIRCLE_BUILD_URL",
+	"BUILDKITE_BUILD_URL",
+}
+
+// StackLockInfo is the metadata stored with a stack lock so that whoever is blocked by it knows who is holding it.
+type StackLockInfo struct {
+	ID        string `json:"ID"`
+	RunID     string `json:"RunID,omitempty"`
+	Who       string `json:"Who"`
+	CIJob     string `json:"CIJob,omitempty"`
+	Path      string `json:"Path"`
+	Command   string `json:"Command"`
+	StartedAt string `json:"StartedAt"`
+}
+
+func (info StackLockInfo) String() string {
+	str := fmt.Sprintf("held by %s since %s (command %s, path %s", info.Who, info.StartedAt, info.Command, info.Path)
+	if info.CIJob != "" {
+		str += fmt.Sprintf(", CI job %s", info.CIJob)
+	}
+	if info.RunID != "" {
+		str += fmt.Sprintf(", run %s", info.RunID)
+	}
+	return str + ")"
+}
+
+// NewStackLockInfo creates the lock metadata for a run-all started with the given options.
+func NewStackLockInfo(terragruntOptions *options.TerragruntOptions) StackLockInfo {
+	who := "unknown"
+	if currentUser, err := user.Current(); err == nil {
+		who = currentUser.Username
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		who = fmt.Sprintf("%s@%s", who, hostname)
+	}
+
+	var ciJob string
+	for _, envVar := range ciJobEnvVars {
+		if value := terragruntOptions.Env[envVar]; value != "" {
+			ciJob = value
+			break
+		}
+	}
+
+	return StackLockInfo{
+		ID:        util.UniqueId(),
+		RunID:     terragruntOptions.RunID,
+		Who:       who,
+		CIJob:     ciJob,
+		Path:      terragruntOptions.WorkingDir,
+		Command:   terragruntOptions.TerraformCommand,
+		StartedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+// StackLock is a distributed mutex that guards a whole stack for the duration of a run-all.
+type StackLock interface {
+	// Acquire takes the lock, returning StackLockedError if someone else is holding it. With steal, the lock is taken
+	// even if it is already held.
+	Acquire(ctx context.Context, info StackLockInfo, steal bool) error
+	// Release releases the lock, if it is still held with the given info.
+	Release(ctx context.Context, info StackLockInfo) error
+}
+
+// NewStackLock creates a stack lock from the given location, which can be:
+//
+//   - dynamodb://<table>/<lock-id>?region=<region> - an item in a DynamoDB table with a `LockID` primary key, such as
+//     a Terraform lock table.
+//   - gcs://<bucket>/<lock-id> - an object in a GCS bucket.
+//
+// If the lock ID is omitted, the working directory is used.
+func NewStackLock(location string, terragruntOptions *options.TerragruntOptions) (StackLock, error) {
+	parsedURL, err := url.Parse(location)
+	if err != nil || parsedURL.Host == "" {
+		return nil, errors.WithStackTrace(InvalidStackLockLocation(location))
+	}
+
+	lockID := strings.Trim(parsedURL.Path, "/")
+	if lockID == "" {
+		lockID = strings.Trim(terragruntOptions.WorkingDir, "/")
+	}
+
+	switch parsedURL.Scheme {
+	case stackLockDynamoDBScheme:
+		return &dynamoDBStackLock{
+			tableName:         parsedURL.Host,
+			lockID:            stackLockIDPrefix + lockID,
+			region:            parsedURL.Query().Get("region"),
+			terragruntOptions: terragruntOptions,
+		}, nil
+	case stackLockGCSScheme:
+		return &gcsStackLock{
+			bucket:            parsedURL.Host,
+			object:            stackLockIDPrefix + lockID,
+			terragruntOptions: terragruntOptions,
+		}, nil
+	default:
+		return nil, errors.WithStackTrace(InvalidStackLockLocation(location))
+	}
+}
+
+type dynamoDBStackLock struct {
+	tableName         string
+	lockID            string
+	region            string
+	terragruntOptions *options.TerragruntOptions
+}
+
+func (lock *dynamoDBStackLock) client() (*awsdynamodb.DynamoDB, error) {
+	return dynamodb.CreateDynamoDbClient(&aws_helper.AwsSessionConfig{Region: lock.region}, lock.terragruntOptions)
+}
+
+func (lock *dynamoDBStackLock) Acquire(ctx context.Context, info StackLockInfo, steal bool) error {
+	client, err := lock.client()
+	if err != nil {
+		return err
+	}
+
+	infoJson, err := json.Marshal(info)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	input := &awsdynamodb.PutItemInput{
+		TableName: aws.String(lock.tableName),
+		Item: map[string]*awsdynamodb.AttributeValue{
+			dynamodb.ATTR_LOCK_ID: {S: aws.String(lock.lockID)},
+			stackLockInfoAttr:     {S: aws.String(string(infoJson))},
+		},
+	}
+	if !steal {
+		input.ConditionExpression = aws.String(fmt.Sprintf("attribute_not_exists(%s)", dynamodb.ATTR_LOCK_ID))
+	}
+
+	if _, err := client.PutItemWithContext(ctx, input); err != nil {
+		if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == awsdynamodb.ErrCodeConditionalCheckFailedException {
+			return errors.WithStackTrace(StackLockedError{LockID: lock.lockID, Info: lock.currentInfo(ctx, client)})
+		}
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// currentInfo reads the metadata of the lock currently held. Failures are ignored, as the metadata is informational.
+func (lock *dynamoDBStackLock) currentInfo(ctx context.Context, client *awsdynamodb.DynamoDB) *StackLockInfo {
+	output, err := client.GetItemWithContext(ctx, &awsdynamodb.GetItemInput{
+		TableName:      aws.String(lock.tableName),
+		Key:            map[string]*awsdynamodb.AttributeValue{dynamodb.ATTR_LOCK_ID: {S: aws.String(lock.lockID)}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil || output.Item == nil || output.Item[stackLockInfoAttr] == nil {
+		return nil
+	}
+
+	var info StackLockInfo
+	if err := json.Unmarshal([]byte(aws.StringValue(output.Item[stackLockInfoAttr].S)), &info); err != nil {
+		return nil
+	}
+	return &info
+}
+
+func (lock *dynamoDBStackLock) Release(ctx context.Context, info StackLockInfo) error {
+	client, err := lock.client()
+	if err != nil {
+		return err
+	}
+
+	infoJson, err := json.Marshal(info)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// Only delete the lock if it is still ours, it could have been stolen in the meantime.
+	_, err = client.DeleteItemWithContext(ctx, &awsdynamodb.DeleteItemInput{
+		TableName:                 aws.String(lock.tableName),
+		Key:                       map[string]*awsdynamodb.AttributeValue{dynamodb.ATTR_LOCK_ID: {S: aws.String(lock.lockID)}},
+		ConditionExpression:       aws.String(fmt.Sprintf("%s = :info", stackLockInfoAttr)),
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{":info": {S: aws.String(string(infoJson))}},
+	})
+	if err != nil {
+		if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == awsdynamodb.ErrCodeConditionalCheckFailedException {
+			lock.terragruntOptions.Logger.Warnf("Stack lock %s is no longer held by this run, most likely it was stolen. Not releasing it.", lock.lockID)
+			return nil
+		}
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+type gcsStackLock struct {
+	bucket            string
+	object            string
+	terragruntOptions *options.TerragruntOptions
+
+	// generation of the lock object written by Acquire, used to make sure Release only deletes our own lock.
+	generation int64
+}
+
+func (lock *gcsStackLock) Acquire(ctx context.Context, info StackLockInfo, steal bool) error {
+	client, err := remote.CreateGCSClient(remote.RemoteStateConfigGCS{})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer client.Close() //nolint:errcheck
+
+	infoJson, err := json.Marshal(info)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	object := client.Bucket(lock.bucket).Object(lock.object)
+	if !steal {
+		object = object.If(storage.Conditions{DoesNotExist: true})
+	}
+
+	writer := object.NewWriter(ctx)
+	writer.ContentType = "application/json"
+
+	if _, err := writer.Write(infoJson); err != nil {
+		_ = writer.Close()
+		return errors.WithStackTrace(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		if apiErr, ok := err.(*googleapi.Error); ok && apiErr.Code == http.StatusPreconditionFailed {
+			return errors.WithStackTrace(StackLockedError{LockID: lock.object, Info: lock.currentInfo(ctx, client)})
+		}
+		return errors.WithStackTrace(err)
+	}
+
+	lock.generation = writer.Attrs().Generation
+
+	return nil
+}
+
+// currentInfo reads the metadata of the lock currently held. Failures are ignored, as the metadata is informational.
+func (lock *gcsStackLock) currentInfo(ctx context.Context, client *storage.Client) *StackLockInfo {
+	reader, err := client.Bucket(lock.bucket).Object(lock.object).NewReader(ctx)
+	if err != nil {
+		return nil
+	}
+	defer reader.Close() //nolint:errcheck
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil
+	}
+
+	var info StackLockInfo
+	if err := json.Unmarshal(content, &info); err != nil {
+		return nil
+	}
+	return &info
+}
+
+func (lock *gcsStackLock) Release(ctx context.Context, info StackLockInfo) error {
+	client, err := remote.CreateGCSClient(remote.RemoteStateConfigGCS{})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer client.Close() //nolint:errcheck
+
+	// Only delete the lock if it is still ours, it could have been stolen in the meantime.
+	object := client.Bucket(lock.bucket).Object(lock.object).If(storage.Conditions{GenerationMatch: lock.generation})
+	if err := object.Delete(ctx); err != nil {
+		if apiErr, ok := err.(*googleapi.Error); ok && apiErr.Code == http.StatusPreconditionFailed {
+			lock.terragruntOptions.Logger.Warnf("Stack lock %s is no longer held by this run, most likely it was stolen. Not releasing it.", lock.object)
+			return nil
+		}
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// Custom error types
+
+type InvalidStackLockLocation string
+
+func (location InvalidStackLockLocation) Error() string {
+	return fmt.Sprintf("Invalid stack lock location %s. Expected dynamodb://<table>[/<lock-id>][?region=<region>] or gcs://<bucket>[/<lock-id>].", string(location))
+}
+
+type StackLockedError struct {
+	LockID string
+	Info   *StackLockInfo
+}
+
+func (err StackLockedError) Error() string {
+	holder := "held by another run"
+	if err.Info != nil {
+		holder = err.Info.String()
+	}
+	return fmt.Sprintf("The stack lock %s is %s. Wait for that run to finish or use --terragrunt-steal-stack-lock to take over the lock.", err.LockID, holder)
+}
@@ -0,0 +1,71 @@
+package configstack
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStackLock(t *testing.T) {
+	t.Parallel()
+
+	opts := options.NewTerragruntOptions()
+	opts.WorkingDir = "/live/prod"
+
+	testCases := []struct {
+		location string
+		expected StackLock
+	}{
+		{
+			"dynamodb://locks/prod?region=eu-west-1",
+			&dynamoDBStackLock{tableName: "locks", lockID: "terragrunt-stack-lock/prod", region: "eu-west-1", terragruntOptions: opts},
+		},
+		{
+			"dynamodb://locks",
+			&dynamoDBStackLock{tableName: "locks", lockID: "terragrunt-stack-lock/live/prod", terragruntOptions: opts},
+		},
+		{
+			"gcs://state-bucket/stacks/prod/",
+			&gcsStackLock{bucket: "state-bucket", object: "terragrunt-stack-lock/stacks/prod", terragruntOptions: opts},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.location, func(t *testing.T) {
+			t.Parallel()
+
+			lock, err := NewStackLock(testCase.location, opts)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, lock)
+		})
+	}
+}
+
+func TestNewStackLockInvalidLocation(t *testing.T) {
+	t.Parallel()
+
+	for _, location := range []string{"s3://bucket/key", "dynamodb:///lock", "locks"} {
+		_, err := NewStackLock(location, options.NewTerragruntOptions())
+		assert.IsType(t, InvalidStackLockLocation(""), errors.Unwrap(err), location)
+	}
+}
+
+func TestStackLockedErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	err := StackLockedError{
+		LockID: "terragrunt-stack-lock/prod",
+		Info:   &StackLockInfo{Who: "jane@ci", CIJob: "1234", Path: "/live/prod", Command: "apply", StartedAt: "2024-01-01T00:00:00Z"},
+	}
+	assert.Contains(t, err.Error(), "held by jane@ci since 2024-01-01T00:00:00Z (command apply, path /live/prod, CI job 1234)")
+
+	err.Info.RunID = "run-1"
+	assert.Contains(t, err.Error(), "(command apply, path /live/prod, CI job 1234, run run-1)")
+
+	err = StackLockedError{LockID: "terragrunt-stack-lock/prod"}
+	assert.Contains(t, err.Error(), "held by another run")
+}
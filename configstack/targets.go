@@ -0,0 +1,180 @@
+package configstack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	targetFlag  = "-target"
+	replaceFlag = "-replace"
+)
+
+// The terraform commands accepting the -target and the -replace flags.
+var (
+	targetCommands  = []string{terraform.CommandNamePlan, terraform.CommandNameApply, terraform.CommandNameDestroy, terraform.CommandNameRefresh}
+	replaceCommands = []string{terraform.CommandNamePlan, terraform.CommandNameApply}
+)
+
+// moduleTarget is a resource of a module run-all targets, or replaces, e.g. `vpc:aws_subnet.private`.
+type moduleTarget struct {
+	// The canonical path of the module owning the resource
+	ModulePath string
+	Address    string
+	Flag       string
+}
+
+// owns returns true if the given module owns the targeted resource. The target of a module applies to the modules run
+// in its workspaces too.
+func (target moduleTarget) owns(module *TerraformModule) bool {
+	return module.Path == target.ModulePath || strings.HasPrefix(module.Path, target.ModulePath+workspacePathSeparator)
+}
+
+func (target moduleTarget) String() string {
+	return target.Flag + "=" + target.Address
+}
+
+// parseModuleTargets parses the given targets of the --terragrunt-target or --terragrunt-replace flag, in the form
+// <module path>:<resource address>, with the module path relative to the given working dir.
+func parseModuleTargets(values []string, flag string, workingDir string) ([]moduleTarget, error) {
+	var targets []moduleTarget
+	for _, value := range values {
+		modulePath, address, ok := strings.Cut(value, ":")
+		if !ok || modulePath == "" || address == "" {
+			return nil, errors.WithStackTrace(InvalidModuleTarget(value))
+		}
+
+		canonicalPath, err := util.CanonicalPath(modulePath, workingDir)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, moduleTarget{ModulePath: canonicalPath, Address: address, Flag: flag})
+	}
+	return targets, nil
+}
+
+// moduleTargets returns the targets of the --terragrunt-target and --terragrunt-replace flags, checking that the command
+// accepts them and that their modules are in the stack.
+func (stack *Stack) moduleTargets(terragruntOptions *options.TerragruntOptions) ([]moduleTarget, error) {
+	command := terragruntOptions.TerraformCommand
+	if len(terragruntOptions.Targets) > 0 && !util.ListContainsElement(targetCommands, command) {
+		return nil, errors.WithStackTrace(UnsupportedTargetCommand{Flag: targetFlag, Command: command, Commands: targetCommands})
+	}
+	if len(terragruntOptions.Replaces) > 0 && !util.ListContainsElement(replaceCommands, command) {
+		return nil, errors.WithStackTrace(UnsupportedTargetCommand{Flag: replaceFlag, Command: command, Commands: replaceCommands})
+	}
+
+	targets, err := parseModuleTargets(terragruntOptions.Targets, targetFlag, terragruntOptions.WorkingDir)
+	if err != nil {
+		return nil, err
+	}
+	replaces, err := parseModuleTargets(terragruntOptions.Replaces, replaceFlag, terragruntOptions.WorkingDir)
+	if err != nil {
+		return nil, err
+	}
+	targets = append(targets, replaces...)
+
+	for _, target := range targets {
+		found := false
+		for _, module := range stack.Modules {
+			found = found || target.owns(module)
+		}
+		if !found {
+			return nil, errors.WithStackTrace(TargetModuleNotFound{ModulePath: target.ModulePath, Address: target.Address})
+		}
+	}
+	return targets, nil
+}
+
+// flagUntargetedModules flags the modules owning none of the resources of the --terragrunt-target and
+// --terragrunt-replace flags as excluded with --terragrunt-skip-untargeted. It is called when the stack is resolved, so
+// that the skipped modules are left out of the deploy order, of the checks and of the prompts before the run.
+func (stack *Stack) flagUntargetedModules(terragruntOptions *options.TerragruntOptions) error {
+	if len(terragruntOptions.Targets) == 0 && len(terragruntOptions.Replaces) == 0 {
+		return nil
+	}
+
+	targets, err := stack.moduleTargets(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	if !terragruntOptions.SkipUntargeted {
+		return nil
+	}
+
+	for _, module := range stack.Modules {
+		targeted := false
+		for _, target := range targets {
+			targeted = targeted || target.owns(module)
+		}
+		if !targeted && !module.FlagExcluded {
+			terragruntOptions.Logger.Debugf("Skipping module %s, as none of the targets is in it", module.Path)
+			module.FlagExcluded = true
+		}
+	}
+	return nil
+}
+
+// setModuleTargets passes the -target and -replace flags of the --terragrunt-target and --terragrunt-replace flags only
+// to the modules owning the resources. The other modules run normally, unless flagUntargetedModules skipped them. It is
+// called once the terraform CLI args of the stack are synced to the modules.
+func (stack *Stack) setModuleTargets(terragruntOptions *options.TerragruntOptions) error {
+	if len(terragruntOptions.Targets) == 0 && len(terragruntOptions.Replaces) == 0 {
+		return nil
+	}
+
+	targets, err := stack.moduleTargets(terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	for _, module := range stack.Modules {
+		var args []string
+		for _, target := range targets {
+			if target.owns(module) {
+				args = append(args, target.String())
+			}
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		// The flags are passed right after the command, before the positional args such as the plan file
+		terragruntOptions.Logger.Debugf("Passing %s to module %s", strings.Join(args, " "), module.Path)
+		cliArgs := module.TerragruntOptions.TerraformCliArgs
+		module.TerragruntOptions.TerraformCliArgs = append(append([]string{util.FirstArg(cliArgs)}, args...), cliArgs[1:]...)
+	}
+	return nil
+}
+
+// Custom error types
+
+type InvalidModuleTarget string
+
+func (err InvalidModuleTarget) Error() string {
+	return fmt.Sprintf("Invalid target %q: expected <module path>:<resource address>, e.g. vpc:aws_subnet.private", string(err))
+}
+
+type UnsupportedTargetCommand struct {
+	Flag     string
+	Command  string
+	Commands []string
+}
+
+func (err UnsupportedTargetCommand) Error() string {
+	return fmt.Sprintf("The command %s does not accept the %s flag. The commands accepting it are: %s", err.Command, err.Flag, strings.Join(err.Commands, ", "))
+}
+
+type TargetModuleNotFound struct {
+	ModulePath string
+	Address    string
+}
+
+func (err TargetModuleNotFound) Error() string {
+	return fmt.Sprintf("The module %s of the target %s is not in the stack", err.ModulePath, err.Address)
+}
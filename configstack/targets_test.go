@@ -0,0 +1,111 @@
+package configstack
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetModuleTargets(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		command          string
+		targets          []string
+		replaces         []string
+		skipUntargeted   bool
+		expectedArgs     map[string][]string
+		expectedExcluded []string
+		expectedErr      error
+	}{
+		{
+			name:    "target",
+			command: "apply",
+			targets: []string{"vpc:aws_subnet.private", "app:module.web.aws_instance.this[\"a:b\"]"},
+			expectedArgs: map[string][]string{
+				"/live/vpc":       {"apply", "-target=aws_subnet.private", "-input=false"},
+				"/live/app@blue":  {"apply", "-target=module.web.aws_instance.this[\"a:b\"]", "-input=false"},
+				"/live/app@green": {"apply", "-target=module.web.aws_instance.this[\"a:b\"]", "-input=false"},
+				"/live/dns":       {"apply", "-input=false"},
+			},
+		},
+		{
+			name:           "replace and skip untargeted",
+			command:        "plan",
+			replaces:       []string{"./app@green:aws_instance.web"},
+			skipUntargeted: true,
+			expectedArgs: map[string][]string{
+				"/live/app@green": {"plan", "-replace=aws_instance.web", "-input=false"},
+			},
+			expectedExcluded: []string{"/live/app@blue", "/live/dns", "/live/vpc"},
+		},
+		{
+			name:        "unsupported command",
+			command:     "validate",
+			replaces:    []string{"vpc:aws_subnet.private"},
+			expectedErr: UnsupportedTargetCommand{Flag: replaceFlag, Command: "validate", Commands: replaceCommands},
+		},
+		{
+			name:        "invalid target",
+			command:     "plan",
+			targets:     []string{"aws_subnet.private"},
+			expectedErr: InvalidModuleTarget("aws_subnet.private"),
+		},
+		{
+			name:        "module not found",
+			command:     "plan",
+			targets:     []string{"db:aws_db_instance.main"},
+			expectedErr: TargetModuleNotFound{ModulePath: "/live/db", Address: "aws_db_instance.main"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest("targets_test")
+			require.NoError(t, err)
+			opts.WorkingDir = "/live"
+			opts.TerraformCommand = testCase.command
+			opts.TerraformCliArgs = []string{testCase.command, "-input=false"}
+			opts.Targets = testCase.targets
+			opts.Replaces = testCase.replaces
+			opts.SkipUntargeted = testCase.skipUntargeted
+
+			newModule := func(path string, workspaces ...string) *TerraformModule {
+				moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+				return &TerraformModule{Path: path, Config: config.TerragruntConfig{Workspaces: workspaces}, TerragruntOptions: moduleOpts}
+			}
+			modules := []*TerraformModule{newModule("/live/vpc"), newModule("/live/app", "blue", "green"), newModule("/live/dns")}
+			stack := &Stack{Path: "/live", Modules: expandModuleWorkspaces(modules)}
+
+			err = stack.flagUntargetedModules(opts)
+			if err == nil {
+				err = stack.setModuleTargets(opts)
+			}
+			if testCase.expectedErr != nil {
+				require.Error(t, err)
+				assert.Equal(t, testCase.expectedErr, errors.Unwrap(err))
+				return
+			}
+			require.NoError(t, err)
+
+			var excluded []string
+			for _, module := range stack.Modules {
+				if module.FlagExcluded {
+					excluded = append(excluded, module.Path)
+					continue
+				}
+				assert.Equal(t, testCase.expectedArgs[module.Path], module.TerragruntOptions.TerraformCliArgs, module.Path)
+			}
+			assert.ElementsMatch(t, testCase.expectedExcluded, excluded)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package configstack
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The separator of the path of a module and of its workspace in the path of the module run in the workspace, e.g.
+// /live/app@blue.
+const workspacePathSeparator = "@"
+
+// expandModuleWorkspaces replaces the modules whose config sets workspaces with a module per workspace, so that run-all
+// schedules, runs and reports every workspace on its own. The modules run in the workspaces depend on the dependencies
+// of the module, and the modules depending on the module depend on all of them. As the workspaces of a module share its
+// working dir, they run one at a time, in any order.
+func expandModuleWorkspaces(modules []*TerraformModule) []*TerraformModule {
+	expanded := map[*TerraformModule][]*TerraformModule{}
+	for _, module := range modules {
+		workspaces := util.RemoveDuplicatesFromList(module.Config.Workspaces)
+		if len(workspaces) == 0 || module.TerragruntOptions == nil {
+			continue
+		}
+
+		runLock := &sync.Mutex{}
+		for _, workspace := range workspaces {
+			workspaceModule := *module
+			workspaceModule.Path = module.Path + workspacePathSeparator + workspace
+			workspaceModule.runLock = runLock
+			workspaceModule.TerragruntOptions = module.TerragruntOptions.Clone(module.TerragruntOptions.TerragruntConfigPath)
+			workspaceModule.TerragruntOptions.TerraformWorkspace = workspace
+			if workspaceModule.TerragruntOptions.IncludeModulePrefix {
+				workspaceModule.TerragruntOptions.OutputPrefix = fmt.Sprintf("[%v] ", workspaceModule.Path)
+			}
+			expanded[module] = append(expanded[module], &workspaceModule)
+		}
+	}
+	if len(expanded) == 0 {
+		return modules
+	}
+
+	expandDependencies := func(dependencies []*TerraformModule) []*TerraformModule {
+		var result []*TerraformModule
+		for _, dependency := range dependencies {
+			if workspaceModules, ok := expanded[dependency]; ok {
+				result = append(result, workspaceModules...)
+			} else {
+				result = append(result, dependency)
+			}
+		}
+		return result
+	}
+
+	var result []*TerraformModule
+	for _, module := range modules {
+		module.Dependencies = expandDependencies(module.Dependencies)
+		if workspaceModules, ok := expanded[module]; ok {
+			result = append(result, workspaceModules...)
+		} else {
+			result = append(result, module)
+		}
+	}
+	// The modules run in the workspaces were copied before the dependencies of the module were expanded
+	for module, workspaceModules := range expanded {
+		for _, workspaceModule := range workspaceModules {
+			workspaceModule.Dependencies = module.Dependencies
+		}
+	}
+	return result
+}
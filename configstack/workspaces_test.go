@@ -0,0 +1,88 @@
+package configstack
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandModuleWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("workspaces_test")
+	require.NoError(t, err)
+
+	newModule := func(path string, workspaces []string, dependencies ...*TerraformModule) *TerraformModule {
+		moduleOpts := opts.Clone(filepath.Join(path, config.DefaultTerragruntConfigPath))
+		return &TerraformModule{Path: path, Dependencies: dependencies, Config: config.TerragruntConfig{Workspaces: workspaces}, TerragruntOptions: moduleOpts}
+	}
+
+	vpc := newModule("/live/vpc", nil)
+	app := newModule("/live/app", []string{"blue", "green", "blue"}, vpc)
+	dns := newModule("/live/dns", nil, app)
+
+	modules := expandModuleWorkspaces([]*TerraformModule{vpc, app, dns})
+
+	paths := map[string]*TerraformModule{}
+	for _, module := range modules {
+		paths[module.Path] = module
+	}
+	require.Len(t, paths, 4)
+	require.Contains(t, paths, "/live/app@blue")
+	require.Contains(t, paths, "/live/app@green")
+
+	for _, workspace := range []string{"blue", "green"} {
+		module := paths["/live/app@"+workspace]
+		assert.Equal(t, workspace, module.TerragruntOptions.TerraformWorkspace)
+		assert.Equal(t, []*TerraformModule{vpc}, module.Dependencies)
+		assert.NotNil(t, module.runLock)
+	}
+	assert.Same(t, paths["/live/app@blue"].runLock, paths["/live/app@green"].runLock)
+	assert.Empty(t, vpc.TerragruntOptions.TerraformWorkspace)
+
+	var dependencies []string
+	for _, dependency := range paths["/live/dns"].Dependencies {
+		dependencies = append(dependencies, dependency.Path)
+	}
+	assert.ElementsMatch(t, []string{"/live/app@blue", "/live/app@green"}, dependencies)
+}
+
+func TestRunModulesWorkspacesOneAtATime(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("workspaces_test")
+	require.NoError(t, err)
+
+	var running atomic.Int32
+	var concurrent atomic.Bool
+	var mutex sync.Mutex
+	var workspaces []string
+
+	moduleOpts := opts.Clone(filepath.Join("/live/app", config.DefaultTerragruntConfigPath))
+	moduleOpts.RunTerragrunt = func(ctx context.Context, opts *options.TerragruntOptions) error {
+		if running.Add(1) > 1 {
+			concurrent.Store(true)
+		}
+		defer running.Add(-1)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		workspaces = append(workspaces, opts.TerraformWorkspace)
+		return nil
+	}
+	app := &TerraformModule{Path: "/live/app", Config: config.TerragruntConfig{Workspaces: []string{"blue", "green", "canary"}}, TerragruntOptions: moduleOpts}
+
+	require.NoError(t, RunModules(context.Background(), opts, expandModuleWorkspaces([]*TerraformModule{app}), 3))
+
+	sort.Strings(workspaces)
+	assert.Equal(t, []string{"blue", "canary", "green"}, workspaces)
+	assert.False(t, concurrent.Load())
+}
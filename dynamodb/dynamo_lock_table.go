@@ -27,6 +27,7 @@ const MAX_RETRIES_WAITING_FOR_TABLE_TO_BE_ACTIVE = 30
 const SLEEP_BETWEEN_TABLE_STATUS_CHECKS = 10 * time.Second
 
 const DYNAMODB_PAY_PER_REQUEST_BILLING_MODE = "PAY_PER_REQUEST"
+const DYNAMODB_PROVISIONED_BILLING_MODE = "PROVISIONED"
 
 const sleepBetweenRetries = 20 * time.Second
 const maxRetries = 15
@@ -41,16 +42,40 @@ func CreateDynamoDbClient(config *aws_helper.AwsSessionConfig, terragruntOptions
 	return dynamodb.New(session), nil
 }
 
+// LockTableSettings are the optional settings applied to the lock table when Terragrunt creates it.
+type LockTableSettings struct {
+	// BillingMode is either PAY_PER_REQUEST (the default) or PROVISIONED.
+	BillingMode string
+	// ReadCapacity and WriteCapacity are only used with the PROVISIONED billing mode.
+	ReadCapacity  int64
+	WriteCapacity int64
+	// TTLAttribute is the name of the attribute DynamoDB uses to expire stale lock items. Empty disables TTL.
+	TTLAttribute        string
+	PointInTimeRecovery bool
+	Tags                map[string]string
+}
+
+// Return the billing mode to use for the lock table, defaulting to on-demand billing.
+func (settings LockTableSettings) GetBillingMode() string {
+	if settings.BillingMode == "" {
+		return DYNAMODB_PAY_PER_REQUEST_BILLING_MODE
+	}
+	return settings.BillingMode
+}
+
 // Create the lock table in DynamoDB if it doesn't already exist
-func CreateLockTableIfNecessary(tableName string, tags map[string]string, client *dynamodb.DynamoDB, terragruntOptions *options.TerragruntOptions) error {
+func CreateLockTableIfNecessary(tableName string, settings LockTableSettings, client *dynamodb.DynamoDB, terragruntOptions *options.TerragruntOptions) error {
 	tableExists, err := LockTableExistsAndIsActive(tableName, client)
 	if err != nil {
 		return err
 	}
 
 	if !tableExists {
+		if terragruntOptions.Restricted || terragruntOptions.ReadOnly {
+			return errors.WithStackTrace(TableCreationNotAllowed{TableName: tableName})
+		}
 		terragruntOptions.Logger.Debugf("Lock table %s does not exist in DynamoDB. Will need to create it just this first time.", tableName)
-		return CreateLockTable(tableName, tags, client, terragruntOptions)
+		return CreateLockTable(tableName, settings, client, terragruntOptions)
 	}
 
 	return nil
@@ -82,7 +107,7 @@ func LockTableCheckSSEncryptionIsOn(tableName string, client *dynamodb.DynamoDB)
 
 // Create a lock table in DynamoDB and wait until it is in "active" state. If the table already exists, merely wait
 // until it is in "active" state.
-func CreateLockTable(tableName string, tags map[string]string, client *dynamodb.DynamoDB, terragruntOptions *options.TerragruntOptions) error {
+func CreateLockTable(tableName string, settings LockTableSettings, client *dynamodb.DynamoDB, terragruntOptions *options.TerragruntOptions) error {
 	tableCreateDeleteSemaphore.Acquire()
 	defer tableCreateDeleteSemaphore.Release()
 
@@ -96,12 +121,21 @@ func CreateLockTable(tableName string, tags map[string]string, client *dynamodb.
 		{AttributeName: aws.String(ATTR_LOCK_ID), KeyType: aws.String(dynamodb.KeyTypeHash)},
 	}
 
-	createTableOutput, err := client.CreateTable(&dynamodb.CreateTableInput{
+	createTableInput := &dynamodb.CreateTableInput{
 		TableName:            aws.String(tableName),
-		BillingMode:          aws.String(DYNAMODB_PAY_PER_REQUEST_BILLING_MODE),
+		BillingMode:          aws.String(settings.GetBillingMode()),
 		AttributeDefinitions: attributeDefinitions,
 		KeySchema:            keySchema,
-	})
+	}
+
+	if settings.GetBillingMode() == DYNAMODB_PROVISIONED_BILLING_MODE {
+		createTableInput.ProvisionedThroughput = &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(settings.ReadCapacity),
+			WriteCapacityUnits: aws.Int64(settings.WriteCapacity),
+		}
+	}
+
+	createTableOutput, err := client.CreateTable(createTableInput)
 
 	if err != nil {
 		if isTableAlreadyBeingCreatedOrUpdatedError(err) {
@@ -118,18 +152,63 @@ func CreateLockTable(tableName string, tags map[string]string, client *dynamodb.
 	}
 
 	if createTableOutput != nil && createTableOutput.TableDescription != nil && createTableOutput.TableDescription.TableArn != nil {
-		// Do not tag in case somebody else had created the table
+		// Do not tag or reconfigure the table in case somebody else had created it
 
-		err = tagTableIfTagsGiven(tags, createTableOutput.TableDescription.TableArn, client, terragruntOptions)
+		err = tagTableIfTagsGiven(settings.Tags, createTableOutput.TableDescription.TableArn, client, terragruntOptions)
 
 		if err != nil {
 			return errors.WithStackTrace(err)
 		}
+
+		if err := enableTTLIfNecessary(tableName, settings.TTLAttribute, client, terragruntOptions); err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		if err := enablePointInTimeRecoveryIfNecessary(tableName, settings.PointInTimeRecovery, client, terragruntOptions); err != nil {
+			return errors.WithStackTrace(err)
+		}
 	}
 
 	return nil
 }
 
+// Enable time to live on the given attribute of the lock table so that DynamoDB removes stale lock items automatically
+func enableTTLIfNecessary(tableName string, ttlAttribute string, client *dynamodb.DynamoDB, terragruntOptions *options.TerragruntOptions) error {
+	if ttlAttribute == "" {
+		return nil
+	}
+
+	terragruntOptions.Logger.Debugf("Enabling TTL on attribute %s of lock table %s", ttlAttribute, tableName)
+
+	_, err := client.UpdateTimeToLive(&dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(tableName),
+		TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+			AttributeName: aws.String(ttlAttribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+
+	return err
+}
+
+// Enable point-in-time recovery (continuous backups) for the lock table
+func enablePointInTimeRecoveryIfNecessary(tableName string, enabled bool, client *dynamodb.DynamoDB, terragruntOptions *options.TerragruntOptions) error {
+	if !enabled {
+		return nil
+	}
+
+	terragruntOptions.Logger.Debugf("Enabling point-in-time recovery on lock table %s", tableName)
+
+	_, err := client.UpdateContinuousBackups(&dynamodb.UpdateContinuousBackupsInput{
+		TableName: aws.String(tableName),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: aws.Bool(true),
+		},
+	})
+
+	return err
+}
+
 func tagTableIfTagsGiven(tags map[string]string, tableArn *string, client *dynamodb.DynamoDB, terragruntOptions *options.TerragruntOptions) error {
 
 	if len(tags) == 0 {
@@ -290,3 +369,14 @@ type TableEncryptedRetriesExceeded struct {
 func (err TableEncryptedRetriesExceeded) Error() string {
 	return fmt.Sprintf("Table %s still does not have encryption enabled after %d retries.", err.TableName, err.Retries)
 }
+
+type TableCreationNotAllowed struct {
+	TableName string
+}
+
+func (err TableCreationNotAllowed) Error() string {
+	return fmt.Sprintf("Creation of lock table %s is not allowed in the restricted and read-only modes", err.TableName)
+}
+
+// PolicyViolation marks the error as Terragrunt refusing to do something the options forbid.
+func (err TableCreationNotAllowed) PolicyViolation() {}
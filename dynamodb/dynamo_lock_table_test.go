@@ -44,7 +44,7 @@ func TestCreateLockTableConcurrency(t *testing.T) {
 		waitGroup.Add(1)
 		go func() {
 			defer waitGroup.Done()
-			err := CreateLockTableIfNecessary(tableName, nil, client, mockOptions)
+			err := CreateLockTableIfNecessary(tableName, LockTableSettings{}, client, mockOptions)
 			assert.Nil(t, err, "Unexpected error: %v", err)
 		}()
 	}
@@ -82,7 +82,7 @@ func TestCreateLockTableIfNecessaryTableAlreadyExists(t *testing.T) {
 		assertCanWriteToTable(t, tableName, client)
 
 		// Try to create the table the second time and make sure you get no errors
-		err = CreateLockTableIfNecessary(tableName, nil, client, mockOptions)
+		err = CreateLockTableIfNecessary(tableName, LockTableSettings{}, client, mockOptions)
 		assert.Nil(t, err, "Unexpected error: %v", err)
 	})
 }
@@ -104,7 +104,7 @@ func TestTableTagging(t *testing.T) {
 		assertTags(tags, tableName, client, t)
 
 		// Try to create the table the second time and make sure you get no errors
-		err = CreateLockTableIfNecessary(tableName, nil, client, mockOptions)
+		err = CreateLockTableIfNecessary(tableName, LockTableSettings{}, client, mockOptions)
 		assert.Nil(t, err, "Unexpected error: %v", err)
 	})
 }
@@ -0,0 +1,102 @@
+// Package encryption encrypts the values terragrunt writes to disk that originate from sensitive outputs, e.g. the
+// inputs set from the sensitive outputs of the dependencies in the debug file, with a local key or a KMS key.
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// EncryptedValuePrefix is the prefix of the encrypted values, followed by the name of the encrypter and the ciphertext
+// in base64, e.g. `tgenc:local:...`.
+const EncryptedValuePrefix = "tgenc:"
+
+// Encrypter encrypts and decrypts the values, the ciphertext being prefixed with its name.
+type Encrypter interface {
+	Name() string
+	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// NewEncrypter returns the encrypter of the key configured in the options, or nil if no key is configured.
+func NewEncrypter(opts *options.TerragruntOptions) (Encrypter, error) {
+	switch {
+	case opts.SensitiveOutputsKey != "" && opts.SensitiveOutputsKMSKeyID != "":
+		return nil, errors.WithStackTrace(ConflictingKeys{})
+	case opts.SensitiveOutputsKey != "":
+		return NewLocalEncrypter(opts.SensitiveOutputsKey)
+	case opts.SensitiveOutputsKMSKeyID != "":
+		return NewKMSEncrypter(opts, opts.SensitiveOutputsKMSKeyID)
+	}
+	return nil, nil
+}
+
+// IsEncrypted returns true if the value was encrypted by EncryptValue.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedValuePrefix)
+}
+
+// EncryptValue encrypts the value and returns it prefixed with the name of the encrypter.
+func EncryptValue(ctx context.Context, encrypter Encrypter, plaintext []byte) (string, error) {
+	ciphertext, err := encrypter.Encrypt(ctx, plaintext)
+	if err != nil {
+		return "", err
+	}
+	return EncryptedValuePrefix + encrypter.Name() + ":" + encodeBase64(ciphertext), nil
+}
+
+// DecryptValue decrypts the value encrypted by EncryptValue, the encrypter being the one the value was encrypted with.
+func DecryptValue(ctx context.Context, encrypter Encrypter, value string) ([]byte, error) {
+	name, ciphertext, ok := strings.Cut(strings.TrimPrefix(value, EncryptedValuePrefix), ":")
+	if !IsEncrypted(value) || !ok {
+		return nil, errors.WithStackTrace(MalformedEncryptedValue{})
+	}
+	if name != encrypter.Name() {
+		return nil, errors.WithStackTrace(WrongEncrypter{Expected: name, Actual: encrypter.Name()})
+	}
+
+	decoded, err := decodeBase64(ciphertext)
+	if err != nil {
+		return nil, errors.WithStackTrace(MalformedEncryptedValue{})
+	}
+	return encrypter.Decrypt(ctx, decoded)
+}
+
+// Custom error types
+
+type ConflictingKeys struct{}
+
+func (err ConflictingKeys) Error() string {
+	return "Only one of a local key and a KMS key can be used to encrypt the sensitive outputs"
+}
+
+type MalformedEncryptedValue struct{}
+
+func (err MalformedEncryptedValue) Error() string {
+	return fmt.Sprintf("The encrypted value is malformed, expected %s<encrypter>:<base64 ciphertext>", EncryptedValuePrefix)
+}
+
+type WrongEncrypter struct {
+	Expected string
+	Actual   string
+}
+
+func (err WrongEncrypter) Error() string {
+	return fmt.Sprintf("The value was encrypted with a %s key, but a %s key is configured to decrypt it", err.Expected, err.Actual)
+}
+
+type InvalidLocalKey struct {
+	Err error
+}
+
+func (err InvalidLocalKey) Error() string {
+	return fmt.Sprintf("The local key must be a base64 encoded 256 bit key, e.g. generated with `openssl rand -base64 32`: %v", err.Err)
+}
+
+func (err InvalidLocalKey) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,79 @@
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testLocalKey      = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+	testOtherLocalKey = "ZmVkY2JhOTg3NjU0MzIxMGZlZGNiYTk4NzY1NDMyMTA="
+)
+
+func TestLocalEncrypterRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	encrypter, err := NewLocalEncrypter(testLocalKey)
+	require.NoError(t, err)
+
+	value, err := EncryptValue(context.Background(), encrypter, []byte(`"hunter2"`))
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(value))
+	assert.Regexp(t, `^tgenc:local:`, value)
+	assert.NotContains(t, value, "hunter2")
+
+	plaintext, err := DecryptValue(context.Background(), encrypter, value)
+	require.NoError(t, err)
+	assert.Equal(t, `"hunter2"`, string(plaintext))
+
+	otherEncrypter, err := NewLocalEncrypter(testOtherLocalKey)
+	require.NoError(t, err)
+	_, err = DecryptValue(context.Background(), otherEncrypter, value)
+	require.Error(t, err)
+}
+
+func TestDecryptValueErrors(t *testing.T) {
+	t.Parallel()
+
+	encrypter, err := NewLocalEncrypter(testLocalKey)
+	require.NoError(t, err)
+
+	_, err = DecryptValue(context.Background(), encrypter, "tgenc:kms:AAAA")
+	var wrongEncrypter WrongEncrypter
+	require.ErrorAs(t, err, &wrongEncrypter)
+	assert.Equal(t, WrongEncrypter{Expected: "kms", Actual: "local"}, wrongEncrypter)
+
+	for _, value := range []string{"hunter2", "tgenc:local", "tgenc:local:not base64"} {
+		_, err = DecryptValue(context.Background(), encrypter, value)
+		assert.IsType(t, MalformedEncryptedValue{}, errors.Unwrap(err), value)
+	}
+}
+
+func TestNewEncrypter(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	encrypter, err := NewEncrypter(opts)
+	require.NoError(t, err)
+	assert.Nil(t, encrypter)
+
+	opts.SensitiveOutputsKey = "too short"
+	_, err = NewEncrypter(opts)
+	assert.IsType(t, InvalidLocalKey{}, errors.Unwrap(err))
+
+	opts.SensitiveOutputsKey = testLocalKey
+	encrypter, err = NewEncrypter(opts)
+	require.NoError(t, err)
+	assert.Equal(t, "local", encrypter.Name())
+
+	opts.SensitiveOutputsKMSKeyID = "alias/terragrunt"
+	_, err = NewEncrypter(opts)
+	assert.IsType(t, ConflictingKeys{}, errors.Unwrap(err))
+}
@@ -0,0 +1,61 @@
+package encryption
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// KMSEncrypter encrypts the values with an AWS KMS key, so that reading them requires the permission to decrypt with
+// the key.
+type KMSEncrypter struct {
+	client *kms.KMS
+	keyID  string
+}
+
+// NewKMSEncrypter returns an encrypter of the given KMS key ID, alias or ARN, in the region of the ARN if given one,
+// and in the default region of the environment otherwise.
+func NewKMSEncrypter(opts *options.TerragruntOptions, keyID string) (*KMSEncrypter, error) {
+	sess, err := aws_helper.CreateAwsSession(nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	config := aws.NewConfig()
+	if keyARN, err := arn.Parse(keyID); err == nil {
+		config = config.WithRegion(keyARN.Region)
+	}
+	return &KMSEncrypter{client: kms.New(sess, config), keyID: keyID}, nil
+}
+
+func (encrypter *KMSEncrypter) Name() string {
+	return "kms"
+}
+
+func (encrypter *KMSEncrypter) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	output, err := encrypter.client.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(encrypter.keyID),
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return output.CiphertextBlob, nil
+}
+
+func (encrypter *KMSEncrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	output, err := encrypter.client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(encrypter.keyID),
+		CiphertextBlob: ciphertext,
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return output.Plaintext, nil
+}
@@ -0,0 +1,72 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+const localKeySize = 32
+
+// LocalEncrypter encrypts the values with AES-256-GCM and a local key, the nonce being prepended to the ciphertext.
+type LocalEncrypter struct {
+	aead cipher.AEAD
+}
+
+// NewLocalEncrypter returns an encrypter of the given base64 encoded 256 bit key.
+func NewLocalEncrypter(key string) (*LocalEncrypter, error) {
+	decodedKey, err := decodeBase64(key)
+	if err != nil {
+		return nil, errors.WithStackTrace(InvalidLocalKey{Err: err})
+	}
+	if len(decodedKey) != localKeySize {
+		return nil, errors.WithStackTrace(InvalidLocalKey{Err: aes.KeySizeError(len(decodedKey))})
+	}
+
+	block, err := aes.NewCipher(decodedKey)
+	if err != nil {
+		return nil, errors.WithStackTrace(InvalidLocalKey{Err: err})
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return &LocalEncrypter{aead: aead}, nil
+}
+
+func (encrypter *LocalEncrypter) Name() string {
+	return "local"
+}
+
+func (encrypter *LocalEncrypter) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, encrypter.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return encrypter.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (encrypter *LocalEncrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	nonceSize := encrypter.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.WithStackTrace(MalformedEncryptedValue{})
+	}
+	plaintext, err := encrypter.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return plaintext, nil
+}
+
+func encodeBase64(value []byte) string {
+	return base64.StdEncoding.EncodeToString(value)
+}
+
+func decodeBase64(value string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(value)
+}
@@ -0,0 +1,22 @@
+package options
+
+import (
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// DownloadRetry returns how the source and provider downloads are retried and fall back to the mirrors. The mirrors are
+// validated on the CLI, the invalid ones are skipped.
+func (opts *TerragruntOptions) DownloadRetry() util.DownloadRetry {
+	retry := util.DownloadRetry{
+		MaxAttempts: opts.DownloadMaxAttempts,
+		Interval:    time.Duration(opts.DownloadRetryIntervalSec) * time.Second,
+	}
+	for _, spec := range opts.DownloadMirrors {
+		if mirror, err := util.ParseDownloadMirror(spec); err == nil {
+			retry.Mirrors = append(retry.Mirrors, mirror)
+		}
+	}
+	return retry
+}
@@ -0,0 +1,78 @@
+package options
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ExecPolicy restricts the external commands that hooks and run_cmd may execute, e.g. to run the configs of less
+// trusted branches in CI. The commands are matched with glob patterns: a pattern with a path separator is matched
+// against the command as written in the config or, for a command without a path, against the absolute path it resolves
+// to in the PATH; any other pattern against the name of a command without a path, resolved in the PATH. A command with
+// a path, such as a binary of the repo, is only allowed by a pattern with a path separator, but denied by any pattern.
+type ExecPolicy struct {
+	// Where the policy comes from, used in the errors.
+	Source string
+	// The commands allowed to execute, all commands are allowed if empty.
+	Allow []string
+	// The commands not allowed to execute, even if they are allowed.
+	Deny []string
+	// Execute the commands without network access.
+	NoNetwork bool
+}
+
+// Allows returns true if the policy allows executing the given command.
+func (policy ExecPolicy) Allows(command string) bool {
+	if matchesCommand(policy.Deny, command, true) {
+		return false
+	}
+	return len(policy.Allow) == 0 || matchesCommand(policy.Allow, command, false)
+}
+
+// matchesCommand returns true if one of the patterns matches the command. The patterns without a path separator only
+// match the name of a command with a path if anyPath is set, as they would otherwise allow any binary with an allowed
+// name, e.g. `./terraform` committed in the branch.
+func matchesCommand(patterns []string, command string, anyPath bool) bool {
+	hasPath := strings.ContainsRune(command, '/') || strings.ContainsRune(command, filepath.Separator)
+
+	path := command
+	if !hasPath {
+		// Only the commands found in the PATH are resolved, not the ones relative to the working dir
+		resolved, err := exec.LookPath(command)
+		if err != nil || !filepath.IsAbs(resolved) {
+			return anyPath && matchesName(patterns, command)
+		}
+		path = resolved
+	}
+
+	path = filepath.ToSlash(path)
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(pattern)
+		if !strings.Contains(pattern, "/") {
+			continue
+		}
+		if matched, err := filepath.Match(pattern, path); err == nil && matched {
+			return true
+		}
+	}
+
+	if hasPath && !anyPath {
+		return false
+	}
+	return matchesName(patterns, filepath.Base(command))
+}
+
+// matchesName returns true if one of the patterns without a path separator matches the given name.
+func matchesName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		pattern = filepath.ToSlash(pattern)
+		if strings.Contains(pattern, "/") {
+			continue
+		}
+		if matched, err := filepath.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
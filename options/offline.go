@@ -0,0 +1,68 @@
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// OfflineAllows returns true if fetching from the given host is allowed, which is always the case out of offline mode.
+// The allowed hosts are matched with glob patterns, e.g. `*.corp.example.com`.
+func (opts *TerragruntOptions) OfflineAllows(host string) bool {
+	if !opts.Offline {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	for _, pattern := range opts.OfflineAllowedHosts {
+		if matched, err := path.Match(strings.ToLower(pattern), host); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckOfflineFetch returns an error if offline mode forbids fetching the given thing, e.g. "the terraform source",
+// from the given host.
+func (opts *TerragruntOptions) CheckOfflineFetch(what string, host string) error {
+	if host == "" || opts.OfflineAllows(host) {
+		return nil
+	}
+	return errors.WithStackTrace(OfflineFetchNotAllowed{What: what, Host: host})
+}
+
+// FetchHost returns the host the given URL, in the go-getter format, is fetched from, or an empty string for the local
+// paths and `file` URLs.
+func FetchHost(rawURL string) string {
+	// Trim the forced getter, e.g. `git::https://github.com/...`
+	if _, forcedURL, found := strings.Cut(rawURL, "::"); found {
+		rawURL = forcedURL
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	// A scheme of one letter is the drive of a Windows path
+	if err != nil || len(parsedURL.Scheme) <= 1 || parsedURL.Scheme == "file" {
+		return ""
+	}
+	return parsedURL.Hostname()
+}
+
+// Custom error types
+
+type OfflineFetchNotAllowed struct {
+	What string
+	Host string
+}
+
+func (err OfflineFetchNotAllowed) Error() string {
+	return fmt.Sprintf("Offline mode (--terragrunt-offline) forbids fetching %s from %s. Vendor it, or allow the host with --terragrunt-offline-allow-host.", err.What, err.Host)
+}
+
+type OfflineWithProviderCache struct{}
+
+func (err OfflineWithProviderCache) Error() string {
+	return "Offline mode (--terragrunt-offline) can't be used with the Terragrunt Provider Cache, which fetches the providers from their registries. Remove --terragrunt-provider-cache, the providers are installed from the provider cache dir in offline mode."
+}
@@ -7,6 +7,8 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gruntwork-io/go-commons/errors"
@@ -36,6 +38,16 @@ const (
 
 	DefaultIAMAssumeRoleDuration = 3600
 
+	// The theme of the module prefixes of run-all output.
+	DefaultPrefixTheme = "default"
+
+	// By default, Terragrunt exits with the exit code of Terraform.
+	DefaultExitCodeMode = "terraform"
+
+	// The source and provider downloads are attempted 3 times, sleeping 2 then 4 seconds in between.
+	DefaultDownloadMaxAttempts      = 3
+	DefaultDownloadRetryIntervalSec = 2
+
 	minCommandLength = 2
 )
 
@@ -125,6 +137,12 @@ type TerragruntOptions struct {
 	// Wrap Terraform logs in JSON format
 	TerraformLogsToJson bool
 
+	// The format of the Terragrunt logs: human, logfmt or json. Empty means json with JsonLogFormat, human otherwise.
+	LogFormat string
+
+	// The fields included in the log entries (module, group, command, duration), defaults to the fields of LogFormat.
+	LogFields []string
+
 	// Log level
 	LogLevel logrus.Level
 
@@ -158,6 +176,10 @@ type TerragruntOptions struct {
 	// IAM Role options that should be used when authenticating to AWS.
 	IAMRoleOptions IAMRoleOptions
 
+	// The AWS named profile of the profile block selected by the module, which the AWS API calls of Terragrunt, and the
+	// assumption of the IAM role, read the credentials from.
+	AWSProfile string
+
 	// If set to true, continue running *-all commands even if a dependency has errors. This is mostly useful for 'output-all <some_variable>'. See https://github.com/gruntwork-io/terragrunt/issues/193
 	IgnoreDependencyErrors bool
 
@@ -192,6 +214,13 @@ type TerragruntOptions struct {
 	// RetryableErrors is an array of regular expressions with RE2 syntax (https://github.com/google/re2/wiki/Syntax) that qualify for retrying
 	RetryableErrors []string
 
+	// Maximum number of times to retry the terraform commands failing to acquire the state lock, set by the state_lock block
+	StateLockRetryMaxAttempts int
+
+	// The wait before the first retry of a command failing to acquire the state lock, doubled on each retry up to StateLockRetryMaxBackoff
+	StateLockRetryBackoff    time.Duration
+	StateLockRetryMaxBackoff time.Duration
+
 	// Unix-style glob of directories to exclude when running *-all commands
 	ExcludeDirs []string
 
@@ -235,6 +264,25 @@ type TerragruntOptions struct {
 	// command for more info.
 	AwsProviderPatchOverrides map[string]string
 
+	// The types of the providers the aws-provider-patch command patches. Defaults to only the aws provider.
+	AwsProviderPatchProviders []string
+
+	// Set the overridden attributes of the aws-provider-patch command even if the provider blocks don't set them.
+	AwsProviderPatchSetMissing bool
+
+	// Override the attributes of the aws-provider-patch command with variables declared in the patched modules, with
+	// the values as their defaults, instead of with the values.
+	AwsProviderPatchInjectVars bool
+
+	// Print the diff of the aws-provider-patch command instead of patching the modules.
+	AwsProviderPatchDryRun bool
+
+	// SPDX identifiers or globs of the licenses of the module sources the licenses command flags as denied.
+	LicenseDenylist []string
+
+	// The file the licenses command writes its report to in JSON, in addition to printing it.
+	LicenseReportFile string
+
 	// True if is required to show dependent modules and confirm action
 	CheckDependentModules bool
 
@@ -262,15 +310,260 @@ type TerragruntOptions struct {
 	// Disables validation terraform command
 	DisableCommandValidation bool
 
+	// Location of the distributed lock acquired for the whole stack during run-all, e.g. dynamodb://table/lock-id.
+	StackLock string
+
+	// Take over the stack lock even if it is held by another run.
+	StealStackLock bool
+
+	// Path to the file where run-all records how long each module took, used to start slow modules first.
+	ModuleDurationsFile string
+
+	// Report succeeded, failed and skipped modules separately at the end of run-all and exit with a distinct code for each outcome.
+	ContinueOnError bool
+
+	// Modules that are assumed to be already applied during run-all: their outputs are used, but they are not run.
+	AssumeAppliedDirs []string
+
+	// Skip applying modules whose fingerprint matches the one recorded on their last successful apply.
+	SkipUnchanged bool
+
+	// Plan before apply with detailed exit code and only apply the plan if it has changes.
+	PlanGatedApply bool
+
+	// In interactive mode, show the plan summary of each module and ask to approve, skip or abort before applying it.
+	ConfirmEach bool
+
+	// Buffer the output of each module during run-all and print it as a contiguous block once the module finishes.
+	BufferOutput bool
+
+	// Don't prefix the terraform output of each module in run-all with the path of the module.
+	NoPrefix bool
+
+	// The color theme of the module prefixes of run-all output.
+	PrefixTheme string
+
+	// Don't periodically log the progress of run-all.
+	NoProgress bool
+
+	// Write GitHub Actions annotations for module failures, configuration errors and drifted modules.
+	GitHubAnnotations bool
+
+	// How the exit code is chosen: terraform, the exit code of Terraform, or detailed, a distinct exit code per outcome.
+	ExitCodeMode string
+
+	// Overrides of the exit codes of the detailed exit code mode, as `<outcome>=<exit code>`.
+	ExitCodes []string
+
+	// The path to a trusted config whose `exec_policy` block restricts the commands hooks and run_cmd may execute.
+	ExecPolicyConfigPath string
+
+	// The commands hooks and run_cmd are allowed to execute, all commands are allowed if empty.
+	ExecAllow []string
+
+	// The commands hooks and run_cmd are not allowed to execute.
+	ExecDeny []string
+
+	// Execute the commands of hooks and run_cmd without network access.
+	ExecNoNetwork bool
+
+	// The execution policies resolved from the flags and the exec policy config, a command must be allowed by all of them.
+	ExecPolicies []ExecPolicy
+
+	// Disable the features that execute arbitrary local code or make changes outside of the module dir, to safely run
+	// the configs of untrusted pull requests.
+	Restricted bool
+
+	// Refuse to run the terraform commands that change the infrastructure or the state, such as apply or import.
+	ReadOnly bool
+
+	// The dirs of the modules protected by prevent_destroy that are allowed to be destroyed.
+	AllowDestroyDirs []string
+
+	// Path to the file where run-all saves the run graph and the status of each module, to resume the run in another CI job.
+	RunQueueFile string
+
+	// The shard of the modules that run-all runs, in the N/M format, to split a run-all across workers.
+	Shard string
+
+	// Location of the ledger where the workers of a sharded run-all record the modules that finished.
+	ShardLedger string
+
+	// Forbid the network fetches of Terragrunt and terraform, except from the OfflineAllowedHosts.
+	Offline bool
+
+	// The hosts, or glob patterns of hosts, that may still be fetched from in offline mode.
+	OfflineAllowedHosts []string
+
+	// The number of attempts of every source and provider download, before falling back to the DownloadMirrors.
+	DownloadMaxAttempts int
+
+	// The seconds to sleep after the first failed attempt of a download, doubled after every further failed attempt.
+	DownloadRetryIntervalSec int
+
+	// The mirrors the downloads fall back to, in order, in the `<original URL prefix>=<mirror URL prefix>` format.
+	DownloadMirrors []string
+
+	// The dir run-all apply writes the snapshot manifest of the applied modules to, under the SnapshotRunID, after a successful run.
+	SnapshotDir string
+
+	// The ID of the run the snapshot manifest is stored under, generated by run-all if not set.
+	SnapshotRunID string
+
+	// The dir of the run the applied module records its part of the snapshot manifest in, set by run-all for the
+	// modules of the stack.
+	SnapshotRunDir string
+
+	// The workspace the module is run in, overriding the workspace of its config, set by run-all for the workspaces of
+	// the modules setting several.
+	TerraformWorkspace string
+
+	// True if run-all apply plans the modules first and checks the service quotas against the resources the plans create.
+	QuotaPreflight bool
+
+	// True if validate, e.g. with run-all, auto-inits the modules without their backend and with the provider cache, so
+	// that the modules share the downloaded providers.
+	SchemaCache bool
+
+	// The base64 encoded 256 bit local key the values originating from sensitive outputs of the dependencies are encrypted with when written to disk, e.g. in the debug file.
+	SensitiveOutputsKey string
+
+	// The AWS KMS key the values originating from sensitive outputs of the dependencies are encrypted with when written to disk, instead of a local key.
+	SensitiveOutputsKMSKeyID string
+
+	// Select for every module the newest terraform or tofu binary found that matches the version constraints of the module.
+	AutoTerraformBinary bool
+
+	// The dirs, or glob patterns of dirs, searched for the terraform and tofu binaries in addition to the PATH when the binary is selected per module.
+	TerraformBinaryDirs []string
+
+	// The ID of the run, shared by all the modules the run runs, e.g. to stamp it in the tags of the resources with get_run_id(). Generated for every run unless set.
+	RunID string
+
+	// The extra_arguments passed via --terragrunt-extra-args, e.g. `plan:-refresh=false`, added to the extra_arguments of the config of every module for this run.
+	ExtraArgs []string
+
+	// The resources run-all targets with -target, in the form <module path>:<resource address>, passed only to the module owning the resource.
+	Targets []string
+
+	// The resources run-all replaces with -replace, in the form <module path>:<resource address>, passed only to the module owning the resource.
+	Replaces []string
+
+	// True if run-all skips the modules none of the Targets and Replaces is in, instead of running them normally.
+	SkipUntargeted bool
+
+	// True if output-module-groups outputs the details of each module, such as its dependencies, instead of only its path.
+	ModuleGroupsDetails bool
+
+	// The owners of the modules run-all runs, as set by the owner attribute of their config. All the modules are run if not set.
+	Owners []string
+
+	// The file run-all appends the results of the run to, e.g. the duration and the outcome of each module, read by the history command.
+	HistoryFile string
+
+	// Counts the retries of the terraform commands of the module, if set. Shared by the clones of the options, so that run-all can record the retries of each module.
+	Retries *atomic.Int64
+
+	// The addresses of the resources whose attributes the refresh of the module updated, if set. Shared by the clones of the options, so that run-all refresh can report the modules the refresh updated.
+	RefreshedResources *[]string
+
+	// The counts of the resources the plan of the module adds, changes and destroys, if set. Shared by the clones of the options, so that run-all plan can print a summary of the plans of the modules.
+	PlanSummary *PlanSummary
+
+	// The run of the module in its Terraform Cloud workspace, if set. Shared by the clones of the options, so that run-all can report the runs of the modules.
+	TerraformCloudRun *TerraformCloudRun
+
+	// The max attempts of the terraform commands of the modules found flaky in the run history, if set and higher than their retry_max_attempts.
+	FlakyRetryMaxAttempts int
+
+	// Set on the options of a module found flaky in the run history, which is retried up to FlakyRetryMaxAttempts times.
+	Flaky bool
+
+	// Resume the last failed run-all from its checkpoint file in the working dir, skipping the modules which already succeeded.
+	Resume bool
+
+	// The file run-all writes the path, the run group, the start and end times, the attempts, the exit code and the error of each module to, in JSON.
+	ReportFile string
+
+	// Limits the number of modules of the same run group run concurrently during *-all commands, in addition to Parallelism. 0 means no limit.
+	GroupParallelism int
+
+	// The codes of the warnings not logged, in addition to the codes suppressed by the suppress_warnings attribute of the config.
+	SuppressWarnings []string
+
+	// Return the warnings which are not suppressed as errors, instead of logging them.
+	WarningsAsErrors bool
+
+	// The git ref the *-all commands diff the repo against, to run only the modules whose files changed since the ref, and the modules depending on them.
+	ChangedSince string
+
+	// Let run-all destroy destroy the modules whose outputs are consumed by modules not destroyed without confirmation, even with NonInteractive.
+	AllowDestroyWithConsumers bool
+
+	// The tags of the modules the *-all commands run, as set by the tags attribute of their config. All the modules are run if not set.
+	IncludeTags []string
+
+	// The tags of the modules the *-all commands do not run, even if they have one of the IncludeTags.
+	ExcludeTags []string
+
+	// Add the dependencies, direct or not, of the modules of IncludeDirs to the run as already applied, so that their outputs are read without running them, instead of running the direct dependencies.
+	IncludeDependencyClosure bool
+
+	// Read the outputs of the dependencies outside of the working dir and of IncludeDirs, without ever running the command against them, instead of prompting whether to run it against the external dependencies.
+	ReadOnlyExternalDependencies bool
+
+	// Show the values of the sensitive outputs of the dependencies in the logs and the rendered config, instead of masking them.
+	ShowSensitive bool
+
+	// Unix-style glob of directories of the modules run-all destroy must never destroy, failing instead.
+	PreventDestroyPaths []string
+
+	// The URL of the remote cache shared between machines: s3://bucket/prefix, gs://bucket/prefix or http(s)://host/prefix.
+	RemoteCacheURL string
+
+	// The token sent as a bearer token to the HTTP server of the remote cache.
+	RemoteCacheToken string
+
+	// The seconds the outputs of the dependencies stored in the remote cache are used for, the outputs are not stored if zero.
+	RemoteCacheOutputsTTLSec int
+
+	// Run only the modules which failed in the previous run-all of the command, as recorded by its report in ReportFile, and the modules depending on them which did not run.
+	RetryFailed bool
+
 	// Variables for usage in scaffolding.
 	ScaffoldVars []string
 
 	// Files with variables to be used in modules scaffolding.
 	ScaffoldVarFiles []string
 
+	// The environment tree `promote` copies the pinned module versions from.
+	PromoteFrom string
+
+	// The environment tree `promote` copies the pinned module versions to.
+	PromoteTo string
+
+	// The snapshot manifest, or the dir of the snapshot run, `promote` takes the pinned module versions from instead of
+	// the sources of the modules.
+	PromoteSnapshot string
+
+	// True if `promote` only shows the diff, without modifying the configs.
+	PromoteDryRun bool
+
+	// The format `docs` renders the documentation of the stack in, markdown or html.
+	DocsFormat string
+
+	// The file `docs` writes the documentation of the stack to, instead of stdout.
+	DocsOutFile string
+
+	// The file, in the dir of every module, `module-docs` writes the documentation of the module to, instead of stdout.
+	ModuleDocsOutFile string
+
 	// Root directory for graph command.
 	GraphRoot string
 
+	// True if `graph diff` prints the diff in JSON.
+	GraphDiffJSON bool
+
 	// Disable listing of dependent modules in render json output
 	JsonDisableDependentModules bool
 
@@ -300,6 +593,31 @@ type TerragruntOptions struct {
 
 	// Folder to store output files.
 	OutputFolder string
+
+	// The dir run-all plan saves the plans of the modules to, with the manifest of the stack they were computed for.
+	SavePlansDir string
+
+	// The dir of the plans saved by run-all plan that run-all apply applies, after verifying the stack against their
+	// manifest.
+	UsePlansDir string
+}
+
+// PlanSummary is the counts of the resources the plan of a module adds, changes and destroys, as `terraform show
+// -json` reports them. A replaced resource counts as added and destroyed.
+type PlanSummary struct {
+	Add     int
+	Change  int
+	Destroy int
+	// True once the plan of the module is summarized
+	Recorded bool
+}
+
+// TerraformCloudRun is the last run the execution backend terraform_cloud triggered in the workspace of a module.
+type TerraformCloudRun struct {
+	ID        string `json:"id"`
+	Workspace string `json:"workspace"`
+	Status    string `json:"status"`
+	URL       string `json:"url"`
 }
 
 // IAMRoleOptions represents options that are used by Terragrunt to assume an IAM role.
@@ -359,6 +677,8 @@ func NewTerragruntOptions() *TerragruntOptions {
 		RetryMaxAttempts:               DEFAULT_RETRY_MAX_ATTEMPTS,
 		RetrySleepIntervalSec:          DEFAULT_RETRY_SLEEP_INTERVAL_SEC,
 		RetryableErrors:                util.CloneStringList(DEFAULT_RETRYABLE_ERRORS),
+		DownloadMaxAttempts:            DefaultDownloadMaxAttempts,
+		DownloadRetryIntervalSec:       DefaultDownloadRetryIntervalSec,
 		ExcludeDirs:                    []string{},
 		IncludeDirs:                    []string{},
 		ModulesThatInclude:             []string{},
@@ -380,6 +700,8 @@ func NewTerragruntOptions() *TerragruntOptions {
 		},
 		ProviderCacheRegistryNames: defaultProviderCacheRegistryNames,
 		OutputFolder:               "",
+		PrefixTheme:                DefaultPrefixTheme,
+		ExitCodeMode:               DefaultExitCodeMode,
 	}
 }
 
@@ -414,6 +736,14 @@ func GetDefaultIAMAssumeRoleSessionName() string {
 	return fmt.Sprintf("terragrunt-%d", time.Now().UTC().UnixNano())
 }
 
+// RunIDEnvName is the env var the ID of the run is set in, for terraform and the hooks.
+const RunIDEnvName = "TERRAGRUNT_RUN_ID"
+
+// NewRunID returns a new ID of a run, sortable by the time of the run.
+func NewRunID() string {
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + strings.ToLower(util.UniqueId())
+}
+
 // Create a new TerragruntOptions object with reasonable defaults for test usage
 func NewTerragruntOptionsForTest(terragruntConfigPath string) (*TerragruntOptions, error) {
 	opts, err := NewTerragruntOptionsWithConfigPath(terragruntConfigPath)
@@ -462,7 +792,7 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		NonInteractive:                      opts.NonInteractive,
 		TerraformCliArgs:                    util.CloneStringList(opts.TerraformCliArgs),
 		WorkingDir:                          workingDir,
-		Logger:                              util.CreateLogEntryWithWriter(opts.ErrWriter, workingDir, opts.LogLevel, opts.Logger.Logger.Hooks),
+		Logger:                              opts.cloneLogger(workingDir),
 		LogLevel:                            opts.LogLevel,
 		ValidateStrict:                      opts.ValidateStrict,
 		Env:                                 util.CloneStringMap(opts.Env),
@@ -473,6 +803,7 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		Debug:                               opts.Debug,
 		OriginalIAMRoleOptions:              opts.OriginalIAMRoleOptions,
 		IAMRoleOptions:                      opts.IAMRoleOptions,
+		AWSProfile:                          opts.AWSProfile,
 		IgnoreDependencyErrors:              opts.IgnoreDependencyErrors,
 		IgnoreDependencyOrder:               opts.IgnoreDependencyOrder,
 		IgnoreExternalDependencies:          opts.IgnoreExternalDependencies,
@@ -483,6 +814,9 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		AutoRetry:                           opts.AutoRetry,
 		RetryMaxAttempts:                    opts.RetryMaxAttempts,
 		RetrySleepIntervalSec:               opts.RetrySleepIntervalSec,
+		StateLockRetryMaxAttempts:           opts.StateLockRetryMaxAttempts,
+		StateLockRetryBackoff:               opts.StateLockRetryBackoff,
+		StateLockRetryMaxBackoff:            opts.StateLockRetryMaxBackoff,
 		RetryableErrors:                     util.CloneStringList(opts.RetryableErrors),
 		ExcludeDirs:                         opts.ExcludeDirs,
 		IncludeDirs:                         opts.IncludeDirs,
@@ -491,6 +825,12 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		StrictInclude:                       opts.StrictInclude,
 		RunTerragrunt:                       opts.RunTerragrunt,
 		AwsProviderPatchOverrides:           opts.AwsProviderPatchOverrides,
+		AwsProviderPatchProviders:           util.CloneStringList(opts.AwsProviderPatchProviders),
+		AwsProviderPatchSetMissing:          opts.AwsProviderPatchSetMissing,
+		AwsProviderPatchInjectVars:          opts.AwsProviderPatchInjectVars,
+		AwsProviderPatchDryRun:              opts.AwsProviderPatchDryRun,
+		LicenseDenylist:                     util.CloneStringList(opts.LicenseDenylist),
+		LicenseReportFile:                   opts.LicenseReportFile,
 		HclFile:                             opts.HclFile,
 		JSONOut:                             opts.JSONOut,
 		Check:                               opts.Check,
@@ -501,12 +841,94 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		IncludeModulePrefix:                 opts.IncludeModulePrefix,
 		FailIfBucketCreationRequired:        opts.FailIfBucketCreationRequired,
 		DisableBucketUpdate:                 opts.DisableBucketUpdate,
+		StackLock:                           opts.StackLock,
+		StealStackLock:                      opts.StealStackLock,
+		ModuleDurationsFile:                 opts.ModuleDurationsFile,
+		ContinueOnError:                     opts.ContinueOnError,
+		AssumeAppliedDirs:                   util.CloneStringList(opts.AssumeAppliedDirs),
+		SkipUnchanged:                       opts.SkipUnchanged,
+		PlanGatedApply:                      opts.PlanGatedApply,
+		ConfirmEach:                         opts.ConfirmEach,
+		BufferOutput:                        opts.BufferOutput,
+		NoPrefix:                            opts.NoPrefix,
+		PrefixTheme:                         opts.PrefixTheme,
+		NoProgress:                          opts.NoProgress,
+		GitHubAnnotations:                   opts.GitHubAnnotations,
+		ExitCodeMode:                        opts.ExitCodeMode,
+		ExitCodes:                           util.CloneStringList(opts.ExitCodes),
+		ExecPolicyConfigPath:                opts.ExecPolicyConfigPath,
+		ExecAllow:                           util.CloneStringList(opts.ExecAllow),
+		ExecDeny:                            util.CloneStringList(opts.ExecDeny),
+		ExecNoNetwork:                       opts.ExecNoNetwork,
+		ExecPolicies:                        opts.ExecPolicies,
+		Restricted:                          opts.Restricted,
+		ReadOnly:                            opts.ReadOnly,
+		AllowDestroyDirs:                    util.CloneStringList(opts.AllowDestroyDirs),
+		RunQueueFile:                        opts.RunQueueFile,
+		Shard:                               opts.Shard,
+		ShardLedger:                         opts.ShardLedger,
+		Offline:                             opts.Offline,
+		OfflineAllowedHosts:                 util.CloneStringList(opts.OfflineAllowedHosts),
+		DownloadMaxAttempts:                 opts.DownloadMaxAttempts,
+		DownloadRetryIntervalSec:            opts.DownloadRetryIntervalSec,
+		DownloadMirrors:                     util.CloneStringList(opts.DownloadMirrors),
+		SnapshotDir:                         opts.SnapshotDir,
+		SnapshotRunID:                       opts.SnapshotRunID,
+		SnapshotRunDir:                      opts.SnapshotRunDir,
+		TerraformWorkspace:                  opts.TerraformWorkspace,
+		QuotaPreflight:                      opts.QuotaPreflight,
+		SchemaCache:                         opts.SchemaCache,
+		SensitiveOutputsKey:                 opts.SensitiveOutputsKey,
+		SensitiveOutputsKMSKeyID:            opts.SensitiveOutputsKMSKeyID,
+		AutoTerraformBinary:                 opts.AutoTerraformBinary,
+		TerraformBinaryDirs:                 util.CloneStringList(opts.TerraformBinaryDirs),
+		RunID:                               opts.RunID,
+		ExtraArgs:                           util.CloneStringList(opts.ExtraArgs),
+		Targets:                             util.CloneStringList(opts.Targets),
+		Replaces:                            util.CloneStringList(opts.Replaces),
+		SkipUntargeted:                      opts.SkipUntargeted,
+		ModuleGroupsDetails:                 opts.ModuleGroupsDetails,
+		Owners:                              util.CloneStringList(opts.Owners),
+		HistoryFile:                         opts.HistoryFile,
+		Retries:                             opts.Retries,
+		RefreshedResources:                  opts.RefreshedResources,
+		PlanSummary:                         opts.PlanSummary,
+		TerraformCloudRun:                   opts.TerraformCloudRun,
+		FlakyRetryMaxAttempts:               opts.FlakyRetryMaxAttempts,
+		Flaky:                               opts.Flaky,
+		Resume:                              opts.Resume,
+		ReportFile:                          opts.ReportFile,
+		GroupParallelism:                    opts.GroupParallelism,
+		SuppressWarnings:                    util.CloneStringList(opts.SuppressWarnings),
+		WarningsAsErrors:                    opts.WarningsAsErrors,
+		ChangedSince:                        opts.ChangedSince,
+		AllowDestroyWithConsumers:           opts.AllowDestroyWithConsumers,
+		IncludeTags:                         util.CloneStringList(opts.IncludeTags),
+		ExcludeTags:                         util.CloneStringList(opts.ExcludeTags),
+		IncludeDependencyClosure:            opts.IncludeDependencyClosure,
+		ReadOnlyExternalDependencies:        opts.ReadOnlyExternalDependencies,
+		ShowSensitive:                       opts.ShowSensitive,
+		PreventDestroyPaths:                 util.CloneStringList(opts.PreventDestroyPaths),
+		RemoteCacheURL:                      opts.RemoteCacheURL,
+		RemoteCacheToken:                    opts.RemoteCacheToken,
+		RemoteCacheOutputsTTLSec:            opts.RemoteCacheOutputsTTLSec,
+		RetryFailed:                         opts.RetryFailed,
 		TerraformImplementation:             opts.TerraformImplementation,
 		JsonLogFormat:                       opts.JsonLogFormat,
+		LogFormat:                           opts.LogFormat,
+		LogFields:                           util.CloneStringList(opts.LogFields),
 		TerraformLogsToJson:                 opts.TerraformLogsToJson,
 		GraphRoot:                           opts.GraphRoot,
+		GraphDiffJSON:                       opts.GraphDiffJSON,
 		ScaffoldVars:                        opts.ScaffoldVars,
 		ScaffoldVarFiles:                    opts.ScaffoldVarFiles,
+		PromoteFrom:                         opts.PromoteFrom,
+		PromoteTo:                           opts.PromoteTo,
+		PromoteSnapshot:                     opts.PromoteSnapshot,
+		PromoteDryRun:                       opts.PromoteDryRun,
+		DocsFormat:                          opts.DocsFormat,
+		DocsOutFile:                         opts.DocsOutFile,
+		ModuleDocsOutFile:                   opts.ModuleDocsOutFile,
 		JsonDisableDependentModules:         opts.JsonDisableDependentModules,
 		ProviderCache:                       opts.ProviderCache,
 		ProviderCacheDir:                    opts.ProviderCacheDir,
@@ -514,6 +936,8 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		ProviderCacheDisablePartialLockFile: opts.ProviderCacheDisablePartialLockFile,
 		DisableLogColors:                    opts.DisableLogColors,
 		OutputFolder:                        opts.OutputFolder,
+		SavePlansDir:                        opts.SavePlansDir,
+		UsePlansDir:                         opts.UsePlansDir,
 	}
 }
 
@@ -542,6 +966,23 @@ func extractPlanFile(argsToInsert []string) (*string, []string) {
 	return nil, filteredArgs
 }
 
+// StructuredLogs returns true if the logs are in a format meant to be parsed, rather than read by humans.
+func (opts *TerragruntOptions) StructuredLogs() bool {
+	return opts.JsonLogFormat || opts.LogFormat == util.LogFormatJSON || opts.LogFormat == util.LogFormatLogfmt
+}
+
+// cloneLogger creates the logger of a clone of these options for the module in the given working dir, keeping the
+// command, run group and run ID fields of the current logger.
+func (opts *TerragruntOptions) cloneLogger(workingDir string) *logrus.Entry {
+	logger := util.CreateLogEntryWithWriter(opts.ErrWriter, workingDir, opts.LogLevel, opts.Logger.Logger.Hooks)
+	for _, field := range []string{util.LogFieldCommand, util.LogFieldGroup, util.LogFieldRunID} {
+		if value, ok := opts.Logger.Data[field]; ok {
+			logger = logger.WithField(field, value)
+		}
+	}
+	return logger
+}
+
 // Inserts the given argsToInsert after the terraform command argument, but before the remaining args
 func (opts *TerragruntOptions) InsertTerraformCliArgs(argsToInsert ...string) {
 	planFile, restArgs := extractPlanFile(argsToInsert)
@@ -0,0 +1,80 @@
+package options
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The codes of the warnings, like the codes of a linter, so that each warning can be suppressed, or turned into an error,
+// on its own. The 1xxx codes are deprecated usages, the 2xxx codes implicit behaviors and the 3xxx codes skipped modules.
+const (
+	WarningDeprecatedCommand              = "TG1001"
+	WarningDeprecatedLockTable            = "TG1002"
+	WarningDeprecatedSkipBucketAccessLogs = "TG1003"
+	WarningAutoInitDisabled               = "TG2001"
+	WarningWorkspaceSelectedByEnv         = "TG2002"
+	WarningStateBucketNotVersioned        = "TG2003"
+	WarningModuleSkippedWithDependency    = "TG3001"
+)
+
+// WarningCodes describes the warnings by code.
+var WarningCodes = map[string]string{
+	WarningDeprecatedCommand:              "A deprecated command, e.g. apply-all, is run as its replacement",
+	WarningDeprecatedLockTable:            "The deprecated lock_table attribute of the S3 remote state is used as dynamodb_table",
+	WarningDeprecatedSkipBucketAccessLogs: "The deprecated skip_bucket_accesslogging attribute of the S3 remote state is set",
+	WarningAutoInitDisabled:               "Init is needed, but is not run as Auto-Init is disabled",
+	WarningWorkspaceSelectedByEnv:         "The workspace of the config is not selected, as TF_WORKSPACE selects another workspace",
+	WarningStateBucketNotVersioned:        "Versioning is not enabled for the remote state bucket",
+	WarningModuleSkippedWithDependency:    "A module of run-all is skipped, as one of its dependencies was skipped",
+}
+
+// Warn logs the warning with the given code, unless the code is suppressed. With WarningsAsErrors, the warning is
+// returned as an error instead of being logged.
+func (opts *TerragruntOptions) Warn(code string, format string, args ...interface{}) error {
+	if util.ListContainsElement(opts.SuppressWarnings, code) {
+		return nil
+	}
+
+	message := fmt.Sprintf(format, args...)
+	if opts.WarningsAsErrors {
+		return errors.WithStackTrace(WarningAsError{Code: code, Message: message})
+	}
+	opts.Logger.Warnf("[%s] %s", code, message)
+	return nil
+}
+
+// ValidateWarningCodes returns an error if any of the given codes is not the code of a warning.
+func ValidateWarningCodes(codes []string) error {
+	for _, code := range codes {
+		if _, ok := WarningCodes[code]; !ok {
+			return errors.WithStackTrace(UnknownWarningCode(code))
+		}
+	}
+	return nil
+}
+
+// Custom error types
+
+type WarningAsError struct {
+	Code    string
+	Message string
+}
+
+func (err WarningAsError) Error() string {
+	return fmt.Sprintf("[%s] %s. Warnings are errors, as --terragrunt-warnings-as-errors is set: fix the warning, or suppress it.", err.Code, err.Message)
+}
+
+type UnknownWarningCode string
+
+func (code UnknownWarningCode) Error() string {
+	codes := make([]string, 0, len(WarningCodes))
+	for code := range WarningCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return fmt.Sprintf("Unknown warning code %s, must be one of: %s", string(code), strings.Join(codes, ", "))
+}
@@ -0,0 +1,117 @@
+package outputstore
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// ssmStore publishes the outputs as parameters of SSM Parameter Store, the sensitive outputs as SecureString parameters.
+type ssmStore struct {
+	client   *ssm.SSM
+	kmsKeyID string
+}
+
+func newSSMStore(config Config, opts *options.TerragruntOptions) (*ssmStore, error) {
+	sess, err := awsSession(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &ssmStore{client: ssm.New(sess), kmsKeyID: config.KmsKeyID}, nil
+}
+
+func (store *ssmStore) Put(ctx context.Context, key string, value string, sensitive bool) error {
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(key),
+		Value:     aws.String(value),
+		Overwrite: aws.Bool(true),
+		Type:      aws.String(ssm.ParameterTypeString),
+		// The parameters of the large outputs switch to the advanced tier
+		Tier: aws.String(ssm.ParameterTierIntelligentTiering),
+	}
+	if sensitive {
+		input.Type = aws.String(ssm.ParameterTypeSecureString)
+		if store.kmsKeyID != "" {
+			input.KeyId = aws.String(store.kmsKeyID)
+		}
+	}
+
+	_, err := store.client.PutParameterWithContext(ctx, input)
+	return errors.WithStackTrace(err)
+}
+
+func (store *ssmStore) Get(ctx context.Context, key string) (string, error) {
+	output, err := store.client.GetParameterWithContext(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(key),
+		WithDecryption: aws.Bool(true),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == ssm.ErrCodeParameterNotFound {
+		return "", errors.WithStackTrace(KeyNotFound(key))
+	}
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return aws.StringValue(output.Parameter.Value), nil
+}
+
+// secretsManagerStore publishes the outputs as secrets of Secrets Manager, creating the secrets which do not exist yet.
+type secretsManagerStore struct {
+	client   *secretsmanager.SecretsManager
+	kmsKeyID string
+}
+
+func newSecretsManagerStore(config Config, opts *options.TerragruntOptions) (*secretsManagerStore, error) {
+	sess, err := awsSession(config, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &secretsManagerStore{client: secretsmanager.New(sess), kmsKeyID: config.KmsKeyID}, nil
+}
+
+func (store *secretsManagerStore) Put(ctx context.Context, key string, value string, sensitive bool) error {
+	_, err := store.client.PutSecretValueWithContext(ctx, &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(key),
+		SecretString: aws.String(value),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+		input := &secretsmanager.CreateSecretInput{
+			Name:         aws.String(key),
+			SecretString: aws.String(value),
+		}
+		if store.kmsKeyID != "" {
+			input.KmsKeyId = aws.String(store.kmsKeyID)
+		}
+		_, err = store.client.CreateSecretWithContext(ctx, input)
+	}
+	return errors.WithStackTrace(err)
+}
+
+func (store *secretsManagerStore) Get(ctx context.Context, key string) (string, error) {
+	output, err := store.client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(key),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+		return "", errors.WithStackTrace(KeyNotFound(key))
+	}
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return aws.StringValue(output.SecretString), nil
+}
+
+// awsSession returns the session of the region of the given config, or of the default region of the environment.
+func awsSession(config Config, opts *options.TerragruntOptions) (*session.Session, error) {
+	var sessionConfig *aws_helper.AwsSessionConfig
+	if config.Region != "" {
+		sessionConfig = &aws_helper.AwsSessionConfig{Region: config.Region}
+	}
+	return aws_helper.CreateAwsSession(sessionConfig, opts)
+}
@@ -0,0 +1,62 @@
+package outputstore
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+// gcsStore publishes the outputs as objects of a GCS bucket, named by their key, the sensitive outputs encrypted with
+// the KMS key of the config. The objects are readable by anyone who can read the bucket, so the sensitive outputs are
+// not published without the KMS key.
+type gcsStore struct {
+	bucket     *storage.BucketHandle
+	kmsKeyName string
+}
+
+func newGCSStore(config Config) (*gcsStore, error) {
+	// The client is authenticated as for the gcs remote state, with the credentials of the environment
+	client, err := remote.CreateGCSClient(remote.RemoteStateConfigGCS{})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return &gcsStore{bucket: client.Bucket(config.Bucket), kmsKeyName: config.KmsKeyID}, nil
+}
+
+func (store *gcsStore) Put(ctx context.Context, key string, value string, sensitive bool) error {
+	if sensitive && store.kmsKeyName == "" {
+		return errors.WithStackTrace(SensitiveOutputWithoutKMSKey(key))
+	}
+
+	writer := store.bucket.Object(key).NewWriter(ctx)
+	writer.ContentType = "text/plain; charset=utf-8"
+	if sensitive {
+		writer.KMSKeyName = store.kmsKeyName
+	}
+	if _, err := writer.Write([]byte(value)); err != nil {
+		_ = writer.Close()
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(writer.Close())
+}
+
+func (store *gcsStore) Get(ctx context.Context, key string) (string, error) {
+	reader, err := store.bucket.Object(key).NewReader(ctx)
+	if err == storage.ErrObjectNotExist {
+		return "", errors.WithStackTrace(KeyNotFound(key))
+	}
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	defer reader.Close()
+
+	value, err := io.ReadAll(reader)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return string(value), nil
+}
@@ -0,0 +1,260 @@
+// Package outputstore publishes the outputs of the modules to the parameter stores shared with the consumers without
+// access to the state, e.g. applications or the stacks of other teams: SSM Parameter Store, Secrets Manager and GCS,
+// and reads the published outputs back.
+package outputstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The backends of the stores.
+const (
+	BackendSSM            = "ssm"
+	BackendSecretsManager = "secrets_manager"
+	BackendGCS            = "gcs"
+)
+
+// OutputPlaceholder is replaced by the name of the output in the keys, so that each output is published to its own key.
+// The outputs of a key without the placeholder are published together to the key, as a JSON object.
+const OutputPlaceholder = "{output}"
+
+var backends = []string{BackendSSM, BackendSecretsManager, BackendGCS}
+
+// Config is the store the outputs are published to.
+type Config struct {
+	Backend string
+	// The AWS region of the ssm and secrets_manager backends, the default region of the environment if empty
+	Region string
+	// The bucket of the gcs backend
+	Bucket string
+	// The KMS key encrypting the sensitive values of the ssm backend and the secrets created by the secrets_manager
+	// backend, the default key of the service if empty. The Cloud KMS key encrypting the sensitive values of the gcs
+	// backend, which refuses to publish them without it
+	KmsKeyID string
+}
+
+// Store is a parameter store holding the published outputs by key.
+type Store interface {
+	// Put writes the value to the key, encrypted if the value is sensitive and the store supports it.
+	Put(ctx context.Context, key string, value string, sensitive bool) error
+	// Get reads the value of the key, or returns a KeyNotFound error if the key does not exist.
+	Get(ctx context.Context, key string) (string, error)
+}
+
+// Output is an output of the module, as in `terraform output -json`.
+type Output struct {
+	Sensitive bool            `json:"sensitive"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Validate checks that the backend is supported and has the settings it requires.
+func (config Config) Validate() error {
+	switch config.Backend {
+	case BackendSSM, BackendSecretsManager:
+		return nil
+	case BackendGCS:
+		if config.Bucket == "" {
+			return errors.WithStackTrace(MissingBucket{})
+		}
+		return nil
+	}
+	return errors.WithStackTrace(UnsupportedBackend(config.Backend))
+}
+
+// New returns the store of the given config.
+func New(config Config, opts *options.TerragruntOptions) (Store, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch config.Backend {
+	case BackendSSM:
+		return newSSMStore(config, opts)
+	case BackendSecretsManager:
+		return newSecretsManagerStore(config, opts)
+	}
+	return newGCSStore(config)
+}
+
+// Publish writes the outputs with the given names, or all the outputs if names is nil, to the keys of the given key,
+// and returns the keys written. The outputs are written to a key each if the key has the output placeholder, and
+// together as a JSON object otherwise.
+func Publish(ctx context.Context, store Store, key string, outputs map[string]Output, names []string) ([]string, error) {
+	if names == nil {
+		for name := range outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	selected := map[string]Output{}
+	for _, name := range names {
+		output, ok := outputs[name]
+		if !ok {
+			return nil, errors.WithStackTrace(OutputNotFound(name))
+		}
+		selected[name] = output
+	}
+
+	if !strings.Contains(key, OutputPlaceholder) {
+		values := map[string]json.RawMessage{}
+		sensitive := false
+		for name, output := range selected {
+			values[name] = output.Value
+			sensitive = sensitive || output.Sensitive
+		}
+		value, err := json.Marshal(values)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		if err := store.Put(ctx, key, string(value), sensitive); err != nil {
+			return nil, err
+		}
+		return []string{key}, nil
+	}
+
+	var keys []string
+	for _, name := range names {
+		outputKey := strings.ReplaceAll(key, OutputPlaceholder, name)
+		value, err := EncodeValue(selected[name].Value)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.Put(ctx, outputKey, value, selected[name].Sensitive); err != nil {
+			return nil, err
+		}
+		keys = append(keys, outputKey)
+	}
+	return keys, nil
+}
+
+// Read reads the outputs with the given names published to the keys of the given key, as Publish writes them, and
+// returns their JSON values. The outputs are read from a key each if the key has the output placeholder, which requires
+// the names, and from the JSON object of the key otherwise, all its outputs if names is nil.
+func Read(ctx context.Context, store Store, key string, names []string) (map[string]json.RawMessage, error) {
+	if !strings.Contains(key, OutputPlaceholder) {
+		value, err := store.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(value), &values); err != nil {
+			return nil, errors.WithStackTrace(InvalidPublishedOutputs{Key: key, Err: err})
+		}
+		if names == nil {
+			return values, nil
+		}
+
+		selected := map[string]json.RawMessage{}
+		for _, name := range names {
+			value, ok := values[name]
+			if !ok {
+				return nil, errors.WithStackTrace(KeyNotFound(key + ":" + name))
+			}
+			selected[name] = value
+		}
+		return selected, nil
+	}
+
+	if names == nil {
+		return nil, errors.WithStackTrace(MissingOutputNames(key))
+	}
+
+	values := map[string]json.RawMessage{}
+	for _, name := range names {
+		value, err := store.Get(ctx, strings.ReplaceAll(key, OutputPlaceholder, name))
+		if err != nil {
+			return nil, err
+		}
+		values[name] = DecodeValue(value)
+	}
+	return values, nil
+}
+
+// EncodeValue returns the published value of the given JSON value of an output: the strings as is, so that the
+// consumers read them without decoding them, and the other values as JSON.
+func EncodeValue(value json.RawMessage) (string, error) {
+	var str string
+	if err := json.Unmarshal(value, &str); err == nil {
+		return str, nil
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, value); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return compacted.String(), nil
+}
+
+// DecodeValue returns the JSON value of the given published value: the JSON objects and lists as is, and the other
+// values as strings, as the strings are published as is.
+func DecodeValue(value string) json.RawMessage {
+	trimmed := strings.TrimSpace(value)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return json.RawMessage(trimmed)
+	}
+
+	// Marshalling a string does not fail
+	encoded, _ := json.Marshal(value)
+	return encoded
+}
+
+// Custom error types
+
+type UnsupportedBackend string
+
+func (backend UnsupportedBackend) Error() string {
+	return fmt.Sprintf("Unsupported backend %q to publish the outputs to, expected one of %s", string(backend), strings.Join(backends, ", "))
+}
+
+type MissingBucket struct{}
+
+func (err MissingBucket) Error() string {
+	return fmt.Sprintf("The bucket to publish the outputs to is required with the %s backend", BackendGCS)
+}
+
+type SensitiveOutputWithoutKMSKey string
+
+func (key SensitiveOutputWithoutKMSKey) Error() string {
+	return fmt.Sprintf("Refusing to publish the sensitive outputs to the key %s of the %s backend without a kms_key_id to encrypt them with", string(key), BackendGCS)
+}
+
+type OutputNotFound string
+
+func (name OutputNotFound) Error() string {
+	return fmt.Sprintf("The module has no output named %q to publish", string(name))
+}
+
+type KeyNotFound string
+
+func (key KeyNotFound) Error() string {
+	return fmt.Sprintf("No output is published to the key %s", string(key))
+}
+
+type MissingOutputNames string
+
+func (key MissingOutputNames) Error() string {
+	return fmt.Sprintf("The names of the outputs to read are required with the key %s, as each output is published to its own key", string(key))
+}
+
+type InvalidPublishedOutputs struct {
+	Key string
+	Err error
+}
+
+func (err InvalidPublishedOutputs) Error() string {
+	return fmt.Sprintf("The outputs published to the key %s are not a JSON object: %v", err.Key, err.Err)
+}
+
+func (err InvalidPublishedOutputs) Unwrap() error {
+	return err.Err
+}
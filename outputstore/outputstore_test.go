@@ -0,0 +1,174 @@
+package outputstore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	values    map[string]string
+	sensitive map[string]bool
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{values: map[string]string{}, sensitive: map[string]bool{}}
+}
+
+func (store *memoryStore) Put(ctx context.Context, key string, value string, sensitive bool) error {
+	store.values[key] = value
+	store.sensitive[key] = sensitive
+	return nil
+}
+
+func (store *memoryStore) Get(ctx context.Context, key string) (string, error) {
+	value, ok := store.values[key]
+	if !ok {
+		return "", errors.WithStackTrace(KeyNotFound(key))
+	}
+	return value, nil
+}
+
+var testOutputs = map[string]Output{
+	"vpc_id":     {Value: json.RawMessage(`"vpc-123"`)},
+	"subnet_ids": {Value: json.RawMessage(`[ "subnet-1", "subnet-2" ]`)},
+	"db_password": {
+		Sensitive: true,
+		Value:     json.RawMessage(`"hunter2"`),
+	},
+}
+
+func TestPublishOutputsToKeyEach(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	keys, err := Publish(context.Background(), store, "/prod/vpc/{output}", testOutputs, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/prod/vpc/db_password", "/prod/vpc/subnet_ids", "/prod/vpc/vpc_id"}, keys)
+	assert.Equal(t, map[string]string{
+		"/prod/vpc/db_password": "hunter2",
+		"/prod/vpc/subnet_ids":  `["subnet-1","subnet-2"]`,
+		"/prod/vpc/vpc_id":      "vpc-123",
+	}, store.values)
+	assert.Equal(t, map[string]bool{"/prod/vpc/db_password": true, "/prod/vpc/subnet_ids": false, "/prod/vpc/vpc_id": false}, store.sensitive)
+}
+
+func TestPublishSelectedOutputsToOneKey(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	keys, err := Publish(context.Background(), store, "prod/vpc", testOutputs, []string{"vpc_id", "subnet_ids"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prod/vpc"}, keys)
+	assert.JSONEq(t, `{"vpc_id": "vpc-123", "subnet_ids": ["subnet-1", "subnet-2"]}`, store.values["prod/vpc"])
+	assert.False(t, store.sensitive["prod/vpc"])
+
+	_, err = Publish(context.Background(), store, "prod/vpc", testOutputs, []string{"vpc_id", "db_password"})
+	require.NoError(t, err)
+	assert.True(t, store.sensitive["prod/vpc"])
+}
+
+func TestPublishMissingOutput(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	_, err := Publish(context.Background(), store, "/prod/vpc/{output}", testOutputs, []string{"vpc_id", "vpc_cidr"})
+	assert.Equal(t, OutputNotFound("vpc_cidr"), errors.Unwrap(err))
+	assert.Empty(t, store.values)
+}
+
+func TestReadPublishedOutputs(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	_, err := Publish(context.Background(), store, "/prod/vpc/{output}", testOutputs, nil)
+	require.NoError(t, err)
+	_, err = Publish(context.Background(), store, "prod/vpc", testOutputs, []string{"vpc_id", "subnet_ids"})
+	require.NoError(t, err)
+
+	values, err := Read(context.Background(), store, "/prod/vpc/{output}", []string{"vpc_id", "subnet_ids"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{"vpc_id": json.RawMessage(`"vpc-123"`), "subnet_ids": json.RawMessage(`["subnet-1","subnet-2"]`)}, values)
+
+	values, err = Read(context.Background(), store, "prod/vpc", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{"vpc_id": json.RawMessage(`"vpc-123"`), "subnet_ids": json.RawMessage(`["subnet-1","subnet-2"]`)}, values)
+
+	values, err = Read(context.Background(), store, "prod/vpc", []string{"vpc_id"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{"vpc_id": json.RawMessage(`"vpc-123"`)}, values)
+
+	_, err = Read(context.Background(), store, "prod/vpc", []string{"vpc_cidr"})
+	assert.IsType(t, KeyNotFound(""), errors.Unwrap(err))
+
+	_, err = Read(context.Background(), store, "/stage/vpc/{output}", []string{"vpc_id"})
+	assert.Equal(t, KeyNotFound("/stage/vpc/vpc_id"), errors.Unwrap(err))
+
+	_, err = Read(context.Background(), store, "/prod/vpc/{output}", nil)
+	assert.Equal(t, MissingOutputNames("/prod/vpc/{output}"), errors.Unwrap(err))
+}
+
+func TestDecodeValue(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, json.RawMessage(`"vpc-123"`), DecodeValue("vpc-123"))
+	assert.Equal(t, json.RawMessage(`"3"`), DecodeValue("3"))
+	assert.Equal(t, json.RawMessage(`{"a":1}`), DecodeValue(`{"a":1}`))
+	assert.Equal(t, json.RawMessage(`"[not json"`), DecodeValue("[not json"))
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("outputstore_test")
+	require.NoError(t, err)
+
+	_, err = New(Config{Backend: "consul"}, opts)
+	assert.Equal(t, UnsupportedBackend("consul"), errors.Unwrap(err))
+
+	_, err = New(Config{Backend: BackendGCS}, opts)
+	assert.Equal(t, MissingBucket{}, errors.Unwrap(err))
+
+	assert.NoError(t, Config{Backend: BackendGCS, Bucket: "outputs"}.Validate())
+}
+
+func TestGCSStoreSensitiveOutputs(t *testing.T) {
+	t.Parallel()
+
+	kmsKeyNames := map[string]string{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		kmsKeyNames[r.URL.Query().Get("name")] = r.URL.Query().Get("kmsKeyName")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"bucket": "outputs", "name": "published"}`))
+	}))
+	defer server.Close()
+
+	client, err := storage.NewClient(context.Background(), option.WithEndpoint(server.URL+"/storage/v1/"), option.WithoutAuthentication())
+	require.NoError(t, err)
+
+	store := &gcsStore{bucket: client.Bucket("outputs")}
+	require.NoError(t, store.Put(context.Background(), "prod/vpc_id", "vpc-123", false))
+	err = store.Put(context.Background(), "prod/db_password", "hunter2", true)
+	assert.IsType(t, SensitiveOutputWithoutKMSKey(""), errors.Unwrap(err))
+	assert.NotContains(t, kmsKeyNames, "prod/db_password")
+
+	kmsKeyName := "projects/my-project/locations/us/keyRings/outputs/cryptoKeys/outputs"
+	store.kmsKeyName = kmsKeyName
+	require.NoError(t, store.Put(context.Background(), "prod/db_password", "hunter2", true))
+	require.NoError(t, store.Put(context.Background(), "prod/subnet_ids", "[]", false))
+	assert.Equal(t, map[string]string{"prod/vpc_id": "", "prod/db_password": kmsKeyName, "prod/subnet_ids": ""}, kmsKeyNames)
+}
@@ -65,6 +65,10 @@ type App struct {
 	// library. This library supports bash, zsh and fish. To add support
 	// for other shells, please see that library.
 	AutocompleteInstaller AutocompleteInstaller
+
+	// FlagValueCompletions are the functions completing the values of
+	// flags, by flag name without the hyphens.
+	FlagValueCompletions map[string]FlagValueCompleteFunc
 }
 
 // NewApp returns app new App instance.
@@ -119,6 +123,10 @@ func (app *App) RunContext(ctx context.Context, arguments []string) (err error)
 			}
 
 			if compLine := os.Getenv(envCompleteLine); compLine != "" {
+				if completed, err := app.completeFlagValue(ctx, compLine); completed {
+					return err
+				}
+
 				args = strings.Fields(compLine)
 				if args[0] == app.Name {
 					args = args[1:]
@@ -54,6 +54,45 @@ func (i *autocompleteInstaller) Uninstall(cmd string) error {
 	return nil
 }
 
+// completeFlagValue prints the suggestions for the value of a flag, if the word being completed in the given line is the
+// value of a flag that has a function in FlagValueCompletions. Returns false if it is not.
+func (app *App) completeFlagValue(ctx *Context, compLine string) (bool, error) {
+	words := strings.Fields(compLine)
+	if len(words) == 0 {
+		return false, nil
+	}
+
+	// The word being completed is empty if the line ends with a space.
+	current, previous := "", words[len(words)-1]
+	if !strings.HasSuffix(compLine, " ") {
+		current, previous = words[len(words)-1], ""
+		if len(words) > 1 {
+			previous = words[len(words)-2]
+		}
+	}
+
+	flagName, prefix := strings.TrimLeft(previous, "-"), ""
+	if strings.HasPrefix(current, "-") && strings.Contains(current, "=") {
+		// `--flag=value` form
+		name, value, _ := strings.Cut(current, "=")
+		flagName, prefix, current = strings.TrimLeft(name, "-"), name+"=", value
+	} else if !strings.HasPrefix(previous, "-") {
+		return false, nil
+	}
+
+	complete, ok := app.FlagValueCompletions[flagName]
+	if !ok {
+		return false, nil
+	}
+
+	for _, suggestion := range complete(ctx, current) {
+		if _, err := fmt.Fprintln(app.Writer, prefix+suggestion); err != nil {
+			return true, errors.WithStackTrace(err)
+		}
+	}
+	return true, nil
+}
+
 // ShowCompletions prints the lists of commands within a given context
 func ShowCompletions(ctx *Context) error {
 	if cmd := ctx.Command; cmd != nil && cmd.Complete != nil {
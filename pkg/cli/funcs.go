@@ -3,6 +3,9 @@ package cli
 // CompleteFunc is an action to execute when the shell completion flag is set
 type CompleteFunc func(ctx *Context) error
 
+// FlagValueCompleteFunc returns the suggestions for the value of a flag, given the part of the value typed so far.
+type FlagValueCompleteFunc func(ctx *Context, arg string) []string
+
 // ActionFunc is the action to execute when no commands/subcommands are specified.
 type ActionFunc func(ctx *Context) error
 
@@ -0,0 +1,115 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The region of the resources when no region is configured for the session, as in the AWS provider.
+const awsFallbackRegion = "us-east-1"
+
+// AWSClient gets the quotas from the Service Quotas API, and their usage from the APIs of the services.
+type AWSClient struct {
+	session *session.Session
+}
+
+// NewAWSClient returns a client with the default credentials and region of the environment.
+func NewAWSClient(opts *options.TerragruntOptions) (*AWSClient, error) {
+	sess, err := aws_helper.CreateAwsSession(nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &AWSClient{session: sess}, nil
+}
+
+func (client *AWSClient) DefaultRegion() string {
+	if region := aws.StringValue(client.session.Config.Region); region != "" {
+		return region
+	}
+	return awsFallbackRegion
+}
+
+// Limit returns the value of the quota applied to the account, or the default value of the quota if it is not
+// applied, i.e. if it was never increased.
+func (client *AWSClient) Limit(ctx context.Context, quota *ResourceQuota, region string) (int, error) {
+	svc := servicequotas.New(client.session, aws.NewConfig().WithRegion(region))
+
+	output, err := svc.GetServiceQuotaWithContext(ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(quota.ServiceCode),
+		QuotaCode:   aws.String(quota.QuotaCode),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == servicequotas.ErrCodeNoSuchResourceException {
+		defaultOutput, err := svc.GetAWSDefaultServiceQuotaWithContext(ctx, &servicequotas.GetAWSDefaultServiceQuotaInput{
+			ServiceCode: aws.String(quota.ServiceCode),
+			QuotaCode:   aws.String(quota.QuotaCode),
+		})
+		if err != nil {
+			return 0, errors.WithStackTrace(err)
+		}
+		return int(aws.Float64Value(defaultOutput.Quota.Value)), nil
+	}
+	if err != nil {
+		return 0, errors.WithStackTrace(err)
+	}
+	return int(aws.Float64Value(output.Quota.Value)), nil
+}
+
+// Usage returns the number of resources counted by the quota.
+func (client *AWSClient) Usage(ctx context.Context, quota *ResourceQuota, region string) (int, error) {
+	config := aws.NewConfig().WithRegion(region)
+	count := 0
+
+	switch quota.ResourceType {
+	case "aws_vpc":
+		err := ec2.New(client.session, config).DescribeVpcsPagesWithContext(ctx, &ec2.DescribeVpcsInput{}, func(page *ec2.DescribeVpcsOutput, lastPage bool) bool {
+			count += len(page.Vpcs)
+			return true
+		})
+		return count, errors.WithStackTrace(err)
+
+	case "aws_internet_gateway":
+		err := ec2.New(client.session, config).DescribeInternetGatewaysPagesWithContext(ctx, &ec2.DescribeInternetGatewaysInput{}, func(page *ec2.DescribeInternetGatewaysOutput, lastPage bool) bool {
+			count += len(page.InternetGateways)
+			return true
+		})
+		return count, errors.WithStackTrace(err)
+
+	case "aws_eip":
+		output, err := ec2.New(client.session, config).DescribeAddressesWithContext(ctx, &ec2.DescribeAddressesInput{
+			Filters: []*ec2.Filter{{Name: aws.String("domain"), Values: aws.StringSlice([]string{"vpc"})}},
+		})
+		if err != nil {
+			return 0, errors.WithStackTrace(err)
+		}
+		return len(output.Addresses), nil
+
+	case "aws_s3_bucket":
+		output, err := s3.New(client.session, config).ListBucketsWithContext(ctx, &s3.ListBucketsInput{})
+		if err != nil {
+			return 0, errors.WithStackTrace(err)
+		}
+		return len(output.Buckets), nil
+
+	default:
+		return 0, errors.WithStackTrace(UnsupportedQuotaUsage(quota.ResourceType))
+	}
+}
+
+// Custom error types
+
+type UnsupportedQuotaUsage string
+
+func (resourceType UnsupportedQuotaUsage) Error() string {
+	return fmt.Sprintf("Counting the resources of the type %s is not supported", string(resourceType))
+}
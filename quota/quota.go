@@ -0,0 +1,174 @@
+// Package quota checks the service quotas of the cloud accounts against the resources the terraform plans create, so
+// that an apply does not fail halfway through a stack when a quota is reached.
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const awsProviderName = "registry.terraform.io/hashicorp/aws"
+
+// ResourceQuota is a service quota limiting the number of resources of a terraform resource type in a region, or in
+// the account if it is global.
+type ResourceQuota struct {
+	ResourceType string
+	Name         string
+	ServiceCode  string
+	QuotaCode    string
+	Global       bool
+}
+
+// ResourceKey is the type and the region of the planned resources, the region being empty for the default region of
+// the account.
+type ResourceKey struct {
+	Type   string
+	Region string
+}
+
+// PlannedResources is the number of resources the plans create, minus the ones they destroy, by type and region.
+type PlannedResources map[ResourceKey]int
+
+// Exceeded is a quota the planned resources would exceed.
+type Exceeded struct {
+	Quota   *ResourceQuota
+	Region  string
+	Usage   int
+	Planned int
+	Limit   int
+}
+
+func (exceeded Exceeded) String() string {
+	return fmt.Sprintf("%s (%s %s) in %s: %d in use + %d planned > %d", exceeded.Quota.Name, exceeded.Quota.ServiceCode, exceeded.Quota.QuotaCode, exceeded.Region, exceeded.Usage, exceeded.Planned, exceeded.Limit)
+}
+
+// Client returns the current usage and the limit of the quotas.
+type Client interface {
+	Usage(ctx context.Context, quota *ResourceQuota, region string) (int, error)
+	Limit(ctx context.Context, quota *ResourceQuota, region string) (int, error)
+	// DefaultRegion returns the region the resources of the providers without an explicit region are created in.
+	DefaultRegion() string
+}
+
+// AWSResourceQuotas are the AWS quotas checked, the ones most commonly reached when deploying a new environment.
+var AWSResourceQuotas = []*ResourceQuota{
+	{ResourceType: "aws_vpc", Name: "VPCs per Region", ServiceCode: "vpc", QuotaCode: "L-F678F1CE"},
+	{ResourceType: "aws_internet_gateway", Name: "Internet gateways per Region", ServiceCode: "vpc", QuotaCode: "L-A4707A72"},
+	{ResourceType: "aws_eip", Name: "EC2-VPC Elastic IPs", ServiceCode: "ec2", QuotaCode: "L-0263D0A3"},
+	{ResourceType: "aws_s3_bucket", Name: "General purpose buckets", ServiceCode: "s3", QuotaCode: "L-DC2B2D3D", Global: true},
+}
+
+// AddPlan adds the resources the given plan, in the JSON format of `terraform show -json`, creates and destroys. The
+// region of the resources is the region of the default `aws` provider of the plan, if it is set to a constant.
+func (planned PlannedResources) AddPlan(planJSON []byte) error {
+	var plan struct {
+		ResourceChanges []struct {
+			Mode         string `json:"mode"`
+			Type         string `json:"type"`
+			ProviderName string `json:"provider_name"`
+			Change       struct {
+				Actions []string `json:"actions"`
+			} `json:"change"`
+		} `json:"resource_changes"`
+		Configuration struct {
+			ProviderConfig map[string]struct {
+				Expressions map[string]struct {
+					ConstantValue interface{} `json:"constant_value"`
+				} `json:"expressions"`
+			} `json:"provider_config"`
+		} `json:"configuration"`
+	}
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	region := ""
+	if provider, ok := plan.Configuration.ProviderConfig["aws"]; ok {
+		if value, ok := provider.Expressions["region"].ConstantValue.(string); ok {
+			region = value
+		}
+	}
+
+	for _, change := range plan.ResourceChanges {
+		if change.Mode != "managed" || change.ProviderName != awsProviderName {
+			continue
+		}
+		key := ResourceKey{Type: change.Type, Region: region}
+		for _, action := range change.Change.Actions {
+			switch action {
+			case "create":
+				planned[key]++
+			case "delete":
+				planned[key]--
+			}
+		}
+	}
+	return nil
+}
+
+// Check returns the quotas the planned resources would exceed, in addition to the resources in use. The quotas that
+// cannot be checked, e.g. for lack of permissions, are logged and skipped.
+func Check(ctx context.Context, opts *options.TerragruntOptions, client Client, quotas []*ResourceQuota, planned PlannedResources) []Exceeded {
+	// Sum the planned resources by quota, as the global quotas count the resources of all the regions
+	plannedByQuota := map[*ResourceQuota]map[string]int{}
+	for key, count := range planned {
+		for _, quota := range quotas {
+			if quota.ResourceType != key.Type {
+				continue
+			}
+			region := key.Region
+			if region == "" {
+				region = client.DefaultRegion()
+			}
+			if quota.Global {
+				region = "all regions"
+			}
+			if plannedByQuota[quota] == nil {
+				plannedByQuota[quota] = map[string]int{}
+			}
+			plannedByQuota[quota][region] += count
+		}
+	}
+
+	var exceeded []Exceeded
+	for _, quota := range quotas {
+		for region, count := range plannedByQuota[quota] {
+			if count <= 0 {
+				continue
+			}
+			quotaRegion := region
+			if quota.Global {
+				quotaRegion = client.DefaultRegion()
+			}
+
+			limit, err := client.Limit(ctx, quota, quotaRegion)
+			if err != nil {
+				opts.Logger.Warnf("Failed to get the quota %s in %s, not checking it: %v", quota.Name, region, err)
+				continue
+			}
+			usage, err := client.Usage(ctx, quota, quotaRegion)
+			if err != nil {
+				opts.Logger.Warnf("Failed to get the usage of the quota %s in %s, not checking it: %v", quota.Name, region, err)
+				continue
+			}
+			opts.Logger.Debugf("Quota %s in %s: %d in use + %d planned, limit %d", quota.Name, region, usage, count, limit)
+
+			if usage+count > limit {
+				exceeded = append(exceeded, Exceeded{Quota: quota, Region: region, Usage: usage, Planned: count, Limit: limit})
+			}
+		}
+	}
+
+	sort.Slice(exceeded, func(i, j int) bool {
+		if exceeded[i].Quota.Name != exceeded[j].Quota.Name {
+			return exceeded[i].Quota.Name < exceeded[j].Quota.Name
+		}
+		return exceeded[i].Region < exceeded[j].Region
+	})
+	return exceeded
+}
@@ -0,0 +1,89 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPlanJSON = `{
+  "resource_changes": [
+    {"mode": "managed", "type": "aws_vpc", "provider_name": "registry.terraform.io/hashicorp/aws", "change": {"actions": ["create"]}},
+    {"mode": "managed", "type": "aws_eip", "provider_name": "registry.terraform.io/hashicorp/aws", "change": {"actions": ["create"]}},
+    {"mode": "managed", "type": "aws_eip", "provider_name": "registry.terraform.io/hashicorp/aws", "change": {"actions": ["create"]}},
+    {"mode": "managed", "type": "aws_eip", "provider_name": "registry.terraform.io/hashicorp/aws", "change": {"actions": ["delete"]}},
+    {"mode": "managed", "type": "aws_s3_bucket", "provider_name": "registry.terraform.io/hashicorp/aws", "change": {"actions": ["delete", "create"]}},
+    {"mode": "data", "type": "aws_vpc", "provider_name": "registry.terraform.io/hashicorp/aws", "change": {"actions": ["read"]}},
+    {"mode": "managed", "type": "null_resource", "provider_name": "registry.terraform.io/hashicorp/null", "change": {"actions": ["create"]}}
+  ],
+  "configuration": {
+    "provider_config": {
+      "aws": {"name": "aws", "expressions": {"region": {"constant_value": "eu-west-1"}}}
+    }
+  }
+}`
+
+func TestPlannedResourcesAddPlan(t *testing.T) {
+	t.Parallel()
+
+	planned := PlannedResources{}
+	require.NoError(t, planned.AddPlan([]byte(testPlanJSON)))
+	require.NoError(t, planned.AddPlan([]byte(`{"resource_changes": [{"mode": "managed", "type": "aws_vpc", "provider_name": "registry.terraform.io/hashicorp/aws", "change": {"actions": ["create"]}}]}`)))
+
+	assert.Equal(t, PlannedResources{
+		{Type: "aws_vpc", Region: "eu-west-1"}:       1,
+		{Type: "aws_vpc", Region: ""}:                1,
+		{Type: "aws_eip", Region: "eu-west-1"}:       1,
+		{Type: "aws_s3_bucket", Region: "eu-west-1"}: 0,
+	}, planned)
+}
+
+type fakeClient struct {
+	usage  map[string]int
+	limits map[string]int
+}
+
+func (client *fakeClient) Usage(ctx context.Context, quota *ResourceQuota, region string) (int, error) {
+	return client.usage[quota.ResourceType+"/"+region], nil
+}
+
+func (client *fakeClient) Limit(ctx context.Context, quota *ResourceQuota, region string) (int, error) {
+	limit, ok := client.limits[quota.ResourceType+"/"+region]
+	if !ok {
+		return 0, errors.New("access denied")
+	}
+	return limit, nil
+}
+
+func (client *fakeClient) DefaultRegion() string {
+	return "us-east-1"
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	client := &fakeClient{
+		usage:  map[string]int{"aws_vpc/eu-west-1": 4, "aws_vpc/us-east-1": 1, "aws_s3_bucket/us-east-1": 99},
+		limits: map[string]int{"aws_vpc/eu-west-1": 5, "aws_vpc/us-east-1": 5, "aws_s3_bucket/us-east-1": 100},
+	}
+	planned := PlannedResources{
+		{Type: "aws_vpc", Region: "eu-west-1"}:       2,
+		{Type: "aws_vpc", Region: ""}:                2,
+		{Type: "aws_s3_bucket", Region: "eu-west-1"}: 1,
+		{Type: "aws_s3_bucket", Region: ""}:          1,
+		// The limit of the quota is not known
+		{Type: "aws_eip", Region: "eu-west-1"}: 10,
+	}
+
+	exceeded := Check(context.Background(), opts, client, AWSResourceQuotas, planned)
+	require.Len(t, exceeded, 2)
+	assert.Equal(t, "General purpose buckets (s3 L-DC2B2D3D) in all regions: 99 in use + 2 planned > 100", exceeded[0].String())
+	assert.Equal(t, "VPCs per Region (vpc L-F678F1CE) in eu-west-1: 4 in use + 2 planned > 5", exceeded[1].String())
+}
@@ -4,7 +4,9 @@ package remote
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/gruntwork-io/go-commons/errors"
@@ -20,6 +22,8 @@ type RemoteState struct {
 	DisableDependencyOptimization bool
 	Generate                      *RemoteStateGenerate
 	Config                        map[string]interface{}
+	// The OpenTofu state and plan encryption config, see remote_state_encryption.go
+	Encryption map[string]interface{}
 }
 
 // map to store mutexes for each state bucket action
@@ -31,7 +35,7 @@ type stateAccess struct {
 var stateAccessLock = newStateAccess()
 
 func (remoteState *RemoteState) String() string {
-	return fmt.Sprintf("RemoteState{Backend = %v, DisableInit = %v, DisableDependencyOptimization = %v, Generate = %v, Config = %v}", remoteState.Backend, remoteState.DisableInit, remoteState.DisableDependencyOptimization, remoteState.Generate, remoteState.Config)
+	return fmt.Sprintf("RemoteState{Backend = %v, DisableInit = %v, DisableDependencyOptimization = %v, Generate = %v, Config = %v, Encryption = %v}", remoteState.Backend, remoteState.DisableInit, remoteState.DisableDependencyOptimization, remoteState.Generate, remoteState.Config, remoteState.encryptionString())
 }
 
 // Code gen configuration for Terraform remote state
@@ -69,7 +73,7 @@ func (remoteState *RemoteState) Validate() error {
 		return errors.WithStackTrace(ErrRemoteBackendMissing)
 	}
 
-	return nil
+	return remoteState.validateEncryption()
 }
 
 // Perform any actions necessary to initialize the remote state before it's used for storage. For example, if you're
@@ -193,6 +197,49 @@ func (remoteState RemoteState) ToTerraformInitArgs() []string {
 	return backendConfigArgs
 }
 
+// EndpointHost returns the host the backend stores the state at, or an empty string if the state is stored locally. For
+// the backends whose endpoint is not known, the name of the backend is returned.
+func (remoteState *RemoteState) EndpointHost() string {
+	configString := func(key string) string {
+		value, _ := remoteState.Config[key].(string)
+		return value
+	}
+
+	switch remoteState.Backend {
+	case "local":
+		return ""
+	case "s3":
+		if endpoint := configString("endpoint"); endpoint != "" {
+			return hostOf(endpoint)
+		}
+		return fmt.Sprintf("s3.%s.amazonaws.com", configString("region"))
+	case "gcs":
+		return "storage.googleapis.com"
+	case "azurerm":
+		return configString("storage_account_name") + ".blob.core.windows.net"
+	case "http", "consul":
+		return hostOf(configString("address"))
+	case "remote":
+		if hostname := configString("hostname"); hostname != "" {
+			return hostname
+		}
+		return "app.terraform.io"
+	default:
+		return remoteState.Backend
+	}
+}
+
+// hostOf returns the host of the given URL or host with an optional port.
+func hostOf(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	if parsedURL, err := url.Parse(endpoint); err == nil {
+		return parsedURL.Hostname()
+	}
+	return endpoint
+}
+
 // Generate the terraform code for configuring remote state backend.
 func (remoteState *RemoteState) GenerateTerraformCode(terragruntOptions *options.TerragruntOptions) error {
 	if remoteState.Generate == nil {
@@ -212,7 +259,7 @@ func (remoteState *RemoteState) GenerateTerraformCode(terragruntOptions *options
 		return err
 	}
 
-	configBytes, err := codegen.RemoteStateConfigToTerraformCode(remoteState.Backend, config)
+	configBytes, err := codegen.RemoteStateConfigToTerraformCode(remoteState.Backend, config, remoteState.Encryption)
 	if err != nil {
 		return err
 	}
@@ -223,7 +270,8 @@ func (remoteState *RemoteState) GenerateTerraformCode(terragruntOptions *options
 		Contents:      string(configBytes),
 		CommentPrefix: codegen.DefaultCommentPrefix,
 	}
-	return codegen.WriteToFile(terragruntOptions, terragruntOptions.WorkingDir, codegenConfig)
+	_, err = codegen.WriteToFile(terragruntOptions, terragruntOptions.WorkingDir, codegenConfig)
+	return err
 }
 
 // Custom errors
@@ -238,6 +286,9 @@ func (bucketName BucketCreationNotAllowed) Error() string {
 	return fmt.Sprintf("Creation of remote state bucket %s is not allowed", string(bucketName))
 }
 
+// PolicyViolation marks the error as Terragrunt refusing to do something the options forbid.
+func (bucketName BucketCreationNotAllowed) PolicyViolation() {}
+
 func newStateAccess() *stateAccess {
 	return &stateAccess{
 		bucketLocks: make(map[string]*sync.Mutex),
@@ -0,0 +1,178 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/kms"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// EnvNameTFEncryption is the env var OpenTofu reads the body of the `encryption` block from, which the encryption config
+// is passed in when the backend is not generated.
+const EnvNameTFEncryption = "TF_ENCRYPTION"
+
+// The minimum length of a pbkdf2 passphrase, as required by OpenTofu.
+const pbkdf2MinPassphraseLength = 16
+
+// encryptionKeyProviderRequiredKeys are the keys each known OpenTofu key provider requires. The key providers not listed
+// here are rendered as configured, without validation.
+var encryptionKeyProviderRequiredKeys = map[string][]string{
+	"pbkdf2":  {"passphrase"},
+	"aws_kms": {"kms_key_id", "key_spec"},
+	"gcp_kms": {"kms_encryption_key", "key_length"},
+	"openbao": {"key_name"},
+}
+
+// checkedEncryptionKeys caches the keys found available, so that the modules of a run-all using the same key check it
+// only once.
+var checkedEncryptionKeys = sync.Map{}
+
+// validateEncryption validates the encryption config, if any, for the key provider it configures.
+func (remoteState *RemoteState) validateEncryption() error {
+	if remoteState.Encryption == nil {
+		return nil
+	}
+
+	keyProvider, _ := remoteState.Encryption[codegen.EncryptionKeyProviderKey].(string)
+	if keyProvider == "" {
+		return errors.WithStackTrace(MissingEncryptionKey{Key: codegen.EncryptionKeyProviderKey})
+	}
+	for _, key := range encryptionKeyProviderRequiredKeys[keyProvider] {
+		if value, ok := remoteState.Encryption[key]; !ok || value == nil || value == "" {
+			return errors.WithStackTrace(MissingEncryptionKey{KeyProvider: keyProvider, Key: key})
+		}
+	}
+
+	if passphrase, ok := remoteState.Encryption["passphrase"].(string); keyProvider == "pbkdf2" && ok && len(passphrase) < pbkdf2MinPassphraseLength {
+		return errors.WithStackTrace(EncryptionPassphraseTooShort{})
+	}
+	return nil
+}
+
+// CheckEncryptionKey checks that the key of the encryption config is available, before OpenTofu fails to read or write
+// the state with it, e.g. that the KMS key exists, is enabled and can be used with the current credentials.
+func (remoteState *RemoteState) CheckEncryptionKey(ctx context.Context, terragruntOptions *options.TerragruntOptions) error {
+	if remoteState.Encryption == nil {
+		return nil
+	}
+	if terragruntOptions.TerraformImplementation == options.TerraformImpl {
+		return errors.WithStackTrace(EncryptionNotSupported{})
+	}
+
+	keyProvider, _ := remoteState.Encryption[codegen.EncryptionKeyProviderKey].(string)
+	switch keyProvider {
+	case "aws_kms":
+		if terragruntOptions.Offline {
+			terragruntOptions.Logger.Debugf("Not checking the KMS key of the state encryption in offline mode")
+			return nil
+		}
+		return checkAWSKMSEncryptionKey(ctx, remoteState.Encryption, terragruntOptions)
+	default:
+		terragruntOptions.Logger.Debugf("The availability of the keys of the %s key provider of the state encryption is not checked", keyProvider)
+		return nil
+	}
+}
+
+// EncryptionEnv returns the env vars passing the encryption config to OpenTofu, when the backend, and thus the
+// `encryption` block, is not generated.
+func (remoteState *RemoteState) EncryptionEnv() (map[string]string, error) {
+	if remoteState.Encryption == nil || remoteState.Generate != nil {
+		return nil, nil
+	}
+	code, err := codegen.EncryptionConfigToTerraformCode(remoteState.Encryption)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{EnvNameTFEncryption: string(code)}, nil
+}
+
+func checkAWSKMSEncryptionKey(ctx context.Context, encryption map[string]interface{}, terragruntOptions *options.TerragruntOptions) error {
+	keyID, _ := encryption["kms_key_id"].(string)
+	region, _ := encryption["region"].(string)
+	profile, _ := encryption["profile"].(string)
+	if keyARN, err := arn.Parse(keyID); err == nil && region == "" {
+		region = keyARN.Region
+	}
+
+	cacheKey := strings.Join([]string{keyID, region, profile}, "|")
+	if _, checked := checkedEncryptionKeys.Load(cacheKey); checked {
+		return nil
+	}
+
+	var sessionConfig *aws_helper.AwsSessionConfig
+	if region != "" || profile != "" {
+		sessionConfig = &aws_helper.AwsSessionConfig{Region: region, Profile: profile}
+	}
+	sess, err := aws_helper.CreateAwsSession(sessionConfig, terragruntOptions)
+	if err != nil {
+		return errors.WithStackTrace(EncryptionKeyUnavailable{KeyID: keyID, Err: err})
+	}
+
+	output, err := kms.New(sess).DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return errors.WithStackTrace(EncryptionKeyUnavailable{KeyID: keyID, Err: err})
+	}
+	if state := aws.StringValue(output.KeyMetadata.KeyState); state != kms.KeyStateEnabled {
+		return errors.WithStackTrace(EncryptionKeyUnavailable{KeyID: keyID, Err: fmt.Errorf("the key is %s", state)})
+	}
+
+	terragruntOptions.Logger.Debugf("The KMS key %s of the state encryption is available", keyID)
+	checkedEncryptionKeys.Store(cacheKey, true)
+	return nil
+}
+
+// encryptionString returns the encryption config without the secrets it may contain, e.g. a passphrase, for logging.
+func (remoteState *RemoteState) encryptionString() string {
+	if remoteState.Encryption == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("{key_provider = %v}", remoteState.Encryption[codegen.EncryptionKeyProviderKey])
+}
+
+// Custom error types
+
+type MissingEncryptionKey struct {
+	KeyProvider string
+	Key         string
+}
+
+func (err MissingEncryptionKey) Error() string {
+	if err.KeyProvider == "" {
+		return fmt.Sprintf("The remote_state.encryption attribute must set %s", err.Key)
+	}
+	return fmt.Sprintf("The remote_state.encryption attribute must set %s for the %s key provider", err.Key, err.KeyProvider)
+}
+
+type EncryptionPassphraseTooShort struct{}
+
+func (err EncryptionPassphraseTooShort) Error() string {
+	return fmt.Sprintf("The passphrase of the pbkdf2 key provider of the remote_state.encryption attribute must be at least %d characters long", pbkdf2MinPassphraseLength)
+}
+
+type EncryptionNotSupported struct{}
+
+func (err EncryptionNotSupported) Error() string {
+	return "The remote_state.encryption attribute configures OpenTofu state encryption, which Terraform does not support. Use OpenTofu via --terragrunt-tfpath, or remove the attribute."
+}
+
+type EncryptionKeyUnavailable struct {
+	KeyID string
+	Err   error
+}
+
+func (err EncryptionKeyUnavailable) Error() string {
+	return fmt.Sprintf("The KMS key %s of the remote_state.encryption attribute is not available: %v", err.KeyID, err.Err)
+}
+
+func (err EncryptionKeyUnavailable) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,74 @@
+package remote
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEncryption(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		encryption  map[string]interface{}
+		expectedErr error
+	}{
+		{"no encryption", nil, nil},
+		{"pbkdf2", map[string]interface{}{"key_provider": "pbkdf2", "passphrase": "correct-horse-battery-staple"}, nil},
+		{"unknown key provider", map[string]interface{}{"key_provider": "external", "command": []interface{}{"./key"}}, nil},
+		{"missing key provider", map[string]interface{}{"passphrase": "correct-horse-battery-staple"}, MissingEncryptionKey{Key: "key_provider"}},
+		{"missing passphrase", map[string]interface{}{"key_provider": "pbkdf2"}, MissingEncryptionKey{KeyProvider: "pbkdf2", Key: "passphrase"}},
+		{"short passphrase", map[string]interface{}{"key_provider": "pbkdf2", "passphrase": "hunter2"}, EncryptionPassphraseTooShort{}},
+		{"missing key spec", map[string]interface{}{"key_provider": "aws_kms", "kms_key_id": "alias/state"}, MissingEncryptionKey{KeyProvider: "aws_kms", Key: "key_spec"}},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			remoteState := RemoteState{Backend: "s3", Encryption: testCase.encryption}
+			err := remoteState.Validate()
+			if testCase.expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.Equal(t, testCase.expectedErr, errors.Unwrap(err))
+		})
+	}
+}
+
+func TestEncryptionEnv(t *testing.T) {
+	t.Parallel()
+
+	remoteState := RemoteState{Backend: "s3", Encryption: map[string]interface{}{"key_provider": "pbkdf2", "passphrase": "correct-horse-battery-staple"}}
+	env, err := remoteState.EncryptionEnv()
+	require.NoError(t, err)
+	assert.Contains(t, env[EnvNameTFEncryption], `key_provider "pbkdf2" "default"`)
+	assert.NotContains(t, remoteState.String(), "correct-horse-battery-staple")
+
+	// The encryption is configured in the generated backend file instead
+	remoteState.Generate = &RemoteStateGenerate{Path: "backend.tf", IfExists: "overwrite"}
+	env, err = remoteState.EncryptionEnv()
+	require.NoError(t, err)
+	assert.Empty(t, env)
+}
+
+func TestCheckEncryptionKeyWithTerraform(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	opts.TerraformImplementation = options.TerraformImpl
+
+	remoteState := RemoteState{Backend: "s3", Encryption: map[string]interface{}{"key_provider": "pbkdf2", "passphrase": "correct-horse-battery-staple"}}
+	err = remoteState.CheckEncryptionKey(context.Background(), opts)
+	assert.IsType(t, EncryptionNotSupported{}, errors.Unwrap(err))
+
+	opts.TerraformImplementation = options.OpenTofuImpl
+	require.NoError(t, remoteState.CheckEncryptionKey(context.Background(), opts))
+}
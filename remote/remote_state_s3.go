@@ -57,6 +57,11 @@ type ExtendedRemoteStateConfigS3 struct {
 	AccessLoggingTargetPrefix      string            `mapstructure:"accesslogging_target_prefix"`
 	BucketSSEAlgorithm             string            `mapstructure:"bucket_sse_algorithm"`
 	BucketSSEKMSKeyID              string            `mapstructure:"bucket_sse_kms_key_id"`
+	LockTableBillingMode           string            `mapstructure:"dynamodb_table_billing_mode"`
+	LockTableReadCapacity          int64             `mapstructure:"dynamodb_table_read_capacity"`
+	LockTableWriteCapacity         int64             `mapstructure:"dynamodb_table_write_capacity"`
+	LockTableTTLAttribute          string            `mapstructure:"dynamodb_table_ttl_attribute"`
+	EnableLockTablePITR            bool              `mapstructure:"enable_lock_table_point_in_time_recovery"`
 }
 
 // These are settings that can appear in the remote_state config that are ONLY used by Terragrunt and NOT forwarded
@@ -78,6 +83,11 @@ var terragruntOnlyConfigs = []string{
 	"accesslogging_target_prefix",
 	"bucket_sse_algorithm",
 	"bucket_sse_kms_key_id",
+	"dynamodb_table_billing_mode",
+	"dynamodb_table_read_capacity",
+	"dynamodb_table_write_capacity",
+	"dynamodb_table_ttl_attribute",
+	"enable_lock_table_point_in_time_recovery",
 }
 
 type RemoteStateConfigS3AssumeRole struct {
@@ -121,6 +131,23 @@ func (c *ExtendedRemoteStateConfigS3) GetAwsSessionConfig() *aws_helper.AwsSessi
 	}
 }
 
+// GetRemoteStateConfigS3 returns the part of the config that is forwarded to the Terraform s3 backend
+func (c *ExtendedRemoteStateConfigS3) GetRemoteStateConfigS3() RemoteStateConfigS3 {
+	return c.remoteStateConfigS3
+}
+
+// Returns the settings Terragrunt applies to the DynamoDB lock table when it has to create it
+func (c *ExtendedRemoteStateConfigS3) GetLockTableSettings() dynamodb.LockTableSettings {
+	return dynamodb.LockTableSettings{
+		BillingMode:         c.LockTableBillingMode,
+		ReadCapacity:        c.LockTableReadCapacity,
+		WriteCapacity:       c.LockTableWriteCapacity,
+		TTLAttribute:        c.LockTableTTLAttribute,
+		PointInTimeRecovery: c.EnableLockTablePITR,
+		Tags:                c.DynamotableTags,
+	}
+}
+
 // The DynamoDB lock table attribute used to be called "lock_table", but has since been renamed to "dynamodb_table", and
 // the old attribute name deprecated. The old attribute name has been eventually removed from Terraform starting with
 // release 0.13. To maintain backwards compatibility, we support both names.
@@ -179,7 +206,9 @@ func (s3Initializer S3Initializer) NeedsInitialization(remoteState *RemoteState,
 	// from it altogether. Display a deprecation warning when the "lock_table"
 	// attribute is being used.
 	if util.KindOf(remoteState.Config["lock_table"]) == reflect.String && remoteState.Config["lock_table"] != "" {
-		terragruntOptions.Logger.Warnf("%s\n", lockTableDeprecationMessage)
+		if err := terragruntOptions.Warn(options.WarningDeprecatedLockTable, "%s", lockTableDeprecationMessage); err != nil {
+			return false, err
+		}
 		remoteState.Config["dynamodb_table"] = remoteState.Config["lock_table"]
 		delete(remoteState.Config, "lock_table")
 	}
@@ -304,7 +333,9 @@ func (s3Initializer S3Initializer) Initialize(ctx context.Context, remoteState *
 		// Display a deprecation warning when the "lock_table" attribute is being used
 		// during initialization.
 		if s3Config.LockTable != "" {
-			terragruntOptions.Logger.Warnf("%s\n", lockTableDeprecationMessage)
+			if err := terragruntOptions.Warn(options.WarningDeprecatedLockTable, "%s", lockTableDeprecationMessage); err != nil {
+				return err
+			}
 		}
 
 		s3Client, err := CreateS3Client(s3ConfigExtended.GetAwsSessionConfig(), terragruntOptions)
@@ -328,7 +359,7 @@ func (s3Initializer S3Initializer) Initialize(ctx context.Context, remoteState *
 			}
 		}
 
-		if err := createLockTableIfNecessary(s3ConfigExtended, s3ConfigExtended.DynamotableTags, terragruntOptions); err != nil {
+		if err := createLockTableIfNecessary(s3ConfigExtended, s3ConfigExtended.GetLockTableSettings(), terragruntOptions); err != nil {
 			return errors.WithStackTrace(err)
 		}
 
@@ -416,6 +447,16 @@ func validateS3Config(extendedConfig *ExtendedRemoteStateConfigS3, terragruntOpt
 		return errors.WithStackTrace(MissingRequiredS3RemoteStateConfig("key"))
 	}
 
+	switch extendedConfig.LockTableBillingMode {
+	case "", dynamodb.DYNAMODB_PAY_PER_REQUEST_BILLING_MODE:
+	case dynamodb.DYNAMODB_PROVISIONED_BILLING_MODE:
+		if extendedConfig.LockTableReadCapacity <= 0 || extendedConfig.LockTableWriteCapacity <= 0 {
+			return errors.WithStackTrace(MissingLockTableProvisionedCapacity(config.GetLockTableName()))
+		}
+	default:
+		return errors.WithStackTrace(InvalidLockTableBillingMode(extendedConfig.LockTableBillingMode))
+	}
+
 	if !config.Encrypt {
 		msg := fmt.Sprintf("Encryption is not enabled on the S3 remote state bucket %s. Terraform state files may contain secrets, so we STRONGLY recommend enabling encryption!", config.Bucket)
 		if extendedConfig.SkipBucketSSEncryption {
@@ -692,8 +733,8 @@ func checkIfVersioningEnabled(s3Client *s3.S3, config *RemoteStateConfigS3, terr
 	// NOTE: There must be a bug in the AWS SDK since out == nil when versioning is not enabled. In the future,
 	// check the AWS SDK for updates to see if we can remove "out == nil ||".
 	if out == nil || out.Status == nil || *out.Status != s3.BucketVersioningStatusEnabled {
-		terragruntOptions.Logger.Warnf("Versioning is not enabled for the remote state S3 bucket %s. We recommend enabling versioning so that you can roll back to previous versions of your Terraform state in case of error.", config.Bucket)
-		return false, nil
+		err := terragruntOptions.Warn(options.WarningStateBucketNotVersioned, "Versioning is not enabled for the remote state S3 bucket %s. We recommend enabling versioning so that you can roll back to previous versions of your Terraform state in case of error.", config.Bucket)
+		return false, err
 	}
 
 	return true, nil
@@ -757,7 +798,9 @@ func CreateS3BucketWithVersioningSSEncryptionAndAccessLogging(s3Client *s3.S3, c
 	}
 
 	if config.SkipBucketAccessLogging {
-		terragruntOptions.Logger.Warnf("Terragrunt configuration option 'skip_bucket_accesslogging' is now deprecated. Access logging for the state bucket %s is disabled by default. To enable access logging for bucket %s, please provide property `accesslogging_bucket_name` in the terragrunt config file. For more details, please refer to the Terragrunt documentation.", config.remoteStateConfigS3.Bucket, config.remoteStateConfigS3.Bucket)
+		if err := terragruntOptions.Warn(options.WarningDeprecatedSkipBucketAccessLogs, "Terragrunt configuration option 'skip_bucket_accesslogging' is now deprecated. Access logging for the state bucket %s is disabled by default. To enable access logging for bucket %s, please provide property `accesslogging_bucket_name` in the terragrunt config file. For more details, please refer to the Terragrunt documentation.", config.remoteStateConfigS3.Bucket, config.remoteStateConfigS3.Bucket); err != nil {
+			return err
+		}
 	}
 
 	if config.AccessLoggingBucketName != "" {
@@ -1425,7 +1468,7 @@ func checkBucketAccess(s3Client *s3.S3, bucket *string, key *string) error {
 }
 
 // Create a table for locks in DynamoDB if the user has configured a lock table and the table doesn't already exist
-func createLockTableIfNecessary(extendedS3Config *ExtendedRemoteStateConfigS3, tags map[string]string, terragruntOptions *options.TerragruntOptions) error {
+func createLockTableIfNecessary(extendedS3Config *ExtendedRemoteStateConfigS3, settings dynamodb.LockTableSettings, terragruntOptions *options.TerragruntOptions) error {
 
 	if extendedS3Config.remoteStateConfigS3.GetLockTableName() == "" {
 		return nil
@@ -1436,7 +1479,7 @@ func createLockTableIfNecessary(extendedS3Config *ExtendedRemoteStateConfigS3, t
 		return err
 	}
 
-	return dynamodb.CreateLockTableIfNecessary(extendedS3Config.remoteStateConfigS3.GetLockTableName(), tags, dynamodbClient, terragruntOptions)
+	return dynamodb.CreateLockTableIfNecessary(extendedS3Config.remoteStateConfigS3.GetLockTableName(), settings, dynamodbClient, terragruntOptions)
 }
 
 // Update a table for locks in DynamoDB if the user has configured a lock table and the table's server-side encryption isn't turned on
@@ -1481,6 +1524,18 @@ func (target MultipleTagsDeclarations) Error() string {
 	return fmt.Sprintf("Tags for %s got declared multiple times. Please do only declare in one block.", string(target))
 }
 
+type InvalidLockTableBillingMode string
+
+func (mode InvalidLockTableBillingMode) Error() string {
+	return fmt.Sprintf("Invalid DynamoDB lock table billing mode %s. Supported values are %s and %s.", string(mode), dynamodb.DYNAMODB_PAY_PER_REQUEST_BILLING_MODE, dynamodb.DYNAMODB_PROVISIONED_BILLING_MODE)
+}
+
+type MissingLockTableProvisionedCapacity string
+
+func (tableName MissingLockTableProvisionedCapacity) Error() string {
+	return fmt.Sprintf("DynamoDB lock table %s uses the %s billing mode, so dynamodb_table_read_capacity and dynamodb_table_write_capacity must be set to positive values.", string(tableName), dynamodb.DYNAMODB_PROVISIONED_BILLING_MODE)
+}
+
 type MaxRetriesWaitingForS3BucketExceeded string
 
 func (err MaxRetriesWaitingForS3BucketExceeded) Error() string {
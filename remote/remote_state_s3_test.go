@@ -319,19 +319,24 @@ func TestGetTerraformInitArgs(t *testing.T) {
 		{
 			"empty-no-values-all-terragrunt-keys-filtered",
 			map[string]interface{}{
-				"s3_bucket_tags":                     map[string]string{},
-				"dynamodb_table_tags":                map[string]string{},
-				"accesslogging_bucket_tags":          map[string]string{},
-				"skip_bucket_versioning":             true,
-				"skip_bucket_ssencryption":           false,
-				"skip_bucket_root_access":            false,
-				"skip_bucket_enforced_tls":           false,
-				"skip_bucket_public_access_blocking": false,
-				"disable_bucket_update":              true,
-				"enable_lock_table_ssencryption":     true,
-				"disable_aws_client_checksums":       false,
-				"accesslogging_bucket_name":          "test",
-				"accesslogging_target_prefix":        "test",
+				"s3_bucket_tags":                           map[string]string{},
+				"dynamodb_table_tags":                      map[string]string{},
+				"accesslogging_bucket_tags":                map[string]string{},
+				"skip_bucket_versioning":                   true,
+				"skip_bucket_ssencryption":                 false,
+				"skip_bucket_root_access":                  false,
+				"skip_bucket_enforced_tls":                 false,
+				"skip_bucket_public_access_blocking":       false,
+				"disable_bucket_update":                    true,
+				"enable_lock_table_ssencryption":           true,
+				"disable_aws_client_checksums":             false,
+				"accesslogging_bucket_name":                "test",
+				"accesslogging_target_prefix":              "test",
+				"dynamodb_table_billing_mode":              "PROVISIONED",
+				"dynamodb_table_read_capacity":             5,
+				"dynamodb_table_write_capacity":            5,
+				"dynamodb_table_ttl_attribute":             "ExpiresAt",
+				"enable_lock_table_point_in_time_recovery": true,
 			},
 			map[string]interface{}{},
 			true,
@@ -511,6 +516,31 @@ func TestValidateS3Config(t *testing.T) {
 			},
 			expectedOutput: "level=debug msg=\"Encryption is not enabled",
 		},
+		{
+			name: "invalid-lock-table-billing-mode",
+			extendedConfig: &ExtendedRemoteStateConfigS3{
+				LockTableBillingMode: "ON_DEMAND",
+				remoteStateConfigS3: RemoteStateConfigS3{
+					Region: "us-west-2",
+					Bucket: "state-bucket",
+					Key:    "terraform.tfstate",
+				},
+			},
+			expectedErr: InvalidLockTableBillingMode("ON_DEMAND"),
+		},
+		{
+			name: "provisioned-lock-table-without-capacity",
+			extendedConfig: &ExtendedRemoteStateConfigS3{
+				LockTableBillingMode: "PROVISIONED",
+				remoteStateConfigS3: RemoteStateConfigS3{
+					Region:        "us-west-2",
+					Bucket:        "state-bucket",
+					Key:           "terraform.tfstate",
+					DynamoDBTable: "lock-table",
+				},
+			},
+			expectedErr: MissingLockTableProvisionedCapacity("lock-table"),
+		},
 	}
 	for _, testCase := range testCases {
 		testCase := testCase
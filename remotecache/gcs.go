@@ -0,0 +1,47 @@
+package remotecache
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"cloud.google.com/go/storage"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+// gcsBackend stores the entries as objects of a GCS bucket, under the prefix of the URL.
+type gcsBackend struct {
+	bucket *storage.BucketHandle
+	prefix string
+}
+
+func newGCSBackend(bucket string, prefix string) (*gcsBackend, error) {
+	// The client is authenticated as for the gcs remote state, with the credentials of the environment
+	client, err := remote.CreateGCSClient(remote.RemoteStateConfigGCS{})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return &gcsBackend{bucket: client.Bucket(bucket), prefix: prefix}, nil
+}
+
+func (backend *gcsBackend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := backend.bucket.Object(path.Join(backend.prefix, key)).NewReader(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, errors.WithStackTrace(EntryNotFound(key))
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return reader, nil
+}
+
+func (backend *gcsBackend) Put(ctx context.Context, key string, content io.ReadSeeker) error {
+	writer := backend.bucket.Object(path.Join(backend.prefix, key)).NewWriter(ctx)
+	if _, err := io.Copy(writer, content); err != nil {
+		_ = writer.Close()
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(writer.Close())
+}
@@ -0,0 +1,90 @@
+package remotecache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// httpBackend stores the entries on an HTTP server, which serves them with GET and stores them with PUT, under the
+// path of the URL. The credentials of the URL, if any, are sent with basic auth, and the token with bearer auth.
+type httpBackend struct {
+	client  *http.Client
+	baseURL *url.URL
+	token   string
+}
+
+func newHTTPBackend(baseURL *url.URL, token string) *httpBackend {
+	return &httpBackend{client: http.DefaultClient, baseURL: baseURL, token: token}
+}
+
+func (backend *httpBackend) entryURL(key string) string {
+	entryURL := *backend.baseURL
+	entryURL.Path = strings.TrimSuffix(entryURL.Path, "/") + "/" + key
+	entryURL.RawPath = ""
+	return entryURL.String()
+}
+
+func (backend *httpBackend) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, backend.entryURL(key), body)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if backend.token != "" {
+		req.Header.Set("Authorization", "Bearer "+backend.token)
+	}
+	return req, nil
+}
+
+func (backend *httpBackend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := backend.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := backend.client.Do(req)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close() //nolint:errcheck
+		return nil, errors.WithStackTrace(EntryNotFound(key))
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close() //nolint:errcheck
+		return nil, errors.WithStackTrace(UnexpectedStatus{Method: req.Method, URL: req.URL.Redacted(), Status: resp.Status})
+	}
+	return resp.Body, nil
+}
+
+func (backend *httpBackend) Put(ctx context.Context, key string, content io.ReadSeeker) error {
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	req, err := backend.newRequest(ctx, http.MethodPut, key, io.NopCloser(content))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := backend.client.Do(req)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.WithStackTrace(UnexpectedStatus{Method: req.Method, URL: req.URL.Redacted(), Status: resp.Status})
+	}
+	return nil
+}
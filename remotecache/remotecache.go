@@ -0,0 +1,278 @@
+// Package remotecache shares the caches of Terragrunt between machines through a remote store: the terraform sources
+// downloaded, the provider packages of the provider cache server and the outputs of the dependencies. The ephemeral CI
+// runners, whose local caches are always empty, then start from the entries stored by the previous runs: S3, GCS or any
+// HTTP server accepting GET and PUT, e.g. a build cache server.
+package remotecache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The schemes of the URLs of the backends.
+const (
+	SchemeS3    = "s3"
+	SchemeGCS   = "gs"
+	SchemeHTTP  = "http"
+	SchemeHTTPS = "https"
+)
+
+// The kinds of the entries, the first segment of their keys.
+const (
+	KindSource   = "sources"
+	KindProvider = "providers"
+	KindOutputs  = "outputs"
+)
+
+var schemes = []string{SchemeS3, SchemeGCS, SchemeHTTP, SchemeHTTPS}
+
+// Backend is a remote store holding the entries of the cache by key.
+type Backend interface {
+	// Get returns the content of the entry, or an EntryNotFound error if the entry does not exist.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Put writes the content of the entry, replacing the previous content if any.
+	Put(ctx context.Context, key string, content io.ReadSeeker) error
+}
+
+var (
+	backends   = map[string]Backend{}
+	backendsMu sync.Mutex
+)
+
+// New returns the backend of the given URL: s3://bucket/prefix, gs://bucket/prefix or http(s)://host/prefix. The
+// region of the S3 bucket is given by the region query parameter, the default region of the environment if not set,
+// and the token, if not empty, is sent as a bearer token to the HTTP server.
+func New(cacheURL string, token string, opts *options.TerragruntOptions) (Backend, error) {
+	parsedURL, err := url.Parse(cacheURL)
+	if err != nil {
+		return nil, errors.WithStackTrace(InvalidCacheURL{URL: cacheURL, Err: err})
+	}
+
+	prefix := strings.Trim(parsedURL.Path, "/")
+	switch parsedURL.Scheme {
+	case SchemeS3:
+		if parsedURL.Host == "" {
+			return nil, errors.WithStackTrace(InvalidCacheURL{URL: cacheURL, Err: MissingBucket{}})
+		}
+		return newS3Backend(parsedURL.Host, prefix, parsedURL.Query().Get("region"), opts)
+	case SchemeGCS:
+		if parsedURL.Host == "" {
+			return nil, errors.WithStackTrace(InvalidCacheURL{URL: cacheURL, Err: MissingBucket{}})
+		}
+		return newGCSBackend(parsedURL.Host, prefix)
+	case SchemeHTTP, SchemeHTTPS:
+		return newHTTPBackend(parsedURL, token), nil
+	}
+	return nil, errors.WithStackTrace(InvalidCacheURL{URL: cacheURL, Err: UnsupportedScheme(parsedURL.Scheme)})
+}
+
+// FromOptions returns the backend of the URL given with --terragrunt-remote-cache, created once per process, or nil if
+// the remote cache is not enabled, or offline mode does not allow its host.
+func FromOptions(opts *options.TerragruntOptions) (Backend, error) {
+	if opts.RemoteCacheURL == "" {
+		return nil, nil
+	}
+	if err := opts.CheckOfflineFetch("the remote cache", options.FetchHost(opts.RemoteCacheURL)); err != nil {
+		opts.Logger.Debugf("Not using the remote cache: %v", err)
+		return nil, nil
+	}
+
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	id := opts.RemoteCacheURL + "\x00" + opts.RemoteCacheToken
+	if backend, ok := backends[id]; ok {
+		return backend, nil
+	}
+	backend, err := New(opts.RemoteCacheURL, opts.RemoteCacheToken, opts)
+	if err != nil {
+		return nil, err
+	}
+	backends[id] = backend
+	return backend, nil
+}
+
+// Key returns the key of the entry of the given kind identified by the given id, e.g. the URL of a source. The id is
+// hashed, so that any string is a valid key for any backend.
+func Key(kind string, id string) string {
+	return fmt.Sprintf("%s/%x", kind, sha256.Sum256([]byte(id)))
+}
+
+// IsEntryNotFound returns true if the given error is returned by a backend for an entry which does not exist.
+func IsEntryNotFound(err error) bool {
+	var notFound EntryNotFound
+	return goerrors.As(err, &notFound)
+}
+
+// FetchBytes returns the content of the entry of the given key, and false if the entry does not exist.
+func FetchBytes(ctx context.Context, backend Backend, key string) ([]byte, bool, error) {
+	reader, err := backend.Get(ctx, key)
+	if IsEntryNotFound(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, false, errors.WithStackTrace(err)
+	}
+	return content, true, nil
+}
+
+// StoreBytes writes the given content to the entry of the given key.
+func StoreBytes(ctx context.Context, backend Backend, key string, content []byte) error {
+	return backend.Put(ctx, key, bytes.NewReader(content))
+}
+
+// FetchFile writes the content of the entry of the given key to the given file, and returns false, without creating
+// the file, if the entry does not exist. The file is written to a temp file first, so that it is never left partial.
+func FetchFile(ctx context.Context, backend Backend, key string, path string) (bool, error) {
+	reader, err := backend.Get(ctx, key)
+	if IsEntryNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+	defer os.Remove(tempFile.Name()) //nolint:errcheck
+
+	_, err = io.Copy(tempFile, reader)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+	return true, errors.WithStackTrace(os.Rename(tempFile.Name(), path))
+}
+
+// StoreFile writes the content of the given file to the entry of the given key.
+func StoreFile(ctx context.Context, backend Backend, key string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer file.Close() //nolint:errcheck
+
+	return backend.Put(ctx, key, file)
+}
+
+// FetchDir extracts the gzipped tar of the entry of the given key into the given dir, and returns false if the entry
+// does not exist.
+func FetchDir(ctx context.Context, backend Backend, key string, dir string) (bool, error) {
+	reader, err := backend.Get(ctx, key)
+	if IsEntryNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer reader.Close() //nolint:errcheck
+
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+	if err := util.ExtractTar(gzipReader, dir); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// StoreDir writes the files of the given dir as a gzipped tar to the entry of the given key, skipping the dirs with
+// the given names. The tar is written to a temp file first, as the backends need the size of the content.
+func StoreDir(ctx context.Context, backend Backend, key string, dir string, skipDirs []string) error {
+	tempFile, err := os.CreateTemp("", "terragrunt-remote-cache-*.tar.gz")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer os.Remove(tempFile.Name()) //nolint:errcheck
+	defer tempFile.Close()           //nolint:errcheck
+
+	gzipWriter := gzip.NewWriter(tempFile)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := util.WriteDirToTar(tarWriter, dir, skipDirs); err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if _, err := tempFile.Seek(0, io.SeekStart); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return backend.Put(ctx, key, tempFile)
+}
+
+// Custom error types
+
+type EntryNotFound string
+
+func (key EntryNotFound) Error() string {
+	return fmt.Sprintf("The remote cache has no entry %s", string(key))
+}
+
+type InvalidCacheURL struct {
+	URL string
+	Err error
+}
+
+func (err InvalidCacheURL) Error() string {
+	return fmt.Sprintf("Invalid URL %s of the remote cache: %v", err.URL, err.Err)
+}
+
+func (err InvalidCacheURL) Unwrap() error {
+	return err.Err
+}
+
+type UnsupportedScheme string
+
+func (scheme UnsupportedScheme) Error() string {
+	return fmt.Sprintf("unsupported scheme %q, expected one of %s", string(scheme), strings.Join(schemes, ", "))
+}
+
+type MissingBucket struct{}
+
+func (err MissingBucket) Error() string {
+	return "the bucket is missing"
+}
+
+type UnexpectedStatus struct {
+	Method string
+	URL    string
+	Status string
+}
+
+func (err UnexpectedStatus) Error() string {
+	return fmt.Sprintf("%s %s to the remote cache failed with the status %s", err.Method, err.URL, err.Status)
+}
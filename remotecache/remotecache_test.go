@@ -0,0 +1,136 @@
+package remotecache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer returns an HTTP cache server storing the entries in memory, which requires the given token if not empty.
+func newTestServer(t *testing.T, token string) (*httptest.Server, map[string][]byte) {
+	t.Helper()
+
+	entries := map[string][]byte{}
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			content, ok := entries[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		case http.MethodPut:
+			content, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			entries[r.URL.Path] = content
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, entries
+}
+
+func TestHTTPBackend(t *testing.T) {
+	t.Parallel()
+
+	server, entries := newTestServer(t, "secret")
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	backend, err := New(server.URL+"/cache/", "secret", opts)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	key := Key(KindOutputs, "vpc")
+	_, hit, err := FetchBytes(ctx, backend, key)
+	require.NoError(t, err)
+	assert.False(t, hit)
+
+	require.NoError(t, StoreBytes(ctx, backend, key, []byte(`{"vpc_id":"vpc-123"}`)))
+	assert.Contains(t, entries, "/cache/"+key)
+
+	content, hit, err := FetchBytes(ctx, backend, key)
+	require.NoError(t, err)
+	assert.True(t, hit)
+	assert.Equal(t, `{"vpc_id":"vpc-123"}`, string(content))
+
+	unauthorized, err := New(server.URL+"/cache", "wrong", opts)
+	require.NoError(t, err)
+	_, _, err = FetchBytes(ctx, unauthorized, key)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401 Unauthorized")
+}
+
+func TestStoreAndFetchDir(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newTestServer(t, "")
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	backend, err := New(server.URL, "", opts)
+	require.NoError(t, err)
+
+	srcDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "modules", "vpc"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "modules", "vpc", "main.tf"), []byte(`resource "null_resource" "vpc" {}`), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, ".terraform"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, ".terraform", "plugin"), []byte("plugin"), 0644))
+
+	ctx := context.Background()
+	key := Key(KindSource, "git::https://github.com/acme/modules.git?ref=v1.0.0")
+	require.NoError(t, StoreDir(ctx, backend, key, srcDir, []string{".terraform"}))
+
+	dstDir := filepath.Join(t.TempDir(), "download")
+	hit, err := FetchDir(ctx, backend, key, dstDir)
+	require.NoError(t, err)
+	assert.True(t, hit)
+
+	content, err := os.ReadFile(filepath.Join(dstDir, "modules", "vpc", "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, `resource "null_resource" "vpc" {}`, string(content))
+	assert.NoFileExists(t, filepath.Join(dstDir, ".terraform", "plugin"))
+
+	hit, err = FetchFile(ctx, backend, Key(KindProvider, "aws"), filepath.Join(dstDir, "aws.zip"))
+	require.NoError(t, err)
+	assert.False(t, hit)
+	assert.NoFileExists(t, filepath.Join(dstDir, "aws.zip"))
+}
+
+func TestNewInvalidURL(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		url      string
+		expected string
+	}{
+		{"ftp://cache.example.com", `unsupported scheme "ftp"`},
+		{"s3:///prefix", "the bucket is missing"},
+		{"gs://", "the bucket is missing"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := New(testCase.url, "", opts)
+		assert.ErrorContains(t, err, testCase.expected, testCase.url)
+	}
+}
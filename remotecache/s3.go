@@ -0,0 +1,58 @@
+package remotecache
+
+import (
+	"context"
+	"io"
+	"path"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+// s3Backend stores the entries as objects of an S3 bucket, under the prefix of the URL.
+type s3Backend struct {
+	client *s3.S3
+	bucket string
+	prefix string
+}
+
+func newS3Backend(bucket string, prefix string, region string, opts *options.TerragruntOptions) (*s3Backend, error) {
+	var sessionConfig *aws_helper.AwsSessionConfig
+	if region != "" {
+		sessionConfig = &aws_helper.AwsSessionConfig{Region: region}
+	}
+	client, err := remote.CreateS3Client(sessionConfig, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Backend{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (backend *s3Backend) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	output, err := backend.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(backend.bucket),
+		Key:    aws.String(path.Join(backend.prefix, key)),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == s3.ErrCodeNoSuchKey {
+		return nil, errors.WithStackTrace(EntryNotFound(key))
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return output.Body, nil
+}
+
+func (backend *s3Backend) Put(ctx context.Context, key string, content io.ReadSeeker) error {
+	_, err := backend.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(backend.bucket),
+		Key:    aws.String(path.Join(backend.prefix, key)),
+		Body:   content,
+	})
+	return errors.WithStackTrace(err)
+}
@@ -6,7 +6,11 @@ import (
 	"github.com/gruntwork-io/terragrunt/options"
 )
 
-const TerraformCommandContextKey ctxKey = iota
+const (
+	TerraformCommandContextKey ctxKey = iota
+	NoNetworkContextKey
+	TerraformExecutorContextKey
+)
 
 type ctxKey byte
 
@@ -25,3 +29,23 @@ func TerraformCommandHookFromContext(ctx context.Context) RunShellCommandFunc {
 
 	return nil
 }
+
+// ContextWithoutNetwork makes the commands run with the returned context run without network access.
+func ContextWithoutNetwork(ctx context.Context) context.Context {
+	return context.WithValue(ctx, NoNetworkContextKey, true)
+}
+
+func NoNetworkFromContext(ctx context.Context) bool {
+	noNetwork, _ := ctx.Value(NoNetworkContextKey).(bool)
+	return noNetwork
+}
+
+// ContextWithTerraformExecutor makes the terraform commands run with the returned context run by the given executor.
+func ContextWithTerraformExecutor(ctx context.Context, executor TerraformExecutor) context.Context {
+	return context.WithValue(ctx, TerraformExecutorContextKey, executor)
+}
+
+func TerraformExecutorFromContext(ctx context.Context) TerraformExecutor {
+	executor, _ := ctx.Value(TerraformExecutorContextKey).(TerraformExecutor)
+	return executor
+}
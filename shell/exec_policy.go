@@ -0,0 +1,69 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// RunConfigCommandWithOutput runs a command defined in the config, by a hook or run_cmd, if the execution policies allow
+// it. Unlike the commands run by Terragrunt itself, such as terraform, these commands come from the config and are
+// subject to the policies.
+func RunConfigCommandWithOutput(
+	ctx context.Context,
+	terragruntOptions *options.TerragruntOptions,
+	workingDir string,
+	suppressStdout bool,
+	command string,
+	args ...string,
+) (*CmdOutput, error) {
+	if err := CheckExecPolicies(terragruntOptions, command); err != nil {
+		return nil, err
+	}
+
+	for _, policy := range terragruntOptions.ExecPolicies {
+		if policy.NoNetwork {
+			ctx = ContextWithoutNetwork(ctx)
+		}
+	}
+
+	return RunShellCommandWithOutput(ctx, terragruntOptions, workingDir, suppressStdout, false, command, args...)
+}
+
+// CheckExecPolicies returns an error if any of the execution policies doesn't allow executing the given command.
+func CheckExecPolicies(terragruntOptions *options.TerragruntOptions, command string) error {
+	for _, policy := range terragruntOptions.ExecPolicies {
+		if !policy.Allows(command) {
+			return errors.WithStackTrace(CommandNotAllowedError{Command: command, Source: policy.Source})
+		}
+	}
+	return nil
+}
+
+// Custom error types
+
+type CommandNotAllowedError struct {
+	Command string
+	Source  string
+}
+
+func (err CommandNotAllowedError) Error() string {
+	return fmt.Sprintf("The command %s is not allowed by the execution policy of %s", err.Command, err.Source)
+}
+
+// PolicyViolation marks the error as Terragrunt refusing to do something the policy forbids.
+func (err CommandNotAllowedError) PolicyViolation() {}
+
+type NoNetworkNotSupportedError struct {
+	Command string
+}
+
+func (err NoNetworkNotSupportedError) Error() string {
+	return fmt.Sprintf("Cannot run the command %s without network access, as required by the execution policy: only supported on Linux", err.Command)
+}
+
+// PolicyViolation marks the error as Terragrunt refusing to do something the policy forbids.
+func (err NoNetworkNotSupportedError) PolicyViolation() {}
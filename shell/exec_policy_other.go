@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package shell
+
+import (
+	"os/exec"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// withoutNetwork fails, as running a command without network access relies on Linux network namespaces.
+func withoutNetwork(cmd *exec.Cmd) error {
+	return errors.WithStackTrace(NoNetworkNotSupportedError{Command: cmd.Path})
+}
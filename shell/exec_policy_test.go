@@ -0,0 +1,70 @@
+package shell
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunConfigCommandWithOutputExecPolicies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		policies       []options.ExecPolicy
+		command        string
+		expectedDenied bool
+	}{
+		{nil, "echo", false},
+		{[]options.ExecPolicy{{Allow: []string{"echo"}}}, "echo", false},
+		{[]options.ExecPolicy{{Allow: []string{"ec*"}}}, "/bin/echo", true},
+		{[]options.ExecPolicy{{Allow: []string{"/bin/*"}}}, "/bin/echo", false},
+		{[]options.ExecPolicy{{Allow: []string{"/usr/bin/*"}}}, "/bin/echo", true},
+		{[]options.ExecPolicy{{Allow: []string{"tflint"}}}, "echo", true},
+		{[]options.ExecPolicy{{Deny: []string{"echo"}}}, "echo", true},
+		{[]options.ExecPolicy{{Deny: []string{"echo"}}}, "/bin/echo", true},
+		{[]options.ExecPolicy{{Allow: []string{"*"}, Deny: []string{"echo"}}}, "echo", true},
+		{[]options.ExecPolicy{{Allow: []string{"echo"}}, {Allow: []string{"tflint"}}}, "echo", true},
+	}
+
+	for _, testCase := range testCases {
+		terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+		require.NoError(t, err)
+		terragruntOptions.ExecPolicies = testCase.policies
+
+		out, err := RunConfigCommandWithOutput(context.Background(), terragruntOptions, "", true, testCase.command, "hello")
+		if testCase.expectedDenied {
+			assert.IsType(t, CommandNotAllowedError{}, errors.Unwrap(err), "policies %v, command %s", testCase.policies, testCase.command)
+			continue
+		}
+		require.NoError(t, err, "policies %v, command %s", testCase.policies, testCase.command)
+		assert.Equal(t, "hello\n", out.Stdout)
+	}
+}
+
+func TestRunConfigCommandWithOutputExecPoliciesDenyRepoLocalCommand(t *testing.T) {
+	t.Parallel()
+
+	// A binary committed in the branch with the name of an allowed command
+	workingDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "terraform"), []byte("#!/bin/sh\necho hello\n"), 0755))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+	terragruntOptions.ExecPolicies = []options.ExecPolicy{{Allow: []string{"terraform"}}}
+
+	for _, command := range []string{"./terraform", filepath.Join(workingDir, "terraform")} {
+		_, err = RunConfigCommandWithOutput(context.Background(), terragruntOptions, workingDir, true, command)
+		assert.IsType(t, CommandNotAllowedError{}, errors.Unwrap(err), "command %s", command)
+	}
+
+	terragruntOptions.ExecPolicies = []options.ExecPolicy{{Allow: []string{"./terraform"}}}
+	out, err := RunConfigCommandWithOutput(context.Background(), terragruntOptions, workingDir, true, "./terraform")
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", out.Stdout)
+}
@@ -0,0 +1,161 @@
+package shell
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The file, in the remote working dir, with the env vars terraform runs with.
+const executorEnvFile = ".terragrunt-executor-env"
+
+// The env vars, by prefix, that are passed on to terraform where an executor runs it. Other env vars, such as PATH or
+// the paths of local caches, only make sense on this machine and must be forwarded explicitly.
+var executorForwardedEnvPrefixes = []string{"TF_VAR_", "TF_CLI_ARGS", "TF_INPUT", "TF_IN_AUTOMATION", "TF_LOG"}
+
+// The dirs of the working dir that are not copied by the executors: the providers installed by `terraform init` are
+// specific to the platform they run on.
+var executorSkipDirs = []string{".terraform"}
+
+// TerraformExecutor runs the terraform commands of a module somewhere else than on this machine, e.g. on a remote host.
+// The executor is responsible for making the working dir of the module available where the commands run, and for
+// bringing back the files the commands write, such as the lock file or a plan file. Executors that hold resources
+// across the commands of a module also implement io.Closer, which is called once the module is done.
+type TerraformExecutor interface {
+	fmt.Stringer
+
+	// RunTerraform runs terraform with the given args in the working dir of the given options, streaming its output to the
+	// given writers. The returned error carries the exit code of terraform, if it ran.
+	RunTerraform(ctx context.Context, terragruntOptions *options.TerragruntOptions, args []string, stdout io.Writer, stderr io.Writer) error
+}
+
+// writeWorkingDirArchive writes the working dir, and the env file terraform runs with, as a gzipped tar to the given
+// writer.
+func writeWorkingDirArchive(writer io.Writer, terragruntOptions *options.TerragruntOptions, forwardEnv []string) error {
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	if err := util.WriteDirToTar(tarWriter, terragruntOptions.WorkingDir, executorSkipDirs); err != nil {
+		return err
+	}
+
+	envFile := executorEnv(terragruntOptions.Env, forwardEnv)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: executorEnvFile, Mode: 0600, Size: int64(len(envFile)), Typeflag: tar.TypeReg}); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := tarWriter.Write([]byte(envFile)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(gzipWriter.Close())
+}
+
+func extractGzipTar(reader io.Reader, dir string) error {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer gzipReader.Close() //nolint:errcheck
+
+	return util.ExtractTar(gzipReader, dir)
+}
+
+// executorEnv returns the env vars terraform runs with where the executor runs it, as a shell script sourced before
+// running it.
+func executorEnv(env map[string]string, forwardEnv []string) string {
+	var names []string
+	for name := range env {
+		forward := util.ListContainsElement(forwardEnv, name)
+		for _, prefix := range executorForwardedEnvPrefixes {
+			forward = forward || strings.HasPrefix(name, prefix)
+		}
+		if forward {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var content strings.Builder
+	for _, name := range names {
+		content.WriteString(fmt.Sprintf("%s=%s\n", name, quoteShellArg(env[name])))
+	}
+	return content.String()
+}
+
+// Shell commands run where the executor runs terraform, to extract the working dir into the remote dir, to run
+// terraform in it, and to archive it back.
+
+func remoteExtractCommand(remoteDir string) string {
+	// The files removed from the working dir since the previous command are removed from the remote dir too, but not the
+	// providers installed by `terraform init`, which are not copied
+	var keeps []string
+	for _, dir := range executorSkipDirs {
+		keeps = append(keeps, "! -name "+quoteShellArg(dir))
+	}
+	dir := quoteRemoteDir(remoteDir)
+	return fmt.Sprintf("mkdir -p %s && find %s -mindepth 1 -maxdepth 1 %s -exec rm -rf {} + && tar -xzf - -C %s", dir, dir, strings.Join(keeps, " "), dir)
+}
+
+func remoteTerraformCommand(remoteDir string, workingDir string, terraformPath string, args []string) string {
+	// The args may refer to files of the working dir, e.g. -var-file, which are found relative to the remote dir terraform
+	// runs in
+	remoteArgs := []string{quoteShellArg(terraformPath)}
+	for _, arg := range args {
+		remoteArgs = append(remoteArgs, quoteShellArg(strings.ReplaceAll(arg, workingDir, ".")))
+	}
+	return fmt.Sprintf("cd %s && set -a && . ./%s && set +a && rm -f ./%s && %s", quoteRemoteDir(remoteDir), executorEnvFile, executorEnvFile, strings.Join(remoteArgs, " "))
+}
+
+func remoteArchiveCommand(remoteDir string) string {
+	var excludes []string
+	for _, dir := range executorSkipDirs {
+		excludes = append(excludes, "--exclude="+quoteShellArg(dir))
+	}
+	return fmt.Sprintf("tar -czf - -C %s %s .", quoteRemoteDir(remoteDir), strings.Join(excludes, " "))
+}
+
+// quoteRemoteDir quotes the given remote dir for a POSIX shell, leaving the leading `~` of a dir in the home dir to the
+// shell to expand, as the quotes would prevent it.
+func quoteRemoteDir(remoteDir string) string {
+	if remoteDir == "~" {
+		return `"$HOME"`
+	}
+	if rest, ok := strings.CutPrefix(remoteDir, "~/"); ok {
+		return `"$HOME"/` + quoteShellArg(rest)
+	}
+	return quoteShellArg(remoteDir)
+}
+
+// quoteShellArg quotes the given arg for a POSIX shell.
+func quoteShellArg(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// Custom error types
+
+type InvalidExecutorRemoteDir string
+
+func (dir InvalidExecutorRemoteDir) Error() string {
+	return fmt.Sprintf("The remote dir %q can't hold the working dir of the module, as its content is removed before every command. Use a dir of its own, e.g. ~/terragrunt/module.", string(dir))
+}
+
+type ExecutorCopyFailed struct {
+	Dir      string
+	Executor string
+	Err      error
+}
+
+func (err ExecutorCopyFailed) Error() string {
+	return fmt.Sprintf("Failed to copy %s with %s: %v", err.Dir, err.Executor, err.Err)
+}
@@ -0,0 +1,366 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	kubectlCommand = "kubectl"
+
+	// The dir, in the container of the Job, where the working dir of the module is copied.
+	kubernetesWorkspaceDir = "/workspace"
+
+	// The file, in the workspace, the container waits for to exit with the exit code written in it.
+	kubernetesExitCodeFile = ".terragrunt-exit-code"
+
+	kubernetesContainerName = "terraform"
+
+	// How long to wait for the pod of the Job to start, e.g. while the image is pulled.
+	kubernetesPodStartTimeout = 10 * time.Minute
+	kubernetesPodPollInterval = 2 * time.Second
+
+	// How long finishing or deleting the Job may take once the module is done, even if the run was interrupted.
+	kubernetesCleanupTimeout = time.Minute
+
+	// How long the Job may run by default. The Job is stopped after that even if Terragrunt never finished it.
+	DefaultKubernetesActiveDeadline = 4 * time.Hour
+)
+
+var invalidKubernetesNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// KubernetesExecutor runs terraform in the pod of a Kubernetes Job, with the system kubectl, so that the kubeconfig of
+// the user is used. A Job is started for every module run: the working dir of the module is copied into the pod before
+// every command, the commands are run in the pod with the output streamed back, and the files terraform wrote are
+// copied back after every command. Once the module is done, the container exits with the exit code of the last command,
+// so that the Job status reflects the run, or the Job is deleted if its pod never ran or can no longer be reached.
+type KubernetesExecutor struct {
+	Image          string
+	Namespace      string
+	Context        string
+	ServiceAccount string
+	// The resource requests and limits of the container, e.g. {"cpu" = "1", "memory" = "2Gi"}.
+	Requests map[string]string
+	Limits   map[string]string
+	Labels   map[string]string
+	// How long the Job may run, DefaultKubernetesActiveDeadline if zero.
+	ActiveDeadline time.Duration
+	// How long the finished Job is kept before Kubernetes deletes it, kept until deleted by hand if zero.
+	TTLAfterFinished time.Duration
+	TerraformPath    string
+	// The names of the env vars to pass on to terraform, in addition to the ones terraform reads its inputs from.
+	ForwardEnv []string
+
+	// The kubectl client, kubectl from the PATH if empty.
+	kubectlCommand string
+
+	job               string
+	pod               string
+	exitCode          int
+	terragruntOptions *options.TerragruntOptions
+}
+
+func (executor *KubernetesExecutor) String() string {
+	if executor.job == "" {
+		return "kubernetes job with image " + executor.Image
+	}
+	return "kubernetes job " + executor.job
+}
+
+func (executor *KubernetesExecutor) RunTerraform(ctx context.Context, terragruntOptions *options.TerragruntOptions, args []string, stdout io.Writer, stderr io.Writer) error {
+	if executor.pod == "" {
+		if err := executor.startJob(ctx, terragruntOptions); err != nil {
+			return err
+		}
+	}
+
+	terraformPath := executor.TerraformPath
+	if terraformPath == "" {
+		terraformPath = terragruntOptions.TerraformPath
+	}
+
+	terragruntOptions.Logger.Debugf("Copying %s to the pod %s", terragruntOptions.WorkingDir, executor.pod)
+	var archive bytes.Buffer
+	if err := writeWorkingDirArchive(&archive, terragruntOptions, executor.ForwardEnv); err != nil {
+		return err
+	}
+	if err := executor.exec(ctx, terragruntOptions, remoteExtractCommand(kubernetesWorkspaceDir), &archive, io.Discard, stderr); err != nil {
+		return executor.podError(ctx, terragruntOptions, ExecutorCopyFailed{Dir: terragruntOptions.WorkingDir, Executor: executor.String(), Err: err})
+	}
+
+	terragruntOptions.Logger.Debugf("Running %s %s in the pod %s", terraformPath, strings.Join(args, " "), executor.pod)
+	runErr := executor.exec(ctx, terragruntOptions, remoteTerraformCommand(kubernetesWorkspaceDir, terragruntOptions.WorkingDir, terraformPath, args), os.Stdin, stdout, stderr)
+	if runErr != nil {
+		if err := executor.podError(ctx, terragruntOptions, runErr); err != runErr {
+			return err
+		}
+		if exitCode, err := GetExitCode(runErr); err == nil && exitCode > 0 {
+			executor.exitCode = exitCode
+		} else {
+			// Interrupted, e.g. as the context was cancelled
+			executor.exitCode = 1
+		}
+	}
+
+	// Copy back the files written by terraform even if it failed, e.g. a partially written plan or the crash log
+	terragruntOptions.Logger.Debugf("Copying the pod %s back to %s", executor.pod, terragruntOptions.WorkingDir)
+	reader, writer := io.Pipe()
+	extractErrs := make(chan error, 1)
+	go func() {
+		extractErr := extractGzipTar(reader, terragruntOptions.WorkingDir)
+		// Drain the rest of the output, so that kubectl does not block writing it
+		_, _ = io.Copy(io.Discard, reader)
+		extractErrs <- extractErr
+	}()
+	copyErr := executor.exec(ctx, terragruntOptions, remoteArchiveCommand(kubernetesWorkspaceDir), nil, writer, stderr)
+	writer.Close() //nolint:errcheck
+	extractErr := <-extractErrs
+
+	if copyErr == nil {
+		copyErr = extractErr
+	} else {
+		copyErr = ExecutorCopyFailed{Dir: kubernetesWorkspaceDir, Executor: executor.String(), Err: copyErr}
+	}
+	if copyErr != nil {
+		if runErr != nil {
+			terragruntOptions.Logger.Warnf("Failed to copy the pod %s back to %s: %v", executor.pod, terragruntOptions.WorkingDir, copyErr)
+			return errors.WithStackTrace(runErr)
+		}
+		return errors.WithStackTrace(copyErr)
+	}
+
+	return errors.WithStackTrace(runErr)
+}
+
+// Close makes the container of the Job exit with the exit code of the last terraform command, which completes the Job.
+// The Job is deleted instead if its pod never ran, e.g. when the module failed or was interrupted before, or if the
+// container cannot be told to exit, so that the pod does not hold the resources of the cluster until its deadline. Close
+// runs with a context of its own, as the context of the run may be cancelled.
+func (executor *KubernetesExecutor) Close() error {
+	if executor.job == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kubernetesCleanupTimeout)
+	defer cancel()
+
+	if executor.pod != "" {
+		command := fmt.Sprintf("echo %d > %s/%s", executor.exitCode, kubernetesWorkspaceDir, kubernetesExitCodeFile)
+		err := executor.exec(ctx, executor.terragruntOptions, command, nil, io.Discard, io.Discard)
+		if err == nil {
+			return nil
+		}
+		executor.terragruntOptions.Logger.Warnf("Failed to finish the %s, deleting it: %v", executor, err)
+	}
+
+	executor.terragruntOptions.Logger.Debugf("Deleting the %s", executor)
+	if _, err := executor.kubectl(ctx, executor.terragruntOptions, nil, "delete", "job", executor.job, "--ignore-not-found", "--cascade=background", "--wait=false"); err != nil {
+		return errors.WithStackTrace(KubernetesJobFailed{Job: executor.job, Reason: "failed to delete the job: " + err.Error()})
+	}
+	return nil
+}
+
+// startJob creates the Job and waits for its pod to run.
+func (executor *KubernetesExecutor) startJob(ctx context.Context, terragruntOptions *options.TerragruntOptions) error {
+	executor.terragruntOptions = terragruntOptions
+	executor.job = kubernetesJobName(terragruntOptions.TerragruntConfigPath)
+
+	manifest, err := json.Marshal(executor.jobManifest())
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	terragruntOptions.Logger.Infof("Starting the %s for %s", executor, terragruntOptions.WorkingDir)
+	if _, err := executor.kubectl(ctx, terragruntOptions, bytes.NewReader(manifest), "create", "-f", "-"); err != nil {
+		return errors.WithStackTrace(KubernetesJobFailed{Job: executor.job, Reason: err.Error()})
+	}
+
+	deadline := time.Now().Add(kubernetesPodStartTimeout)
+	for {
+		output, err := executor.kubectl(ctx, terragruntOptions, nil, "get", "pods", "--selector", "job-name="+executor.job, "--output", `jsonpath={.items[0].metadata.name} {.items[0].status.phase}`)
+		if err == nil {
+			name, phase, _ := strings.Cut(strings.TrimSpace(output), " ")
+			switch phase {
+			case "Running":
+				executor.pod = name
+				terragruntOptions.Logger.Debugf("The pod %s of the %s is running", name, executor)
+				return nil
+			case "Failed", "Succeeded":
+				return errors.WithStackTrace(KubernetesJobFailed{Job: executor.job, Pod: name, Reason: executor.withJobFailureReason(ctx, terragruntOptions, "the pod exited before terraform ran, with status "+phase)})
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return errors.WithStackTrace(KubernetesJobFailed{Job: executor.job, Reason: executor.withJobFailureReason(ctx, terragruntOptions, fmt.Sprintf("the pod did not start within %s", kubernetesPodStartTimeout))})
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.WithStackTrace(ctx.Err())
+		case <-time.After(kubernetesPodPollInterval):
+		}
+	}
+}
+
+func (executor *KubernetesExecutor) jobManifest() map[string]interface{} {
+	labels := map[string]string{"app.kubernetes.io/managed-by": "terragrunt"}
+	for name, value := range executor.Labels {
+		labels[name] = value
+	}
+
+	activeDeadline := executor.ActiveDeadline
+	if activeDeadline == 0 {
+		activeDeadline = DefaultKubernetesActiveDeadline
+	}
+
+	// The container waits for Terragrunt to tell it the exit code, while Terragrunt runs the commands in it
+	waitScript := fmt.Sprintf(`while [ ! -f %s/%s ]; do sleep 1; done; exit "$(cat %s/%s)"`, kubernetesWorkspaceDir, kubernetesExitCodeFile, kubernetesWorkspaceDir, kubernetesExitCodeFile)
+
+	podSpec := map[string]interface{}{
+		"restartPolicy": "Never",
+		"containers": []interface{}{map[string]interface{}{
+			"name":         kubernetesContainerName,
+			"image":        executor.Image,
+			"command":      []string{"sh", "-c", waitScript},
+			"workingDir":   kubernetesWorkspaceDir,
+			"resources":    map[string]interface{}{"requests": executor.Requests, "limits": executor.Limits},
+			"volumeMounts": []interface{}{map[string]interface{}{"name": "workspace", "mountPath": kubernetesWorkspaceDir}},
+		}},
+		"volumes": []interface{}{map[string]interface{}{"name": "workspace", "emptyDir": map[string]interface{}{}}},
+	}
+	if executor.ServiceAccount != "" {
+		podSpec["serviceAccountName"] = executor.ServiceAccount
+	}
+
+	jobSpec := map[string]interface{}{
+		"backoffLimit":          0,
+		"activeDeadlineSeconds": int64(activeDeadline.Seconds()),
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": labels},
+			"spec":     podSpec,
+		},
+	}
+	if executor.TTLAfterFinished > 0 {
+		jobSpec["ttlSecondsAfterFinished"] = int64(executor.TTLAfterFinished.Seconds())
+	}
+
+	return map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata":   map[string]interface{}{"name": executor.job, "labels": labels},
+		"spec":       jobSpec,
+	}
+}
+
+// podError returns why the pod failed if it is no longer running, e.g. because it was evicted or ran out of memory,
+// otherwise the given error.
+func (executor *KubernetesExecutor) podError(ctx context.Context, terragruntOptions *options.TerragruntOptions, err error) error {
+	output, getErr := executor.kubectl(ctx, terragruntOptions, nil, "get", "pod", executor.pod, "--output", `jsonpath={.status.phase} {.status.reason} {.status.containerStatuses[0].state.terminated.reason}`)
+	if getErr != nil {
+		return err
+	}
+
+	status := strings.Fields(output)
+	if len(status) == 0 || status[0] == "Running" {
+		return err
+	}
+	return errors.WithStackTrace(KubernetesJobFailed{Job: executor.job, Pod: executor.pod, Reason: executor.withJobFailureReason(ctx, terragruntOptions, strings.Join(status, " "))})
+}
+
+// withJobFailureReason prefixes the given reason with why Kubernetes failed the Job, e.g. DeadlineExceeded or
+// BackoffLimitExceeded, if it did.
+func (executor *KubernetesExecutor) withJobFailureReason(ctx context.Context, terragruntOptions *options.TerragruntOptions, reason string) string {
+	output, err := executor.kubectl(ctx, terragruntOptions, nil, "get", "job", executor.job, "--output", `jsonpath={.status.conditions[?(@.type=="Failed")].reason}`)
+	if jobReason := strings.TrimSpace(output); err == nil && jobReason != "" {
+		return fmt.Sprintf("job %s, %s", jobReason, reason)
+	}
+	return reason
+}
+
+// exec runs the given shell command in the container of the pod.
+func (executor *KubernetesExecutor) exec(ctx context.Context, terragruntOptions *options.TerragruntOptions, command string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	args := append(executor.kubectlArgs(), "exec", "--container", kubernetesContainerName)
+	if stdin != nil {
+		args = append(args, "--stdin")
+	}
+	args = append(args, executor.pod, "--", "sh", "-c", command)
+
+	cmd := exec.CommandContext(ctx, executor.command(), args...)
+	cmd.Env = toEnvVarsList(terragruntOptions.Env)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return errors.WithStackTrace(cmd.Run())
+}
+
+// kubectl runs kubectl with the given args, returning its output.
+func (executor *KubernetesExecutor) kubectl(ctx context.Context, terragruntOptions *options.TerragruntOptions, stdin io.Reader, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, executor.command(), append(executor.kubectlArgs(), args...)...)
+	cmd.Env = toEnvVarsList(terragruntOptions.Env)
+	cmd.Stdin = stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", errors.WithStackTrace(fmt.Errorf("%s %s: %w: %s", kubectlCommand, strings.Join(args, " "), err, strings.TrimSpace(stderr.String())))
+	}
+	return stdout.String(), nil
+}
+
+func (executor *KubernetesExecutor) kubectlArgs() []string {
+	var args []string
+	if executor.Context != "" {
+		args = append(args, "--context", executor.Context)
+	}
+	if executor.Namespace != "" {
+		args = append(args, "--namespace", executor.Namespace)
+	}
+	return args
+}
+
+func (executor *KubernetesExecutor) command() string {
+	if executor.kubectlCommand != "" {
+		return executor.kubectlCommand
+	}
+	return kubectlCommand
+}
+
+// kubernetesJobName returns a unique name for the Job of the module with the given config, named after the dir of the
+// module to tell the Jobs apart.
+func kubernetesJobName(configPath string) string {
+	name := invalidKubernetesNameChars.ReplaceAllString(strings.ToLower(filepath.Base(filepath.Dir(configPath))), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 40 {
+		name = name[:40]
+	}
+	return strings.TrimSuffix("terragrunt-"+name, "-") + "-" + strings.ToLower(util.UniqueId())
+}
+
+// Custom error types
+
+type KubernetesJobFailed struct {
+	Job    string
+	Pod    string
+	Reason string
+}
+
+func (err KubernetesJobFailed) Error() string {
+	if err.Pod != "" {
+		return fmt.Sprintf("The Kubernetes job %s failed in the pod %s: %s", err.Job, err.Pod, err.Reason)
+	}
+	return fmt.Sprintf("The Kubernetes job %s failed: %s", err.Job, err.Reason)
+}
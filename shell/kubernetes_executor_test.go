@@ -0,0 +1,149 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package shell
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesExecutorRunTerraform(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	workingDir := filepath.Join(tmpDir, "module")
+	workspace := filepath.Join(tmpDir, "workspace")
+	require.NoError(t, os.MkdirAll(workingDir, os.ModePerm))
+	require.NoError(t, os.MkdirAll(workspace, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "main.tf"), []byte("# main\n"), 0644))
+
+	// A fake kubectl, saving the manifest of the Job and running the exec commands with the workspace in the temp dir
+	fakeKubectl := filepath.Join(tmpDir, "kubectl")
+	script := `#!/bin/sh
+case "$1 $2" in
+"create -f") cat > ` + tmpDir + `/job.json ;;
+"get pods") echo "terragrunt-pod Running" ;;
+"get pod") echo "Running" ;;
+exec*) for arg; do cmd="$arg"; done
+	cmd=$(printf '%s' "$cmd" | sed "s#` + kubernetesWorkspaceDir + `#` + workspace + `#g")
+	exec env -i PATH="$PATH" sh -c "$cmd" ;;
+esac
+`
+	require.NoError(t, os.WriteFile(fakeKubectl, []byte(script), 0755))
+
+	// A fake terraform, printing an input and writing a plan file
+	fakeTerraform := filepath.Join(tmpDir, "terraform")
+	require.NoError(t, os.WriteFile(fakeTerraform, []byte("#!/bin/sh\ntest -f main.tf || exit 3\ntest \"$1\" != apply || exit 5\necho \"$TF_VAR_name $1\"\necho plan > \"$2\"\n"), 0755))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = workingDir
+	terragruntOptions.Env = map[string]string{"TF_VAR_name": "it's me"}
+
+	executor := &KubernetesExecutor{Image: "hashicorp/terraform:1.5", ServiceAccount: "deployer", TerraformPath: fakeTerraform, kubectlCommand: fakeKubectl}
+
+	var stdout bytes.Buffer
+	err = executor.RunTerraform(context.Background(), terragruntOptions, []string{"plan", filepath.Join(workingDir, "out.tfplan")}, &stdout, os.Stderr)
+	require.NoError(t, err)
+	assert.Equal(t, "it's me plan\n", stdout.String())
+	assert.Equal(t, "terragrunt-pod", executor.pod)
+
+	plan, err := os.ReadFile(filepath.Join(workingDir, "out.tfplan"))
+	require.NoError(t, err)
+	assert.Equal(t, "plan\n", string(plan))
+
+	err = executor.RunTerraform(context.Background(), terragruntOptions, []string{"apply"}, &stdout, os.Stderr)
+	require.Error(t, err)
+	exitCode, err := GetExitCode(err)
+	require.NoError(t, err)
+	assert.Equal(t, 5, exitCode)
+
+	// Finishing the run tells the container to exit with the exit code of the last command
+	require.NoError(t, executor.Close())
+	exitCodeFile, err := os.ReadFile(filepath.Join(workspace, kubernetesExitCodeFile))
+	require.NoError(t, err)
+	assert.Equal(t, "5", strings.TrimSpace(string(exitCodeFile)))
+
+	var job map[string]interface{}
+	manifest, err := os.ReadFile(filepath.Join(tmpDir, "job.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(manifest, &job))
+	assert.Equal(t, executor.job, job["metadata"].(map[string]interface{})["name"])
+	podSpec := job["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	assert.Equal(t, "deployer", podSpec["serviceAccountName"])
+	assert.Equal(t, "hashicorp/terraform:1.5", podSpec["containers"].([]interface{})[0].(map[string]interface{})["image"])
+}
+
+func TestKubernetesExecutorJobFailed(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	workingDir := filepath.Join(tmpDir, "module")
+	require.NoError(t, os.MkdirAll(workingDir, os.ModePerm))
+
+	// A fake kubectl, whose Job fails before its pod runs, recording the deleted Jobs
+	fakeKubectl := filepath.Join(tmpDir, "kubectl")
+	script := `#!/bin/sh
+case "$1 $2" in
+"create -f") cat > /dev/null ;;
+"get pods") echo "terragrunt-pod Failed" ;;
+"get job") echo "DeadlineExceeded" ;;
+"delete job") echo "$3" >> ` + tmpDir + `/deleted ;;
+esac
+`
+	require.NoError(t, os.WriteFile(fakeKubectl, []byte(script), 0755))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = workingDir
+
+	executor := &KubernetesExecutor{Image: "hashicorp/terraform:1.5", kubectlCommand: fakeKubectl}
+
+	err = executor.RunTerraform(context.Background(), terragruntOptions, []string{"plan"}, io.Discard, io.Discard)
+	var jobFailed KubernetesJobFailed
+	require.ErrorAs(t, errors.Unwrap(err), &jobFailed)
+	assert.Contains(t, jobFailed.Reason, "DeadlineExceeded")
+
+	// The Job whose pod never ran is deleted once the module is done
+	require.NoError(t, executor.Close())
+	deleted, err := os.ReadFile(filepath.Join(tmpDir, "deleted"))
+	require.NoError(t, err)
+	assert.Equal(t, executor.job, strings.TrimSpace(string(deleted)))
+}
+
+func TestKubernetesJobManifestDeadlines(t *testing.T) {
+	t.Parallel()
+
+	executor := &KubernetesExecutor{Image: "terraform", job: "terragrunt-vpc-abc"}
+	jobSpec := executor.jobManifest()["spec"].(map[string]interface{})
+	assert.Equal(t, int64(DefaultKubernetesActiveDeadline.Seconds()), jobSpec["activeDeadlineSeconds"])
+	assert.NotContains(t, jobSpec, "ttlSecondsAfterFinished")
+
+	executor.ActiveDeadline = time.Hour
+	executor.TTLAfterFinished = 10 * time.Minute
+	jobSpec = executor.jobManifest()["spec"].(map[string]interface{})
+	assert.Equal(t, int64(3600), jobSpec["activeDeadlineSeconds"])
+	assert.Equal(t, int64(600), jobSpec["ttlSecondsAfterFinished"])
+}
+
+func TestKubernetesJobName(t *testing.T) {
+	t.Parallel()
+
+	name := kubernetesJobName("/live/prod/My_VPC/terragrunt.hcl")
+	assert.True(t, strings.HasPrefix(name, "terragrunt-my-vpc-"), name)
+	assert.Equal(t, strings.ToLower(name), name)
+	assert.LessOrEqual(t, len(kubernetesJobName("/live/"+strings.Repeat("a", 100)+"/terragrunt.hcl")), 63)
+}
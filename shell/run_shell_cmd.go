@@ -118,10 +118,10 @@ func RunShellCommandWithOutput(
 		var errWriter = terragruntOptions.ErrWriter
 		var outWriter = terragruntOptions.Writer
 
-		// redirect output through logger with json wrapping
-		if terragruntOptions.JsonLogFormat && terragruntOptions.TerraformLogsToJson {
-			outWriter = terragruntOptions.Logger.Logger.WithField("workingDir", terragruntOptions.WorkingDir).WithField("executedCommandArgs", args).Writer()
-			errWriter = terragruntOptions.Logger.Logger.WithField("workingDir", terragruntOptions.WorkingDir).WithField("executedCommandArgs", args).WriterLevel(logrus.ErrorLevel)
+		// redirect output through logger with json or logfmt wrapping, with the same fields as the Terragrunt logs
+		if terragruntOptions.StructuredLogs() && terragruntOptions.TerraformLogsToJson {
+			outWriter = terragruntOptions.Logger.WithField("workingDir", terragruntOptions.WorkingDir).WithField("executedCommandArgs", args).Writer()
+			errWriter = terragruntOptions.Logger.WithField("workingDir", terragruntOptions.WorkingDir).WithField("executedCommandArgs", args).WriterLevel(logrus.ErrorLevel)
 		}
 
 		var prefix = ""
@@ -130,6 +130,12 @@ func RunShellCommandWithOutput(
 		}
 		cmd.Dir = commandDir
 
+		if NoNetworkFromContext(ctx) {
+			if err := withoutNetwork(cmd); err != nil {
+				return err
+			}
+		}
+
 		// Inspired by https://blog.kowalczyk.info/article/wOYk/advanced-command-execution-in-go-with-osexec.html
 		cmdStderr := io.MultiWriter(withPrefix(errWriter, prefix), &stderrBuf)
 		var cmdStdout io.Writer
@@ -139,6 +145,21 @@ func RunShellCommandWithOutput(
 			cmdStdout = io.MultiWriter(&stdoutBuf)
 		}
 
+		executor := TerraformExecutorFromContext(ctx)
+		if executor != nil && command == terragruntOptions.TerraformPath {
+			err := executor.RunTerraform(ctx, terragruntOptions, args, cmdStdout, cmdStderr)
+			output = &CmdOutput{Stdout: stdoutBuf.String(), Stderr: stderrBuf.String()}
+			if err != nil {
+				err = ProcessExecutionError{
+					Err:        err,
+					StdOut:     stdoutBuf.String(),
+					Stderr:     stderrBuf.String(),
+					WorkingDir: fmt.Sprintf("%s on %s", cmd.Dir, executor),
+				}
+			}
+			return errors.WithStackTrace(err)
+		}
+
 		// If we need to allocate a ptty for the command, route through the ptty routine. Otherwise, directly call the
 		// command.
 		if allocatePseudoTty {
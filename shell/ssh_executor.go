@@ -0,0 +1,160 @@
+package shell
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	sshCommand = "ssh"
+
+	// The dir, in the home dir of the remote host, where the working dirs of the modules are copied by default.
+	sshDefaultRemoteDir = ".terragrunt-ssh"
+)
+
+// SSHExecutor runs terraform on a remote host over SSH, with the system ssh client, so that the SSH config and agent of
+// the user are used. Before every command the working dir of the module is copied to the remote host, and after the
+// command the files terraform wrote are copied back.
+type SSHExecutor struct {
+	Host         string
+	User         string
+	Port         int
+	IdentityFile string
+	// The dir on the remote host the working dir is copied to, a dir per module in the home dir if empty.
+	RemoteDir     string
+	TerraformPath string
+	// The names of the env vars to pass on to terraform, in addition to the ones terraform reads its inputs from.
+	ForwardEnv []string
+	// Extra args of the ssh client, e.g. `-o ProxyJump=bastion`.
+	ExtraArgs []string
+
+	// The ssh client, ssh from the PATH if empty.
+	sshCommand string
+}
+
+func (executor *SSHExecutor) String() string {
+	return "ssh://" + executor.target()
+}
+
+func (executor *SSHExecutor) target() string {
+	if executor.User != "" {
+		return executor.User + "@" + executor.Host
+	}
+	return executor.Host
+}
+
+func (executor *SSHExecutor) RunTerraform(ctx context.Context, terragruntOptions *options.TerragruntOptions, args []string, stdout io.Writer, stderr io.Writer) error {
+	remoteDir := executor.RemoteDir
+	if remoteDir == "" {
+		remoteDir = sshDefaultRemoteDir + "/" + util.EncodeBase64Sha1(terragruntOptions.WorkingDir)
+	}
+	// The content of the remote dir is replaced with the working dir
+	if cleanDir := path.Clean(remoteDir); cleanDir == "/" || cleanDir == "." || cleanDir == "~" {
+		return errors.WithStackTrace(InvalidExecutorRemoteDir(remoteDir))
+	}
+
+	terraformPath := executor.TerraformPath
+	if terraformPath == "" {
+		terraformPath = terragruntOptions.TerraformPath
+	}
+
+	terragruntOptions.Logger.Debugf("Copying %s to %s on %s", terragruntOptions.WorkingDir, remoteDir, executor)
+	if err := executor.upload(ctx, terragruntOptions, remoteDir, stderr); err != nil {
+		return err
+	}
+
+	terragruntOptions.Logger.Debugf("Running %s %s in %s on %s", terraformPath, strings.Join(args, " "), remoteDir, executor)
+	cmd := exec.CommandContext(ctx, executor.command(), executor.sshArgs(remoteTerraformCommand(remoteDir, terragruntOptions.WorkingDir, terraformPath, args))...)
+	cmd.Env = toEnvVarsList(terragruntOptions.Env)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	runErr := cmd.Run()
+
+	// Copy back the files written by terraform even if it failed, e.g. a partially written plan or the crash log
+	terragruntOptions.Logger.Debugf("Copying %s on %s back to %s", remoteDir, executor, terragruntOptions.WorkingDir)
+	if err := executor.download(ctx, terragruntOptions, remoteDir, stderr); err != nil {
+		if runErr != nil {
+			terragruntOptions.Logger.Warnf("Failed to copy %s on %s back to %s: %v", remoteDir, executor, terragruntOptions.WorkingDir, err)
+			return errors.WithStackTrace(runErr)
+		}
+		return err
+	}
+
+	return errors.WithStackTrace(runErr)
+}
+
+// upload copies the working dir to the remote dir, together with the env file terraform runs with.
+func (executor *SSHExecutor) upload(ctx context.Context, terragruntOptions *options.TerragruntOptions, remoteDir string, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, executor.command(), executor.sshArgs(remoteExtractCommand(remoteDir))...)
+	cmd.Env = toEnvVarsList(terragruntOptions.Env)
+	cmd.Stderr = stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := cmd.Start(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	writeErr := writeWorkingDirArchive(stdin, terragruntOptions, executor.ForwardEnv)
+	stdin.Close() //nolint:errcheck
+
+	if err := cmd.Wait(); err != nil {
+		return errors.WithStackTrace(ExecutorCopyFailed{Dir: terragruntOptions.WorkingDir, Executor: executor.String(), Err: err})
+	}
+	return writeErr
+}
+
+// download copies the remote dir back to the working dir.
+func (executor *SSHExecutor) download(ctx context.Context, terragruntOptions *options.TerragruntOptions, remoteDir string, stderr io.Writer) error {
+	cmd := exec.CommandContext(ctx, executor.command(), executor.sshArgs(remoteArchiveCommand(remoteDir))...)
+	cmd.Env = toEnvVarsList(terragruntOptions.Env)
+	cmd.Stderr = stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := cmd.Start(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	extractErr := extractGzipTar(stdout, terragruntOptions.WorkingDir)
+	// Drain the rest of the output, so that ssh does not block writing it
+	_, _ = io.Copy(io.Discard, stdout)
+
+	if err := cmd.Wait(); err != nil {
+		return errors.WithStackTrace(ExecutorCopyFailed{Dir: remoteDir, Executor: executor.String(), Err: err})
+	}
+	return extractErr
+}
+
+func (executor *SSHExecutor) command() string {
+	if executor.sshCommand != "" {
+		return executor.sshCommand
+	}
+	return sshCommand
+}
+
+func (executor *SSHExecutor) sshArgs(remoteCommand string) []string {
+	var args []string
+	if executor.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(executor.Port))
+	}
+	if executor.IdentityFile != "" {
+		args = append(args, "-i", executor.IdentityFile)
+	}
+	args = append(args, executor.ExtraArgs...)
+	return append(args, executor.target(), remoteCommand)
+}
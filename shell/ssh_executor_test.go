@@ -0,0 +1,101 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package shell
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSHExecutorRunTerraform(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	workingDir := filepath.Join(tmpDir, "module")
+	remoteHome := filepath.Join(tmpDir, "remote")
+	require.NoError(t, os.MkdirAll(workingDir, os.ModePerm))
+	require.NoError(t, os.MkdirAll(remoteHome, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "main.tf"), []byte("# main\n"), 0644))
+
+	// A fake ssh client, running the remote command in the remote home dir
+	fakeSSH := filepath.Join(tmpDir, "ssh")
+	require.NoError(t, os.WriteFile(fakeSSH, []byte("#!/bin/sh\nfor arg; do cmd=\"$arg\"; done\ncd "+remoteHome+" && exec env -i PATH=\"$PATH\" sh -c \"$cmd\"\n"), 0755))
+
+	// A fake terraform, printing an input and writing a plan file
+	fakeTerraform := filepath.Join(tmpDir, "terraform")
+	require.NoError(t, os.WriteFile(fakeTerraform, []byte("#!/bin/sh\ntest -f main.tf || exit 3\ntest \"$1\" != apply || exit 5\necho \"$TF_VAR_name $1\"\necho plan > \"$2\"\ntest -z \"$SECRET\" || exit 4\n"), 0755))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = workingDir
+	terragruntOptions.Env = map[string]string{"TF_VAR_name": "it's me", "SECRET": "not forwarded"}
+
+	executor := &SSHExecutor{Host: "enclave", TerraformPath: fakeTerraform, sshCommand: fakeSSH}
+
+	var stdout bytes.Buffer
+	err = executor.RunTerraform(context.Background(), terragruntOptions, []string{"plan", filepath.Join(workingDir, "out.tfplan")}, &stdout, os.Stderr)
+	require.NoError(t, err)
+	assert.Equal(t, "it's me plan\n", stdout.String())
+
+	plan, err := os.ReadFile(filepath.Join(workingDir, "out.tfplan"))
+	require.NoError(t, err)
+	assert.Equal(t, "plan\n", string(plan))
+	assert.NoFileExists(t, filepath.Join(workingDir, executorEnvFile))
+
+	err = executor.RunTerraform(context.Background(), terragruntOptions, []string{"apply"}, &stdout, os.Stderr)
+	require.Error(t, err)
+	exitCode, err := GetExitCode(err)
+	require.NoError(t, err)
+	assert.Equal(t, 5, exitCode)
+}
+
+func TestSSHExecutorRemoteDir(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	workingDir := filepath.Join(tmpDir, "module")
+	remoteHome := filepath.Join(tmpDir, "remote home")
+	require.NoError(t, os.MkdirAll(workingDir, os.ModePerm))
+	require.NoError(t, os.MkdirAll(remoteHome, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "main.tf"), []byte("# main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "removed.tf"), []byte("# removed\n"), 0644))
+
+	// A fake ssh client, running the remote command in the remote home dir
+	fakeSSH := filepath.Join(tmpDir, "ssh")
+	require.NoError(t, os.WriteFile(fakeSSH, []byte("#!/bin/sh\nfor arg; do cmd=\"$arg\"; done\ncd '"+remoteHome+"' && exec env -i PATH=\"$PATH\" HOME='"+remoteHome+"' sh -c \"$cmd\"\n"), 0755))
+	fakeTerraform := filepath.Join(tmpDir, "terraform")
+	require.NoError(t, os.WriteFile(fakeTerraform, []byte("#!/bin/sh\ntest -f main.tf || exit 3\n"), 0755))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = workingDir
+
+	executor := &SSHExecutor{Host: "enclave", RemoteDir: "~/work dir/module", TerraformPath: fakeTerraform, sshCommand: fakeSSH}
+	remoteDir := filepath.Join(remoteHome, "work dir", "module")
+
+	require.NoError(t, executor.RunTerraform(context.Background(), terragruntOptions, []string{"init"}, io.Discard, os.Stderr))
+	assert.FileExists(t, filepath.Join(remoteDir, "removed.tf"))
+
+	// The files removed from the working dir are removed from the remote dir, but not the providers
+	require.NoError(t, os.MkdirAll(filepath.Join(remoteDir, ".terraform"), os.ModePerm))
+	require.NoError(t, os.Remove(filepath.Join(workingDir, "removed.tf")))
+	require.NoError(t, executor.RunTerraform(context.Background(), terragruntOptions, []string{"plan"}, io.Discard, os.Stderr))
+	assert.NoFileExists(t, filepath.Join(remoteDir, "removed.tf"))
+	assert.FileExists(t, filepath.Join(remoteDir, "main.tf"))
+	assert.DirExists(t, filepath.Join(remoteDir, ".terraform"))
+
+	executor.RemoteDir = "~/"
+	err = executor.RunTerraform(context.Background(), terragruntOptions, []string{"plan"}, io.Discard, os.Stderr)
+	require.Error(t, err)
+	assert.IsType(t, InvalidExecutorRemoteDir(""), errors.Unwrap(err))
+}
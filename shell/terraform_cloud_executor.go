@@ -0,0 +1,538 @@
+package shell
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/tfcloud"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The env var with the token of any Terraform Cloud or Terraform Enterprise host, read if the host has no token of its
+// own, as in the provider of Terraform Enterprise.
+const terraformCloudTokenEnv = "TFE_TOKEN"
+
+// The args of plan and apply a run of a workspace has no use for, which are ignored without a warning.
+var terraformCloudIgnoredArgs = []string{"-input", "-no-color", "-compact-warnings", "-lock", "-lock-timeout"}
+
+// TerraformCloudExecutor runs the plans and applies of a module as the runs of a Terraform Cloud or Terraform
+// Enterprise workspace. Before the first command of the module, the workspace is created if it does not exist yet, its
+// settings are updated and the inputs of the module are set as its terraform variables. Every plan, apply and destroy
+// then triggers a run of the workspace, with the working dir uploaded as its configuration unless the workspace is
+// VCS-driven, and waits for the run to finish, printing its logs. `init` only prepares the workspace, which runs init
+// itself, and `output` reads the outputs of the current state of the workspace.
+type TerraformCloudExecutor struct {
+	// The hostname of Terraform Enterprise, or its address with the scheme, tfcloud.DefaultHostname if empty.
+	Hostname     string
+	Organization string
+	Workspace    string
+	ProjectID    string
+	// The terraform version and the dir of the repo the workspace runs in, left as they are if empty.
+	TerraformVersion string
+	WorkingDirectory string
+	Tags             []string
+	// The repo of a VCS-driven workspace, or nil for a CLI-driven workspace.
+	VCSRepo *tfcloud.VCSRepo
+	// The inputs of the module, set as the terraform variables of the workspace, and the names of the sensitive ones.
+	Inputs          map[string]interface{}
+	SensitiveInputs []string
+	// How long to wait for a run to finish, and how often to check its status.
+	Timeout      time.Duration
+	PollInterval time.Duration
+
+	client    *tfcloud.Client
+	workspace *tfcloud.Workspace
+}
+
+func (executor *TerraformCloudExecutor) String() string {
+	return fmt.Sprintf("terraform cloud workspace %s/%s", executor.Organization, executor.Workspace)
+}
+
+func (executor *TerraformCloudExecutor) RunTerraform(ctx context.Context, terragruntOptions *options.TerragruntOptions, args []string, stdout io.Writer, stderr io.Writer) error {
+	command := util.FirstArg(args)
+	switch command {
+	case terraform.CommandNameInit:
+		return executor.prepareWorkspace(ctx, terragruntOptions)
+	case terraform.CommandNamePlan, terraform.CommandNameApply, terraform.CommandNameDestroy:
+		runOptions, err := terraformCloudRunOptions(terragruntOptions, args)
+		if err != nil {
+			return err
+		}
+		if err := executor.prepareWorkspace(ctx, terragruntOptions); err != nil {
+			return err
+		}
+		return executor.run(ctx, terragruntOptions, runOptions, stdout)
+	case terraform.CommandNameOutput:
+		return executor.printOutputs(ctx, terragruntOptions, args, stdout)
+	default:
+		return errors.WithStackTrace(UnsupportedTerraformCloudCommand(command))
+	}
+}
+
+// prepareWorkspace creates or updates the workspace and sets its variables, once per module run.
+func (executor *TerraformCloudExecutor) prepareWorkspace(ctx context.Context, terragruntOptions *options.TerragruntOptions) error {
+	if executor.workspace != nil {
+		return nil
+	}
+	client, err := executor.getClient(terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	workspace, created, err := client.EnsureWorkspace(ctx, executor.Organization, &tfcloud.WorkspaceOptions{
+		Name:             executor.Workspace,
+		ProjectID:        executor.ProjectID,
+		TerraformVersion: executor.TerraformVersion,
+		WorkingDirectory: executor.WorkingDirectory,
+		Tags:             executor.Tags,
+		VCSRepo:          executor.VCSRepo,
+	})
+	if err != nil {
+		return err
+	}
+	if created {
+		terragruntOptions.Logger.Infof("Created the workspace %s of the organization %s", executor.Workspace, executor.Organization)
+	}
+
+	variables, err := terraformCloudVariables(executor.Inputs, executor.SensitiveInputs)
+	if err != nil {
+		return err
+	}
+	changed, err := client.SyncVariables(ctx, workspace.ID, variables)
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		terragruntOptions.Logger.Debugf("Set the variables %s of the workspace %s", strings.Join(changed, ", "), executor.Workspace)
+	}
+
+	executor.workspace = workspace
+	return nil
+}
+
+// run triggers a run of the workspace with the given settings, waits for it to finish and prints its logs. The run is
+// recorded on the options, for the report of run-all.
+func (executor *TerraformCloudExecutor) run(ctx context.Context, terragruntOptions *options.TerragruntOptions, runOptions *tfcloud.RunOptions, stdout io.Writer) error {
+	client := executor.client
+	runOptions.WorkspaceID = executor.workspace.ID
+
+	if executor.VCSRepo == nil {
+		configurationVersionID, err := executor.uploadConfiguration(ctx, terragruntOptions, runOptions.PlanOnly)
+		if err != nil {
+			return err
+		}
+		runOptions.ConfigurationVersionID = configurationVersionID
+	}
+
+	run, err := client.CreateRun(ctx, runOptions)
+	if err != nil {
+		return err
+	}
+	runURL := client.RunURL(executor.Organization, executor.Workspace, run.ID)
+	terragruntOptions.Logger.Infof("Triggered the run %s of the workspace %s, waiting for it to finish: %s", run.ID, executor.Workspace, runURL)
+
+	record := func(run *tfcloud.Run) {
+		if terragruntOptions.TerraformCloudRun != nil {
+			*terragruntOptions.TerraformCloudRun = options.TerraformCloudRun{ID: run.ID, Workspace: executor.Workspace, Status: run.Status, URL: runURL}
+		}
+	}
+	record(run)
+
+	confirmed := false
+	deadline := time.Now().Add(executor.Timeout)
+	for !run.IsFinished() {
+		if time.Now().Add(executor.PollInterval).After(deadline) {
+			return errors.WithStackTrace(TerraformCloudRunTimedOut{Workspace: executor.Workspace, RunID: run.ID, Status: run.Status, Timeout: executor.Timeout})
+		}
+		select {
+		case <-ctx.Done():
+			return errors.WithStackTrace(ctx.Err())
+		case <-time.After(executor.PollInterval):
+		}
+
+		status := run.Status
+		if run, err = client.ReadRun(ctx, run.ID); err != nil {
+			return err
+		}
+		record(run)
+		if run.Status != status {
+			terragruntOptions.Logger.Debugf("The run %s of the workspace %s is %s", run.ID, executor.Workspace, run.Status)
+		}
+
+		// The runs of the applies without -auto-approve wait for a confirmation, as terraform does
+		if run.IsConfirmable && !runOptions.AutoApply && !runOptions.PlanOnly && !confirmed {
+			executor.printPlanLog(ctx, terragruntOptions, run, stdout)
+			if err := executor.confirmRun(ctx, terragruntOptions, run, runURL); err != nil {
+				return err
+			}
+			confirmed = true
+		}
+	}
+
+	if !confirmed {
+		executor.printPlanLog(ctx, terragruntOptions, run, stdout)
+	}
+	executor.printApplyLog(ctx, terragruntOptions, run, stdout)
+
+	if !run.Succeeded() {
+		return errors.WithStackTrace(TerraformCloudRunFailed{Workspace: executor.Workspace, RunID: run.ID, Status: run.Status, URL: runURL})
+	}
+	return nil
+}
+
+// uploadConfiguration uploads the working dir as a configuration version of the workspace, and returns its ID once
+// the workspace processed the upload.
+func (executor *TerraformCloudExecutor) uploadConfiguration(ctx context.Context, terragruntOptions *options.TerragruntOptions, speculative bool) (string, error) {
+	client := executor.client
+	configurationVersion, err := client.CreateConfigurationVersion(ctx, executor.workspace.ID, speculative)
+	if err != nil {
+		return "", err
+	}
+
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := util.WriteDirToTar(tarWriter, terragruntOptions.WorkingDir, executorSkipDirs); err != nil {
+		return "", err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	if err := client.UploadConfiguration(ctx, configurationVersion.UploadURL, archive.Bytes()); err != nil {
+		return "", errors.WithStackTrace(ExecutorCopyFailed{Dir: terragruntOptions.WorkingDir, Executor: executor.String(), Err: err})
+	}
+
+	deadline := time.Now().Add(executor.Timeout)
+	for configurationVersion.Status != tfcloud.ConfigurationStatusUploaded {
+		if configurationVersion.Status == tfcloud.ConfigurationStatusErrored || time.Now().After(deadline) {
+			return "", errors.WithStackTrace(ExecutorCopyFailed{Dir: terragruntOptions.WorkingDir, Executor: executor.String(), Err: ConfigurationNotUploaded(configurationVersion.Status)})
+		}
+		select {
+		case <-ctx.Done():
+			return "", errors.WithStackTrace(ctx.Err())
+		case <-time.After(executor.PollInterval):
+		}
+		if configurationVersion, err = client.ReadConfigurationVersion(ctx, configurationVersion.ID); err != nil {
+			return "", err
+		}
+	}
+	return configurationVersion.ID, nil
+}
+
+// confirmRun asks the user to confirm the run of an apply without -auto-approve, which waits for a confirmation, and
+// applies or discards it. Without a user to ask, the run is left waiting for a confirmation in Terraform Cloud.
+func (executor *TerraformCloudExecutor) confirmRun(ctx context.Context, terragruntOptions *options.TerragruntOptions, run *tfcloud.Run, runURL string) error {
+	if terragruntOptions.NonInteractive {
+		return errors.WithStackTrace(TerraformCloudRunNeedsConfirmation{Workspace: executor.Workspace, RunID: run.ID, URL: runURL})
+	}
+
+	confirmed, err := PromptUserForYesNo(fmt.Sprintf("Do you want to apply the run %s of the workspace %s?", run.ID, executor.Workspace), terragruntOptions)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		if err := executor.client.DiscardRun(ctx, run.ID); err != nil {
+			return err
+		}
+		return errors.WithStackTrace(TerraformCloudRunDiscarded{Workspace: executor.Workspace, RunID: run.ID})
+	}
+	return executor.client.ApplyRun(ctx, run.ID)
+}
+
+// printPlanLog and printApplyLog print the logs of the plan and the apply of the given run. The logs are only printed
+// for the user, so failing to read them does not fail the command.
+func (executor *TerraformCloudExecutor) printPlanLog(ctx context.Context, terragruntOptions *options.TerragruntOptions, run *tfcloud.Run, stdout io.Writer) {
+	if run.PlanID != "" {
+		if log, err := executor.client.ReadPlanLog(ctx, run.PlanID); err != nil {
+			terragruntOptions.Logger.Warnf("Failed to read the log of the plan of the run %s: %v", run.ID, err)
+		} else {
+			fmt.Fprint(stdout, log)
+		}
+	}
+}
+
+func (executor *TerraformCloudExecutor) printApplyLog(ctx context.Context, terragruntOptions *options.TerragruntOptions, run *tfcloud.Run, stdout io.Writer) {
+	if run.ApplyID != "" && run.Status != tfcloud.RunStatusPlannedAndFinished {
+		if log, err := executor.client.ReadApplyLog(ctx, run.ApplyID); err != nil {
+			terragruntOptions.Logger.Warnf("Failed to read the log of the apply of the run %s: %v", run.ID, err)
+		} else {
+			fmt.Fprint(stdout, log)
+		}
+	}
+}
+
+// printOutputs prints the outputs of the current state of the workspace as `terraform output` does: all the outputs
+// or the given one, in JSON with -json.
+func (executor *TerraformCloudExecutor) printOutputs(ctx context.Context, terragruntOptions *options.TerragruntOptions, args []string, stdout io.Writer) error {
+	client, err := executor.getClient(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	workspace := executor.workspace
+	if workspace == nil {
+		if workspace, err = client.ReadWorkspace(ctx, executor.Organization, executor.Workspace); err != nil {
+			return err
+		}
+	}
+	outputs, err := client.ReadOutputs(ctx, workspace.ID)
+	if err != nil {
+		return err
+	}
+
+	asJSON := util.ListContainsElement(args, "-json")
+	name := ""
+	for _, arg := range args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			name = arg
+		}
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].Name < outputs[j].Name
+	})
+
+	if name != "" {
+		for _, output := range outputs {
+			if output.Name == name {
+				_, err := fmt.Fprintln(stdout, string(output.Value))
+				return errors.WithStackTrace(err)
+			}
+		}
+		return errors.WithStackTrace(TerraformCloudOutputNotFound{Name: name, Workspace: executor.Workspace})
+	}
+
+	if asJSON {
+		type jsonOutput struct {
+			Sensitive bool            `json:"sensitive"`
+			Type      json.RawMessage `json:"type,omitempty"`
+			Value     json.RawMessage `json:"value"`
+		}
+		result := map[string]jsonOutput{}
+		for _, output := range outputs {
+			result[output.Name] = jsonOutput{Sensitive: output.Sensitive, Type: output.Type, Value: output.Value}
+		}
+		content, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		_, err = fmt.Fprintln(stdout, string(content))
+		return errors.WithStackTrace(err)
+	}
+
+	for _, output := range outputs {
+		value := string(output.Value)
+		if output.Sensitive {
+			value = "<sensitive>"
+		}
+		if _, err := fmt.Fprintf(stdout, "%s = %s\n", output.Name, value); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+	return nil
+}
+
+func (executor *TerraformCloudExecutor) getClient(terragruntOptions *options.TerragruntOptions) (*tfcloud.Client, error) {
+	if executor.client != nil {
+		return executor.client, nil
+	}
+
+	hostname := executor.Hostname
+	if hostname == "" {
+		hostname = tfcloud.DefaultHostname
+	}
+	host := hostname
+	if parsedURL, err := url.Parse(hostname); err == nil && parsedURL.Host != "" {
+		host = parsedURL.Hostname()
+	}
+	if err := terragruntOptions.CheckOfflineFetch("the runs of "+executor.String(), host); err != nil {
+		return nil, err
+	}
+
+	token := terraformCloudToken(terragruntOptions, host)
+	if token == "" {
+		return nil, errors.WithStackTrace(MissingTerraformCloudToken(host))
+	}
+	executor.client = tfcloud.NewClient(hostname, token)
+	return executor.client, nil
+}
+
+// terraformCloudToken returns the token of the given host the way terraform finds it: from the TF_TOKEN_* env var of the
+// host, then from the credentials file `terraform login` writes. The TFE_TOKEN env var is the fallback.
+func terraformCloudToken(terragruntOptions *options.TerragruntOptions, host string) string {
+	if token := terragruntOptions.Env[terraformCloudHostTokenEnv(host)]; token != "" {
+		return token
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		if content, err := os.ReadFile(filepath.Join(homeDir, ".terraform.d", "credentials.tfrc.json")); err == nil {
+			var credentials struct {
+				Credentials map[string]struct {
+					Token string `json:"token"`
+				} `json:"credentials"`
+			}
+			if err := json.Unmarshal(content, &credentials); err == nil && credentials.Credentials[host].Token != "" {
+				return credentials.Credentials[host].Token
+			}
+		}
+	}
+	return terragruntOptions.Env[terraformCloudTokenEnv]
+}
+
+// terraformCloudHostTokenEnv returns the TF_TOKEN_* env var of the token of the given host.
+func terraformCloudHostTokenEnv(host string) string {
+	return fmt.Sprintf(terraform.EnvNameTFTokenFmt, strings.NewReplacer(".", "_", "-", "__").Replace(host))
+}
+
+// terraformCloudRunOptions returns the settings of the run of the given plan, apply or destroy command. The args a run
+// does not support are ignored with a warning, except for a saved plan, which the run cannot apply. An apply or destroy
+// is only applied without a confirmation with -auto-approve.
+func terraformCloudRunOptions(terragruntOptions *options.TerragruntOptions, args []string) (*tfcloud.RunOptions, error) {
+	command := util.FirstArg(args)
+	runOptions := &tfcloud.RunOptions{
+		Message:   "Triggered by Terragrunt: " + strings.Join(args, " "),
+		PlanOnly:  command == terraform.CommandNamePlan,
+		IsDestroy: command == terraform.CommandNameDestroy,
+	}
+
+	var ignored []string
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case name == "-auto-approve" && command != terraform.CommandNamePlan:
+			runOptions.AutoApply = !hasValue || value != "false"
+		case name == "-destroy":
+			runOptions.IsDestroy = true
+		case name == "-refresh-only":
+			runOptions.RefreshOnly = true
+		case name == "-target" || name == "-replace":
+			if !hasValue && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			if name == "-target" {
+				runOptions.TargetAddrs = append(runOptions.TargetAddrs, value)
+			} else {
+				runOptions.ReplaceAddrs = append(runOptions.ReplaceAddrs, value)
+			}
+		case !strings.HasPrefix(arg, "-"):
+			return nil, errors.WithStackTrace(UnsupportedTerraformCloudCommand(command + " " + arg))
+		case !util.ListContainsElement(terraformCloudIgnoredArgs, name):
+			ignored = append(ignored, arg)
+		}
+	}
+	if len(ignored) > 0 {
+		terragruntOptions.Logger.Warnf("Ignoring the args %s, which the runs of Terraform Cloud do not support", strings.Join(ignored, " "))
+	}
+	return runOptions, nil
+}
+
+// terraformCloudVariables returns the given inputs as the terraform variables of a workspace: the strings as they are,
+// the other values in JSON, which is valid HCL.
+func terraformCloudVariables(inputs map[string]interface{}, sensitiveInputs []string) ([]*tfcloud.Variable, error) {
+	variables := make([]*tfcloud.Variable, 0, len(inputs))
+	for key, value := range inputs {
+		variable := &tfcloud.Variable{Key: key, Category: tfcloud.CategoryTerraform, Sensitive: util.ListContainsElement(sensitiveInputs, key)}
+		if str, ok := value.(string); ok {
+			variable.Value = str
+		} else {
+			content, err := json.Marshal(value)
+			if err != nil {
+				return nil, errors.WithStackTrace(err)
+			}
+			variable.Value, variable.HCL = string(content), true
+		}
+		variables = append(variables, variable)
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Key < variables[j].Key
+	})
+	return variables, nil
+}
+
+// Custom error types
+
+type UnsupportedTerraformCloudCommand string
+
+func (command UnsupportedTerraformCloudCommand) Error() string {
+	return fmt.Sprintf("The command %s is not supported by the execution backend terraform_cloud, which only runs plan, apply, destroy and output", string(command))
+}
+
+type MissingTerraformCloudToken string
+
+func (host MissingTerraformCloudToken) Error() string {
+	return fmt.Sprintf("No token to call the API of %s: set the %s env var, run `terraform login %s`, or set the %s env var", string(host), terraformCloudHostTokenEnv(string(host)), string(host), terraformCloudTokenEnv)
+}
+
+type ConfigurationNotUploaded string
+
+func (status ConfigurationNotUploaded) Error() string {
+	return fmt.Sprintf("the configuration version is %s instead of %s", string(status), tfcloud.ConfigurationStatusUploaded)
+}
+
+type TerraformCloudRunFailed struct {
+	Workspace string
+	RunID     string
+	Status    string
+	URL       string
+}
+
+func (err TerraformCloudRunFailed) Error() string {
+	return fmt.Sprintf("The run %s of the workspace %s finished with the status %s: %s", err.RunID, err.Workspace, err.Status, err.URL)
+}
+
+type TerraformCloudRunTimedOut struct {
+	Workspace string
+	RunID     string
+	Status    string
+	Timeout   time.Duration
+}
+
+func (err TerraformCloudRunTimedOut) Error() string {
+	return fmt.Sprintf("The run %s of the workspace %s did not finish in %s, it is still %s", err.RunID, err.Workspace, err.Timeout, err.Status)
+}
+
+type TerraformCloudRunNeedsConfirmation struct {
+	Workspace string
+	RunID     string
+	URL       string
+}
+
+func (err TerraformCloudRunNeedsConfirmation) Error() string {
+	return fmt.Sprintf("The run %s of the workspace %s waits for a confirmation to apply, and Terragrunt runs non-interactively: confirm or discard it in Terraform Cloud, %s, or pass -auto-approve", err.RunID, err.Workspace, err.URL)
+}
+
+type TerraformCloudRunDiscarded struct {
+	Workspace string
+	RunID     string
+}
+
+func (err TerraformCloudRunDiscarded) Error() string {
+	return fmt.Sprintf("Apply cancelled: the run %s of the workspace %s was discarded", err.RunID, err.Workspace)
+}
+
+type TerraformCloudOutputNotFound struct {
+	Name      string
+	Workspace string
+}
+
+func (err TerraformCloudOutputNotFound) Error() string {
+	return fmt.Sprintf("The state of the workspace %s has no output %s", err.Workspace, err.Name)
+}
@@ -0,0 +1,254 @@
+package shell
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/tfcloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTerraformCloud is a Terraform Cloud with the workspace vpc, whose runs go through the given statuses.
+type fakeTerraformCloud struct {
+	mu sync.Mutex
+	// The statuses of the run, the last one repeated
+	statuses   []string
+	reads      int
+	vars       map[string]interface{}
+	uploaded   []string
+	runRequest map[string]interface{}
+	// The actions taken on the run, e.g. apply or discard
+	runActions []string
+}
+
+func (api *fakeTerraformCloud) handler(serverURL func() string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		respond := func(data interface{}) {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v2/organizations/acme/workspaces/vpc", "PATCH /api/v2/workspaces/ws-1":
+			respond(map[string]interface{}{"id": "ws-1", "type": "workspaces", "attributes": map[string]interface{}{"name": "vpc"}})
+		case "GET /api/v2/workspaces/ws-1/vars":
+			respond([]interface{}{})
+		case "POST /api/v2/workspaces/ws-1/vars":
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			attributes := body.Data["attributes"].(map[string]interface{})
+			api.vars[attributes["key"].(string)] = attributes["value"]
+			respond(body.Data)
+		case "POST /api/v2/workspaces/ws-1/configuration-versions":
+			respond(map[string]interface{}{"id": "cv-1", "type": "configuration-versions", "attributes": map[string]interface{}{"status": "pending", "upload-url": serverURL() + "/upload/cv-1"}})
+		case "PUT /upload/cv-1":
+			api.uploaded = archiveFiles(r.Body)
+		case "GET /api/v2/configuration-versions/cv-1":
+			respond(map[string]interface{}{"id": "cv-1", "type": "configuration-versions", "attributes": map[string]interface{}{"status": tfcloud.ConfigurationStatusUploaded}})
+		case "POST /api/v2/runs":
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			api.runRequest = body.Data
+			respond(api.run("pending"))
+		case "GET /api/v2/runs/run-1":
+			status := api.statuses[len(api.statuses)-1]
+			if api.reads < len(api.statuses) {
+				status = api.statuses[api.reads]
+			}
+			api.reads++
+			respond(api.run(status))
+		case "POST /api/v2/runs/run-1/actions/apply", "POST /api/v2/runs/run-1/actions/discard":
+			api.runActions = append(api.runActions, filepath.Base(r.URL.Path))
+			w.WriteHeader(http.StatusAccepted)
+		case "GET /api/v2/plans/plan-1":
+			respond(map[string]interface{}{"id": "plan-1", "type": "plans", "attributes": map[string]interface{}{"log-read-url": serverURL() + "/logs/plan-1"}})
+		case "GET /api/v2/applies/apply-1":
+			respond(map[string]interface{}{"id": "apply-1", "type": "applies", "attributes": map[string]interface{}{"log-read-url": serverURL() + "/logs/apply-1"}})
+		case "GET /logs/plan-1":
+			_, _ = fmt.Fprintln(w, "Plan: 1 to add, 0 to change, 0 to destroy.")
+		case "GET /logs/apply-1":
+			_, _ = fmt.Fprintln(w, "Apply complete! Resources: 1 added, 0 changed, 0 destroyed.")
+		case "GET /api/v2/workspaces/ws-1/current-state-version-outputs":
+			respond([]interface{}{
+				map[string]interface{}{"id": "wsout-1", "type": "state-version-outputs", "attributes": map[string]interface{}{"name": "vpc_id", "sensitive": false, "detailed-type": "string", "value": "vpc-123"}},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func (api *fakeTerraformCloud) run(status string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         "run-1",
+		"type":       "runs",
+		"attributes": map[string]interface{}{"status": status, "actions": map[string]interface{}{"is-confirmable": status == "planned"}},
+		"relationships": map[string]interface{}{
+			"plan":  map[string]interface{}{"data": map[string]interface{}{"id": "plan-1", "type": "plans"}},
+			"apply": map[string]interface{}{"data": map[string]interface{}{"id": "apply-1", "type": "applies"}},
+		},
+	}
+}
+
+func archiveFiles(body io.Reader) []string {
+	gzipReader, err := gzip.NewReader(body)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			return files
+		}
+		if header.Typeflag == tar.TypeReg {
+			files = append(files, header.Name)
+		}
+	}
+}
+
+func newTestTerraformCloud(t *testing.T, statuses ...string) (*fakeTerraformCloud, *TerraformCloudExecutor, *options.TerragruntOptions) {
+	t.Helper()
+
+	api := &fakeTerraformCloud{statuses: statuses, vars: map[string]interface{}{}}
+	var server *httptest.Server
+	server = httptest.NewServer(api.handler(func() string { return server.URL }))
+	t.Cleanup(server.Close)
+
+	workingDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "main.tf"), []byte("# main\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(workingDir, ".terraform"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, ".terraform", "providers"), []byte("providers"), 0644))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = workingDir
+	terragruntOptions.Env = map[string]string{terraformCloudTokenEnv: "secret"}
+	terragruntOptions.TerraformCloudRun = &options.TerraformCloudRun{}
+
+	executor := &TerraformCloudExecutor{
+		Hostname:     server.URL,
+		Organization: "acme",
+		Workspace:    "vpc",
+		Inputs:       map[string]interface{}{"name": "main", "cidrs": []interface{}{"10.0.0.0/16"}},
+		Timeout:      time.Minute,
+		PollInterval: time.Millisecond,
+	}
+	return api, executor, terragruntOptions
+}
+
+func TestTerraformCloudExecutorApply(t *testing.T) {
+	t.Parallel()
+
+	api, executor, terragruntOptions := newTestTerraformCloud(t, "planning", "applying", tfcloud.RunStatusApplied)
+
+	var stdout bytes.Buffer
+	require.NoError(t, executor.RunTerraform(context.Background(), terragruntOptions, []string{"init"}, &stdout, io.Discard))
+	require.NoError(t, executor.RunTerraform(context.Background(), terragruntOptions, []string{"apply", "-auto-approve", "-target=aws_vpc.main"}, &stdout, io.Discard))
+
+	assert.Equal(t, "Plan: 1 to add, 0 to change, 0 to destroy.\nApply complete! Resources: 1 added, 0 changed, 0 destroyed.\n", stdout.String())
+	assert.Equal(t, map[string]interface{}{"name": "main", "cidrs": `["10.0.0.0/16"]`}, api.vars)
+	assert.Equal(t, []string{"main.tf"}, api.uploaded)
+
+	attributes := api.runRequest["attributes"].(map[string]interface{})
+	assert.Equal(t, true, attributes["auto-apply"])
+	assert.Equal(t, false, attributes["plan-only"])
+	assert.Equal(t, []interface{}{"aws_vpc.main"}, attributes["target-addrs"])
+
+	assert.Equal(t, "run-1", terragruntOptions.TerraformCloudRun.ID)
+	assert.Equal(t, tfcloud.RunStatusApplied, terragruntOptions.TerraformCloudRun.Status)
+	assert.Contains(t, terragruntOptions.TerraformCloudRun.URL, "/app/acme/workspaces/vpc/runs/run-1")
+
+	stdout.Reset()
+	require.NoError(t, executor.RunTerraform(context.Background(), terragruntOptions, []string{"output", "-json", "vpc_id"}, &stdout, io.Discard))
+	assert.Equal(t, "\"vpc-123\"\n", stdout.String())
+}
+
+func TestTerraformCloudExecutorApplyNeedsConfirmation(t *testing.T) {
+	t.Parallel()
+
+	api, executor, terragruntOptions := newTestTerraformCloud(t, "planning", "planned")
+	terragruntOptions.NonInteractive = true
+
+	var stdout bytes.Buffer
+	err := executor.RunTerraform(context.Background(), terragruntOptions, []string{"apply", "-input=false"}, &stdout, io.Discard)
+	var needsConfirmation TerraformCloudRunNeedsConfirmation
+	require.ErrorAs(t, errors.Unwrap(err), &needsConfirmation)
+	assert.Equal(t, "run-1", needsConfirmation.RunID)
+
+	// The run is left waiting for a confirmation in Terraform Cloud
+	assert.Equal(t, false, api.runRequest["attributes"].(map[string]interface{})["auto-apply"])
+	assert.Empty(t, api.runActions)
+	assert.Equal(t, "Plan: 1 to add, 0 to change, 0 to destroy.\n", stdout.String())
+}
+
+func TestTerraformCloudExecutorRunFailed(t *testing.T) {
+	t.Parallel()
+
+	_, executor, terragruntOptions := newTestTerraformCloud(t, "planning", tfcloud.RunStatusErrored)
+
+	err := executor.RunTerraform(context.Background(), terragruntOptions, []string{"plan"}, io.Discard, io.Discard)
+	require.Error(t, err)
+	var runFailed TerraformCloudRunFailed
+	require.ErrorAs(t, errors.Unwrap(err), &runFailed)
+	assert.Equal(t, tfcloud.RunStatusErrored, runFailed.Status)
+	assert.Equal(t, tfcloud.RunStatusErrored, terragruntOptions.TerraformCloudRun.Status)
+}
+
+func TestTerraformCloudExecutorUnsupportedCommand(t *testing.T) {
+	t.Parallel()
+
+	_, executor, terragruntOptions := newTestTerraformCloud(t, tfcloud.RunStatusApplied)
+
+	err := executor.RunTerraform(context.Background(), terragruntOptions, []string{"state", "list"}, io.Discard, io.Discard)
+	assert.Equal(t, UnsupportedTerraformCloudCommand("state"), errors.Unwrap(err))
+
+	err = executor.RunTerraform(context.Background(), terragruntOptions, []string{"apply", "out.tfplan"}, io.Discard, io.Discard)
+	assert.Equal(t, UnsupportedTerraformCloudCommand("apply out.tfplan"), errors.Unwrap(err))
+}
+
+func TestTerraformCloudRunOptions(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		args     []string
+		expected tfcloud.RunOptions
+	}{
+		{[]string{"plan"}, tfcloud.RunOptions{PlanOnly: true}},
+		{[]string{"plan", "-destroy"}, tfcloud.RunOptions{PlanOnly: true, IsDestroy: true}},
+		{[]string{"apply", "-input=false", "-replace", "aws_instance.web"}, tfcloud.RunOptions{ReplaceAddrs: []string{"aws_instance.web"}}},
+		{[]string{"apply", "-auto-approve", "-refresh-only"}, tfcloud.RunOptions{AutoApply: true, RefreshOnly: true}},
+		{[]string{"apply", "-auto-approve=false"}, tfcloud.RunOptions{}},
+		{[]string{"destroy", "-auto-approve", "-target=module.db"}, tfcloud.RunOptions{AutoApply: true, IsDestroy: true, TargetAddrs: []string{"module.db"}}},
+		{[]string{"destroy"}, tfcloud.RunOptions{IsDestroy: true}},
+	}
+
+	for _, testCase := range testCases {
+		runOptions, err := terraformCloudRunOptions(terragruntOptions, testCase.args)
+		require.NoError(t, err)
+		runOptions.Message = ""
+		assert.Equal(t, &testCase.expected, runOptions, testCase.args)
+	}
+}
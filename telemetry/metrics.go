@@ -11,6 +11,7 @@ import (
 
 	"github.com/gruntwork-io/go-commons/env"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
@@ -74,6 +75,14 @@ func Count(ctx context.Context, opts *options.TerragruntOptions, name string, va
 	counter.Add(ctx, value)
 }
 
+// CountCaches - add the hits and misses of the caches to the `cache_<name>_hits` and `cache_<name>_misses` counters
+func CountCaches(ctx context.Context, opts *options.TerragruntOptions) {
+	for cache, stats := range util.GetCacheStats() {
+		Count(ctx, opts, fmt.Sprintf("cache_%s_hits", cache), stats.Hits)
+		Count(ctx, opts, fmt.Sprintf("cache_%s_misses", cache), stats.Misses)
+	}
+}
+
 // configureMetricsCollection - configure the metrics collection
 func configureMetricsCollection(ctx context.Context, opts *TelemetryOptions) error {
 	exporter, err := newMetricsExporter(ctx, opts)
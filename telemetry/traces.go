@@ -35,7 +35,7 @@ func Trace(ctx context.Context, opts *options.TerragruntOptions, name string, at
 		return fn(ctx)
 	}
 
-	ctx, span := openSpan(ctx, name, attrs)
+	ctx, span := openSpan(ctx, name, withRunID(opts, attrs))
 	defer span.End()
 
 	if err := fn(ctx); err != nil {
@@ -45,6 +45,19 @@ func Trace(ctx context.Context, opts *options.TerragruntOptions, name string, at
 	return nil
 }
 
+// withRunID - add the ID of the run to the given attributes, so that the spans of a run can be correlated with its logs.
+func withRunID(opts *options.TerragruntOptions, attrs map[string]interface{}) map[string]interface{} {
+	if opts == nil || opts.RunID == "" {
+		return attrs
+	}
+	result := make(map[string]interface{}, len(attrs)+1)
+	for key, value := range attrs {
+		result[key] = value
+	}
+	result["runID"] = opts.RunID
+	return result
+}
+
 // configureTraceCollection - configure the traces collection
 func configureTraceCollection(ctx context.Context, opts *TelemetryOptions) error {
 	exp, err := newTraceExporter(ctx, opts)
@@ -5,6 +5,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
@@ -102,3 +103,12 @@ func TestNewTraceExporter(t *testing.T) {
 		})
 	}
 }
+
+func TestWithRunID(t *testing.T) {
+	attrs := map[string]interface{}{"path": "/live/vpc"}
+
+	assert.Equal(t, attrs, withRunID(&options.TerragruntOptions{}, attrs))
+	assert.Equal(t, map[string]interface{}{"path": "/live/vpc", "runID": "run-1"}, withRunID(&options.TerragruntOptions{RunID: "run-1"}, attrs))
+	// The attributes of the caller are not changed
+	assert.Equal(t, map[string]interface{}{"path": "/live/vpc"}, attrs)
+}
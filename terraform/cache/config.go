@@ -4,6 +4,9 @@ import (
 	"net"
 	"strconv"
 	"time"
+
+	"github.com/gruntwork-io/terragrunt/remotecache"
+	"github.com/gruntwork-io/terragrunt/util"
 )
 
 const (
@@ -66,6 +69,22 @@ func WithDisablePartialLockFile(completeLock bool) Option {
 	}
 }
 
+// WithDownloadRetry sets how the provider downloads are retried and fall back to the mirrors.
+func WithDownloadRetry(downloadRetry util.DownloadRetry) Option {
+	return func(cfg Config) Config {
+		cfg.downloadRetry = downloadRetry
+		return cfg
+	}
+}
+
+// WithRemoteCache sets the remote cache the archives of the providers are shared through between machines.
+func WithRemoteCache(remoteCache remotecache.Backend) Option {
+	return func(cfg Config) Config {
+		cfg.remoteCache = remoteCache
+		return cfg
+	}
+}
+
 type Config struct {
 	hostname        string
 	port            int
@@ -76,6 +95,8 @@ type Config struct {
 	providerCacheDir       string
 	providerArchiveDir     string
 	disablePartialLockFile bool
+	downloadRetry          util.DownloadRetry
+	remoteCache            remotecache.Backend
 }
 
 func NewConfig(opts ...Option) *Config {
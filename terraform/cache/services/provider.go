@@ -12,6 +12,7 @@ import (
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/pkg/log"
+	"github.com/gruntwork-io/terragrunt/remotecache"
 	"github.com/gruntwork-io/terragrunt/terraform/cache/models"
 	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/hashicorp/go-getter/v2"
@@ -25,9 +26,6 @@ const (
 
 	retryDelayLockFile = time.Second * 5
 	maxRetriesLockFile = 60
-
-	retryDelayFetchFile = time.Second * 2
-	maxRetriesFetchFile = 5
 )
 
 // Borrow the "unpack a zip cache into a target directory" logic from go-getter
@@ -110,15 +108,22 @@ func (cache *ProviderCache) warmUp(ctx context.Context) error {
 	}
 
 	if needCacheArchives && downloadURL != "" && !util.FileExists(archiveFilename) {
-		if err := util.DoWithRetry(ctx, fmt.Sprintf("Fetching provider %q", cache.Provider), maxRetriesFetchFile, retryDelayFetchFile, logrus.DebugLevel, func() error {
-			return util.FetchFile(ctx, downloadURL, archiveFilename)
-		}); err != nil {
-			os.Remove(archiveFilename) //nolint:errcheck
-			return err
+		if !cache.fetchArchiveFromRemoteCache(ctx) {
+			downloadRetry := cache.downloadRetry
+			if err := downloadRetry.Do(ctx, fmt.Sprintf("Fetching provider %q", cache.Provider), downloadRetry.URLs(downloadURL), func(downloadURL string) error {
+				return util.FetchFile(ctx, downloadURL, archiveFilename)
+			}); err != nil {
+				os.Remove(archiveFilename) //nolint:errcheck
+				return err
+			}
+			cache.storeArchiveInRemoteCache(ctx)
 		}
 		cache.archiveCached = true
 	}
 
+	// The provider is a hit once in the cache dir, or in the user plugins dir, of any Terragrunt process
+	util.CountCacheLookup(util.ProviderCache, unpackedCached)
+
 	if !unpackedCached {
 		log.Debugf("Unpack provider archive %s", archiveFilename)
 
@@ -130,6 +135,42 @@ func (cache *ProviderCache) warmUp(ctx context.Context) error {
 	return nil
 }
 
+func (cache *ProviderCache) remoteCacheKey() string {
+	return remotecache.Key(remotecache.KindProvider, cache.Provider.Filename()+path.Ext(cache.downloadURL()))
+}
+
+// fetchArchiveFromRemoteCache fetches the archive of the provider from the remote cache, if any, and returns false if
+// the remote cache does not have it, or fetching it failed, so that it is downloaded from the registry instead.
+func (cache *ProviderCache) fetchArchiveFromRemoteCache(ctx context.Context) bool {
+	if cache.remoteCache == nil {
+		return false
+	}
+
+	hit, err := remotecache.FetchFile(ctx, cache.remoteCache, cache.remoteCacheKey(), cache.ArchiveFilename())
+	if err != nil {
+		log.Warnf("Failed to fetch the provider %s from the remote cache, downloading it: %v", cache.Provider, err)
+		hit = false
+	}
+	util.CountCacheLookup(util.RemoteCache, hit)
+	if hit {
+		log.Debugf("Fetched the provider %s from the remote cache", cache.Provider)
+	}
+	return hit
+}
+
+// storeArchiveInRemoteCache stores the archive of the provider downloaded from the registry in the remote cache, if any.
+func (cache *ProviderCache) storeArchiveInRemoteCache(ctx context.Context) {
+	if cache.remoteCache == nil {
+		return
+	}
+
+	if err := remotecache.StoreFile(ctx, cache.remoteCache, cache.remoteCacheKey(), cache.ArchiveFilename()); err != nil {
+		log.Warnf("Failed to store the provider %s in the remote cache: %v", cache.Provider, err)
+		return
+	}
+	log.Debugf("Stored the provider %s in the remote cache", cache.Provider)
+}
+
 func (cache *ProviderCache) removeArchive() error {
 	var archiveFilename = cache.ArchiveFilename()
 
@@ -180,15 +221,23 @@ type ProviderService struct {
 
 	// If needCacheArchives is true, ensures that not only the unarchived binary is cached, but also its archive. We need acrhives in order to reduce the bandwidth, because `terraform lock provider` always loads providers from a remote registry to create a lock file rather than using a cached one. This is only used when opts.ProviderCompleteLock is true.
 	needCacheArchives bool
+
+	// How the provider downloads are retried and fall back to the mirrors.
+	downloadRetry util.DownloadRetry
+
+	// The remote cache the archives of the providers are shared through between machines, nil if not enabled.
+	remoteCache remotecache.Backend
 }
 
-func NewProviderService(baseCacheDir, baseArchiveDir, baseUserProviderDir string, needCacheArchives bool) *ProviderService {
+func NewProviderService(baseCacheDir, baseArchiveDir, baseUserProviderDir string, needCacheArchives bool, downloadRetry util.DownloadRetry, remoteCache remotecache.Backend) *ProviderService {
 	return &ProviderService{
 		baseCacheDir:          baseCacheDir,
 		baseArchiveDir:        baseArchiveDir,
 		baseUserProviderDir:   baseUserProviderDir,
 		providerCacheWarmUpCh: make(chan *ProviderCache),
 		needCacheArchives:     needCacheArchives,
+		downloadRetry:         downloadRetry,
+		remoteCache:           remoteCache,
 	}
 }
 
@@ -204,6 +253,7 @@ func (service *ProviderService) CacheProvider(ctx context.Context, provider *mod
 	defer service.cacheMu.Unlock()
 
 	if cache := service.providerCaches.Find(provider); cache != nil {
+		util.CountCacheLookup(util.ProviderCache, true)
 		return
 	}
 
@@ -1,6 +1,7 @@
 package cliconfig
 
 import (
+	"bytes"
 	"os"
 	"regexp"
 
@@ -63,11 +64,29 @@ func (cfg *Config) SetProviderInstallation(filesystemMethod *ProviderInstallatio
 	cfg.ProviderInstallation = providerInstallation
 }
 
+// SetProviderInstallationNetworkMirror sets the network mirror installation method, keeping the other methods, https://developer.hashicorp.com/terraform/cli/config/config-file#network_mirror
+//
+//	provider_installation {
+//		network_mirror {
+//			url = "https://terraform.example.com/providers/"
+//		}
+//	}
+func (cfg *Config) SetProviderInstallationNetworkMirror(networkMethod *ProviderInstallationNetworkMirror) {
+	if cfg.ProviderInstallation == nil {
+		cfg.ProviderInstallation = &ProviderInstallation{}
+	}
+	cfg.ProviderInstallation.NetworkMirror = networkMethod
+}
+
 // Save marshalls and saves CLI config with the given config path.
 func (cfg *Config) Save(configPath string) error {
 	rawHCL := cfg.rawHCL
 	// Since `Config` structure already has `plugin_cache_dir`, remove it from the raw HCL config to prevent repeating in the saved file.
 	rawHCL = configParamPluginCacheDirReg.ReplaceAll(rawHCL, []byte{})
+	// Make sure the new sections start on a new line if the user config doesn't end with one.
+	if len(rawHCL) > 0 && !bytes.HasSuffix(rawHCL, []byte("\n")) {
+		rawHCL = append(rawHCL, '\n')
+	}
 
 	newHCL, err := dethcl.Marshal(cfg)
 	if err != nil {
@@ -90,3 +90,35 @@ func TestConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigNetworkMirror(t *testing.T) {
+	t.Parallel()
+
+	configFile := filepath.Join(t.TempDir(), ".terraformrc")
+
+	config := &Config{rawHCL: []byte(`
+  disable_checkpoint = true`)}
+	config.AddHost("registry.terraform.io", map[string]any{
+		"modules.v1": "https://mirror.example.com/modules/v1/",
+	})
+	config.SetProviderInstallationNetworkMirror(NewProviderInstallationNetworkMirror("https://mirror.example.com/providers/", nil, nil))
+
+	err := config.Save(configFile)
+	assert.NoError(t, err)
+
+	hclBytes, err := os.ReadFile(configFile)
+	assert.NoError(t, err)
+
+	assert.Equal(t, `
+  disable_checkpoint = true
+  host "registry.terraform.io" {
+    services  = {
+      modules.v1 = "https://mirror.example.com/modules/v1/"
+    }
+  }
+  provider_installation {
+    network_mirror {
+      url = "https://mirror.example.com/providers/"
+    }
+  }`, string(hclBytes))
+}
@@ -21,6 +21,21 @@ func NewProviderInstallationFilesystemMirror(location string, include, exclude [
 	}
 }
 
+type ProviderInstallationNetworkMirror struct {
+	URL string `hcl:"url"`
+	*ProviderInstallationMethod
+}
+
+func NewProviderInstallationNetworkMirror(url string, include, exclude []string) *ProviderInstallationNetworkMirror {
+	return &ProviderInstallationNetworkMirror{
+		URL: url,
+		ProviderInstallationMethod: &ProviderInstallationMethod{
+			Include: include,
+			Exclude: exclude,
+		},
+	}
+}
+
 type ProviderInstallationDirect struct {
 	*ProviderInstallationMethod
 }
@@ -37,5 +52,6 @@ func NewProviderInstallationDirect(include, exclude []string) *ProviderInstallat
 // ProviderInstallation is the structure of the "provider_installation" nested block within the CLI configuration.
 type ProviderInstallation struct {
 	FilesystemMirror *ProviderInstallationFilesystemMirror `hcl:"filesystem_mirror"`
+	NetworkMirror    *ProviderInstallationNetworkMirror    `hcl:"network_mirror"`
 	Direct           *ProviderInstallationDirect           `hcl:"direct"`
 }
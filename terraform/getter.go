@@ -71,6 +71,11 @@ type RegistryServicePath struct {
 type RegistryGetter struct {
 	client            *getter.Client
 	TerragruntOptions *options.TerragruntOptions
+	// The client of the requests to the registries, the default client if nil.
+	HTTPClient *http.Client
+	// The base URLs of the module registry APIs to use instead of the ones found with service discovery, by registry
+	// host, e.g. to download the modules of the public registry from a mirror.
+	ModuleMirrors map[string]string
 }
 
 // SetClient allows the getter to know what getter client (different from the underlying HTTP client) to use for
@@ -87,6 +92,20 @@ func (tfrGetter *RegistryGetter) Context() context.Context {
 	return tfrGetter.client.Ctx
 }
 
+func (tfrGetter *RegistryGetter) httpClient() *http.Client {
+	if tfrGetter.HTTPClient != nil {
+		return tfrGetter.HTTPClient
+	}
+	return httpClient
+}
+
+func (tfrGetter *RegistryGetter) checkOfflineFetch(what string, host string) error {
+	if tfrGetter.TerragruntOptions == nil {
+		return nil
+	}
+	return tfrGetter.TerragruntOptions.CheckOfflineFetch(what, host)
+}
+
 // registryDomain returns the default registry domain to use for the getter.
 func (tfrGetter *RegistryGetter) registryDomain() string {
 	if tfrGetter.TerragruntOptions == nil {
@@ -133,17 +152,27 @@ func (tfrGetter *RegistryGetter) Get(dstPath string, srcURL *url.URL) error {
 	}
 	version := versionList[0]
 
-	moduleRegistryBasePath, err := getModuleRegistryURLBasePath(ctx, registryDomain)
-	if err != nil {
+	moduleRegistryBasePath, hasMirror := tfrGetter.ModuleMirrors[registryDomain]
+	registryHost := registryDomain
+	if hasMirror {
+		registryHost = options.FetchHost(moduleRegistryBasePath)
+	}
+	if err := tfrGetter.checkOfflineFetch("the registry module "+strings.Trim(modulePath, "/"), registryHost); err != nil {
 		return err
 	}
+	if !hasMirror {
+		var err error
+		if moduleRegistryBasePath, err = getModuleRegistryURLBasePath(ctx, tfrGetter.httpClient(), registryDomain); err != nil {
+			return err
+		}
+	}
 
 	moduleURL, err := buildRequestUrl(registryDomain, moduleRegistryBasePath, modulePath, version)
 	if err != nil {
 		return err
 	}
 
-	terraformGet, err := getTerraformGetHeader(ctx, *moduleURL)
+	terraformGet, err := getTerraformGetHeader(ctx, tfrGetter.httpClient(), *moduleURL)
 	if err != nil {
 		return err
 	}
@@ -152,6 +181,9 @@ func (tfrGetter *RegistryGetter) Get(dstPath string, srcURL *url.URL) error {
 	if err != nil {
 		return err
 	}
+	if err := tfrGetter.checkOfflineFetch("the code of the registry module "+strings.Trim(modulePath, "/")+" at "+downloadURL, options.FetchHost(downloadURL)); err != nil {
+		return err
+	}
 
 	// If there is a subdir component, then we download the root separately into a temporary directory, then copy over
 	// the proper subdir. Note that we also have to take into account sub dirs in the original URL in addition to the
@@ -237,13 +269,13 @@ func (tfrGetter *RegistryGetter) getSubdir(ctx context.Context, dstPath, sourceU
 // (https://www.terraform.io/docs/internals/remote-service-discovery.html)
 // to figure out where the modules are stored. This will return the base
 // path where the modules can be accessed
-func getModuleRegistryURLBasePath(ctx context.Context, domain string) (string, error) {
+func getModuleRegistryURLBasePath(ctx context.Context, client *http.Client, domain string) (string, error) {
 	sdURL := url.URL{
 		Scheme: "https",
 		Host:   domain,
 		Path:   serviceDiscoveryPath,
 	}
-	bodyData, _, err := httpGETAndGetResponse(ctx, sdURL)
+	bodyData, _, err := httpGETAndGetResponse(ctx, client, sdURL)
 	if err != nil {
 		return "", err
 	}
@@ -258,8 +290,8 @@ func getModuleRegistryURLBasePath(ctx context.Context, domain string) (string, e
 
 // getTerraformGetHeader makes an http GET call to the given registry URL and return the contents of location json
 // body or the header X-Terraform-Get. This function will return an error if the response does not contain the header.
-func getTerraformGetHeader(ctx context.Context, url url.URL) (string, error) {
-	body, header, err := httpGETAndGetResponse(ctx, url)
+func getTerraformGetHeader(ctx context.Context, client *http.Client, url url.URL) (string, error) {
+	body, header, err := httpGETAndGetResponse(ctx, client, url)
 	if err != nil {
 		details := "error receiving HTTP data"
 		return "", errors.WithStackTrace(ModuleDownloadErr{sourceURL: url.String(), details: details})
@@ -307,9 +339,9 @@ func getDownloadURLFromHeader(moduleURL url.URL, terraformGet string) (string, e
 	return terraformGet, nil
 }
 
-// httpGETAndGetResponse is a helper function to make a GET request to the given URL using the http client. This
+// httpGETAndGetResponse is a helper function to make a GET request to the given URL using the given http client. This
 // function will then read the response and return the contents + the response header.
-func httpGETAndGetResponse(ctx context.Context, getURL url.URL) ([]byte, *http.Header, error) {
+func httpGETAndGetResponse(ctx context.Context, client *http.Client, getURL url.URL) ([]byte, *http.Header, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", getURL.String(), nil)
 	if err != nil {
 		return nil, nil, errors.WithStackTrace(err)
@@ -322,7 +354,7 @@ func httpGETAndGetResponse(ctx context.Context, getURL url.URL) ([]byte, *http.H
 		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", authToken))
 	}
 
-	resp, err := httpClient.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, nil, errors.WithStackTrace(err)
 	}
@@ -0,0 +1,49 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// InstalledModule is a module terraform installed in the data dir, as recorded in its manifest.
+type InstalledModule struct {
+	// The address of the module call, e.g. `vpc.endpoints`, empty for the root module.
+	Key     string `json:"Key"`
+	Source  string `json:"Source"`
+	Version string `json:"Version"`
+	// The dir of the module code, relative to the working dir.
+	Dir string `json:"Dir"`
+}
+
+// IsLocal returns true if the module is in a subdir of the module calling it, rather than fetched from its own source.
+func (module InstalledModule) IsLocal() bool {
+	return module.Source == "" || strings.HasPrefix(module.Source, "./") || strings.HasPrefix(module.Source, "../")
+}
+
+// InstalledModules returns the modules terraform installed in the data dir, read from its manifest, or nil if terraform
+// did not install any modules yet.
+func InstalledModules(terragruntOptions *options.TerragruntOptions) ([]InstalledModule, error) {
+	manifestFile := filepath.Join(terragruntOptions.DataDir(), "modules", "modules.json")
+	if !util.FileExists(manifestFile) {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(manifestFile)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var manifest struct {
+		Modules []InstalledModule `json:"Modules"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return manifest.Modules, nil
+}
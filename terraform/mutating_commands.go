@@ -0,0 +1,57 @@
+package terraform
+
+// The terraform commands that change the infrastructure or the state.
+var mutatingCommands = []string{
+	CommandNameApply,
+	CommandNameDestroy,
+	CommandNameImport,
+	CommandNameTaint,
+	CommandNameUntaint,
+	CommandNameForceUnlock,
+	"refresh",
+	"push",
+}
+
+// The subcommands that change the state, by the commands they belong to.
+var mutatingSubcommands = map[string][]string{
+	CommandNameState: {"mv", "rm", "push", "replace-provider"},
+	"workspace":      {"new", "delete"},
+}
+
+// The flags of `init` that change the state, by migrating or copying it to a new backend.
+var mutatingInitFlags = []string{"-migrate-state", "-force-copy"}
+
+// IsMutatingCommand returns true if the terraform command given by args changes the infrastructure or the state, such as
+// `apply`, `import` or `state rm`.
+func IsMutatingCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	command := args[0]
+
+	for _, mutatingCommand := range mutatingCommands {
+		if command == mutatingCommand {
+			return true
+		}
+	}
+
+	if subcommands, ok := mutatingSubcommands[command]; ok && len(args) > 1 {
+		for _, subcommand := range subcommands {
+			if args[1] == subcommand {
+				return true
+			}
+		}
+	}
+
+	if command == CommandNameInit {
+		for _, arg := range args[1:] {
+			for _, flag := range mutatingInitFlags {
+				if arg == flag || arg == flag+"=true" {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
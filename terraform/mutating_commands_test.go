@@ -0,0 +1,42 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMutatingCommand(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		args     string
+		expected bool
+	}{
+		{"", false},
+		{"plan -out=tfplan", false},
+		{"plan -destroy", false},
+		{"output -json", false},
+		{"state list", false},
+		{"state show aws_instance.foo", false},
+		{"workspace select prod", false},
+		{"init", false},
+		{"init -migrate-state=false", false},
+		{"apply -auto-approve", true},
+		{"apply tfplan", true},
+		{"destroy", true},
+		{"import aws_instance.foo i-123", true},
+		{"refresh", true},
+		{"taint aws_instance.foo", true},
+		{"force-unlock 123", true},
+		{"state rm aws_instance.foo", true},
+		{"state mv aws_instance.foo aws_instance.bar", true},
+		{"workspace new prod", true},
+		{"init -migrate-state", true},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, IsMutatingCommand(strings.Fields(testCase.args)), testCase.args)
+	}
+}
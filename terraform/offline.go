@@ -0,0 +1,83 @@
+package terraform
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/hashicorp/terraform/registry/regsrc"
+)
+
+// CheckOfflineModules returns an error if offline mode forbids fetching one of the modules called by the terraform code
+// of the working dir that terraform did not install in the data dir yet.
+func CheckOfflineModules(terragruntOptions *options.TerragruntOptions) error {
+	if !terragruntOptions.Offline {
+		return nil
+	}
+
+	module, diags := tfconfig.LoadModule(terragruntOptions.WorkingDir)
+	if diags.HasErrors() {
+		// terraform reports the errors of the code itself
+		return nil
+	}
+
+	installedModules, err := installedModuleKeys(terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range module.ModuleCalls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		call := module.ModuleCalls[name]
+		if installedModules[call.Name] {
+			continue
+		}
+		if err := terragruntOptions.CheckOfflineFetch("the module "+call.Name+" from "+call.Source, ModuleSourceHost(terragruntOptions, call.Source)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ModuleSourceHost returns the host terraform fetches the module with the given source from, or an empty string for the
+// local modules.
+func ModuleSourceHost(terragruntOptions *options.TerragruntOptions, source string) string {
+	if strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") {
+		return ""
+	}
+
+	if module, err := regsrc.ParseModuleSource(source); err == nil {
+		if module.RawHost != nil {
+			return module.RawHost.Normalized()
+		}
+		return (&RegistryGetter{TerragruntOptions: terragruntOptions}).registryDomain()
+	}
+
+	sourceURL, err := getter.Detect(source, terragruntOptions.WorkingDir, getter.Detectors)
+	if err != nil {
+		// terraform reports the invalid sources itself
+		return ""
+	}
+	return options.FetchHost(sourceURL)
+}
+
+// installedModuleKeys returns the keys of the modules terraform installed in the data dir, read from its manifest.
+func installedModuleKeys(terragruntOptions *options.TerragruntOptions) (map[string]bool, error) {
+	modules, err := InstalledModules(terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for _, module := range modules {
+		keys[module.Key] = true
+	}
+	return keys, nil
+}
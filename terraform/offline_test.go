@@ -0,0 +1,84 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestModuleSourceHost(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		source       string
+		expectedHost string
+	}{
+		{"./modules/vpc", ""},
+		{"../vpc", ""},
+		{"/opt/modules/vpc", ""},
+		{"terraform-aws-modules/vpc/aws", "registry.terraform.io"},
+		{"app.terraform.io/example-corp/k8s-cluster/azurerm", "app.terraform.io"},
+		{"github.com/hashicorp/example", "github.com"},
+		{"git@github.com:hashicorp/example.git", "github.com"},
+		{"git::https://example.com/vpc.git?ref=v1.2.0", "example.com"},
+		{"https://example.com/vpc-module.zip", "example.com"},
+		{"s3::https://s3-eu-west-1.amazonaws.com/examplecorp-terraform-modules/vpc.zip", "s3-eu-west-1.amazonaws.com"},
+	}
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.source, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expectedHost, ModuleSourceHost(terragruntOptions, testCase.source))
+		})
+	}
+}
+
+func TestCheckOfflineModules(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	code := `
+module "local" {
+  source = "./local"
+}
+
+module "installed" {
+  source = "terraform-aws-modules/vpc/aws"
+}
+
+module "remote" {
+  source = "git::https://example.com/remote.git"
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, "main.tf"), []byte(code), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(workingDir, ".terraform", "modules"), os.ModePerm))
+	manifest := `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"installed","Source":"registry.terraform.io/terraform-aws-modules/vpc/aws","Dir":".terraform/modules/installed"}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(workingDir, ".terraform", "modules", "modules.json"), []byte(manifest), 0644))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(workingDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = workingDir
+
+	require.NoError(t, CheckOfflineModules(terragruntOptions))
+
+	terragruntOptions.Offline = true
+	err = CheckOfflineModules(terragruntOptions)
+	var notAllowed options.OfflineFetchNotAllowed
+	require.ErrorAs(t, errors.Unwrap(err), &notAllowed)
+	assert.Equal(t, "example.com", notAllowed.Host)
+	assert.Contains(t, notAllowed.What, "remote")
+
+	terragruntOptions.OfflineAllowedHosts = []string{"*.com"}
+	require.NoError(t, CheckOfflineModules(terragruntOptions))
+}
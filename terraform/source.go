@@ -24,6 +24,10 @@ var (
 
 const matchCount = 2
 
+// SourceVersionParams are the query params the terraform sources are pinned to a version with: `ref` for the git and the
+// url sources and `version` for the registry sources.
+var SourceVersionParams = []string{"ref", "version"}
+
 // This struct represents information about Terraform source code that needs to be downloaded
 type Source struct {
 	// A canonical version of RawSource, in URL format
@@ -293,3 +297,22 @@ func getForcedGetter(sourceUrl string) (string, string) {
 
 	return "", sourceUrl
 }
+
+// SourceVersion returns the query param the given terraform source is pinned with and the version it is pinned to, or
+// empty strings if the source is not pinned.
+func SourceVersion(source string) (string, string) {
+	index := strings.LastIndex(source, "?")
+	if index < 0 {
+		return "", ""
+	}
+	query, err := url.ParseQuery(source[index+1:])
+	if err != nil {
+		return "", ""
+	}
+	for _, param := range SourceVersionParams {
+		if version := query.Get(param); version != "" {
+			return param, version
+		}
+	}
+	return "", ""
+}
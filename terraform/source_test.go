@@ -110,3 +110,25 @@ func TestRegressionSupportForGitRemoteCodecommit(t *testing.T) {
 	require.Equal(t, "git::codecommit::ap-northeast-1://my_app_modules", actualRootRepo.String())
 	require.Equal(t, "my-app/modules/main-module", actualModulePath)
 }
+
+func TestSourceVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		source          string
+		expectedParam   string
+		expectedVersion string
+	}{
+		{"git::https://example.com/modules.git//vpc?ref=v1.2.0", "ref", "v1.2.0"},
+		{"tfr:///terraform-aws-modules/vpc/aws?version=5.1.0", "version", "5.1.0"},
+		{"git::https://example.com/modules.git//vpc?depth=1&ref=main", "ref", "main"},
+		{"git::https://example.com/modules.git//vpc", "", ""},
+		{"../modules//vpc", "", ""},
+	}
+
+	for _, testCase := range testCases {
+		param, version := SourceVersion(testCase.source)
+		assert.Equal(t, testCase.expectedParam, param, testCase.source)
+		assert.Equal(t, testCase.expectedVersion, version, testCase.source)
+	}
+}
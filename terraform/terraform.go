@@ -14,6 +14,7 @@ const (
 	CommandNameDestroy        = "destroy"
 	CommandNameValidate       = "validate"
 	CommandNameOutput         = "output"
+	CommandNameShow           = "show"
 	CommandNameProviders      = "providers"
 	CommandNameState          = "state"
 	CommandNameLock           = "lock"
@@ -22,16 +23,34 @@ const (
 	CommandNameUntaint        = "untaint"
 	CommandNameConsole        = "console"
 	CommandNameForceUnlock    = "force-unlock"
+	CommandNameWorkspace      = "workspace"
+	CommandNameRefresh        = "refresh"
+	CommandNamePull           = "pull"
 
 	FlagNameNoColor = "-no-color"
 	// `apply -destroy` is alias for `destroy`
 	FlagNameDestroy = "-destroy"
+	// `plan -detailed-exitcode` exits with DetailedExitCodeChanges when the plan has changes
+	FlagNameDetailedExitCode = "-detailed-exitcode"
+	// `init -backend=false` initializes the module without its backend
+	FlagNameBackendFalse = "-backend=false"
+	// `plan -parallelism=N` and `apply -parallelism=N` limit the concurrent operations of terraform
+	FlagNameParallelism = "-parallelism"
+	// `plan -lock-timeout=DURATION` waits for the state lock, and `plan -lock=false` doesn't lock the state
+	FlagNameLockTimeout = "-lock-timeout"
+	FlagNameLockFalse   = "-lock=false"
+
+	DetailedExitCodeChanges = 2
 
 	EnvNameTFCLIConfigFile                         = "TF_CLI_CONFIG_FILE"
 	EnvNameTFPluginCacheDir                        = "TF_PLUGIN_CACHE_DIR"
 	EnvNameTFPluginCacheMayBreakDependencyLockFile = "TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE"
 	EnvNameTFTokenFmt                              = "TF_TOKEN_%s"
 	EnvNameTFVarFmt                                = "TF_VAR_%s"
+	EnvNameTFWorkspace                             = "TF_WORKSPACE"
+
+	// The workspace terraform selects when no other workspace is selected
+	DefaultWorkspace = "default"
 
 	TerraformLockFile = ".terraform.lock.hcl"
 
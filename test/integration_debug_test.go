@@ -260,6 +260,8 @@ func TestRenderJSONConfig(t *testing.T) {
 					"path":              "provider.tf",
 					"comment_prefix":    "# ",
 					"disable_signature": false,
+					"source":            "",
+					"checksum":          "",
 					"disable":           false,
 					"if_exists":         "overwrite_terragrunt",
 					"contents": `provider "aws" {
@@ -362,6 +364,8 @@ func TestRenderJSONConfigWithIncludesDependenciesAndLocals(t *testing.T) {
 					"path":              "provider.tf",
 					"comment_prefix":    "# ",
 					"disable_signature": false,
+					"source":            "",
+					"checksum":          "",
 					"disable":           false,
 					"if_exists":         "overwrite",
 					"contents":          "# This is just a test",
@@ -3601,6 +3601,8 @@ func TestReadTerragruntConfigFull(t *testing.T) {
 				"if_exists":         "overwrite_terragrunt",
 				"comment_prefix":    "# ",
 				"disable_signature": false,
+				"source":            "",
+				"checksum":          "",
 				"disable":           false,
 				"contents": `provider "aws" {
   region = "us-east-1"
@@ -5449,6 +5451,8 @@ func TestRenderJsonMetadataIncludes(t *testing.T) {
 				"comment_prefix":    "# ",
 				"contents":          "# test\n",
 				"disable_signature": false,
+				"source":            "",
+				"checksum":          "",
 				"disable":           false,
 				"if_exists":         "overwrite",
 				"path":              "provider.tf",
@@ -0,0 +1,642 @@
+// Package tfcloud is a client of the API of Terraform Cloud and Terraform Enterprise, managing the workspaces the
+// modules run in, their variables and their runs.
+package tfcloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// DefaultHostname is the hostname of Terraform Cloud.
+const DefaultHostname = "app.terraform.io"
+
+// The categories of the variables of a workspace.
+const (
+	CategoryTerraform = "terraform"
+	CategoryEnv       = "env"
+)
+
+// The statuses of a run the API reports once the run is finished.
+const (
+	RunStatusApplied            = "applied"
+	RunStatusPlannedAndFinished = "planned_and_finished"
+	RunStatusErrored            = "errored"
+	RunStatusDiscarded          = "discarded"
+	RunStatusCanceled           = "canceled"
+	RunStatusForceCanceled      = "force_canceled"
+	RunStatusPolicySoftFailed   = "policy_soft_failed"
+)
+
+// The statuses of a configuration version.
+const (
+	ConfigurationStatusUploaded = "uploaded"
+	ConfigurationStatusErrored  = "errored"
+)
+
+const mediaType = "application/vnd.api+json"
+
+// How long a single request to the API may take.
+const requestTimeout = time.Minute
+
+// Client calls the API of the given Terraform Cloud or Terraform Enterprise host with a user, team or organization
+// token.
+type Client struct {
+	address    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient returns a client of the API of the given host, e.g. app.terraform.io, or of the given address, e.g.
+// https://tfe.example.com, to reach a host on another scheme or port.
+func NewClient(host string, token string) *Client {
+	address := host
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	return &Client{address: strings.TrimSuffix(address, "/"), token: token, httpClient: &http.Client{Timeout: requestTimeout}}
+}
+
+// Workspace is a workspace of an organization.
+type Workspace struct {
+	ID   string
+	Name string
+}
+
+// WorkspaceOptions are the settings of a workspace terragrunt creates or updates. The empty settings are left as they
+// are, or as the organization defaults them.
+type WorkspaceOptions struct {
+	Name             string
+	ProjectID        string
+	TerraformVersion string
+	WorkingDirectory string
+	Tags             []string
+	// The repo the workspace plans the commits of, or nil for a CLI-driven workspace, which plans the uploaded
+	// configurations
+	VCSRepo *VCSRepo
+}
+
+// VCSRepo is the repo of a VCS-driven workspace.
+type VCSRepo struct {
+	Identifier   string
+	Branch       string
+	OAuthTokenID string
+}
+
+// Variable is a variable of a workspace. The API does not return the values of the sensitive variables.
+type Variable struct {
+	ID        string
+	Key       string
+	Value     string
+	Category  string
+	HCL       bool
+	Sensitive bool
+}
+
+// ConfigurationVersion is an upload of the configuration a workspace plans.
+type ConfigurationVersion struct {
+	ID        string
+	Status    string
+	UploadURL string
+}
+
+// RunOptions are the settings of a run terragrunt triggers.
+type RunOptions struct {
+	WorkspaceID string
+	// The uploaded configuration the run plans, or empty to plan the latest commit of the repo of a VCS-driven workspace
+	ConfigurationVersionID string
+	Message                string
+	IsDestroy              bool
+	PlanOnly               bool
+	AutoApply              bool
+	RefreshOnly            bool
+	TargetAddrs            []string
+	ReplaceAddrs           []string
+}
+
+// Run is a run of a workspace.
+type Run struct {
+	ID         string
+	Status     string
+	HasChanges bool
+	// The run was planned and waits for a confirmation to apply.
+	IsConfirmable bool
+	PlanID        string
+	ApplyID       string
+}
+
+// IsFinished returns true if the run will not change status anymore, or waits for an override of a policy.
+func (run *Run) IsFinished() bool {
+	switch run.Status {
+	case RunStatusApplied, RunStatusPlannedAndFinished, RunStatusErrored, RunStatusDiscarded, RunStatusCanceled, RunStatusForceCanceled, RunStatusPolicySoftFailed:
+		return true
+	}
+	return false
+}
+
+// Succeeded returns true if the run planned, and applied if it was not a plan-only run, without errors.
+func (run *Run) Succeeded() bool {
+	return run.Status == RunStatusApplied || run.Status == RunStatusPlannedAndFinished
+}
+
+// Output is an output of the current state of a workspace.
+type Output struct {
+	Name      string          `json:"name"`
+	Sensitive bool            `json:"sensitive"`
+	Type      json.RawMessage `json:"detailed-type"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// ReadWorkspace returns the workspace with the given name, or a WorkspaceNotFound error if the organization has none.
+func (client *Client) ReadWorkspace(ctx context.Context, organization string, name string) (*Workspace, error) {
+	var response document
+	err := client.do(ctx, http.MethodGet, fmt.Sprintf("/organizations/%s/workspaces/%s", url.PathEscape(organization), url.PathEscape(name)), nil, &response)
+	if isNotFound(err) {
+		return nil, errors.WithStackTrace(WorkspaceNotFound{Organization: organization, Name: name})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response.Data.workspace()
+}
+
+// CreateWorkspace creates the workspace with the given settings in the organization.
+func (client *Client) CreateWorkspace(ctx context.Context, organization string, options *WorkspaceOptions) (*Workspace, error) {
+	var response document
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/organizations/%s/workspaces", url.PathEscape(organization)), options.resource(), &response); err != nil {
+		return nil, err
+	}
+	return response.Data.workspace()
+}
+
+// UpdateWorkspace updates the settings of the workspace with the given ID.
+func (client *Client) UpdateWorkspace(ctx context.Context, workspaceID string, options *WorkspaceOptions) (*Workspace, error) {
+	var response document
+	if err := client.do(ctx, http.MethodPatch, "/workspaces/"+url.PathEscape(workspaceID), options.resource(), &response); err != nil {
+		return nil, err
+	}
+	return response.Data.workspace()
+}
+
+// EnsureWorkspace creates the workspace with the given settings if the organization has none with its name, and
+// updates its settings otherwise.
+func (client *Client) EnsureWorkspace(ctx context.Context, organization string, options *WorkspaceOptions) (*Workspace, bool, error) {
+	workspace, err := client.ReadWorkspace(ctx, organization, options.Name)
+	if IsWorkspaceNotFound(err) {
+		workspace, err = client.CreateWorkspace(ctx, organization, options)
+		return workspace, true, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	workspace, err = client.UpdateWorkspace(ctx, workspace.ID, options)
+	return workspace, false, err
+}
+
+// ListVariables returns the variables of the workspace with the given ID.
+func (client *Client) ListVariables(ctx context.Context, workspaceID string) ([]*Variable, error) {
+	var response listDocument
+	if err := client.do(ctx, http.MethodGet, fmt.Sprintf("/workspaces/%s/vars", url.PathEscape(workspaceID)), nil, &response); err != nil {
+		return nil, err
+	}
+
+	variables := make([]*Variable, 0, len(response.Data))
+	for _, data := range response.Data {
+		var attributes variableAttributes
+		if err := json.Unmarshal(data.Attributes, &attributes); err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		variables = append(variables, &Variable{ID: data.ID, Key: attributes.Key, Value: attributes.Value, Category: attributes.Category, HCL: attributes.HCL, Sensitive: attributes.Sensitive})
+	}
+	return variables, nil
+}
+
+// SyncVariables sets the given variables in the workspace with the given ID: the variables the workspace does not have
+// yet are created, and the variables whose value or settings differ are updated, the sensitive variables always as
+// their values cannot be read. The other variables of the workspace, e.g. set in the UI, are kept. Returns the keys of
+// the variables created or updated.
+func (client *Client) SyncVariables(ctx context.Context, workspaceID string, variables []*Variable) ([]string, error) {
+	existing, err := client.ListVariables(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := map[string]*Variable{}
+	for _, variable := range existing {
+		byKey[variable.Category+"/"+variable.Key] = variable
+	}
+
+	var changed []string
+	for _, variable := range variables {
+		current, ok := byKey[variable.Category+"/"+variable.Key]
+		switch {
+		case !ok:
+			if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/workspaces/%s/vars", url.PathEscape(workspaceID)), variable.resource(""), nil); err != nil {
+				return nil, err
+			}
+		case current.Sensitive || variable.Sensitive || current.Value != variable.Value || current.HCL != variable.HCL:
+			if err := client.do(ctx, http.MethodPatch, fmt.Sprintf("/workspaces/%s/vars/%s", url.PathEscape(workspaceID), url.PathEscape(current.ID)), variable.resource(current.ID), nil); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+		changed = append(changed, variable.Key)
+	}
+	return changed, nil
+}
+
+// CreateConfigurationVersion creates a configuration version of the workspace with the given ID, to upload the
+// configuration to with UploadConfiguration. The runs of a speculative configuration version can only plan.
+func (client *Client) CreateConfigurationVersion(ctx context.Context, workspaceID string, speculative bool) (*ConfigurationVersion, error) {
+	request := resource{Type: "configuration-versions", Attributes: map[string]interface{}{"auto-queue-runs": false, "speculative": speculative}}
+
+	var response document
+	if err := client.do(ctx, http.MethodPost, fmt.Sprintf("/workspaces/%s/configuration-versions", url.PathEscape(workspaceID)), request, &response); err != nil {
+		return nil, err
+	}
+	return response.Data.configurationVersion()
+}
+
+// ReadConfigurationVersion returns the configuration version with the given ID.
+func (client *Client) ReadConfigurationVersion(ctx context.Context, id string) (*ConfigurationVersion, error) {
+	var response document
+	if err := client.do(ctx, http.MethodGet, "/configuration-versions/"+url.PathEscape(id), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data.configurationVersion()
+}
+
+// UploadConfiguration uploads the given gzipped tar of the configuration to the upload URL of a configuration version.
+// The upload URL is authorized by itself, the token is not sent.
+func (client *Client) UploadConfiguration(ctx context.Context, uploadURL string, content []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(content))
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return errors.WithStackTrace(RequestFailed{Method: http.MethodPut, URL: uploadURL, Err: err})
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.WithStackTrace(UnexpectedStatus{Method: http.MethodPut, URL: uploadURL, Status: resp.Status})
+	}
+	return nil
+}
+
+// CreateRun triggers a run with the given settings.
+func (client *Client) CreateRun(ctx context.Context, options *RunOptions) (*Run, error) {
+	attributes := map[string]interface{}{
+		"message":      options.Message,
+		"is-destroy":   options.IsDestroy,
+		"plan-only":    options.PlanOnly,
+		"auto-apply":   options.AutoApply,
+		"refresh-only": options.RefreshOnly,
+	}
+	if len(options.TargetAddrs) > 0 {
+		attributes["target-addrs"] = options.TargetAddrs
+	}
+	if len(options.ReplaceAddrs) > 0 {
+		attributes["replace-addrs"] = options.ReplaceAddrs
+	}
+	relationships := map[string]relationship{"workspace": {Data: &resourceRef{Type: "workspaces", ID: options.WorkspaceID}}}
+	if options.ConfigurationVersionID != "" {
+		relationships["configuration-version"] = relationship{Data: &resourceRef{Type: "configuration-versions", ID: options.ConfigurationVersionID}}
+	}
+
+	var response document
+	if err := client.do(ctx, http.MethodPost, "/runs", resource{Type: "runs", Attributes: attributes, Relationships: relationships}, &response); err != nil {
+		return nil, err
+	}
+	return response.Data.run()
+}
+
+// ReadRun returns the run with the given ID.
+func (client *Client) ReadRun(ctx context.Context, id string) (*Run, error) {
+	var response document
+	if err := client.do(ctx, http.MethodGet, "/runs/"+url.PathEscape(id), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data.run()
+}
+
+// ApplyRun confirms the run with the given ID, which waits for a confirmation, to apply it.
+func (client *Client) ApplyRun(ctx context.Context, id string) error {
+	return client.do(ctx, http.MethodPost, "/runs/"+url.PathEscape(id)+"/actions/apply", nil, nil)
+}
+
+// DiscardRun discards the run with the given ID, which waits for a confirmation, without applying it.
+func (client *Client) DiscardRun(ctx context.Context, id string) error {
+	return client.do(ctx, http.MethodPost, "/runs/"+url.PathEscape(id)+"/actions/discard", nil, nil)
+}
+
+// ReadPlanLog returns the log of the plan with the given ID.
+func (client *Client) ReadPlanLog(ctx context.Context, planID string) (string, error) {
+	return client.readLog(ctx, "/plans/"+url.PathEscape(planID))
+}
+
+// ReadApplyLog returns the log of the apply with the given ID.
+func (client *Client) ReadApplyLog(ctx context.Context, applyID string) (string, error) {
+	return client.readLog(ctx, "/applies/"+url.PathEscape(applyID))
+}
+
+// ReadOutputs returns the outputs of the current state of the workspace with the given ID. The API only returns the
+// values of the sensitive outputs to the tokens allowed to read the state.
+func (client *Client) ReadOutputs(ctx context.Context, workspaceID string) ([]*Output, error) {
+	var response listDocument
+	if err := client.do(ctx, http.MethodGet, fmt.Sprintf("/workspaces/%s/current-state-version-outputs", url.PathEscape(workspaceID)), nil, &response); err != nil {
+		return nil, err
+	}
+
+	outputs := make([]*Output, 0, len(response.Data))
+	for _, data := range response.Data {
+		output := &Output{}
+		if err := json.Unmarshal(data.Attributes, output); err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
+
+// RunURL returns the URL of the page of the given run in the UI.
+func (client *Client) RunURL(organization string, workspace string, runID string) string {
+	return fmt.Sprintf("%s/app/%s/workspaces/%s/runs/%s", client.address, url.PathEscape(organization), url.PathEscape(workspace), url.PathEscape(runID))
+}
+
+// readLog returns the log of the plan or apply at the given path, read from the log URL the API returns for it.
+func (client *Client) readLog(ctx context.Context, path string) (string, error) {
+	var response document
+	if err := client.do(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return "", err
+	}
+	var attributes struct {
+		LogReadURL string `json:"log-read-url"`
+	}
+	if err := json.Unmarshal(response.Data.Attributes, &attributes); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	if attributes.LogReadURL == "" {
+		return "", nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attributes.LogReadURL, nil)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return "", errors.WithStackTrace(RequestFailed{Method: http.MethodGet, URL: attributes.LogReadURL, Err: err})
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.WithStackTrace(UnexpectedStatus{Method: http.MethodGet, URL: attributes.LogReadURL, Status: resp.Status})
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return string(content), nil
+}
+
+// do sends a request to the API at the given path with the given resource, if any, as the data of the body, and decodes
+// the body of the response into the given value, if any.
+func (client *Client) do(ctx context.Context, method string, path string, data interface{}, out interface{}) error {
+	reqURL := client.address + "/api/v2" + path
+
+	var body io.Reader
+	if data != nil {
+		content, err := json.Marshal(map[string]interface{}{"data": data})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		body = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+client.token)
+	req.Header.Set("Accept", mediaType)
+	if data != nil {
+		req.Header.Set("Content-Type", mediaType)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return errors.WithStackTrace(RequestFailed{Method: method, URL: reqURL, Err: err})
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.WithStackTrace(UnexpectedStatus{Method: method, URL: reqURL, Status: resp.Status, StatusCode: resp.StatusCode, Detail: errorDetail(resp.Body)})
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errors.WithStackTrace(RequestFailed{Method: method, URL: reqURL, Err: err})
+	}
+	return nil
+}
+
+// errorDetail returns the first error of the given body of an error response, in the JSON:API format, or an empty
+// string if the body has none.
+func errorDetail(body io.Reader) string {
+	var response struct {
+		Errors []struct {
+			Title  string `json:"title"`
+			Detail string `json:"detail"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(body).Decode(&response); err != nil || len(response.Errors) == 0 {
+		return ""
+	}
+	if response.Errors[0].Detail != "" {
+		return response.Errors[0].Detail
+	}
+	return response.Errors[0].Title
+}
+
+// The resources of the API, in the JSON:API format.
+
+type resource struct {
+	ID            string                  `json:"id,omitempty"`
+	Type          string                  `json:"type"`
+	Attributes    map[string]interface{}  `json:"attributes,omitempty"`
+	Relationships map[string]relationship `json:"relationships,omitempty"`
+}
+
+type relationship struct {
+	Data *resourceRef `json:"data"`
+}
+
+type resourceRef struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type document struct {
+	Data resourceData `json:"data"`
+}
+
+type listDocument struct {
+	Data []resourceData `json:"data"`
+}
+
+type resourceData struct {
+	ID            string                  `json:"id"`
+	Type          string                  `json:"type"`
+	Attributes    json.RawMessage         `json:"attributes"`
+	Relationships map[string]relationship `json:"relationships"`
+}
+
+type variableAttributes struct {
+	Key       string `json:"key"`
+	Value     string `json:"value"`
+	Category  string `json:"category"`
+	HCL       bool   `json:"hcl"`
+	Sensitive bool   `json:"sensitive"`
+}
+
+func (data *resourceData) workspace() (*Workspace, error) {
+	var attributes struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data.Attributes, &attributes); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return &Workspace{ID: data.ID, Name: attributes.Name}, nil
+}
+
+func (data *resourceData) configurationVersion() (*ConfigurationVersion, error) {
+	var attributes struct {
+		Status    string `json:"status"`
+		UploadURL string `json:"upload-url"`
+	}
+	if err := json.Unmarshal(data.Attributes, &attributes); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return &ConfigurationVersion{ID: data.ID, Status: attributes.Status, UploadURL: attributes.UploadURL}, nil
+}
+
+func (data *resourceData) run() (*Run, error) {
+	var attributes struct {
+		Status     string `json:"status"`
+		HasChanges bool   `json:"has-changes"`
+		Actions    struct {
+			IsConfirmable bool `json:"is-confirmable"`
+		} `json:"actions"`
+	}
+	if err := json.Unmarshal(data.Attributes, &attributes); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	run := &Run{ID: data.ID, Status: attributes.Status, HasChanges: attributes.HasChanges, IsConfirmable: attributes.Actions.IsConfirmable}
+	if plan := data.Relationships["plan"]; plan.Data != nil {
+		run.PlanID = plan.Data.ID
+	}
+	if apply := data.Relationships["apply"]; apply.Data != nil {
+		run.ApplyID = apply.Data.ID
+	}
+	return run, nil
+}
+
+func (options *WorkspaceOptions) resource() resource {
+	attributes := map[string]interface{}{"name": options.Name}
+	if options.TerraformVersion != "" {
+		attributes["terraform-version"] = options.TerraformVersion
+	}
+	if options.WorkingDirectory != "" {
+		attributes["working-directory"] = options.WorkingDirectory
+	}
+	if len(options.Tags) > 0 {
+		attributes["tag-names"] = options.Tags
+	}
+	if options.VCSRepo != nil {
+		vcsRepo := map[string]interface{}{"identifier": options.VCSRepo.Identifier, "oauth-token-id": options.VCSRepo.OAuthTokenID}
+		if options.VCSRepo.Branch != "" {
+			vcsRepo["branch"] = options.VCSRepo.Branch
+		}
+		attributes["vcs-repo"] = vcsRepo
+	}
+
+	workspace := resource{Type: "workspaces", Attributes: attributes}
+	if options.ProjectID != "" {
+		workspace.Relationships = map[string]relationship{"project": {Data: &resourceRef{Type: "projects", ID: options.ProjectID}}}
+	}
+	return workspace
+}
+
+func (variable *Variable) resource(id string) resource {
+	return resource{ID: id, Type: "vars", Attributes: map[string]interface{}{
+		"key":       variable.Key,
+		"value":     variable.Value,
+		"category":  variable.Category,
+		"hcl":       variable.HCL,
+		"sensitive": variable.Sensitive,
+	}}
+}
+
+// IsWorkspaceNotFound returns true if the given error is returned for a workspace which does not exist.
+func IsWorkspaceNotFound(err error) bool {
+	_, ok := errors.Unwrap(err).(WorkspaceNotFound)
+	return ok
+}
+
+func isNotFound(err error) bool {
+	status, ok := errors.Unwrap(err).(UnexpectedStatus)
+	return ok && status.StatusCode == http.StatusNotFound
+}
+
+// Custom error types
+
+type WorkspaceNotFound struct {
+	Organization string
+	Name         string
+}
+
+func (err WorkspaceNotFound) Error() string {
+	return fmt.Sprintf("The organization %s has no workspace %s", err.Organization, err.Name)
+}
+
+type RequestFailed struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (err RequestFailed) Error() string {
+	return fmt.Sprintf("%s %s failed: %v", err.Method, err.URL, err.Err)
+}
+
+func (err RequestFailed) Unwrap() error {
+	return err.Err
+}
+
+type UnexpectedStatus struct {
+	Method     string
+	URL        string
+	Status     string
+	StatusCode int
+	Detail     string
+}
+
+func (err UnexpectedStatus) Error() string {
+	if err.Detail != "" {
+		return fmt.Sprintf("%s %s failed with the status %s: %s", err.Method, err.URL, err.Status, err.Detail)
+	}
+	return fmt.Sprintf("%s %s failed with the status %s", err.Method, err.URL, err.Status)
+}
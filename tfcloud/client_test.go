@@ -0,0 +1,176 @@
+package tfcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI is the part of the API of Terraform Cloud managing the workspaces and their variables, recording the requests.
+type fakeAPI struct {
+	mu         sync.Mutex
+	workspaces map[string]string
+	vars       map[string]map[string]interface{}
+	requests   []string
+}
+
+func (api *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	api.requests = append(api.requests, r.Method+" "+r.URL.Path)
+
+	var body struct {
+		Data struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"data"`
+	}
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	respond := func(data interface{}) {
+		w.Header().Set("Content-Type", mediaType)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/organizations/acme/workspaces/vpc":
+		if id, ok := api.workspaces["vpc"]; ok {
+			respond(map[string]interface{}{"id": id, "type": "workspaces", "attributes": map[string]interface{}{"name": "vpc"}})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprint(w, `{"errors":[{"status":"404","title":"not found"}]}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/organizations/acme/workspaces":
+		api.workspaces["vpc"] = "ws-1"
+		respond(map[string]interface{}{"id": "ws-1", "type": "workspaces", "attributes": body.Data.Attributes})
+	case r.Method == http.MethodPatch && r.URL.Path == "/api/v2/workspaces/ws-1":
+		respond(map[string]interface{}{"id": "ws-1", "type": "workspaces", "attributes": body.Data.Attributes})
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v2/workspaces/ws-1/vars":
+		var data []interface{}
+		for id, attributes := range api.vars {
+			data = append(data, map[string]interface{}{"id": id, "type": "vars", "attributes": attributes})
+		}
+		respond(data)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v2/workspaces/ws-1/vars":
+		id := fmt.Sprintf("var-%d", len(api.vars)+1)
+		api.vars[id] = body.Data.Attributes
+		respond(map[string]interface{}{"id": id, "type": "vars", "attributes": body.Data.Attributes})
+	case r.Method == http.MethodPatch:
+		var id string
+		if _, err := fmt.Sscanf(r.URL.Path, "/api/v2/workspaces/ws-1/vars/%s", &id); err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		api.vars[id] = body.Data.Attributes
+		respond(map[string]interface{}{"id": id, "type": "vars", "attributes": body.Data.Attributes})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestEnsureWorkspace(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeAPI{workspaces: map[string]string{}, vars: map[string]map[string]interface{}{}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+
+	workspace, created, err := client.EnsureWorkspace(context.Background(), "acme", &WorkspaceOptions{Name: "vpc"})
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, &Workspace{ID: "ws-1", Name: "vpc"}, workspace)
+
+	_, created, err = client.EnsureWorkspace(context.Background(), "acme", &WorkspaceOptions{Name: "vpc", TerraformVersion: "1.5.7"})
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	assert.Equal(t, []string{
+		"GET /api/v2/organizations/acme/workspaces/vpc",
+		"POST /api/v2/organizations/acme/workspaces",
+		"GET /api/v2/organizations/acme/workspaces/vpc",
+		"PATCH /api/v2/workspaces/ws-1",
+	}, api.requests)
+}
+
+func TestReadWorkspaceNotFound(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(&fakeAPI{workspaces: map[string]string{}})
+	defer server.Close()
+
+	_, err := NewClient(server.URL, "secret").ReadWorkspace(context.Background(), "acme", "vpc")
+	require.Error(t, err)
+	assert.True(t, IsWorkspaceNotFound(err))
+
+	_, err = NewClient(server.URL, "wrong").ReadWorkspace(context.Background(), "acme", "vpc")
+	require.Error(t, err)
+	assert.False(t, IsWorkspaceNotFound(err))
+	assert.Contains(t, err.Error(), "401")
+}
+
+func TestSyncVariables(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeAPI{workspaces: map[string]string{"vpc": "ws-1"}, vars: map[string]map[string]interface{}{
+		"var-1": {"key": "region", "value": "us-east-1", "category": CategoryTerraform, "hcl": false, "sensitive": false},
+		"var-2": {"key": "cidr", "value": "10.0.0.0/16", "category": CategoryTerraform, "hcl": false, "sensitive": false},
+		"var-3": {"key": "password", "value": "", "category": CategoryTerraform, "hcl": false, "sensitive": true},
+		"var-4": {"key": "AWS_REGION", "value": "us-east-1", "category": CategoryEnv, "hcl": false, "sensitive": false},
+	}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	changed, err := NewClient(server.URL, "secret").SyncVariables(context.Background(), "ws-1", []*Variable{
+		{Key: "cidr", Value: "10.1.0.0/16", Category: CategoryTerraform},
+		{Key: "password", Value: "hunter2", Category: CategoryTerraform, Sensitive: true},
+		{Key: "region", Value: "us-east-1", Category: CategoryTerraform},
+		{Key: "tags", Value: `{"team":"platform"}`, Category: CategoryTerraform, HCL: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cidr", "password", "tags"}, changed)
+
+	assert.Equal(t, "10.1.0.0/16", api.vars["var-2"]["value"])
+	assert.Equal(t, "hunter2", api.vars["var-3"]["value"])
+	assert.Equal(t, map[string]interface{}{"key": "tags", "value": `{"team":"platform"}`, "category": CategoryTerraform, "hcl": true, "sensitive": false}, api.vars["var-5"])
+	// The variables terragrunt does not set are kept
+	assert.Equal(t, "us-east-1", api.vars["var-4"]["value"])
+}
+
+func TestRunIsFinished(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		status    string
+		finished  bool
+		succeeded bool
+	}{
+		{"pending", false, false},
+		{"planning", false, false},
+		{"applying", false, false},
+		{RunStatusApplied, true, true},
+		{RunStatusPlannedAndFinished, true, true},
+		{RunStatusErrored, true, false},
+		{RunStatusDiscarded, true, false},
+		{RunStatusPolicySoftFailed, true, false},
+	}
+
+	for _, testCase := range testCases {
+		run := &Run{Status: testCase.status}
+		assert.Equal(t, testCase.finished, run.IsFinished(), testCase.status)
+		assert.Equal(t, testCase.succeeded, run.Succeeded(), testCase.status)
+	}
+}
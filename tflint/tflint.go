@@ -162,7 +162,7 @@ func tfArgumentsToTflintVar(terragruntOptions *options.TerragruntOptions, hook c
 
 	for _, arg := range config.ExtraArgs {
 		// use extra args which will be used on same command as hook
-		if len(collections.ListIntersection(arg.Commands, hook.Commands)) == 0 {
+		if !arg.IsEnabled() || len(collections.ListIntersection(arg.Commands, hook.Commands)) == 0 {
 			continue
 		}
 		if arg.EnvVars != nil {
@@ -0,0 +1,167 @@
+package util
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// WriteDirToTar writes the files of the given dir to the given tar writer, with paths relative to the dir, skipping the
+// dirs with the given names wherever they are in the tree.
+func WriteDirToTar(tarWriter *tar.Writer, dir string, skipDirs []string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if path == dir {
+			return nil
+		}
+		if info.IsDir() && ListContainsElement(skipDirs, info.Name()) {
+			return filepath.SkipDir
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return errors.WithStackTrace(err)
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		defer file.Close() //nolint:errcheck
+
+		if _, err := io.Copy(tarWriter, file); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		return nil
+	})
+}
+
+// ExtractTar extracts the dirs, regular files and symlinks of the given tar stream into the given dir. Entries that
+// would end up outside of the dir are refused, including the ones written through a symlink of the archive, which may
+// point anywhere.
+func ExtractTar(reader io.Reader, dir string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if path != filepath.Clean(dir) && !strings.HasPrefix(path, filepath.Clean(dir)+string(filepath.Separator)) {
+			return errors.WithStackTrace(ArchiveEntryOutsideDir{Entry: header.Name, Dir: dir})
+		}
+		symlink, err := symlinkInPath(dir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if symlink != "" {
+			return errors.WithStackTrace(ArchiveEntryThroughSymlink{Entry: header.Name, Symlink: symlink})
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, os.FileMode(header.Mode)|0700); err != nil {
+				return errors.WithStackTrace(err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				return errors.WithStackTrace(err)
+			}
+			// Replace rather than write through a symlink of the archive at the path of the file
+			if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(path); err != nil {
+					return errors.WithStackTrace(err)
+				}
+			}
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode))
+			if err != nil {
+				return errors.WithStackTrace(err)
+			}
+			_, err = io.Copy(file, tarReader)
+			file.Close() //nolint:errcheck
+			if err != nil {
+				return errors.WithStackTrace(err)
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				return errors.WithStackTrace(err)
+			}
+			if err := os.RemoveAll(path); err != nil {
+				return errors.WithStackTrace(err)
+			}
+			if err := os.Symlink(header.Linkname, path); err != nil {
+				return errors.WithStackTrace(err)
+			}
+		}
+	}
+}
+
+// symlinkInPath returns the first symlink among the given path and its parents up to the given dir, excluded, which
+// must be a parent of the path, empty if there is none.
+func symlinkInPath(dir string, path string) (string, error) {
+	dir = filepath.Clean(dir)
+	for path = filepath.Clean(path); path != dir && strings.HasPrefix(path, dir); path = filepath.Dir(path) {
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// Custom error types
+
+type ArchiveEntryOutsideDir struct {
+	Entry string
+	Dir   string
+}
+
+func (err ArchiveEntryOutsideDir) Error() string {
+	return fmt.Sprintf("The archive entry %s would be extracted outside of %s", err.Entry, err.Dir)
+}
+
+type ArchiveEntryThroughSymlink struct {
+	Entry   string
+	Symlink string
+}
+
+func (err ArchiveEntryThroughSymlink) Error() string {
+	return fmt.Sprintf("The archive entry %s would be extracted through the symlink %s", err.Entry, err.Symlink)
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractTar(t *testing.T) {
+	t.Parallel()
+
+	srcDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(srcDir, "modules", "vpc"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "modules", "vpc", "main.tf"), []byte("# vpc"), 0644))
+	require.NoError(t, os.Symlink("modules/vpc", filepath.Join(srcDir, "vpc")))
+
+	var archive bytes.Buffer
+	tarWriter := tar.NewWriter(&archive)
+	require.NoError(t, WriteDirToTar(tarWriter, srcDir, nil))
+	require.NoError(t, tarWriter.Close())
+
+	dir := t.TempDir()
+	require.NoError(t, ExtractTar(&archive, dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "vpc", "main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "# vpc", string(content))
+}
+
+func TestExtractTarRefusesWritingThroughSymlinks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		entries []tar.Header
+	}{
+		{"symlink then file", []tar.Header{{Name: "link", Typeflag: tar.TypeSymlink}, {Name: "link/x", Typeflag: tar.TypeReg}}},
+		{"symlink then nested file", []tar.Header{{Name: "link", Typeflag: tar.TypeSymlink}, {Name: "link/a/x", Typeflag: tar.TypeReg}}},
+		{"symlink then dir", []tar.Header{{Name: "link", Typeflag: tar.TypeSymlink}, {Name: "link/a", Typeflag: tar.TypeDir}}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			outsideDir := t.TempDir()
+			var archive bytes.Buffer
+			tarWriter := tar.NewWriter(&archive)
+			for _, header := range testCase.entries {
+				header := header
+				header.Mode = 0644
+				if header.Typeflag == tar.TypeSymlink {
+					header.Linkname = outsideDir
+				}
+				require.NoError(t, tarWriter.WriteHeader(&header))
+			}
+			require.NoError(t, tarWriter.Close())
+
+			err := ExtractTar(&archive, t.TempDir())
+			assert.IsType(t, ArchiveEntryThroughSymlink{}, errors.Unwrap(err))
+
+			entries, err := os.ReadDir(outsideDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestExtractTarReplacesSymlinkWithFile(t *testing.T) {
+	t.Parallel()
+
+	outsideFile := filepath.Join(t.TempDir(), "outside")
+	require.NoError(t, os.WriteFile(outsideFile, []byte("outside"), 0644))
+
+	var archive bytes.Buffer
+	tarWriter := tar.NewWriter(&archive)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: outsideFile}))
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeReg, Mode: 0644, Size: 6}))
+	_, err := tarWriter.Write([]byte("inside"))
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+
+	dir := t.TempDir()
+	require.NoError(t, ExtractTar(&archive, dir))
+
+	content, err := os.ReadFile(outsideFile)
+	require.NoError(t, err)
+	assert.Equal(t, "outside", string(content))
+	content, err = os.ReadFile(filepath.Join(dir, "link"))
+	require.NoError(t, err)
+	assert.Equal(t, "inside", string(content))
+}
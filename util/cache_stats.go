@@ -0,0 +1,86 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The caches whose hits and misses are counted, to tell how much of a run the caches save.
+const (
+	// The terraform source downloaded in the download dir of a module
+	SourceCache = "source"
+	// The providers of the provider cache server
+	ProviderCache = "provider"
+	// The outputs of the dependencies read once per run
+	DependencyOutputCache = "dependency_output"
+	// The parsed HCL files and the partially parsed configs
+	ParsedConfigCache = "parsed_config"
+	// The sources, providers and dependency outputs shared between machines through --terragrunt-remote-cache
+	RemoteCache = "remote"
+)
+
+var (
+	cacheStats   = map[string]CacheStats{}
+	cacheStatsMu sync.Mutex
+)
+
+// CacheStats are the hits and misses of a cache.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// HitRate returns the share of the lookups of the cache which were hits, from 0 to 1.
+func (stats CacheStats) HitRate() float64 {
+	if stats.Hits+stats.Misses == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(stats.Hits+stats.Misses)
+}
+
+func (stats CacheStats) String() string {
+	return fmt.Sprintf("%d hits, %d misses (%.0f%% hit rate)", stats.Hits, stats.Misses, stats.HitRate()*100)
+}
+
+// CountCacheLookup counts a hit, or a miss, of the cache with the given name.
+func CountCacheLookup(cache string, hit bool) {
+	cacheStatsMu.Lock()
+	defer cacheStatsMu.Unlock()
+
+	stats := cacheStats[cache]
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+	cacheStats[cache] = stats
+}
+
+// GetCacheStats returns the hits and misses of the caches looked up since the start of the process, by cache name.
+func GetCacheStats() map[string]CacheStats {
+	cacheStatsMu.Lock()
+	defer cacheStatsMu.Unlock()
+
+	stats := make(map[string]CacheStats, len(cacheStats))
+	for cache, cacheStat := range cacheStats {
+		stats[cache] = cacheStat
+	}
+	return stats
+}
+
+// CacheStatsString returns the given stats of the caches, one cache per line, sorted by cache name.
+func CacheStatsString(stats map[string]CacheStats) string {
+	caches := make([]string, 0, len(stats))
+	for cache := range stats {
+		caches = append(caches, cache)
+	}
+	sort.Strings(caches)
+
+	var str strings.Builder
+	for _, cache := range caches {
+		str.WriteString("  - " + cache + ": " + stats[cache].String() + "\n")
+	}
+	return str.String()
+}
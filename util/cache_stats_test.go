@@ -0,0 +1,23 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheStats(t *testing.T) {
+	t.Parallel()
+
+	const cache = "test_cache_stats"
+	CountCacheLookup(cache, true)
+	CountCacheLookup(cache, true)
+	CountCacheLookup(cache, true)
+	CountCacheLookup(cache, false)
+
+	stats := GetCacheStats()[cache]
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 1}, stats)
+	assert.Equal(t, 0.75, stats.HitRate())
+	assert.Equal(t, "  - test_cache_stats: 3 hits, 1 misses (75% hit rate)\n", CacheStatsString(map[string]CacheStats{cache: stats}))
+	assert.Equal(t, float64(0), CacheStats{}.HitRate())
+}
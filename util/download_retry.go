@@ -0,0 +1,142 @@
+package util
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/pkg/log"
+)
+
+// The longest sleep between two attempts of a download, however many attempts failed.
+const maxDownloadRetryInterval = time.Minute
+
+// DownloadMirror replaces the Original prefix of the download URLs with the Mirror prefix, e.g.
+// `https://github.com/` with `https://git.example.com/github/`.
+type DownloadMirror struct {
+	Original string
+	Mirror   string
+}
+
+// ParseDownloadMirror parses a mirror in the `<original prefix>=<mirror prefix>` format.
+func ParseDownloadMirror(spec string) (DownloadMirror, error) {
+	original, mirror, found := strings.Cut(spec, "=")
+	if !found || original == "" || mirror == "" {
+		return DownloadMirror{}, errors.WithStackTrace(InvalidDownloadMirror(spec))
+	}
+	return DownloadMirror{Original: original, Mirror: mirror}, nil
+}
+
+// DownloadRetry makes the downloads resilient to flaky hosts: every URL is attempted up to MaxAttempts times, sleeping
+// between the attempts for Interval, doubled after every failed attempt, and then the mirrors of the URL are attempted
+// in order.
+type DownloadRetry struct {
+	MaxAttempts int
+	Interval    time.Duration
+	Mirrors     []DownloadMirror
+}
+
+// URLs returns the given URL followed by its mirrors. The go-getter forced getter, e.g. `git::`, is kept.
+func (retry DownloadRetry) URLs(downloadURL string) []string {
+	urls := []string{downloadURL}
+
+	forcedGetter, rawURL := "", downloadURL
+	if getter, url, found := strings.Cut(downloadURL, "::"); found {
+		forcedGetter, rawURL = getter+"::", url
+	}
+	for _, mirror := range retry.Mirrors {
+		if strings.HasPrefix(rawURL, mirror.Original) {
+			urls = append(urls, forcedGetter+mirror.Mirror+strings.TrimPrefix(rawURL, mirror.Original))
+		}
+	}
+	return urls
+}
+
+// Do runs the download of the given URLs in order, until one succeeds, returning the error of the last attempt if all
+// fail. FatalError errors are not retried.
+func (retry DownloadRetry) Do(ctx context.Context, description string, urls []string, download func(downloadURL string) error) error {
+	maxAttempts := retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var err error
+	for i, downloadURL := range urls {
+		if i > 0 {
+			log.Warnf("%s failed: %v. Falling back to the mirror %s.", description, err, downloadURL)
+		}
+
+		interval := retry.Interval
+		for attempt := 1; attempt <= maxAttempts; attempt++ {
+			if err = download(downloadURL); err == nil {
+				return nil
+			}
+			if fatalErr, isFatalErr := err.(FatalError); isFatalErr {
+				return fatalErr.Underlying
+			}
+			if attempt == maxAttempts {
+				break
+			}
+
+			log.Warnf("%s from %s returned an error: %v. Attempt %d of %d. Sleeping for %s and will try again.", description, downloadURL, err, attempt, maxAttempts, interval)
+			select {
+			case <-ctx.Done():
+				return errors.WithStackTrace(ctx.Err())
+			case <-time.After(interval):
+			}
+			interval = min(interval*2, maxDownloadRetryInterval)
+		}
+	}
+	return err
+}
+
+// The messages of the errors of the hosts and the VCS clients that the downloads run, which a later attempt can fix.
+var transientDownloadErrorMessages = []string{
+	"bad response code: 5",
+	"bad response code: 429",
+	"could not resolve host",
+	"connection refused",
+	"connection reset",
+	"connection timed out",
+	"operation timed out",
+	"timeout",
+	"tls handshake",
+	"temporary failure",
+	"unexpected eof",
+	"early eof",
+	"the remote end hung up",
+	"service unavailable",
+	"bad gateway",
+	"gateway timeout",
+	"too many requests",
+}
+
+// IsTransientDownloadError returns true if the given download error is a network error, or a server error, that a
+// later attempt of the download can fix. The download of a source that does not exist, e.g., is not retried.
+func IsTransientDownloadError(err error) bool {
+	var netErr net.Error
+	if goerrors.As(err, &netErr) || goerrors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, transientMessage := range transientDownloadErrorMessages {
+		if strings.Contains(message, transientMessage) {
+			return true
+		}
+	}
+	return false
+}
+
+// Custom error types
+
+type InvalidDownloadMirror string
+
+func (spec InvalidDownloadMirror) Error() string {
+	return fmt.Sprintf("Invalid download mirror %q, must be in the format <original URL prefix>=<mirror URL prefix>", string(spec))
+}
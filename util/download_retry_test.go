@@ -0,0 +1,88 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDownloadMirror(t *testing.T) {
+	t.Parallel()
+
+	mirror, err := ParseDownloadMirror("https://github.com/=https://git.example.com/github/")
+	require.NoError(t, err)
+	assert.Equal(t, DownloadMirror{Original: "https://github.com/", Mirror: "https://git.example.com/github/"}, mirror)
+
+	for _, spec := range []string{"https://github.com/", "=https://git.example.com/", "https://github.com/="} {
+		_, err := ParseDownloadMirror(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestDownloadRetryURLs(t *testing.T) {
+	t.Parallel()
+
+	retry := DownloadRetry{Mirrors: []DownloadMirror{
+		{Original: "https://github.com/", Mirror: "https://git.example.com/github/"},
+		{Original: "https://releases.example.com/", Mirror: "https://mirror.example.com/releases/"},
+		{Original: "https://github.com/", Mirror: "https://backup.example.com/"},
+	}}
+
+	assert.Equal(t, []string{
+		"git::https://github.com/org/repo.git?ref=v1.0.0",
+		"git::https://git.example.com/github/org/repo.git?ref=v1.0.0",
+		"git::https://backup.example.com/org/repo.git?ref=v1.0.0",
+	}, retry.URLs("git::https://github.com/org/repo.git?ref=v1.0.0"))
+	assert.Equal(t, []string{"https://gitlab.com/org/repo.zip"}, retry.URLs("https://gitlab.com/org/repo.zip"))
+}
+
+func TestDownloadRetryDo(t *testing.T) {
+	t.Parallel()
+
+	retry := DownloadRetry{MaxAttempts: 2}
+
+	var attempts []string
+	err := retry.Do(context.Background(), "Downloading", []string{"primary", "mirror"}, func(downloadURL string) error {
+		attempts = append(attempts, downloadURL)
+		if downloadURL == "mirror" && len(attempts) == 4 {
+			return nil
+		}
+		return fmt.Errorf("failed %s", downloadURL)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"primary", "primary", "mirror", "mirror"}, attempts)
+
+	attempts = nil
+	err = retry.Do(context.Background(), "Downloading", []string{"primary", "mirror"}, func(downloadURL string) error {
+		attempts = append(attempts, downloadURL)
+		return FatalError{Underlying: fmt.Errorf("fatal")}
+	})
+	assert.EqualError(t, err, "fatal")
+	assert.Equal(t, []string{"primary"}, attempts)
+}
+
+func TestIsTransientDownloadError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		err       error
+		transient bool
+	}{
+		{&net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")}, true},
+		{fmt.Errorf("wrapped: %w", io.ErrUnexpectedEOF), true},
+		{fmt.Errorf("error downloading 'https://example.com/module.zip': bad response code: 503"), true},
+		{fmt.Errorf("fatal: unable to access 'https://github.com/org/repo.git/': Could not resolve host: github.com"), true},
+		{fmt.Errorf("error downloading 'https://example.com/module.zip': bad response code: 404"), false},
+		{fmt.Errorf("fatal: repository 'https://github.com/org/notreal.git/' not found"), false},
+		{fmt.Errorf("source path error: stat /tmp/does-not-exist: no such file or directory"), false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.transient, IsTransientDownloadError(testCase.err), testCase.err.Error())
+	}
+}
@@ -0,0 +1,133 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// The levels of GitHub Actions workflow annotations.
+const (
+	GitHubAnnotationError   = "error"
+	GitHubAnnotationWarning = "warning"
+)
+
+// GitHubAnnotation is a GitHub Actions workflow command that shows a message inline in the Actions UI, on the given
+// file and line if any. See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions.
+type GitHubAnnotation struct {
+	Level   string
+	File    string
+	Line    int
+	Title   string
+	Message string
+}
+
+func (annotation GitHubAnnotation) String() string {
+	var properties []string
+	if annotation.File != "" {
+		properties = append(properties, "file="+escapeGitHubAnnotationProperty(annotation.File))
+	}
+	if annotation.Line > 0 {
+		properties = append(properties, fmt.Sprintf("line=%d", annotation.Line))
+	}
+	if annotation.Title != "" {
+		properties = append(properties, "title="+escapeGitHubAnnotationProperty(annotation.Title))
+	}
+
+	command := annotation.Level
+	if len(properties) > 0 {
+		command += " " + strings.Join(properties, ",")
+	}
+	return fmt.Sprintf("::%s::%s", command, escapeGitHubAnnotationData(annotation.Message))
+}
+
+var (
+	gitHubAnnotationsMutex   sync.Mutex
+	gitHubAnnotationsEmitted = map[string]bool{}
+)
+
+// EmitGitHubAnnotations writes the given annotations, skipping those already written, as the same error may be
+// reported both by the module that failed and by the command that ran it.
+func EmitGitHubAnnotations(writer io.Writer, annotations ...GitHubAnnotation) {
+	gitHubAnnotationsMutex.Lock()
+	defer gitHubAnnotationsMutex.Unlock()
+
+	for _, annotation := range annotations {
+		line := annotation.String()
+		if gitHubAnnotationsEmitted[line] {
+			continue
+		}
+		gitHubAnnotationsEmitted[line] = true
+		fmt.Fprintln(writer, line)
+	}
+}
+
+// GitHubAnnotationsEmittedCount returns how many distinct annotations were written so far.
+func GitHubAnnotationsEmittedCount() int {
+	gitHubAnnotationsMutex.Lock()
+	defer gitHubAnnotationsMutex.Unlock()
+
+	return len(gitHubAnnotationsEmitted)
+}
+
+// GitHubAnnotationsForDiagnostics returns an annotation, with the file and line, for every HCL diagnostic found in the
+// given error. The files are made relative to the given workspace dir, as GitHub expects paths relative to the repo.
+func GitHubAnnotationsForDiagnostics(err error, workspaceDir string) []GitHubAnnotation {
+	var annotations []GitHubAnnotation
+
+	switch err := errors.Unwrap(err).(type) {
+	case hcl.Diagnostics:
+		for _, diag := range err {
+			annotation := GitHubAnnotation{Level: GitHubAnnotationError, Title: diag.Summary, Message: diag.Detail}
+			if diag.Severity == hcl.DiagWarning {
+				annotation.Level = GitHubAnnotationWarning
+			}
+			if annotation.Message == "" {
+				annotation.Message = diag.Summary
+			}
+			if diag.Subject != nil {
+				annotation.File = GitHubAnnotationPath(diag.Subject.Filename, workspaceDir)
+				annotation.Line = diag.Subject.Start.Line
+			}
+			annotations = append(annotations, annotation)
+		}
+	case *multierror.Error:
+		for _, err := range err.Errors {
+			annotations = append(annotations, GitHubAnnotationsForDiagnostics(err, workspaceDir)...)
+		}
+	case interface{ Unwrap() error }:
+		if wrapped := err.Unwrap(); wrapped != nil {
+			annotations = append(annotations, GitHubAnnotationsForDiagnostics(wrapped, workspaceDir)...)
+		}
+	}
+
+	return annotations
+}
+
+// GitHubAnnotationPath returns the given path relative to the workspace dir, if the path is in it.
+func GitHubAnnotationPath(path string, workspaceDir string) string {
+	if workspaceDir == "" || !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+	relPath, err := GetPathRelativeTo(path, workspaceDir)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return filepath.ToSlash(path)
+	}
+	return relPath
+}
+
+// Escape the message of an annotation, as documented by GitHub.
+func escapeGitHubAnnotationData(data string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(data)
+}
+
+// Escape a property of an annotation, as documented by GitHub.
+func escapeGitHubAnnotationProperty(property string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(property)
+}
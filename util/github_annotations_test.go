@@ -0,0 +1,64 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitHubAnnotationString(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		annotation GitHubAnnotation
+		expected   string
+	}{
+		{
+			GitHubAnnotation{Level: GitHubAnnotationError, Message: "failed"},
+			"::error::failed",
+		},
+		{
+			GitHubAnnotation{Level: GitHubAnnotationWarning, File: "live/vpc/terragrunt.hcl", Line: 3, Title: "Drift: detected", Message: "100% changed\nreally"},
+			"::warning file=live/vpc/terragrunt.hcl,line=3,title=Drift%3A detected::100%25 changed%0Areally",
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, testCase.annotation.String())
+	}
+}
+
+func TestGitHubAnnotationsForDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	diags := hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  "Unsupported argument",
+		Detail:   `An argument named "foo" is not expected here.`,
+		Subject:  &hcl.Range{Filename: "/repo/live/vpc/terragrunt.hcl", Start: hcl.Pos{Line: 7}},
+	}}
+	err := multierror.Append(errors.WithStackTrace(diags), errors.WithStackTrace(assert.AnError))
+
+	assert.Equal(t, []GitHubAnnotation{{
+		Level:   GitHubAnnotationError,
+		File:    "live/vpc/terragrunt.hcl",
+		Line:    7,
+		Title:   "Unsupported argument",
+		Message: `An argument named "foo" is not expected here.`,
+	}}, GitHubAnnotationsForDiagnostics(err, "/repo"))
+}
+
+func TestEmitGitHubAnnotationsSkipsDuplicates(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	annotation := GitHubAnnotation{Level: GitHubAnnotationError, Message: "emit duplicates test"}
+	EmitGitHubAnnotations(&output, annotation, annotation)
+	EmitGitHubAnnotations(&output, annotation)
+
+	assert.Equal(t, "::error::emit duplicates test\n", output.String())
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
 
 	"golang.org/x/term"
 
@@ -18,6 +21,27 @@ const (
 	logLevelEnvVar  = "TERRAGRUNT_LOG_LEVEL"
 )
 
+// The formats of the log output.
+const (
+	LogFormatHuman  = "human"
+	LogFormatLogfmt = "logfmt"
+	LogFormatJSON   = "json"
+)
+
+// The fields of log entries that can be selected with SetLogFields.
+const (
+	LogFieldModule   = "module"
+	LogFieldGroup    = "group"
+	LogFieldCommand  = "command"
+	LogFieldDuration = "duration"
+	LogFieldRunID    = "run_id"
+)
+
+var (
+	LogFormats   = []string{LogFormatHuman, LogFormatLogfmt, LogFormatJSON}
+	LogFieldList = []string{LogFieldModule, LogFieldGroup, LogFieldCommand, LogFieldDuration, LogFieldRunID}
+)
+
 var (
 	// GlobalFallbackLogEntry is a global fallback logentry for the application
 	// Should be used in cases when more specific logger can't be created (like in the very beginning, when we have not yet
@@ -28,7 +52,9 @@ var (
 	GlobalFallbackLogEntry *logrus.Entry
 
 	disableLogColors bool
-	jsonLogFormat    bool
+	logFormat        = LogFormatHuman
+	// The selectable fields included in log entries, nil means the default of the log format.
+	logFields []string
 )
 
 func init() {
@@ -43,15 +69,45 @@ func DisableLogColors() {
 }
 
 func JsonFormat() {
-	jsonLogFormat = true
+	logFormat = LogFormatJSON
 	// Needs to re-create the global logger
 	GlobalFallbackLogEntry = CreateLogEntry("", defaultLogLevel)
 }
 
 func DisableJsonFormat() {
-	jsonLogFormat = false
+	logFormat = LogFormatHuman
+	// Needs to re-create the global logger
+	GlobalFallbackLogEntry = CreateLogEntry("", defaultLogLevel)
+}
+
+// SetLogFormat sets the format of the log output to one of LogFormats.
+func SetLogFormat(format string) error {
+	if !ListContainsElement(LogFormats, format) {
+		return errors.WithStackTrace(UnknownLogFormat(format))
+	}
+	logFormat = format
+	// Needs to re-create the global logger
+	GlobalFallbackLogEntry = CreateLogEntry("", defaultLogLevel)
+	return nil
+}
+
+// IsStructuredLogFormat returns true if the log output is meant to be parsed, rather than read by humans.
+func IsStructuredLogFormat() bool {
+	return logFormat != LogFormatHuman
+}
+
+// SetLogFields sets the fields of LogFieldList included in log entries. By default, the human format includes none of
+// them, as the module is already part of the prefix, while the structured formats include all of them.
+func SetLogFields(fields ...string) error {
+	for _, field := range fields {
+		if !ListContainsElement(LogFieldList, field) {
+			return errors.WithStackTrace(UnknownLogField(field))
+		}
+	}
+	logFields = fields
 	// Needs to re-create the global logger
 	GlobalFallbackLogEntry = CreateLogEntry("", defaultLogLevel)
+	return nil
 }
 
 // CreateLogger creates a logger. If debug is set, we use ErrorLevel to enable verbose output, otherwise - only errors are shown
@@ -59,17 +115,52 @@ func CreateLogger(lvl logrus.Level) *logrus.Logger {
 	logger := logrus.New()
 	logger.SetLevel(lvl)
 	logger.SetOutput(os.Stderr) // Terragrunt should output all it's logs to stderr by default
-	if jsonLogFormat {
-		logger.SetFormatter(&logrus.JSONFormatter{})
-	} else {
-		logger.SetFormatter(&logrus.TextFormatter{
+
+	var formatter logrus.Formatter
+	switch logFormat {
+	case LogFormatJSON:
+		formatter = &logrus.JSONFormatter{}
+	case LogFormatLogfmt:
+		formatter = &logrus.TextFormatter{
+			DisableColors: true,
+			FullTimestamp: true,
+		}
+	default:
+		formatter = &logrus.TextFormatter{
 			DisableQuote:  true,
 			DisableColors: disableLogColors,
-		})
+		}
+	}
+
+	fields := logFields
+	if fields == nil && IsStructuredLogFormat() {
+		fields = LogFieldList
 	}
+	logger.SetFormatter(&fieldsFilterFormatter{formatter: formatter, fields: fields})
 	return logger
 }
 
+// fieldsFilterFormatter removes the fields of LogFieldList that are not selected from log entries, before formatting
+// them with the wrapped formatter. Any other fields are kept.
+type fieldsFilterFormatter struct {
+	formatter logrus.Formatter
+	fields    []string
+}
+
+func (filter *fieldsFilterFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		if ListContainsElement(LogFieldList, key) && !ListContainsElement(filter.fields, key) {
+			continue
+		}
+		data[key] = value
+	}
+
+	filteredEntry := *entry
+	filteredEntry.Data = data
+	return filter.formatter.Format(&filteredEntry)
+}
+
 // CreateLogEntry creates a logger entry with the given prefix field
 func CreateLogEntry(prefix string, level logrus.Level) *logrus.Entry {
 	logger := CreateLogger(level)
@@ -82,8 +173,10 @@ func CreateLogEntry(prefix string, level logrus.Level) *logrus.Entry {
 	return logger.WithFields(fields)
 }
 
-// CreateLogEntryWithWriter Create a logger around the given output stream and prefix
+// CreateLogEntryWithWriter Create a logger around the given output stream and prefix. The prefix, if any, is also
+// recorded as the module field.
 func CreateLogEntryWithWriter(writer io.Writer, prefix string, level logrus.Level, hooks logrus.LevelHooks) *logrus.Entry {
+	module := prefix
 	if prefix != "" {
 		prefix = fmt.Sprintf("[%s] ", prefix)
 	} else {
@@ -92,6 +185,9 @@ func CreateLogEntryWithWriter(writer io.Writer, prefix string, level logrus.Leve
 	logger := CreateLogEntry(prefix, level)
 	logger.Logger.SetOutput(writer)
 	logger.Logger.ReplaceHooks(hooks)
+	if module != "" {
+		logger = logger.WithField(LogFieldModule, module)
+	}
 	return logger
 }
 
@@ -141,3 +237,17 @@ func (w *LogWriter) Write(p []byte) (n int, err error) {
 	w.Logger.Log(w.Level, string(p))
 	return len(p), nil
 }
+
+// Custom error types
+
+type UnknownLogFormat string
+
+func (err UnknownLogFormat) Error() string {
+	return fmt.Sprintf("Unknown log format %s, must be one of: %s", string(err), strings.Join(LogFormats, ", "))
+}
+
+type UnknownLogField string
+
+func (err UnknownLogField) Error() string {
+	return fmt.Sprintf("Unknown log field %s, must be one of: %s", string(err), strings.Join(LogFieldList, ", "))
+}
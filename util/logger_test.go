@@ -0,0 +1,64 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldsFilterFormatter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		fields         []string
+		expectedFields []string
+	}{
+		{"no-fields", nil, []string{"prefix"}},
+		{"module-only", []string{LogFieldModule}, []string{"prefix", LogFieldModule}},
+		{"all", LogFieldList, []string{"prefix", LogFieldModule, LogFieldGroup, LogFieldCommand, LogFieldDuration, LogFieldRunID}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+			logger := logrus.New()
+			logger.SetOutput(&output)
+			logger.SetFormatter(&fieldsFilterFormatter{formatter: &logrus.JSONFormatter{}, fields: testCase.fields})
+
+			logger.WithFields(logrus.Fields{
+				"prefix":         "[a] ",
+				LogFieldModule:   "a",
+				LogFieldGroup:    1,
+				LogFieldCommand:  "apply",
+				LogFieldDuration: "1s",
+				LogFieldRunID:    "run-1",
+			}).Info("done")
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(output.Bytes(), &entry))
+
+			var actualFields []string
+			for key := range entry {
+				if key != "level" && key != "msg" && key != "time" {
+					actualFields = append(actualFields, key)
+				}
+			}
+			assert.ElementsMatch(t, testCase.expectedFields, actualFields)
+		})
+	}
+}
+
+func TestSetLogFormatAndFieldsInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Error(t, SetLogFormat("yaml"))
+	assert.Error(t, SetLogFields(LogFieldModule, "host"))
+}